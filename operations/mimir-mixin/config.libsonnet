@@ -654,7 +654,7 @@
     ],
 
     // All query methods from IngesterServer interface. Basically everything except Push.
-    ingester_read_path_routes_regex: '/cortex.Ingester/(QueryStream|QueryExemplars|LabelValues|LabelNames|UserStats|AllUserStats|MetricsForLabelMatchers|MetricsMetadata|LabelNamesAndValues|LabelValuesCardinality|ActiveSeries)',
+    ingester_read_path_routes_regex: '/cortex.Ingester/(QueryStream|QueryExemplars|LabelValues|LabelNames|UserStats|AllUserStats|MetricsForLabelMatchers|MetricsMetadata|LabelNamesAndValues|LabelValuesCardinality|CardinalityHistory|ActiveSeries)',
 
     // The default datasource used for dashboards.
     dashboard_datasource: 'default',
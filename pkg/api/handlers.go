@@ -6,11 +6,13 @@
 package api
 
 import (
+	"cmp"
 	"context"
 	"embed"
 	"html/template"
 	"net/http"
 	"path"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -29,6 +31,7 @@ import (
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/storage"
+	promstats "github.com/prometheus/prometheus/util/stats"
 	v1 "github.com/prometheus/prometheus/web/api/v1"
 
 	"github.com/grafana/mimir/pkg/querier"
@@ -277,7 +280,7 @@ func NewQuerierHandler(
 		// This is used for the stats API which we should not support. Or find other ways to.
 		prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) { return nil, nil }),
 		reg,
-		nil,
+		querierStatsRenderer,
 		remoteWriteEnabled,
 		oltpEnabled,
 	)
@@ -330,6 +333,7 @@ func NewQuerierHandler(
 	router.Path(path.Join(prefix, "/api/v1/metadata")).Methods("GET").Handler(metadataQueryStats.Wrap(querier.NewMetadataHandler(metadataSupplier)))
 	router.Path(path.Join(prefix, "/api/v1/cardinality/label_names")).Methods("GET", "POST").Handler(cardinalityQueryStats.Wrap(querier.LabelNamesCardinalityHandler(distributor, limits)))
 	router.Path(path.Join(prefix, "/api/v1/cardinality/label_values")).Methods("GET", "POST").Handler(cardinalityQueryStats.Wrap(querier.LabelValuesCardinalityHandler(distributor, limits)))
+	router.Path(path.Join(prefix, "/api/v1/cardinality/history")).Methods("GET", "POST").Handler(cardinalityQueryStats.Wrap(querier.CardinalityHistoryHandler(distributor, limits)))
 	router.Path(path.Join(prefix, "/api/v1/cardinality/active_series")).Methods("GET", "POST").Handler(cardinalityQueryStats.Wrap(querier.ActiveSeriesCardinalityHandler(distributor, limits)))
 	router.Path(path.Join(prefix, "/api/v1/cardinality/active_native_histogram_metrics")).Methods("GET", "POST").Handler(cardinalityQueryStats.Wrap(querier.ActiveNativeHistogramMetricsHandler(distributor, limits)))
 	router.Path(path.Join(prefix, "/api/v1/format_query")).Methods("GET", "POST").Handler(formattingQueryStats.Wrap(promRouter))
@@ -341,6 +345,35 @@ func NewQuerierHandler(
 //go:embed memberlist_status.gohtml
 var memberlistStatusPageHTML string
 
+// querierStatsRenderer tracks the samples processed by the engine in the query stats, if the query stats
+// are enabled. In this case, the stats are returned to the query-frontend, which merges the stats of all the
+// queries it runs for a request and renders them in the response, so the querier doesn't render them.
+func querierStatsRenderer(ctx context.Context, s *promstats.Statistics, param string) promstats.QueryStats {
+	queryStats := stats.FromContext(ctx)
+	if queryStats == nil {
+		return v1.DefaultStatsRenderer(ctx, s, param)
+	}
+
+	if s == nil || s.Samples == nil {
+		return nil
+	}
+
+	queryStats.AddSamplesProcessed(uint64(s.Samples.TotalSamples))
+
+	if perStep := s.Samples.TotalSamplesPerStepMap(); perStep != nil {
+		steps := make([]stats.StepStat, 0, len(*perStep))
+		for ts, v := range *perStep {
+			steps = append(steps, stats.StepStat{Timestamp: ts, Value: int64(v)})
+		}
+		slices.SortFunc(steps, func(a, b stats.StepStat) int {
+			return cmp.Compare(a.Timestamp, b.Timestamp)
+		})
+		queryStats.AddSamplesProcessedPerStep(steps)
+	}
+
+	return nil
+}
+
 func memberlistStatusHandler(httpPathPrefix string, kvs *memberlist.KVInitService) http.Handler {
 	templ := template.New("memberlist_status")
 	templ.Funcs(map[string]interface{}{
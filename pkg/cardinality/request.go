@@ -14,6 +14,8 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql/parser"
 	"golang.org/x/exp/slices"
+
+	"github.com/grafana/mimir/pkg/util"
 )
 
 type CountMethod string
@@ -178,6 +180,51 @@ func DecodeLabelValuesRequestFromValues(values url.Values) (*LabelValuesRequest,
 	return parsed, nil
 }
 
+type HistoryRequest struct {
+	Start int64
+	End   int64
+	Limit int
+}
+
+// DecodeHistoryRequest decodes the input http.Request into a HistoryRequest.
+// The input http.Request can either be a GET or POST with URL-encoded parameters.
+func DecodeHistoryRequest(r *http.Request) (*HistoryRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return DecodeHistoryRequestFromValues(r.Form)
+}
+
+// DecodeHistoryRequestFromValues is like DecodeHistoryRequest but takes url.Values in input.
+func DecodeHistoryRequestFromValues(values url.Values) (*HistoryRequest, error) {
+	var (
+		parsed = &HistoryRequest{}
+		err    error
+	)
+
+	parsed.Start, err = extractTime(values, "start")
+	if err != nil {
+		return nil, err
+	}
+
+	parsed.End, err = extractTime(values, "end")
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Start > 0 && parsed.End > 0 && parsed.End < parsed.Start {
+		return nil, fmt.Errorf("'end' param cannot be before 'start' param")
+	}
+
+	parsed.Limit, err = extractLimit(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
 // extractSelector parses and gets selector query parameter containing a single matcher
 func extractSelector(values url.Values) (matchers []*labels.Matcher, err error) {
 	selectorParams := values["selector"]
@@ -237,6 +284,22 @@ func extractLimit(values url.Values) (limit int, err error) {
 	return limit, nil
 }
 
+// extractTime parses the request param with the given name if it's defined, otherwise returns 0.
+func extractTime(values url.Values, name string) (int64, error) {
+	params := values[name]
+	if len(params) == 0 {
+		return 0, nil
+	}
+	if len(params) > 1 {
+		return 0, fmt.Errorf("multiple '%s' params are not allowed", name)
+	}
+	ts, err := util.ParseTime(params[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid '%s' param: %w", name, err)
+	}
+	return ts, nil
+}
+
 // extractLabelNames parses and gets label_names query parameter containing an array of label values
 func extractLabelNames(values url.Values) ([]model.LabelName, error) {
 	labelNamesParams := values["label_names[]"]
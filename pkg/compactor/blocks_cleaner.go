@@ -387,6 +387,12 @@ func (c *BlocksCleaner) deleteUserMarkedForDeletion(ctx context.Context, userID
 		level.Info(userLogger).Log("msg", "deleted files under "+block.DebugMetas+" for tenant marked for deletion", "count", deleted)
 	}
 
+	if deleted, err := bucket.DeletePrefix(ctx, userBucket, mimir_tsdb.SeriesDeletionRequestsPath, userLogger); err != nil {
+		return errors.Wrap(err, "failed to delete series deletion requests")
+	} else if deleted > 0 {
+		level.Info(userLogger).Log("msg", "deleted series deletion requests for tenant marked for deletion", "count", deleted)
+	}
+
 	// Tenant deletion mark file is inside Markers as well.
 	if deleted, err := bucket.DeletePrefix(ctx, userBucket, block.MarkersPathname, userLogger); err != nil {
 		return errors.Wrap(err, "failed to delete marker files")
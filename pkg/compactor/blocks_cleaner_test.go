@@ -36,6 +36,7 @@ import (
 	mimir_testutil "github.com/grafana/mimir/pkg/storage/tsdb/testutil"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/test"
+	"github.com/grafana/mimir/pkg/util/validation"
 )
 
 type testBlocksCleanerOptions struct {
@@ -1254,6 +1255,7 @@ func (m *mockBucketFailure) Delete(ctx context.Context, name string) error {
 
 type mockConfigProvider struct {
 	userRetentionPeriods         map[string]time.Duration
+	seriesRetentionRules         map[string][]*validation.SeriesRetentionRule
 	splitAndMergeShards          map[string]int
 	instancesShardSize           map[string]int
 	splitGroups                  map[string]int
@@ -1268,6 +1270,7 @@ type mockConfigProvider struct {
 func newMockConfigProvider() *mockConfigProvider {
 	return &mockConfigProvider{
 		userRetentionPeriods:         make(map[string]time.Duration),
+		seriesRetentionRules:         make(map[string][]*validation.SeriesRetentionRule),
 		splitAndMergeShards:          make(map[string]int),
 		splitGroups:                  make(map[string]int),
 		blockUploadEnabled:           make(map[string]bool),
@@ -1286,6 +1289,10 @@ func (m *mockConfigProvider) CompactorBlocksRetentionPeriod(user string) time.Du
 	return 0
 }
 
+func (m *mockConfigProvider) CompactorSeriesRetentionRules(user string) []*validation.SeriesRetentionRule {
+	return m.seriesRetentionRules[user]
+}
+
 func (m *mockConfigProvider) CompactorSplitAndMergeShards(user string) int {
 	if result, ok := m.splitAndMergeShards[user]; ok {
 		return result
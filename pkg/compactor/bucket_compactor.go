@@ -309,6 +309,7 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 	// Once we have a plan we need to download the actual data.
 	downloadBegin := time.Now()
 
+	hasDeletedSamples := make([]bool, len(toCompact))
 	err = concurrency.ForEachJob(ctx, len(toCompact), c.blockSyncConcurrency, func(ctx context.Context, idx int) error {
 		meta := toCompact[idx]
 
@@ -343,6 +344,13 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 		if err := stats.OutOfOrderLabelsErr(); err != nil {
 			return errors.Wrapf(err, "block id %s", meta.ULID)
 		}
+
+		// Write tombstones for the samples deleted by series deletion requests and retention rules, so that they're not compacted.
+		deleted, err := applySeriesDeletionRequests(ctx, jobLogger, bdir, meta, c.seriesDeletionRequests)
+		if err != nil {
+			return errors.Wrapf(err, "apply series deletion requests to block %s", meta.ULID)
+		}
+		hasDeletedSamples[idx] = deleted
 		return nil
 	})
 	if err != nil {
@@ -371,8 +379,9 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 	if !hasNonZeroULIDs(compIDs) {
 		// Prometheus compactor found that the compacted block would have no samples.
 		level.Info(jobLogger).Log("msg", "compacted block would have no samples, deleting source blocks", "block_count", blockCount, "blocks", toCompactStr)
-		for _, meta := range toCompact {
-			if meta.Stats.NumSamples == 0 {
+		for ix, meta := range toCompact {
+			// Blocks may have samples if all of them have been deleted by series deletion requests or retention rules.
+			if meta.Stats.NumSamples == 0 || hasDeletedSamples[ix] {
 				if err := deleteBlock(c.bkt, meta.ULID, filepath.Join(subDir, meta.ULID.String()), jobLogger, c.metrics.blocksMarkedForDeletion); err != nil {
 					level.Warn(jobLogger).Log("msg", "failed to mark for deletion an empty block found during compaction", "block", meta.ULID, "err", err)
 				}
@@ -412,6 +421,8 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 			Downsample:   block.ThanosDownsample{Resolution: job.Resolution()},
 			Source:       block.CompactorSource,
 			SegmentFiles: block.GetSegmentFiles(bdir),
+			// The compacted block time range is within the job one.
+			SeriesRetentionRules: c.seriesDeletions.expiredRetentionRules(job.MaxTime()),
 		}, nil)
 		if err != nil {
 			return errors.Wrapf(err, "failed to finalize the block %s", bdir)
@@ -761,6 +772,10 @@ type BucketCompactor struct {
 	waitPeriod           time.Duration
 	blockSyncConcurrency int
 	metrics              *BucketCompactorMetrics
+
+	// Series deletion requests and retention rules applied to the blocks being compacted.
+	seriesDeletions        *seriesDeletions
+	seriesDeletionRequests []*mimir_tsdb.SeriesDeletionRequest
 }
 
 // NewBucketCompactor creates a new bucket compactor.
@@ -779,6 +794,7 @@ func NewBucketCompactor(
 	waitPeriod time.Duration,
 	blockSyncConcurrency int,
 	metrics *BucketCompactorMetrics,
+	seriesDeletions *seriesDeletions,
 ) (*BucketCompactor, error) {
 	if concurrency <= 0 {
 		return nil, errors.Errorf("invalid concurrency level (%d), concurrency level must be > 0", concurrency)
@@ -798,6 +814,9 @@ func NewBucketCompactor(
 		waitPeriod:           waitPeriod,
 		blockSyncConcurrency: blockSyncConcurrency,
 		metrics:              metrics,
+
+		seriesDeletions:        seriesDeletions,
+		seriesDeletionRequests: seriesDeletions.allRequests(),
 	}, nil
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/grafana/dskit/kv"
 	"github.com/grafana/dskit/ring"
 	"github.com/grafana/dskit/services"
+	"github.com/oklog/ulid"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -37,6 +38,7 @@ import (
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/util"
 	util_log "github.com/grafana/mimir/pkg/util/log"
+	"github.com/grafana/mimir/pkg/util/validation"
 )
 
 const (
@@ -209,6 +211,9 @@ type ConfigProvider interface {
 	// CompactorBlocksRetentionPeriod returns the retention period for a given user.
 	CompactorBlocksRetentionPeriod(user string) time.Duration
 
+	// CompactorSeriesRetentionRules returns the per-series retention rules for a given user.
+	CompactorSeriesRetentionRules(userID string) []*validation.SeriesRetentionRule
+
 	// CompactorSplitAndMergeShards returns the number of shards to use when splitting blocks.
 	CompactorSplitAndMergeShards(userID string) int
 
@@ -286,6 +291,15 @@ type MultitenantCompactor struct {
 	compactionRunInterval          prometheus.Gauge
 	blocksMarkedForDeletion        prometheus.Counter
 
+	// Series deletion metrics.
+	seriesDeletionRequestsProcessed         prometheus.Counter
+	blocksRewrittenForSeriesDeletion        prometheus.Counter
+	blocksMarkedForDeletionBySeriesDeletion prometheus.Counter
+
+	// Blocks checked for per-series retention rules without any sample to delete, by tenant.
+	// The value is the list of retention rules the block has been checked for.
+	seriesRetentionCheckedBlocks map[string]map[ulid.ULID]string
+
 	// outOfSpace is a separate metric for out-of-space errors because this is a common issue which often requires an operator to investigate,
 	// so alerts need to be able to treat it with higher priority than other compaction errors.
 	outOfSpace prometheus.Counter
@@ -347,6 +361,8 @@ func newMultitenantCompactor(
 		blocksGrouperFactory:   blocksGrouperFactory,
 		blocksCompactorFactory: blocksCompactorFactory,
 
+		seriesRetentionCheckedBlocks: map[string]map[ulid.ULID]string{},
+
 		compactionRunsStarted: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
 			Name: "cortex_compactor_runs_started_total",
 			Help: "Total number of compaction runs started.",
@@ -398,6 +414,19 @@ func newMultitenantCompactor(
 			Help:        blocksMarkedForDeletionHelp,
 			ConstLabels: prometheus.Labels{"reason": "compaction"},
 		}),
+		seriesDeletionRequestsProcessed: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_series_deletion_requests_processed_total",
+			Help: "Total number of series deletion requests processed by the compactor.",
+		}),
+		blocksRewrittenForSeriesDeletion: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_blocks_rewritten_for_series_deletion_total",
+			Help: "Total number of blocks rewritten by the compactor to delete the samples of series deletion requests and retention rules.",
+		}),
+		blocksMarkedForDeletionBySeriesDeletion: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Name:        blocksMarkedForDeletionName,
+			Help:        blocksMarkedForDeletionHelp,
+			ConstLabels: prometheus.Labels{"reason": "series-deletion"},
+		}),
 		blockUploadBlocks: promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
 			Name: "cortex_block_upload_api_blocks_total",
 			Help: "Total number of blocks successfully uploaded and validated using the block upload API.",
@@ -744,6 +773,12 @@ func (c *MultitenantCompactor) compactUser(ctx context.Context, userID string) e
 	userBucket := bucket.NewUserBucketClient(userID, c.bucketClient, c.cfgProvider)
 	userLogger := util_log.WithUserID(userID, c.logger)
 
+	// Series deletion requests and retention rules are applied to the blocks being compacted.
+	seriesDeletions, err := c.loadSeriesDeletions(ctx, userID, userLogger)
+	if err != nil {
+		return err
+	}
+
 	reg := prometheus.NewRegistry()
 	defer c.syncerMetrics.gatherThanosSyncerMetrics(reg, userLogger)
 
@@ -798,6 +833,7 @@ func (c *MultitenantCompactor) compactUser(ctx context.Context, userID string) e
 		c.compactorCfg.CompactionWaitPeriod,
 		c.compactorCfg.BlockSyncConcurrency,
 		c.bucketCompactorMetrics,
+		seriesDeletions,
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to create bucket compactor")
@@ -807,6 +843,24 @@ func (c *MultitenantCompactor) compactUser(ctx context.Context, userID string) e
 		return errors.Wrap(err, "compaction")
 	}
 
+	// Blocks are rewritten by a single compactor, the one owning the tenant for the blocks cleanup.
+	owned, err := c.shardingStrategy.blocksCleanerOwnsUser(userID)
+	if err != nil {
+		level.Info(userLogger).Log("msg", "skipped series deletion and retention because unable to check whether the tenant is owned by the compactor instance", "err", err)
+		return nil
+	}
+	if !owned {
+		return nil
+	}
+
+	if err := c.processSeriesDeletionRequests(ctx, userID, userBucket, seriesDeletions, userLogger); err != nil {
+		return errors.Wrap(err, "series deletion")
+	}
+
+	if err := c.processSeriesRetentionRules(ctx, userID, userBucket, seriesDeletions, userLogger); err != nil {
+		return errors.Wrap(err, "series retention")
+	}
+
 	return nil
 }
 
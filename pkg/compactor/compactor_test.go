@@ -246,6 +246,7 @@ func TestMultitenantCompactor_ShouldDoNothingOnNoUserBlocks(t *testing.T) {
 		cortex_compactor_blocks_marked_for_deletion_total{reason="compaction"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="partial"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="retention"} 0
+		cortex_compactor_blocks_marked_for_deletion_total{reason="series-deletion"} 0
 
 		# TYPE cortex_compactor_block_cleanup_started_total counter
 		# HELP cortex_compactor_block_cleanup_started_total Total number of blocks cleanup runs started.
@@ -385,6 +386,7 @@ func TestMultitenantCompactor_ShouldRetryCompactionOnFailureWhileDiscoveringUser
 		cortex_compactor_blocks_marked_for_deletion_total{reason="compaction"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="partial"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="retention"} 0
+		cortex_compactor_blocks_marked_for_deletion_total{reason="series-deletion"} 0
 
 		# TYPE cortex_compactor_block_cleanup_started_total counter
 		# HELP cortex_compactor_block_cleanup_started_total Total number of blocks cleanup runs started.
@@ -428,6 +430,7 @@ func TestMultitenantCompactor_ShouldIncrementCompactionErrorIfFailedToCompactASi
 	bucketClient.MockIter("", []string{userID}, nil)
 	bucketClient.MockIter(userID+"/", []string{userID + "/01DTVP434PA9VFXSW2JKB3392D", userID + "/01DTW0ZCPDDNV4BV83Q2SV4QAZ"}, nil)
 	bucketClient.MockIter(userID+"/markers/", nil, nil)
+	bucketClient.MockIter(userID+"/series-deletion-requests/", nil, nil)
 	bucketClient.MockExists(path.Join(userID, mimir_tsdb.TenantDeletionMarkPath), false, nil)
 	bucketClient.MockGet(userID+"/01DTVP434PA9VFXSW2JKB3392D/meta.json", mockBlockMetaJSON("01DTVP434PA9VFXSW2JKB3392D"), nil)
 	bucketClient.MockGet(userID+"/01DTVP434PA9VFXSW2JKB3392D/deletion-mark.json", "", nil)
@@ -477,6 +480,7 @@ func TestMultitenantCompactor_ShouldIncrementCompactionShutdownIfTheContextIsCan
 	bucketClient.MockIter("", []string{userID}, nil)
 	bucketClient.MockIter(userID+"/", []string{userID + "/01DTVP434PA9VFXSW2JKB3392D", userID + "/01DTW0ZCPDDNV4BV83Q2SV4QAZ"}, nil)
 	bucketClient.MockIter(userID+"/markers/", nil, nil)
+	bucketClient.MockIter(userID+"/series-deletion-requests/", nil, nil)
 	bucketClient.MockExists(path.Join(userID, mimir_tsdb.TenantDeletionMarkPath), false, nil)
 	bucketClient.MockGet(userID+"/01DTVP434PA9VFXSW2JKB3392D/meta.json", mockBlockMetaJSON("01DTVP434PA9VFXSW2JKB3392D"), nil)
 	bucketClient.MockGet(userID+"/01DTVP434PA9VFXSW2JKB3392D/deletion-mark.json", "", nil)
@@ -548,7 +552,9 @@ func TestMultitenantCompactor_ShouldIterateOverUsersAndRunCompaction(t *testing.
 	bucketClient.MockGet("user-1/bucket-index.json.gz", "", nil)
 	bucketClient.MockGet("user-2/bucket-index.json.gz", "", nil)
 	bucketClient.MockIter("user-1/markers/", nil, nil)
+	bucketClient.MockIter("user-1/series-deletion-requests/", nil, nil)
 	bucketClient.MockIter("user-2/markers/", nil, nil)
+	bucketClient.MockIter("user-2/series-deletion-requests/", nil, nil)
 	bucketClient.MockUpload("user-1/bucket-index.json.gz", nil)
 	bucketClient.MockUpload("user-2/bucket-index.json.gz", nil)
 
@@ -650,6 +656,7 @@ func TestMultitenantCompactor_ShouldIterateOverUsersAndRunCompaction(t *testing.
 		cortex_compactor_blocks_marked_for_deletion_total{reason="compaction"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="partial"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="retention"} 0
+		cortex_compactor_blocks_marked_for_deletion_total{reason="series-deletion"} 0
 
 		# TYPE cortex_compactor_block_cleanup_started_total counter
 		# HELP cortex_compactor_block_cleanup_started_total Total number of blocks cleanup runs started.
@@ -688,6 +695,7 @@ func TestMultitenantCompactor_ShouldStopCompactingTenantOnReachingMaxCompactionT
 	bucketClient.MockGet("user-1/01FRQGQB7RWQ2TS0VWA82QTPXE/no-compact-mark.json", "", nil)
 	bucketClient.MockGet("user-1/bucket-index.json.gz", "", nil)
 	bucketClient.MockIter("user-1/markers/", nil, nil)
+	bucketClient.MockIter("user-1/series-deletion-requests/", nil, nil)
 	bucketClient.MockUpload("user-1/bucket-index.json.gz", nil)
 
 	cfg := prepareConfig(t)
@@ -764,6 +772,7 @@ func TestMultitenantCompactor_ShouldNotCompactBlocksMarkedForDeletion(t *testing
 		"user-1/markers/01DTVP434PA9VFXSW2JKB3392D-deletion-mark.json",
 		"user-1/markers/01DTW0ZCPDDNV4BV83Q2SV4QAZ-deletion-mark.json",
 	}, nil)
+	bucketClient.MockIter("user-1/series-deletion-requests/", nil, nil)
 
 	bucketClient.MockDelete("user-1/01DTW0ZCPDDNV4BV83Q2SV4QAZ/meta.json", nil)
 	bucketClient.MockDelete("user-1/01DTW0ZCPDDNV4BV83Q2SV4QAZ/deletion-mark.json", nil)
@@ -836,6 +845,7 @@ func TestMultitenantCompactor_ShouldNotCompactBlocksMarkedForDeletion(t *testing
 		cortex_compactor_blocks_marked_for_deletion_total{reason="compaction"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="partial"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="retention"} 0
+		cortex_compactor_blocks_marked_for_deletion_total{reason="series-deletion"} 0
 
 		# TYPE cortex_compactor_block_cleanup_started_total counter
 		# HELP cortex_compactor_block_cleanup_started_total Total number of blocks cleanup runs started.
@@ -869,6 +879,7 @@ func TestMultitenantCompactor_ShouldNotCompactBlocksMarkedForNoCompaction(t *tes
 	bucketClient.MockGet("user-1/01DTVP434PA9VFXSW2JKB3392D/no-compact-mark.json", `{"id":"01DTVP434PA9VFXSW2JKB3392D","version":1,"details":"details","no_compact_time":1637757932,"reason":"reason"}`, nil)
 
 	bucketClient.MockIter("user-1/markers/", []string{"user-1/markers/01DTVP434PA9VFXSW2JKB3392D-no-compact-mark.json"}, nil)
+	bucketClient.MockIter("user-1/series-deletion-requests/", nil, nil)
 
 	bucketClient.MockGet("user-1/bucket-index.json.gz", "", nil)
 	bucketClient.MockUpload("user-1/bucket-index.json.gz", nil)
@@ -994,6 +1005,7 @@ func TestMultitenantCompactor_ShouldNotCompactBlocksForUsersMarkedForDeletion(t
 		cortex_compactor_blocks_marked_for_deletion_total{reason="compaction"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="partial"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="retention"} 0
+		cortex_compactor_blocks_marked_for_deletion_total{reason="series-deletion"} 0
 
 		# TYPE cortex_compactor_block_cleanup_started_total counter
 		# HELP cortex_compactor_block_cleanup_started_total Total number of blocks cleanup runs started.
@@ -1020,7 +1032,9 @@ func TestMultitenantCompactor_ShouldCompactAllUsersOnShardingEnabledButOnlyOneIn
 	bucketClient.MockIter("user-1/", []string{"user-1/01DTVP434PA9VFXSW2JKB3392D", "user-1/01FSTQ95C8FS0ZAGTQS2EF1NEG"}, nil)
 	bucketClient.MockIter("user-2/", []string{"user-2/01DTW0ZCPDDNV4BV83Q2SV4QAZ", "user-2/01FSV54G6QFQH1G9QE93G3B9TB"}, nil)
 	bucketClient.MockIter("user-1/markers/", nil, nil)
+	bucketClient.MockIter("user-1/series-deletion-requests/", nil, nil)
 	bucketClient.MockIter("user-2/markers/", nil, nil)
+	bucketClient.MockIter("user-2/series-deletion-requests/", nil, nil)
 	bucketClient.MockGet("user-1/01DTVP434PA9VFXSW2JKB3392D/meta.json", mockBlockMetaJSON("01DTVP434PA9VFXSW2JKB3392D"), nil)
 	bucketClient.MockGet("user-1/01DTVP434PA9VFXSW2JKB3392D/deletion-mark.json", "", nil)
 	bucketClient.MockGet("user-1/01DTVP434PA9VFXSW2JKB3392D/no-compact-mark.json", "", nil)
@@ -1126,6 +1140,7 @@ func TestMultitenantCompactor_ShouldCompactAllUsersOnShardingEnabledButOnlyOneIn
 		cortex_compactor_blocks_marked_for_deletion_total{reason="compaction"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="partial"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="retention"} 0
+		cortex_compactor_blocks_marked_for_deletion_total{reason="series-deletion"} 0
 	`),
 		"cortex_compactor_runs_started_total",
 		"cortex_compactor_runs_completed_total",
@@ -1155,6 +1170,7 @@ func TestMultitenantCompactor_ShouldCompactOnlyUsersOwnedByTheInstanceOnSharding
 	for _, userID := range userIDs {
 		bucketClient.MockIter(userID+"/", []string{userID + "/01DTVP434PA9VFXSW2JKB3392D"}, nil)
 		bucketClient.MockIter(userID+"/markers/", nil, nil)
+		bucketClient.MockIter(userID+"/series-deletion-requests/", nil, nil)
 		bucketClient.MockExists(path.Join(userID, mimir_tsdb.TenantDeletionMarkPath), false, nil)
 		bucketClient.MockGet(userID+"/01DTVP434PA9VFXSW2JKB3392D/meta.json", mockBlockMetaJSON("01DTVP434PA9VFXSW2JKB3392D"), nil)
 		bucketClient.MockGet(userID+"/01DTVP434PA9VFXSW2JKB3392D/deletion-mark.json", "", nil)
@@ -1327,6 +1343,7 @@ func TestMultitenantCompactor_ShouldSkipCompactionForJobsNoMoreOwnedAfterPlannin
 	bucketClient.MockExists(path.Join("user-1", mimir_tsdb.TenantDeletionMarkPath), false, nil)
 	bucketClient.MockIter("user-1/", []string{"user-1/01DTVP434PA9VFXSW2JK000001", "user-1/01DTVP434PA9VFXSW2JK000002"}, nil)
 	bucketClient.MockIter("user-1/markers/", nil, nil)
+	bucketClient.MockIter("user-1/series-deletion-requests/", nil, nil)
 	bucketClient.MockGet("user-1/01DTVP434PA9VFXSW2JK000001/meta.json", mockBlockMetaJSONWithTimeRange("01DTVP434PA9VFXSW2JK000001", 1574776800000, 1574784000000), nil)
 	bucketClient.MockGet("user-1/01DTVP434PA9VFXSW2JK000001/deletion-mark.json", "", nil)
 	bucketClient.MockGet("user-1/01DTVP434PA9VFXSW2JK000001/no-compact-mark.json", "", nil)
@@ -1396,6 +1413,7 @@ func TestMultitenantCompactor_ShouldSkipCompactionForJobsNoMoreOwnedAfterPlannin
 		`level=info component=compactor user=user-1 groupKey=0@17241709254077376921-split-4_of_4-1574776800000-1574784000000 job_type=split msg="compaction job succeeded" block_count=1`,
 		`level=info component=compactor user=user-1 msg="skipped compaction because unable to check whether the job is owned by the compactor instance" groupKey=0@17241709254077376921-split-1_of_4-1574863200000-1574870400000 err="at least 1 live replicas required, could only find 0 - unhealthy instances: 1.2.3.4:0"`,
 		`level=info component=compactor user=user-1 msg="compaction iterations done"`,
+		`level=info component=compactor user=user-1 msg="skipped series deletion and retention because unable to check whether the tenant is owned by the compactor instance" err="at least 1 live replicas required, could only find 0 - unhealthy instances: 1.2.3.4:0"`,
 		`level=info component=compactor msg="successfully compacted user blocks" user=user-1`,
 	}, removeIgnoredLogs(strings.Split(strings.TrimSpace(logs.String()), "\n")))
 
@@ -1434,6 +1452,7 @@ func TestMultitenantCompactor_ShouldSkipCompactionForJobsNoMoreOwnedAfterPlannin
 		cortex_compactor_blocks_marked_for_deletion_total{reason="compaction"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="partial"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="retention"} 0
+		cortex_compactor_blocks_marked_for_deletion_total{reason="series-deletion"} 0
 	`),
 		"cortex_compactor_runs_started_total",
 		"cortex_compactor_runs_completed_total",
@@ -1544,6 +1563,7 @@ func TestMultitenantCompactor_ShouldSkipCompactionForJobsWithFirstLevelCompactio
 		cortex_compactor_blocks_marked_for_deletion_total{reason="compaction"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="partial"} 0
 		cortex_compactor_blocks_marked_for_deletion_total{reason="retention"} 0
+		cortex_compactor_blocks_marked_for_deletion_total{reason="series-deletion"} 0
 	`),
 		"cortex_compactor_runs_started_total",
 		"cortex_compactor_runs_completed_total",
@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/thanos-io/objstore"
+
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+// applySeriesDeletionRequests writes tombstones to the block stored in blockDir for the samples
+// deleted by the input requests, so that they're removed when the block is compacted.
+// Returns whether any sample of the block has been deleted.
+//
+// The block meta.json file is rewritten without the Thanos section, so the block must not be uploaded as is.
+func applySeriesDeletionRequests(ctx context.Context, logger log.Logger, blockDir string, meta *block.Meta, requests []*mimir_tsdb.SeriesDeletionRequest) (bool, error) {
+	var overlapping []*mimir_tsdb.SeriesDeletionRequest
+	for _, r := range requests {
+		// Block max time is exclusive.
+		if r.Overlaps(meta.MinTime, meta.MaxTime-1) {
+			overlapping = append(overlapping, r)
+		}
+	}
+	if len(overlapping) == 0 {
+		return false, nil
+	}
+
+	b, err := tsdb.OpenBlock(logger, blockDir, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "open block")
+	}
+	defer func() {
+		if err := b.Close(); err != nil {
+			level.Warn(logger).Log("msg", "failed to close block", "block", meta.ULID, "err", err)
+		}
+	}()
+
+	for _, r := range overlapping {
+		matchers, err := r.Matchers()
+		if err != nil {
+			return false, errors.Wrapf(err, "series deletion request %s", r.RequestID)
+		}
+
+		for _, ms := range matchers {
+			if err := b.Delete(ctx, r.StartTime, r.EndTime, ms...); err != nil {
+				return false, errors.Wrapf(err, "apply series deletion request %s", r.RequestID)
+			}
+		}
+	}
+
+	return b.Meta().Stats.NumTombstones > 0, nil
+}
+
+// processSeriesDeletionRequests rewrites the blocks which may contain samples deleted by pending series deletion
+// requests, and marks the requests as processed once all such blocks have been rewritten. Blocks created by the
+// compactor after a request has been created are skipped, given the request has been applied while compacting them.
+func (c *MultitenantCompactor) processSeriesDeletionRequests(ctx context.Context, userID string, userBucket objstore.InstrumentedBucket, deletions *seriesDeletions, logger log.Logger) error {
+	var pending []*mimir_tsdb.SeriesDeletionRequest
+	for _, r := range deletions.requests {
+		if r.Status() == mimir_tsdb.SeriesDeletionRequestStatusPending {
+			pending = append(pending, r)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	metas, ids, err := c.fetchBlocksToRewrite(ctx, userID, userBucket, logger)
+	if err != nil {
+		return err
+	}
+
+	workDir := filepath.Join(c.compactorCfg.DataDir, "series-deletion", userID)
+	defer func() {
+		if err := os.RemoveAll(workDir); err != nil {
+			level.Error(logger).Log("msg", "failed to remove series deletion work directory", "path", workDir, "err", err)
+		}
+	}()
+
+	// Blocks are rewritten applying all requests, so each block needs to be checked once.
+	checked := map[ulid.ULID]struct{}{}
+
+	for _, r := range pending {
+		for _, id := range ids {
+			meta := metas[id]
+			if _, ok := checked[id]; ok || !seriesDeletionRequestRequiresRewrite(r, meta) {
+				continue
+			}
+
+			if _, err := c.rewriteBlockWithSeriesDeletions(ctx, userBucket, workDir, meta, deletions, logger); err != nil {
+				return errors.Wrapf(err, "rewrite block %s", id)
+			}
+			checked[id] = struct{}{}
+		}
+
+		r.ProcessedTime = util.UnixSecondsFromTime(time.Now())
+		if err := mimir_tsdb.WriteSeriesDeletionRequest(ctx, c.bucketClient, userID, c.cfgProvider, r); err != nil {
+			return err
+		}
+
+		c.seriesDeletionRequestsProcessed.Inc()
+		level.Info(logger).Log("msg", "processed series deletion request", "request_id", r.RequestID)
+	}
+
+	return nil
+}
+
+// fetchBlocksToRewrite returns the metas of the tenant blocks not marked for deletion, and their IDs sorted.
+func (c *MultitenantCompactor) fetchBlocksToRewrite(ctx context.Context, userID string, userBucket objstore.InstrumentedBucket, logger log.Logger) (map[ulid.ULID]*block.Meta, []ulid.ULID, error) {
+	// Blocks marked for no-compaction must be rewritten too, so we don't use the compaction filters.
+	fetcher, err := block.NewMetaFetcher(logger, c.compactorCfg.MetaSyncConcurrency, userBucket, c.metaSyncDirForUser(userID), nil, []block.MetadataFilter{
+		NewLabelRemoverFilter(compactionIgnoredLabels),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metas, _, err := fetcher.FetchWithoutMarkedForDeletion(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "fetch blocks")
+	}
+
+	ids := make([]ulid.ULID, 0, len(metas))
+	for id := range metas {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].Compare(ids[j]) < 0
+	})
+
+	return metas, ids, nil
+}
+
+func seriesDeletionRequestRequiresRewrite(r *mimir_tsdb.SeriesDeletionRequest, meta *block.Meta) bool {
+	// Block max time is exclusive.
+	if !r.Overlaps(meta.MinTime, meta.MaxTime-1) {
+		return false
+	}
+
+	createdAfterRequest := ulid.Time(meta.ULID.Time()).After(r.CreationTime.Time())
+	return meta.Thanos.Source != block.CompactorSource || !createdAfterRequest
+}
+
+// rewriteBlockWithSeriesDeletions downloads the block, and if any of its samples are deleted by the input series deletion
+// requests or retention rules, uploads a copy of the block without them and marks the original block for deletion.
+// Returns whether the block has been rewritten.
+func (c *MultitenantCompactor) rewriteBlockWithSeriesDeletions(ctx context.Context, userBucket objstore.Bucket, workDir string, meta *block.Meta, deletions *seriesDeletions, logger log.Logger) (bool, error) {
+	logger = log.With(logger, "block", meta.ULID)
+
+	if err := os.RemoveAll(workDir); err != nil {
+		return false, errors.Wrap(err, "clean series deletion work directory")
+	}
+	if err := os.MkdirAll(workDir, 0750); err != nil {
+		return false, errors.Wrap(err, "create series deletion work directory")
+	}
+
+	bdir := filepath.Join(workDir, meta.ULID.String())
+	if err := block.Download(ctx, logger, userBucket, meta.ULID, bdir); err != nil {
+		return false, errors.Wrap(err, "download block")
+	}
+
+	deleted, err := applySeriesDeletionRequests(ctx, logger, bdir, meta, deletions.allRequests())
+	if err != nil {
+		return false, err
+	}
+	if !deleted {
+		level.Debug(logger).Log("msg", "block has no samples deleted by series deletion requests or retention rules")
+		return false, nil
+	}
+
+	compIDs, err := c.blocksCompactor.Compact(workDir, []string{bdir}, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "rewrite block")
+	}
+
+	if hasNonZeroULIDs(compIDs) {
+		newDir := filepath.Join(workDir, compIDs[0].String())
+
+		newMeta, err := block.InjectThanosMeta(logger, newDir, block.ThanosMeta{
+			Labels:       meta.Thanos.Labels,
+			Downsample:   meta.Thanos.Downsample,
+			Source:       block.CompactorSource,
+			SegmentFiles: block.GetSegmentFiles(newDir),
+			// The rewritten block has the same time range of the original one.
+			SeriesRetentionRules: deletions.expiredRetentionRules(meta.MaxTime),
+		}, nil)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to finalize the block %s", newDir)
+		}
+
+		if err = os.Remove(filepath.Join(newDir, "tombstones")); err != nil {
+			return false, errors.Wrap(err, "remove tombstones")
+		}
+
+		if err := block.VerifyBlock(ctx, logger, newDir, newMeta.MinTime, newMeta.MaxTime, false); err != nil {
+			return false, errors.Wrapf(err, "invalid rewritten block %s", newDir)
+		}
+
+		if err := block.Upload(ctx, logger, userBucket, newDir, nil); err != nil {
+			return false, errors.Wrapf(err, "upload of %s failed", compIDs[0])
+		}
+
+		level.Info(logger).Log("msg", "uploaded block rewritten to apply series deletion requests and retention rules", "result_block", compIDs[0])
+	} else {
+		level.Info(logger).Log("msg", "all samples of block deleted by series deletion requests and retention rules")
+	}
+
+	c.blocksRewrittenForSeriesDeletion.Inc()
+
+	// Spawn a new context so we always mark a block for deletion in full on shutdown.
+	delCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := block.MarkForDeletion(delCtx, logger, userBucket, meta.ULID, "source of block rewritten to apply series deletion requests and retention rules", c.blocksMarkedForDeletionBySeriesDeletion); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+// DeleteSeries creates a request to delete the samples of the series matching the match[] selectors
+// within the start and end time range.
+func (c *MultitenantCompactor) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		// When Mimir is running, it uses Auth Middleware for checking X-Scope-OrgID and injecting tenant into context.
+		// Auth Middleware sends http.StatusUnauthorized if X-Scope-OrgID is missing, so we do too here, for consistency.
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	start, err := util.ParseTimeParam(r, "start", math.MinInt64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	end, err := util.ParseTimeParam(r, "end", now.UnixMilli())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req, err := mimir_tsdb.NewSeriesDeletionRequest(r.Form["match[]"], start, end, now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := mimir_tsdb.WriteSeriesDeletionRequest(ctx, c.bucketClient, userID, c.cfgProvider, req); err != nil {
+		level.Error(c.logger).Log("msg", "failed to write series deletion request", "user", userID, "err", err)
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	level.Info(c.logger).Log("msg", "series deletion request created", "user", userID, "request_id", req.RequestID, "selectors", req.Selectors, "start", req.StartTime, "end", req.EndTime)
+
+	util.WriteJSONResponse(w, newSeriesDeletionRequestStatus(req))
+}
+
+type SeriesDeletionRequestStatus struct {
+	RequestID     string   `json:"request_id"`
+	Selectors     []string `json:"selectors"`
+	StartTime     int64    `json:"start_time"`
+	EndTime       int64    `json:"end_time"`
+	CreationTime  int64    `json:"creation_time"`
+	ProcessedTime int64    `json:"processed_time,omitempty"`
+	Status        string   `json:"status"`
+}
+
+type DeleteSeriesStatusResponse struct {
+	TenantID string                        `json:"tenant_id"`
+	Requests []SeriesDeletionRequestStatus `json:"requests"`
+}
+
+// DeleteSeriesStatus returns the status of all series deletion requests of the tenant.
+func (c *MultitenantCompactor) DeleteSeriesStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		// When Mimir is running, it uses Auth Middleware for checking X-Scope-OrgID and injecting tenant into context.
+		// Auth Middleware sends http.StatusUnauthorized if X-Scope-OrgID is missing, so we do too here, for consistency.
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := mimir_tsdb.ReadSeriesDeletionRequests(ctx, c.bucketClient, userID, c.logger)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := DeleteSeriesStatusResponse{
+		TenantID: userID,
+		Requests: make([]SeriesDeletionRequestStatus, 0, len(requests)),
+	}
+	for _, req := range requests {
+		result.Requests = append(result.Requests, newSeriesDeletionRequestStatus(req))
+	}
+
+	util.WriteJSONResponse(w, result)
+}
+
+func newSeriesDeletionRequestStatus(r *mimir_tsdb.SeriesDeletionRequest) SeriesDeletionRequestStatus {
+	return SeriesDeletionRequestStatus{
+		RequestID:     r.RequestID,
+		Selectors:     r.Selectors,
+		StartTime:     r.StartTime,
+		EndTime:       r.EndTime,
+		CreationTime:  int64(r.CreationTime),
+		ProcessedTime: int64(r.ProcessedTime),
+		Status:        r.Status(),
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+)
+
+func TestDeleteSeries(t *testing.T) {
+	bkt := objstore.NewInMemBucket()
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), c))
+	t.Cleanup(stopServiceFn(t, c))
+
+	ctx := user.InjectOrgID(context.Background(), "fake")
+
+	t.Run("missing tenant", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		c.DeleteSeries(resp, httptest.NewRequest(http.MethodPost, "/compactor/delete_series?match[]=up", nil))
+		require.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+
+	for name, target := range map[string]string{
+		"missing selectors":  "/compactor/delete_series?start=10&end=20",
+		"invalid selector":   "/compactor/delete_series?match[]={__name__=",
+		"invalid time range": "/compactor/delete_series?match[]=up&start=20&end=10",
+		"invalid start time": "/compactor/delete_series?match[]=up&start=foo",
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			c.DeleteSeries(resp, httptest.NewRequest(http.MethodPost, target, nil).WithContext(ctx))
+			require.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+
+	t.Run("valid request", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		c.DeleteSeries(resp, httptest.NewRequest(http.MethodPost, `/compactor/delete_series?match[]=up&match[]={job="test"}&start=10&end=20`, nil).WithContext(ctx))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		created := SeriesDeletionRequestStatus{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+		require.NotEmpty(t, created.RequestID)
+		require.Equal(t, []string{"up", `{job="test"}`}, created.Selectors)
+		require.Equal(t, int64(10_000), created.StartTime)
+		require.Equal(t, int64(20_000), created.EndTime)
+		require.Equal(t, mimir_tsdb.SeriesDeletionRequestStatusPending, created.Status)
+
+		requests, err := mimir_tsdb.ReadSeriesDeletionRequests(context.Background(), bkt, "fake", log.NewNopLogger())
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		require.Equal(t, created.RequestID, requests[0].RequestID)
+
+		// The request is returned by the status API.
+		resp = httptest.NewRecorder()
+		c.DeleteSeriesStatus(resp, httptest.NewRequest(http.MethodGet, "/compactor/delete_series_status", nil).WithContext(ctx))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		status := DeleteSeriesStatusResponse{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+		require.Equal(t, DeleteSeriesStatusResponse{TenantID: "fake", Requests: []SeriesDeletionRequestStatus{created}}, status)
+	})
+}
+
+func TestDeleteSeriesStatus(t *testing.T) {
+	bkt := objstore.NewInMemBucket()
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+
+	resp := httptest.NewRecorder()
+	c.DeleteSeriesStatus(resp, httptest.NewRequest(http.MethodGet, "/compactor/delete_series_status", nil))
+	require.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	c.bucketClient = bkt
+	resp = httptest.NewRecorder()
+	c.DeleteSeriesStatus(resp, httptest.NewRequest(http.MethodGet, "/compactor/delete_series_status", nil).WithContext(user.InjectOrgID(context.Background(), "fake")))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"tenant_id":"fake","requests":[]}`, resp.Body.String())
+}
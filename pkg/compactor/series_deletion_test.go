@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	prom_testutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+func TestMultitenantCompactor_ProcessSeriesDeletionRequests(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	bkt := objstore.NewInMemBucket()
+	userBucket := bucket.NewUserBucketClient(userID, bkt, nil)
+
+	// The first block contains series with series_id from 0 to 9, the second one from 0 to 4.
+	firstBlock := createTSDBBlock(t, bkt, userID, 0, 2*time.Hour.Milliseconds(), 10, nil)
+	secondBlock := createTSDBBlock(t, bkt, userID, 2*time.Hour.Milliseconds(), 4*time.Hour.Milliseconds(), 5, nil)
+
+	req, err := mimir_tsdb.NewSeriesDeletionRequest([]string{`{series_id="7"}`}, 0, 4*time.Hour.Milliseconds(), time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteSeriesDeletionRequest(ctx, bkt, userID, nil, req))
+
+	cfg := prepareConfig(t)
+	cfg.DataDir = t.TempDir()
+	c, err := newMultitenantCompactor(cfg, mimir_tsdb.BlocksStorageConfig{}, nil, log.NewNopLogger(), prometheus.NewPedanticRegistry(), nil, splitAndMergeGrouperFactory, splitAndMergeCompactorFactory)
+	require.NoError(t, err)
+	c.bucketClient = bkt
+	c.blocksCompactor, c.blocksPlanner, err = splitAndMergeCompactorFactory(ctx, cfg, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+
+	requests, err := mimir_tsdb.ReadSeriesDeletionRequests(ctx, bkt, userID, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, c.processSeriesDeletionRequests(ctx, userID, userBucket, &seriesDeletions{requests: requests}, log.NewNopLogger()))
+
+	// Only the first block contains the deleted series, so it's the only one rewritten.
+	require.Equal(t, float64(1), prom_testutil.ToFloat64(c.blocksRewrittenForSeriesDeletion))
+	require.Equal(t, float64(1), prom_testutil.ToFloat64(c.blocksMarkedForDeletionBySeriesDeletion))
+	require.Equal(t, float64(1), prom_testutil.ToFloat64(c.seriesDeletionRequestsProcessed))
+
+	for blockID, expectedMarked := range map[ulid.ULID]bool{firstBlock: true, secondBlock: false} {
+		marked, err := userBucket.Exists(ctx, path.Join(blockID.String(), block.DeletionMarkFilename))
+		require.NoError(t, err)
+		require.Equal(t, expectedMarked, marked, blockID.String())
+	}
+
+	// Find the rewritten block.
+	var rewrittenBlock ulid.ULID
+	require.NoError(t, userBucket.Iter(ctx, "", func(name string) error {
+		if id, ok := block.IsBlockDir(name); ok && id != firstBlock && id != secondBlock {
+			rewrittenBlock = id
+		}
+		return nil
+	}))
+	require.NotZero(t, rewrittenBlock)
+
+	meta, err := block.DownloadMeta(ctx, log.NewNopLogger(), userBucket, rewrittenBlock)
+	require.NoError(t, err)
+	require.Equal(t, block.CompactorSource, meta.Thanos.Source)
+	require.Equal(t, uint64(9), meta.Stats.NumSeries)
+	require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "8", "9"}, readSeriesIDs(t, userBucket, rewrittenBlock))
+
+	// The request has been marked as processed.
+	requests, err = mimir_tsdb.ReadSeriesDeletionRequests(ctx, bkt, userID, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Equal(t, mimir_tsdb.SeriesDeletionRequestStatusProcessed, requests[0].Status())
+
+	// Processed requests are not processed again.
+	require.NoError(t, c.processSeriesDeletionRequests(ctx, userID, userBucket, &seriesDeletions{requests: requests}, log.NewNopLogger()))
+	require.Equal(t, float64(1), prom_testutil.ToFloat64(c.blocksRewrittenForSeriesDeletion))
+	require.Equal(t, float64(1), prom_testutil.ToFloat64(c.seriesDeletionRequestsProcessed))
+}
+
+func TestApplySeriesDeletionRequests(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	bkt := objstore.NewInMemBucket()
+	userBucket := bucket.NewUserBucketClient(userID, bkt, nil)
+	blockID := createTSDBBlock(t, bkt, userID, 0, 2*time.Hour.Milliseconds(), 10, nil)
+
+	meta, err := block.DownloadMeta(ctx, log.NewNopLogger(), userBucket, blockID)
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		selectors  []string
+		start, end int64
+		expected   bool
+	}{
+		"request matching series within the block time range": {
+			selectors: []string{`{series_id="1"}`},
+			start:     0,
+			end:       2 * time.Hour.Milliseconds(),
+			expected:  true,
+		},
+		"request not matching any series": {
+			selectors: []string{`{series_id="100"}`},
+			start:     0,
+			end:       2 * time.Hour.Milliseconds(),
+			expected:  false,
+		},
+		"request outside of the block time range": {
+			selectors: []string{`{series_id="1"}`},
+			start:     2 * time.Hour.Milliseconds(),
+			end:       3 * time.Hour.Milliseconds(),
+			expected:  false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			blockDir := filepath.Join(t.TempDir(), blockID.String())
+			require.NoError(t, block.Download(ctx, log.NewNopLogger(), userBucket, blockID, blockDir))
+
+			req, err := mimir_tsdb.NewSeriesDeletionRequest(tc.selectors, tc.start, tc.end, time.Now())
+			require.NoError(t, err)
+
+			deleted, err := applySeriesDeletionRequests(ctx, log.NewNopLogger(), blockDir, &meta, []*mimir_tsdb.SeriesDeletionRequest{req})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, deleted)
+		})
+	}
+}
+
+func readSeriesIDs(t *testing.T, bkt objstore.Bucket, blockID ulid.ULID) []string {
+	blockDir := filepath.Join(t.TempDir(), blockID.String())
+	require.NoError(t, block.Download(context.Background(), log.NewNopLogger(), bkt, blockID, blockDir))
+
+	b, err := tsdb.OpenBlock(log.NewNopLogger(), blockDir, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, b.Close()) })
+
+	idx, err := b.Index()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, idx.Close()) })
+
+	values, err := idx.SortedLabelValues(context.Background(), "series_id")
+	require.NoError(t, err)
+	return values
+}
+
+func TestSeriesDeletionRequestRequiresRewrite(t *testing.T) {
+	now := time.Now()
+	req := &mimir_tsdb.SeriesDeletionRequest{StartTime: 10, EndTime: 20, CreationTime: util.UnixSecondsFromTime(now)}
+
+	newMeta := func(minT, maxT int64, created time.Time, source block.SourceType) *block.Meta {
+		meta := &block.Meta{}
+		meta.ULID = ulid.MustNew(ulid.Timestamp(created), nil)
+		meta.MinTime = minT
+		meta.MaxTime = maxT
+		meta.Thanos.Source = source
+		return meta
+	}
+
+	require.True(t, seriesDeletionRequestRequiresRewrite(req, newMeta(0, 11, now.Add(-time.Hour), block.CompactorSource)))
+	require.True(t, seriesDeletionRequestRequiresRewrite(req, newMeta(0, 11, now.Add(time.Hour), block.ReceiveSource)))
+	require.False(t, seriesDeletionRequestRequiresRewrite(req, newMeta(0, 11, now.Add(time.Hour), block.CompactorSource)))
+	require.False(t, seriesDeletionRequestRequiresRewrite(req, newMeta(0, 10, now.Add(-time.Hour), block.ReceiveSource)))
+	require.False(t, seriesDeletionRequestRequiresRewrite(req, newMeta(21, 30, now.Add(-time.Hour), block.ReceiveSource)))
+}
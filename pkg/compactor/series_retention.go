@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/index"
+	"github.com/thanos-io/objstore"
+
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+// seriesRetentionMarkFilename is the name of the file, stored in the block directory, listing the retention rules
+// checked against a block which has no sample deleted by them, so that the block isn't checked again.
+const seriesRetentionMarkFilename = "series-retention-mark.json"
+
+// seriesRetentionMark is the content of the series retention mark file.
+type seriesRetentionMark struct {
+	// Rules are the keys of the retention rules checked against the block.
+	Rules []string `json:"rules"`
+}
+
+// seriesRetentionRule is a per-series retention rule, along with the series deletion request deleting
+// the samples of the matching series older than the rule cutoff.
+type seriesRetentionRule struct {
+	key     string
+	cutoff  int64
+	request *mimir_tsdb.SeriesDeletionRequest
+}
+
+func newSeriesRetentionRules(rules []*validation.SeriesRetentionRule, now time.Time) []seriesRetentionRule {
+	result := make([]seriesRetentionRule, 0, len(rules))
+	for _, r := range rules {
+		key := r.String()
+		cutoff := r.Cutoff(now)
+
+		result = append(result, seriesRetentionRule{
+			key:    key,
+			cutoff: cutoff,
+			request: &mimir_tsdb.SeriesDeletionRequest{
+				RequestID: key,
+				Selectors: []string{r.Selector},
+				StartTime: math.MinInt64,
+				EndTime:   cutoff - 1,
+			},
+		})
+	}
+	return result
+}
+
+// expired returns whether all the samples of the matching series within a block with the input max time have expired.
+func (r seriesRetentionRule) expired(maxTime int64) bool {
+	// Block max time is exclusive.
+	return maxTime <= r.cutoff
+}
+
+// seriesDeletions holds the series deletion requests and the per-series retention rules of a tenant.
+type seriesDeletions struct {
+	requests       []*mimir_tsdb.SeriesDeletionRequest
+	retentionRules []seriesRetentionRule
+}
+
+// allRequests returns the series deletion requests, including the ones deleting the samples expired by retention rules.
+func (d *seriesDeletions) allRequests() []*mimir_tsdb.SeriesDeletionRequest {
+	if d == nil {
+		return nil
+	}
+
+	all := make([]*mimir_tsdb.SeriesDeletionRequest, 0, len(d.requests)+len(d.retentionRules))
+	all = append(all, d.requests...)
+	for _, r := range d.retentionRules {
+		all = append(all, r.request)
+	}
+	return all
+}
+
+// expiredRetentionRules returns the keys of the retention rules whose samples have all expired
+// within a block with the input max time.
+func (d *seriesDeletions) expiredRetentionRules(maxTime int64) []string {
+	var keys []string
+	for _, r := range d.expiredRetentionRulesFor(maxTime) {
+		keys = append(keys, r.key)
+	}
+	return keys
+}
+
+// expiredRetentionRulesFor returns the retention rules whose samples have all expired within a block with the input max time.
+func (d *seriesDeletions) expiredRetentionRulesFor(maxTime int64) []seriesRetentionRule {
+	if d == nil {
+		return nil
+	}
+
+	var rules []seriesRetentionRule
+	for _, r := range d.retentionRules {
+		if r.expired(maxTime) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// loadSeriesDeletions returns the series deletion requests and the per-series retention rules of the tenant.
+func (c *MultitenantCompactor) loadSeriesDeletions(ctx context.Context, userID string, logger log.Logger) (*seriesDeletions, error) {
+	requests, err := mimir_tsdb.ReadSeriesDeletionRequests(ctx, c.bucketClient, userID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &seriesDeletions{
+		requests:       requests,
+		retentionRules: newSeriesRetentionRules(c.cfgProvider.CompactorSeriesRetentionRules(userID), time.Now()),
+	}, nil
+}
+
+// processSeriesRetentionRules rewrites the blocks whose samples have all expired for any retention rule not yet applied
+// to them, so that the samples of the matching series are deleted. Blocks whose samples have only partially expired are
+// rewritten once they fully expire, while their expired samples are deleted when they're compacted and filtered out by queriers.
+//
+// Only the index of a block is downloaded to check whether the block has series matching the expired rules. Blocks with no
+// matching series get a series retention mark listing the checked rules, so that they're not checked again.
+func (c *MultitenantCompactor) processSeriesRetentionRules(ctx context.Context, userID string, userBucket objstore.InstrumentedBucket, deletions *seriesDeletions, logger log.Logger) error {
+	if len(deletions.retentionRules) == 0 {
+		delete(c.seriesRetentionCheckedBlocks, userID)
+		return nil
+	}
+
+	metas, ids, err := c.fetchBlocksToRewrite(ctx, userID, userBucket, logger)
+	if err != nil {
+		return err
+	}
+
+	// Keep track of the blocks whose series retention mark has been read or written, to not read it again.
+	checked := c.seriesRetentionCheckedBlocks[userID]
+	if checked == nil {
+		checked = map[ulid.ULID]string{}
+		c.seriesRetentionCheckedBlocks[userID] = checked
+	}
+	for id := range checked {
+		if _, ok := metas[id]; !ok {
+			delete(checked, id)
+		}
+	}
+
+	workDir := filepath.Join(c.compactorCfg.DataDir, "series-retention", userID)
+	defer func() {
+		if err := os.RemoveAll(workDir); err != nil {
+			level.Error(logger).Log("msg", "failed to remove series retention work directory", "path", workDir, "err", err)
+		}
+	}()
+
+	for _, id := range ids {
+		meta := metas[id]
+
+		expired := deletions.expiredRetentionRulesFor(meta.MaxTime)
+		expiredKeys := deletions.expiredRetentionRules(meta.MaxTime)
+		if len(expired) == 0 || seriesRetentionRulesApplied(meta, expiredKeys) {
+			continue
+		}
+
+		expiredKey := strings.Join(expiredKeys, ",")
+		if checked[id] == expiredKey {
+			continue
+		}
+
+		mark, err := readSeriesRetentionMark(ctx, userBucket, id)
+		if err != nil {
+			return errors.Wrapf(err, "read series retention mark of block %s", id)
+		}
+		if containsAll(mark.Rules, expiredKeys) {
+			checked[id] = expiredKey
+			continue
+		}
+
+		matching, err := blockHasSeriesMatchingRetentionRules(ctx, logger, userBucket, workDir, meta, expired)
+		if err != nil {
+			return errors.Wrapf(err, "check series of block %s", id)
+		}
+
+		rewritten := false
+		if matching {
+			rewritten, err = c.rewriteBlockWithSeriesDeletions(ctx, userBucket, workDir, meta, deletions, logger)
+			if err != nil {
+				return errors.Wrapf(err, "rewrite block %s", id)
+			}
+		}
+		if rewritten {
+			delete(checked, id)
+			continue
+		}
+
+		if err := writeSeriesRetentionMark(ctx, userBucket, id, seriesRetentionMark{Rules: expiredKeys}); err != nil {
+			return errors.Wrapf(err, "write series retention mark of block %s", id)
+		}
+		checked[id] = expiredKey
+	}
+
+	return nil
+}
+
+// blockHasSeriesMatchingRetentionRules downloads the index of the block, and returns whether any of its series matches
+// the input retention rules.
+func blockHasSeriesMatchingRetentionRules(ctx context.Context, logger log.Logger, userBucket objstore.Bucket, workDir string, meta *block.Meta, rules []seriesRetentionRule) (bool, error) {
+	if err := os.RemoveAll(workDir); err != nil {
+		return false, errors.Wrap(err, "clean series retention work directory")
+	}
+	if err := os.MkdirAll(workDir, 0750); err != nil {
+		return false, errors.Wrap(err, "create series retention work directory")
+	}
+
+	indexFile := filepath.Join(workDir, block.IndexFilename)
+	if err := objstore.DownloadFile(ctx, logger, userBucket, path.Join(meta.ULID.String(), block.IndexFilename), indexFile); err != nil {
+		return false, errors.Wrap(err, "download block index")
+	}
+
+	r, err := index.NewFileReader(indexFile)
+	if err != nil {
+		return false, errors.Wrap(err, "open block index")
+	}
+	defer func() {
+		if err := r.Close(); err != nil {
+			level.Warn(logger).Log("msg", "failed to close block index", "block", meta.ULID, "err", err)
+		}
+	}()
+
+	for _, rule := range rules {
+		matchers, err := rule.request.Matchers()
+		if err != nil {
+			return false, errors.Wrapf(err, "series retention rule %s", rule.key)
+		}
+
+		for _, ms := range matchers {
+			postings, err := tsdb.PostingsForMatchers(ctx, r, ms...)
+			if err != nil {
+				return false, errors.Wrapf(err, "series retention rule %s", rule.key)
+			}
+			if postings.Next() {
+				return true, nil
+			}
+			if err := postings.Err(); err != nil {
+				return false, errors.Wrapf(err, "series retention rule %s", rule.key)
+			}
+		}
+	}
+	return false, nil
+}
+
+// readSeriesRetentionMark reads the series retention mark of the block. It returns an empty mark if the block has none.
+func readSeriesRetentionMark(ctx context.Context, userBucket objstore.Bucket, id ulid.ULID) (seriesRetentionMark, error) {
+	var mark seriesRetentionMark
+
+	r, err := userBucket.Get(ctx, path.Join(id.String(), seriesRetentionMarkFilename))
+	if err != nil {
+		if userBucket.IsObjNotFoundErr(err) {
+			return mark, nil
+		}
+		return mark, err
+	}
+	defer func() { _ = r.Close() }()
+
+	if err := json.NewDecoder(r).Decode(&mark); err != nil {
+		return mark, errors.Wrap(err, "decode series retention mark")
+	}
+	return mark, nil
+}
+
+// writeSeriesRetentionMark writes the series retention mark of the block.
+func writeSeriesRetentionMark(ctx context.Context, userBucket objstore.Bucket, id ulid.ULID, mark seriesRetentionMark) error {
+	data, err := json.Marshal(mark)
+	if err != nil {
+		return err
+	}
+	return userBucket.Upload(ctx, path.Join(id.String(), seriesRetentionMarkFilename), bytes.NewReader(data))
+}
+
+// containsAll returns whether s contains all the values.
+func containsAll(s, values []string) bool {
+	for _, v := range values {
+		if !slices.Contains(s, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// seriesRetentionRulesApplied returns whether all the input retention rules have been applied to the block.
+func seriesRetentionRulesApplied(meta *block.Meta, keys []string) bool {
+	return containsAll(meta.Thanos.SeriesRetentionRules, keys)
+}
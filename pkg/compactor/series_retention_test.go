@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	prom_testutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestSeriesDeletions(t *testing.T) {
+	now := time.Now()
+	request := &mimir_tsdb.SeriesDeletionRequest{RequestID: "request", Selectors: []string{`{job="test"}`}, StartTime: 10, EndTime: 20}
+
+	deletions := &seriesDeletions{
+		requests: []*mimir_tsdb.SeriesDeletionRequest{request},
+		retentionRules: newSeriesRetentionRules([]*validation.SeriesRetentionRule{
+			{Selector: `{__name__=~"debug_.*"}`, Period: model.Duration(7 * 24 * time.Hour)},
+			{Selector: `{__name__="up"}`, Period: model.Duration(time.Hour)},
+		}, now),
+	}
+
+	require.Equal(t, []*mimir_tsdb.SeriesDeletionRequest{
+		request,
+		{RequestID: `{__name__=~"debug_.*"}[1w]`, Selectors: []string{`{__name__=~"debug_.*"}`}, StartTime: math.MinInt64, EndTime: now.Add(-7*24*time.Hour).UnixMilli() - 1},
+		{RequestID: `{__name__="up"}[1h]`, Selectors: []string{`{__name__="up"}`}, StartTime: math.MinInt64, EndTime: now.Add(-time.Hour).UnixMilli() - 1},
+	}, deletions.allRequests())
+
+	require.Equal(t, []string{`{__name__=~"debug_.*"}[1w]`, `{__name__="up"}[1h]`}, deletions.expiredRetentionRules(now.Add(-7*24*time.Hour).UnixMilli()))
+	require.Equal(t, []string{`{__name__="up"}[1h]`}, deletions.expiredRetentionRules(now.Add(-2*time.Hour).UnixMilli()))
+	require.Empty(t, deletions.expiredRetentionRules(now.UnixMilli()))
+
+	// Nil series deletions don't delete anything.
+	require.Empty(t, (*seriesDeletions)(nil).allRequests())
+	require.Empty(t, (*seriesDeletions)(nil).expiredRetentionRules(0))
+}
+
+func TestMultitenantCompactor_ProcessSeriesRetentionRules(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	bkt := block.BucketWithGlobalMarkers(objstore.NewInMemBucket())
+	userBucket := bucket.NewUserBucketClient(userID, bkt, nil)
+
+	// The first block contains series with series_id from 0 to 9, the second one from 0 to 4,
+	// and the third one, which is recent, from 0 to 9.
+	now := time.Now()
+	firstBlock := createTSDBBlock(t, bkt, userID, 0, 2*time.Hour.Milliseconds(), 10, nil)
+	secondBlock := createTSDBBlock(t, bkt, userID, 2*time.Hour.Milliseconds(), 4*time.Hour.Milliseconds(), 5, nil)
+	recentBlock := createTSDBBlock(t, bkt, userID, now.Add(-2*time.Hour).UnixMilli(), now.UnixMilli(), 10, nil)
+
+	cfg := prepareConfig(t)
+	cfg.DataDir = t.TempDir()
+	cfgProvider := newMockConfigProvider()
+	cfgProvider.seriesRetentionRules[userID] = []*validation.SeriesRetentionRule{{Selector: `{series_id="7"}`, Period: model.Duration(24 * time.Hour)}}
+
+	c, err := newMultitenantCompactor(cfg, mimir_tsdb.BlocksStorageConfig{}, cfgProvider, log.NewNopLogger(), prometheus.NewPedanticRegistry(), nil, splitAndMergeGrouperFactory, splitAndMergeCompactorFactory)
+	require.NoError(t, err)
+	c.bucketClient = bkt
+	c.blocksCompactor, c.blocksPlanner, err = splitAndMergeCompactorFactory(ctx, cfg, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+
+	process := func() {
+		deletions, err := c.loadSeriesDeletions(ctx, userID, log.NewNopLogger())
+		require.NoError(t, err)
+		require.NoError(t, c.processSeriesRetentionRules(ctx, userID, userBucket, deletions, log.NewNopLogger()))
+	}
+	process()
+
+	// Only the first block contains the expired series, so it's the only one rewritten.
+	require.Equal(t, float64(1), prom_testutil.ToFloat64(c.blocksRewrittenForSeriesDeletion))
+	require.Equal(t, float64(1), prom_testutil.ToFloat64(c.blocksMarkedForDeletionBySeriesDeletion))
+
+	for blockID, expectedMarked := range map[ulid.ULID]bool{firstBlock: true, secondBlock: false, recentBlock: false} {
+		marked, err := userBucket.Exists(ctx, path.Join(blockID.String(), block.DeletionMarkFilename))
+		require.NoError(t, err)
+		require.Equal(t, expectedMarked, marked, blockID.String())
+	}
+
+	// The second block has been checked, and is not checked again while the retention rules don't change.
+	require.Equal(t, map[ulid.ULID]string{secondBlock: `{series_id="7"}[1d]`}, c.seriesRetentionCheckedBlocks[userID])
+
+	mark, err := readSeriesRetentionMark(ctx, userBucket, secondBlock)
+	require.NoError(t, err)
+	require.Equal(t, seriesRetentionMark{Rules: []string{`{series_id="7"}[1d]`}}, mark)
+
+	// The series retention mark prevents the second block from being checked again after a restart:
+	// its index is removed, so checking it would fail.
+	indexPath := path.Join(secondBlock.String(), block.IndexFilename)
+	index, err := userBucket.Get(ctx, indexPath)
+	require.NoError(t, err)
+	indexData, err := io.ReadAll(index)
+	require.NoError(t, err)
+	require.NoError(t, index.Close())
+	require.NoError(t, userBucket.Delete(ctx, indexPath))
+
+	c.seriesRetentionCheckedBlocks = map[string]map[ulid.ULID]string{}
+	process()
+	require.Equal(t, float64(1), prom_testutil.ToFloat64(c.blocksRewrittenForSeriesDeletion))
+	require.Equal(t, map[ulid.ULID]string{secondBlock: `{series_id="7"}[1d]`}, c.seriesRetentionCheckedBlocks[userID])
+	require.NoError(t, userBucket.Upload(ctx, indexPath, bytes.NewReader(indexData)))
+
+	// Find the rewritten block.
+	var rewrittenBlock ulid.ULID
+	require.NoError(t, userBucket.Iter(ctx, "", func(name string) error {
+		if id, ok := block.IsBlockDir(name); ok && id != firstBlock && id != secondBlock && id != recentBlock {
+			rewrittenBlock = id
+		}
+		return nil
+	}))
+	require.NotZero(t, rewrittenBlock)
+
+	meta, err := block.DownloadMeta(ctx, log.NewNopLogger(), userBucket, rewrittenBlock)
+	require.NoError(t, err)
+	require.Equal(t, []string{`{series_id="7"}[1d]`}, meta.Thanos.SeriesRetentionRules)
+	require.Equal(t, uint64(9), meta.Stats.NumSeries)
+	require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "8", "9"}, readSeriesIDs(t, userBucket, rewrittenBlock))
+
+	// Blocks are not rewritten again, given the retention rules have already been applied to them.
+	process()
+	require.Equal(t, float64(1), prom_testutil.ToFloat64(c.blocksRewrittenForSeriesDeletion))
+
+	// Changing the retention rules causes the blocks to be rewritten again.
+	cfgProvider.seriesRetentionRules[userID] = []*validation.SeriesRetentionRule{{Selector: `{series_id=~"3|7"}`, Period: model.Duration(24 * time.Hour)}}
+	process()
+	require.Equal(t, float64(3), prom_testutil.ToFloat64(c.blocksRewrittenForSeriesDeletion))
+	require.Empty(t, c.seriesRetentionCheckedBlocks[userID])
+
+	// Removing the retention rules stops tracking the checked blocks.
+	cfgProvider.seriesRetentionRules[userID] = nil
+	process()
+	require.Equal(t, float64(3), prom_testutil.ToFloat64(c.blocksRewrittenForSeriesDeletion))
+	require.NotContains(t, c.seriesRetentionCheckedBlocks, userID)
+}
+
+func TestMultitenantCompactor_ShouldApplySeriesRetentionRulesWhileCompacting(t *testing.T) {
+	const blockRange = 2 * time.Hour
+
+	storageCfg := mimir_tsdb.BlocksStorageConfig{}
+	flagext.DefaultValues(&storageCfg)
+	storageCfg.Bucket.Backend = bucket.Filesystem
+	storageCfg.Bucket.Filesystem.Directory = t.TempDir()
+
+	compactorCfg := prepareConfig(t)
+	compactorCfg.DataDir = t.TempDir()
+	compactorCfg.BlockRanges = mimir_tsdb.DurationList{blockRange, 2 * blockRange}
+
+	// The first tenant has a retention rule for a single series, while the second one for all series.
+	cfgProvider := newMockConfigProvider()
+	cfgProvider.seriesRetentionRules["user-1"] = []*validation.SeriesRetentionRule{{Selector: `{series_id="7"}`, Period: model.Duration(24 * time.Hour)}}
+	cfgProvider.seriesRetentionRules["user-2"] = []*validation.SeriesRetentionRule{{Selector: `{series_id=~".+"}`, Period: model.Duration(24 * time.Hour)}}
+
+	ctx := context.Background()
+	reg := prometheus.NewPedanticRegistry()
+	bucketClient, err := bucket.NewClient(ctx, storageCfg.Bucket, "test", log.NewNopLogger(), nil)
+	require.NoError(t, err)
+
+	for _, userID := range []string{"user-1", "user-2"} {
+		createTSDBBlock(t, bucketClient, userID, 0, blockRange.Milliseconds(), 10, nil)
+		createTSDBBlock(t, bucketClient, userID, blockRange.Milliseconds(), 2*blockRange.Milliseconds(), 10, nil)
+	}
+
+	c, err := NewMultitenantCompactor(compactorCfg, storageCfg, cfgProvider, log.NewNopLogger(), reg)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(ctx, c))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), c))
+	})
+
+	// Wait until the first compaction run completed.
+	test.Poll(t, 15*time.Second, nil, func() interface{} {
+		return prom_testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP cortex_compactor_runs_completed_total Total number of compaction runs successfully completed.
+			# TYPE cortex_compactor_runs_completed_total counter
+			cortex_compactor_runs_completed_total 1
+		`), "cortex_compactor_runs_completed_total")
+	})
+
+	// The blocks have been compacted without the expired series, and aren't rewritten afterwards.
+	require.Equal(t, float64(0), prom_testutil.ToFloat64(c.blocksRewrittenForSeriesDeletion))
+
+	fetchMetas := func(userID string) map[ulid.ULID]*block.Meta {
+		fetcher, err := block.NewMetaFetcher(log.NewNopLogger(), 1, bucket.NewUserBucketClient(userID, bucketClient, nil), t.TempDir(), nil, nil)
+		require.NoError(t, err)
+		metas, partials, err := fetcher.FetchWithoutMarkedForDeletion(ctx)
+		require.NoError(t, err)
+		require.Empty(t, partials)
+		return metas
+	}
+
+	metas := fetchMetas("user-1")
+	require.Len(t, metas, 1)
+	for id, meta := range metas {
+		require.Equal(t, int64(0), meta.MinTime)
+		require.Equal(t, 2*blockRange.Milliseconds(), meta.MaxTime)
+		require.Equal(t, []string{`{series_id="7"}[1d]`}, meta.Thanos.SeriesRetentionRules)
+		require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "8", "9"}, readSeriesIDs(t, bucket.NewUserBucketClient("user-1", bucketClient, nil), id))
+	}
+
+	// All samples of the second tenant have expired, so its blocks have been deleted.
+	require.Empty(t, fetchMetas("user-2"))
+}
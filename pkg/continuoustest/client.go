@@ -63,7 +63,7 @@ func (cfg *ClientConfig) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&cfg.WriteBaseEndpoint, "tests.write-endpoint", "The base endpoint on the write path. The URL should have no trailing slash. The specific API path is appended by the tool to the URL, for example /api/v1/push for the remote write API endpoint, so the configured URL must not include it.")
 	f.IntVar(&cfg.WriteBatchSize, "tests.write-batch-size", 1000, "The maximum number of series to write in a single request.")
 	f.DurationVar(&cfg.WriteTimeout, "tests.write-timeout", 5*time.Second, "The timeout for a single write request.")
-	f.StringVar(&cfg.WriteProtocol, "tests.write-protocol", "prometheus", "The protocol to use to write series data. Supported values are: prometheus, otlp-http")
+	f.StringVar(&cfg.WriteProtocol, "tests.write-protocol", "prometheus", "The protocol to use to write series data. Supported values are: prometheus, prometheus-rw2, otlp-http")
 
 	f.Var(&cfg.ReadBaseEndpoint, "tests.read-endpoint", "The base endpoint on the read path. The URL should have no trailing slash. The specific API path is appended by the tool to the URL, for example /api/v1/query_range for range query API, so the configured URL must not include it.")
 	f.DurationVar(&cfg.ReadTimeout, "tests.read-timeout", 60*time.Second, "The timeout for a single read request.")
@@ -97,8 +97,8 @@ func NewClient(cfg ClientConfig, logger log.Logger) (*Client, error) {
 	if cfg.ReadBaseEndpoint.URL == nil {
 		return nil, errors.New("the read endpoint has not been set")
 	}
-	if cfg.WriteProtocol != "prometheus" && cfg.WriteProtocol != "otlp-http" {
-		return nil, fmt.Errorf("the only supported write protocols are \"prometheus\" or \"otlp-http\"")
+	if cfg.WriteProtocol != "prometheus" && cfg.WriteProtocol != "prometheus-rw2" && cfg.WriteProtocol != "otlp-http" {
+		return nil, fmt.Errorf("the only supported write protocols are \"prometheus\", \"prometheus-rw2\" or \"otlp-http\"")
 	}
 	// Ensure not both tenant-id and basic-auth are used at the same time
 	// anonymous is the default value for TenantID.
@@ -121,12 +121,13 @@ func NewClient(cfg ClientConfig, logger log.Logger) (*Client, error) {
 
 	switch cfg.WriteProtocol {
 
-	case "prometheus":
+	case "prometheus", "prometheus-rw2":
 		writeClient = &prometheusWriter{
 			httpClient:        &http.Client{Transport: rt},
 			writeBaseEndpoint: cfg.WriteBaseEndpoint,
 			writeBatchSize:    cfg.WriteBatchSize,
 			writeTimeout:      cfg.WriteTimeout,
+			remoteWriteV2:     cfg.WriteProtocol == "prometheus-rw2",
 		}
 
 	case "otlp-http":
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -22,6 +23,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 
+	"github.com/grafana/mimir/pkg/distributor"
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier/api"
 )
 
@@ -197,6 +200,108 @@ func TestPromWriterClient_WriteSeries(t *testing.T) {
 	})
 }
 
+func TestPromRW2WriterClient_WriteSeries(t *testing.T) {
+	var (
+		nextStatusCode       = http.StatusOK
+		omitWrittenHeaders   = false
+		receivedContentTypes []string
+		receivedRequests     []mimirpb.WriteRequest
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		// Read the entire body.
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		require.NoError(t, request.Body.Close())
+
+		// Decode and unmarshal it.
+		body, err = snappy.Decode(nil, body)
+		require.NoError(t, err)
+
+		var reqV2 mimirpb.WriteRequestV2
+		require.NoError(t, reqV2.Unmarshal(body))
+		var req mimirpb.WriteRequest
+		require.NoError(t, reqV2.ToWriteRequest(&req, false))
+		receivedContentTypes = append(receivedContentTypes, request.Header.Get("Content-Type"))
+		receivedRequests = append(receivedRequests, req)
+
+		if !omitWrittenHeaders {
+			var samples, histograms int
+			for _, ts := range req.Timeseries {
+				samples += len(ts.Samples)
+				histograms += len(ts.Histograms)
+			}
+			writer.Header().Set("X-Prometheus-Remote-Write-Samples-Written", strconv.Itoa(samples))
+			writer.Header().Set("X-Prometheus-Remote-Write-Histograms-Written", strconv.Itoa(histograms))
+		}
+		writer.WriteHeader(nextStatusCode)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := ClientConfig{}
+	flagext.DefaultValues(&cfg)
+	cfg.WriteBatchSize = 10
+	cfg.WriteProtocol = "prometheus-rw2"
+	require.NoError(t, cfg.WriteBaseEndpoint.Set(server.URL))
+	require.NoError(t, cfg.ReadBaseEndpoint.Set(server.URL))
+
+	c, err := NewClient(cfg, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("write float series in multiple batches", func(t *testing.T) {
+		receivedContentTypes, receivedRequests = nil, nil
+		nextStatusCode, omitWrittenHeaders = http.StatusOK, false
+
+		series := generateSineWaveSeries("test", now, 12)
+		statusCode, err := c.WriteSeries(ctx, series)
+		require.NoError(t, err)
+		assert.Equal(t, 200, statusCode)
+
+		require.Len(t, receivedRequests, 2)
+		assert.Equal(t, []string{distributor.RemoteWriteV2ContentType, distributor.RemoteWriteV2ContentType}, receivedContentTypes)
+		assert.Len(t, receivedRequests[0].Timeseries, 10)
+		assert.Len(t, receivedRequests[1].Timeseries, 2)
+		assert.Equal(t, series[0].Samples[0].Value, receivedRequests[0].Timeseries[0].Samples[0].Value)
+		assert.Equal(t, series[0].Labels[0].Value, receivedRequests[0].Timeseries[0].Labels[0].Value)
+	})
+
+	t.Run("write histogram series", func(t *testing.T) {
+		receivedContentTypes, receivedRequests = nil, nil
+		nextStatusCode, omitWrittenHeaders = http.StatusOK, false
+
+		series := histogramProfiles[1].generateSeries("test", now, 2)
+		statusCode, err := c.WriteSeries(ctx, series)
+		require.NoError(t, err)
+		assert.Equal(t, 200, statusCode)
+
+		require.Len(t, receivedRequests, 1)
+		require.Len(t, receivedRequests[0].Timeseries, 2)
+		assert.Len(t, receivedRequests[0].Timeseries[0].Histograms, 1)
+	})
+
+	t.Run("request failed because the written headers are missing", func(t *testing.T) {
+		receivedContentTypes, receivedRequests = nil, nil
+		nextStatusCode, omitWrittenHeaders = http.StatusOK, true
+
+		series := generateSineWaveSeries("test", now, 1)
+		_, err := c.WriteSeries(ctx, series)
+		require.ErrorContains(t, err, "X-Prometheus-Remote-Write-")
+	})
+
+	t.Run("request failed with 4xx error", func(t *testing.T) {
+		receivedContentTypes, receivedRequests = nil, nil
+		nextStatusCode, omitWrittenHeaders = http.StatusBadRequest, false
+
+		series := generateSineWaveSeries("test", now, 1)
+		statusCode, err := c.WriteSeries(ctx, series)
+		require.Error(t, err)
+		assert.Equal(t, 400, statusCode)
+	})
+}
+
 func TestClient_QueryRange(t *testing.T) {
 	var (
 		receivedRequests []*http.Request
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -15,6 +16,9 @@ import (
 	"github.com/grafana/dskit/flagext"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/mimir/pkg/distributor"
+	"github.com/grafana/mimir/pkg/mimirpb"
 )
 
 type prometheusWriter struct {
@@ -22,10 +26,21 @@ type prometheusWriter struct {
 	writeBaseEndpoint flagext.URLValue
 	writeBatchSize    int
 	writeTimeout      time.Duration
+
+	// remoteWriteV2 enables the Prometheus remote-write 2.0 protocol.
+	remoteWriteV2 bool
 }
 
 func (pw *prometheusWriter) sendWriteRequest(ctx context.Context, req *prompb.WriteRequest) (int, error) {
-	data, err := proto.Marshal(req)
+	var (
+		data []byte
+		err  error
+	)
+	if pw.remoteWriteV2 {
+		data, err = mimirpb.FromPrometheusTimeseriesToWriteRequestV2(req.Timeseries, nil).Marshal()
+	} else {
+		data, err = proto.Marshal(req)
+	}
 	if err != nil {
 		return 0, err
 	}
@@ -41,8 +56,13 @@ func (pw *prometheusWriter) sendWriteRequest(ctx context.Context, req *prompb.Wr
 		return 0, err
 	}
 	httpReq.Header.Add("Content-Encoding", "snappy")
-	httpReq.Header.Set("Content-Type", "application/x-protobuf")
-	httpReq.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if pw.remoteWriteV2 {
+		httpReq.Header.Set("Content-Type", distributor.RemoteWriteV2ContentType)
+		httpReq.Header.Set("X-Prometheus-Remote-Write-Version", "2.0.0")
+	} else {
+		httpReq.Header.Set("Content-Type", "application/x-protobuf")
+		httpReq.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	}
 
 	httpResp, err := pw.httpClient.Do(httpReq)
 	if err != nil {
@@ -59,5 +79,34 @@ func (pw *prometheusWriter) sendWriteRequest(ctx context.Context, req *prompb.Wr
 		return httpResp.StatusCode, fmt.Errorf("server returned HTTP status %s and body %q (truncated to %d bytes)", httpResp.Status, string(truncatedBody), maxErrMsgLen)
 	}
 
+	if pw.remoteWriteV2 {
+		if err := checkRemoteWriteV2WrittenHeaders(httpResp.Header, req); err != nil {
+			return httpResp.StatusCode, err
+		}
+	}
+
 	return httpResp.StatusCode, nil
 }
+
+// checkRemoteWriteV2WrittenHeaders checks that the remote-write 2.0 response reports all the samples and histograms of the request as written.
+func checkRemoteWriteV2WrittenHeaders(header http.Header, req *prompb.WriteRequest) error {
+	var samples, histograms int
+	for _, ts := range req.Timeseries {
+		samples += len(ts.Samples)
+		histograms += len(ts.Histograms)
+	}
+
+	for name, expected := range map[string]int{
+		"X-Prometheus-Remote-Write-Samples-Written":    samples,
+		"X-Prometheus-Remote-Write-Histograms-Written": histograms,
+	} {
+		value := header.Get(name)
+		if value == "" {
+			return fmt.Errorf("server response is missing the %s header", name)
+		}
+		if written, err := strconv.Atoi(value); err != nil || written != expected {
+			return fmt.Errorf("server response %s header is %q while %d were sent", name, value, expected)
+		}
+	}
+	return nil
+}
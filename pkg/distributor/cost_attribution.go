@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	costAttributionIdleTimeout     = 20 * time.Minute
+	costAttributionCleanupInterval = time.Minute
+)
+
+// costAttribution tracks the received and discarded samples by the value of the tenants' cost attribution label.
+type costAttribution struct {
+	services.Service
+
+	limits *validation.Overrides
+
+	trackersMx sync.RWMutex
+	trackers   map[string]*util.CostAttributionTracker
+
+	receivedSamples  *prometheus.CounterVec
+	discardedSamples *prometheus.CounterVec
+}
+
+func newCostAttribution(limits *validation.Overrides, reg prometheus.Registerer) *costAttribution {
+	c := &costAttribution{
+		limits:   limits,
+		trackers: map[string]*util.CostAttributionTracker{},
+		receivedSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_attributed_received_samples_total",
+			Help: "The total number of received samples, excluding rejected and deduped samples, by cost attribution.",
+		}, []string{"user", "attribution"}),
+		discardedSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_attributed_discarded_samples_total",
+			Help: "The total number of samples that were discarded because of validation errors or rate limiting, by cost attribution.",
+		}, []string{"user", "attribution"}),
+	}
+	c.Service = services.NewTimerService(costAttributionCleanupInterval, nil, c.iteration, nil)
+	return c
+}
+
+// tracker returns the cost attribution tracker of the tenant, or nil if cost attribution is disabled for the tenant.
+// The tracker is replaced, and the tenant's metrics reset, when the tenant's cost attribution limits change.
+func (c *costAttribution) tracker(userID string) *util.CostAttributionTracker {
+	label := c.limits.CostAttributionLabel(userID)
+	maxValues := c.limits.MaxCostAttributionCardinalityPerUser(userID)
+
+	c.trackersMx.RLock()
+	t, ok := c.trackers[userID]
+	c.trackersMx.RUnlock()
+	if ok && t.Label() == label && t.MaxValues() == maxValues {
+		return t
+	}
+
+	c.trackersMx.Lock()
+	defer c.trackersMx.Unlock()
+
+	t, ok = c.trackers[userID]
+	if ok && t.Label() == label && t.MaxValues() == maxValues {
+		return t
+	}
+	if ok {
+		c.deleteUserMetrics(userID)
+	}
+	if label == "" {
+		delete(c.trackers, userID)
+		return nil
+	}
+	t = util.NewCostAttributionTracker(label, maxValues)
+	c.trackers[userID] = t
+	return t
+}
+
+// attribute returns the cost attribution of the series with the given labels.
+func (c *costAttribution) attribute(t *util.CostAttributionTracker, labels []mimirpb.LabelAdapter, now time.Time) string {
+	value := ""
+	for _, l := range labels {
+		if l.Name == t.Label() {
+			value = l.Value
+			break
+		}
+	}
+	return t.Attribute(value, now)
+}
+
+// updateReceivedSamples tracks the received samples of req by cost attribution.
+func (c *costAttribution) updateReceivedSamples(req *mimirpb.WriteRequest, userID string, now time.Time) {
+	t := c.tracker(userID)
+	if t == nil {
+		return
+	}
+
+	received := map[string]int{}
+	for _, ts := range req.Timeseries {
+		if n := len(ts.Samples) + len(ts.Histograms); n > 0 {
+			received[c.attribute(t, ts.Labels, now)] += n
+		}
+	}
+	for attribution, n := range received {
+		c.receivedSamples.WithLabelValues(userID, attribution).Add(float64(n))
+	}
+}
+
+// updateDiscardedSamples tracks the discarded samples by cost attribution.
+func (c *costAttribution) updateDiscardedSamples(userID string, discarded map[string]int) {
+	for attribution, n := range discarded {
+		c.discardedSamples.WithLabelValues(userID, attribution).Add(float64(n))
+	}
+}
+
+// iteration removes the metrics of the attributions which haven't been seen recently.
+func (c *costAttribution) iteration(_ context.Context) error {
+	deadline := time.Now().Add(-costAttributionIdleTimeout)
+
+	c.trackersMx.RLock()
+	defer c.trackersMx.RUnlock()
+
+	for userID, t := range c.trackers {
+		for _, attribution := range t.PurgeInactive(deadline) {
+			c.receivedSamples.DeleteLabelValues(userID, attribution)
+			c.discardedSamples.DeleteLabelValues(userID, attribution)
+		}
+	}
+	return nil
+}
+
+// removeUser forgets the tenant's tracker and removes its metrics.
+func (c *costAttribution) removeUser(userID string) {
+	c.trackersMx.Lock()
+	defer c.trackersMx.Unlock()
+
+	delete(c.trackers, userID)
+	c.deleteUserMetrics(userID)
+}
+
+func (c *costAttribution) deleteUserMetrics(userID string) {
+	filter := prometheus.Labels{"user": userID}
+	c.receivedSamples.DeletePartialMatch(filter)
+	c.discardedSamples.DeletePartialMatch(filter)
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestDistributor_CostAttribution(t *testing.T) {
+	limits := prepareDefaultLimits()
+	limits.CostAttributionLabel = "team"
+	limits.MaxCostAttributionCardinalityPerUser = 2
+
+	ds, _, regs, _ := prepare(t, prepConfig{
+		numIngesters:    3,
+		happyIngesters:  3,
+		numDistributors: 1,
+		limits:          limits,
+	})
+	d, reg := ds[0], regs[0]
+
+	now := time.Now().UnixMilli()
+	req := makeWriteRequestWith(
+		makeTimeseries([]string{"__name__", "foo", "team", "a"}, []mimirpb.Sample{{TimestampMs: now, Value: 1}, {TimestampMs: now + 1, Value: 2}}, nil),
+		makeTimeseries([]string{"__name__", "foo"}, makeSamples(now, 1), nil),
+		makeTimeseries([]string{"__name__", "foo", "team", "b"}, makeSamples(now, 1), nil),
+		// Invalid label name.
+		makeTimeseries([]string{"__name__", "bar", "team", "a", "0invalid", "x"}, makeSamples(now, 1), nil),
+	)
+	ctx := user.InjectOrgID(context.Background(), "user")
+	_, err := d.Push(ctx, req)
+	require.Error(t, err)
+
+	metrics := []string{"cortex_distributor_attributed_received_samples_total", "cortex_distributor_attributed_discarded_samples_total"}
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_distributor_attributed_discarded_samples_total The total number of samples that were discarded because of validation errors or rate limiting, by cost attribution.
+		# TYPE cortex_distributor_attributed_discarded_samples_total counter
+		cortex_distributor_attributed_discarded_samples_total{attribution="a",user="user"} 1
+
+		# HELP cortex_distributor_attributed_received_samples_total The total number of received samples, excluding rejected and deduped samples, by cost attribution.
+		# TYPE cortex_distributor_attributed_received_samples_total counter
+		cortex_distributor_attributed_received_samples_total{attribution="__missing__",user="user"} 1
+		cortex_distributor_attributed_received_samples_total{attribution="__overflow__",user="user"} 1
+		cortex_distributor_attributed_received_samples_total{attribution="a",user="user"} 2
+	`), metrics...))
+
+	// Changing the limits resets the metrics.
+	limits.MaxCostAttributionCardinalityPerUser = 3
+	d.costAttribution.limits, err = validation.NewOverrides(*limits, nil)
+	require.NoError(t, err)
+	d.costAttribution.tracker("user")
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(""), metrics...))
+
+	_, err = d.Push(ctx, makeWriteRequestWith(makeTimeseries([]string{"__name__", "foo", "team", "b"}, makeSamples(now+2, 1), nil)))
+	require.NoError(t, err)
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_distributor_attributed_received_samples_total The total number of received samples, excluding rejected and deduped samples, by cost attribution.
+		# TYPE cortex_distributor_attributed_received_samples_total counter
+		cortex_distributor_attributed_received_samples_total{attribution="b",user="user"} 1
+	`), metrics...))
+
+	d.cleanupInactiveUser("user")
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(""), metrics...))
+}
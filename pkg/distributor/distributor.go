@@ -48,6 +48,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/grafana/mimir/pkg/cardinality"
+	"github.com/grafana/mimir/pkg/distributor/distributorpb"
 	ingester_client "github.com/grafana/mimir/pkg/ingester/client"
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier/stats"
@@ -118,6 +119,11 @@ type Distributor struct {
 	activeUsers  *util.ActiveUsersCleanupService
 	activeGroups *util.ActiveGroupsCleanupService
 
+	costAttribution *costAttribution
+
+	streamAggregation *streamAggregation
+	relabelPreview    *relabelPreview
+
 	ingestionRate             *util_math.EwmaRate
 	inflightPushRequests      atomic.Int64
 	inflightPushRequestsBytes atomic.Int64
@@ -266,8 +272,11 @@ const (
 )
 
 type PushMetrics struct {
-	otlpRequestCounter   *prometheus.CounterVec
-	uncompressedBodySize *prometheus.HistogramVec
+	otlpRequestCounter           *prometheus.CounterVec
+	otlpDroppedAttributesCounter *prometheus.CounterVec
+	influxRequestCounter         *prometheus.CounterVec
+	pushgatewayRequestCounter    *prometheus.CounterVec
+	uncompressedBodySize         *prometheus.HistogramVec
 }
 
 func newPushMetrics(reg prometheus.Registerer) *PushMetrics {
@@ -276,6 +285,18 @@ func newPushMetrics(reg prometheus.Registerer) *PushMetrics {
 			Name: "cortex_distributor_otlp_requests_total",
 			Help: "The total number of OTLP requests that have come in to the distributor.",
 		}, []string{"user"}),
+		otlpDroppedAttributesCounter: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_otlp_dropped_promoted_attributes_total",
+			Help: "The total number of OTLP resource and scope attributes which were not promoted to labels because of the label limits.",
+		}, []string{"user", "reason"}),
+		influxRequestCounter: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_influx_requests_total",
+			Help: "The total number of InfluxDB line protocol requests that have come in to the distributor.",
+		}, []string{"user"}),
+		pushgatewayRequestCounter: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_pushgateway_requests_total",
+			Help: "The total number of Pushgateway compatible exposition format requests that have come in to the distributor.",
+		}, []string{"user"}),
 		uncompressedBodySize: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
 			Name:                            "cortex_distributor_uncompressed_request_body_size_bytes",
 			Help:                            "Size of uncompressed request body in bytes.",
@@ -292,6 +313,24 @@ func (m *PushMetrics) IncOTLPRequest(user string) {
 	}
 }
 
+func (m *PushMetrics) AddOTLPDroppedAttributes(user, reason string, count int) {
+	if m != nil {
+		m.otlpDroppedAttributesCounter.WithLabelValues(user, reason).Add(float64(count))
+	}
+}
+
+func (m *PushMetrics) IncInfluxRequest(user string) {
+	if m != nil {
+		m.influxRequestCounter.WithLabelValues(user).Inc()
+	}
+}
+
+func (m *PushMetrics) IncPushgatewayRequest(user string) {
+	if m != nil {
+		m.pushgatewayRequestCounter.WithLabelValues(user).Inc()
+	}
+}
+
 func (m *PushMetrics) ObserveUncompressedBodySize(user string, size float64) {
 	if m != nil {
 		m.uncompressedBodySize.WithLabelValues(user).Observe(size)
@@ -300,6 +339,9 @@ func (m *PushMetrics) ObserveUncompressedBodySize(user string, size float64) {
 
 func (m *PushMetrics) deleteUserMetrics(user string) {
 	m.otlpRequestCounter.DeleteLabelValues(user)
+	m.otlpDroppedAttributesCounter.DeletePartialMatch(prometheus.Labels{"user": user})
+	m.influxRequestCounter.DeleteLabelValues(user)
+	m.pushgatewayRequestCounter.DeleteLabelValues(user)
 	m.uncompressedBodySize.DeleteLabelValues(user)
 }
 
@@ -532,9 +574,19 @@ func New(cfg Config, clientConfig ingester_client.Config, limits *validation.Ove
 	d.activeUsers = util.NewActiveUsersCleanupWithDefaultValues(d.cleanupInactiveUser)
 	d.activeGroups = activeGroupsCleanupService
 
+	// The stream aggregation outputs are written through the middlewares following the stream aggregation one.
+	var distributorClients *ring_client.Pool
+	if distributorsRing != nil {
+		distributorClients = newDistributorClientPool(clientConfig.GRPCClientConfig, log, reg)
+		subservices = append(subservices, distributorClients)
+	}
+	d.streamAggregation = newStreamAggregation(limits, distributorsRing, cfg.DistributorRing.Common.InstanceID, distributorClients, d.wrapPushWithPostAggregationMiddlewares(d.push), log, reg)
+	d.relabelPreview = newRelabelPreview(limits, reg)
 	d.PushWithMiddlewares = d.wrapPushWithMiddlewares(d.push)
 
-	subservices = append(subservices, d.ingesterPool, d.activeUsers)
+	d.costAttribution = newCostAttribution(limits, reg)
+
+	subservices = append(subservices, d.ingesterPool, d.activeUsers, d.costAttribution, d.streamAggregation)
 
 	if cfg.ReusableIngesterPushWorkers > 0 {
 		wp := concurrency.NewReusableGoroutinesPool(cfg.ReusableIngesterPushWorkers)
@@ -671,6 +723,9 @@ func (d *Distributor) cleanupInactiveUser(userID string) {
 	d.latestSeenSampleTimestampPerUser.DeleteLabelValues(userID)
 
 	d.PushMetrics.deleteUserMetrics(userID)
+	d.costAttribution.removeUser(userID)
+	d.streamAggregation.removeUser(userID)
+	d.relabelPreview.removeUser(userID)
 
 	filter := prometheus.Labels{"user": userID}
 	d.dedupedSamples.DeletePartialMatch(filter)
@@ -700,7 +755,7 @@ func (d *Distributor) stopping(_ error) error {
 // Returns a boolean that indicates whether or not we want to remove the replica label going forward,
 // and an error that indicates whether we want to accept samples based on the cluster/replica found in ts.
 // nil for the error means accept the sample.
-func (d *Distributor) checkSample(ctx context.Context, userID, cluster, replica string) (removeReplicaLabel bool, _ error) {
+func (d *Distributor) checkSample(ctx context.Context, userID, cluster, replica string, newestSampleTimestamp int64) (removeReplicaLabel bool, _ error) {
 	// If the sample doesn't have either HA label, accept it.
 	// At the moment we want to accept these samples by default.
 	if cluster == "" || replica == "" {
@@ -714,7 +769,7 @@ func (d *Distributor) checkSample(ctx context.Context, userID, cluster, replica
 
 	// At this point we know we have both HA labels, we should lookup
 	// the cluster/instance here to see if we want to accept this sample.
-	err := d.HATracker.checkReplica(ctx, userID, cluster, replica, time.Now())
+	err := d.HATracker.checkReplica(ctx, userID, cluster, replica, time.Now(), newestSampleTimestamp)
 	// checkReplica would have returned an error if there was a real error talking to Consul,
 	// or if the replica is not the currently elected replica.
 	if err != nil { // Don't accept the sample.
@@ -810,6 +865,21 @@ func (d *Distributor) wrapPushWithMiddlewares(next PushFunc) PushFunc {
 	middlewares = append(middlewares, d.metricsMiddleware)
 	middlewares = append(middlewares, d.prePushHaDedupeMiddleware)
 	middlewares = append(middlewares, d.prePushRelabelMiddleware)
+	middlewares = append(middlewares, d.prePushStreamAggregationMiddleware)
+
+	next = d.wrapPushWithPostAggregationMiddlewares(next)
+	for ix := len(middlewares) - 1; ix >= 0; ix-- {
+		next = middlewares[ix](next)
+	}
+
+	return next
+}
+
+// wrapPushWithPostAggregationMiddlewares returns push function wrapped in the Distributor's middlewares following
+// the stream aggregation one, which both the pushed requests and the stream aggregation outputs go through.
+func (d *Distributor) wrapPushWithPostAggregationMiddlewares(next PushFunc) PushFunc {
+	var middlewares []PushWrapper
+
 	middlewares = append(middlewares, d.prePushSortAndFilterMiddleware)
 	middlewares = append(middlewares, d.prePushValidationMiddleware)
 	middlewares = append(middlewares, d.cfg.PushWrappers...)
@@ -857,7 +927,12 @@ func (d *Distributor) prePushHaDedupeMiddleware(next PushFunc) PushFunc {
 			numSamples += len(ts.Samples) + len(ts.Histograms)
 		}
 
-		removeReplica, err := d.checkSample(ctx, userID, cluster, replica)
+		var newestSampleTimestamp int64
+		if d.HATracker.cfg.FailoverSampleLagThreshold > 0 && cluster != "" && replica != "" {
+			newestSampleTimestamp = findNewestSampleTimestamp(req.Timeseries)
+		}
+
+		removeReplica, err := d.checkSample(ctx, userID, cluster, replica, newestSampleTimestamp)
 		if err != nil {
 			if errors.As(err, &replicasDidNotMatchError{}) {
 				// These samples have been deduped.
@@ -897,7 +972,9 @@ func (d *Distributor) prePushRelabelMiddleware(next PushFunc) PushFunc {
 			return err
 		}
 
-		if !d.limits.MetricRelabelingEnabled(userID) {
+		relabelingEnabled := d.limits.MetricRelabelingEnabled(userID)
+		shadowRelabelingEnabled := d.limits.ShadowMetricRelabelingEnabled(userID)
+		if !relabelingEnabled && !shadowRelabelingEnabled {
 			return next(ctx, pushReq)
 		}
 
@@ -906,33 +983,91 @@ func (d *Distributor) prePushRelabelMiddleware(next PushFunc) PushFunc {
 			return err
 		}
 
+		var shadow *relabelPreviewBatch
+		if shadowRelabelingEnabled {
+			shadow = d.relabelPreview.newBatch(userID, relabelingEnabled)
+		}
+
 		var removeTsIndexes []int
 		lb := labels.NewBuilder(labels.EmptyLabels())
 		for tsIdx := 0; tsIdx < len(req.Timeseries); tsIdx++ {
-			ts := req.Timeseries[tsIdx]
+			var before labels.Labels
+			if shadow != nil {
+				// The labels are copied because the active relabeling modifies them in place.
+				before = mimirpb.FromLabelAdaptersToLabels(req.Timeseries[tsIdx].Labels).Copy()
+			}
 
-			if mrc := d.limits.MetricRelabelConfigs(userID); len(mrc) > 0 {
-				mimirpb.FromLabelAdaptersToBuilder(ts.Labels, lb)
-				lb.Set(metaLabelTenantID, userID)
-				keep := relabel.ProcessBuilder(lb, mrc...)
-				if !keep {
-					removeTsIndexes = append(removeTsIndexes, tsIdx)
-					continue
-				}
-				lb.Del(metaLabelTenantID)
-				req.Timeseries[tsIdx].SetLabels(mimirpb.FromBuilderToLabelAdapters(lb, ts.Labels))
+			keep := true
+			if relabelingEnabled {
+				keep = d.relabelTimeseries(userID, &req.Timeseries[tsIdx], lb)
 			}
 
-			for _, labelName := range d.limits.DropLabels(userID) {
-				req.Timeseries[tsIdx].RemoveLabel(labelName)
+			if shadow != nil {
+				shadow.observe(before, keep, req.Timeseries[tsIdx].Labels)
 			}
 
-			if len(ts.Labels) == 0 {
+			if !keep {
 				removeTsIndexes = append(removeTsIndexes, tsIdx)
-				continue
 			}
 		}
 
+		if shadow != nil {
+			d.relabelPreview.commit(shadow)
+		}
+
+		if len(removeTsIndexes) > 0 {
+			for _, removeTsIndex := range removeTsIndexes {
+				mimirpb.ReusePreallocTimeseries(&req.Timeseries[removeTsIndex])
+			}
+			req.Timeseries = util.RemoveSliceIndexes(req.Timeseries, removeTsIndexes)
+		}
+
+		return next(ctx, pushReq)
+	}
+}
+
+// relabelTimeseries applies the tenant's metric relabel configs and drop labels to the series, and returns
+// false if the series must be dropped.
+func (d *Distributor) relabelTimeseries(userID string, ts *mimirpb.PreallocTimeseries, lb *labels.Builder) bool {
+	if mrc := d.limits.MetricRelabelConfigs(userID); len(mrc) > 0 {
+		mimirpb.FromLabelAdaptersToBuilder(ts.Labels, lb)
+		lb.Set(metaLabelTenantID, userID)
+		if !relabel.ProcessBuilder(lb, mrc...) {
+			return false
+		}
+		lb.Del(metaLabelTenantID)
+		ts.SetLabels(mimirpb.FromBuilderToLabelAdapters(lb, ts.Labels))
+	}
+
+	for _, labelName := range d.limits.DropLabels(userID) {
+		ts.RemoveLabel(labelName)
+	}
+
+	return len(ts.Labels) > 0
+}
+
+// prePushStreamAggregationMiddleware aggregates the samples of the series matching the tenant's stream aggregation rules,
+// and removes these series from the request unless a matching rule keeps its input.
+func (d *Distributor) prePushStreamAggregationMiddleware(next PushFunc) PushFunc {
+	return func(ctx context.Context, pushReq *Request) error {
+		next, maybeCleanup := NextOrCleanup(next, pushReq)
+		defer maybeCleanup()
+
+		userID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(d.limits.StreamAggregationRules(userID)) == 0 {
+			return next(ctx, pushReq)
+		}
+
+		req, err := pushReq.WriteRequest()
+		if err != nil {
+			return err
+		}
+
+		removeTsIndexes := d.streamAggregation.aggregate(ctx, userID, req, mtime.Now())
 		if len(removeTsIndexes) > 0 {
 			for _, removeTsIndex := range removeTsIndexes {
 				mimirpb.ReusePreallocTimeseries(&req.Timeseries[removeTsIndex])
@@ -1010,6 +1145,15 @@ func (d *Distributor) prePushValidationMiddleware(next PushFunc) PushFunc {
 
 		group := d.activeGroups.UpdateActiveGroupTimestamp(userID, validation.GroupLabel(d.limits, userID, req.Timeseries), now)
 
+		// Validated and discarded samples by cost attribution, if cost attribution is enabled for the tenant.
+		costAttributionTracker := d.costAttribution.tracker(userID)
+		var validatedByAttribution, discardedByAttribution map[string]int
+		if costAttributionTracker != nil {
+			validatedByAttribution = map[string]int{}
+			discardedByAttribution = map[string]int{}
+			defer func() { d.costAttribution.updateDiscardedSamples(userID, discardedByAttribution) }()
+		}
+
 		// A WriteRequest can only contain series or metadata but not both. This might change in the future.
 		validatedMetadata := 0
 		validatedSamples := 0
@@ -1061,6 +1205,12 @@ func (d *Distributor) prePushValidationMiddleware(next PushFunc) PushFunc {
 
 			d.labelsHistogram.Observe(float64(len(ts.Labels)))
 
+			attribution := ""
+			if costAttributionTracker != nil {
+				attribution = d.costAttribution.attribute(costAttributionTracker, ts.Labels, now)
+			}
+			numSamples := len(ts.Samples) + len(ts.Histograms)
+
 			skipLabelNameValidation := d.cfg.SkipLabelNameValidation || req.GetSkipLabelNameValidation()
 			// Note that validateSeries may drop some data in ts.
 			validationErr := d.validateSeries(now, &req.Timeseries[tsIdx], userID, group, skipLabelNameValidation, minExemplarTS, maxExemplarTS)
@@ -1073,11 +1223,17 @@ func (d *Distributor) prePushValidationMiddleware(next PushFunc) PushFunc {
 					firstPartialErr = newValidationError(validationErr)
 				}
 				removeIndexes = append(removeIndexes, tsIdx)
+				if costAttributionTracker != nil {
+					discardedByAttribution[attribution] += numSamples
+				}
 				continue
 			}
 
 			validatedSamples += len(ts.Samples) + len(ts.Histograms)
 			validatedExemplars += len(ts.Exemplars)
+			if costAttributionTracker != nil {
+				validatedByAttribution[attribution] += len(ts.Samples) + len(ts.Histograms)
+			}
 		}
 
 		d.incomingSamplesPerRequest.WithLabelValues(userID).Observe(float64(totalSamples))
@@ -1115,6 +1271,9 @@ func (d *Distributor) prePushValidationMiddleware(next PushFunc) PushFunc {
 		totalN := validatedSamples + validatedExemplars + validatedMetadata
 		if !d.ingestionRateLimiter.AllowN(now, userID, totalN) {
 			d.discardedSamplesRateLimited.WithLabelValues(userID, group).Add(float64(validatedSamples))
+			for attribution, n := range validatedByAttribution {
+				discardedByAttribution[attribution] += n
+			}
 			d.discardedExemplarsRateLimited.WithLabelValues(userID).Add(float64(validatedExemplars))
 			d.discardedMetadataRateLimited.WithLabelValues(userID).Add(float64(validatedMetadata))
 
@@ -1393,6 +1552,20 @@ func (d *Distributor) Push(ctx context.Context, req *mimirpb.WriteRequest) (*mim
 	return nil, handledErr
 }
 
+// PushStreamAggregationInputs is gRPC method registered as distributor.DistributorServer. It's called by the other
+// distributors to forward the stream aggregation inputs of the output series owned by this distributor, which have
+// already been validated by them, so the inputs are only aggregated.
+func (d *Distributor) PushStreamAggregationInputs(ctx context.Context, req *distributorpb.StreamAggregationInputsRequest) (*mimirpb.WriteResponse, error) {
+	defer mimirpb.ReuseSlice(req.Request.Timeseries)
+
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.streamAggregation.aggregateForwarded(userID, int(req.RuleIndex), &req.Request, mtime.Now())
+	return &mimirpb.WriteResponse{}, nil
+}
+
 func (d *Distributor) handlePushError(ctx context.Context, pushErr error) error {
 	if errors.Is(pushErr, context.Canceled) {
 		return pushErr
@@ -1440,6 +1613,7 @@ func (d *Distributor) push(ctx context.Context, pushReq *Request) error {
 	}
 
 	d.updateReceivedMetrics(req, userID)
+	d.costAttribution.updateReceivedSamples(req, userID, mtime.Now())
 
 	if len(req.Timeseries) == 0 && len(req.Metadata) == 0 {
 		return nil
@@ -1482,7 +1656,18 @@ func (d *Distributor) push(ctx context.Context, pushReq *Request) error {
 	// once all backend requests have completed (see cleanup function passed to sendWriteRequestToBackends()).
 	cleanupInDefer = false
 
-	return d.sendWriteRequestToBackends(ctx, userID, req, keys, initialMetadataIndex, ingestersSubring, partitionsSubring, pushReq.CleanUp)
+	// Count the written samples before sending them, because the request may be cleaned up before the send returns.
+	written := writtenStatsFromContext(ctx)
+	var stats remoteWriteStats
+	if written != nil {
+		stats = newRemoteWriteStats(req)
+	}
+
+	err = d.sendWriteRequestToBackends(ctx, userID, req, keys, initialMetadataIndex, ingestersSubring, partitionsSubring, pushReq.CleanUp)
+	if err == nil && written != nil {
+		written.add(stats)
+	}
+	return err
 }
 
 // sendWriteRequestToBackends sends the input req data to backends. The backends could be:
@@ -2032,20 +2217,25 @@ func (d *Distributor) labelValuesCardinality(ctx context.Context, labelNames []m
 		return nil, err
 	}
 
+	return cardinalityConcurrentMap.toResponse(d.cardinalityApproximateFromZonesFunc(replicationSets)), nil
+}
+
+// cardinalityApproximateFromZonesFunc returns the function used to approximate the number of series of a replication
+// set from the counts returned by ingesters in each zone.
+func (d *Distributor) cardinalityApproximateFromZonesFunc(replicationSets []ring.ReplicationSet) func(countByZone map[string]uint64) uint64 {
+	// When the ingest storage is enabled a partition is owned by only 1 ingester per zone.
+	// So we always approximate the resulting stats as max of what a single zone has.
+	if d.cfg.IngestStorageConfig.Enabled {
+		return maxFromZones[uint64]
+	}
+
 	zonesTotal := 0
 	if len(replicationSets) > 0 {
 		zonesTotal = replicationSets[0].ZoneCount()
 	}
-	approximateFromZonesFunc := func(countByZone map[string]uint64) uint64 {
+	return func(countByZone map[string]uint64) uint64 {
 		return approximateFromZones(zonesTotal, d.ingestersRing.ReplicationFactor(), countByZone)
 	}
-	// When the ingest storage is enabled a partition is owned by only 1 ingester per zone.
-	// So we always approximate the resulting stats as max of what a single zone has.
-	if d.cfg.IngestStorageConfig.Enabled {
-		approximateFromZonesFunc = maxFromZones[uint64]
-	}
-
-	return cardinalityConcurrentMap.toResponse(approximateFromZonesFunc), nil
 }
 
 func toLabelValuesCardinalityRequest(labelNames []model.LabelName, matchers []*labels.Matcher, countMethod cardinality.CountMethod) (*ingester_client.LabelValuesCardinalityRequest, error) {
@@ -2169,6 +2359,127 @@ func (cm *labelValuesCardinalityConcurrentMap) toResponse(approximateFromZonesFu
 	}
 }
 
+// CardinalityHistory queries ingesters for the cardinality samples recorded between start and end (in milliseconds,
+// 0 means no boundary) and merges the samples recorded at the same time by different ingesters. The number of series
+// is approximated like in LabelValuesCardinality. Since each ingester only records its own top label values, the
+// number of series of a label value may be underestimated.
+func (d *Distributor) CardinalityHistory(ctx context.Context, start, end int64) (*ingester_client.CardinalityHistoryResponse, error) {
+	replicationSets, err := d.getIngesterReplicationSetsForQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// When ingest storage is disabled, if ingesters are running in a single zone we can't tolerate any errors.
+	// In this case we expect exactly 1 replication set.
+	if !d.cfg.IngestStorageConfig.Enabled && len(replicationSets) == 1 && replicationSets[0].ZoneCount() == 1 {
+		replicationSets[0].MaxErrors = 0
+	}
+
+	historyMap := &cardinalityHistoryConcurrentMap{
+		numReplicationSets: len(replicationSets),
+		samples:            map[int64]*cardinalityHistorySampleCounters{},
+	}
+	req := &ingester_client.CardinalityHistoryRequest{StartTimestampMs: start, EndTimestampMs: end}
+	quorumConfig := d.queryQuorumConfigForReplicationSets(ctx, replicationSets)
+
+	// Like in labelValuesCardinality, the responses are collected by ReplicationSet.
+	err = concurrency.ForEachJob(ctx, len(replicationSets), 0, func(ctx context.Context, replicationSetIdx int) error {
+		replicationSet := replicationSets[replicationSetIdx]
+
+		_, err := ring.DoUntilQuorum[any](ctx, replicationSet, quorumConfig, func(ctx context.Context, desc *ring.InstanceDesc) (any, error) {
+			poolClient, err := d.ingesterPool.GetClientForInstance(*desc)
+			if err != nil {
+				return nil, err
+			}
+
+			client := poolClient.(ingester_client.IngesterClient)
+
+			resp, err := client.CardinalityHistory(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+
+			historyMap.processResponse(replicationSetIdx, desc.Zone, resp)
+			return nil, nil
+		}, func(_ any) {})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return historyMap.toResponse(d.cardinalityApproximateFromZonesFunc(replicationSets)), nil
+}
+
+type cardinalityHistorySampleCounters struct {
+	numSeries   countByReplicationSetAndZone
+	labelValues *labelValuesCardinalityConcurrentMap
+}
+
+type cardinalityHistoryConcurrentMap struct {
+	numReplicationSets int
+
+	// samples stores the counters of the samples by timestamp.
+	samplesMx sync.Mutex
+	samples   map[int64]*cardinalityHistorySampleCounters
+}
+
+// processResponse processes a CardinalityHistoryResponse received from an ingester via gRPC and increments the
+// counters of each sample.
+func (cm *cardinalityHistoryConcurrentMap) processResponse(replicationSetIdx int, zone string, resp *ingester_client.CardinalityHistoryResponse) {
+	cm.samplesMx.Lock()
+	defer cm.samplesMx.Unlock()
+
+	for _, sample := range resp.Samples {
+		counters, ok := cm.samples[sample.TimestampMs]
+		if !ok {
+			counters = &cardinalityHistorySampleCounters{
+				numSeries: make(countByReplicationSetAndZone, cm.numReplicationSets),
+				labelValues: &labelValuesCardinalityConcurrentMap{
+					numReplicationSets:  cm.numReplicationSets,
+					labelValuesCounters: map[string]map[string]countByReplicationSetAndZone{},
+				},
+			}
+			cm.samples[sample.TimestampMs] = counters
+		}
+
+		if counters.numSeries[replicationSetIdx] == nil {
+			counters.numSeries[replicationSetIdx] = map[string]uint64{}
+		}
+		counters.numSeries[replicationSetIdx][zone] += sample.NumSeries
+
+		counters.labelValues.processMessage(replicationSetIdx, zone, &ingester_client.LabelValuesCardinalityResponse{Items: sample.Items})
+	}
+}
+
+// toResponse builds and returns the CardinalityHistoryResponse with the merged samples sorted by timestamp.
+func (cm *cardinalityHistoryConcurrentMap) toResponse(approximateFromZonesFunc func(countByZone map[string]uint64) uint64) *ingester_client.CardinalityHistoryResponse {
+	cm.samplesMx.Lock()
+	defer cm.samplesMx.Unlock()
+
+	samples := make([]*ingester_client.CardinalityHistorySample, 0, len(cm.samples))
+	for ts, counters := range cm.samples {
+		numSeries := uint64(0)
+		for _, countByZone := range counters.numSeries {
+			numSeries += approximateFromZonesFunc(countByZone)
+		}
+
+		samples = append(samples, &ingester_client.CardinalityHistorySample{
+			TimestampMs: ts,
+			NumSeries:   numSeries,
+			Items:       counters.labelValues.toResponse(approximateFromZonesFunc).Items,
+		})
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].TimestampMs < samples[j].TimestampMs
+	})
+
+	return &ingester_client.CardinalityHistoryResponse{Samples: samples}
+}
+
 // ActiveSeries queries the ingester replication set for active series matching
 // the given selector. It combines and deduplicates the results.
 func (d *Distributor) ActiveSeries(ctx context.Context, matchers []*labels.Matcher) ([]labels.Labels, error) {
@@ -2686,11 +2997,12 @@ func (d *Distributor) UserStats(ctx context.Context, countMethod cardinality.Cou
 	// processed and there are no more goroutines accessing responsesByReplicationSet.
 	for replicationSetIdx, resps := range responsesByReplicationSet {
 		var (
-			replicationSet        = replicationSets[replicationSetIdx]
-			zoneIngestionRate     = map[string]float64{}
-			zoneAPIIngestionRate  = map[string]float64{}
-			zoneRuleIngestionRate = map[string]float64{}
-			zoneNumSeries         = map[string]uint64{}
+			replicationSet         = replicationSets[replicationSetIdx]
+			zoneIngestionRate      = map[string]float64{}
+			zoneAPIIngestionRate   = map[string]float64{}
+			zoneRuleIngestionRate  = map[string]float64{}
+			zoneNumSeries          = map[string]uint64{}
+			zoneSeriesCreationRate = map[string]float64{}
 		)
 
 		// Collect responses by zone.
@@ -2699,6 +3011,7 @@ func (d *Distributor) UserStats(ctx context.Context, countMethod cardinality.Cou
 			zoneAPIIngestionRate[r.zone] += r.resp.ApiIngestionRate
 			zoneRuleIngestionRate[r.zone] += r.resp.RuleIngestionRate
 			zoneNumSeries[r.zone] += r.resp.NumSeries
+			zoneSeriesCreationRate[r.zone] += r.resp.SeriesCreationRate
 		}
 
 		// When the ingest storage is enabled, a partition is owned by only 1 ingester per zone.
@@ -2708,11 +3021,13 @@ func (d *Distributor) UserStats(ctx context.Context, countMethod cardinality.Cou
 			totalStats.APIIngestionRate += maxFromZones(zoneAPIIngestionRate)
 			totalStats.RuleIngestionRate += maxFromZones(zoneRuleIngestionRate)
 			totalStats.NumSeries += maxFromZones(zoneNumSeries)
+			totalStats.SeriesCreationRate += maxFromZones(zoneSeriesCreationRate)
 		} else {
 			totalStats.IngestionRate += approximateFromZones(replicationSet.ZoneCount(), d.ingestersRing.ReplicationFactor(), zoneIngestionRate)
 			totalStats.APIIngestionRate += approximateFromZones(replicationSet.ZoneCount(), d.ingestersRing.ReplicationFactor(), zoneAPIIngestionRate)
 			totalStats.RuleIngestionRate += approximateFromZones(replicationSet.ZoneCount(), d.ingestersRing.ReplicationFactor(), zoneRuleIngestionRate)
 			totalStats.NumSeries += approximateFromZones(replicationSet.ZoneCount(), d.ingestersRing.ReplicationFactor(), zoneNumSeries)
+			totalStats.SeriesCreationRate += approximateFromZones(replicationSet.ZoneCount(), d.ingestersRing.ReplicationFactor(), zoneSeriesCreationRate)
 		}
 	}
 
@@ -2753,6 +3068,7 @@ func (d *Distributor) AllUserStats(ctx context.Context) ([]UserIDStats, error) {
 			s.APIIngestionRate += u.Data.ApiIngestionRate
 			s.RuleIngestionRate += u.Data.RuleIngestionRate
 			s.NumSeries += u.Data.NumSeries
+			s.SeriesCreationRate += u.Data.SeriesCreationRate
 			perUserTotals[u.UserId] = s
 		}
 	}
@@ -2763,10 +3079,11 @@ func (d *Distributor) AllUserStats(ctx context.Context) ([]UserIDStats, error) {
 		response = append(response, UserIDStats{
 			UserID: id,
 			UserStats: UserStats{
-				IngestionRate:     stats.IngestionRate,
-				APIIngestionRate:  stats.APIIngestionRate,
-				RuleIngestionRate: stats.RuleIngestionRate,
-				NumSeries:         stats.NumSeries,
+				IngestionRate:      stats.IngestionRate,
+				APIIngestionRate:   stats.APIIngestionRate,
+				RuleIngestionRate:  stats.RuleIngestionRate,
+				NumSeries:          stats.NumSeries,
+				SeriesCreationRate: stats.SeriesCreationRate,
 			},
 		})
 	}
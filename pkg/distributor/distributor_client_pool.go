@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/grpcclient"
+	"github.com/grafana/dskit/ring"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/grafana/mimir/pkg/distributor/distributorpb"
+)
+
+// newDistributorClientPool returns a pool of clients of the other distributors, used to forward them
+// the stream aggregation inputs of the output series they own.
+func newDistributorClientPool(clientCfg grpcclient.Config, logger log.Logger, reg prometheus.Registerer) *ring_client.Pool {
+	// We prefer sane defaults instead of exposing further config options.
+	poolCfg := ring_client.PoolConfig{
+		CheckInterval:      10 * time.Second,
+		HealthCheckEnabled: true,
+		HealthCheckTimeout: 10 * time.Second,
+	}
+
+	clientsCount := promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+		Name: "cortex_distributor_distributor_clients",
+		Help: "The current number of clients of other distributors in the pool.",
+	})
+
+	requestDuration := promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cortex_distributor_distributor_client_request_duration_seconds",
+		Help:    "Time spent executing requests to other distributors.",
+		Buckets: prometheus.ExponentialBuckets(0.008, 4, 7),
+	}, []string{"operation", "status_code"})
+
+	factory := ring_client.PoolInstFunc(func(inst ring.InstanceDesc) (ring_client.PoolClient, error) {
+		return dialDistributorClient(clientCfg, inst, requestDuration)
+	})
+
+	return ring_client.NewPool("distributor", poolCfg, nil, factory, clientsCount, logger)
+}
+
+func dialDistributorClient(clientCfg grpcclient.Config, inst ring.InstanceDesc, requestDuration *prometheus.HistogramVec) (*distributorClient, error) {
+	opts, err := clientCfg.DialOption(grpcclient.Instrument(requestDuration))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:staticcheck // grpc.Dial() has been deprecated; we'll address it before upgrading to gRPC 2.
+	conn, err := grpc.Dial(inst.Addr, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial distributor %s %s", inst.Id, inst.Addr)
+	}
+
+	return &distributorClient{
+		DistributorClient: distributorpb.NewDistributorClient(conn),
+		HealthClient:      grpc_health_v1.NewHealthClient(conn),
+		conn:              conn,
+	}, nil
+}
+
+type distributorClient struct {
+	distributorpb.DistributorClient
+	grpc_health_v1.HealthClient
+	conn *grpc.ClientConn
+}
+
+func (c *distributorClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *distributorClient) String() string {
+	return c.RemoteAddress()
+}
+
+func (c *distributorClient) RemoteAddress() string {
+	return c.conn.Target()
+}
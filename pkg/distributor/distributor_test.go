@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"math"
 	"math/rand"
 	"net/http"
@@ -1027,7 +1028,7 @@ func TestDistributor_PushHAInstances(t *testing.T) {
 
 			userID, err := tenant.TenantID(ctx)
 			assert.NoError(t, err)
-			err = d.HATracker.checkReplica(ctx, userID, tc.cluster, tc.acceptedReplica, time.Now())
+			err = d.HATracker.checkReplica(ctx, userID, tc.cluster, tc.acceptedReplica, time.Now(), 0)
 			assert.NoError(t, err)
 
 			request := makeWriteRequestForGenerators(tc.samples, labelSetGenWithReplicaAndCluster(tc.testReplica, tc.cluster), nil, nil)
@@ -4174,6 +4175,72 @@ func TestDistributor_LabelValuesCardinality(t *testing.T) {
 	}
 }
 
+func TestDistributor_CardinalityHistory(t *testing.T) {
+	fixtures := []labels.Labels{
+		labels.FromStrings(labels.MetricName, "test_1", "status", "200"),
+		labels.FromStrings(labels.MetricName, "test_1", "status", "500"),
+		labels.FromStrings(labels.MetricName, "test_2"),
+	}
+
+	expectedSample := func(ts int64) *client.CardinalityHistorySample {
+		return &client.CardinalityHistorySample{
+			TimestampMs: ts,
+			NumSeries:   3,
+			Items:       []*client.LabelValueSeriesCount{{LabelName: labels.MetricName, LabelValueSeries: map[string]uint64{"test_1": 2, "test_2": 1}}},
+		}
+	}
+
+	tests := map[string]struct {
+		ingesterZones []string
+		start, end    int64
+		expected      *client.CardinalityHistoryResponse
+	}{
+		"single zone": {
+			expected: &client.CardinalityHistoryResponse{Samples: []*client.CardinalityHistorySample{expectedSample(1000), expectedSample(2000)}},
+		},
+		"multiple zones": {
+			ingesterZones: []string{"ZONE-A", "ZONE-B", "ZONE-C"},
+			expected:      &client.CardinalityHistoryResponse{Samples: []*client.CardinalityHistorySample{expectedSample(1000), expectedSample(2000)}},
+		},
+		"with time range": {
+			start:    1500,
+			expected: &client.CardinalityHistoryResponse{Samples: []*client.CardinalityHistorySample{expectedSample(2000)}},
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			ds, ingesters, _, _ := prepare(t, prepConfig{
+				numIngesters:      3,
+				happyIngesters:    3,
+				numDistributors:   1,
+				replicationFactor: 3,
+				ingesterZones:     testData.ingesterZones,
+			})
+
+			ctx := user.InjectOrgID(context.Background(), "cardinality-history")
+			for _, series := range fixtures {
+				_, err := ds[0].Push(ctx, mockWriteRequest(series, 1, 100000))
+				require.NoError(t, err)
+			}
+
+			// Since the Push() response is sent as soon as the quorum is reached, when we reach this point
+			// the final ingester may not have received series yet.
+			test.Poll(t, time.Second, testData.expected, func() interface{} {
+				res, err := ds[0].CardinalityHistory(ctx, testData.start, testData.end)
+				require.NoError(t, err)
+				return res
+			})
+
+			assert.GreaterOrEqual(t, countMockIngestersCalled(ingesters, "CardinalityHistory"), 2)
+		})
+	}
+}
+
 func TestDistributor_LabelValuesCardinality_AvailabilityAndConsistency(t *testing.T) {
 	var (
 		// Define fixtures used in tests.
@@ -6716,6 +6783,41 @@ func (i *mockIngester) AllUserStats(context.Context, *client.UserStatsRequest, .
 	return &i.stats, nil
 }
 
+func (i *mockIngester) CardinalityHistory(ctx context.Context, req *client.CardinalityHistoryRequest, _ ...grpc.CallOption) (*client.CardinalityHistoryResponse, error) {
+	i.trackCall("CardinalityHistory")
+
+	if err := i.enforceReadConsistency(ctx); err != nil {
+		return nil, err
+	}
+
+	i.Lock()
+	defer i.Unlock()
+
+	if !i.happy {
+		return nil, errFail
+	}
+
+	// Each sample reports the series currently stored in the mock ingester.
+	metricNames := map[string]uint64{}
+	for _, ts := range i.timeseries {
+		metricNames[mimirpb.FromLabelAdaptersToLabels(ts.Labels).Get(labels.MetricName)]++
+	}
+
+	result := &client.CardinalityHistoryResponse{}
+	for _, sampleTs := range []int64{1000, 2000} {
+		if (req.StartTimestampMs > 0 && sampleTs < req.StartTimestampMs) || (req.EndTimestampMs > 0 && sampleTs > req.EndTimestampMs) {
+			continue
+		}
+
+		result.Samples = append(result.Samples, &client.CardinalityHistorySample{
+			TimestampMs: sampleTs,
+			NumSeries:   uint64(len(i.timeseries)),
+			Items:       []*client.LabelValueSeriesCount{{LabelName: labels.MetricName, LabelValueSeries: maps.Clone(metricNames)}},
+		})
+	}
+	return result, nil
+}
+
 func (i *mockIngester) UserStats(ctx context.Context, _ *client.UserStatsRequest, _ ...grpc.CallOption) (*client.UserStatsResponse, error) {
 	i.trackCall("UserStats")
 
@@ -12,7 +12,11 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	io "io"
 	math "math"
+	math_bits "math/bits"
+	reflect "reflect"
+	strings "strings"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -26,25 +30,133 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+type StreamAggregationInputsRequest struct {
+	// The index of the tenant's stream aggregation rule the series are inputs of.
+	RuleIndex int32                `protobuf:"varint,1,opt,name=rule_index,json=ruleIndex,proto3" json:"rule_index,omitempty"`
+	Request   mimirpb.WriteRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request"`
+}
+
+func (m *StreamAggregationInputsRequest) Reset()      { *m = StreamAggregationInputsRequest{} }
+func (*StreamAggregationInputsRequest) ProtoMessage() {}
+func (*StreamAggregationInputsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c518e33639ca565d, []int{0}
+}
+func (m *StreamAggregationInputsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamAggregationInputsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamAggregationInputsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StreamAggregationInputsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamAggregationInputsRequest.Merge(m, src)
+}
+func (m *StreamAggregationInputsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamAggregationInputsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamAggregationInputsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamAggregationInputsRequest proto.InternalMessageInfo
+
+func (m *StreamAggregationInputsRequest) GetRuleIndex() int32 {
+	if m != nil {
+		return m.RuleIndex
+	}
+	return 0
+}
+
+func (m *StreamAggregationInputsRequest) GetRequest() mimirpb.WriteRequest {
+	if m != nil {
+		return m.Request
+	}
+	return mimirpb.WriteRequest{}
+}
+
+func init() {
+	proto.RegisterType((*StreamAggregationInputsRequest)(nil), "distributor.StreamAggregationInputsRequest")
+}
+
 func init() { proto.RegisterFile("distributor.proto", fileDescriptor_c518e33639ca565d) }
 
 var fileDescriptor_c518e33639ca565d = []byte{
-	// 225 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4c, 0xc9, 0x2c, 0x2e,
-	0x29, 0xca, 0x4c, 0x2a, 0x2d, 0xc9, 0x2f, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x46,
-	0x12, 0x92, 0xd2, 0x4d, 0xcf, 0x2c, 0xc9, 0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xcf,
-	0x4f, 0xcf, 0xd7, 0x07, 0xab, 0x49, 0x2a, 0x4d, 0x03, 0xf3, 0xc0, 0x1c, 0x30, 0x0b, 0xa2, 0x57,
-	0xca, 0x00, 0x59, 0x79, 0x51, 0x62, 0x5a, 0x62, 0x5e, 0xa2, 0x7e, 0x6e, 0x66, 0x6e, 0x66, 0x91,
-	0x7e, 0x41, 0x76, 0x3a, 0x84, 0x55, 0x90, 0x04, 0xa1, 0x21, 0x3a, 0x8c, 0x3c, 0xb8, 0xb8, 0x5d,
-	0x10, 0xf6, 0x09, 0x59, 0x72, 0xb1, 0x04, 0x94, 0x16, 0x67, 0x08, 0x89, 0xe9, 0x25, 0xe7, 0x17,
-	0x95, 0xa4, 0x56, 0x14, 0x24, 0xe9, 0x85, 0x17, 0x65, 0x96, 0xa4, 0x06, 0xa5, 0x16, 0x96, 0xa6,
-	0x16, 0x97, 0x48, 0x89, 0x63, 0x88, 0x17, 0x17, 0xe4, 0xe7, 0x15, 0xa7, 0x2a, 0x31, 0x38, 0x39,
-	0x5f, 0x78, 0x28, 0xc7, 0x70, 0xe3, 0xa1, 0x1c, 0xc3, 0x87, 0x87, 0x72, 0x8c, 0x0d, 0x8f, 0xe4,
-	0x18, 0x57, 0x3c, 0x92, 0x63, 0x3c, 0xf1, 0x48, 0x8e, 0xf1, 0xc2, 0x23, 0x39, 0xc6, 0x07, 0x8f,
-	0xe4, 0x18, 0x5f, 0x3c, 0x92, 0x63, 0xf8, 0xf0, 0x48, 0x8e, 0x71, 0xc2, 0x63, 0x39, 0x86, 0x0b,
-	0x8f, 0xe5, 0x18, 0x6e, 0x3c, 0x96, 0x63, 0x88, 0xe2, 0x45, 0xf2, 0x6d, 0x41, 0x52, 0x12, 0x1b,
-	0xd8, 0x55, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x85, 0x48, 0xef, 0xb2, 0x18, 0x01, 0x00,
-	0x00,
+	// 319 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x90, 0x31, 0x4b, 0x03, 0x31,
+	0x18, 0x86, 0x13, 0xa9, 0x8a, 0x29, 0x0e, 0x66, 0xd0, 0x52, 0xf1, 0xb3, 0x74, 0x2a, 0x88, 0x77,
+	0x52, 0x41, 0x70, 0xb4, 0xba, 0x74, 0x93, 0x3a, 0x08, 0x2e, 0x72, 0x69, 0xd3, 0x34, 0xe8, 0x5d,
+	0x62, 0x2e, 0xc1, 0x8e, 0xfe, 0x04, 0x7f, 0x86, 0x9b, 0x7f, 0xa3, 0x63, 0xc7, 0x4e, 0x62, 0xd3,
+	0xc5, 0xb1, 0x3f, 0x41, 0x7a, 0xa7, 0x78, 0x20, 0xed, 0x94, 0xef, 0x7b, 0x79, 0x9f, 0x7c, 0xbc,
+	0x2f, 0xd9, 0xe9, 0xc9, 0xd4, 0x1a, 0xc9, 0x9c, 0x55, 0x26, 0xd0, 0x46, 0x59, 0x45, 0xcb, 0x05,
+	0xa9, 0x7a, 0x2c, 0xa4, 0x1d, 0x38, 0x16, 0x74, 0x55, 0x1c, 0x0a, 0x25, 0x54, 0x98, 0x79, 0x98,
+	0xeb, 0x67, 0x5b, 0xb6, 0x64, 0x53, 0xce, 0x56, 0x4f, 0x8a, 0x76, 0x13, 0xf5, 0xa3, 0x24, 0x0a,
+	0x63, 0x19, 0x4b, 0x13, 0xea, 0x07, 0x91, 0x4f, 0x9a, 0xe5, 0x6f, 0x4e, 0xd4, 0x9f, 0x09, 0xdc,
+	0x58, 0xc3, 0xa3, 0xf8, 0x42, 0x08, 0xc3, 0x45, 0x64, 0xa5, 0x4a, 0xda, 0x89, 0x76, 0x36, 0xed,
+	0xf0, 0x27, 0xc7, 0x53, 0x4b, 0x0f, 0x08, 0x31, 0xee, 0x91, 0xdf, 0xcb, 0xa4, 0xc7, 0x87, 0x15,
+	0x5c, 0xc3, 0x8d, 0xf5, 0xce, 0xd6, 0x42, 0x69, 0x2f, 0x04, 0x7a, 0x46, 0x36, 0x4d, 0xee, 0xac,
+	0xac, 0xd5, 0x70, 0xa3, 0xdc, 0xdc, 0x0d, 0xba, 0xca, 0x58, 0x3e, 0xd4, 0x2c, 0xb8, 0x35, 0xd2,
+	0xf2, 0x9f, 0x7f, 0x5a, 0xa5, 0xd1, 0xc7, 0x21, 0xea, 0xfc, 0x9a, 0x9b, 0xef, 0x98, 0x94, 0xaf,
+	0xfe, 0x92, 0xd2, 0x73, 0x52, 0xba, 0x76, 0xe9, 0x80, 0x2e, 0xc1, 0xab, 0x7b, 0xff, 0xf4, 0x54,
+	0xab, 0x24, 0xe5, 0x75, 0x44, 0x39, 0xd9, 0x5f, 0xa0, 0x4b, 0x72, 0xd0, 0xa3, 0xa0, 0x58, 0xf2,
+	0xea, 0xb4, 0x2b, 0xce, 0xb4, 0x2e, 0xc7, 0x53, 0x40, 0x93, 0x29, 0xa0, 0xf9, 0x14, 0xf0, 0x8b,
+	0x07, 0xfc, 0xe6, 0x01, 0x8f, 0x3c, 0xe0, 0xb1, 0x07, 0xfc, 0xe9, 0x01, 0x7f, 0x79, 0x40, 0x73,
+	0x0f, 0xf8, 0x75, 0x06, 0x68, 0x3c, 0x03, 0x34, 0x99, 0x01, 0xba, 0xdb, 0x2e, 0x1c, 0xd7, 0x8c,
+	0x6d, 0x64, 0xb5, 0x9f, 0x7e, 0x0f, 0x00, 0x6e, 0xd2, 0x47, 0x2c, 0xf9, 0x01, 0x00, 0x00,
+}
+
+func (this *StreamAggregationInputsRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*StreamAggregationInputsRequest)
+	if !ok {
+		that2, ok := that.(StreamAggregationInputsRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.RuleIndex != that1.RuleIndex {
+		return false
+	}
+	if !this.Request.Equal(&that1.Request) {
+		return false
+	}
+	return true
+}
+func (this *StreamAggregationInputsRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&distributorpb.StreamAggregationInputsRequest{")
+	s = append(s, "RuleIndex: "+fmt.Sprintf("%#v", this.RuleIndex)+",\n")
+	s = append(s, "Request: "+strings.Replace(this.Request.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringDistributor(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -60,6 +172,9 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type DistributorClient interface {
 	Push(ctx context.Context, in *mimirpb.WriteRequest, opts ...grpc.CallOption) (*mimirpb.WriteResponse, error)
+	// PushStreamAggregationInputs aggregates the inputs of a stream aggregation rule forwarded by the distributor
+	// which received them, because it doesn't own their output series. It's only called by other distributors.
+	PushStreamAggregationInputs(ctx context.Context, in *StreamAggregationInputsRequest, opts ...grpc.CallOption) (*mimirpb.WriteResponse, error)
 }
 
 type distributorClient struct {
@@ -79,9 +194,21 @@ func (c *distributorClient) Push(ctx context.Context, in *mimirpb.WriteRequest,
 	return out, nil
 }
 
+func (c *distributorClient) PushStreamAggregationInputs(ctx context.Context, in *StreamAggregationInputsRequest, opts ...grpc.CallOption) (*mimirpb.WriteResponse, error) {
+	out := new(mimirpb.WriteResponse)
+	err := c.cc.Invoke(ctx, "/distributor.Distributor/PushStreamAggregationInputs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DistributorServer is the server API for Distributor service.
 type DistributorServer interface {
 	Push(context.Context, *mimirpb.WriteRequest) (*mimirpb.WriteResponse, error)
+	// PushStreamAggregationInputs aggregates the inputs of a stream aggregation rule forwarded by the distributor
+	// which received them, because it doesn't own their output series. It's only called by other distributors.
+	PushStreamAggregationInputs(context.Context, *StreamAggregationInputsRequest) (*mimirpb.WriteResponse, error)
 }
 
 // UnimplementedDistributorServer can be embedded to have forward compatible implementations.
@@ -91,6 +218,9 @@ type UnimplementedDistributorServer struct {
 func (*UnimplementedDistributorServer) Push(ctx context.Context, req *mimirpb.WriteRequest) (*mimirpb.WriteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Push not implemented")
 }
+func (*UnimplementedDistributorServer) PushStreamAggregationInputs(ctx context.Context, req *StreamAggregationInputsRequest) (*mimirpb.WriteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushStreamAggregationInputs not implemented")
+}
 
 func RegisterDistributorServer(s *grpc.Server, srv DistributorServer) {
 	s.RegisterService(&_Distributor_serviceDesc, srv)
@@ -114,6 +244,24 @@ func _Distributor_Push_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Distributor_PushStreamAggregationInputs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StreamAggregationInputsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistributorServer).PushStreamAggregationInputs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/distributor.Distributor/PushStreamAggregationInputs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistributorServer).PushStreamAggregationInputs(ctx, req.(*StreamAggregationInputsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Distributor_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "distributor.Distributor",
 	HandlerType: (*DistributorServer)(nil),
@@ -122,7 +270,315 @@ var _Distributor_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Push",
 			Handler:    _Distributor_Push_Handler,
 		},
+		{
+			MethodName: "PushStreamAggregationInputs",
+			Handler:    _Distributor_PushStreamAggregationInputs_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "distributor.proto",
 }
+
+func (m *StreamAggregationInputsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamAggregationInputsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StreamAggregationInputsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Request.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintDistributor(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if m.RuleIndex != 0 {
+		i = encodeVarintDistributor(dAtA, i, uint64(m.RuleIndex))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintDistributor(dAtA []byte, offset int, v uint64) int {
+	offset -= sovDistributor(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *StreamAggregationInputsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.RuleIndex != 0 {
+		n += 1 + sovDistributor(uint64(m.RuleIndex))
+	}
+	l = m.Request.Size()
+	n += 1 + l + sovDistributor(uint64(l))
+	return n
+}
+
+func sovDistributor(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozDistributor(x uint64) (n int) {
+	return sovDistributor(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *StreamAggregationInputsRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamAggregationInputsRequest{`,
+		`RuleIndex:` + fmt.Sprintf("%v", this.RuleIndex) + `,`,
+		`Request:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Request), "WriteRequest", "mimirpb.WriteRequest", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringDistributor(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *StreamAggregationInputsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDistributor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamAggregationInputsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamAggregationInputsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RuleIndex", wireType)
+			}
+			m.RuleIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDistributor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RuleIndex |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Request", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDistributor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDistributor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthDistributor
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Request.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDistributor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDistributor
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthDistributor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipDistributor(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowDistributor
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowDistributor
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowDistributor
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthDistributor
+			}
+			iNdEx += length
+			if iNdEx < 0 {
+				return 0, ErrInvalidLengthDistributor
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowDistributor
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipDistributor(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+				if iNdEx < 0 {
+					return 0, ErrInvalidLengthDistributor
+				}
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthDistributor = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowDistributor   = fmt.Errorf("proto: integer overflow")
+)
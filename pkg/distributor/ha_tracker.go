@@ -33,6 +33,18 @@ var (
 	errNegativeUpdateTimeoutJitterMax = errors.New("HA tracker max update timeout jitter shouldn't be negative")
 	errInvalidFailoverTimeout         = "HA Tracker failover timeout (%v) must be at least 1s greater than update timeout - max jitter (%v)"
 	errMemberlistUnsupported          = errors.New("memberlist is not supported by the HA tracker since gossip propagation is too slow for HA purposes")
+	errNegativeFailoverSampleLag      = errors.New("HA tracker failover sample lag threshold shouldn't be negative")
+)
+
+// Reasons why a replica has been elected, stored in the KV store with the elected replica.
+const (
+	// The replica is the first one the HA tracker received samples from for the cluster.
+	electionReasonFirstReplica = "first_replica"
+	// No samples were received from the previously elected replica during the failover timeout.
+	electionReasonFailoverTimeout = "failover_timeout"
+	// The newest sample received from the previously elected replica lagged behind the newest sample received
+	// from this replica by more than the failover sample lag threshold.
+	electionReasonSampleLag = "sample_lag"
 )
 
 type haTrackerLimits interface {
@@ -65,6 +77,10 @@ type HATrackerConfig struct {
 	// between the stored timestamp and the time we received a sample is
 	// more than this duration
 	FailoverTimeout time.Duration `yaml:"ha_tracker_failover_timeout" category:"advanced"`
+	// We should also failover to another replica if the newest sample
+	// timestamp received from the elected replica lags behind the newest
+	// sample timestamp received from the other replica by more than this duration.
+	FailoverSampleLagThreshold time.Duration `yaml:"ha_tracker_failover_sample_lag_threshold" category:"experimental"`
 
 	KVStore kv.Config `yaml:"kvstore" doc:"description=Backend storage to use for the ring. Please be aware that memberlist is not supported by the HA tracker since gossip propagation is too slow for HA purposes."`
 }
@@ -75,6 +91,7 @@ func (cfg *HATrackerConfig) RegisterFlags(f *flag.FlagSet) {
 	f.DurationVar(&cfg.UpdateTimeout, "distributor.ha-tracker.update-timeout", 15*time.Second, "Update the timestamp in the KV store for a given cluster/replica only after this amount of time has passed since the current stored timestamp.")
 	f.DurationVar(&cfg.UpdateTimeoutJitterMax, "distributor.ha-tracker.update-timeout-jitter-max", 5*time.Second, "Maximum jitter applied to the update timeout, in order to spread the HA heartbeats over time.")
 	f.DurationVar(&cfg.FailoverTimeout, "distributor.ha-tracker.failover-timeout", 30*time.Second, "If we don't receive any samples from the accepted replica for a cluster in this amount of time we will failover to the next replica we receive a sample from. This value must be greater than the update timeout")
+	f.DurationVar(&cfg.FailoverSampleLagThreshold, "distributor.ha-tracker.failover-sample-lag-threshold", 0, "If the newest sample timestamp received from the accepted replica for a cluster lags behind the newest sample timestamp received from another replica by more than this amount of time, we will failover to the other replica, even if the accepted replica is still sending samples. 0 to disable.")
 
 	// We want the ability to use different instances for the ring and
 	// for HA cluster tracking. We also customize the default keys prefix, in
@@ -89,6 +106,10 @@ func (cfg *HATrackerConfig) Validate() error {
 		return errNegativeUpdateTimeoutJitterMax
 	}
 
+	if cfg.FailoverSampleLagThreshold < 0 {
+		return errNegativeFailoverSampleLag
+	}
+
 	minFailureTimeout := cfg.UpdateTimeout + cfg.UpdateTimeoutJitterMax + time.Second
 	if cfg.FailoverTimeout < minFailureTimeout {
 		return fmt.Errorf(errInvalidFailoverTimeout, cfg.FailoverTimeout, minFailureTimeout)
@@ -136,6 +157,11 @@ type haClusterInfo struct {
 	electedLastSeenTimestamp    int64
 	nonElectedLastSeenReplica   string
 	nonElectedLastSeenTimestamp int64
+
+	// Newest sample timestamps received from the elected replica and from the last seen non-elected replica,
+	// tracked when the failover sample lag threshold is set. Reset when the elected replica changes.
+	electedNewestSampleTimestamp    int64
+	nonElectedNewestSampleTimestamp int64
 }
 
 // newHATracker returns a new HA cluster tracker using either Consul,
@@ -302,11 +328,14 @@ func (h *haTracker) updateKVStoreAll(ctx context.Context, now time.Time) {
 	// the Go language allows this: https://golang.org/ref/spec#For_range note 3.
 	for userID, clusters := range h.clusters {
 		for cluster, entry := range clusters {
-			if h.withinUpdateTimeout(now, entry.elected.ReceivedAt) {
+			var replica, laggingReplica string
+			if h.electedReplicaLagging(now, entry) {
+				// The elected replica is lagging behind another one which is still sending samples: attempt to fail over.
+				replica, laggingReplica = entry.nonElectedLastSeenReplica, entry.elected.Replica
+				level.Info(h.logger).Log("msg", "elected replica is lagging behind another replica, failing over", "user", userID, "cluster", cluster, "elected", laggingReplica, "replica", replica)
+			} else if h.withinUpdateTimeout(now, entry.elected.ReceivedAt) {
 				continue // Some other process updated it recently; nothing to do.
-			}
-			var replica string
-			if h.withinUpdateTimeout(now, entry.electedLastSeenTimestamp) {
+			} else if h.withinUpdateTimeout(now, entry.electedLastSeenTimestamp) {
 				// We have seen the elected replica recently; carry on with that choice.
 				replica = entry.elected.Replica
 			} else if h.withinUpdateTimeout(now, entry.nonElectedLastSeenTimestamp) {
@@ -317,7 +346,7 @@ func (h *haTracker) updateKVStoreAll(ctx context.Context, now time.Time) {
 			}
 			// Release lock while we talk to KVStore, which could take a while.
 			h.electedLock.RUnlock()
-			err := h.updateKVStore(ctx, userID, cluster, replica, now)
+			err := h.updateKVStore(ctx, userID, cluster, replica, now, laggingReplica)
 			h.electedLock.RLock()
 			if err != nil {
 				// Failed to store - log it but carry on
@@ -327,6 +356,22 @@ func (h *haTracker) updateKVStoreAll(ctx context.Context, now time.Time) {
 	}
 }
 
+// electedReplicaLagging returns whether the newest sample received from the elected replica lags behind the newest sample
+// received from the last seen non-elected replica by more than the failover sample lag threshold, while the non-elected
+// replica is still sending samples. Must be called with electedLock held.
+func (h *haTracker) electedReplicaLagging(now time.Time, entry *haClusterInfo) bool {
+	if h.cfg.FailoverSampleLagThreshold <= 0 || entry.nonElectedLastSeenReplica == entry.elected.Replica {
+		return false
+	}
+	if entry.electedNewestSampleTimestamp == 0 || entry.nonElectedNewestSampleTimestamp == 0 {
+		return false
+	}
+	if !h.withinUpdateTimeout(now, entry.nonElectedLastSeenTimestamp) {
+		return false
+	}
+	return entry.nonElectedNewestSampleTimestamp-entry.electedNewestSampleTimestamp > h.cfg.FailoverSampleLagThreshold.Milliseconds()
+}
+
 // Replicas marked for deletion before deadline will be deleted.
 // Replicas with last-received timestamp before deadline will be marked for deletion.
 func (h *haTracker) cleanupOldReplicas(ctx context.Context, deadline time.Time) {
@@ -407,7 +452,9 @@ func (h *haTracker) cleanupOldReplicas(ctx context.Context, deadline time.Time)
 // Updates to and from the KV store are handled in the background, except
 // if we have no cached data for this cluster in which case we create the
 // record and store it in-band.
-func (h *haTracker) checkReplica(ctx context.Context, userID, cluster, replica string, now time.Time) error {
+// newestSampleTimestamp is the timestamp of the newest sample received from the replica,
+// used to detect a lagging elected replica. It's 0 if unknown.
+func (h *haTracker) checkReplica(ctx context.Context, userID, cluster, replica string, now time.Time, newestSampleTimestamp int64) error {
 	// If HA tracking isn't enabled then accept the sample
 	if !h.cfg.EnableHATracker {
 		return nil
@@ -419,10 +466,15 @@ func (h *haTracker) checkReplica(ctx context.Context, userID, cluster, replica s
 		if entry.elected.Replica == replica {
 			// Sample received is from elected replica: update timestamp and carry on.
 			entry.electedLastSeenTimestamp = timestamp.FromTime(now)
+			entry.electedNewestSampleTimestamp = max(entry.electedNewestSampleTimestamp, newestSampleTimestamp)
 		} else {
 			// Sample received is from non-elected replica: record details and reject.
+			if entry.nonElectedLastSeenReplica != replica {
+				entry.nonElectedNewestSampleTimestamp = 0
+			}
 			entry.nonElectedLastSeenReplica = replica
 			entry.nonElectedLastSeenTimestamp = timestamp.FromTime(now)
+			entry.nonElectedNewestSampleTimestamp = max(entry.nonElectedNewestSampleTimestamp, newestSampleTimestamp)
 			err = newReplicasDidNotMatchError(replica, entry.elected.Replica)
 		}
 		h.electedLock.Unlock()
@@ -437,13 +489,27 @@ func (h *haTracker) checkReplica(ctx context.Context, userID, cluster, replica s
 		return newTooManyClustersError(limit)
 	}
 
-	err := h.updateKVStore(ctx, userID, cluster, replica, now)
+	err := h.updateKVStore(ctx, userID, cluster, replica, now, "")
 	if err != nil {
 		level.Error(h.logger).Log("msg", "failed to update KVStore - rejecting sample", "err", err)
 		return err
 	}
 	// Cache will now have the value - recurse to check it again.
-	return h.checkReplica(ctx, userID, cluster, replica, now)
+	return h.checkReplica(ctx, userID, cluster, replica, now, newestSampleTimestamp)
+}
+
+// findNewestSampleTimestamp returns the timestamp of the newest float or histogram sample in the series.
+func findNewestSampleTimestamp(series []mimirpb.PreallocTimeseries) int64 {
+	var newest int64
+	for _, ts := range series {
+		for _, s := range ts.Samples {
+			newest = max(newest, s.TimestampMs)
+		}
+		for _, h := range ts.Histograms {
+			newest = max(newest, h.Timestamp)
+		}
+	}
+	return newest
 }
 
 func (h *haTracker) withinUpdateTimeout(now time.Time, receivedAt int64) bool {
@@ -462,6 +528,8 @@ func (h *haTracker) updateCache(userID, cluster string, desc *ReplicaDesc) {
 	}
 	if desc.Replica != entry.elected.Replica {
 		h.electedReplicaChanges.WithLabelValues(userID, cluster).Inc()
+		entry.electedNewestSampleTimestamp = 0
+		entry.nonElectedNewestSampleTimestamp = 0
 	}
 	entry.elected = *desc
 	h.electedReplicaTimestamp.WithLabelValues(userID, cluster).Set(float64(desc.ReceivedAt / 1000))
@@ -469,25 +537,37 @@ func (h *haTracker) updateCache(userID, cluster string, desc *ReplicaDesc) {
 
 // If we do set the value then err will be nil and desc will contain the value we set.
 // If there is already a valid value in the store, return nil, nil.
-func (h *haTracker) updateKVStore(ctx context.Context, userID, cluster, replica string, now time.Time) error {
+// If laggingReplica is set and is the replica in the KV store, the replica is replaced
+// without waiting for the update and failover timeouts.
+func (h *haTracker) updateKVStore(ctx context.Context, userID, cluster, replica string, now time.Time, laggingReplica string) error {
 	key := fmt.Sprintf("%s/%s", userID, cluster)
 	var desc *ReplicaDesc
 	err := h.client.CAS(ctx, key, func(in interface{}) (out interface{}, retry bool, err error) {
+		reason := electionReasonFirstReplica
 		var ok bool
 		if desc, ok = in.(*ReplicaDesc); ok && desc.DeletedAt == 0 {
+			switch {
+			case laggingReplica != "" && desc.Replica == laggingReplica && desc.Replica != replica:
+				// The elected replica is lagging behind our replica: fail over now.
+				reason = electionReasonSampleLag
 			// If the entry in KVStore is up-to-date, just stop the loop.
-			if h.withinUpdateTimeout(now, desc.ReceivedAt) ||
+			case h.withinUpdateTimeout(now, desc.ReceivedAt) ||
 				// If our replica is different, wait until the failover time.
-				desc.Replica != replica && now.Sub(timestamp.Time(desc.ReceivedAt)) < h.cfg.FailoverTimeout {
+				desc.Replica != replica && now.Sub(timestamp.Time(desc.ReceivedAt)) < h.cfg.FailoverTimeout:
 				return nil, false, nil
+			case desc.Replica == replica:
+				reason = desc.ElectionReason
+			default:
+				reason = electionReasonFailoverTimeout
 			}
 		}
 
 		// Attempt to update KVStore to our timestamp and replica.
 		desc = &ReplicaDesc{
-			Replica:    replica,
-			ReceivedAt: timestamp.FromTime(now),
-			DeletedAt:  0,
+			Replica:        replica,
+			ReceivedAt:     timestamp.FromTime(now),
+			DeletedAt:      0,
+			ElectionReason: reason,
 		}
 		return desc, true, nil
 	})
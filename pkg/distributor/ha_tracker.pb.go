@@ -34,6 +34,8 @@ type ReplicaDesc struct {
 	// already remove entry from memory. Actual deletion from KV store does *not* trigger
 	// "watch" notification with a key for all KV stores.
 	DeletedAt int64 `protobuf:"varint,3,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// Reason why this replica has been elected. The reason is kept when the timestamp of the elected replica is updated.
+	ElectionReason string `protobuf:"bytes,4,opt,name=election_reason,json=electionReason,proto3" json:"election_reason,omitempty"`
 }
 
 func (m *ReplicaDesc) Reset()      { *m = ReplicaDesc{} }
@@ -89,6 +91,13 @@ func (m *ReplicaDesc) GetDeletedAt() int64 {
 	return 0
 }
 
+func (m *ReplicaDesc) GetElectionReason() string {
+	if m != nil {
+		return m.ElectionReason
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ReplicaDesc)(nil), "distributor.ReplicaDesc")
 }
@@ -96,21 +105,23 @@ func init() {
 func init() { proto.RegisterFile("ha_tracker.proto", fileDescriptor_86f0e7bcf71d860b) }
 
 var fileDescriptor_86f0e7bcf71d860b = []byte{
-	// 224 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xc8, 0x48, 0x8c, 0x2f,
-	0x29, 0x4a, 0x4c, 0xce, 0x4e, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x4e, 0xc9,
-	0x2c, 0x2e, 0x29, 0xca, 0x4c, 0x2a, 0x2d, 0xc9, 0x2f, 0x92, 0xd2, 0x4d, 0xcf, 0x2c, 0xc9, 0x28,
-	0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xcf, 0x4f, 0xcf, 0xd7, 0x07, 0xab, 0x49, 0x2a, 0x4d,
-	0x03, 0xf3, 0xc0, 0x1c, 0x30, 0x0b, 0xa2, 0x57, 0x29, 0x9d, 0x8b, 0x3b, 0x28, 0xb5, 0x20, 0x27,
-	0x33, 0x39, 0xd1, 0x25, 0xb5, 0x38, 0x59, 0x48, 0x82, 0x8b, 0xbd, 0x08, 0xc2, 0x95, 0x60, 0x54,
-	0x60, 0xd4, 0xe0, 0x0c, 0x82, 0x71, 0x85, 0xe4, 0xb9, 0xb8, 0x8b, 0x52, 0x93, 0x53, 0x33, 0xcb,
-	0x52, 0x53, 0xe2, 0x13, 0x4b, 0x24, 0x98, 0x14, 0x18, 0x35, 0x98, 0x83, 0xb8, 0x60, 0x42, 0x8e,
-	0x25, 0x42, 0xb2, 0x5c, 0x5c, 0x29, 0xa9, 0x39, 0xa9, 0x25, 0x10, 0x79, 0x66, 0xb0, 0x3c, 0x27,
-	0x54, 0xc4, 0xb1, 0xc4, 0xc9, 0xe4, 0xc2, 0x43, 0x39, 0x86, 0x1b, 0x0f, 0xe5, 0x18, 0x3e, 0x3c,
-	0x94, 0x63, 0x6c, 0x78, 0x24, 0xc7, 0xb8, 0xe2, 0x91, 0x1c, 0xe3, 0x89, 0x47, 0x72, 0x8c, 0x17,
-	0x1e, 0xc9, 0x31, 0x3e, 0x78, 0x24, 0xc7, 0xf8, 0xe2, 0x91, 0x1c, 0xc3, 0x87, 0x47, 0x72, 0x8c,
-	0x13, 0x1e, 0xcb, 0x31, 0x5c, 0x78, 0x2c, 0xc7, 0x70, 0xe3, 0xb1, 0x1c, 0x43, 0x12, 0x1b, 0xd8,
-	0x95, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0xb3, 0xd1, 0xdd, 0x8d, 0xf5, 0x00, 0x00, 0x00,
+	// 244 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x34, 0x8f, 0x31, 0x4e, 0xc3, 0x40,
+	0x10, 0x45, 0x77, 0x08, 0x02, 0x65, 0x23, 0x01, 0xda, 0xca, 0x42, 0x62, 0x88, 0x68, 0x48, 0x43,
+	0x52, 0xc0, 0x05, 0x82, 0x38, 0x81, 0x2f, 0x60, 0xad, 0xd7, 0x83, 0xb3, 0xc2, 0x64, 0xa3, 0xf5,
+	0x98, 0x9a, 0x23, 0xe4, 0x18, 0x1c, 0x85, 0xd2, 0x65, 0x4a, 0xbc, 0x6e, 0x28, 0x73, 0x04, 0xa4,
+	0x35, 0xee, 0xe6, 0xbd, 0x3f, 0xa3, 0xd1, 0x97, 0x57, 0x1b, 0x9d, 0xb1, 0xd7, 0xe6, 0x8d, 0xfc,
+	0x72, 0xe7, 0x1d, 0x3b, 0x35, 0x2b, 0x6c, 0xcd, 0xde, 0xe6, 0x0d, 0x3b, 0x7f, 0xfd, 0x50, 0x5a,
+	0xde, 0x34, 0xf9, 0xd2, 0xb8, 0xf7, 0x55, 0xe9, 0x4a, 0xb7, 0x8a, 0x3b, 0x79, 0xf3, 0x1a, 0x29,
+	0x42, 0x9c, 0x86, 0xdb, 0xbb, 0x3d, 0xc8, 0x59, 0x4a, 0xbb, 0xca, 0x1a, 0xfd, 0x42, 0xb5, 0x51,
+	0x89, 0x3c, 0xf7, 0x03, 0x26, 0x30, 0x87, 0xc5, 0x34, 0x1d, 0x51, 0xdd, 0xca, 0x99, 0x27, 0x43,
+	0xf6, 0x83, 0x8a, 0x4c, 0x73, 0x72, 0x32, 0x87, 0xc5, 0x24, 0x95, 0xa3, 0x5a, 0xb3, 0xba, 0x91,
+	0xb2, 0xa0, 0x8a, 0x78, 0xc8, 0x27, 0x31, 0x9f, 0xfe, 0x9b, 0x35, 0xab, 0x7b, 0x79, 0x49, 0x15,
+	0x19, 0xb6, 0x6e, 0x9b, 0x79, 0xd2, 0xb5, 0xdb, 0x26, 0xa7, 0xf1, 0xc3, 0xc5, 0xa8, 0xd3, 0x68,
+	0x9f, 0x9f, 0xda, 0x0e, 0xc5, 0xa1, 0x43, 0x71, 0xec, 0x10, 0x3e, 0x03, 0xc2, 0x57, 0x40, 0xf8,
+	0x0e, 0x08, 0x6d, 0x40, 0xf8, 0x09, 0x08, 0xbf, 0x01, 0xc5, 0x31, 0x20, 0xec, 0x7b, 0x14, 0x6d,
+	0x8f, 0xe2, 0xd0, 0xa3, 0xc8, 0xcf, 0x62, 0x9f, 0xc7, 0xbf, 0x01, 0x00, 0xfb, 0xf4, 0xeb, 0x42,
+	0x1f, 0x01, 0x00, 0x00,
 }
 
 func (this *ReplicaDesc) Equal(that interface{}) bool {
@@ -141,17 +152,21 @@ func (this *ReplicaDesc) Equal(that interface{}) bool {
 	if this.DeletedAt != that1.DeletedAt {
 		return false
 	}
+	if this.ElectionReason != that1.ElectionReason {
+		return false
+	}
 	return true
 }
 func (this *ReplicaDesc) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 7)
+	s := make([]string, 0, 8)
 	s = append(s, "&distributor.ReplicaDesc{")
 	s = append(s, "Replica: "+fmt.Sprintf("%#v", this.Replica)+",\n")
 	s = append(s, "ReceivedAt: "+fmt.Sprintf("%#v", this.ReceivedAt)+",\n")
 	s = append(s, "DeletedAt: "+fmt.Sprintf("%#v", this.DeletedAt)+",\n")
+	s = append(s, "ElectionReason: "+fmt.Sprintf("%#v", this.ElectionReason)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -183,6 +198,13 @@ func (m *ReplicaDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.ElectionReason) > 0 {
+		i -= len(m.ElectionReason)
+		copy(dAtA[i:], m.ElectionReason)
+		i = encodeVarintHaTracker(dAtA, i, uint64(len(m.ElectionReason)))
+		i--
+		dAtA[i] = 0x22
+	}
 	if m.DeletedAt != 0 {
 		i = encodeVarintHaTracker(dAtA, i, uint64(m.DeletedAt))
 		i--
@@ -230,6 +252,10 @@ func (m *ReplicaDesc) Size() (n int) {
 	if m.DeletedAt != 0 {
 		n += 1 + sovHaTracker(uint64(m.DeletedAt))
 	}
+	l = len(m.ElectionReason)
+	if l > 0 {
+		n += 1 + l + sovHaTracker(uint64(l))
+	}
 	return n
 }
 
@@ -247,6 +273,7 @@ func (this *ReplicaDesc) String() string {
 		`Replica:` + fmt.Sprintf("%v", this.Replica) + `,`,
 		`ReceivedAt:` + fmt.Sprintf("%v", this.ReceivedAt) + `,`,
 		`DeletedAt:` + fmt.Sprintf("%v", this.DeletedAt) + `,`,
+		`ElectionReason:` + fmt.Sprintf("%v", this.ElectionReason) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -358,6 +385,38 @@ func (m *ReplicaDesc) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElectionReason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHaTracker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ElectionReason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHaTracker(dAtA[iNdEx:])
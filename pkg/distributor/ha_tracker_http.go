@@ -33,6 +33,11 @@ type haTrackerReplica struct {
 	ElectedAt    time.Time     `json:"electedAt"`
 	UpdateTime   time.Duration `json:"updateDuration"`
 	FailoverTime time.Duration `json:"failoverDuration"`
+
+	ElectionReason string `json:"electionReason"`
+	// How much the newest sample received from the elected replica lags behind the newest sample
+	// received from the last seen non-elected replica. Only tracked if the failover sample lag threshold is set.
+	SampleLag time.Duration `json:"sampleLag"`
 }
 
 func (h *haTracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -42,13 +47,19 @@ func (h *haTracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	for userID, clusters := range h.clusters {
 		for cluster, entry := range clusters {
 			desc := &entry.elected
+			var sampleLag time.Duration
+			if entry.electedNewestSampleTimestamp > 0 && entry.nonElectedNewestSampleTimestamp > entry.electedNewestSampleTimestamp {
+				sampleLag = time.Duration(entry.nonElectedNewestSampleTimestamp-entry.electedNewestSampleTimestamp) * time.Millisecond
+			}
 			electedReplicas = append(electedReplicas, haTrackerReplica{
-				UserID:       userID,
-				Cluster:      cluster,
-				Replica:      desc.Replica,
-				ElectedAt:    timestamp.Time(desc.ReceivedAt),
-				UpdateTime:   time.Until(timestamp.Time(desc.ReceivedAt).Add(h.cfg.UpdateTimeout)),
-				FailoverTime: time.Until(timestamp.Time(desc.ReceivedAt).Add(h.cfg.FailoverTimeout)),
+				UserID:         userID,
+				Cluster:        cluster,
+				Replica:        desc.Replica,
+				ElectedAt:      timestamp.Time(desc.ReceivedAt),
+				UpdateTime:     time.Until(timestamp.Time(desc.ReceivedAt).Add(h.cfg.UpdateTimeout)),
+				FailoverTime:   time.Until(timestamp.Time(desc.ReceivedAt).Add(h.cfg.FailoverTimeout)),
+				ElectionReason: desc.ElectionReason,
+				SampleLag:      sampleLag,
 			})
 		}
 	}
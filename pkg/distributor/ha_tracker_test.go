@@ -8,6 +8,8 @@ package distributor
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -125,6 +127,16 @@ func TestHATrackerConfig_Validate(t *testing.T) {
 			}(),
 			expectedErr: errMemberlistUnsupported,
 		},
+		"should fail if failover sample lag threshold is negative": {
+			cfg: func() HATrackerConfig {
+				cfg := HATrackerConfig{}
+				flagext.DefaultValues(&cfg)
+				cfg.FailoverSampleLagThreshold = -1
+
+				return cfg
+			}(),
+			expectedErr: errNegativeFailoverSampleLag,
+		},
 	}
 
 	for testName, testData := range tests {
@@ -158,7 +170,7 @@ func TestWatchPrefixAssignment(t *testing.T) {
 	// Write the first time.
 	now := time.Now()
 
-	err = c.checkReplica(context.Background(), "user", cluster, replica, now)
+	err = c.checkReplica(context.Background(), "user", cluster, replica, now, 0)
 	assert.NoError(t, err)
 
 	// Check to see if the value in the trackers cache is correct.
@@ -183,18 +195,18 @@ func TestCheckReplicaOverwriteTimeout(t *testing.T) {
 	now := time.Now()
 
 	// Write the first time.
-	err = c.checkReplica(context.Background(), "user", "test", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "test", replica1, now, 0)
 	assert.NoError(t, err)
 
 	// Throw away a sample from replica2.
-	err = c.checkReplica(context.Background(), "user", "test", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "test", replica2, now, 0)
 	assert.Error(t, err)
 
 	// Wait more than the overwrite timeout.
 	now = now.Add(1100 * time.Millisecond)
 
 	// Another sample from replica2 to update its timestamp.
-	err = c.checkReplica(context.Background(), "user", "test", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "test", replica2, now, 0)
 	assert.Error(t, err)
 
 	// Update KVStore - this should elect replica 2.
@@ -203,14 +215,81 @@ func TestCheckReplicaOverwriteTimeout(t *testing.T) {
 	checkReplicaTimestamp(t, time.Second, c, "user", "test", replica2, now)
 
 	// Now we should accept from replica 2.
-	err = c.checkReplica(context.Background(), "user", "test", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "test", replica2, now, 0)
 	assert.NoError(t, err)
 
 	// We timed out accepting samples from replica 1 and should now reject them.
-	err = c.checkReplica(context.Background(), "user", "test", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "test", replica1, now, 0)
 	assert.Error(t, err)
 }
 
+func TestCheckReplicaSampleLagFailover(t *testing.T) {
+	replica1 := "replica1"
+	replica2 := "replica2"
+
+	kvStore, closer := consul.NewInMemoryClient(GetReplicaDescCodec(), log.NewNopLogger(), nil)
+	t.Cleanup(func() { assert.NoError(t, closer.Close()) })
+
+	c, err := newHATracker(HATrackerConfig{
+		EnableHATracker:            true,
+		KVStore:                    kv.Config{Mock: kvStore},
+		UpdateTimeout:              100 * time.Millisecond,
+		UpdateTimeoutJitterMax:     0,
+		FailoverTimeout:            time.Second,
+		FailoverSampleLagThreshold: time.Minute,
+	}, trackerLimits{maxClusters: 100}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), c))
+	defer services.StopAndAwaitTerminated(context.Background(), c) //nolint:errcheck
+
+	electionReason := func() string {
+		c.electedLock.RLock()
+		defer c.electedLock.RUnlock()
+		return c.clusters["user"]["test"].elected.ElectionReason
+	}
+
+	now := time.Now()
+	newestSample := now.UnixMilli()
+
+	// Write the first time.
+	require.NoError(t, c.checkReplica(context.Background(), "user", "test", replica1, now, newestSample))
+	require.Equal(t, electionReasonFirstReplica, electionReason())
+
+	// replica2 is ahead of replica1, but within the threshold: replica1 stays elected.
+	now = now.Add(150 * time.Millisecond)
+	require.NoError(t, c.checkReplica(context.Background(), "user", "test", replica1, now, newestSample))
+	require.Error(t, c.checkReplica(context.Background(), "user", "test", replica2, now, newestSample+(30*time.Second).Milliseconds()))
+	c.updateKVStoreAll(context.Background(), now)
+	checkReplicaTimestamp(t, time.Second, c, "user", "test", replica1, now)
+	require.Equal(t, electionReasonFirstReplica, electionReason())
+
+	// replica1 keeps sending samples, but its newest sample lags behind replica2 by more than the threshold.
+	now = now.Add(150 * time.Millisecond)
+	require.NoError(t, c.checkReplica(context.Background(), "user", "test", replica1, now, newestSample))
+	require.Error(t, c.checkReplica(context.Background(), "user", "test", replica2, now, newestSample+(2*time.Minute).Milliseconds()))
+
+	// Update KVStore - this should elect replica2 before the failover timeout.
+	c.updateKVStoreAll(context.Background(), now)
+	checkReplicaTimestamp(t, time.Second, c, "user", "test", replica2, now)
+	require.Equal(t, electionReasonSampleLag, electionReason())
+
+	require.NoError(t, c.checkReplica(context.Background(), "user", "test", replica2, now, newestSample+(2*time.Minute).Milliseconds()))
+	require.Error(t, c.checkReplica(context.Background(), "user", "test", replica1, now, newestSample))
+
+	// The election reason is kept when the elected replica timestamp is updated.
+	now = now.Add(150 * time.Millisecond)
+	require.NoError(t, c.checkReplica(context.Background(), "user", "test", replica2, now, newestSample+(2*time.Minute).Milliseconds()))
+	c.updateKVStoreAll(context.Background(), now)
+	checkReplicaTimestamp(t, time.Second, c, "user", "test", replica2, now)
+	require.Equal(t, electionReasonSampleLag, electionReason())
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/distributor/ha_tracker", nil)
+	c.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "<td>sample_lag</td>")
+}
+
 func TestCheckReplicaMultiCluster(t *testing.T) {
 	replica1 := "replica1"
 	replica2 := "replica2"
@@ -230,21 +309,21 @@ func TestCheckReplicaMultiCluster(t *testing.T) {
 	now := time.Now()
 
 	// Write the first time.
-	err = c.checkReplica(context.Background(), "user", "c1", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "c1", replica1, now, 0)
 	assert.NoError(t, err)
-	err = c.checkReplica(context.Background(), "user", "c2", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "c2", replica1, now, 0)
 	assert.NoError(t, err)
 
 	// Reject samples from replica 2 in each cluster.
-	err = c.checkReplica(context.Background(), "user", "c1", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "c1", replica2, now, 0)
 	assert.Error(t, err)
-	err = c.checkReplica(context.Background(), "user", "c2", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "c2", replica2, now, 0)
 	assert.Error(t, err)
 
 	// We should still accept from replica 1.
-	err = c.checkReplica(context.Background(), "user", "c1", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "c1", replica1, now, 0)
 	assert.NoError(t, err)
-	err = c.checkReplica(context.Background(), "user", "c2", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "c2", replica1, now, 0)
 	assert.NoError(t, err)
 
 	// We expect no CAS operation failures.
@@ -280,45 +359,45 @@ func TestCheckReplicaMultiClusterTimeout(t *testing.T) {
 	now := time.Now()
 
 	// Write the first time.
-	err = c.checkReplica(context.Background(), "user", "c1", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "c1", replica1, now, 0)
 	assert.NoError(t, err)
-	err = c.checkReplica(context.Background(), "user", "c2", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "c2", replica1, now, 0)
 	assert.NoError(t, err)
 
 	// Reject samples from replica 2 in each cluster.
-	err = c.checkReplica(context.Background(), "user", "c1", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "c1", replica2, now, 0)
 	assert.Error(t, err)
-	err = c.checkReplica(context.Background(), "user", "c2", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "c2", replica2, now, 0)
 	assert.Error(t, err)
 
 	// Accept a sample for replica1 in C2.
 	now = now.Add(500 * time.Millisecond)
-	err = c.checkReplica(context.Background(), "user", "c2", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "c2", replica1, now, 0)
 	assert.NoError(t, err)
 
 	// Reject samples from replica 2 in each cluster.
-	err = c.checkReplica(context.Background(), "user", "c1", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "c1", replica2, now, 0)
 	assert.Error(t, err)
-	err = c.checkReplica(context.Background(), "user", "c2", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "c2", replica2, now, 0)
 	assert.Error(t, err)
 
 	// Wait more than the failover timeout.
 	now = now.Add(1100 * time.Millisecond)
 
 	// Another sample from c1/replica2 to update its timestamp.
-	err = c.checkReplica(context.Background(), "user", "c1", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "c1", replica2, now, 0)
 	assert.Error(t, err)
 	c.updateKVStoreAll(context.Background(), now)
 	checkReplicaTimestamp(t, time.Second, c, "user", "c1", replica2, now)
 
 	// Accept a sample from c1/replica2.
-	err = c.checkReplica(context.Background(), "user", "c1", replica2, now)
+	err = c.checkReplica(context.Background(), "user", "c1", replica2, now, 0)
 	assert.NoError(t, err)
 
 	// We should still accept from c2/replica1 but reject from c1/replica1.
-	err = c.checkReplica(context.Background(), "user", "c1", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "c1", replica1, now, 0)
 	assert.Error(t, err)
-	err = c.checkReplica(context.Background(), "user", "c2", replica1, now)
+	err = c.checkReplica(context.Background(), "user", "c2", replica1, now, 0)
 	assert.NoError(t, err)
 
 	// We expect no CAS operation failures.
@@ -359,13 +438,13 @@ func TestCheckReplicaUpdateTimeout(t *testing.T) {
 
 	// Write the first time.
 	startTime := time.Now()
-	err = c.checkReplica(context.Background(), user, cluster, replica, startTime)
+	err = c.checkReplica(context.Background(), user, cluster, replica, startTime, 0)
 	assert.NoError(t, err)
 
 	checkReplicaTimestamp(t, time.Second, c, user, cluster, replica, startTime)
 
 	// Timestamp should not update here, since time has not advanced.
-	err = c.checkReplica(context.Background(), user, cluster, replica, startTime)
+	err = c.checkReplica(context.Background(), user, cluster, replica, startTime, 0)
 	assert.NoError(t, err)
 
 	checkReplicaTimestamp(t, time.Second, c, user, cluster, replica, startTime)
@@ -374,7 +453,7 @@ func TestCheckReplicaUpdateTimeout(t *testing.T) {
 	updateTime := time.Unix(0, startTime.UnixNano()).Add(500 * time.Millisecond)
 	c.updateKVStoreAll(context.Background(), updateTime)
 
-	err = c.checkReplica(context.Background(), user, cluster, replica, updateTime)
+	err = c.checkReplica(context.Background(), user, cluster, replica, updateTime, 0)
 	assert.NoError(t, err)
 	checkReplicaTimestamp(t, time.Second, c, user, cluster, replica, startTime)
 
@@ -382,7 +461,7 @@ func TestCheckReplicaUpdateTimeout(t *testing.T) {
 	updateTime = time.Unix(0, startTime.UnixNano()).Add(1100 * time.Millisecond)
 	c.updateKVStoreAll(context.Background(), updateTime)
 
-	err = c.checkReplica(context.Background(), user, cluster, replica, updateTime)
+	err = c.checkReplica(context.Background(), user, cluster, replica, updateTime, 0)
 	assert.NoError(t, err)
 	checkReplicaTimestamp(t, time.Second, c, user, cluster, replica, updateTime)
 }
@@ -411,18 +490,18 @@ func TestCheckReplicaMultiUser(t *testing.T) {
 	now := time.Now()
 
 	// Write the first time for user 1.
-	err = c.checkReplica(context.Background(), "user1", cluster, replica, now)
+	err = c.checkReplica(context.Background(), "user1", cluster, replica, now, 0)
 	assert.NoError(t, err)
 	checkReplicaTimestamp(t, time.Second, c, "user1", cluster, replica, now)
 
 	// Write the first time for user 2.
-	err = c.checkReplica(context.Background(), "user2", cluster, replica, now)
+	err = c.checkReplica(context.Background(), "user2", cluster, replica, now, 0)
 	assert.NoError(t, err)
 	checkReplicaTimestamp(t, time.Second, c, "user2", cluster, replica, now)
 
 	// Now we've waited > 1s, so the timestamp should update.
 	updated := now.Add(1100 * time.Millisecond)
-	err = c.checkReplica(context.Background(), "user1", cluster, replica, updated)
+	err = c.checkReplica(context.Background(), "user1", cluster, replica, updated, 0)
 	assert.NoError(t, err)
 	c.updateKVStoreAll(context.Background(), updated)
 
@@ -499,12 +578,12 @@ func TestCheckReplicaUpdateTimeoutJitter(t *testing.T) {
 			c.updateTimeoutJitter = testData.updateJitter
 
 			// Init the replica in the KV Store
-			err = c.checkReplica(ctx, "user1", "cluster", "replica-1", testData.startTime)
+			err = c.checkReplica(ctx, "user1", "cluster", "replica-1", testData.startTime, 0)
 			require.NoError(t, err)
 			checkReplicaTimestamp(t, time.Second, c, "user1", "cluster", "replica-1", testData.startTime)
 
 			// Refresh the replica in the KV Store
-			err = c.checkReplica(ctx, "user1", "cluster", "replica-1", testData.updateTime)
+			err = c.checkReplica(ctx, "user1", "cluster", "replica-1", testData.updateTime, 0)
 			require.NoError(t, err)
 			c.updateKVStoreAll(context.Background(), testData.updateTime)
 
@@ -595,26 +674,26 @@ func TestHAClustersLimit(t *testing.T) {
 
 	now := time.Now()
 
-	assert.NoError(t, t1.checkReplica(context.Background(), userID, "a", "a1", now))
+	assert.NoError(t, t1.checkReplica(context.Background(), userID, "a", "a1", now, 0))
 	waitForClustersUpdate(t, 1, t1, userID)
 
-	assert.NoError(t, t1.checkReplica(context.Background(), userID, "b", "b1", now))
+	assert.NoError(t, t1.checkReplica(context.Background(), userID, "b", "b1", now, 0))
 	waitForClustersUpdate(t, 2, t1, userID)
 
 	expectedErr := newTooManyClustersError(2)
-	assert.EqualError(t, t1.checkReplica(context.Background(), userID, "c", "c1", now), expectedErr.Error())
+	assert.EqualError(t, t1.checkReplica(context.Background(), userID, "c", "c1", now, 0), expectedErr.Error())
 
 	// Move time forward, and make sure that checkReplica for existing cluster works fine.
 	now = now.Add(5 * time.Second) // higher than "update timeout"
 
 	// Another sample to update internal timestamp.
-	err = t1.checkReplica(context.Background(), userID, "b", "b2", now)
+	err = t1.checkReplica(context.Background(), userID, "b", "b2", now, 0)
 	assert.Error(t, err)
 	// Update KVStore.
 	t1.updateKVStoreAll(context.Background(), now)
 	checkReplicaTimestamp(t, time.Second, t1, userID, "b", "b2", now)
 
-	assert.NoError(t, t1.checkReplica(context.Background(), userID, "b", "b2", now))
+	assert.NoError(t, t1.checkReplica(context.Background(), userID, "b", "b2", now, 0))
 	waitForClustersUpdate(t, 2, t1, userID)
 
 	// Mark cluster "a" for deletion (it was last updated 5 seconds ago)
@@ -623,12 +702,12 @@ func TestHAClustersLimit(t *testing.T) {
 	waitForClustersUpdate(t, 1, t1, userID)
 
 	// Now adding cluster "c" works.
-	assert.NoError(t, t1.checkReplica(context.Background(), userID, "c", "c1", now))
+	assert.NoError(t, t1.checkReplica(context.Background(), userID, "c", "c1", now, 0))
 	waitForClustersUpdate(t, 2, t1, userID)
 
 	// But yet another cluster doesn't.
 	expectedErr = newTooManyClustersError(2)
-	assert.EqualError(t, t1.checkReplica(context.Background(), userID, "a", "a2", now), expectedErr.Error())
+	assert.EqualError(t, t1.checkReplica(context.Background(), userID, "a", "a2", now, 0), expectedErr.Error())
 
 	now = now.Add(5 * time.Second)
 
@@ -637,7 +716,7 @@ func TestHAClustersLimit(t *testing.T) {
 	waitForClustersUpdate(t, 0, t1, userID)
 
 	// Now "a" works again.
-	assert.NoError(t, t1.checkReplica(context.Background(), userID, "a", "a1", now))
+	assert.NoError(t, t1.checkReplica(context.Background(), userID, "a", "a1", now, 0))
 	waitForClustersUpdate(t, 1, t1, userID)
 }
 
@@ -743,7 +822,7 @@ func TestCheckReplicaCleanup(t *testing.T) {
 
 	now := time.Now()
 
-	err = c.checkReplica(context.Background(), userID, cluster, replica, now)
+	err = c.checkReplica(context.Background(), userID, cluster, replica, now, 0)
 	assert.NoError(t, err)
 	checkReplicaTimestamp(t, time.Second, c, userID, cluster, replica, now)
 
@@ -760,7 +839,7 @@ func TestCheckReplicaCleanup(t *testing.T) {
 
 	// This will "revive" the replica.
 	now = time.Now()
-	err = c.checkReplica(context.Background(), userID, cluster, replica, now)
+	err = c.checkReplica(context.Background(), userID, cluster, replica, now, 0)
 	assert.NoError(t, err)
 	checkReplicaTimestamp(t, time.Second, c, userID, cluster, replica, now) // This also checks that entry is not marked for deletion.
 	checkUserClusters(t, time.Second, c, userID, 1)
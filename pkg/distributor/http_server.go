@@ -14,10 +14,11 @@ import (
 
 // UserStats models ingestion statistics for one user.
 type UserStats struct {
-	IngestionRate     float64 `json:"ingestionRate"`
-	NumSeries         uint64  `json:"numSeries"`
-	APIIngestionRate  float64 `json:"APIIngestionRate"`
-	RuleIngestionRate float64 `json:"RuleIngestionRate"`
+	IngestionRate      float64 `json:"ingestionRate"`
+	NumSeries          uint64  `json:"numSeries"`
+	APIIngestionRate   float64 `json:"APIIngestionRate"`
+	RuleIngestionRate  float64 `json:"RuleIngestionRate"`
+	SeriesCreationRate float64 `json:"seriesCreationRate"`
 }
 
 // UserStatsHandler handles user stats to the Distributor.
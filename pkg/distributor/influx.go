@@ -0,0 +1,409 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+// influxValueField is the InfluxDB field whose series are named after the measurement only.
+const influxValueField = "value"
+
+// InfluxHandler is a http.Handler which accepts InfluxDB line protocol requests, optionally compressed with gzip.
+// Each numeric field of a line is converted into a series named "<measurement>_<field>", or "<measurement>"
+// for the "value" field, labelled with the line tags. String fields are ignored.
+func InfluxHandler(
+	maxRecvMsgSize int,
+	requestBufferPool util.Pool,
+	sourceIPs *middleware.SourceIPExtractor,
+	limits *validation.Overrides,
+	retryCfg RetryConfig,
+	push PushFunc,
+	pushMetrics *PushMetrics,
+	logger log.Logger,
+) http.Handler {
+	h := handler(maxRecvMsgSize, requestBufferPool, sourceIPs, false, limits, retryCfg, push, logger, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers, req *mimirpb.PreallocWriteRequest, logger log.Logger) error {
+		precision, err := influxPrecision(r.URL.Query().Get("precision"))
+		if err != nil {
+			return err
+		}
+
+		if r.ContentLength > int64(maxRecvMsgSize) {
+			return httpgrpc.Errorf(http.StatusRequestEntityTooLarge, distributorMaxWriteMessageSizeErr{
+				actual: int(r.ContentLength),
+				limit:  maxRecvMsgSize,
+			}.Error())
+		}
+
+		spanLogger, ctx := spanlogger.NewWithLogger(ctx, logger, "Distributor.InfluxHandler.decodeAndConvert")
+		defer spanLogger.Span.Finish()
+
+		body, err := readGzipBody(r, maxRecvMsgSize, buffers)
+		if err != nil {
+			return err
+		}
+
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return err
+		}
+		pushMetrics.IncInfluxRequest(tenantID)
+		pushMetrics.ObserveUncompressedBodySize(tenantID, float64(len(body)))
+
+		req.Timeseries, err = influxLinesToTimeseries(body, precision, time.Now())
+		if err != nil {
+			return err
+		}
+
+		level.Debug(spanLogger).Log("msg", "InfluxDB line protocol to Prometheus conversion complete", "series_count", len(req.Timeseries))
+		return nil
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// InfluxDB clients expect 204 No Content on success.
+		rw := &influxResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(rw, r)
+		if !rw.wroteHeader {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+// influxResponseWriter tracks whether the response status code has been written.
+type influxResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *influxResponseWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *influxResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// readGzipBody reads the request body, optionally compressed with gzip, up to maxRecvMsgSize bytes.
+func readGzipBody(r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers) ([]byte, error) {
+	var reader io.Reader = r.Body
+	switch contentEncoding := r.Header.Get("Content-Encoding"); contentEncoding {
+	case "gzip":
+		gzReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "create gzip reader")
+		}
+		defer gzReader.Close()
+		reader = gzReader
+	case "":
+	default:
+		return nil, httpgrpc.Errorf(http.StatusUnsupportedMediaType, "unsupported compression: %s. Only \"gzip\" or no compression supported", contentEncoding)
+	}
+
+	sz := int(r.ContentLength)
+	if sz > 0 {
+		// Extra space guarantees no reallocation
+		sz += bytes.MinRead
+	}
+	buf := buffers.Get(sz)
+
+	reader = http.MaxBytesReader(nil, io.NopCloser(reader), int64(maxRecvMsgSize))
+	if _, err := buf.ReadFrom(reader); err != nil {
+		if util.IsRequestBodyTooLarge(err) {
+			return nil, httpgrpc.Errorf(http.StatusRequestEntityTooLarge, distributorMaxWriteMessageSizeErr{
+				actual: -1,
+				limit:  maxRecvMsgSize,
+			}.Error())
+		}
+		return nil, errors.Wrap(err, "read write request")
+	}
+	return buf.Bytes(), nil
+}
+
+// influxPrecision returns the duration of the timestamps unit of the InfluxDB precision query parameter.
+func influxPrecision(precision string) (time.Duration, error) {
+	switch precision {
+	case "", "n", "ns":
+		return time.Nanosecond, nil
+	case "u", "us":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	default:
+		return 0, fmt.Errorf("invalid precision %q, supported values are: ns, us, ms, s, m, h", precision)
+	}
+}
+
+// influxLinesToTimeseries converts the InfluxDB line protocol data into series. Samples of lines without
+// a timestamp are timestamped with now. Samples of the same series are merged into a single series.
+func influxLinesToTimeseries(data []byte, precision time.Duration, now time.Time) ([]mimirpb.PreallocTimeseries, error) {
+	timeseries := mimirpb.PreallocTimeseriesSliceFromPool()
+	seriesIdx := map[string]int{}
+
+	for lineNum, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		point, err := parseInfluxLine(line, precision, now)
+		if err != nil {
+			mimirpb.ReuseSlice(timeseries)
+			return nil, fmt.Errorf("unable to parse InfluxDB line protocol at line %d: %w", lineNum+1, err)
+		}
+
+		for _, field := range point.fields {
+			lbls := make([]mimirpb.LabelAdapter, 0, len(point.tags)+1)
+			lbls = append(lbls, mimirpb.LabelAdapter{Name: model.MetricNameLabel, Value: influxMetricName(point.measurement, field.key)})
+			lbls = append(lbls, point.tags...)
+			sample := mimirpb.Sample{Value: field.value, TimestampMs: point.timestampMs}
+
+			key := mimirpb.FromLabelAdaptersToKeyString(lbls)
+			if idx, ok := seriesIdx[key]; ok {
+				timeseries[idx].Samples = append(timeseries[idx].Samples, sample)
+				continue
+			}
+
+			ts := mimirpb.TimeseriesFromPool()
+			ts.Labels = append(ts.Labels, lbls...)
+			ts.Samples = append(ts.Samples, sample)
+			seriesIdx[key] = len(timeseries)
+			timeseries = append(timeseries, mimirpb.PreallocTimeseries{TimeSeries: ts})
+		}
+	}
+
+	for _, ts := range timeseries {
+		slices.SortStableFunc(ts.Samples, func(a, b mimirpb.Sample) int {
+			return cmp.Compare(a.TimestampMs, b.TimestampMs)
+		})
+	}
+	return timeseries, nil
+}
+
+type influxPoint struct {
+	measurement string
+	tags        []mimirpb.LabelAdapter
+	fields      []influxField
+	timestampMs int64
+}
+
+type influxField struct {
+	key   string
+	value float64
+}
+
+// parseInfluxLine parses a line in the "<measurement>[,<tag>=<value>...] <field>=<value>[,<field>=<value>...] [<timestamp>]" format.
+func parseInfluxLine(line string, precision time.Duration, now time.Time) (influxPoint, error) {
+	// Double quotes are only special in string field values, so they're ignored when splitting the measurement and tags.
+	keysSection, rest := cutInfluxLine(line, ' ')
+	sections := splitInfluxLine(strings.TrimLeft(rest, " "), ' ', true)
+	if rest == "" || len(sections) > 2 {
+		return influxPoint{}, errors.New("expected measurement, fields and optional timestamp separated by spaces")
+	}
+
+	var point influxPoint
+
+	// Measurement and tags.
+	keys := splitInfluxLine(keysSection, ',', false)
+	point.measurement = unescapeInflux(keys[0])
+	if point.measurement == "" {
+		return influxPoint{}, errors.New("missing measurement")
+	}
+	for _, tag := range keys[1:] {
+		name, value, err := splitInfluxKeyValue(tag)
+		if err != nil {
+			return influxPoint{}, fmt.Errorf("invalid tag %q: %w", tag, err)
+		}
+		point.tags = append(point.tags, mimirpb.LabelAdapter{Name: sanitizeInfluxLabelName(name), Value: value})
+	}
+	slices.SortFunc(point.tags, func(a, b mimirpb.LabelAdapter) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	// Fields.
+	for _, field := range splitInfluxLine(sections[0], ',', true) {
+		key, rawValue, err := splitInfluxKeyValue(field)
+		if err != nil {
+			return influxPoint{}, fmt.Errorf("invalid field %q: %w", field, err)
+		}
+		value, ok, err := parseInfluxFieldValue(rawValue)
+		if err != nil {
+			return influxPoint{}, fmt.Errorf("invalid value of field %q: %w", key, err)
+		}
+		if ok {
+			point.fields = append(point.fields, influxField{key: key, value: value})
+		}
+	}
+
+	// Timestamp.
+	point.timestampMs = now.UnixMilli()
+	if len(sections) == 2 {
+		ts, err := strconv.ParseInt(sections[1], 10, 64)
+		if err != nil {
+			return influxPoint{}, fmt.Errorf("invalid timestamp %q", sections[1])
+		}
+		point.timestampMs = ts * int64(precision) / int64(time.Millisecond)
+	}
+
+	return point, nil
+}
+
+// cutInfluxLine slices s around the first unescaped separator.
+func cutInfluxLine(s string, sep byte) (string, string) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:]
+		}
+	}
+	return s, ""
+}
+
+// splitInfluxLine splits s on the separator, ignoring escaped separators and, if quotes is true, separators within double quotes.
+func splitInfluxLine(s string, sep byte, quotes bool) []string {
+	var (
+		parts    []string
+		start    int
+		inQuotes bool
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			inQuotes = quotes && !inQuotes
+		case sep:
+			if inQuotes {
+				continue
+			}
+			// Consecutive spaces are a single separator.
+			if sep == ' ' && i == start {
+				start = i + 1
+				continue
+			}
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func splitInfluxKeyValue(s string) (string, string, error) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '=':
+			key, value := unescapeInflux(s[:i]), s[i+1:]
+			if key == "" {
+				return "", "", errors.New("empty key")
+			}
+			if value == "" {
+				return "", "", errors.New("empty value")
+			}
+			return key, unescapeInflux(value), nil
+		}
+	}
+	return "", "", errors.New("missing '='")
+}
+
+var influxUnescaper = strings.NewReplacer(`\,`, ",", `\=`, "=", `\ `, " ", `\"`, `"`, `\\`, `\`)
+
+func unescapeInflux(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return influxUnescaper.Replace(s)
+}
+
+// parseInfluxFieldValue parses a field value. String fields are not converted, and false is returned for them.
+func parseInfluxFieldValue(v string) (float64, bool, error) {
+	switch v {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, true, nil
+	}
+
+	switch {
+	case strings.HasPrefix(v, `"`):
+		return 0, false, nil
+	case strings.HasSuffix(v, "i"):
+		i, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+		return float64(i), err == nil, err
+	case strings.HasSuffix(v, "u"):
+		u, err := strconv.ParseUint(v[:len(v)-1], 10, 64)
+		return float64(u), err == nil, err
+	default:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil, err
+	}
+}
+
+// influxMetricName returns the name of the series of the measurement field.
+func influxMetricName(measurement, field string) string {
+	if field == influxValueField {
+		return sanitizeInfluxMetricName(measurement)
+	}
+	return sanitizeInfluxMetricName(measurement + "_" + field)
+}
+
+func sanitizeInfluxMetricName(name string) string {
+	return sanitizeInfluxName(name, func(r rune) bool { return r == ':' })
+}
+
+func sanitizeInfluxLabelName(name string) string {
+	return sanitizeInfluxName(name, func(rune) bool { return false })
+}
+
+// sanitizeInfluxName replaces the characters not allowed in Prometheus names with underscores.
+func sanitizeInfluxName(name string, allowed func(r rune) bool) string {
+	var sb strings.Builder
+	sb.Grow(len(name) + 1)
+	for i, r := range name {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || allowed(r):
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
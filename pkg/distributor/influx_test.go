@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+func TestInfluxLinesToTimeseries(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+
+	tests := map[string]struct {
+		lines       string
+		precision   time.Duration
+		expected    []mimirpb.PreallocTimeseries
+		expectedErr string
+	}{
+		"fields are converted into series named after the measurement": {
+			lines:     "cpu,host=a,region=eu usage_idle=90.5,usage_user=5i,value=1u 1700000000000000000",
+			precision: time.Nanosecond,
+			expected: []mimirpb.PreallocTimeseries{
+				influxSeries(mimirpb.Sample{Value: 90.5, TimestampMs: 1700000000000}, "__name__", "cpu_usage_idle", "host", "a", "region", "eu"),
+				influxSeries(mimirpb.Sample{Value: 5, TimestampMs: 1700000000000}, "__name__", "cpu_usage_user", "host", "a", "region", "eu"),
+				influxSeries(mimirpb.Sample{Value: 1, TimestampMs: 1700000000000}, "__name__", "cpu", "host", "a", "region", "eu"),
+			},
+		},
+		"tags are sorted and names are sanitized": {
+			lines:     "disk.io,z-tag=1,a\\ tag=x\\,y reads=2 1700000000",
+			precision: time.Second,
+			expected: []mimirpb.PreallocTimeseries{
+				influxSeries(mimirpb.Sample{Value: 2, TimestampMs: 1700000000000}, "__name__", "disk_io_reads", "a_tag", "x,y", "z_tag", "1"),
+			},
+		},
+		"string fields are ignored and booleans are converted": {
+			lines:     `status,host=a message="all good, really",up=true,down=F 1700000000000`,
+			precision: time.Millisecond,
+			expected: []mimirpb.PreallocTimeseries{
+				influxSeries(mimirpb.Sample{Value: 1, TimestampMs: 1700000000000}, "__name__", "status_up", "host", "a"),
+				influxSeries(mimirpb.Sample{Value: 0, TimestampMs: 1700000000000}, "__name__", "status_down", "host", "a"),
+			},
+		},
+		"lines without timestamp get the current time, comments and empty lines are skipped": {
+			lines:     "# comment\n\nmem free=10\n",
+			precision: time.Nanosecond,
+			expected: []mimirpb.PreallocTimeseries{
+				influxSeries(mimirpb.Sample{Value: 10, TimestampMs: now.UnixMilli()}, "__name__", "mem_free"),
+			},
+		},
+		"samples of the same series are merged and sorted": {
+			lines:     "mem free=2 1700000001000\nmem free=1 1700000000000\n",
+			precision: time.Millisecond,
+			expected: []mimirpb.PreallocTimeseries{
+				{TimeSeries: &mimirpb.TimeSeries{
+					Labels:  []mimirpb.LabelAdapter{{Name: "__name__", Value: "mem_free"}},
+					Samples: []mimirpb.Sample{{Value: 1, TimestampMs: 1700000000000}, {Value: 2, TimestampMs: 1700000001000}},
+				}},
+			},
+		},
+		"missing fields": {
+			lines:       "mem,host=a",
+			expectedErr: "line 1: expected measurement, fields and optional timestamp separated by spaces",
+		},
+		"invalid field value": {
+			lines:       "mem free=1\nmem free=abc",
+			expectedErr: `line 2: invalid value of field "free"`,
+		},
+		"invalid integer field value": {
+			lines:       "mem free=1.5i",
+			expectedErr: `invalid value of field "free"`,
+		},
+		"invalid timestamp": {
+			lines:       "mem free=1 abc",
+			expectedErr: `invalid timestamp "abc"`,
+		},
+		"invalid tag": {
+			lines:       "mem,host free=1",
+			expectedErr: `invalid tag "host": missing '='`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := influxLinesToTimeseries([]byte(tc.lines), tc.precision, now)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, actual, len(tc.expected))
+			for i := range tc.expected {
+				assert.Equal(t, tc.expected[i].Labels, actual[i].Labels)
+				assert.Equal(t, tc.expected[i].Samples, actual[i].Samples)
+			}
+		})
+	}
+}
+
+func TestInfluxHandler(t *testing.T) {
+	const lines = "cpu,host=a usage_idle=90 1700000000000\ncpu,host=b usage_idle=80 1700000000000\n"
+
+	gzipped := func(data string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		return buf.Bytes()
+	}
+
+	tests := map[string]struct {
+		body            []byte
+		contentEncoding string
+		query           string
+		pushErr         error
+		maxMsgSize      int
+		expectedCode    int
+		expectedSeries  int
+	}{
+		"uncompressed request": {
+			body:           []byte(lines),
+			query:          "precision=ms",
+			expectedCode:   http.StatusNoContent,
+			expectedSeries: 2,
+		},
+		"gzip compressed request": {
+			body:            gzipped(lines),
+			contentEncoding: "gzip",
+			query:           "precision=ms",
+			expectedCode:    http.StatusNoContent,
+			expectedSeries:  2,
+		},
+		"unsupported compression": {
+			body:            []byte(lines),
+			contentEncoding: "snappy",
+			expectedCode:    http.StatusUnsupportedMediaType,
+		},
+		"invalid precision": {
+			body:         []byte(lines),
+			query:        "precision=d",
+			expectedCode: http.StatusBadRequest,
+		},
+		"invalid line protocol": {
+			body:         []byte("cpu"),
+			expectedCode: http.StatusBadRequest,
+		},
+		"request too large": {
+			body:         gzipped(lines),
+			maxMsgSize:   10,
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		"push error": {
+			body:         []byte(lines),
+			query:        "precision=ms",
+			pushErr:      httpgrpc.Errorf(http.StatusTooManyRequests, "too many requests"),
+			expectedCode: http.StatusTooManyRequests,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			maxMsgSize := tc.maxMsgSize
+			if maxMsgSize == 0 {
+				maxMsgSize = 100000
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/push/influx/write?"+tc.query, bytes.NewReader(tc.body))
+			if tc.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", tc.contentEncoding)
+			}
+			req = req.WithContext(user.InjectOrgID(context.Background(), "test"))
+
+			var pushed *mimirpb.WriteRequest
+			reg := prometheus.NewPedanticRegistry()
+			handler := InfluxHandler(maxMsgSize, nil, nil, nil, RetryConfig{}, func(_ context.Context, pushReq *Request) error {
+				request, err := pushReq.WriteRequest()
+				if err != nil {
+					return err
+				}
+				t.Cleanup(pushReq.CleanUp)
+				pushed = request
+				return tc.pushErr
+			}, newPushMetrics(reg), log.NewNopLogger())
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code, resp.Body.String())
+
+			if tc.expectedSeries > 0 {
+				require.Len(t, pushed.Timeseries, tc.expectedSeries)
+				require.Equal(t, []mimirpb.LabelAdapter{{Name: "__name__", Value: "cpu_usage_idle"}, {Name: "host", Value: "a"}}, pushed.Timeseries[0].Labels)
+				require.Equal(t, []mimirpb.Sample{{Value: 90, TimestampMs: 1700000000000}}, pushed.Timeseries[0].Samples)
+				require.Equal(t, mimirpb.API, pushed.Source)
+
+				require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+					# HELP cortex_distributor_influx_requests_total The total number of InfluxDB line protocol requests that have come in to the distributor.
+					# TYPE cortex_distributor_influx_requests_total counter
+					cortex_distributor_influx_requests_total{user="test"} 1
+				`), "cortex_distributor_influx_requests_total"))
+			}
+		})
+	}
+}
+
+func influxSeries(sample mimirpb.Sample, lbls ...string) mimirpb.PreallocTimeseries {
+	ts := &mimirpb.TimeSeries{Samples: []mimirpb.Sample{sample}}
+	for i := 0; i < len(lbls); i += 2 {
+		ts.Labels = append(ts.Labels, mimirpb.LabelAdapter{Name: lbls[i], Value: lbls[i+1]})
+	}
+	return mimirpb.PreallocTimeseries{TimeSeries: ts}
+}
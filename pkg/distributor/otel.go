@@ -43,12 +43,21 @@ const (
 	pbContentType   = "application/x-protobuf"
 	jsonContentType = "application/json"
 
-	otelParseError = "otlp_parse_error"
-	maxErrMsgLen   = 1024
+	otelParseError       = "otlp_parse_error"
+	otelDeltaSeriesLimit = "otlp_delta_series_limit"
+	maxErrMsgLen         = 1024
 )
 
 type OTLPHandlerLimits interface {
 	OTelMetricSuffixesEnabled(id string) bool
+	OTelConvertDeltaToCumulative(id string) bool
+	OTelDeltaMaxTrackedSeries(id string) int
+	PromoteOTelResourceAttributes(id string) []string
+	OTelDisableTargetInfo(id string) bool
+	OTelPromoteScopeMetadata(id string) bool
+	MaxLabelNamesPerSeries(id string) int
+	MaxLabelNameLength(id string) int
+	MaxLabelValueLength(id string) int
 }
 
 // OTLPHandler is an http.Handler accepting OTLP write requests.
@@ -66,8 +75,11 @@ func OTLPHandler(
 	directTranslation bool,
 ) http.Handler {
 	discardedDueToOtelParseError := validation.DiscardedSamplesCounter(reg, otelParseError)
+	discardedDueToOtelDeltaSeriesLimit := validation.DiscardedSamplesCounter(reg, otelDeltaSeriesLimit)
+	deltaConverter := newOTLPDeltaConverter(reg)
 
-	return otlpHandler(maxRecvMsgSize, requestBufferPool, sourceIPs, retryCfg, push, logger, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers, req *mimirpb.PreallocWriteRequest, logger log.Logger) error {
+	// The state of the converted delta series is updated only once the request has been successfully pushed.
+	return deltaConverter.withDeltaUpdates(otlpHandler(maxRecvMsgSize, requestBufferPool, sourceIPs, retryCfg, deltaConverter.commitAfterPush(push), logger, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers, req *mimirpb.PreallocWriteRequest, logger log.Logger) error {
 		contentType := r.Header.Get("Content-Type")
 		contentEncoding := r.Header.Get("Content-Encoding")
 		var compression util.CompressionType
@@ -165,14 +177,41 @@ func OTLPHandler(
 		pushMetrics.IncOTLPRequest(tenantID)
 		pushMetrics.ObserveUncompressedBodySize(tenantID, float64(uncompressedBodySize))
 
+		if limits.OTelConvertDeltaToCumulative(tenantID) {
+			update := deltaConverter.convert(tenantID, otlpReq.Metrics(), limits.OTelDeltaMaxTrackedSeries(tenantID), time.Now())
+			setOTLPDeltaUpdate(ctx, update)
+			if update.dropped > 0 {
+				discardedDueToOtelParseError.WithLabelValues(tenantID, "").Add(float64(update.dropped))
+				level.Debug(spanLogger).Log("msg", "dropped OTLP delta data points which couldn't be converted to cumulative", "dropped", update.dropped)
+			}
+			if update.droppedOverLimit > 0 {
+				discardedDueToOtelDeltaSeriesLimit.WithLabelValues(tenantID, "").Add(float64(update.droppedOverLimit))
+				level.Debug(spanLogger).Log("msg", "dropped OTLP delta data points of new series because the tracked delta series limit has been reached", "dropped", update.droppedOverLimit)
+			}
+		}
+
+		promotion := otlpAttributesPromotion{
+			resourceAttributes:     limits.PromoteOTelResourceAttributes(tenantID),
+			scopeMetadata:          limits.OTelPromoteScopeMetadata(tenantID),
+			maxLabelNamesPerSeries: limits.MaxLabelNamesPerSeries(tenantID),
+			maxLabelNameLength:     limits.MaxLabelNameLength(tenantID),
+			maxLabelValueLength:    limits.MaxLabelValueLength(tenantID),
+		}
+		if promotion.enabled() {
+			for reason, count := range promotion.promote(otlpReq.Metrics()) {
+				pushMetrics.AddOTLPDroppedAttributes(tenantID, reason, count)
+			}
+		}
+		disableTargetInfo := limits.OTelDisableTargetInfo(tenantID)
+
 		var metrics []mimirpb.PreallocTimeseries
 		if directTranslation {
-			metrics, err = otelMetricsToTimeseries(tenantID, addSuffixes, discardedDueToOtelParseError, logger, otlpReq.Metrics())
+			metrics, err = otelMetricsToTimeseries(tenantID, addSuffixes, disableTargetInfo, discardedDueToOtelParseError, logger, otlpReq.Metrics())
 			if err != nil {
 				return err
 			}
 		} else {
-			metrics, err = otelMetricsToTimeseriesOld(tenantID, addSuffixes, discardedDueToOtelParseError, logger, otlpReq.Metrics())
+			metrics, err = otelMetricsToTimeseriesOld(tenantID, addSuffixes, disableTargetInfo, discardedDueToOtelParseError, logger, otlpReq.Metrics())
 			if err != nil {
 				return err
 			}
@@ -205,7 +244,7 @@ func OTLPHandler(
 		}
 
 		return nil
-	})
+	}))
 }
 
 func otlpHandler(
@@ -405,10 +444,11 @@ func otelMetricsToMetadata(addSuffixes bool, md pmetric.Metrics) []*mimirpb.Metr
 	return metadata
 }
 
-func otelMetricsToTimeseries(tenantID string, addSuffixes bool, discardedDueToOtelParseError *prometheus.CounterVec, logger log.Logger, md pmetric.Metrics) ([]mimirpb.PreallocTimeseries, error) {
+func otelMetricsToTimeseries(tenantID string, addSuffixes, disableTargetInfo bool, discardedDueToOtelParseError *prometheus.CounterVec, logger log.Logger, md pmetric.Metrics) ([]mimirpb.PreallocTimeseries, error) {
 	converter := otlp.NewMimirConverter()
 	errs := converter.FromMetrics(md, otlp.Settings{
 		AddMetricSuffixes: addSuffixes,
+		DisableTargetInfo: disableTargetInfo,
 	})
 	mimirTS := converter.TimeSeries()
 	if errs != nil {
@@ -431,10 +471,11 @@ func otelMetricsToTimeseries(tenantID string, addSuffixes bool, discardedDueToOt
 }
 
 // Old, less efficient, version of otelMetricsToTimeseries.
-func otelMetricsToTimeseriesOld(tenantID string, addSuffixes bool, discardedDueToOtelParseError *prometheus.CounterVec, logger log.Logger, md pmetric.Metrics) ([]mimirpb.PreallocTimeseries, error) {
+func otelMetricsToTimeseriesOld(tenantID string, addSuffixes, disableTargetInfo bool, discardedDueToOtelParseError *prometheus.CounterVec, logger log.Logger, md pmetric.Metrics) ([]mimirpb.PreallocTimeseries, error) {
 	converter := prometheusremotewrite.NewPrometheusConverter()
 	errs := converter.FromMetrics(md, prometheusremotewrite.Settings{
 		AddMetricSuffixes: addSuffixes,
+		DisableTargetInfo: disableTargetInfo,
 	})
 	promTS := converter.TimeSeries()
 	if errs != nil {
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"slices"
+	"strings"
+
+	prometheustranslator "github.com/prometheus/prometheus/storage/remote/otlptranslator/prometheus"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+)
+
+const (
+	otlpScopeNameLabel       = "otel_scope_name"
+	otlpScopeVersionLabel    = "otel_scope_version"
+	otlpScopeAttributePrefix = "otel_scope_"
+
+	otlpDroppedAttributeReasonMaxLabelNames     = "max_label_names_per_series"
+	otlpDroppedAttributeReasonLabelNameTooLong  = "label_name_too_long"
+	otlpDroppedAttributeReasonLabelValueTooLong = "label_value_too_long"
+)
+
+// otlpAttributesPromotion promotes OTLP resource attributes and instrumentation scope metadata to series labels.
+type otlpAttributesPromotion struct {
+	resourceAttributes []string
+	scopeMetadata      bool
+
+	maxLabelNamesPerSeries int
+	maxLabelNameLength     int
+	maxLabelValueLength    int
+}
+
+type otlpPromotedAttribute struct {
+	name, value string
+}
+
+// enabled returns whether there's anything to promote.
+func (p otlpAttributesPromotion) enabled() bool {
+	return len(p.resourceAttributes) > 0 || p.scopeMetadata
+}
+
+// promote copies the promoted attributes into the attributes of every data point of md, so that the
+// translation to Prometheus time series turns them into labels. It returns the number of attributes
+// which were not promoted because of the label limits, by reason.
+func (p otlpAttributesPromotion) promote(md pmetric.Metrics) map[string]int {
+	dropped := map[string]int{}
+
+	resourceMetrics := md.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		rm := resourceMetrics.At(i)
+		resourceAttrs := rm.Resource().Attributes()
+
+		var resourcePromoted []otlpPromotedAttribute
+		for _, name := range p.resourceAttributes {
+			if value, ok := resourceAttrs.Get(name); ok {
+				resourcePromoted = append(resourcePromoted, otlpPromotedAttribute{name: name, value: value.AsString()})
+			}
+		}
+
+		// The metric name label, and the job and instance labels, are added by the translation.
+		extraLabels := 1
+		if _, ok := resourceAttrs.Get(conventions.AttributeServiceName); ok {
+			extraLabels++
+		}
+		if _, ok := resourceAttrs.Get(conventions.AttributeServiceInstanceID); ok {
+			extraLabels++
+		}
+
+		scopeMetrics := rm.ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			sm := scopeMetrics.At(j)
+			promoted := resourcePromoted
+			if p.scopeMetadata {
+				promoted = append(slices.Clip(promoted), otlpScopeMetadata(sm.Scope())...)
+			}
+			if len(promoted) == 0 {
+				continue
+			}
+
+			metrics := sm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				forEachOTLPDataPointAttributes(metrics.At(k), func(attrs pcommon.Map, bucketLabel bool) {
+					labels := extraLabels
+					if bucketLabel {
+						labels++
+					}
+					p.promoteTo(attrs, promoted, labels, dropped)
+				})
+			}
+		}
+	}
+
+	return dropped
+}
+
+// promoteTo adds the promoted attributes to attrs, unless they would exceed the label limits.
+// Attributes already in attrs take precedence.
+func (p otlpAttributesPromotion) promoteTo(attrs pcommon.Map, promoted []otlpPromotedAttribute, extraLabels int, dropped map[string]int) {
+	for _, attr := range promoted {
+		if _, ok := attrs.Get(attr.name); ok {
+			continue
+		}
+
+		var reason string
+		switch {
+		case p.maxLabelNamesPerSeries > 0 && attrs.Len()+extraLabels >= p.maxLabelNamesPerSeries:
+			reason = otlpDroppedAttributeReasonMaxLabelNames
+		case p.maxLabelNameLength > 0 && len(prometheustranslator.NormalizeLabel(attr.name)) > p.maxLabelNameLength:
+			reason = otlpDroppedAttributeReasonLabelNameTooLong
+		case p.maxLabelValueLength > 0 && len(attr.value) > p.maxLabelValueLength:
+			reason = otlpDroppedAttributeReasonLabelValueTooLong
+		default:
+			attrs.PutStr(attr.name, attr.value)
+			continue
+		}
+		dropped[reason]++
+	}
+}
+
+// otlpScopeMetadata returns the instrumentation scope name, version and attributes as attributes to promote.
+func otlpScopeMetadata(scope pcommon.InstrumentationScope) []otlpPromotedAttribute {
+	var result []otlpPromotedAttribute
+	if scope.Name() != "" {
+		result = append(result, otlpPromotedAttribute{name: otlpScopeNameLabel, value: scope.Name()})
+	}
+	if scope.Version() != "" {
+		result = append(result, otlpPromotedAttribute{name: otlpScopeVersionLabel, value: scope.Version()})
+	}
+
+	start := len(result)
+	scope.Attributes().Range(func(k string, v pcommon.Value) bool {
+		result = append(result, otlpPromotedAttribute{name: otlpScopeAttributePrefix + k, value: v.AsString()})
+		return true
+	})
+	// Sort the scope attributes, so that the same ones get promoted when the label limits are hit.
+	slices.SortFunc(result[start:], func(a, b otlpPromotedAttribute) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return result
+}
+
+// forEachOTLPDataPointAttributes calls fn with the attributes of every data point of metric,
+// and whether the translation adds a bucket or quantile label to the data point series.
+func forEachOTLPDataPointAttributes(metric pmetric.Metric, fn func(attrs pcommon.Map, bucketLabel bool)) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes(), false)
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes(), false)
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes(), true)
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes(), false)
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes(), true)
+		}
+	}
+}
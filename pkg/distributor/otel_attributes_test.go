@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestOTLPAttributesPromotion(t *testing.T) {
+	newMetrics := func() pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", "app")
+		rm.Resource().Attributes().PutStr("k8s.namespace.name", "prod")
+		rm.Resource().Attributes().PutStr("k8s.pod.name", "app-123")
+		sm := rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName("github.com/example/instrumentation")
+		sm.Scope().SetVersion("1.0.0")
+		sm.Scope().Attributes().PutStr("team", "checkout")
+		metric := sm.Metrics().AppendEmpty()
+		metric.SetName("requests")
+		dp := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("method", "GET")
+		dp.Attributes().PutStr("k8s.pod.name", "overridden")
+		return md
+	}
+	attributes := func(md pmetric.Metrics) map[string]any {
+		return md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).Attributes().AsRaw()
+	}
+
+	tests := map[string]struct {
+		promotion       otlpAttributesPromotion
+		expectedAttrs   map[string]any
+		expectedDropped map[string]int
+	}{
+		"resource attributes are promoted, data point attributes take precedence": {
+			promotion: otlpAttributesPromotion{resourceAttributes: []string{"k8s.namespace.name", "k8s.pod.name", "missing"}},
+			expectedAttrs: map[string]any{
+				"method":             "GET",
+				"k8s.pod.name":       "overridden",
+				"k8s.namespace.name": "prod",
+			},
+			expectedDropped: map[string]int{},
+		},
+		"scope metadata is promoted": {
+			promotion: otlpAttributesPromotion{scopeMetadata: true},
+			expectedAttrs: map[string]any{
+				"method":             "GET",
+				"k8s.pod.name":       "overridden",
+				"otel_scope_name":    "github.com/example/instrumentation",
+				"otel_scope_version": "1.0.0",
+				"otel_scope_team":    "checkout",
+			},
+			expectedDropped: map[string]int{},
+		},
+		"attributes exceeding the label limits are not promoted": {
+			promotion: otlpAttributesPromotion{
+				resourceAttributes:     []string{"k8s.namespace.name"},
+				scopeMetadata:          true,
+				maxLabelNamesPerSeries: 6,
+				maxLabelValueLength:    20,
+			},
+			// The translation also adds the __name__ and job labels.
+			expectedAttrs: map[string]any{
+				"method":             "GET",
+				"k8s.pod.name":       "overridden",
+				"k8s.namespace.name": "prod",
+				"otel_scope_version": "1.0.0",
+			},
+			expectedDropped: map[string]int{
+				otlpDroppedAttributeReasonLabelValueTooLong: 1,
+				otlpDroppedAttributeReasonMaxLabelNames:     1,
+			},
+		},
+		"attributes with too long names are not promoted": {
+			promotion:     otlpAttributesPromotion{resourceAttributes: []string{"k8s.namespace.name"}, maxLabelNameLength: 10},
+			expectedAttrs: map[string]any{"method": "GET", "k8s.pod.name": "overridden"},
+			expectedDropped: map[string]int{
+				otlpDroppedAttributeReasonLabelNameTooLong: 1,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			md := newMetrics()
+			dropped := tc.promotion.promote(md)
+			assert.Equal(t, tc.expectedAttrs, attributes(md))
+			assert.Equal(t, tc.expectedDropped, dropped)
+		})
+	}
+}
+
+func TestHandler_otlpAttributesPromotion(t *testing.T) {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "app")
+	rm.Resource().Attributes().PutStr("service.instance.id", "app-1")
+	rm.Resource().Attributes().PutStr("deployment.environment", "production")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("instrumentation")
+	metric := sm.Metrics().AppendEmpty()
+	metric.SetName("requests")
+	dp := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	dp.SetDoubleValue(1)
+
+	limits, err := validation.NewOverrides(validation.Limits{
+		PromoteOTelResourceAttributes: []string{"deployment.environment"},
+		OTelDisableTargetInfo:         true,
+		OTelPromoteScopeMetadata:      true,
+		MaxLabelNamesPerSeries:        4,
+	}, nil)
+	require.NoError(t, err)
+
+	for _, directTranslation := range []bool{true, false} {
+		var series [][]mimirpb.LabelAdapter
+		reg := prometheus.NewPedanticRegistry()
+		req := createOTLPProtoRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
+		resp := httptest.NewRecorder()
+		handler := OTLPHandler(100000, nil, nil, false, limits, RetryConfig{}, func(_ context.Context, pushReq *Request) error {
+			request, err := pushReq.WriteRequest()
+			if err != nil {
+				return err
+			}
+			t.Cleanup(pushReq.CleanUp)
+			for _, ts := range request.Timeseries {
+				series = append(series, ts.Labels)
+			}
+			return nil
+		}, newPushMetrics(reg), reg, log.NewNopLogger(), directTranslation)
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, 200, resp.Code)
+
+		// The target_info series is not generated.
+		require.Len(t, series, 1)
+		assert.Equal(t, []mimirpb.LabelAdapter{
+			{Name: "__name__", Value: "requests"},
+			{Name: "deployment_environment", Value: "production"},
+			{Name: "instance", Value: "app-1"},
+			{Name: "job", Value: "app"},
+		}, series[0])
+
+		require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP cortex_distributor_otlp_dropped_promoted_attributes_total The total number of OTLP resource and scope attributes which were not promoted to labels because of the label limits.
+			# TYPE cortex_distributor_otlp_dropped_promoted_attributes_total counter
+			cortex_distributor_otlp_dropped_promoted_attributes_total{reason="max_label_names_per_series",user="test"} 1
+		`), "cortex_distributor_otlp_dropped_promoted_attributes_total"))
+	}
+}
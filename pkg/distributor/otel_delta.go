@@ -0,0 +1,551 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	// otlpDeltaSeriesIdleTimeout is how long the cumulative state of a delta series is kept after its last data point.
+	otlpDeltaSeriesIdleTimeout = 15 * time.Minute
+
+	// otlpDeltaMaxExpHistogramBuckets is the maximum number of positive or negative buckets of a converted
+	// exponential histogram. When exceeded, the histogram is downscaled.
+	otlpDeltaMaxExpHistogramBuckets = 160
+
+	// otlpDeltaMinExpHistogramScale is the lowest scale supported by Prometheus native histograms.
+	otlpDeltaMinExpHistogramScale = -4
+)
+
+// otlpDeltaConverter converts OTLP delta sums and delta exponential histograms into cumulative ones,
+// keeping the running totals of each series in memory. The state is local to the distributor, so
+// clients should send the data points of a series to the same distributor, in order.
+type otlpDeltaConverter struct {
+	mtx       sync.Mutex
+	tenants   map[string]*otlpTenantDeltaState
+	lastSweep time.Time
+
+	trackedSeries prometheus.Gauge
+}
+
+type otlpTenantDeltaState struct {
+	mtx     sync.Mutex
+	series  map[uint64]*otlpDeltaSeries
+	removed bool
+
+	trackedSeries prometheus.Gauge
+}
+
+type otlpDeltaSeries struct {
+	startTimestamp pcommon.Timestamp
+	lastTimestamp  pcommon.Timestamp
+	lastUpdate     time.Time
+
+	sum       float64
+	histogram *otlpCumulativeExpHistogram
+}
+
+func (s *otlpDeltaSeries) clone() *otlpDeltaSeries {
+	c := *s
+	if s.histogram != nil {
+		h := *s.histogram
+		h.positive.counts = slices.Clone(h.positive.counts)
+		h.negative.counts = slices.Clone(h.negative.counts)
+		c.histogram = &h
+	}
+	return &c
+}
+
+func newOTLPDeltaConverter(reg prometheus.Registerer) *otlpDeltaConverter {
+	return &otlpDeltaConverter{
+		tenants: map[string]*otlpTenantDeltaState{},
+		trackedSeries: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "cortex_distributor_otlp_delta_tracked_series",
+			Help: "The number of OTLP delta series whose cumulative state is tracked by the distributor.",
+		}),
+	}
+}
+
+// otlpDeltaUpdate is the state of the delta series converted in a request. The state of the converter is
+// only updated once the update is committed, after the converted data points have been successfully pushed.
+type otlpDeltaUpdate struct {
+	tenantID  string
+	maxSeries int
+	series    map[uint64]otlpDeltaSeriesUpdate
+	newSeries int
+
+	// dropped is the number of data points which couldn't be converted.
+	dropped int
+	// droppedOverLimit is the number of data points of new series dropped because the tenant reached the tracked series limit.
+	droppedOverLimit int
+}
+
+type otlpDeltaSeriesUpdate struct {
+	prev *otlpDeltaSeries // The state of the series the update is based on, nil if the series wasn't tracked.
+	next *otlpDeltaSeries
+}
+
+// get returns a copy of the state of the series to update, and whether the series is tracked.
+func (u *otlpDeltaUpdate) get(state *otlpTenantDeltaState, key uint64) (*otlpDeltaSeries, bool) {
+	if su, ok := u.series[key]; ok {
+		return su.next, true
+	}
+	prev, ok := state.series[key]
+	if !ok {
+		return nil, false
+	}
+	return prev.clone(), true
+}
+
+// put sets the updated state of the series.
+func (u *otlpDeltaUpdate) put(state *otlpTenantDeltaState, key uint64, next *otlpDeltaSeries) {
+	if su, ok := u.series[key]; ok {
+		su.next = next
+		u.series[key] = su
+		return
+	}
+	prev := state.series[key]
+	if prev == nil {
+		u.newSeries++
+	}
+	u.series[key] = otlpDeltaSeriesUpdate{prev: prev, next: next}
+}
+
+// limitReached returns whether a new series can't be tracked because the tenant reached the tracked series limit.
+func (u *otlpDeltaUpdate) limitReached(state *otlpTenantDeltaState) bool {
+	return u.maxSeries > 0 && len(state.series)+u.newSeries >= u.maxSeries
+}
+
+// convert converts in place the delta sums and delta exponential histograms of md into cumulative ones, tracking at most
+// maxSeries delta series for the tenant, or an unlimited number if maxSeries is 0. Data points which can't be converted are
+// removed, and their number is returned in the update. The returned update must be committed once the converted data points
+// have been successfully pushed.
+func (c *otlpDeltaConverter) convert(tenantID string, md pmetric.Metrics, maxSeries int, now time.Time) *otlpDeltaUpdate {
+	c.removeIdleSeries(now)
+
+	u := &otlpDeltaUpdate{tenantID: tenantID, maxSeries: maxSeries}
+	if !hasOTLPDeltaMetrics(md) {
+		return u
+	}
+
+	state := c.lockTenantState(tenantID)
+	defer state.mtx.Unlock()
+
+	u.series = map[uint64]otlpDeltaSeriesUpdate{}
+	resourceMetrics := md.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		rm := resourceMetrics.At(i)
+		scopeMetrics := rm.ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			sm := scopeMetrics.At(j)
+			metrics := sm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				switch {
+				case metric.Type() == pmetric.MetricTypeSum && metric.Sum().AggregationTemporality() == pmetric.AggregationTemporalityDelta:
+					metric.Sum().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+						key := otlpDeltaSeriesKey(rm.Resource(), sm.Scope(), metric, dp.Attributes())
+						series, tracked := u.get(state, key)
+						if !tracked && u.limitReached(state) {
+							u.droppedOverLimit++
+							return true
+						}
+						series, ok := convertOTLPDeltaSum(series, dp, now)
+						if !ok {
+							u.dropped++
+							return true
+						}
+						u.put(state, key, series)
+						return false
+					})
+					metric.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+				case metric.Type() == pmetric.MetricTypeExponentialHistogram && metric.ExponentialHistogram().AggregationTemporality() == pmetric.AggregationTemporalityDelta:
+					metric.ExponentialHistogram().DataPoints().RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool {
+						key := otlpDeltaSeriesKey(rm.Resource(), sm.Scope(), metric, dp.Attributes())
+						series, tracked := u.get(state, key)
+						if !tracked && u.limitReached(state) {
+							u.droppedOverLimit++
+							return true
+						}
+						series, ok := convertOTLPDeltaExpHistogram(series, dp, now)
+						if !ok {
+							u.dropped++
+							return true
+						}
+						u.put(state, key, series)
+						return false
+					})
+					metric.ExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				}
+			}
+		}
+	}
+
+	return u
+}
+
+// commit applies the update to the state of the tenant's delta series. The series updated in the meantime by another
+// request, or removed because idle, are left untouched, because their update is based on a stale state.
+func (c *otlpDeltaConverter) commit(u *otlpDeltaUpdate) {
+	if len(u.series) == 0 {
+		return
+	}
+
+	state := c.lockTenantState(u.tenantID)
+	defer state.mtx.Unlock()
+
+	for key, su := range u.series {
+		if state.series[key] != su.prev {
+			continue
+		}
+		if su.prev != nil {
+			state.series[key] = su.next
+			continue
+		}
+		if u.maxSeries > 0 && len(state.series) >= u.maxSeries {
+			continue
+		}
+		state.addSeries(key, su.next)
+	}
+}
+
+type otlpDeltaUpdateContextKey struct{}
+
+// otlpDeltaUpdateHolder carries the delta update of a request from the request parsing to the push function.
+type otlpDeltaUpdateHolder struct {
+	update *otlpDeltaUpdate
+}
+
+// setOTLPDeltaUpdate stores the delta update of the request in ctx, to be committed by the push function wrapped by commitAfterPush.
+func setOTLPDeltaUpdate(ctx context.Context, u *otlpDeltaUpdate) {
+	if holder, ok := ctx.Value(otlpDeltaUpdateContextKey{}).(*otlpDeltaUpdateHolder); ok {
+		holder.update = u
+	}
+}
+
+// withDeltaUpdates wraps an OTLP handler, injecting in the request context the holder of the delta update of the request.
+func (c *otlpDeltaConverter) withDeltaUpdates(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), otlpDeltaUpdateContextKey{}, &otlpDeltaUpdateHolder{})
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// commitAfterPush wraps push, committing the delta update of the request once the request has been successfully pushed.
+func (c *otlpDeltaConverter) commitAfterPush(push PushFunc) PushFunc {
+	return func(ctx context.Context, req *Request) error {
+		if err := push(ctx, req); err != nil {
+			return err
+		}
+		if holder, ok := ctx.Value(otlpDeltaUpdateContextKey{}).(*otlpDeltaUpdateHolder); ok && holder.update != nil {
+			c.commit(holder.update)
+		}
+		return nil
+	}
+}
+
+// removeIdleSeries removes the state of the series which haven't been updated for otlpDeltaSeriesIdleTimeout,
+// at most once per otlpDeltaSeriesIdleTimeout.
+func (c *otlpDeltaConverter) removeIdleSeries(now time.Time) {
+	c.mtx.Lock()
+	if now.Sub(c.lastSweep) < otlpDeltaSeriesIdleTimeout {
+		c.mtx.Unlock()
+		return
+	}
+	c.lastSweep = now
+	tenants := make(map[string]*otlpTenantDeltaState, len(c.tenants))
+	for tenantID, state := range c.tenants {
+		tenants[tenantID] = state
+	}
+	c.mtx.Unlock()
+
+	for tenantID, state := range tenants {
+		state.mtx.Lock()
+		state.removeIdleSeries(now.Add(-otlpDeltaSeriesIdleTimeout))
+		if len(state.series) == 0 {
+			c.mtx.Lock()
+			delete(c.tenants, tenantID)
+			state.removed = true
+			c.mtx.Unlock()
+		}
+		state.mtx.Unlock()
+	}
+}
+
+// lockTenantState returns the locked state of the tenant, creating it if needed.
+func (c *otlpDeltaConverter) lockTenantState(tenantID string) *otlpTenantDeltaState {
+	for {
+		c.mtx.Lock()
+		state, ok := c.tenants[tenantID]
+		if !ok {
+			state = &otlpTenantDeltaState{series: map[uint64]*otlpDeltaSeries{}, trackedSeries: c.trackedSeries}
+			c.tenants[tenantID] = state
+		}
+		c.mtx.Unlock()
+
+		state.mtx.Lock()
+		// The state may have been removed while we were waiting for the lock.
+		if !state.removed {
+			return state
+		}
+		state.mtx.Unlock()
+	}
+}
+
+// convertOTLPDeltaSum adds the delta value of dp to the running total of the series, and replaces it with the cumulative value.
+// series is nil if the series isn't tracked yet. It returns the updated series, and false if dp is out of order and can't be converted.
+func convertOTLPDeltaSum(series *otlpDeltaSeries, dp pmetric.NumberDataPoint, now time.Time) (*otlpDeltaSeries, bool) {
+	if series == nil {
+		series = &otlpDeltaSeries{startTimestamp: dp.StartTimestamp()}
+		if series.startTimestamp == 0 {
+			series.startTimestamp = dp.Timestamp()
+		}
+	} else if dp.Timestamp() <= series.lastTimestamp {
+		return nil, false
+	}
+	series.lastTimestamp = dp.Timestamp()
+	series.lastUpdate = now
+
+	dp.SetStartTimestamp(series.startTimestamp)
+	if dp.Flags().NoRecordedValue() {
+		return series, true
+	}
+
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeInt:
+		series.sum += float64(dp.IntValue())
+	case pmetric.NumberDataPointValueTypeDouble:
+		series.sum += dp.DoubleValue()
+	}
+	dp.SetDoubleValue(series.sum)
+	return series, true
+}
+
+// convertOTLPDeltaExpHistogram adds the delta histogram dp to the running histogram of the series, and replaces it with the
+// cumulative histogram. series is nil if the series isn't tracked yet. It returns the updated series, and false if dp is out
+// of order, or it can't be merged with the previous data points.
+func convertOTLPDeltaExpHistogram(series *otlpDeltaSeries, dp pmetric.ExponentialHistogramDataPoint, now time.Time) (*otlpDeltaSeries, bool) {
+	if series == nil {
+		series = &otlpDeltaSeries{
+			startTimestamp: dp.StartTimestamp(),
+			histogram:      &otlpCumulativeExpHistogram{scale: dp.Scale(), zeroThreshold: dp.ZeroThreshold()},
+		}
+		if series.startTimestamp == 0 {
+			series.startTimestamp = dp.Timestamp()
+		}
+	} else if dp.Timestamp() <= series.lastTimestamp || series.histogram == nil {
+		return nil, false
+	}
+
+	if !dp.Flags().NoRecordedValue() && !series.histogram.add(dp) {
+		return nil, false
+	}
+
+	series.lastTimestamp = dp.Timestamp()
+	series.lastUpdate = now
+
+	dp.SetStartTimestamp(series.startTimestamp)
+	if !dp.Flags().NoRecordedValue() {
+		series.histogram.copyTo(dp)
+	}
+	return series, true
+}
+
+func (s *otlpTenantDeltaState) addSeries(key uint64, series *otlpDeltaSeries) {
+	s.series[key] = series
+	s.trackedSeries.Inc()
+}
+
+func (s *otlpTenantDeltaState) removeIdleSeries(before time.Time) {
+	for key, series := range s.series {
+		if series.lastUpdate.Before(before) {
+			delete(s.series, key)
+			s.trackedSeries.Dec()
+		}
+	}
+}
+
+// otlpCumulativeExpHistogram is the running total of a delta exponential histogram.
+type otlpCumulativeExpHistogram struct {
+	scale         int32
+	zeroThreshold float64
+	count         uint64
+	sum           float64
+	zeroCount     uint64
+	positive      otlpExpHistogramBuckets
+	negative      otlpExpHistogramBuckets
+}
+
+// add merges dp into the histogram, downscaling it if needed. It returns false if dp can't be merged.
+func (h *otlpCumulativeExpHistogram) add(dp pmetric.ExponentialHistogramDataPoint) bool {
+	if dp.ZeroThreshold() != h.zeroThreshold {
+		return false
+	}
+
+	scale := min(h.scale, dp.Scale())
+	for !h.positive.fits(h.scale-scale, dp.Positive(), dp.Scale()-scale) || !h.negative.fits(h.scale-scale, dp.Negative(), dp.Scale()-scale) {
+		if scale <= otlpDeltaMinExpHistogramScale {
+			return false
+		}
+		scale--
+	}
+
+	h.positive.downscale(h.scale - scale)
+	h.positive.add(dp.Positive(), dp.Scale()-scale)
+	h.negative.downscale(h.scale - scale)
+	h.negative.add(dp.Negative(), dp.Scale()-scale)
+
+	h.scale = scale
+	h.count += dp.Count()
+	h.sum += dp.Sum()
+	h.zeroCount += dp.ZeroCount()
+	return true
+}
+
+func (h *otlpCumulativeExpHistogram) copyTo(dp pmetric.ExponentialHistogramDataPoint) {
+	dp.SetScale(h.scale)
+	dp.SetCount(h.count)
+	dp.SetSum(h.sum)
+	dp.SetZeroCount(h.zeroCount)
+	dp.Positive().SetOffset(h.positive.offset)
+	dp.Positive().BucketCounts().FromRaw(h.positive.counts)
+	dp.Negative().SetOffset(h.negative.offset)
+	dp.Negative().BucketCounts().FromRaw(h.negative.counts)
+
+	// The minimum and maximum of the delta data point don't apply to the cumulative histogram.
+	dp.RemoveMin()
+	dp.RemoveMax()
+}
+
+// otlpExpHistogramBuckets holds dense bucket counts starting at the bucket index offset.
+type otlpExpHistogramBuckets struct {
+	offset int32
+	counts []uint64
+}
+
+// fits returns whether the buckets, downscaled by the given amount, and the other buckets,
+// downscaled by otherDownscale, fit together in otlpDeltaMaxExpHistogramBuckets.
+func (b *otlpExpHistogramBuckets) fits(downscale int32, other pmetric.ExponentialHistogramDataPointBuckets, otherDownscale int32) bool {
+	lowest, highest := int32(0), int32(-1)
+	extend := func(offset int32, length int, by int32) {
+		if length == 0 {
+			return
+		}
+		lo, hi := offset>>by, (offset+int32(length)-1)>>by
+		if highest < lowest {
+			lowest, highest = lo, hi
+			return
+		}
+		lowest, highest = min(lowest, lo), max(highest, hi)
+	}
+	extend(b.offset, len(b.counts), downscale)
+	extend(other.Offset(), other.BucketCounts().Len(), otherDownscale)
+	return int64(highest)-int64(lowest) < otlpDeltaMaxExpHistogramBuckets
+}
+
+// downscale merges the buckets, reducing their scale by the given amount.
+func (b *otlpExpHistogramBuckets) downscale(by int32) {
+	if by == 0 || len(b.counts) == 0 {
+		return
+	}
+	counts := b.counts
+	offset := b.offset
+	*b = otlpExpHistogramBuckets{}
+	for i, c := range counts {
+		b.addAt((offset+int32(i))>>by, c)
+	}
+}
+
+// add adds the other bucket counts, after downscaling them by the given amount.
+func (b *otlpExpHistogramBuckets) add(other pmetric.ExponentialHistogramDataPointBuckets, by int32) {
+	counts := other.BucketCounts()
+	for i := 0; i < counts.Len(); i++ {
+		b.addAt((other.Offset()+int32(i))>>by, counts.At(i))
+	}
+}
+
+func (b *otlpExpHistogramBuckets) addAt(idx int32, count uint64) {
+	if count == 0 {
+		return
+	}
+	switch {
+	case len(b.counts) == 0:
+		b.offset = idx
+		b.counts = []uint64{0}
+	case idx < b.offset:
+		counts := make([]uint64, int(b.offset-idx)+len(b.counts))
+		copy(counts[b.offset-idx:], b.counts)
+		b.offset = idx
+		b.counts = counts
+	case int(idx-b.offset) >= len(b.counts):
+		b.counts = append(b.counts, make([]uint64, int(idx-b.offset)-len(b.counts)+1)...)
+	}
+	b.counts[idx-b.offset] += count
+}
+
+// otlpDeltaSeriesKey returns the hash identifying the series of a data point.
+func otlpDeltaSeriesKey(resource pcommon.Resource, scope pcommon.InstrumentationScope, metric pmetric.Metric, attributes pcommon.Map) uint64 {
+	h := xxhash.New()
+	writeString := func(s string) {
+		_, _ = h.WriteString(s)
+		_, _ = h.Write([]byte{0xff})
+	}
+
+	writeString(metric.Name())
+	writeString(metric.Unit())
+	writeString(metric.Type().String())
+	writeString(scope.Name())
+	writeString(scope.Version())
+	for _, attrs := range []pcommon.Map{resource.Attributes(), attributes} {
+		keys := make([]string, 0, attrs.Len())
+		attrs.Range(func(k string, _ pcommon.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		slices.Sort(keys)
+		for _, k := range keys {
+			v, _ := attrs.Get(k)
+			writeString(k)
+			writeString(v.AsString())
+		}
+		writeString("")
+	}
+	return h.Sum64()
+}
+
+// hasOTLPDeltaMetrics returns whether md contains delta sums or delta exponential histograms.
+func hasOTLPDeltaMetrics(md pmetric.Metrics) bool {
+	resourceMetrics := md.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		scopeMetrics := resourceMetrics.At(i).ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			metrics := scopeMetrics.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				switch metric.Type() {
+				case pmetric.MetricTypeSum:
+					if metric.Sum().AggregationTemporality() == pmetric.AggregationTemporalityDelta {
+						return true
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					if metric.ExponentialHistogram().AggregationTemporality() == pmetric.AggregationTemporalityDelta {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,356 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestOTLPDeltaConverter_Sum(t *testing.T) {
+	now := time.Now()
+	c := newOTLPDeltaConverter(nil)
+
+	convert := func(tenantID string, points ...float64) (pmetric.Metric, int) {
+		md := pmetric.NewMetrics()
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", "app")
+		metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		metric.SetName("requests")
+		metric.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		metric.Sum().SetIsMonotonic(true)
+		for i := 0; i < len(points); i += 2 {
+			dp := metric.Sum().DataPoints().AppendEmpty()
+			dp.SetTimestamp(pcommon.Timestamp(points[i]))
+			dp.SetDoubleValue(points[i+1])
+		}
+		u := c.convert(tenantID, md, 0, now)
+		c.commit(u)
+		return metric, u.dropped
+	}
+
+	metric, dropped := convert("user-1", 10, 1, 20, 2)
+	require.Equal(t, 0, dropped)
+	require.Equal(t, pmetric.AggregationTemporalityCumulative, metric.Sum().AggregationTemporality())
+	require.Equal(t, 2, metric.Sum().DataPoints().Len())
+	assert.Equal(t, 1.0, metric.Sum().DataPoints().At(0).DoubleValue())
+	assert.Equal(t, 3.0, metric.Sum().DataPoints().At(1).DoubleValue())
+	assert.Equal(t, pcommon.Timestamp(10), metric.Sum().DataPoints().At(1).StartTimestamp())
+
+	// The state is kept across requests, and out of order data points are dropped.
+	metric, dropped = convert("user-1", 15, 100, 30, 4)
+	require.Equal(t, 1, dropped)
+	require.Equal(t, 1, metric.Sum().DataPoints().Len())
+	assert.Equal(t, 7.0, metric.Sum().DataPoints().At(0).DoubleValue())
+
+	// The state is per tenant.
+	metric, dropped = convert("user-2", 30, 5)
+	require.Equal(t, 0, dropped)
+	assert.Equal(t, 5.0, metric.Sum().DataPoints().At(0).DoubleValue())
+
+	// The state of idle series is removed.
+	now = now.Add(otlpDeltaSeriesIdleTimeout + time.Second)
+	metric, dropped = convert("user-1", 40, 1)
+	require.Equal(t, 0, dropped)
+	assert.Equal(t, 1.0, metric.Sum().DataPoints().At(0).DoubleValue())
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.trackedSeries))
+}
+
+func TestOTLPDeltaConverter_ExponentialHistogram(t *testing.T) {
+	c := newOTLPDeltaConverter(nil)
+
+	type point struct {
+		ts            pcommon.Timestamp
+		scale         int32
+		zeroThreshold float64
+		offset        int32
+		counts        []uint64
+	}
+	convert := func(p point) (pmetric.ExponentialHistogramDataPoint, int) {
+		md := pmetric.NewMetrics()
+		metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		metric.SetName("latency")
+		metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		dp := metric.ExponentialHistogram().DataPoints().AppendEmpty()
+		dp.SetTimestamp(p.ts)
+		dp.SetScale(p.scale)
+		dp.SetZeroThreshold(p.zeroThreshold)
+		dp.SetZeroCount(1)
+		dp.Positive().SetOffset(p.offset)
+		dp.Positive().BucketCounts().FromRaw(p.counts)
+		count := uint64(1)
+		for _, c := range p.counts {
+			count += c
+		}
+		dp.SetCount(count)
+		dp.SetSum(float64(count))
+		dp.SetMax(10)
+
+		u := c.convert("user-1", md, 0, time.Now())
+		c.commit(u)
+		dropped := u.dropped
+		require.Equal(t, pmetric.AggregationTemporalityCumulative, metric.ExponentialHistogram().AggregationTemporality())
+		if dropped > 0 {
+			require.Equal(t, 0, metric.ExponentialHistogram().DataPoints().Len())
+			return pmetric.NewExponentialHistogramDataPoint(), dropped
+		}
+		return metric.ExponentialHistogram().DataPoints().At(0), dropped
+	}
+
+	dp, dropped := convert(point{ts: 10, scale: 2, offset: 4, counts: []uint64{1, 2}})
+	require.Equal(t, 0, dropped)
+	assert.Equal(t, int32(2), dp.Scale())
+	assert.Equal(t, uint64(4), dp.Count())
+	assert.False(t, dp.HasMax())
+
+	// Buckets with a lower scale downscale the cumulative histogram.
+	dp, dropped = convert(point{ts: 20, scale: 1, offset: 1, counts: []uint64{3, 0, 1}})
+	require.Equal(t, 0, dropped)
+	assert.Equal(t, int32(1), dp.Scale())
+	assert.Equal(t, uint64(9), dp.Count())
+	assert.Equal(t, uint64(2), dp.ZeroCount())
+	assert.Equal(t, 9.0, dp.Sum())
+	assert.Equal(t, int32(1), dp.Positive().Offset())
+	assert.Equal(t, []uint64{3, 3, 1}, dp.Positive().BucketCounts().AsRaw())
+	assert.Equal(t, pcommon.Timestamp(10), dp.StartTimestamp())
+
+	// Buckets with a higher scale are downscaled.
+	dp, dropped = convert(point{ts: 30, scale: 3, offset: 12, counts: []uint64{1}})
+	require.Equal(t, 0, dropped)
+	assert.Equal(t, int32(1), dp.Scale())
+	assert.Equal(t, []uint64{3, 3, 2}, dp.Positive().BucketCounts().AsRaw())
+
+	// Buckets too far apart are downscaled to fit.
+	dp, dropped = convert(point{ts: 40, scale: 1, offset: 1000, counts: []uint64{1}})
+	require.Equal(t, 0, dropped)
+	assert.Less(t, dp.Scale(), int32(1))
+	assert.LessOrEqual(t, dp.Positive().BucketCounts().Len(), otlpDeltaMaxExpHistogramBuckets)
+	assert.Equal(t, uint64(13), dp.Count())
+
+	// A different zero threshold can't be merged.
+	_, dropped = convert(point{ts: 50, scale: 1, zeroThreshold: 0.5, offset: 1, counts: []uint64{1}})
+	require.Equal(t, 1, dropped)
+
+	// Out of order data points are dropped.
+	_, dropped = convert(point{ts: 40, scale: 1, offset: 1, counts: []uint64{1}})
+	require.Equal(t, 1, dropped)
+}
+
+func TestOTLPDeltaConverter_MaxTrackedSeries(t *testing.T) {
+	now := time.Now()
+	c := newOTLPDeltaConverter(nil)
+
+	newMetrics := func(ts pcommon.Timestamp, series ...string) (pmetric.Metrics, pmetric.Metric) {
+		md := pmetric.NewMetrics()
+		metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		metric.SetName("requests")
+		metric.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		for _, s := range series {
+			dp := metric.Sum().DataPoints().AppendEmpty()
+			dp.Attributes().PutStr("series", s)
+			dp.SetTimestamp(ts)
+			dp.SetDoubleValue(1)
+		}
+		return md, metric
+	}
+
+	md, metric := newMetrics(10, "a", "b", "c")
+	u := c.convert("user-1", md, 2, now)
+	require.Equal(t, 0, u.dropped)
+	require.Equal(t, 1, u.droppedOverLimit)
+	require.Equal(t, 2, metric.Sum().DataPoints().Len())
+	c.commit(u)
+	assert.Equal(t, 2.0, testutil.ToFloat64(c.trackedSeries))
+
+	// The data points of the tracked series are still converted once the limit is reached.
+	md, metric = newMetrics(20, "a", "c")
+	u = c.convert("user-1", md, 2, now)
+	require.Equal(t, 1, u.droppedOverLimit)
+	require.Equal(t, 1, metric.Sum().DataPoints().Len())
+	assert.Equal(t, 2.0, metric.Sum().DataPoints().At(0).DoubleValue())
+	c.commit(u)
+
+	// The limit is per tenant.
+	md, metric = newMetrics(20, "a", "c")
+	u = c.convert("user-2", md, 2, now)
+	require.Equal(t, 0, u.droppedOverLimit)
+	require.Equal(t, 2, metric.Sum().DataPoints().Len())
+}
+
+func TestOTLPDeltaConverter_StateUpdatedOnlyOnCommit(t *testing.T) {
+	now := time.Now()
+	c := newOTLPDeltaConverter(nil)
+
+	convert := func(ts pcommon.Timestamp) (*otlpDeltaUpdate, float64) {
+		md := pmetric.NewMetrics()
+		metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		metric.SetName("requests")
+		metric.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		dp := metric.Sum().DataPoints().AppendEmpty()
+		dp.SetTimestamp(ts)
+		dp.SetDoubleValue(1)
+
+		u := c.convert("user-1", md, 0, now)
+		require.Equal(t, 0, u.dropped)
+		require.Equal(t, 1, metric.Sum().DataPoints().Len())
+		return u, metric.Sum().DataPoints().At(0).DoubleValue()
+	}
+
+	// The update of a request which failed to be pushed isn't committed.
+	_, value := convert(10)
+	assert.Equal(t, 1.0, value)
+	assert.Equal(t, 0.0, testutil.ToFloat64(c.trackedSeries))
+
+	// A retry of the request is converted to the same value.
+	u, value := convert(10)
+	assert.Equal(t, 1.0, value)
+	c.commit(u)
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.trackedSeries))
+
+	// An update based on a stale state isn't committed.
+	stale, value := convert(20)
+	assert.Equal(t, 2.0, value)
+	u, value = convert(30)
+	assert.Equal(t, 2.0, value)
+	c.commit(u)
+	c.commit(stale)
+
+	_, value = convert(40)
+	assert.Equal(t, 3.0, value)
+}
+
+func TestOTLPExpHistogramBuckets_Downscale(t *testing.T) {
+	b := otlpExpHistogramBuckets{offset: -3, counts: []uint64{1, 2, 3, 4, 5}}
+	b.downscale(1)
+	// Indexes -3..1 become -2, -1, -1, 0, 0.
+	assert.Equal(t, otlpExpHistogramBuckets{offset: -2, counts: []uint64{1, 5, 9}}, b)
+}
+
+func TestHandler_otlpDeltaToCumulative(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("requests")
+	metric.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	metric.Sum().SetIsMonotonic(true)
+	ts := time.Now()
+	for i := 0; i < 2; i++ {
+		dp := metric.Sum().DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(ts.Add(time.Duration(i) * time.Second)))
+		dp.SetIntValue(2)
+	}
+	// A data point with a duplicate timestamp can't be converted.
+	metric.Sum().DataPoints().At(0).CopyTo(metric.Sum().DataPoints().AppendEmpty())
+
+	for _, directTranslation := range []bool{true, false} {
+		for _, enabled := range []bool{true, false} {
+			limits, err := validation.NewOverrides(validation.Limits{OTelConvertDeltaToCumulative: enabled}, nil)
+			require.NoError(t, err)
+
+			var samples []mimirpb.Sample
+			reg := prometheus.NewPedanticRegistry()
+			req := createOTLPProtoRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false)
+			resp := httptest.NewRecorder()
+			handler := OTLPHandler(100000, nil, nil, false, limits, RetryConfig{}, func(_ context.Context, pushReq *Request) error {
+				request, err := pushReq.WriteRequest()
+				if err != nil {
+					return err
+				}
+				t.Cleanup(pushReq.CleanUp)
+				for _, ts := range request.Timeseries {
+					samples = append(samples, ts.Samples...)
+				}
+				return nil
+			}, nil, reg, log.NewNopLogger(), directTranslation)
+			handler.ServeHTTP(resp, req)
+
+			if !enabled {
+				// Delta sums are rejected.
+				require.Equal(t, 400, resp.Code)
+				continue
+			}
+			require.Equal(t, 200, resp.Code)
+			require.Len(t, samples, 2)
+			assert.Equal(t, 2.0, samples[0].Value)
+			assert.Equal(t, 4.0, samples[1].Value)
+			require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+				# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+				# TYPE cortex_discarded_samples_total counter
+				cortex_discarded_samples_total{group="",reason="otlp_parse_error",user="test"} 1
+			`), "cortex_discarded_samples_total"))
+		}
+	}
+}
+
+func TestHandler_otlpDeltaToCumulative_ShouldUpdateStateOnlyAfterSuccessfulPush(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("requests")
+	metric.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	metric.Sum().SetIsMonotonic(true)
+	ts := time.Now()
+	for _, series := range []string{"a", "b"} {
+		dp := metric.Sum().DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("series", series)
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		dp.SetIntValue(2)
+	}
+
+	limits, err := validation.NewOverrides(validation.Limits{OTelConvertDeltaToCumulative: true, OTelDeltaMaxTrackedSeries: 1}, nil)
+	require.NoError(t, err)
+
+	var (
+		samples []mimirpb.Sample
+		pushErr error
+	)
+	reg := prometheus.NewPedanticRegistry()
+	handler := OTLPHandler(100000, nil, nil, false, limits, RetryConfig{}, func(_ context.Context, pushReq *Request) error {
+		request, err := pushReq.WriteRequest()
+		if err != nil {
+			return err
+		}
+		t.Cleanup(pushReq.CleanUp)
+		for _, ts := range request.Timeseries {
+			samples = append(samples, ts.Samples...)
+		}
+		return pushErr
+	}, nil, reg, log.NewNopLogger(), true)
+
+	// The first push fails, so the state of the delta series isn't updated.
+	pushErr = httpgrpc.Errorf(http.StatusServiceUnavailable, "unavailable")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, createOTLPProtoRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false))
+	require.Equal(t, http.StatusServiceUnavailable, resp.Code)
+
+	// The retry is converted to the same cumulative value.
+	pushErr = nil
+	samples = nil
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, createOTLPProtoRequest(t, pmetricotlp.NewExportRequestFromMetrics(md), false))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Len(t, samples, 1)
+	assert.Equal(t, 2.0, samples[0].Value)
+
+	// The data points of the series exceeding the tracked series limit are discarded.
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+		# TYPE cortex_discarded_samples_total counter
+		cortex_discarded_samples_total{group="",reason="otlp_delta_series_limit",user="test"} 2
+	`), "cortex_discarded_samples_total"))
+}
@@ -68,7 +68,8 @@ func (cfg *RetryConfig) Validate() error {
 	return nil
 }
 
-// Handler is a http.Handler which accepts WriteRequests.
+// Handler is a http.Handler which accepts WriteRequests. Prometheus remote-write 2.0 requests are negotiated
+// with the Content-Type header, and converted into WriteRequests.
 func Handler(
 	maxRecvMsgSize int,
 	requestBufferPool util.Pool,
@@ -81,14 +82,25 @@ func Handler(
 	logger log.Logger,
 ) http.Handler {
 	return handler(maxRecvMsgSize, requestBufferPool, sourceIPs, allowSkipLabelNameValidation, limits, retryCfg, push, logger, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers, req *mimirpb.PreallocWriteRequest, _ log.Logger) error {
-		protoBodySize, err := util.ParseProtoReader(ctx, r.Body, int(r.ContentLength), maxRecvMsgSize, buffers, req, util.RawSnappy)
-		if errors.Is(err, util.MsgSizeTooLargeErr{}) {
-			err = distributorMaxWriteMessageSizeErr{actual: int(r.ContentLength), limit: maxRecvMsgSize}
+		protoMessage, err := remoteWriteProtoMessage(r.Header.Get("Content-Type"))
+		if err != nil {
+			return httpgrpc.Errorf(http.StatusUnsupportedMediaType, err.Error())
 		}
+
+		tenantID, err := tenant.TenantID(ctx)
 		if err != nil {
 			return err
 		}
-		tenantID, err := tenant.TenantID(ctx)
+
+		var protoBodySize int
+		if protoMessage == remoteWriteV2ProtoMessage {
+			protoBodySize, err = parseRemoteWriteV2(ctx, r, maxRecvMsgSize, buffers, req, limits.RemoteWriteCreatedTimestampZeroIngestionEnabled(tenantID))
+		} else {
+			protoBodySize, err = util.ParseProtoReader(ctx, r.Body, int(r.ContentLength), maxRecvMsgSize, buffers, req, util.RawSnappy)
+		}
+		if errors.Is(err, util.MsgSizeTooLargeErr{}) {
+			err = distributorMaxWriteMessageSizeErr{actual: int(r.ContentLength), limit: maxRecvMsgSize}
+		}
 		if err != nil {
 			return err
 		}
@@ -130,6 +142,15 @@ func handler(
 				logger = utillog.WithSourceIPs(source, logger)
 			}
 		}
+		// Remote-write 2.0 requests get the number of written samples, histograms and exemplars in every response.
+		// The samples dropped before being written to the storage, for example by relabeling, aren't counted.
+		isRemoteWriteV2 := isRemoteWriteV2Request(r)
+		var written *writtenStats
+		if isRemoteWriteV2 {
+			written = &writtenStats{}
+			ctx = contextWithWrittenStats(ctx, written)
+		}
+
 		supplier := func() (*mimirpb.WriteRequest, func(), error) {
 			rb := util.NewRequestBuffers(requestBufferPool)
 			var req mimirpb.PreallocWriteRequest
@@ -156,7 +177,14 @@ func handler(
 			return &req.WriteRequest, cleanup, nil
 		}
 		req := newRequest(supplier)
-		if err := push(ctx, req); err != nil {
+		err := push(ctx, req)
+
+		// The written stats are returned for failed requests too, given they may have been partially written.
+		if isRemoteWriteV2 {
+			written.setWrittenHeaders(w.Header())
+		}
+
+		if err != nil {
 			if errors.Is(err, context.Canceled) {
 				http.Error(w, err.Error(), statusClientClosedRequest)
 				level.Warn(logger).Log("msg", "push request canceled", "err", err)
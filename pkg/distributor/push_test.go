@@ -32,6 +32,8 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/stretchr/testify/assert"
@@ -56,6 +58,234 @@ func TestHandler_remoteWrite(t *testing.T) {
 	assert.Equal(t, 200, resp.Code)
 }
 
+func TestHandler_remoteWriteV2(t *testing.T) {
+	histogram := remote.HistogramToHistogramProto(1337, test.GenerateTestHistogram(1))
+	reqV2 := mimirpb.FromPrometheusTimeseriesToWriteRequestV2([]prompb.TimeSeries{
+		{
+			Labels:     []prompb.Label{{Name: "__name__", Value: "foo"}, {Name: "job", Value: "test"}},
+			Samples:    []prompb.Sample{{Value: 1, Timestamp: 1000}, {Value: 2, Timestamp: 2000}},
+			Histograms: []prompb.Histogram{histogram},
+			Exemplars:  []prompb.Exemplar{{Labels: []prompb.Label{{Name: "trace_id", Value: "abc"}}, Value: 1, Timestamp: 1000}},
+		},
+		{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "bar"}, {Name: "job", Value: "test"}},
+			Samples: []prompb.Sample{{Value: 3, Timestamp: 1000}},
+		},
+	}, []mimirpb.MetricMetadata{{Type: mimirpb.COUNTER, MetricFamilyName: "foo", Help: "Foo help.", Unit: "seconds"}})
+	data, err := reqV2.Marshal()
+	require.NoError(t, err)
+
+	limits := validation.Limits{}
+	flagext.DefaultValues(&limits)
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	t.Run("request is converted and the written stats are returned", func(t *testing.T) {
+		req := createRequest(t, data)
+		req.Header.Set("Content-Type", RemoteWriteV2ContentType)
+		req.Header.Set("X-Prometheus-Remote-Write-Version", "2.0.0")
+
+		var pushed *mimirpb.WriteRequest
+		resp := httptest.NewRecorder()
+		handler := Handler(100000, nil, nil, false, overrides, RetryConfig{}, func(ctx context.Context, pushReq *Request) error {
+			request, err := pushReq.WriteRequest()
+			require.NoError(t, err)
+			t.Cleanup(pushReq.CleanUp)
+			pushed = request
+
+			// Emulate the distributor writing the request to the storage.
+			writtenStatsFromContext(ctx).add(newRemoteWriteStats(request))
+			return nil
+		}, nil, log.NewNopLogger())
+		handler.ServeHTTP(resp, req)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Equal(t, "3", resp.Header().Get(remoteWriteSamplesWrittenHeader))
+		require.Equal(t, "1", resp.Header().Get(remoteWriteHistogramsWrittenHeader))
+		require.Equal(t, "1", resp.Header().Get(remoteWriteExemplarsWrittenHeader))
+
+		require.Len(t, pushed.Timeseries, 2)
+		require.Equal(t, []mimirpb.LabelAdapter{{Name: "__name__", Value: "foo"}, {Name: "job", Value: "test"}}, pushed.Timeseries[0].Labels)
+		require.Equal(t, []mimirpb.Sample{{Value: 1, TimestampMs: 1000}, {Value: 2, TimestampMs: 2000}}, pushed.Timeseries[0].Samples)
+		require.Equal(t, []mimirpb.Histogram{promToMimirHistogram(&histogram)}, pushed.Timeseries[0].Histograms)
+		require.Equal(t, []mimirpb.Exemplar{{Labels: []mimirpb.LabelAdapter{{Name: "trace_id", Value: "abc"}}, Value: 1, TimestampMs: 1000}}, pushed.Timeseries[0].Exemplars)
+		require.Equal(t, []mimirpb.LabelAdapter{{Name: "__name__", Value: "bar"}, {Name: "job", Value: "test"}}, pushed.Timeseries[1].Labels)
+		require.Equal(t, []*mimirpb.MetricMetadata{{Type: mimirpb.COUNTER, MetricFamilyName: "foo", Help: "Foo help.", Unit: "seconds"}}, pushed.Metadata)
+	})
+
+	t.Run("written stats are returned if the push failed", func(t *testing.T) {
+		for _, code := range []int{http.StatusBadRequest, http.StatusInternalServerError} {
+			req := createRequest(t, data)
+			req.Header.Set("Content-Type", RemoteWriteV2ContentType)
+
+			resp := httptest.NewRecorder()
+			handler := Handler(100000, nil, nil, false, overrides, RetryConfig{}, func(ctx context.Context, pushReq *Request) error {
+				defer pushReq.CleanUp()
+				_, err := pushReq.WriteRequest()
+				require.NoError(t, err)
+
+				// Emulate the distributor partially writing the request to the storage.
+				writtenStatsFromContext(ctx).add(remoteWriteStats{samples: 2})
+				return httpgrpc.Errorf(code, "failed to write series")
+			}, nil, log.NewNopLogger())
+			handler.ServeHTTP(resp, req)
+
+			require.Equal(t, code, resp.Code)
+			require.Equal(t, "2", resp.Header().Get(remoteWriteSamplesWrittenHeader))
+			require.Equal(t, "0", resp.Header().Get(remoteWriteHistogramsWrittenHeader))
+			require.Equal(t, "0", resp.Header().Get(remoteWriteExemplarsWrittenHeader))
+		}
+	})
+
+	t.Run("written stats are returned if the request is invalid", func(t *testing.T) {
+		req := createRequest(t, []byte("invalid"))
+		req.Header.Set("Content-Type", RemoteWriteV2ContentType)
+
+		resp := httptest.NewRecorder()
+		handler := Handler(100000, nil, nil, false, overrides, RetryConfig{}, readBodyPushFunc(t), nil, log.NewNopLogger())
+		handler.ServeHTTP(resp, req)
+
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+		require.Equal(t, "0", resp.Header().Get(remoteWriteSamplesWrittenHeader))
+	})
+
+	t.Run("native histograms with custom buckets are rejected", func(t *testing.T) {
+		reqV2 := mimirpb.FromPrometheusTimeseriesToWriteRequestV2([]prompb.TimeSeries{{
+			Labels:     []prompb.Label{{Name: "__name__", Value: "foo"}},
+			Histograms: []prompb.Histogram{histogram},
+		}}, nil)
+		reqV2.Timeseries[0].Histograms[0].Schema = -53
+		data, err := reqV2.Marshal()
+		require.NoError(t, err)
+
+		req := createRequest(t, data)
+		req.Header.Set("Content-Type", RemoteWriteV2ContentType)
+
+		resp := httptest.NewRecorder()
+		handler := Handler(100000, nil, nil, false, overrides, RetryConfig{}, readBodyPushFunc(t), nil, log.NewNopLogger())
+		handler.ServeHTTP(resp, req)
+
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+		require.Contains(t, resp.Body.String(), "custom buckets")
+		require.Equal(t, "0", resp.Header().Get(remoteWriteHistogramsWrittenHeader))
+	})
+
+	t.Run("written stats are not returned for remote-write 1.0 requests", func(t *testing.T) {
+		req := createRequest(t, createPrometheusRemoteWriteProtobuf(t))
+		req.Header.Set("Content-Type", "application/x-protobuf;proto=prometheus.WriteRequest")
+
+		resp := httptest.NewRecorder()
+		handler := Handler(100000, nil, nil, false, overrides, RetryConfig{}, verifyWritePushFunc(t, mimirpb.API), nil, log.NewNopLogger())
+		handler.ServeHTTP(resp, req)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Empty(t, resp.Header().Get(remoteWriteSamplesWrittenHeader))
+	})
+
+	t.Run("unsupported protobuf message is rejected", func(t *testing.T) {
+		req := createRequest(t, data)
+		req.Header.Set("Content-Type", "application/x-protobuf;proto=io.prometheus.write.v3.Request")
+
+		resp := httptest.NewRecorder()
+		handler := Handler(100000, nil, nil, false, overrides, RetryConfig{}, readBodyPushFunc(t), nil, log.NewNopLogger())
+		handler.ServeHTTP(resp, req)
+
+		require.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+	})
+}
+
+func TestHandler_remoteWriteV2_ShouldReturnTheStatsOfTheSamplesWrittenByTheDistributor(t *testing.T) {
+	now := time.Now()
+
+	reqV2 := mimirpb.FromPrometheusTimeseriesToWriteRequestV2([]prompb.TimeSeries{
+		{
+			Labels:    []prompb.Label{{Name: "__name__", Value: "foo"}, {Name: "job", Value: "keep"}},
+			Samples:   []prompb.Sample{{Value: 1, Timestamp: now.Add(-time.Minute).UnixMilli()}, {Value: 2, Timestamp: now.UnixMilli()}},
+			Exemplars: []prompb.Exemplar{{Labels: []prompb.Label{{Name: "trace_id", Value: "abc"}}, Value: 1, Timestamp: now.UnixMilli()}},
+		},
+		{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "foo"}, {Name: "job", Value: "drop"}},
+			Samples: []prompb.Sample{{Value: 3, Timestamp: now.UnixMilli()}},
+		},
+	}, nil)
+	reqV2.Timeseries[0].CreatedTimestamp = now.Add(-time.Hour).UnixMilli()
+	data, err := reqV2.Marshal()
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		createdTimestampZeroIngestion bool
+		expectedSamples               []mimirpb.Sample
+	}{
+		"created timestamp zero ingestion disabled": {
+			expectedSamples: []mimirpb.Sample{{Value: 1, TimestampMs: now.Add(-time.Minute).UnixMilli()}, {Value: 2, TimestampMs: now.UnixMilli()}},
+		},
+		"created timestamp zero ingestion enabled": {
+			createdTimestampZeroIngestion: true,
+			expectedSamples:               []mimirpb.Sample{{Value: 0, TimestampMs: now.Add(-time.Hour).UnixMilli()}, {Value: 1, TimestampMs: now.Add(-time.Minute).UnixMilli()}, {Value: 2, TimestampMs: now.UnixMilli()}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// The series of the "drop" job are dropped by relabeling, while the exemplars are dropped
+			// because they're disabled by default.
+			limits := validation.Limits{}
+			flagext.DefaultValues(&limits)
+			limits.RemoteWriteCreatedTimestampZeroIngestionEnabled = tc.createdTimestampZeroIngestion
+			limits.MetricRelabelConfigs = []*relabel.Config{{
+				SourceLabels: []model.LabelName{"job"},
+				Action:       relabel.Drop,
+				Regex:        relabel.MustNewRegexp("drop"),
+			}}
+			overrides, err := validation.NewOverrides(limits, nil)
+			require.NoError(t, err)
+
+			ds, ingesters, _, _ := prepare(t, prepConfig{
+				numIngesters:      1,
+				happyIngesters:    1,
+				replicationFactor: 1,
+				numDistributors:   1,
+				limits:            &limits,
+			})
+
+			req := createRequest(t, data)
+			req.Header.Set("Content-Type", RemoteWriteV2ContentType)
+			req.Header.Set("X-Prometheus-Remote-Write-Version", "2.0.0")
+
+			resp := httptest.NewRecorder()
+			handler := Handler(100000, nil, nil, false, overrides, RetryConfig{}, ds[0].PushWithMiddlewares, nil, log.NewNopLogger())
+			handler.ServeHTTP(resp, req)
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			require.Equal(t, strconv.Itoa(len(tc.expectedSamples)), resp.Header().Get(remoteWriteSamplesWrittenHeader))
+			require.Equal(t, "0", resp.Header().Get(remoteWriteHistogramsWrittenHeader))
+			require.Equal(t, "0", resp.Header().Get(remoteWriteExemplarsWrittenHeader))
+
+			series := ingesters[0].series()
+			require.Len(t, series, 1)
+			for _, s := range series {
+				require.Equal(t, tc.expectedSamples, s.Samples)
+			}
+		})
+	}
+}
+
+func TestRemoteWriteProtoMessage(t *testing.T) {
+	for contentType, expected := range map[string]string{
+		"":                         remoteWriteV1ProtoMessage,
+		"application/x-protobuf":   remoteWriteV1ProtoMessage,
+		"application/octet-stream": remoteWriteV1ProtoMessage,
+		"application/x-protobuf;proto=prometheus.WriteRequest":         remoteWriteV1ProtoMessage,
+		"application/x-protobuf;proto=io.prometheus.write.v2.Request":  remoteWriteV2ProtoMessage,
+		"application/x-protobuf; proto=io.prometheus.write.v2.Request": remoteWriteV2ProtoMessage,
+	} {
+		actual, err := remoteWriteProtoMessage(contentType)
+		require.NoError(t, err, contentType)
+		require.Equal(t, expected, actual, contentType)
+	}
+
+	_, err := remoteWriteProtoMessage("application/x-protobuf;proto=unknown")
+	require.Error(t, err)
+}
+
 func TestOTelMetricsToMetadata(t *testing.T) {
 	otelMetrics := pmetric.NewMetrics()
 	rs := otelMetrics.ResourceMetrics().AppendEmpty()
@@ -1082,3 +1312,35 @@ type otlpLimitsMock struct{}
 func (o otlpLimitsMock) OTelMetricSuffixesEnabled(_ string) bool {
 	return false
 }
+
+func (o otlpLimitsMock) OTelConvertDeltaToCumulative(_ string) bool {
+	return false
+}
+
+func (o otlpLimitsMock) OTelDeltaMaxTrackedSeries(_ string) int {
+	return 0
+}
+
+func (o otlpLimitsMock) PromoteOTelResourceAttributes(_ string) []string {
+	return nil
+}
+
+func (o otlpLimitsMock) OTelDisableTargetInfo(_ string) bool {
+	return false
+}
+
+func (o otlpLimitsMock) OTelPromoteScopeMetadata(_ string) bool {
+	return false
+}
+
+func (o otlpLimitsMock) MaxLabelNamesPerSeries(_ string) int {
+	return 0
+}
+
+func (o otlpLimitsMock) MaxLabelNameLength(_ string) int {
+	return 0
+}
+
+func (o otlpLimitsMock) MaxLabelValueLength(_ string) int {
+	return 0
+}
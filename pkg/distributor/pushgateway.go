@@ -0,0 +1,265 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	// pushgatewayMetricsPath precedes the grouping key in the request path, like in the Pushgateway API.
+	pushgatewayMetricsPath = "/metrics/"
+
+	// pushgatewayBase64Suffix is the suffix of the grouping key label names whose value is base64 encoded.
+	pushgatewayBase64Suffix = "@base64"
+)
+
+// PushgatewayHandler is a http.Handler which accepts metrics in the Prometheus text, OpenMetrics text
+// or delimited protobuf exposition formats, optionally compressed with gzip, like the Prometheus Pushgateway.
+// The grouping key is read from the request path, in the ".../metrics/job/<job>[/<label>/<value>...]" format,
+// and its labels are added to all the pushed series, overriding the ones with the same name.
+func PushgatewayHandler(
+	maxRecvMsgSize int,
+	requestBufferPool util.Pool,
+	sourceIPs *middleware.SourceIPExtractor,
+	limits *validation.Overrides,
+	retryCfg RetryConfig,
+	push PushFunc,
+	pushMetrics *PushMetrics,
+	logger log.Logger,
+) http.Handler {
+	return handler(maxRecvMsgSize, requestBufferPool, sourceIPs, false, limits, retryCfg, push, logger, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers, req *mimirpb.PreallocWriteRequest, logger log.Logger) error {
+		_, groupingPath, ok := strings.Cut(r.URL.Path, pushgatewayMetricsPath)
+		if !ok {
+			return fmt.Errorf("missing grouping key in path %q", r.URL.Path)
+		}
+		groupingKey, err := parsePushgatewayGroupingKey(groupingPath)
+		if err != nil {
+			return err
+		}
+
+		if r.ContentLength > int64(maxRecvMsgSize) {
+			return httpgrpc.Errorf(http.StatusRequestEntityTooLarge, distributorMaxWriteMessageSizeErr{
+				actual: int(r.ContentLength),
+				limit:  maxRecvMsgSize,
+			}.Error())
+		}
+
+		spanLogger, ctx := spanlogger.NewWithLogger(ctx, logger, "Distributor.PushgatewayHandler.decodeAndConvert")
+		defer spanLogger.Span.Finish()
+
+		body, err := readGzipBody(r, maxRecvMsgSize, buffers)
+		if err != nil {
+			return err
+		}
+
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return err
+		}
+		pushMetrics.IncPushgatewayRequest(tenantID)
+		pushMetrics.ObserveUncompressedBodySize(tenantID, float64(len(body)))
+
+		req.Timeseries, req.Metadata, err = expositionToTimeseries(body, r.Header.Get("Content-Type"), groupingKey, time.Now())
+		if err != nil {
+			return err
+		}
+
+		level.Debug(spanLogger).Log("msg", "exposition format to Prometheus conversion complete", "series_count", len(req.Timeseries), "metadata_count", len(req.Metadata))
+		return nil
+	})
+}
+
+// parsePushgatewayGroupingKey parses the "job/<job>[/<label>/<value>...]" grouping key. Label values
+// can be encoded with base64 URL encoding, by adding the "@base64" suffix to the label name.
+func parsePushgatewayGroupingKey(path string) (labels.Labels, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts)%2 != 0 {
+		return labels.EmptyLabels(), fmt.Errorf("invalid grouping key %q: odd number of path components", path)
+	}
+
+	b := labels.NewScratchBuilder(len(parts) / 2)
+	seen := make(map[string]struct{}, len(parts)/2)
+	for i := 0; i < len(parts); i += 2 {
+		name, value := parts[i], parts[i+1]
+		if strings.HasSuffix(name, pushgatewayBase64Suffix) {
+			name = strings.TrimSuffix(name, pushgatewayBase64Suffix)
+			decoded, err := decodePushgatewayBase64(value)
+			if err != nil {
+				return labels.EmptyLabels(), fmt.Errorf("invalid base64 value of grouping key label %q: %w", name, err)
+			}
+			value = decoded
+		}
+
+		if i == 0 && name != model.JobLabel {
+			return labels.EmptyLabels(), fmt.Errorf("invalid grouping key %q: it must start with the %q label", path, model.JobLabel)
+		}
+		if !model.LabelName(name).IsValid() || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+			return labels.EmptyLabels(), fmt.Errorf("invalid grouping key label name %q", name)
+		}
+		if _, ok := seen[name]; ok {
+			return labels.EmptyLabels(), fmt.Errorf("duplicate grouping key label name %q", name)
+		}
+		seen[name] = struct{}{}
+		b.Add(name, value)
+	}
+
+	b.Sort()
+	groupingKey := b.Labels()
+	if groupingKey.Get(model.JobLabel) == "" {
+		return labels.EmptyLabels(), errors.New("the job name in the grouping key must not be empty")
+	}
+	return groupingKey, nil
+}
+
+// decodePushgatewayBase64 decodes a base64 URL encoded label value, with or without padding.
+// A single "=" is the empty value.
+func decodePushgatewayBase64(value string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// expositionToTimeseries converts the metrics in the exposition format of the content type into series and metadata.
+// The grouping key labels are added to all the series. Samples without a timestamp are timestamped with now.
+func expositionToTimeseries(data []byte, contentType string, groupingKey labels.Labels, now time.Time) ([]mimirpb.PreallocTimeseries, []*mimirpb.MetricMetadata, error) {
+	parser, err := textparse.New(data, contentType, false, labels.NewSymbolTable())
+	if err != nil {
+		return nil, nil, httpgrpc.Errorf(http.StatusUnsupportedMediaType, "invalid content type %q: %s", contentType, err)
+	}
+
+	var (
+		timeseries  = mimirpb.PreallocTimeseriesSliceFromPool()
+		seriesIdx   = map[string]int{}
+		metadata    []*mimirpb.MetricMetadata
+		metadataIdx = map[string]int{}
+		lbls        labels.Labels
+		lb          = labels.NewBuilder(labels.EmptyLabels())
+		ex          exemplar.Exemplar
+	)
+
+	familyMetadata := func(name []byte) *mimirpb.MetricMetadata {
+		if idx, ok := metadataIdx[string(name)]; ok {
+			return metadata[idx]
+		}
+		m := &mimirpb.MetricMetadata{MetricFamilyName: string(name), Type: mimirpb.UNKNOWN}
+		metadataIdx[m.MetricFamilyName] = len(metadata)
+		metadata = append(metadata, m)
+		return m
+	}
+
+	for {
+		entry, err := parser.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			mimirpb.ReuseSlice(timeseries)
+			return nil, nil, fmt.Errorf("unable to parse exposition format: %w", err)
+		}
+
+		var (
+			timestampPtr *int64
+			sample       *mimirpb.Sample
+			histogram    *mimirpb.Histogram
+		)
+		switch entry {
+		case textparse.EntryType:
+			name, typ := parser.Type()
+			familyMetadata(name).Type = mimirpb.MetricTypeToMetricMetadataMetricType(typ)
+			continue
+		case textparse.EntryHelp:
+			name, help := parser.Help()
+			familyMetadata(name).Help = string(help)
+			continue
+		case textparse.EntryUnit:
+			name, unit := parser.Unit()
+			familyMetadata(name).Unit = string(unit)
+			continue
+		case textparse.EntrySeries:
+			var value float64
+			_, timestampPtr, value = parser.Series()
+			sample = &mimirpb.Sample{Value: value}
+		case textparse.EntryHistogram:
+			var h mimirpb.Histogram
+			_, ts, intHistogram, floatHistogram := parser.Histogram()
+			if intHistogram != nil {
+				h = mimirpb.FromHistogramToHistogramProto(0, intHistogram)
+			} else {
+				h = mimirpb.FromFloatHistogramToHistogramProto(0, floatHistogram)
+			}
+			timestampPtr, histogram = ts, &h
+		default:
+			continue
+		}
+
+		timestampMs := now.UnixMilli()
+		if timestampPtr != nil {
+			timestampMs = *timestampPtr
+		}
+
+		parser.Metric(&lbls)
+		lb.Reset(lbls)
+		groupingKey.Range(func(l labels.Label) {
+			lb.Set(l.Name, l.Value)
+		})
+		lbls = lb.Labels()
+
+		key := lbls.String()
+		idx, ok := seriesIdx[key]
+		if !ok {
+			ts := mimirpb.TimeseriesFromPool()
+			ts.Labels = mimirpb.FromLabelsToLabelAdapters(lbls)
+			idx = len(timeseries)
+			seriesIdx[key] = idx
+			timeseries = append(timeseries, mimirpb.PreallocTimeseries{TimeSeries: ts})
+		}
+		ts := timeseries[idx].TimeSeries
+
+		if sample != nil {
+			sample.TimestampMs = timestampMs
+			ts.Samples = append(ts.Samples, *sample)
+		} else {
+			histogram.Timestamp = timestampMs
+			ts.Histograms = append(ts.Histograms, *histogram)
+		}
+
+		for parser.Exemplar(&ex) {
+			if !ex.HasTs {
+				ex.Ts = timestampMs
+			}
+			ts.Exemplars = append(ts.Exemplars, mimirpb.Exemplar{
+				Labels:      mimirpb.FromLabelsToLabelAdapters(ex.Labels),
+				Value:       ex.Value,
+				TimestampMs: ex.Ts,
+			})
+			ex = exemplar.Exemplar{}
+		}
+	}
+
+	return timeseries, metadata, nil
+}
@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+func TestParsePushgatewayGroupingKey(t *testing.T) {
+	tests := map[string]struct {
+		path        string
+		expected    labels.Labels
+		expectedErr string
+	}{
+		"job only": {
+			path:     "job/batch",
+			expected: labels.FromStrings("job", "batch"),
+		},
+		"job and labels": {
+			path:     "job/batch/zone/eu/instance/host-1/",
+			expected: labels.FromStrings("instance", "host-1", "job", "batch", "zone", "eu"),
+		},
+		"base64 encoded values": {
+			path:     "job@base64/YmF0Y2g/path@base64/L3Zhci90bXA=/empty@base64/=",
+			expected: labels.FromStrings("empty", "", "job", "batch", "path", "/var/tmp"),
+		},
+		"missing job": {
+			path:        "instance/host-1",
+			expectedErr: `it must start with the "job" label`,
+		},
+		"empty job": {
+			path:        "job@base64/=",
+			expectedErr: "the job name in the grouping key must not be empty",
+		},
+		"odd number of components": {
+			path:        "job/batch/zone",
+			expectedErr: "odd number of path components",
+		},
+		"invalid label name": {
+			path:        "job/batch/zone-name/eu",
+			expectedErr: `invalid grouping key label name "zone-name"`,
+		},
+		"reserved label name": {
+			path:        "job/batch/__name__/foo",
+			expectedErr: `invalid grouping key label name "__name__"`,
+		},
+		"duplicate label name": {
+			path:        "job/batch/zone/eu/zone@base64/dXM",
+			expectedErr: `duplicate grouping key label name "zone"`,
+		},
+		"invalid base64 value": {
+			path:        "job/batch/zone@base64/!!",
+			expectedErr: `invalid base64 value of grouping key label "zone"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parsePushgatewayGroupingKey(tc.path)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestExpositionToTimeseries(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	groupingKey := labels.FromStrings("instance", "", "job", "batch")
+
+	t.Run("Prometheus text format", func(t *testing.T) {
+		const body = `# HELP jobs_processed_total Processed jobs.
+# TYPE jobs_processed_total counter
+jobs_processed_total{job="other",queue="a"} 10
+jobs_processed_total{queue="b"} 20 1699999990000
+# TYPE last_run_seconds gauge
+last_run_seconds 1.5
+`
+		series, metadata, err := expositionToTimeseries([]byte(body), "text/plain; version=0.0.4", groupingKey, now)
+		require.NoError(t, err)
+
+		// The grouping key labels override the series ones, and empty labels are removed.
+		require.Len(t, series, 3)
+		assert.Equal(t, []mimirpb.LabelAdapter{{Name: "__name__", Value: "jobs_processed_total"}, {Name: "job", Value: "batch"}, {Name: "queue", Value: "a"}}, series[0].Labels)
+		assert.Equal(t, []mimirpb.Sample{{Value: 10, TimestampMs: now.UnixMilli()}}, series[0].Samples)
+		assert.Equal(t, []mimirpb.LabelAdapter{{Name: "__name__", Value: "jobs_processed_total"}, {Name: "job", Value: "batch"}, {Name: "queue", Value: "b"}}, series[1].Labels)
+		assert.Equal(t, []mimirpb.Sample{{Value: 20, TimestampMs: 1699999990000}}, series[1].Samples)
+		assert.Equal(t, []mimirpb.LabelAdapter{{Name: "__name__", Value: "last_run_seconds"}, {Name: "job", Value: "batch"}}, series[2].Labels)
+
+		require.Equal(t, []*mimirpb.MetricMetadata{
+			{MetricFamilyName: "jobs_processed_total", Type: mimirpb.COUNTER, Help: "Processed jobs."},
+			{MetricFamilyName: "last_run_seconds", Type: mimirpb.GAUGE},
+		}, metadata)
+	})
+
+	t.Run("OpenMetrics text format", func(t *testing.T) {
+		const body = `# TYPE request_duration_seconds histogram
+# UNIT request_duration_seconds seconds
+request_duration_seconds_bucket{le="1"} 3 # {trace_id="abc"} 0.5 1699999999.5
+request_duration_seconds_bucket{le="+Inf"} 4
+request_duration_seconds_count 4
+request_duration_seconds_sum 5.5
+# EOF
+`
+		series, metadata, err := expositionToTimeseries([]byte(body), "application/openmetrics-text; version=1.0.0", groupingKey, now)
+		require.NoError(t, err)
+
+		require.Len(t, series, 4)
+		assert.Equal(t, []mimirpb.LabelAdapter{{Name: "__name__", Value: "request_duration_seconds_bucket"}, {Name: "job", Value: "batch"}, {Name: "le", Value: "1"}}, series[0].Labels)
+		assert.Equal(t, []mimirpb.Exemplar{{Labels: []mimirpb.LabelAdapter{{Name: "trace_id", Value: "abc"}}, Value: 0.5, TimestampMs: 1699999999500}}, series[0].Exemplars)
+		assert.Empty(t, series[1].Exemplars)
+
+		require.Equal(t, []*mimirpb.MetricMetadata{
+			{MetricFamilyName: "request_duration_seconds", Type: mimirpb.HISTOGRAM, Unit: "seconds"},
+		}, metadata)
+	})
+
+	t.Run("delimited protobuf format with native histograms", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		h := prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:                        "task_duration_seconds",
+			Help:                        "Task duration.",
+			NativeHistogramBucketFactor: 1.1,
+		})
+		reg.MustRegister(h)
+		h.Observe(1)
+		h.Observe(2)
+
+		families, err := reg.Gather()
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		format := expfmt.NewFormat(expfmt.TypeProtoDelim)
+		enc := expfmt.NewEncoder(&buf, format)
+		for _, mf := range families {
+			require.NoError(t, enc.Encode(mf))
+		}
+
+		series, metadata, err := expositionToTimeseries(buf.Bytes(), string(format), groupingKey, now)
+		require.NoError(t, err)
+
+		require.Len(t, series, 1)
+		assert.Equal(t, []mimirpb.LabelAdapter{{Name: "__name__", Value: "task_duration_seconds"}, {Name: "job", Value: "batch"}}, series[0].Labels)
+		require.Len(t, series[0].Histograms, 1)
+		assert.Equal(t, now.UnixMilli(), series[0].Histograms[0].Timestamp)
+		assert.Equal(t, uint64(2), series[0].Histograms[0].GetCountInt())
+		assert.Equal(t, 3.0, series[0].Histograms[0].Sum)
+
+		require.Equal(t, []*mimirpb.MetricMetadata{
+			{MetricFamilyName: "task_duration_seconds", Type: mimirpb.HISTOGRAM, Help: "Task duration."},
+		}, metadata)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		_, _, err := expositionToTimeseries([]byte("metric{ 1\n"), "text/plain", groupingKey, now)
+		require.ErrorContains(t, err, "unable to parse exposition format")
+	})
+}
+
+func TestPushgatewayHandler(t *testing.T) {
+	const body = "# TYPE jobs_processed_total counter\njobs_processed_total 10 1700000000000\n"
+
+	gzipped := func(data string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		return buf.Bytes()
+	}
+
+	tests := map[string]struct {
+		method          string
+		path            string
+		body            []byte
+		contentEncoding string
+		contentType     string
+		expectedCode    int
+		expectedLabels  []mimirpb.LabelAdapter
+	}{
+		"POST request": {
+			method:         http.MethodPost,
+			path:           "/api/v1/push/pushgateway/metrics/job/batch",
+			body:           []byte(body),
+			expectedCode:   http.StatusOK,
+			expectedLabels: []mimirpb.LabelAdapter{{Name: "__name__", Value: "jobs_processed_total"}, {Name: "job", Value: "batch"}},
+		},
+		"gzip compressed PUT request with grouping labels": {
+			method:          http.MethodPut,
+			path:            "/api/v1/push/pushgateway/metrics/job/batch/instance/host-1",
+			body:            gzipped(body),
+			contentEncoding: "gzip",
+			expectedCode:    http.StatusOK,
+			expectedLabels:  []mimirpb.LabelAdapter{{Name: "__name__", Value: "jobs_processed_total"}, {Name: "instance", Value: "host-1"}, {Name: "job", Value: "batch"}},
+		},
+		"invalid grouping key": {
+			method:       http.MethodPost,
+			path:         "/api/v1/push/pushgateway/metrics/instance/host-1",
+			body:         []byte(body),
+			expectedCode: http.StatusBadRequest,
+		},
+		"invalid content type": {
+			method:       http.MethodPost,
+			path:         "/api/v1/push/pushgateway/metrics/job/batch",
+			body:         []byte(body),
+			contentType:  "text/plain; version",
+			expectedCode: http.StatusUnsupportedMediaType,
+		},
+		"unsupported compression": {
+			method:          http.MethodPost,
+			path:            "/api/v1/push/pushgateway/metrics/job/batch",
+			body:            []byte(body),
+			contentEncoding: "snappy",
+			expectedCode:    http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, bytes.NewReader(tc.body))
+			if tc.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", tc.contentEncoding)
+			}
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			req = req.WithContext(user.InjectOrgID(context.Background(), "test"))
+
+			var pushed *mimirpb.WriteRequest
+			reg := prometheus.NewPedanticRegistry()
+			handler := PushgatewayHandler(100000, nil, nil, nil, RetryConfig{}, func(_ context.Context, pushReq *Request) error {
+				request, err := pushReq.WriteRequest()
+				if err != nil {
+					return err
+				}
+				t.Cleanup(pushReq.CleanUp)
+				pushed = request
+				return nil
+			}, newPushMetrics(reg), log.NewNopLogger())
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code, resp.Body.String())
+
+			if tc.expectedLabels != nil {
+				require.Len(t, pushed.Timeseries, 1)
+				require.Equal(t, tc.expectedLabels, pushed.Timeseries[0].Labels)
+				require.Equal(t, []mimirpb.Sample{{Value: 10, TimestampMs: 1700000000000}}, pushed.Timeseries[0].Samples)
+				require.Equal(t, []*mimirpb.MetricMetadata{{MetricFamilyName: "jobs_processed_total", Type: mimirpb.COUNTER}}, pushed.Metadata)
+
+				require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+					# HELP cortex_distributor_pushgateway_requests_total The total number of Pushgateway compatible exposition format requests that have come in to the distributor.
+					# TYPE cortex_distributor_pushgateway_requests_total counter
+					cortex_distributor_pushgateway_requests_total{user="test"} 1
+				`), "cortex_distributor_pushgateway_requests_total"))
+			}
+		})
+	}
+}
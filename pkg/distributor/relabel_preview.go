@@ -0,0 +1,323 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	_ "embed" // Used to embed html template
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+//go:embed relabel_preview.gohtml
+var relabelPreviewPageHTML string
+var relabelPreviewPageTemplate = template.Must(template.New("relabel-preview").Parse(relabelPreviewPageHTML))
+
+// relabelPreviewMaxExamples is the number of example series kept per tenant and outcome.
+const relabelPreviewMaxExamples = 10
+
+// Outcomes of the shadow relabeling of a series, compared to the active relabeling.
+const (
+	relabelPreviewDropped  = "dropped"  // The series is kept by the active relabeling, and dropped by the shadow one.
+	relabelPreviewModified = "modified" // The series is kept by both relabelings, with different labels.
+	relabelPreviewCreated  = "created"  // The series is dropped by the active relabeling, and kept by the shadow one.
+)
+
+var relabelPreviewOutcomes = []string{relabelPreviewDropped, relabelPreviewModified, relabelPreviewCreated}
+
+// relabelPreviewConfig is the active and shadow relabeling configuration of a tenant.
+type relabelPreviewConfig struct {
+	activeRelabelConfigs []*relabel.Config
+	activeDropLabels     []string
+	shadowRelabelConfigs []*relabel.Config
+	shadowDropLabels     []string
+}
+
+// sameAs returns whether c and other are backed by the same slices. The limits return the same slices until
+// the runtime configuration is reloaded, so this is a cheap way to detect that the configuration may have changed.
+func (c relabelPreviewConfig) sameAs(other relabelPreviewConfig) bool {
+	return sameSlice(c.activeRelabelConfigs, other.activeRelabelConfigs) &&
+		sameSlice(c.activeDropLabels, other.activeDropLabels) &&
+		sameSlice(c.shadowRelabelConfigs, other.shadowRelabelConfigs) &&
+		sameSlice(c.shadowDropLabels, other.shadowDropLabels)
+}
+
+// hash returns a hash of the configuration content.
+func (c relabelPreviewConfig) hash() uint64 {
+	out, err := yaml.Marshal(map[string]interface{}{
+		"active_relabel_configs": c.activeRelabelConfigs,
+		"active_drop_labels":     c.activeDropLabels,
+		"shadow_relabel_configs": c.shadowRelabelConfigs,
+		"shadow_drop_labels":     c.shadowDropLabels,
+	})
+	if err != nil {
+		// Should never happen, because the configuration has been unmarshalled from YAML.
+		return 0
+	}
+	return xxhash.Sum64(out)
+}
+
+func sameSlice[T any](a, b []T) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+type relabelPreviewExample struct {
+	Outcome string `json:"outcome"`
+	Before  string `json:"before"`
+	Active  string `json:"active,omitempty"` // Empty if the active relabeling drops the series.
+	Shadow  string `json:"shadow,omitempty"` // Empty if the shadow relabeling drops the series.
+}
+
+// tenantRelabelPreview holds the series counts and examples collected for a tenant since its relabeling
+// configuration last changed.
+type tenantRelabelPreview struct {
+	mtx        sync.Mutex
+	config     relabelPreviewConfig
+	configHash uint64
+	since      time.Time
+	counts     map[string]int
+	examples   map[string][]relabelPreviewExample
+}
+
+// reset clears the series counts and examples, and starts collecting them for the given configuration.
+func (t *tenantRelabelPreview) reset(config relabelPreviewConfig, configHash uint64) {
+	t.config = config
+	t.configHash = configHash
+	t.since = time.Now()
+	t.counts = make(map[string]int, len(relabelPreviewOutcomes))
+	t.examples = make(map[string][]relabelPreviewExample, len(relabelPreviewOutcomes))
+}
+
+// relabelPreview evaluates the tenants' shadow metric relabel configs and shadow drop labels alongside the active
+// ones, and tracks the series the shadow relabeling would drop, modify or newly create.
+type relabelPreview struct {
+	limits *validation.Overrides
+
+	tenantsMtx sync.RWMutex
+	tenants    map[string]*tenantRelabelPreview
+
+	series *prometheus.CounterVec
+}
+
+func newRelabelPreview(limits *validation.Overrides, reg prometheus.Registerer) *relabelPreview {
+	return &relabelPreview{
+		limits:  limits,
+		tenants: map[string]*tenantRelabelPreview{},
+		series: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_shadow_relabel_series_total",
+			Help: "The total number of received series the shadow relabeling would drop, modify or newly create compared to the active relabeling, since the tenant's relabeling configuration last changed.",
+		}, []string{"user", "outcome"}),
+	}
+}
+
+// relabelPreviewBatch evaluates the shadow relabeling of the series of a single push request.
+type relabelPreviewBatch struct {
+	userID string
+	config relabelPreviewConfig
+	lb     *labels.Builder
+
+	counts   map[string]int
+	examples []relabelPreviewExample
+}
+
+// newBatch returns a batch evaluating the tenant's current shadow relabeling. The active relabeling is
+// only taken into account if relabelingEnabled is true.
+func (p *relabelPreview) newBatch(userID string, relabelingEnabled bool) *relabelPreviewBatch {
+	b := &relabelPreviewBatch{
+		userID: userID,
+		config: relabelPreviewConfig{
+			shadowRelabelConfigs: p.limits.ShadowMetricRelabelConfigs(userID),
+			shadowDropLabels:     p.limits.ShadowDropLabels(userID),
+		},
+		lb:     labels.NewBuilder(labels.EmptyLabels()),
+		counts: make(map[string]int, len(relabelPreviewOutcomes)),
+	}
+	if relabelingEnabled {
+		b.config.activeRelabelConfigs = p.limits.MetricRelabelConfigs(userID)
+		b.config.activeDropLabels = p.limits.DropLabels(userID)
+	}
+	return b
+}
+
+// observe applies the shadow relabeling to the series labels before the active relabeling, and compares the result with
+// the labels after the active relabeling. active is ignored if activeKeep is false.
+func (b *relabelPreviewBatch) observe(before labels.Labels, activeKeep bool, active []mimirpb.LabelAdapter) {
+	b.lb.Reset(before)
+	b.lb.Set(metaLabelTenantID, b.userID)
+	shadowKeep := relabel.ProcessBuilder(b.lb, b.config.shadowRelabelConfigs...)
+	b.lb.Del(metaLabelTenantID)
+	b.lb.Del(b.config.shadowDropLabels...)
+	shadow := b.lb.Labels()
+	shadowKeep = shadowKeep && !shadow.IsEmpty()
+
+	var outcome string
+	switch {
+	case activeKeep && !shadowKeep:
+		outcome = relabelPreviewDropped
+	case !activeKeep && shadowKeep:
+		outcome = relabelPreviewCreated
+	case activeKeep && shadowKeep && !sameLabels(active, shadow):
+		outcome = relabelPreviewModified
+	default:
+		return
+	}
+
+	b.counts[outcome]++
+	if b.counts[outcome] > relabelPreviewMaxExamples {
+		return
+	}
+	example := relabelPreviewExample{Outcome: outcome, Before: before.String()}
+	if activeKeep {
+		example.Active = mimirpb.FromLabelAdaptersToLabels(active).String()
+	}
+	if shadowKeep {
+		example.Shadow = shadow.String()
+	}
+	b.examples = append(b.examples, example)
+}
+
+// sameLabels returns whether the label adapters hold the same label set as lbls, regardless of their order.
+// Labels with an empty value are ignored, because they're removed before ingestion.
+func sameLabels(adapters []mimirpb.LabelAdapter, lbls labels.Labels) bool {
+	count := 0
+	for _, l := range adapters {
+		if l.Value == "" {
+			continue
+		}
+		if lbls.Get(l.Name) != l.Value {
+			return false
+		}
+		count++
+	}
+	return count == lbls.Len()
+}
+
+// commit adds the series counts and examples of the batch to the tenant's ones. They're reset first if the tenant's
+// relabeling configuration changed.
+func (p *relabelPreview) commit(b *relabelPreviewBatch) {
+	t := p.getOrCreateTenant(b)
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	// The configuration content is only compared when the runtime configuration has been reloaded,
+	// in order to keep the hashing out of the hot path.
+	if !t.config.sameAs(b.config) {
+		if configHash := b.config.hash(); configHash != t.configHash {
+			p.series.DeletePartialMatch(prometheus.Labels{"user": b.userID})
+			t.reset(b.config, configHash)
+		} else {
+			t.config = b.config
+		}
+	}
+
+	for outcome, count := range b.counts {
+		t.counts[outcome] += count
+		p.series.WithLabelValues(b.userID, outcome).Add(float64(count))
+	}
+	for _, example := range b.examples {
+		if len(t.examples[example.Outcome]) < relabelPreviewMaxExamples {
+			t.examples[example.Outcome] = append(t.examples[example.Outcome], example)
+		}
+	}
+}
+
+// getOrCreateTenant returns the tenant of the batch, creating it if it doesn't exist yet.
+func (p *relabelPreview) getOrCreateTenant(b *relabelPreviewBatch) *tenantRelabelPreview {
+	p.tenantsMtx.RLock()
+	t, ok := p.tenants[b.userID]
+	p.tenantsMtx.RUnlock()
+	if ok {
+		return t
+	}
+
+	p.tenantsMtx.Lock()
+	defer p.tenantsMtx.Unlock()
+
+	if t, ok := p.tenants[b.userID]; ok {
+		return t
+	}
+	t = &tenantRelabelPreview{}
+	t.reset(b.config, b.config.hash())
+	p.tenants[b.userID] = t
+	return t
+}
+
+func (p *relabelPreview) removeUser(userID string) {
+	p.tenantsMtx.Lock()
+	defer p.tenantsMtx.Unlock()
+
+	delete(p.tenants, userID)
+	p.series.DeletePartialMatch(prometheus.Labels{"user": userID})
+}
+
+type relabelPreviewPageContents struct {
+	Now     time.Time              `json:"now"`
+	Tenants []relabelPreviewTenant `json:"tenants"`
+}
+
+type relabelPreviewTenant struct {
+	UserID   string                  `json:"user_id"`
+	Since    time.Time               `json:"since"`
+	Dropped  int                     `json:"dropped"`
+	Modified int                     `json:"modified"`
+	Created  int                     `json:"created"`
+	Examples []relabelPreviewExample `json:"examples"`
+}
+
+// ServeHTTP shows the series counts and examples of the tenants with shadow metric relabeling enabled,
+// or of a single tenant if the tenant query parameter is set.
+func (p *relabelPreview) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	filter := req.URL.Query().Get("tenant")
+
+	p.tenantsMtx.RLock()
+	tenants := make([]relabelPreviewTenant, 0, len(p.tenants))
+	for userID, t := range p.tenants {
+		if (filter != "" && userID != filter) || !p.limits.ShadowMetricRelabelingEnabled(userID) {
+			continue
+		}
+		t.mtx.Lock()
+		tenant := relabelPreviewTenant{
+			UserID:   userID,
+			Since:    t.since,
+			Dropped:  t.counts[relabelPreviewDropped],
+			Modified: t.counts[relabelPreviewModified],
+			Created:  t.counts[relabelPreviewCreated],
+		}
+		for _, outcome := range relabelPreviewOutcomes {
+			tenant.Examples = append(tenant.Examples, t.examples[outcome]...)
+		}
+		t.mtx.Unlock()
+		tenants = append(tenants, tenant)
+	}
+	p.tenantsMtx.RUnlock()
+
+	slices.SortFunc(tenants, func(a, b relabelPreviewTenant) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	util.RenderHTTPResponse(w, relabelPreviewPageContents{
+		Now:     time.Now(),
+		Tenants: tenants,
+	}, relabelPreviewPageTemplate, req)
+}
+
+// RelabelPreviewHandler shows the series the tenants' shadow relabeling would drop, modify or newly create,
+// compared to their active relabeling.
+func (d *Distributor) RelabelPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	d.relabelPreview.ServeHTTP(w, r)
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+func TestDistributor_RelabelPreview(t *testing.T) {
+	limits := prepareDefaultLimits()
+	limits.MetricRelabelConfigs = []*relabel.Config{
+		{
+			SourceLabels: []model.LabelName{"__name__"},
+			Action:       relabel.Drop,
+			Regex:        relabel.MustNewRegexp("a"),
+		},
+	}
+	limits.ShadowMetricRelabelingEnabled = true
+	limits.ShadowMetricRelabelConfigs = []*relabel.Config{
+		{
+			SourceLabels: []model.LabelName{"__name__"},
+			Action:       relabel.Drop,
+			Regex:        relabel.MustNewRegexp("b"),
+		},
+	}
+	limits.ShadowDropLabels = []string{"pod"}
+
+	ds, ingesters, regs, _ := prepare(t, prepConfig{
+		numIngesters:      1,
+		happyIngesters:    1,
+		replicationFactor: 1,
+		numDistributors:   1,
+		limits:            limits,
+	})
+	d, reg := ds[0], regs[0]
+
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now().UnixMilli()
+	_, err := d.Push(ctx, makeWriteRequestWith(
+		makeTimeseries([]string{"__name__", "a", "job", "x"}, makeSamples(now, 1), nil),
+		makeTimeseries([]string{"__name__", "b", "job", "x"}, makeSamples(now, 1), nil),
+		makeTimeseries([]string{"__name__", "c", "job", "x", "pod", "p"}, makeSamples(now, 1), nil),
+		makeTimeseries([]string{"__name__", "d", "job", "x"}, makeSamples(now, 1), nil),
+	))
+	require.NoError(t, err)
+
+	// The shadow relabeling doesn't modify the ingested series.
+	require.Equal(t, []string{"b", "c", "d"}, ingesters[0].metricNames())
+	for _, ts := range ingesters[0].series() {
+		lbls := mimirpb.FromLabelAdaptersToLabels(ts.Labels)
+		if lbls.Get("__name__") == "c" {
+			require.Equal(t, "p", lbls.Get("pod"))
+		}
+	}
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_distributor_shadow_relabel_series_total The total number of received series the shadow relabeling would drop, modify or newly create compared to the active relabeling, since the tenant's relabeling configuration last changed.
+		# TYPE cortex_distributor_shadow_relabel_series_total counter
+		cortex_distributor_shadow_relabel_series_total{outcome="created",user="user"} 1
+		cortex_distributor_shadow_relabel_series_total{outcome="dropped",user="user"} 1
+		cortex_distributor_shadow_relabel_series_total{outcome="modified",user="user"} 1
+	`), "cortex_distributor_shadow_relabel_series_total"))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/distributor/relabel_preview?tenant=user", nil)
+	req.Header.Set("Accept", "application/json")
+	d.RelabelPreviewHandler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var page relabelPreviewPageContents
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	require.Len(t, page.Tenants, 1)
+	require.Equal(t, "user", page.Tenants[0].UserID)
+	require.Equal(t, 1, page.Tenants[0].Dropped)
+	require.Equal(t, 1, page.Tenants[0].Modified)
+	require.Equal(t, 1, page.Tenants[0].Created)
+	require.Equal(t, []relabelPreviewExample{
+		{Outcome: relabelPreviewDropped, Before: `{__name__="b", job="x"}`, Active: `{__name__="b", job="x"}`},
+		{Outcome: relabelPreviewModified, Before: `{__name__="c", job="x", pod="p"}`, Active: `{__name__="c", job="x", pod="p"}`, Shadow: `{__name__="c", job="x"}`},
+		{Outcome: relabelPreviewCreated, Before: `{__name__="a", job="x"}`, Shadow: `{__name__="a", job="x"}`},
+	}, page.Tenants[0].Examples)
+
+	// The counts aren't reset when the runtime configuration is reloaded without changing the tenant's relabeling configuration.
+	reloaded := d.relabelPreview.newBatch("user", true)
+	reloaded.config.activeRelabelConfigs = slices.Clone(reloaded.config.activeRelabelConfigs)
+	reloaded.config.shadowRelabelConfigs = slices.Clone(reloaded.config.shadowRelabelConfigs)
+	reloaded.config.shadowDropLabels = slices.Clone(reloaded.config.shadowDropLabels)
+	d.relabelPreview.commit(reloaded)
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_distributor_shadow_relabel_series_total The total number of received series the shadow relabeling would drop, modify or newly create compared to the active relabeling, since the tenant's relabeling configuration last changed.
+		# TYPE cortex_distributor_shadow_relabel_series_total counter
+		cortex_distributor_shadow_relabel_series_total{outcome="created",user="user"} 1
+		cortex_distributor_shadow_relabel_series_total{outcome="dropped",user="user"} 1
+		cortex_distributor_shadow_relabel_series_total{outcome="modified",user="user"} 1
+	`), "cortex_distributor_shadow_relabel_series_total"))
+
+	// The counts are reset when the tenant's relabeling configuration changes.
+	d.relabelPreview.commit(d.relabelPreview.newBatch("user", false))
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(""), "cortex_distributor_shadow_relabel_series_total"))
+
+	rec = httptest.NewRecorder()
+	d.RelabelPreviewHandler(rec, httptest.NewRequest("GET", "/distributor/relabel_preview", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "<h2>Tenant user</h2>")
+	require.NotContains(t, rec.Body.String(), "job=&#34;x&#34;")
+
+	d.cleanupInactiveUser("user")
+	d.relabelPreview.tenantsMtx.RLock()
+	require.Empty(t, d.relabelPreview.tenants)
+	d.relabelPreview.tenantsMtx.RUnlock()
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+const (
+	remoteWriteV1ProtoMessage = "prometheus.WriteRequest"
+	remoteWriteV2ProtoMessage = "io.prometheus.write.v2.Request"
+
+	// RemoteWriteV2ContentType is the Content-Type of the Prometheus remote-write 2.0 requests.
+	RemoteWriteV2ContentType = "application/x-protobuf;proto=" + remoteWriteV2ProtoMessage
+
+	remoteWriteSamplesWrittenHeader    = "X-Prometheus-Remote-Write-Samples-Written"
+	remoteWriteHistogramsWrittenHeader = "X-Prometheus-Remote-Write-Histograms-Written"
+	remoteWriteExemplarsWrittenHeader  = "X-Prometheus-Remote-Write-Exemplars-Written"
+)
+
+// remoteWriteProtoMessage returns the remote-write protobuf message negotiated with the Content-Type header.
+// Requests without a protobuf Content-Type, or without the proto parameter, are remote-write 1.0 requests.
+func remoteWriteProtoMessage(contentType string) (string, error) {
+	if contentType == "" {
+		return remoteWriteV1ProtoMessage, nil
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "application/x-protobuf" {
+		return remoteWriteV1ProtoMessage, nil
+	}
+
+	switch msg := params["proto"]; msg {
+	case "", remoteWriteV1ProtoMessage:
+		return remoteWriteV1ProtoMessage, nil
+	case remoteWriteV2ProtoMessage:
+		return remoteWriteV2ProtoMessage, nil
+	default:
+		return "", fmt.Errorf("unsupported remote-write protobuf message %q, supported messages are %q and %q", msg, remoteWriteV1ProtoMessage, remoteWriteV2ProtoMessage)
+	}
+}
+
+// isRemoteWriteV2Request returns whether the request is a remote-write 2.0 one.
+func isRemoteWriteV2Request(r *http.Request) bool {
+	msg, err := remoteWriteProtoMessage(r.Header.Get("Content-Type"))
+	return err == nil && msg == remoteWriteV2ProtoMessage
+}
+
+// parseRemoteWriteV2 reads the remote-write 2.0 request from the HTTP request, and converts it into req.
+// The converted labels reference the buffers, so they're only valid until the buffers are cleaned up.
+func parseRemoteWriteV2(ctx context.Context, r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers, req *mimirpb.PreallocWriteRequest, createdTimestampZeroSamples bool) (int, error) {
+	var reqV2 mimirpb.WriteRequestV2
+	protoBodySize, err := util.ParseProtoReader(ctx, r.Body, int(r.ContentLength), maxRecvMsgSize, buffers, &reqV2, util.RawSnappy)
+	if err != nil {
+		return 0, err
+	}
+	if err := reqV2.ToWriteRequest(&req.WriteRequest, createdTimestampZeroSamples); err != nil {
+		return 0, err
+	}
+	return protoBodySize, nil
+}
+
+// remoteWriteStats holds the number of samples, histograms and exemplars of a remote-write request.
+type remoteWriteStats struct {
+	samples, histograms, exemplars int
+}
+
+func newRemoteWriteStats(req *mimirpb.WriteRequest) remoteWriteStats {
+	var stats remoteWriteStats
+	for _, ts := range req.Timeseries {
+		stats.samples += len(ts.Samples)
+		stats.histograms += len(ts.Histograms)
+		stats.exemplars += len(ts.Exemplars)
+	}
+	return stats
+}
+
+// writtenStats tracks the number of samples, histograms and exemplars written to the storage by a push.
+type writtenStats struct {
+	mtx   sync.Mutex
+	stats remoteWriteStats
+}
+
+type writtenStatsContextKey int
+
+const writtenStatsKey writtenStatsContextKey = 0
+
+// contextWithWrittenStats returns a context tracking the samples, histograms and exemplars written to the storage
+// by the push in stats.
+func contextWithWrittenStats(ctx context.Context, stats *writtenStats) context.Context {
+	return context.WithValue(ctx, writtenStatsKey, stats)
+}
+
+func writtenStatsFromContext(ctx context.Context) *writtenStats {
+	stats, _ := ctx.Value(writtenStatsKey).(*writtenStats)
+	return stats
+}
+
+func (w *writtenStats) add(stats remoteWriteStats) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.stats.samples += stats.samples
+	w.stats.histograms += stats.histograms
+	w.stats.exemplars += stats.exemplars
+}
+
+// setWrittenHeaders sets the remote-write 2.0 response headers with the number of written samples, histograms and exemplars.
+func (w *writtenStats) setWrittenHeaders(h http.Header) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	h.Set(remoteWriteSamplesWrittenHeader, strconv.Itoa(w.stats.samples))
+	h.Set(remoteWriteHistogramsWrittenHeader, strconv.Itoa(w.stats.histograms))
+	h.Set(remoteWriteExemplarsWrittenHeader, strconv.Itoa(w.stats.exemplars))
+}
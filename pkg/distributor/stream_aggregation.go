@@ -0,0 +1,589 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/dskit/ring"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/mimir/pkg/distributor/distributorpb"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const streamAggregationFlushCheckInterval = time.Second
+
+// streamAggregationRingOp is used to find the distributor owning a stream aggregation output series.
+var streamAggregationRingOp = ring.NewOp([]ring.InstanceState{ring.ACTIVE}, nil)
+
+// streamAggregation aggregates the samples of the series matching the tenants' stream aggregation rules,
+// and writes the aggregated outputs once per rule interval.
+//
+// Each output series is aggregated by a single distributor, which owns the hash of the output series in the
+// distributors ring. The inputs of the output series owned by other distributors are forwarded to them.
+type streamAggregation struct {
+	services.Service
+
+	limits *validation.Overrides
+	logger log.Logger
+
+	// distributorsRing is nil if the distributor can't join the distributors ring, in which case all outputs are aggregated locally.
+	distributorsRing ring.ReadRing
+	instanceID       string
+
+	// push writes the aggregated outputs.
+	push PushFunc
+
+	// forward sends the inputs of the rule at ruleIdx to the distributor owning their output series.
+	forward func(ctx context.Context, inst ring.InstanceDesc, ruleIdx int, req *mimirpb.WriteRequest) error
+
+	tenantsMx sync.RWMutex
+	tenants   map[string]*tenantStreamAggregation
+
+	inputSamples     *prometheus.CounterVec
+	forwardedSamples *prometheus.CounterVec
+	failedForwards   *prometheus.CounterVec
+	outputSamples    *prometheus.CounterVec
+	failedFlushes    *prometheus.CounterVec
+}
+
+type tenantStreamAggregation struct {
+	mtx         sync.Mutex
+	rules       []*validation.StreamAggregationRule
+	rulesHash   uint64
+	aggregators []*streamAggregator
+}
+
+// streamAggregator aggregates the samples of the series matching a stream aggregation rule.
+type streamAggregator struct {
+	rule     *validation.StreamAggregationRule
+	matchers []*labels.Matcher
+	rate     bool
+
+	mtx       sync.Mutex
+	nextFlush time.Time
+	outputs   map[string]*streamAggregationOutput
+	keyBuf    []byte
+}
+
+// streamAggregationForward holds the inputs of a stream aggregation rule to forward to the distributor owning their output series.
+type streamAggregationForward struct {
+	inst      ring.InstanceDesc
+	ruleIdx   int
+	series    []mimirpb.PreallocTimeseries
+	samples   int
+	tsIndexes []int // Indexes of the forwarded series in the request.
+}
+
+// streamAggregationOutput holds the aggregated samples of an output series since the last flush.
+type streamAggregationOutput struct {
+	metricName string
+	labels     []mimirpb.LabelAdapter // Labels of the input series, without the metric name and the rule labels.
+
+	samples  int
+	sum      float64
+	max      float64
+	increase float64
+
+	// Last value of each input series, used to compute the rate.
+	rateInputs map[string]*streamAggregationRateInput
+}
+
+type streamAggregationRateInput struct {
+	lastValue float64
+	seen      bool // Whether the input series has been seen since the last flush.
+}
+
+// newStreamAggregation returns a streamAggregation writing the aggregated outputs with push, and forwarding the inputs
+// owned by other distributors in distributorsRing with clients.
+func newStreamAggregation(limits *validation.Overrides, distributorsRing ring.ReadRing, instanceID string, clients *ring_client.Pool, push PushFunc, logger log.Logger, reg prometheus.Registerer) *streamAggregation {
+	s := &streamAggregation{
+		limits:           limits,
+		logger:           logger,
+		distributorsRing: distributorsRing,
+		instanceID:       instanceID,
+		push:             push,
+		tenants:          map[string]*tenantStreamAggregation{},
+		inputSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_stream_aggregation_input_samples_total",
+			Help: "The total number of samples aggregated by stream aggregation rules.",
+		}, []string{"user"}),
+		forwardedSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_stream_aggregation_forwarded_samples_total",
+			Help: "The total number of stream aggregation input samples forwarded to the distributor owning their output series.",
+		}, []string{"user"}),
+		failedForwards: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_stream_aggregation_forward_failures_total",
+			Help: "The total number of failed forwards of stream aggregation inputs to the distributor owning their output series.",
+		}, []string{"user"}),
+		outputSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_stream_aggregation_output_samples_total",
+			Help: "The total number of samples written by stream aggregation rules.",
+		}, []string{"user"}),
+		failedFlushes: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_stream_aggregation_flush_failures_total",
+			Help: "The total number of failed writes of stream aggregation outputs.",
+		}, []string{"user"}),
+	}
+	s.forward = func(ctx context.Context, inst ring.InstanceDesc, ruleIdx int, req *mimirpb.WriteRequest) error {
+		c, err := clients.GetClientForInstance(inst)
+		if err != nil {
+			return err
+		}
+		_, err = c.(distributorpb.DistributorClient).PushStreamAggregationInputs(ctx, &distributorpb.StreamAggregationInputsRequest{
+			RuleIndex: int32(ruleIdx),
+			Request:   *req,
+		})
+		return err
+	}
+	s.Service = services.NewTimerService(streamAggregationFlushCheckInterval, nil, s.iteration, nil)
+	return s
+}
+
+// aggregators returns the stream aggregators of the tenant, or nil if the tenant has no stream aggregation rules.
+// The aggregators, and their pending outputs, are replaced when the tenant's rules change.
+func (s *streamAggregation) aggregators(userID string, now time.Time) []*streamAggregator {
+	rules := s.limits.StreamAggregationRules(userID)
+	if len(rules) == 0 {
+		s.removeTenant(userID)
+		return nil
+	}
+
+	t := s.getOrCreateTenant(userID, rules, now)
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	// The rules content is only compared when the runtime configuration has been reloaded,
+	// in order to keep the hashing out of the hot path.
+	if !sameSlice(t.rules, rules) {
+		if rulesHash := hashStreamAggregationRules(rules); rulesHash != t.rulesHash {
+			t.reset(rules, rulesHash, now, log.With(s.logger, "user", userID))
+		} else {
+			t.rules = rules
+		}
+	}
+	return t.aggregators
+}
+
+// getOrCreateTenant returns the stream aggregation of the tenant, creating it with the given rules if it doesn't exist yet.
+func (s *streamAggregation) getOrCreateTenant(userID string, rules []*validation.StreamAggregationRule, now time.Time) *tenantStreamAggregation {
+	s.tenantsMx.RLock()
+	t, ok := s.tenants[userID]
+	s.tenantsMx.RUnlock()
+	if ok {
+		return t
+	}
+
+	s.tenantsMx.Lock()
+	defer s.tenantsMx.Unlock()
+
+	if t, ok := s.tenants[userID]; ok {
+		return t
+	}
+	t = &tenantStreamAggregation{}
+	t.reset(rules, hashStreamAggregationRules(rules), now, log.With(s.logger, "user", userID))
+	s.tenants[userID] = t
+	return t
+}
+
+// removeTenant forgets the tenant's pending outputs, if any.
+func (s *streamAggregation) removeTenant(userID string) {
+	s.tenantsMx.RLock()
+	_, ok := s.tenants[userID]
+	s.tenantsMx.RUnlock()
+	if !ok {
+		return
+	}
+
+	s.tenantsMx.Lock()
+	delete(s.tenants, userID)
+	s.tenantsMx.Unlock()
+}
+
+// reset replaces the aggregators, and their pending outputs, with the ones of the given rules.
+func (t *tenantStreamAggregation) reset(rules []*validation.StreamAggregationRule, rulesHash uint64, now time.Time, logger log.Logger) {
+	t.rules = rules
+	t.rulesHash = rulesHash
+	t.aggregators = make([]*streamAggregator, 0, len(rules))
+	for _, rule := range rules {
+		matchers, err := rule.Matchers()
+		if err != nil {
+			// This should never happen because rules are validated when loaded.
+			level.Warn(logger).Log("msg", "skipping invalid stream aggregation rule", "err", err)
+			continue
+		}
+		interval := time.Duration(rule.Interval)
+		t.aggregators = append(t.aggregators, &streamAggregator{
+			rule:      rule,
+			matchers:  matchers,
+			rate:      slices.Contains(rule.Outputs, validation.StreamAggregationOutputRate),
+			nextFlush: now.Truncate(interval).Add(interval),
+			outputs:   map[string]*streamAggregationOutput{},
+		})
+	}
+}
+
+// hashStreamAggregationRules returns a hash of the rules content.
+func hashStreamAggregationRules(rules []*validation.StreamAggregationRule) uint64 {
+	out, err := yaml.Marshal(rules)
+	if err != nil {
+		// Should never happen, because the rules have been unmarshalled from YAML.
+		return 0
+	}
+	return xxhash.Sum64(out)
+}
+
+// aggregate aggregates the float samples of the series of req matching the tenant's stream aggregation rules,
+// and returns the indexes of the series to remove from req because no matching rule keeps its input.
+// The inputs of the output series owned by other distributors are forwarded to them. The inputs which
+// fail to be forwarded are kept in req, so that they're ingested as they are rather than lost.
+func (s *streamAggregation) aggregate(ctx context.Context, userID string, req *mimirpb.WriteRequest, now time.Time) []int {
+	aggregators := s.aggregators(userID, now)
+	if len(aggregators) == 0 {
+		return nil
+	}
+
+	var (
+		removeTsIndexes              []int
+		forwards                     []*streamAggregationForward
+		aggregatedSamples            = 0
+		outputLabels                 []mimirpb.LabelAdapter
+		bufDescs, bufHosts, bufZones = ring.MakeBuffersForGet()
+	)
+	for tsIdx, ts := range req.Timeseries {
+		// Native histograms aren't aggregated, so series with histograms are kept as they are.
+		if len(ts.Samples) == 0 || len(ts.Histograms) > 0 {
+			continue
+		}
+
+		matched, keepInput := false, false
+		for ruleIdx, a := range aggregators {
+			if !a.matches(ts.Labels) {
+				continue
+			}
+			matched = true
+			keepInput = keepInput || a.rule.KeepInput
+
+			outputLabels = a.outputLabels(ts.Labels, outputLabels[:0])
+			inst, local := s.owner(userID, outputLabels, bufDescs, bufHosts, bufZones)
+			if local {
+				a.add(ts.Labels, outputLabels, ts.Samples)
+				aggregatedSamples += len(ts.Samples)
+				continue
+			}
+			forwards = appendStreamAggregationForward(forwards, inst, ruleIdx, tsIdx, ts.TimeSeries)
+		}
+		if matched && !keepInput {
+			removeTsIndexes = append(removeTsIndexes, tsIdx)
+		}
+	}
+	if aggregatedSamples > 0 {
+		s.inputSamples.WithLabelValues(userID).Add(float64(aggregatedSamples))
+	}
+
+	if len(forwards) == 0 {
+		return removeTsIndexes
+	}
+
+	forwarded := make([]bool, len(forwards))
+	_ = concurrency.ForEachJob(ctx, len(forwards), len(forwards), func(ctx context.Context, idx int) error {
+		f := forwards[idx]
+		req := &mimirpb.WriteRequest{Timeseries: f.series, Source: mimirpb.API}
+		if err := s.forward(ctx, f.inst, f.ruleIdx, req); err != nil {
+			s.failedForwards.WithLabelValues(userID).Inc()
+			level.Warn(s.logger).Log("msg", "failed to forward stream aggregation inputs", "user", userID, "distributor", f.inst.Id, "err", err)
+			return nil
+		}
+		s.forwardedSamples.WithLabelValues(userID).Add(float64(f.samples))
+		forwarded[idx] = true
+		return nil
+	})
+
+	// The forwards not run because the context has been canceled are considered failed too.
+	var keepTsIndexes []int
+	for idx, f := range forwards {
+		if !forwarded[idx] {
+			keepTsIndexes = append(keepTsIndexes, f.tsIndexes...)
+		}
+	}
+	if len(keepTsIndexes) > 0 {
+		removeTsIndexes = slices.DeleteFunc(removeTsIndexes, func(tsIdx int) bool {
+			return slices.Contains(keepTsIndexes, tsIdx)
+		})
+	}
+	return removeTsIndexes
+}
+
+// aggregateForwarded aggregates the float samples of the series of req, forwarded by another distributor, into
+// the outputs of the tenant's stream aggregation rule at ruleIdx. The inputs are never forwarded again, even if the
+// distributor doesn't own their output series according to its view of the distributors ring.
+func (s *streamAggregation) aggregateForwarded(userID string, ruleIdx int, req *mimirpb.WriteRequest, now time.Time) {
+	aggregators := s.aggregators(userID, now)
+	if ruleIdx < 0 || ruleIdx >= len(aggregators) {
+		level.Warn(s.logger).Log("msg", "dropping forwarded stream aggregation inputs of unknown rule", "user", userID, "rule", ruleIdx)
+		return
+	}
+	a := aggregators[ruleIdx]
+
+	var (
+		aggregatedSamples = 0
+		outputLabels      []mimirpb.LabelAdapter
+	)
+	for _, ts := range req.Timeseries {
+		// The rules may have changed since the inputs were forwarded.
+		if len(ts.Samples) == 0 || !a.matches(ts.Labels) {
+			continue
+		}
+		outputLabels = a.outputLabels(ts.Labels, outputLabels[:0])
+		a.add(ts.Labels, outputLabels, ts.Samples)
+		aggregatedSamples += len(ts.Samples)
+	}
+	if aggregatedSamples > 0 {
+		s.inputSamples.WithLabelValues(userID).Add(float64(aggregatedSamples))
+	}
+}
+
+// owner returns the distributor owning the output series with the given labels, and whether it's this distributor.
+func (s *streamAggregation) owner(userID string, outputLabels []mimirpb.LabelAdapter, bufDescs []ring.InstanceDesc, bufHosts, bufZones []string) (ring.InstanceDesc, bool) {
+	if s.distributorsRing == nil {
+		return ring.InstanceDesc{}, true
+	}
+
+	set, err := s.distributorsRing.Get(mimirpb.ShardByAllLabelAdapters(userID, outputLabels), streamAggregationRingOp, bufDescs, bufHosts, bufZones)
+	if err != nil || len(set.Instances) == 0 {
+		// The output series is aggregated locally while the ring is empty, like when the distributors are starting.
+		level.Debug(s.logger).Log("msg", "failed to find the distributor owning a stream aggregation output series, aggregating it locally", "user", userID, "err", err)
+		return ring.InstanceDesc{}, true
+	}
+	inst := set.Instances[0]
+	return inst, inst.Id == s.instanceID
+}
+
+// appendStreamAggregationForward appends a copy of the input series ts, at tsIdx in the request, to the forward of the rule at ruleIdx to inst.
+func appendStreamAggregationForward(forwards []*streamAggregationForward, inst ring.InstanceDesc, ruleIdx, tsIdx int, ts *mimirpb.TimeSeries) []*streamAggregationForward {
+	var f *streamAggregationForward
+	for _, candidate := range forwards {
+		if candidate.inst.Id == inst.Id && candidate.ruleIdx == ruleIdx {
+			f = candidate
+			break
+		}
+	}
+	if f == nil {
+		f = &streamAggregationForward{inst: inst, ruleIdx: ruleIdx}
+		forwards = append(forwards, f)
+	}
+
+	// The series is copied because the request it belongs to is released, and its slices reused, independently.
+	f.series = append(f.series, mimirpb.PreallocTimeseries{TimeSeries: &mimirpb.TimeSeries{
+		Labels:  slices.Clone(ts.Labels),
+		Samples: slices.Clone(ts.Samples),
+	}})
+	f.samples += len(ts.Samples)
+	f.tsIndexes = append(f.tsIndexes, tsIdx)
+	return forwards
+}
+
+func (s *streamAggregation) iteration(ctx context.Context) error {
+	s.flush(ctx, time.Now())
+	return nil
+}
+
+// flush writes the outputs of the stream aggregators whose interval has ended.
+func (s *streamAggregation) flush(ctx context.Context, now time.Time) {
+	s.tenantsMx.RLock()
+	userIDs := make([]string, 0, len(s.tenants))
+	for userID := range s.tenants {
+		userIDs = append(userIDs, userID)
+	}
+	s.tenantsMx.RUnlock()
+
+	for _, userID := range userIDs {
+		var series []mimirpb.PreallocTimeseries
+		for _, a := range s.aggregators(userID, now) {
+			series = a.flush(now, series)
+		}
+		if len(series) == 0 {
+			continue
+		}
+
+		req := &mimirpb.WriteRequest{Timeseries: series, Source: mimirpb.API}
+		if err := s.push(user.InjectOrgID(ctx, userID), NewParsedRequest(req)); err != nil {
+			s.failedFlushes.WithLabelValues(userID).Inc()
+			level.Warn(s.logger).Log("msg", "failed to write stream aggregation outputs", "user", userID, "err", err)
+			continue
+		}
+		s.outputSamples.WithLabelValues(userID).Add(float64(len(series)))
+	}
+}
+
+// removeUser forgets the tenant's pending outputs and removes its metrics.
+func (s *streamAggregation) removeUser(userID string) {
+	s.removeTenant(userID)
+
+	s.inputSamples.DeleteLabelValues(userID)
+	s.forwardedSamples.DeleteLabelValues(userID)
+	s.failedForwards.DeleteLabelValues(userID)
+	s.outputSamples.DeleteLabelValues(userID)
+	s.failedFlushes.DeleteLabelValues(userID)
+}
+
+func (a *streamAggregator) matches(lbls []mimirpb.LabelAdapter) bool {
+	for _, m := range a.matchers {
+		value := ""
+		for _, l := range lbls {
+			if l.Name == m.Name {
+				value = l.Value
+				break
+			}
+		}
+		if !m.Matches(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// outputLabels appends the labels of the output series of the input series with the given labels to buf, sorted by name.
+func (a *streamAggregator) outputLabels(lbls []mimirpb.LabelAdapter, buf []mimirpb.LabelAdapter) []mimirpb.LabelAdapter {
+	for _, l := range lbls {
+		if !slices.Contains(a.rule.Without, l.Name) {
+			buf = append(buf, l)
+		}
+	}
+	// Input labels aren't sorted yet, given the series are sorted by a later middleware.
+	slices.SortFunc(buf, func(a, b mimirpb.LabelAdapter) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return buf
+}
+
+// add aggregates the samples of the input series with the given labels into its output series, with the given sorted labels.
+func (a *streamAggregator) add(lbls, outputLabels []mimirpb.LabelAdapter, samples []mimirpb.Sample) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	// The output series is identified by its labels.
+	a.keyBuf = a.keyBuf[:0]
+	for _, l := range outputLabels {
+		a.keyBuf = append(a.keyBuf, l.Name...)
+		a.keyBuf = append(a.keyBuf, '\xff')
+		a.keyBuf = append(a.keyBuf, l.Value...)
+		a.keyBuf = append(a.keyBuf, '\xff')
+	}
+
+	out := a.outputs[string(a.keyBuf)]
+	if out == nil {
+		// Input labels may be backed by the request buffer, so they're copied to be retained.
+		out = &streamAggregationOutput{max: math.Inf(-1)}
+		for _, l := range outputLabels {
+			if l.Name == labels.MetricName {
+				out.metricName = string([]byte(l.Value))
+				continue
+			}
+			out.labels = append(out.labels, mimirpb.LabelAdapter{Name: string([]byte(l.Name)), Value: string([]byte(l.Value))})
+		}
+		if a.rate {
+			out.rateInputs = map[string]*streamAggregationRateInput{}
+		}
+		a.outputs[string(a.keyBuf)] = out
+	}
+
+	for _, sample := range samples {
+		out.samples++
+		out.sum += sample.Value
+		out.max = math.Max(out.max, sample.Value)
+	}
+
+	if a.rate {
+		inputKey := mimirpb.FromLabelAdaptersToString(lbls)
+		in := out.rateInputs[inputKey]
+		first := 0
+		if in == nil {
+			// The first sample of an input series is the base of its increase.
+			in = &streamAggregationRateInput{lastValue: samples[0].Value}
+			out.rateInputs[inputKey] = in
+			first = 1
+		}
+		for _, sample := range samples[first:] {
+			if sample.Value >= in.lastValue {
+				out.increase += sample.Value - in.lastValue
+			} else {
+				// Counter reset.
+				out.increase += sample.Value
+			}
+			in.lastValue = sample.Value
+		}
+		in.seen = true
+	}
+}
+
+// flush appends the output series of the aggregator to series if its interval has ended, and resets the outputs.
+func (a *streamAggregator) flush(now time.Time, series []mimirpb.PreallocTimeseries) []mimirpb.PreallocTimeseries {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if now.Before(a.nextFlush) {
+		return series
+	}
+	interval := time.Duration(a.rule.Interval)
+	timestamp := a.nextFlush.UnixMilli()
+	a.nextFlush = now.Truncate(interval).Add(interval)
+
+	for key, out := range a.outputs {
+		if out.samples == 0 {
+			// The output hasn't received any sample during the whole interval.
+			delete(a.outputs, key)
+			continue
+		}
+
+		for _, output := range a.rule.Outputs {
+			var value float64
+			switch output {
+			case validation.StreamAggregationOutputSum:
+				value = out.sum
+			case validation.StreamAggregationOutputCount:
+				value = float64(out.samples)
+			case validation.StreamAggregationOutputMax:
+				value = out.max
+			case validation.StreamAggregationOutputRate:
+				value = out.increase / interval.Seconds()
+			}
+
+			lbls := make([]mimirpb.LabelAdapter, 0, len(out.labels)+1)
+			lbls = append(lbls, mimirpb.LabelAdapter{Name: labels.MetricName, Value: a.rule.OutputMetricName(out.metricName, output)})
+			lbls = append(lbls, out.labels...)
+			series = append(series, mimirpb.PreallocTimeseries{TimeSeries: &mimirpb.TimeSeries{
+				Labels:  lbls,
+				Samples: []mimirpb.Sample{{TimestampMs: timestamp, Value: value}},
+			}})
+		}
+
+		out.samples, out.sum, out.max, out.increase = 0, 0, math.Inf(-1), 0
+		for inputKey, in := range out.rateInputs {
+			if !in.seen {
+				delete(out.rateInputs, inputKey)
+				continue
+			}
+			in.seen = false
+		}
+	}
+	return series
+}
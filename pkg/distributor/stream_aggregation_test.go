@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/distributor/distributorpb"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestDistributor_StreamAggregation(t *testing.T) {
+	limits := prepareDefaultLimits()
+	limits.StreamAggregationRules = []*validation.StreamAggregationRule{
+		{
+			Selector: `{__name__="http_requests_total"}`,
+			Interval: model.Duration(time.Minute),
+			Without:  []string{"pod"},
+			Outputs:  []string{"sum", "count", "max", "rate"},
+		},
+		{
+			Selector:  `{__name__="kept"}`,
+			Interval:  model.Duration(time.Minute),
+			Outputs:   []string{"max"},
+			KeepInput: true,
+		},
+	}
+
+	ds, ingesters, regs, _ := prepare(t, prepConfig{
+		numIngesters:      1,
+		happyIngesters:    1,
+		replicationFactor: 1,
+		numDistributors:   1,
+		limits:            limits,
+	})
+	d, reg := ds[0], regs[0]
+
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now().UnixMilli()
+	_, err := d.Push(ctx, makeWriteRequestWith(
+		makeTimeseries([]string{"__name__", "http_requests_total", "job", "a", "pod", "p1"}, makeSamples(now, 10), nil),
+		makeTimeseries([]string{"__name__", "http_requests_total", "job", "a", "pod", "p2"}, makeSamples(now, 5), nil),
+		makeTimeseries([]string{"__name__", "kept", "job", "a"}, makeSamples(now, 3), nil),
+		makeTimeseries([]string{"__name__", "other", "job", "a"}, makeSamples(now, 1), nil),
+	))
+	require.NoError(t, err)
+
+	_, err = d.Push(ctx, makeWriteRequestWith(
+		makeTimeseries([]string{"__name__", "http_requests_total", "job", "a", "pod", "p1"}, makeSamples(now+1, 20), nil),
+		// Counter reset.
+		makeTimeseries([]string{"__name__", "http_requests_total", "job", "a", "pod", "p2"}, makeSamples(now+1, 2), nil),
+	))
+	require.NoError(t, err)
+
+	// The aggregated input series are dropped, unless a rule keeps them.
+	require.Equal(t, []string{"kept", "other"}, ingesters[0].metricNames())
+
+	// Nothing is written before the end of the interval.
+	d.streamAggregation.flush(ctx, time.Now().Add(-time.Minute))
+	require.Equal(t, []string{"kept", "other"}, ingesters[0].metricNames())
+
+	d.streamAggregation.flush(ctx, time.Now().Add(time.Minute))
+	values := map[string]float64{}
+	for _, ts := range ingesters[0].series() {
+		lbls := mimirpb.FromLabelAdaptersToLabels(ts.Labels)
+		if !strings.Contains(lbls.Get(labels.MetricName), ":") {
+			continue
+		}
+		require.Equal(t, "a", lbls.Get("job"))
+		require.False(t, lbls.Has("pod"))
+		require.Len(t, ts.Samples, 1)
+		values[lbls.Get(labels.MetricName)] = ts.Samples[0].Value
+	}
+	require.Equal(t, map[string]float64{
+		"http_requests_total:1m_without_pod_sum":   37,
+		"http_requests_total:1m_without_pod_count": 4,
+		"http_requests_total:1m_without_pod_max":   20,
+		"http_requests_total:1m_without_pod_rate":  12.0 / 60,
+		"kept:1m_max": 3,
+	}, values)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_distributor_stream_aggregation_input_samples_total The total number of samples aggregated by stream aggregation rules.
+		# TYPE cortex_distributor_stream_aggregation_input_samples_total counter
+		cortex_distributor_stream_aggregation_input_samples_total{user="user"} 5
+
+		# HELP cortex_distributor_stream_aggregation_output_samples_total The total number of samples written by stream aggregation rules.
+		# TYPE cortex_distributor_stream_aggregation_output_samples_total counter
+		cortex_distributor_stream_aggregation_output_samples_total{user="user"} 5
+	`), "cortex_distributor_stream_aggregation_input_samples_total", "cortex_distributor_stream_aggregation_output_samples_total"))
+
+	// Outputs which haven't received samples during the interval aren't written again.
+	d.streamAggregation.flush(ctx, time.Now().Add(2*time.Minute))
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_distributor_stream_aggregation_output_samples_total The total number of samples written by stream aggregation rules.
+		# TYPE cortex_distributor_stream_aggregation_output_samples_total counter
+		cortex_distributor_stream_aggregation_output_samples_total{user="user"} 5
+	`), "cortex_distributor_stream_aggregation_output_samples_total"))
+
+	d.cleanupInactiveUser("user")
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(""), "cortex_distributor_stream_aggregation_input_samples_total", "cortex_distributor_stream_aggregation_output_samples_total"))
+}
+
+func TestDistributor_StreamAggregation_ShouldAggregateEachOutputSeriesInTheDistributorOwningIt(t *testing.T) {
+	const numJobs = 50
+
+	limits := prepareDefaultLimits()
+	limits.StreamAggregationRules = []*validation.StreamAggregationRule{{
+		Selector: `{__name__="http_requests_total"}`,
+		Interval: model.Duration(time.Minute),
+		Without:  []string{"pod"},
+		Outputs:  []string{"sum"},
+	}}
+
+	ds, ingesters, _, _ := prepare(t, prepConfig{
+		numIngesters:      1,
+		happyIngesters:    1,
+		replicationFactor: 1,
+		numDistributors:   2,
+		limits:            limits,
+	})
+
+	// Forward the inputs to the distributors in-process, like the gRPC server would.
+	distributorsByID := map[string]*Distributor{}
+	for _, d := range ds {
+		distributorsByID[d.cfg.DistributorRing.Common.InstanceID] = d
+	}
+	for _, d := range ds {
+		d.streamAggregation.forward = func(ctx context.Context, inst ring.InstanceDesc, ruleIdx int, req *mimirpb.WriteRequest) error {
+			_, err := distributorsByID[inst.Id].PushStreamAggregationInputs(ctx, &distributorpb.StreamAggregationInputsRequest{
+				RuleIndex: int32(ruleIdx),
+				Request:   *req,
+			})
+			return err
+		}
+	}
+
+	// Each distributor receives the input series of a different pod, with labels out of order.
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now().UnixMilli()
+	for i, d := range ds {
+		var series []mimirpb.PreallocTimeseries
+		for job := 0; job < numJobs; job++ {
+			series = append(series, makeTimeseries([]string{"pod", strconv.Itoa(i), "job", strconv.Itoa(job), "__name__", "http_requests_total"}, makeSamples(now, float64(job+i)), nil))
+		}
+		_, err := d.Push(ctx, makeWriteRequestWith(series...))
+		require.NoError(t, err)
+	}
+
+	for _, d := range ds {
+		d.streamAggregation.flush(ctx, time.Now().Add(time.Minute))
+	}
+
+	// Each output series is written once, with the samples received by all the distributors.
+	values := map[string]float64{}
+	for _, ts := range ingesters[0].series() {
+		lbls := mimirpb.FromLabelAdaptersToLabels(ts.Labels)
+		require.Equal(t, "http_requests_total:1m_without_pod_sum", lbls.Get(labels.MetricName))
+		require.Equal(t, 2, lbls.Len())
+		require.Len(t, ts.Samples, 1)
+		_, exists := values[lbls.Get("job")]
+		require.False(t, exists, lbls.String())
+		values[lbls.Get("job")] = ts.Samples[0].Value
+	}
+	expected := map[string]float64{}
+	for job := 0; job < numJobs; job++ {
+		expected[strconv.Itoa(job)] = float64(2*job + 1)
+	}
+	require.Equal(t, expected, values)
+
+	// For each job, the input series received by the distributor not owning the output series has been forwarded.
+	var inputSamples, forwardedSamples float64
+	for i, d := range ds {
+		inputSamples += testutil.ToFloat64(d.streamAggregation.inputSamples.WithLabelValues("user"))
+		forwardedSamples += testutil.ToFloat64(d.streamAggregation.forwardedSamples.WithLabelValues("user"))
+		require.Zero(t, testutil.ToFloat64(d.streamAggregation.failedForwards.WithLabelValues("user")), i)
+	}
+	require.Equal(t, float64(2*numJobs), inputSamples)
+	require.Equal(t, float64(numJobs), forwardedSamples)
+}
+
+func TestDistributor_StreamAggregation_ShouldKeepTheInputsFailedToBeForwarded(t *testing.T) {
+	const numJobs = 50
+
+	limits := prepareDefaultLimits()
+	limits.StreamAggregationRules = []*validation.StreamAggregationRule{{
+		Selector: `{__name__="http_requests_total"}`,
+		Interval: model.Duration(time.Minute),
+		Without:  []string{"pod"},
+		Outputs:  []string{"sum"},
+	}}
+
+	ds, ingesters, _, _ := prepare(t, prepConfig{
+		numIngesters:      1,
+		happyIngesters:    1,
+		replicationFactor: 1,
+		numDistributors:   2,
+		limits:            limits,
+	})
+	d := ds[0]
+	d.streamAggregation.forward = func(context.Context, ring.InstanceDesc, int, *mimirpb.WriteRequest) error {
+		return errors.New("failed to forward")
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now().UnixMilli()
+	var series []mimirpb.PreallocTimeseries
+	for job := 0; job < numJobs; job++ {
+		series = append(series, makeTimeseries([]string{"__name__", "http_requests_total", "job", strconv.Itoa(job), "pod", "p1"}, makeSamples(now, float64(job)), nil))
+	}
+	_, err := d.Push(ctx, makeWriteRequestWith(series...))
+	require.NoError(t, err)
+
+	// The inputs which failed to be forwarded have been ingested as they are.
+	inputs := 0
+	for _, ts := range ingesters[0].series() {
+		require.Equal(t, "http_requests_total", mimirpb.FromLabelAdaptersToLabels(ts.Labels).Get(labels.MetricName))
+		inputs++
+	}
+	inputSamples := testutil.ToFloat64(d.streamAggregation.inputSamples.WithLabelValues("user"))
+	require.NotZero(t, inputs)
+	require.Equal(t, float64(numJobs-inputs), inputSamples)
+	require.NotZero(t, testutil.ToFloat64(d.streamAggregation.failedForwards.WithLabelValues("user")))
+	require.Zero(t, testutil.ToFloat64(d.streamAggregation.forwardedSamples.WithLabelValues("user")))
+}
+
+func TestStreamAggregation_Aggregators(t *testing.T) {
+	rule := func(selector string) *validation.StreamAggregationRule {
+		return &validation.StreamAggregationRule{Selector: selector, Interval: model.Duration(time.Minute), Outputs: []string{"sum"}}
+	}
+
+	tenantLimits := map[string]*validation.Limits{"user": {StreamAggregationRules: []*validation.StreamAggregationRule{rule(`{__name__="a"}`)}}}
+	overrides, err := validation.NewOverrides(validation.Limits{}, validation.NewMockTenantLimits(tenantLimits))
+	require.NoError(t, err)
+
+	s := newStreamAggregation(overrides, nil, "", nil, nil, log.NewNopLogger(), nil)
+	now := time.Now()
+
+	aggregators := s.aggregators("user", now)
+	require.Len(t, aggregators, 1)
+	require.Nil(t, s.aggregators("other", now))
+
+	// The aggregators are kept as long as the rules are the same.
+	require.Same(t, aggregators[0], s.aggregators("user", now)[0])
+
+	// The aggregators are kept when the rules are reloaded with the same content.
+	tenantLimits["user"] = &validation.Limits{StreamAggregationRules: []*validation.StreamAggregationRule{rule(`{__name__="a"}`)}}
+	require.Same(t, aggregators[0], s.aggregators("user", now)[0])
+
+	// The aggregators are replaced when the rules change.
+	tenantLimits["user"] = &validation.Limits{StreamAggregationRules: []*validation.StreamAggregationRule{rule(`{__name__="b"}`), rule(`{__name__="c"}`)}}
+	updated := s.aggregators("user", now)
+	require.Len(t, updated, 2)
+	require.NotSame(t, aggregators[0], updated[0])
+	require.Equal(t, `{__name__="b"}`, updated[0].rule.Selector)
+
+	// The tenant is forgotten when its rules are removed.
+	tenantLimits["user"] = &validation.Limits{}
+	require.Nil(t, s.aggregators("user", now))
+	require.Empty(t, s.tenants)
+}
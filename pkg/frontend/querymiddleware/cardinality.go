@@ -98,6 +98,11 @@ func (c *cardinalityEstimation) Do(ctx context.Context, request MetricsQueryRequ
 	actualCardinality := statistics.GetFetchedSeriesCount()
 	spanLog.LogFields(otlog.Uint64("actual cardinality", actualCardinality))
 
+	if queryPlanFromContext(ctx) != nil {
+		// The query was run in explain mode, so the actual cardinality is unknown.
+		return res, nil
+	}
+
 	if !estimateAvailable || !isCardinalitySimilar(actualCardinality, estimatedCardinality) {
 		c.storeCardinalityForKey(k, actualCardinality)
 		spanLog.LogFields(otlog.Bool("cache updated", true))
@@ -35,6 +35,7 @@ import (
 	apierror "github.com/grafana/mimir/pkg/api/error"
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier/api"
+	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/streamingpromql/compat"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/spanlogger"
@@ -63,6 +64,11 @@ const (
 
 	formatJSON     = "json"
 	formatProtobuf = "protobuf"
+
+	// statsParam is the request parameter used to ask for the query statistics to be returned in the response.
+	statsParam = "stats"
+	// statsParamAll is the value of the stats parameter used to ask for the per-step statistics too.
+	statsParamAll = "all"
 )
 
 // Codec is used to encode/decode query requests and responses so they can be passed down to middlewares.
@@ -309,6 +315,7 @@ func (c prometheusCodec) decodeRangeQueryRequest(r *http.Request) (MetricsQueryR
 
 	var options Options
 	decodeOptions(r, &options)
+	options.Stats = reqValues.Get(statsParam)
 
 	req := NewPrometheusRangeQueryRequest(
 		r.URL.Path, headers, start, end, step, c.lookbackDelta, queryExpr, options, nil,
@@ -341,6 +348,7 @@ func (c prometheusCodec) decodeInstantQueryRequest(r *http.Request) (MetricsQuer
 
 	var options Options
 	decodeOptions(r, &options)
+	options.Stats = reqValues.Get(statsParam)
 
 	req := NewPrometheusInstantQueryRequest(
 		r.URL.Path, headers, time, c.lookbackDelta, queryExpr, options, nil,
@@ -558,6 +566,17 @@ func (c prometheusCodec) EncodeMetricsQueryRequest(ctx context.Context, r Metric
 		return nil, fmt.Errorf("unsupported request type %T", r)
 	}
 
+	// Queriers return the statistics of the queries they run only if the query stats are enabled,
+	// so there's no point in asking them to track the per-step statistics otherwise. Tracking them
+	// isn't free, so they're requested only if the statistics have been requested by the client.
+	// In such case, the per-step statistics are always requested, because they're stored in the
+	// results cache and used to compute the statistics of later requests hitting the cache.
+	if r.GetOptions().Stats != "" && stats.IsEnabled(ctx) {
+		params := u.Query()
+		params.Set(statsParam, statsParamAll)
+		u.RawQuery = params.Encode()
+	}
+
 	req := &http.Request{
 		Method:     "GET",
 		RequestURI: u.String(), // This is what the httpgrpc code looks at.
@@ -731,6 +750,20 @@ func (c prometheusCodec) DecodeResponse(ctx context.Context, r *http.Response, _
 	return resp, nil
 }
 
+// requestFormValue returns the first value of the request parameter key, without consuming the request body.
+func requestFormValue(r *http.Request, key string) string {
+	// If the form has already been parsed, the body may have been consumed, so we must not parse it again.
+	params := r.Form
+	if params == nil {
+		var err error
+		if params, err = util.ParseRequestFormWithoutConsumingBody(r); err != nil {
+			return ""
+		}
+	}
+
+	return params.Get(key)
+}
+
 func findFormatter(contentType string) formatter {
 	for _, f := range knownFormats {
 		if f.ContentType().String() == contentType {
@@ -751,6 +784,15 @@ func (c prometheusCodec) EncodeResponse(ctx context.Context, req *http.Request,
 	}
 	if a.Data != nil {
 		sp.LogFields(otlog.Int("series", len(a.Data.Result)))
+
+		if queryStats := stats.FromContext(ctx); queryStats != nil && requestFormValue(req, statsParam) != "" {
+			// Don't modify the response in place, as it may be shared with the results cache.
+			data := *a.Data
+			data.Stats = newPrometheusResponseStats(queryStats, requestFormValue(req, statsParam) == statsParamAll)
+			withStats := *a
+			withStats.Data = &data
+			a = &withStats
+		}
 	}
 
 	selectedContentType, formatter := c.negotiateContentType(req.Header.Get("Accept"))
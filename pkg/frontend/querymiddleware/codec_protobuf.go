@@ -72,11 +72,46 @@ func (f protobufFormatter) EncodeResponse(resp *PrometheusResponse) ([]byte, err
 		default:
 			return nil, fmt.Errorf("unknown result type '%s'", resp.Data.ResultType)
 		}
+
+		payload.Stats = f.encodeStats(resp.Data.Stats)
 	}
 
 	return payload.Marshal()
 }
 
+func (protobufFormatter) encodeStats(s *PrometheusResponseStats) *mimirpb.QueryStats {
+	if s == nil {
+		return nil
+	}
+
+	encoded := &mimirpb.QueryStats{}
+
+	if s.Samples != nil {
+		encoded.TotalQueryableSamples = s.Samples.TotalQueryableSamples
+
+		if len(s.Samples.TotalQueryableSamplesPerStep) > 0 {
+			encoded.TotalQueryableSamplesPerStep = make([]mimirpb.QueryStepStat, 0, len(s.Samples.TotalQueryableSamplesPerStep))
+			for _, step := range s.Samples.TotalQueryableSamplesPerStep {
+				encoded.TotalQueryableSamplesPerStep = append(encoded.TotalQueryableSamplesPerStep, mimirpb.QueryStepStat{TimestampMs: step.TimestampMs, Value: step.Value})
+			}
+		}
+	}
+
+	if s.Querier != nil {
+		encoded.WallTimeSeconds = s.Querier.WallTimeSeconds
+		encoded.FetchedSeriesCount = s.Querier.FetchedSeriesCount
+		encoded.FetchedChunkBytes = s.Querier.FetchedChunkBytes
+		encoded.FetchedChunksCount = s.Querier.FetchedChunksCount
+		encoded.FetchedIndexBytes = s.Querier.FetchedIndexBytes
+		encoded.ShardedQueries = s.Querier.ShardedQueries
+		encoded.SplitQueries = s.Querier.SplitQueries
+		encoded.EstimatedSeriesCount = s.Querier.EstimatedSeriesCount
+		encoded.QueueTimeSeconds = s.Querier.QueueTimeSeconds
+	}
+
+	return encoded
+}
+
 func (protobufFormatter) encodeStringData(data []SampleStream) (mimirpb.StringData, error) {
 	if len(data) != 1 {
 		return mimirpb.StringData{}, fmt.Errorf("expected string response to contain exactly one stream, but it has %d", len(data))
@@ -206,6 +241,10 @@ func (f protobufFormatter) DecodeResponse(buf []byte) (*PrometheusResponse, erro
 		return nil, err
 	}
 
+	if data != nil {
+		data.Stats = f.decodeStats(resp.Stats)
+	}
+
 	return &PrometheusResponse{
 		Status:    status,
 		ErrorType: errorType,
@@ -215,6 +254,38 @@ func (f protobufFormatter) DecodeResponse(buf []byte) (*PrometheusResponse, erro
 	}, nil
 }
 
+func (protobufFormatter) decodeStats(s *mimirpb.QueryStats) *PrometheusResponseStats {
+	if s == nil {
+		return nil
+	}
+
+	var samplesPerStep []PrometheusResponseQueryableSamplesStatsPerStep
+	if len(s.TotalQueryableSamplesPerStep) > 0 {
+		samplesPerStep = make([]PrometheusResponseQueryableSamplesStatsPerStep, 0, len(s.TotalQueryableSamplesPerStep))
+		for _, step := range s.TotalQueryableSamplesPerStep {
+			samplesPerStep = append(samplesPerStep, PrometheusResponseQueryableSamplesStatsPerStep{TimestampMs: step.TimestampMs, Value: step.Value})
+		}
+	}
+
+	return &PrometheusResponseStats{
+		Samples: &PrometheusResponseSamplesStats{
+			TotalQueryableSamples:        s.TotalQueryableSamples,
+			TotalQueryableSamplesPerStep: samplesPerStep,
+		},
+		Querier: &PrometheusResponseQuerierStats{
+			WallTimeSeconds:      s.WallTimeSeconds,
+			FetchedSeriesCount:   s.FetchedSeriesCount,
+			FetchedChunkBytes:    s.FetchedChunkBytes,
+			FetchedChunksCount:   s.FetchedChunksCount,
+			FetchedIndexBytes:    s.FetchedIndexBytes,
+			ShardedQueries:       s.ShardedQueries,
+			SplitQueries:         s.SplitQueries,
+			EstimatedSeriesCount: s.EstimatedSeriesCount,
+			QueueTimeSeconds:     s.QueueTimeSeconds,
+		},
+	}
+}
+
 func (f protobufFormatter) decodeData(resp mimirpb.QueryResponse) (*PrometheusData, error) {
 	if resp.Data == nil {
 		if resp.Status != mimirpb.QueryResponse_SUCCESS {
@@ -34,6 +34,7 @@ import (
 	apierror "github.com/grafana/mimir/pkg/api/error"
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier/api"
+	"github.com/grafana/mimir/pkg/querier/stats"
 )
 
 var (
@@ -718,6 +719,164 @@ func TestPrometheusCodec_EncodeRequest_ReadConsistency(t *testing.T) {
 	}
 }
 
+func TestPrometheusCodec_EncodeRequest_Stats(t *testing.T) {
+	codec := newTestPrometheusCodec()
+	_, ctxWithStats := stats.ContextWithEmptyStats(context.Background())
+
+	testCases := map[string]struct {
+		ctx           context.Context
+		statsValue    string
+		expectedStats string
+	}{
+		"stats not requested but enabled": {
+			ctx: ctxWithStats,
+		},
+		"stats requested and enabled": {
+			ctx:           ctxWithStats,
+			statsValue:    "all",
+			expectedStats: "all",
+		},
+		"stats requested without per-step statistics and enabled": {
+			// The per-step statistics are requested anyway to store them in the results cache.
+			ctx:           ctxWithStats,
+			statsValue:    "true",
+			expectedStats: "all",
+		},
+		"stats requested but not enabled": {
+			ctx:        context.Background(),
+			statsValue: "all",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, req := range []MetricsQueryRequest{
+				NewPrometheusRangeQueryRequest("/api/v1/query_range", nil, 0, 60_000, 15_000, 0, parseQuery(t, "up"), Options{Stats: testCase.statsValue}, nil),
+				NewPrometheusInstantQueryRequest("/api/v1/query", nil, 60_000, 0, parseQuery(t, "up"), Options{Stats: testCase.statsValue}, nil),
+			} {
+				encodedRequest, err := codec.EncodeMetricsQueryRequest(testCase.ctx, req)
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedStats, encodedRequest.URL.Query().Get("stats"))
+
+				// Decoding the request should preserve the stats parameter.
+				decodedRequest, err := codec.DecodeMetricsQueryRequest(testCase.ctx, encodedRequest)
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedStats, decodedRequest.GetOptions().Stats)
+			}
+		})
+	}
+}
+
+func TestPrometheusCodec_EncodeResponse_Stats(t *testing.T) {
+	newResponse := func() *PrometheusResponse {
+		return &PrometheusResponse{
+			Status: statusSuccess,
+			Data: &PrometheusData{
+				ResultType: model.ValVector.String(),
+				Result: []SampleStream{
+					{Labels: []mimirpb.LabelAdapter{{Name: "foo", Value: "bar"}}, Samples: []mimirpb.Sample{{TimestampMs: 2000, Value: 1}}},
+				},
+			},
+		}
+	}
+
+	queryStats, ctx := stats.ContextWithEmptyStats(context.Background())
+	queryStats.AddWallTime(2 * time.Second)
+	queryStats.AddFetchedSeries(3)
+	queryStats.AddShardedQueries(2)
+	queryStats.AddSamplesProcessed(30)
+	queryStats.AddSamplesProcessedPerStep([]stats.StepStat{{Timestamp: 1000, Value: 10}, {Timestamp: 2000, Value: 20}})
+
+	expectedStats := &PrometheusResponseStats{
+		Samples: &PrometheusResponseSamplesStats{
+			TotalQueryableSamples: 30,
+			TotalQueryableSamplesPerStep: []PrometheusResponseQueryableSamplesStatsPerStep{
+				{TimestampMs: 1000, Value: 10},
+				{TimestampMs: 2000, Value: 20},
+			},
+		},
+		Querier: &PrometheusResponseQuerierStats{
+			WallTimeSeconds:    2,
+			FetchedSeriesCount: 3,
+			ShardedQueries:     2,
+		},
+	}
+
+	codec := newTestPrometheusCodec()
+
+	for _, format := range []string{jsonMimeType, mimirpb.QueryResponseMimeType} {
+		t.Run(format, func(t *testing.T) {
+			for name, testCase := range map[string]struct {
+				ctx           context.Context
+				target        string
+				expectedStats *PrometheusResponseStats
+			}{
+				"stats requested": {
+					ctx:           ctx,
+					target:        "/api/v1/query?query=foo&stats=all",
+					expectedStats: expectedStats,
+				},
+				"stats requested without per-step statistics": {
+					ctx:    ctx,
+					target: "/api/v1/query?query=foo&stats=true",
+					expectedStats: &PrometheusResponseStats{
+						Samples: &PrometheusResponseSamplesStats{TotalQueryableSamples: 30},
+						Querier: expectedStats.Querier,
+					},
+				},
+				"stats not requested": {
+					ctx:    ctx,
+					target: "/api/v1/query?query=foo",
+				},
+				"stats requested but not enabled": {
+					ctx:    context.Background(),
+					target: "/api/v1/query?query=foo&stats=all",
+				},
+			} {
+				t.Run(name, func(t *testing.T) {
+					req, err := http.NewRequest(http.MethodGet, testCase.target, nil)
+					require.NoError(t, err)
+					req.Header.Set("Accept", format)
+
+					res := newResponse()
+					encodedResponse, err := codec.EncodeResponse(testCase.ctx, req, res)
+					require.NoError(t, err)
+					require.Nil(t, res.Data.Stats, "the encoded response should not be modified")
+
+					decodedResponse, err := codec.DecodeResponse(context.Background(), encodedResponse, nil, log.NewNopLogger())
+					require.NoError(t, err)
+					require.Equal(t, testCase.expectedStats, decodedResponse.(*PrometheusResponse).Data.Stats)
+				})
+			}
+		})
+	}
+
+	t.Run("JSON encoding is compatible with Prometheus", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/query?query=foo&stats=all", nil)
+		require.NoError(t, err)
+
+		encodedResponse, err := codec.EncodeResponse(ctx, req, newResponse())
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(encodedResponse.Body)
+		require.NoError(t, err)
+
+		res := struct {
+			Data struct {
+				Stats struct {
+					Samples struct {
+						TotalQueryableSamples        int64        `json:"totalQueryableSamples"`
+						TotalQueryableSamplesPerStep [][2]float64 `json:"totalQueryableSamplesPerStep"`
+					} `json:"samples"`
+				} `json:"stats"`
+			} `json:"data"`
+		}{}
+		require.NoError(t, json.Unmarshal(body, &res))
+		require.Equal(t, int64(30), res.Data.Stats.Samples.TotalQueryableSamples)
+		require.Equal(t, [][2]float64{{1, 10}, {2, 20}}, res.Data.Stats.Samples.TotalQueryableSamplesPerStep)
+	})
+}
+
 func TestPrometheusCodec_EncodeResponse_ContentNegotiation(t *testing.T) {
 	testResponse := &PrometheusResponse{
 		Status:    statusError,
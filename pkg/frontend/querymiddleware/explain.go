@@ -0,0 +1,349 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/mimir/pkg/querier/stats"
+	"github.com/grafana/mimir/pkg/streamingpromql"
+	"github.com/grafana/mimir/pkg/streamingpromql/compat"
+)
+
+const (
+	// explainParam is the request parameter used to ask the query-frontend to explain how it would run
+	// a query, instead of running it.
+	explainParam = "explain"
+
+	queryEnginePrometheus = "prometheus"
+	queryEngineMimir      = "mimir"
+
+	cacheLookupStatusHit     = "hit"
+	cacheLookupStatusPartial = "partial"
+	cacheLookupStatusMiss    = "miss"
+	cacheLookupStatusSkipped = "skipped"
+)
+
+// queryPlan records what the query middlewares did to a query run in explain (dry-run) mode.
+//
+// When a query is run in explain mode, the middlewares run as usual, except that:
+//   - no request is sent to queriers: each downstream request is recorded and an empty response is returned instead
+//   - nothing is written to the results cache
+//
+// All methods are safe to call concurrently.
+type queryPlan struct {
+	mtx sync.Mutex
+
+	Query              string                       `json:"query"`
+	Start              int64                        `json:"start"`
+	End                int64                        `json:"end"`
+	Step               int64                        `json:"step"`
+	Stages             []queryPlanStage             `json:"stages"`
+	Rewrites           []queryPlanRewrite           `json:"rewrites"`
+	CacheLookups       []queryPlanCacheLookup       `json:"cacheLookups"`
+	DownstreamRequests []queryPlanDownstreamRequest `json:"downstreamRequests"`
+
+	engineChooser *queryEngineChooser
+}
+
+// queryPlanStage is a request received by a named stage of the middleware chain.
+type queryPlanStage struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+	Step  int64  `json:"step"`
+}
+
+// queryPlanRewrite is a query rewritten by a middleware into a query with embedded queries.
+type queryPlanRewrite struct {
+	Stage          string `json:"stage"`
+	OriginalQuery  string `json:"originalQuery"`
+	RewrittenQuery string `json:"rewrittenQuery"`
+	Start          int64  `json:"start"`
+	End            int64  `json:"end"`
+	TotalShards    int    `json:"totalShards,omitempty"`
+	ShardedQueries int    `json:"shardedQueries,omitempty"`
+	SplitQueries   int    `json:"splitQueries,omitempty"`
+}
+
+// queryPlanCacheLookup is a lookup of the results cache for a split query.
+type queryPlanCacheLookup struct {
+	Key    string `json:"key,omitempty"`
+	Query  string `json:"query"`
+	Start  int64  `json:"start"`
+	End    int64  `json:"end"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// queryPlanDownstreamRequest is a request that would be sent to queriers.
+type queryPlanDownstreamRequest struct {
+	Query                string `json:"query"`
+	Start                int64  `json:"start"`
+	End                  int64  `json:"end"`
+	Step                 int64  `json:"step"`
+	Engine               string `json:"engine"`
+	EngineFallbackReason string `json:"engineFallbackReason,omitempty"`
+}
+
+type queryPlanContextKey int
+
+const queryPlanCtxKey = queryPlanContextKey(0)
+
+// contextWithQueryPlan returns a context with plan injected, to run the query middlewares in explain mode.
+func contextWithQueryPlan(ctx context.Context, plan *queryPlan) context.Context {
+	return context.WithValue(ctx, queryPlanCtxKey, plan)
+}
+
+// queryPlanFromContext returns the queryPlan in ctx, or nil if the query is not run in explain mode.
+func queryPlanFromContext(ctx context.Context) *queryPlan {
+	o := ctx.Value(queryPlanCtxKey)
+	if o == nil {
+		return nil
+	}
+	return o.(*queryPlan)
+}
+
+func (p *queryPlan) addStage(name string, req MetricsQueryRequest) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.Stages = append(p.Stages, queryPlanStage{
+		Name:  name,
+		Query: req.GetQuery(),
+		Start: req.GetStart(),
+		End:   req.GetEnd(),
+		Step:  req.GetStep(),
+	})
+}
+
+func (p *queryPlan) addRewrite(rewrite queryPlanRewrite) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.Rewrites = append(p.Rewrites, rewrite)
+}
+
+func (p *queryPlan) addCacheLookup(key string, req MetricsQueryRequest, status, reason string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.CacheLookups = append(p.CacheLookups, queryPlanCacheLookup{
+		Key:    key,
+		Query:  req.GetQuery(),
+		Start:  req.GetStart(),
+		End:    req.GetEnd(),
+		Status: status,
+		Reason: reason,
+	})
+}
+
+// dryRun records req as a downstream request and returns an empty response for it.
+func (p *queryPlan) dryRun(ctx context.Context, req MetricsQueryRequest) (Response, error) {
+	engine, fallbackReason := p.engineChooser.chooseEngine(ctx, req)
+
+	p.mtx.Lock()
+	p.DownstreamRequests = append(p.DownstreamRequests, queryPlanDownstreamRequest{
+		Query:                req.GetQuery(),
+		Start:                req.GetStart(),
+		End:                  req.GetEnd(),
+		Step:                 req.GetStep(),
+		Engine:               engine,
+		EngineFallbackReason: fallbackReason,
+	})
+	p.mtx.Unlock()
+
+	resultType := parser.ValueTypeMatrix
+	if _, ok := req.(*PrometheusInstantQueryRequest); ok {
+		resultType = parser.ValueTypeVector
+	}
+
+	return &PrometheusResponse{
+		Status: statusSuccess,
+		Data: &PrometheusData{
+			ResultType: string(resultType),
+			Result:     []SampleStream{},
+		},
+	}, nil
+}
+
+// sort sorts the entries recorded concurrently, so that the plan for a given query is always the same.
+func (p *queryPlan) sort() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	slices.SortStableFunc(p.CacheLookups, func(a, b queryPlanCacheLookup) int {
+		return compareQueryPlanEntries(a.Start, b.Start, a.Query, b.Query)
+	})
+	slices.SortStableFunc(p.DownstreamRequests, func(a, b queryPlanDownstreamRequest) int {
+		return compareQueryPlanEntries(a.Start, b.Start, a.Query, b.Query)
+	})
+}
+
+func compareQueryPlanEntries(aStart, bStart int64, aQuery, bQuery string) int {
+	if c := cmp.Compare(aStart, bStart); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(aQuery, bQuery)
+}
+
+// queryEngineChooser determines which engine queriers use to run a query.
+type queryEngineChooser struct {
+	engine          string
+	fallbackEnabled bool
+
+	// streamingEngine is only used to check if a query is supported by the Mimir query engine, never to run it.
+	streamingEngine promql.QueryEngine
+}
+
+func newQueryEngineChooser(cfg Config, engineOpts promql.EngineOpts, logger log.Logger) *queryEngineChooser {
+	c := &queryEngineChooser{
+		engine:          cfg.QueryEngine,
+		fallbackEnabled: cfg.EnableQueryEngineFallback,
+	}
+
+	if c.engine == "" {
+		c.engine = queryEnginePrometheus
+	}
+
+	if c.engine == queryEngineMimir {
+		// Don't register the engine metrics, as this engine never runs queries.
+		engineOpts.Reg = nil
+		engineOpts.ActiveQueryTracker = nil
+
+		streamingEngine, err := streamingpromql.NewEngine(engineOpts, streamingpromql.NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), logger)
+		if err == nil {
+			c.streamingEngine = streamingEngine
+		}
+	}
+
+	return c
+}
+
+// chooseEngine returns the name of the engine that queriers use to run req and, if queriers fall back to
+// Prometheus' engine because the query is not supported by the Mimir query engine, the reason for this.
+func (c *queryEngineChooser) chooseEngine(ctx context.Context, req MetricsQueryRequest) (string, string) {
+	if c == nil || c.engine != queryEngineMimir || c.streamingEngine == nil {
+		return queryEnginePrometheus, ""
+	}
+
+	var q promql.Query
+	var err error
+
+	switch req.(type) {
+	case *PrometheusInstantQueryRequest:
+		q, err = c.streamingEngine.NewInstantQuery(ctx, nil, nil, req.GetQuery(), timestamp.Time(req.GetStart()))
+	default:
+		q, err = c.streamingEngine.NewRangeQuery(ctx, nil, nil, req.GetQuery(), timestamp.Time(req.GetStart()), timestamp.Time(req.GetEnd()), time.Duration(req.GetStep())*time.Millisecond)
+	}
+
+	if err == nil {
+		q.Close()
+		return queryEngineMimir, ""
+	}
+
+	notSupportedErr := compat.NotSupportedError{}
+	if !errors.As(err, &notSupportedErr) {
+		// The query fails regardless of the engine, so report the configured one.
+		return queryEngineMimir, ""
+	}
+
+	if c.fallbackEnabled {
+		return queryEnginePrometheus, err.Error()
+	}
+
+	return queryEngineMimir, err.Error()
+}
+
+// queryExplainRoundTripper runs queries with the explain parameter set through next in explain mode,
+// and returns the resulting queryPlan instead of the query result.
+type queryExplainRoundTripper struct {
+	next          http.RoundTripper
+	engineChooser *queryEngineChooser
+}
+
+func newQueryExplainRoundTripper(next http.RoundTripper, engineChooser *queryEngineChooser) http.RoundTripper {
+	return &queryExplainRoundTripper{
+		next:          next,
+		engineChooser: engineChooser,
+	}
+}
+
+func (rt *queryExplainRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !isExplainRequest(r) {
+		return rt.next.RoundTrip(r)
+	}
+
+	plan := &queryPlan{engineChooser: rt.engineChooser}
+	ctx := contextWithQueryPlan(r.Context(), plan)
+
+	res, err := rt.next.RoundTrip(r.WithContext(ctx))
+	if err != nil || res.StatusCode/100 != 2 {
+		return res, err
+	}
+
+	// The response of a query run in explain mode is empty, so we can just discard it.
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+
+	return plan.encode()
+}
+
+func isExplainRequest(r *http.Request) bool {
+	explain, _ := strconv.ParseBool(requestFormValue(r, explainParam))
+	return explain
+}
+
+// setRequest populates the plan with the details of the request received by the query-frontend.
+func (p *queryPlan) setRequest(req MetricsQueryRequest) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.Query = req.GetQuery()
+	p.Start = req.GetStart()
+	p.End = req.GetEnd()
+	p.Step = req.GetStep()
+}
+
+func (p *queryPlan) encode() (*http.Response, error) {
+	p.sort()
+
+	p.mtx.Lock()
+	b, err := json.Marshal(struct {
+		Status string     `json:"status"`
+		Data   *queryPlan `json:"data"`
+	}{
+		Status: statusSuccess,
+		Data:   p,
+	})
+	p.mtx.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Header: http.Header{
+			"Content-Type": []string{jsonMimeType},
+		},
+		Body:          io.NopCloser(bytes.NewBuffer(b)),
+		StatusCode:    http.StatusOK,
+		ContentLength: int64(len(b)),
+	}, nil
+}
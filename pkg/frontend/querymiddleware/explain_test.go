@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/cache"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/querier/stats"
+)
+
+func TestTripperware_Explain(t *testing.T) {
+	const totalShards = 2
+
+	tw, err := NewTripperware(
+		makeTestConfig(func(cfg *Config) {
+			cfg.ShardedQueries = true
+			cfg.SplitQueriesByInterval = 24 * time.Hour
+			cfg.QueryEngine = queryEngineMimir
+			cfg.EnableQueryEngineFallback = true
+		}),
+		log.NewNopLogger(),
+		mockLimits{totalShards: totalShards},
+		newTestPrometheusCodec(),
+		nil,
+		promql.EngineOpts{
+			Logger:               log.NewNopLogger(),
+			Reg:                  nil,
+			MaxSamples:           1000,
+			Timeout:              time.Minute,
+			EnableAtModifier:     true,
+			EnableNegativeOffset: true,
+		},
+		true,
+		nil,
+	)
+	require.NoError(t, err)
+
+	downstreamCalls := 0
+	tripper := tw(RoundTripFunc(func(*http.Request) (*http.Response, error) {
+		downstreamCalls++
+		return nil, nil
+	}))
+
+	doExplain := func(t *testing.T, path string, params url.Values) *queryPlan {
+		params.Set("explain", "true")
+		req, err := http.NewRequest(http.MethodGet, path+"?"+params.Encode(), http.NoBody)
+		require.NoError(t, err)
+
+		ctx := user.InjectOrgID(context.Background(), "user-1")
+		req = req.WithContext(ctx)
+		require.NoError(t, user.InjectOrgIDIntoHTTPRequest(ctx, req))
+
+		resp, err := tripper.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, jsonMimeType, resp.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		res := struct {
+			Status string     `json:"status"`
+			Data   *queryPlan `json:"data"`
+		}{}
+		require.NoError(t, json.Unmarshal(body, &res))
+		require.Equal(t, statusSuccess, res.Status)
+
+		return res.Data
+	}
+
+	t.Run("range query split by interval and sharded", func(t *testing.T) {
+		start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		end := start.Add(24 * time.Hour)
+
+		plan := doExplain(t, "/api/v1/query_range", url.Values{
+			"query": []string{`sum(rate(metric[5m]))`},
+			"start": []string{start.Format(time.RFC3339)},
+			"end":   []string{end.Format(time.RFC3339)},
+			"step":  []string{"3600"},
+		})
+
+		assert.Equal(t, `sum(rate(metric[5m]))`, plan.Query)
+		assert.Equal(t, start.UnixMilli(), plan.Start)
+		assert.Equal(t, end.UnixMilli(), plan.End)
+		assert.Equal(t, time.Hour.Milliseconds(), plan.Step)
+
+		// The query is split into 2 days, and each split query is sharded.
+		require.Len(t, plan.Rewrites, 2)
+		for _, rewrite := range plan.Rewrites {
+			assert.Equal(t, "querysharding", rewrite.Stage)
+			assert.Equal(t, totalShards, rewrite.TotalShards)
+			assert.Contains(t, rewrite.RewrittenQuery, "__embedded_queries__")
+		}
+
+		require.Len(t, plan.DownstreamRequests, 2*totalShards)
+		for _, req := range plan.DownstreamRequests {
+			assert.Contains(t, req.Query, "__query_shard__")
+			assert.Equal(t, queryEngineMimir, req.Engine)
+			assert.Empty(t, req.EngineFallbackReason)
+		}
+
+		stageNames := map[string]int{}
+		for _, stage := range plan.Stages {
+			stageNames[stage.Name]++
+		}
+		assert.Equal(t, 1, stageNames["step_align"])
+		assert.Equal(t, 1, stageNames["split_by_interval_and_results_cache"])
+		assert.Equal(t, 2, stageNames["querysharding"])
+
+		// The results cache is disabled, so no lookups should be reported.
+		assert.Empty(t, plan.CacheLookups)
+	})
+
+	t.Run("instant query that is not shardable", func(t *testing.T) {
+		ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		plan := doExplain(t, "/api/v1/query", url.Values{
+			"query": []string{`metric`},
+			"time":  []string{ts.Format(time.RFC3339)},
+		})
+
+		assert.Equal(t, `metric`, plan.Query)
+		assert.Empty(t, plan.Rewrites)
+		require.Len(t, plan.DownstreamRequests, 1)
+		assert.Equal(t, queryPlanDownstreamRequest{
+			Query:  `metric`,
+			Start:  ts.UnixMilli(),
+			End:    ts.UnixMilli(),
+			Engine: queryEngineMimir,
+		}, plan.DownstreamRequests[0])
+	})
+
+	require.Equal(t, 0, downstreamCalls, "queries run in explain mode must not be sent downstream")
+}
+
+func TestQueryEngineChooser(t *testing.T) {
+	engineOpts := promql.EngineOpts{
+		Logger:               log.NewNopLogger(),
+		MaxSamples:           1000,
+		Timeout:              time.Minute,
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+	}
+
+	supportedReq := &PrometheusRangeQueryRequest{queryExpr: parseQuery(t, `sum(metric)`), start: 0, end: 60_000, step: 10_000}
+	unsupportedReq := &PrometheusInstantQueryRequest{queryExpr: parseQuery(t, `sort(metric)`), time: 60_000}
+
+	testCases := map[string]struct {
+		cfg                     Config
+		req                     MetricsQueryRequest
+		expectedEngine          string
+		expectedFallbackMessage bool
+	}{
+		"Prometheus' engine": {
+			cfg:            Config{QueryEngine: queryEnginePrometheus},
+			req:            unsupportedReq,
+			expectedEngine: queryEnginePrometheus,
+		},
+		"no engine configured": {
+			cfg:            Config{},
+			req:            supportedReq,
+			expectedEngine: queryEnginePrometheus,
+		},
+		"Mimir's engine, supported query": {
+			cfg:            Config{QueryEngine: queryEngineMimir, EnableQueryEngineFallback: true},
+			req:            supportedReq,
+			expectedEngine: queryEngineMimir,
+		},
+		"Mimir's engine, unsupported query, fallback enabled": {
+			cfg:                     Config{QueryEngine: queryEngineMimir, EnableQueryEngineFallback: true},
+			req:                     unsupportedReq,
+			expectedEngine:          queryEnginePrometheus,
+			expectedFallbackMessage: true,
+		},
+		"Mimir's engine, unsupported query, fallback disabled": {
+			cfg:                     Config{QueryEngine: queryEngineMimir, EnableQueryEngineFallback: false},
+			req:                     unsupportedReq,
+			expectedEngine:          queryEngineMimir,
+			expectedFallbackMessage: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			chooser := newQueryEngineChooser(testCase.cfg, engineOpts, log.NewNopLogger())
+			engine, reason := chooser.chooseEngine(context.Background(), testCase.req)
+			require.Equal(t, testCase.expectedEngine, engine)
+
+			if testCase.expectedFallbackMessage {
+				require.Contains(t, reason, "not supported")
+			} else {
+				require.Empty(t, reason)
+			}
+		})
+	}
+}
+
+func TestSplitAndCacheMiddleware_Explain(t *testing.T) {
+	cacheBackend := cache.NewInstrumentedMockCache()
+
+	mw := newSplitAndCacheMiddleware(
+		true,
+		true,
+		24*time.Hour,
+		mockLimits{maxCacheFreshness: 10 * time.Minute, resultsCacheTTL: resultsCacheTTL, resultsCacheOutOfOrderWindowTTL: resultsCacheLowerTTL},
+		newTestPrometheusCodec(),
+		cacheBackend,
+		DefaultCacheKeyGenerator{interval: day},
+		PrometheusResponseExtractor{},
+		resultsCacheAlwaysEnabled,
+		log.NewNopLogger(),
+		prometheus.NewPedanticRegistry(),
+	)
+
+	req := MetricsQueryRequest(&PrometheusRangeQueryRequest{
+		path:      "/api/v1/query_range",
+		start:     parseTimeRFC3339(t, "2021-10-15T10:00:00Z").Unix() * 1000,
+		end:       parseTimeRFC3339(t, "2021-10-15T12:00:00Z").Unix() * 1000,
+		step:      120 * 1000,
+		queryExpr: parseQuery(t, `{__name__=~".+"}`),
+	})
+
+	_, ctx := stats.ContextWithEmptyStats(context.Background())
+	ctx = user.InjectOrgID(ctx, "1")
+
+	run := func(t *testing.T, explain bool) *queryPlan {
+		ctx := ctx
+		var plan *queryPlan
+		if explain {
+			plan = &queryPlan{}
+			ctx = contextWithQueryPlan(ctx, plan)
+		}
+
+		handler := mw.Wrap(HandlerFunc(func(ctx context.Context, r MetricsQueryRequest) (Response, error) {
+			if plan != nil {
+				return plan.dryRun(ctx, r)
+			}
+
+			return &PrometheusResponse{Status: statusSuccess, Data: &PrometheusData{ResultType: "matrix", Result: []SampleStream{}}}, nil
+		}))
+
+		_, err := handler.Do(ctx, req)
+		require.NoError(t, err)
+
+		return plan
+	}
+
+	// Running the query in explain mode should report a cache miss, and not store anything in the cache.
+	plan := run(t, true)
+	require.Len(t, plan.CacheLookups, 1)
+	require.Equal(t, cacheLookupStatusMiss, plan.CacheLookups[0].Status)
+	require.NotEmpty(t, plan.CacheLookups[0].Key)
+	require.Len(t, plan.DownstreamRequests, 1)
+	require.Equal(t, 0, cacheBackend.CountStoreCalls())
+
+	// Running the query normally should populate the cache.
+	run(t, false)
+	require.Equal(t, 1, cacheBackend.CountStoreCalls())
+
+	// Running the query in explain mode again should report a cache hit.
+	plan = run(t, true)
+	require.Len(t, plan.CacheLookups, 1)
+	require.Equal(t, cacheLookupStatusHit, plan.CacheLookups[0].Status)
+	require.Empty(t, plan.DownstreamRequests)
+	require.Equal(t, 1, cacheBackend.CountStoreCalls())
+}
@@ -42,6 +42,10 @@ func newInstrumentMiddleware(name string, metrics *instrumentMiddlewareMetrics)
 }
 
 func (h *instrumentMiddleware) Do(ctx context.Context, req MetricsQueryRequest) (Response, error) {
+	if plan := queryPlanFromContext(ctx); plan != nil {
+		plan.addStage(h.name, req)
+	}
+
 	var resp Response
 	err := instrument.CollectedRequest(ctx, h.name, h.durationCol, instrument.ErrorCode, func(ctx context.Context) error {
 		sp := opentracing.SpanFromContext(ctx)
@@ -222,6 +222,11 @@ func (rt limitedParallelismRoundTripper) RoundTrip(r *http.Request) (*http.Respo
 		return nil, apierror.New(apierror.TypeBadData, err.Error())
 	}
 
+	plan := queryPlanFromContext(ctx)
+	if plan != nil {
+		plan.setRequest(request)
+	}
+
 	// Limit the amount of parallel sub-requests according to the MaxQueryParallelism tenant setting.
 	parallelism := validation.SmallestPositiveIntPerTenant(tenantIDs, rt.limits.MaxQueryParallelism)
 	sem := semaphore.NewWeighted(int64(parallelism))
@@ -236,6 +241,10 @@ func (rt limitedParallelismRoundTripper) RoundTrip(r *http.Request) (*http.Respo
 			}
 			defer sem.Release(1)
 
+			if plan != nil {
+				return plan.dryRun(ctx, r)
+			}
+
 			return rt.downstream.Do(ctx, r)
 		})).Do(ctx, request)
 	if err != nil {
@@ -7,6 +7,7 @@ package querymiddleware
 
 import (
 	bytes "bytes"
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
@@ -168,6 +169,8 @@ func (m *PrometheusResponse) GetWarnings() []string {
 type PrometheusData struct {
 	ResultType string         `protobuf:"bytes,1,opt,name=ResultType,proto3" json:"resultType"`
 	Result     []SampleStream `protobuf:"bytes,2,rep,name=Result,proto3" json:"result"`
+	// Stats are only set in the response sent by the query-frontend to the client, when requested with the stats parameter.
+	Stats *PrometheusResponseStats `protobuf:"bytes,3,opt,name=Stats,proto3" json:"stats,omitempty"`
 }
 
 func (m *PrometheusData) Reset()      { *m = PrometheusData{} }
@@ -216,6 +219,277 @@ func (m *PrometheusData) GetResult() []SampleStream {
 	return nil
 }
 
+func (m *PrometheusData) GetStats() *PrometheusResponseStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+type PrometheusResponseStats struct {
+	Samples *PrometheusResponseSamplesStats `protobuf:"bytes,1,opt,name=Samples,proto3" json:"samples"`
+	Querier *PrometheusResponseQuerierStats `protobuf:"bytes,2,opt,name=Querier,proto3" json:"querier"`
+}
+
+func (m *PrometheusResponseStats) Reset()      { *m = PrometheusResponseStats{} }
+func (*PrometheusResponseStats) ProtoMessage() {}
+func (*PrometheusResponseStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{3}
+}
+func (m *PrometheusResponseStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PrometheusResponseStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PrometheusResponseStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PrometheusResponseStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrometheusResponseStats.Merge(m, src)
+}
+func (m *PrometheusResponseStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *PrometheusResponseStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrometheusResponseStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrometheusResponseStats proto.InternalMessageInfo
+
+func (m *PrometheusResponseStats) GetSamples() *PrometheusResponseSamplesStats {
+	if m != nil {
+		return m.Samples
+	}
+	return nil
+}
+
+func (m *PrometheusResponseStats) GetQuerier() *PrometheusResponseQuerierStats {
+	if m != nil {
+		return m.Querier
+	}
+	return nil
+}
+
+type PrometheusResponseSamplesStats struct {
+	TotalQueryableSamples        int64                                            `protobuf:"varint,1,opt,name=TotalQueryableSamples,proto3" json:"totalQueryableSamples"`
+	TotalQueryableSamplesPerStep []PrometheusResponseQueryableSamplesStatsPerStep `protobuf:"bytes,2,rep,name=TotalQueryableSamplesPerStep,proto3" json:"totalQueryableSamplesPerStep,omitempty"`
+}
+
+func (m *PrometheusResponseSamplesStats) Reset()      { *m = PrometheusResponseSamplesStats{} }
+func (*PrometheusResponseSamplesStats) ProtoMessage() {}
+func (*PrometheusResponseSamplesStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{4}
+}
+func (m *PrometheusResponseSamplesStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PrometheusResponseSamplesStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PrometheusResponseSamplesStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PrometheusResponseSamplesStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrometheusResponseSamplesStats.Merge(m, src)
+}
+func (m *PrometheusResponseSamplesStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *PrometheusResponseSamplesStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrometheusResponseSamplesStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrometheusResponseSamplesStats proto.InternalMessageInfo
+
+func (m *PrometheusResponseSamplesStats) GetTotalQueryableSamples() int64 {
+	if m != nil {
+		return m.TotalQueryableSamples
+	}
+	return 0
+}
+
+func (m *PrometheusResponseSamplesStats) GetTotalQueryableSamplesPerStep() []PrometheusResponseQueryableSamplesStatsPerStep {
+	if m != nil {
+		return m.TotalQueryableSamplesPerStep
+	}
+	return nil
+}
+
+type PrometheusResponseQueryableSamplesStatsPerStep struct {
+	TimestampMs int64 `protobuf:"varint,1,opt,name=TimestampMs,proto3" json:"TimestampMs,omitempty"`
+	Value       int64 `protobuf:"varint,2,opt,name=Value,proto3" json:"Value,omitempty"`
+}
+
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) Reset() {
+	*m = PrometheusResponseQueryableSamplesStatsPerStep{}
+}
+func (*PrometheusResponseQueryableSamplesStatsPerStep) ProtoMessage() {}
+func (*PrometheusResponseQueryableSamplesStatsPerStep) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{5}
+}
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PrometheusResponseQueryableSamplesStatsPerStep.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrometheusResponseQueryableSamplesStatsPerStep.Merge(m, src)
+}
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) XXX_Size() int {
+	return m.Size()
+}
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrometheusResponseQueryableSamplesStatsPerStep.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrometheusResponseQueryableSamplesStatsPerStep proto.InternalMessageInfo
+
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) GetTimestampMs() int64 {
+	if m != nil {
+		return m.TimestampMs
+	}
+	return 0
+}
+
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+// PrometheusResponseQuerierStats holds the statistics tracked by queriers, merged for all the queries run to
+// execute a request.
+type PrometheusResponseQuerierStats struct {
+	WallTimeSeconds      float64 `protobuf:"fixed64,1,opt,name=WallTimeSeconds,proto3" json:"wallTimeSeconds"`
+	FetchedSeriesCount   uint64  `protobuf:"varint,2,opt,name=FetchedSeriesCount,proto3" json:"fetchedSeriesCount"`
+	FetchedChunkBytes    uint64  `protobuf:"varint,3,opt,name=FetchedChunkBytes,proto3" json:"fetchedChunkBytes"`
+	FetchedChunksCount   uint64  `protobuf:"varint,4,opt,name=FetchedChunksCount,proto3" json:"fetchedChunksCount"`
+	FetchedIndexBytes    uint64  `protobuf:"varint,5,opt,name=FetchedIndexBytes,proto3" json:"fetchedIndexBytes"`
+	ShardedQueries       uint32  `protobuf:"varint,6,opt,name=ShardedQueries,proto3" json:"shardedQueries"`
+	SplitQueries         uint32  `protobuf:"varint,7,opt,name=SplitQueries,proto3" json:"splitQueries"`
+	EstimatedSeriesCount uint64  `protobuf:"varint,8,opt,name=EstimatedSeriesCount,proto3" json:"estimatedSeriesCount"`
+	QueueTimeSeconds     float64 `protobuf:"fixed64,9,opt,name=QueueTimeSeconds,proto3" json:"queueTimeSeconds"`
+}
+
+func (m *PrometheusResponseQuerierStats) Reset()      { *m = PrometheusResponseQuerierStats{} }
+func (*PrometheusResponseQuerierStats) ProtoMessage() {}
+func (*PrometheusResponseQuerierStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{6}
+}
+func (m *PrometheusResponseQuerierStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PrometheusResponseQuerierStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PrometheusResponseQuerierStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PrometheusResponseQuerierStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrometheusResponseQuerierStats.Merge(m, src)
+}
+func (m *PrometheusResponseQuerierStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *PrometheusResponseQuerierStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrometheusResponseQuerierStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrometheusResponseQuerierStats proto.InternalMessageInfo
+
+func (m *PrometheusResponseQuerierStats) GetWallTimeSeconds() float64 {
+	if m != nil {
+		return m.WallTimeSeconds
+	}
+	return 0
+}
+
+func (m *PrometheusResponseQuerierStats) GetFetchedSeriesCount() uint64 {
+	if m != nil {
+		return m.FetchedSeriesCount
+	}
+	return 0
+}
+
+func (m *PrometheusResponseQuerierStats) GetFetchedChunkBytes() uint64 {
+	if m != nil {
+		return m.FetchedChunkBytes
+	}
+	return 0
+}
+
+func (m *PrometheusResponseQuerierStats) GetFetchedChunksCount() uint64 {
+	if m != nil {
+		return m.FetchedChunksCount
+	}
+	return 0
+}
+
+func (m *PrometheusResponseQuerierStats) GetFetchedIndexBytes() uint64 {
+	if m != nil {
+		return m.FetchedIndexBytes
+	}
+	return 0
+}
+
+func (m *PrometheusResponseQuerierStats) GetShardedQueries() uint32 {
+	if m != nil {
+		return m.ShardedQueries
+	}
+	return 0
+}
+
+func (m *PrometheusResponseQuerierStats) GetSplitQueries() uint32 {
+	if m != nil {
+		return m.SplitQueries
+	}
+	return 0
+}
+
+func (m *PrometheusResponseQuerierStats) GetEstimatedSeriesCount() uint64 {
+	if m != nil {
+		return m.EstimatedSeriesCount
+	}
+	return 0
+}
+
+func (m *PrometheusResponseQuerierStats) GetQueueTimeSeconds() float64 {
+	if m != nil {
+		return m.QueueTimeSeconds
+	}
+	return 0
+}
+
 type SampleStream struct {
 	Labels     []github_com_grafana_mimir_pkg_mimirpb.LabelAdapter `protobuf:"bytes,1,rep,name=labels,proto3,customtype=github.com/grafana/mimir/pkg/mimirpb.LabelAdapter" json:"metric"`
 	Samples    []mimirpb.Sample                                    `protobuf:"bytes,2,rep,name=samples,proto3" json:"values"`
@@ -225,7 +499,7 @@ type SampleStream struct {
 func (m *SampleStream) Reset()      { *m = SampleStream{} }
 func (*SampleStream) ProtoMessage() {}
 func (*SampleStream) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{3}
+	return fileDescriptor_4c16552f9fdb66d8, []int{7}
 }
 func (m *SampleStream) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -277,7 +551,7 @@ type CachedResponse struct {
 func (m *CachedResponse) Reset()      { *m = CachedResponse{} }
 func (*CachedResponse) ProtoMessage() {}
 func (*CachedResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{4}
+	return fileDescriptor_4c16552f9fdb66d8, []int{8}
 }
 func (m *CachedResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -329,12 +603,15 @@ type Extent struct {
 	// If the response is combination of multiple queries over time, all of which had timestamp set, this is the timestamp of oldest query.
 	// When merging extents and some of them have 0 query timestamp, we keep non-zero timestamp, if possible.
 	QueryTimestampMs int64 `protobuf:"varint,6,opt,name=query_timestamp_ms,json=queryTimestampMs,proto3" json:"query_timestamp_ms,omitempty"`
+	// The number of samples processed by the PromQL engine at each step of the cached response, sorted by timestamp.
+	// Used to include the statistics of the cached responses in the statistics of a query.
+	SamplesProcessedPerStep []PrometheusResponseQueryableSamplesStatsPerStep `protobuf:"bytes,7,rep,name=samples_processed_per_step,json=samplesProcessedPerStep,proto3" json:"samples_processed_per_step"`
 }
 
 func (m *Extent) Reset()      { *m = Extent{} }
 func (*Extent) ProtoMessage() {}
 func (*Extent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{5}
+	return fileDescriptor_4c16552f9fdb66d8, []int{9}
 }
 func (m *Extent) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -398,6 +675,13 @@ func (m *Extent) GetQueryTimestampMs() int64 {
 	return 0
 }
 
+func (m *Extent) GetSamplesProcessedPerStep() []PrometheusResponseQueryableSamplesStatsPerStep {
+	if m != nil {
+		return m.SamplesProcessedPerStep
+	}
+	return nil
+}
+
 type Options struct {
 	CacheDisabled        bool  `protobuf:"varint,1,opt,name=CacheDisabled,proto3" json:"CacheDisabled,omitempty"`
 	ShardingDisabled     bool  `protobuf:"varint,2,opt,name=ShardingDisabled,proto3" json:"ShardingDisabled,omitempty"`
@@ -405,12 +689,14 @@ type Options struct {
 	InstantSplitDisabled bool  `protobuf:"varint,4,opt,name=InstantSplitDisabled,proto3" json:"InstantSplitDisabled,omitempty"`
 	// Instant split by time interval unit stored in nanoseconds (time.Duration unit in int64)
 	InstantSplitInterval int64 `protobuf:"varint,5,opt,name=InstantSplitInterval,proto3" json:"InstantSplitInterval,omitempty"`
+	// The value of the stats parameter of the request, used to request query statistics in the response.
+	Stats string `protobuf:"bytes,6,opt,name=Stats,proto3" json:"Stats,omitempty"`
 }
 
 func (m *Options) Reset()      { *m = Options{} }
 func (*Options) ProtoMessage() {}
 func (*Options) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{6}
+	return fileDescriptor_4c16552f9fdb66d8, []int{10}
 }
 func (m *Options) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -474,6 +760,13 @@ func (m *Options) GetInstantSplitInterval() int64 {
 	return 0
 }
 
+func (m *Options) GetStats() string {
+	if m != nil {
+		return m.Stats
+	}
+	return ""
+}
+
 type QueryStatistics struct {
 	EstimatedSeriesCount uint64 `protobuf:"varint,1,opt,name=EstimatedSeriesCount,proto3" json:"EstimatedSeriesCount,omitempty"`
 }
@@ -481,7 +774,7 @@ type QueryStatistics struct {
 func (m *QueryStatistics) Reset()      { *m = QueryStatistics{} }
 func (*QueryStatistics) ProtoMessage() {}
 func (*QueryStatistics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{7}
+	return fileDescriptor_4c16552f9fdb66d8, []int{11}
 }
 func (m *QueryStatistics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -533,7 +826,7 @@ type CachedHTTPResponse struct {
 func (m *CachedHTTPResponse) Reset()      { *m = CachedHTTPResponse{} }
 func (*CachedHTTPResponse) ProtoMessage() {}
 func (*CachedHTTPResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{8}
+	return fileDescriptor_4c16552f9fdb66d8, []int{12}
 }
 func (m *CachedHTTPResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -599,7 +892,7 @@ type CachedHTTPHeader struct {
 func (m *CachedHTTPHeader) Reset()      { *m = CachedHTTPHeader{} }
 func (*CachedHTTPHeader) ProtoMessage() {}
 func (*CachedHTTPHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{9}
+	return fileDescriptor_4c16552f9fdb66d8, []int{13}
 }
 func (m *CachedHTTPHeader) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -646,6 +939,10 @@ func init() {
 	proto.RegisterType((*PrometheusHeader)(nil), "queryrange.PrometheusHeader")
 	proto.RegisterType((*PrometheusResponse)(nil), "queryrange.PrometheusResponse")
 	proto.RegisterType((*PrometheusData)(nil), "queryrange.PrometheusData")
+	proto.RegisterType((*PrometheusResponseStats)(nil), "queryrange.PrometheusResponseStats")
+	proto.RegisterType((*PrometheusResponseSamplesStats)(nil), "queryrange.PrometheusResponseSamplesStats")
+	proto.RegisterType((*PrometheusResponseQueryableSamplesStatsPerStep)(nil), "queryrange.PrometheusResponseQueryableSamplesStatsPerStep")
+	proto.RegisterType((*PrometheusResponseQuerierStats)(nil), "queryrange.PrometheusResponseQuerierStats")
 	proto.RegisterType((*SampleStream)(nil), "queryrange.SampleStream")
 	proto.RegisterType((*CachedResponse)(nil), "queryrange.CachedResponse")
 	proto.RegisterType((*Extent)(nil), "queryrange.Extent")
@@ -658,70 +955,95 @@ func init() {
 func init() { proto.RegisterFile("model.proto", fileDescriptor_4c16552f9fdb66d8) }
 
 var fileDescriptor_4c16552f9fdb66d8 = []byte{
-	// 998 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0x41, 0x6f, 0x1b, 0xc5,
-	0x17, 0xf7, 0x66, 0x6d, 0xc7, 0x79, 0xee, 0x3f, 0xb1, 0xa6, 0xd1, 0x1f, 0x27, 0xc0, 0xae, 0xb5,
-	0xe2, 0x10, 0x50, 0xeb, 0x40, 0x10, 0x1c, 0x10, 0x20, 0xea, 0x34, 0x28, 0x81, 0x02, 0x61, 0x1c,
-	0x81, 0xc4, 0x25, 0x1a, 0x7b, 0xa7, 0xf6, 0xd2, 0xdd, 0x9d, 0x65, 0x66, 0xdc, 0xd6, 0x37, 0xc4,
-	0x07, 0x40, 0x7c, 0x02, 0xce, 0x7c, 0x94, 0x1e, 0xc3, 0xad, 0xea, 0x61, 0x45, 0x1c, 0x21, 0x21,
-	0x9f, 0xfa, 0x11, 0xd0, 0xbe, 0xd9, 0x5d, 0x6f, 0xdb, 0x1c, 0xb8, 0x78, 0x67, 0x7e, 0xef, 0xf7,
-	0x7b, 0xef, 0xcd, 0x9b, 0x37, 0xcf, 0xd0, 0x8e, 0x84, 0xcf, 0xc3, 0x7e, 0x22, 0x85, 0x16, 0x04,
-	0x7e, 0x9a, 0x71, 0x39, 0x97, 0x2c, 0x9e, 0xf0, 0xdd, 0xdb, 0x93, 0x40, 0x4f, 0x67, 0xa3, 0xfe,
-	0x58, 0x44, 0xfb, 0x13, 0x31, 0x11, 0xfb, 0x48, 0x19, 0xcd, 0xee, 0xe3, 0x0e, 0x37, 0xb8, 0x32,
-	0xd2, 0xdd, 0x77, 0xab, 0x74, 0xc9, 0xee, 0xb3, 0x98, 0xed, 0x47, 0x41, 0x14, 0xc8, 0xfd, 0xe4,
-	0xc1, 0xc4, 0xac, 0x92, 0x91, 0xf9, 0xe6, 0x8a, 0x9d, 0x89, 0x10, 0x93, 0x90, 0xaf, 0xfc, 0xb2,
-	0x78, 0x6e, 0x4c, 0xde, 0x3d, 0xe8, 0x9c, 0x4a, 0x11, 0x71, 0x3d, 0xe5, 0x33, 0x75, 0xcc, 0x99,
-	0xcf, 0x25, 0xd9, 0x81, 0xfa, 0xd7, 0x2c, 0xe2, 0x5d, 0xab, 0x67, 0xed, 0x6d, 0x0c, 0x1a, 0xcb,
-	0xd4, 0xb5, 0x6e, 0x53, 0x84, 0xc8, 0x9b, 0xd0, 0xfc, 0x8e, 0x85, 0x33, 0xae, 0xba, 0x6b, 0x3d,
-	0x7b, 0x65, 0xcc, 0x41, 0xef, 0xcf, 0x35, 0x20, 0x2b, 0x77, 0x94, 0xab, 0x44, 0xc4, 0x8a, 0x13,
-	0x0f, 0x9a, 0x43, 0xcd, 0xf4, 0x4c, 0xe5, 0x2e, 0x61, 0x99, 0xba, 0x4d, 0x85, 0x08, 0xcd, 0x2d,
-	0x64, 0x00, 0xf5, 0xbb, 0x4c, 0xb3, 0xee, 0x5a, 0xcf, 0xda, 0x6b, 0x1f, 0xec, 0xf6, 0x57, 0xf5,
-	0xe9, 0xaf, 0x3c, 0x66, 0x8c, 0x01, 0x59, 0xa6, 0xee, 0xa6, 0xcf, 0x34, 0xbb, 0x25, 0xa2, 0x40,
-	0xf3, 0x28, 0xd1, 0x73, 0x8a, 0x5a, 0xf2, 0x01, 0x6c, 0x1c, 0x49, 0x29, 0xe4, 0xd9, 0x3c, 0xe1,
-	0x5d, 0x1b, 0x43, 0xbd, 0xb6, 0x4c, 0xdd, 0x9b, 0xbc, 0x00, 0x2b, 0x8a, 0x15, 0x93, 0xbc, 0x0d,
-	0x0d, 0xdc, 0x74, 0xeb, 0x28, 0xb9, 0xb9, 0x4c, 0xdd, 0x2d, 0x94, 0x54, 0xe8, 0x86, 0x41, 0x3e,
-	0x81, 0x75, 0x53, 0x24, 0xd5, 0x6d, 0xf4, 0xec, 0xbd, 0xf6, 0xc1, 0x1b, 0xd7, 0x27, 0x6a, 0x48,
-	0x45, 0x79, 0x0a, 0x0d, 0x39, 0x80, 0xd6, 0xf7, 0x4c, 0xc6, 0x41, 0x3c, 0x51, 0xdd, 0x26, 0x16,
-	0xf0, 0xff, 0xcb, 0xd4, 0x25, 0x8f, 0x72, 0xac, 0x12, 0xaf, 0xe4, 0x79, 0xbf, 0x58, 0xb0, 0xf9,
-	0x62, 0x05, 0x48, 0x1f, 0x80, 0x72, 0x35, 0x0b, 0x35, 0x1e, 0xd4, 0xd4, 0x74, 0x73, 0x99, 0xba,
-	0x20, 0x4b, 0x94, 0x56, 0x18, 0xe4, 0x33, 0x68, 0x9a, 0x1d, 0xde, 0x5a, 0xfb, 0xa0, 0x5b, 0x4d,
-	0x7a, 0xc8, 0xa2, 0x24, 0xe4, 0x43, 0x2d, 0x39, 0x8b, 0x06, 0x9b, 0x4f, 0x52, 0xb7, 0x96, 0xdd,
-	0x8e, 0xf1, 0x44, 0x73, 0x9d, 0xf7, 0xeb, 0x1a, 0xdc, 0xa8, 0x12, 0x49, 0x02, 0xcd, 0x90, 0x8d,
-	0x78, 0x98, 0x5d, 0x69, 0xe6, 0xf2, 0x66, 0x7f, 0x2c, 0xa4, 0xe6, 0x8f, 0x93, 0x51, 0xff, 0x5e,
-	0x86, 0x9f, 0xb2, 0x40, 0x0e, 0x0e, 0x33, 0x6f, 0xcf, 0x52, 0xf7, 0xbd, 0xff, 0xd2, 0xb1, 0x46,
-	0x77, 0xc7, 0x67, 0x89, 0xe6, 0x32, 0x4b, 0x21, 0xe2, 0x5a, 0x06, 0x63, 0x9a, 0xc7, 0x21, 0x1f,
-	0xc1, 0xba, 0xc2, 0x0c, 0x54, 0x7e, 0x8a, 0xce, 0x2a, 0xa4, 0x49, 0x6d, 0x95, 0xfd, 0x43, 0x6c,
-	0x47, 0x5a, 0x08, 0xc8, 0x29, 0xc0, 0x34, 0x50, 0x5a, 0x4c, 0x24, 0x8b, 0x54, 0xd7, 0xce, 0x6f,
-	0xae, 0x94, 0x7f, 0x1e, 0x0a, 0xa6, 0x8f, 0x0b, 0x02, 0xa6, 0x4e, 0x72, 0x57, 0x15, 0x1d, 0xad,
-	0xac, 0xbd, 0x1f, 0x61, 0xf3, 0x90, 0x8d, 0xa7, 0xdc, 0x2f, 0x9b, 0x7c, 0x07, 0xec, 0x07, 0x7c,
-	0x9e, 0xdf, 0xc6, 0xfa, 0x32, 0x75, 0xb3, 0x2d, 0xcd, 0x7e, 0xb2, 0xae, 0xe1, 0x8f, 0x35, 0x8f,
-	0x75, 0x91, 0x3a, 0xa9, 0x5e, 0xc0, 0x11, 0x9a, 0x06, 0x5b, 0x79, 0xc4, 0x82, 0x4a, 0x8b, 0x85,
-	0xf7, 0xcc, 0x82, 0xa6, 0x21, 0x11, 0x17, 0x1a, 0x4a, 0x33, 0xa9, 0x31, 0x8c, 0x3d, 0xd8, 0x58,
-	0xa6, 0xae, 0x01, 0xa8, 0xf9, 0x64, 0x59, 0xf0, 0xd8, 0xc7, 0x57, 0x64, 0x9b, 0x2c, 0x78, 0xec,
-	0xd3, 0xec, 0x87, 0xf4, 0xa0, 0xa5, 0x25, 0x1b, 0xf3, 0xf3, 0xc0, 0xcf, 0x3b, 0xbd, 0x68, 0x4f,
-	0x84, 0x4f, 0x7c, 0xf2, 0x29, 0xb4, 0x64, 0x7e, 0x9c, 0x6e, 0x03, 0xdf, 0xe1, 0x76, 0xdf, 0x8c,
-	0x8e, 0x7e, 0x31, 0x3a, 0xfa, 0x77, 0xe2, 0xf9, 0xe0, 0xc6, 0x32, 0x75, 0x4b, 0x26, 0x2d, 0x57,
-	0xe4, 0x16, 0x10, 0x3c, 0xd7, 0xb9, 0x0e, 0x22, 0xae, 0x34, 0x8b, 0x92, 0xf3, 0x28, 0x6b, 0x74,
-	0x6b, 0xcf, 0xa6, 0x1d, 0xb4, 0x9c, 0x15, 0x86, 0xaf, 0xd4, 0x17, 0xf5, 0x96, 0xdd, 0xa9, 0x7b,
-	0x7f, 0x5b, 0xb0, 0xfe, 0x4d, 0xa2, 0x03, 0x11, 0x2b, 0xf2, 0x16, 0xfc, 0x0f, 0x8b, 0x7a, 0x37,
-	0x50, 0x6c, 0x14, 0x72, 0x1f, 0x4f, 0xd9, 0xa2, 0x2f, 0x82, 0xe4, 0x1d, 0xe8, 0x0c, 0xa7, 0x4c,
-	0xfa, 0x41, 0x3c, 0x29, 0x89, 0x6b, 0x48, 0x7c, 0x05, 0x27, 0x3d, 0x68, 0x9f, 0x09, 0xcd, 0x42,
-	0x34, 0x28, 0x9c, 0x09, 0x0d, 0x5a, 0x85, 0xc8, 0x01, 0x6c, 0x9f, 0xc4, 0x4a, 0xb3, 0x58, 0x0f,
-	0x93, 0x30, 0xd0, 0xa5, 0xc7, 0x3a, 0x7a, 0xbc, 0xd6, 0xf6, 0xb2, 0xe6, 0x24, 0xd6, 0x5c, 0x3e,
-	0x64, 0x21, 0xd6, 0xcc, 0xa6, 0xd7, 0xda, 0xbc, 0x23, 0xd8, 0xfa, 0x36, 0xab, 0x40, 0x36, 0xee,
-	0x02, 0xa5, 0x83, 0x31, 0x86, 0x3e, 0x52, 0x3a, 0x88, 0x98, 0xe6, 0xfe, 0x90, 0xcb, 0x80, 0xab,
-	0x43, 0x31, 0x8b, 0xcd, 0xdd, 0xd6, 0xe9, 0xb5, 0x36, 0xef, 0x77, 0x0b, 0x88, 0x69, 0xbc, 0xe3,
-	0xb3, 0xb3, 0xd3, 0xb2, 0xf9, 0x5e, 0x87, 0x8d, 0x71, 0x86, 0x9e, 0x97, 0x2d, 0x48, 0x5b, 0x08,
-	0x7c, 0xc9, 0xe7, 0xc4, 0x85, 0xb6, 0x19, 0xb6, 0xe7, 0x63, 0xe1, 0x73, 0xac, 0x55, 0x83, 0x82,
-	0x81, 0x0e, 0x85, 0xcf, 0xc9, 0x87, 0xb0, 0x3e, 0xcd, 0xa7, 0x9a, 0xfd, 0xea, 0x54, 0x5b, 0x85,
-	0x33, 0x63, 0x8c, 0x16, 0x64, 0x42, 0xa0, 0x3e, 0x12, 0xfe, 0x1c, 0x6b, 0x75, 0x83, 0xe2, 0xda,
-	0xfb, 0x18, 0x3a, 0x2f, 0x0b, 0x32, 0x5e, 0x5c, 0xfe, 0xa1, 0x50, 0x5c, 0x93, 0x6d, 0x68, 0xe0,
-	0x2b, 0xc5, 0x74, 0x36, 0xa8, 0xd9, 0x0c, 0x8e, 0x2e, 0x2e, 0x9d, 0xda, 0xd3, 0x4b, 0xa7, 0xf6,
-	0xfc, 0xd2, 0xb1, 0x7e, 0x5e, 0x38, 0xd6, 0x1f, 0x0b, 0xc7, 0x7a, 0xb2, 0x70, 0xac, 0x8b, 0x85,
-	0x63, 0xfd, 0xb5, 0x70, 0xac, 0x7f, 0x16, 0x4e, 0xed, 0xf9, 0xc2, 0xb1, 0x7e, 0xbb, 0x72, 0x6a,
-	0x17, 0x57, 0x4e, 0xed, 0xe9, 0x95, 0x53, 0xfb, 0x61, 0x0b, 0xb3, 0x8d, 0x02, 0xdf, 0x0f, 0xf9,
-	0x23, 0x26, 0xf9, 0xa8, 0x89, 0xed, 0xfa, 0xfe, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x00, 0xcd,
-	0x0d, 0xa4, 0x73, 0x07, 0x00, 0x00,
+	// 1402 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0x4f, 0x6f, 0x1b, 0x45,
+	0x14, 0xcf, 0xfa, 0x6f, 0xf2, 0x9c, 0x26, 0xee, 0x34, 0xa5, 0x4e, 0x28, 0xbb, 0xd1, 0x82, 0x50,
+	0xa8, 0x5a, 0x07, 0xcc, 0x9f, 0x43, 0x45, 0x51, 0xeb, 0x34, 0x55, 0x03, 0xa5, 0x4d, 0xc7, 0x11,
+	0x95, 0xb8, 0x58, 0x63, 0xef, 0xc4, 0x5e, 0xba, 0xff, 0x3a, 0x3b, 0x6e, 0xeb, 0x03, 0x12, 0x9f,
+	0x00, 0xf1, 0x09, 0x38, 0x70, 0xe2, 0xc4, 0x57, 0xe0, 0xda, 0x63, 0xe1, 0x54, 0x38, 0xac, 0xa8,
+	0x7b, 0x41, 0x7b, 0xea, 0xbd, 0x17, 0x34, 0x33, 0xbb, 0xf6, 0x26, 0x5e, 0xda, 0x22, 0x2e, 0xf1,
+	0xce, 0x7b, 0xbf, 0xdf, 0x7b, 0x6f, 0xde, 0x9b, 0xf7, 0x66, 0x02, 0x35, 0xd7, 0xb7, 0xa8, 0xd3,
+	0x0c, 0x98, 0xcf, 0x7d, 0x04, 0xf7, 0x46, 0x94, 0x8d, 0x19, 0xf1, 0x06, 0x74, 0xe3, 0xc2, 0xc0,
+	0xe6, 0xc3, 0x51, 0xaf, 0xd9, 0xf7, 0xdd, 0xed, 0x81, 0x3f, 0xf0, 0xb7, 0x25, 0xa4, 0x37, 0x3a,
+	0x94, 0x2b, 0xb9, 0x90, 0x5f, 0x8a, 0xba, 0xf1, 0x7e, 0x16, 0xce, 0xc8, 0x21, 0xf1, 0xc8, 0xb6,
+	0x6b, 0xbb, 0x36, 0xdb, 0x0e, 0xee, 0x0e, 0xd4, 0x57, 0xd0, 0x53, 0xbf, 0x09, 0x63, 0x7d, 0xe0,
+	0xfb, 0x03, 0x87, 0xce, 0xec, 0x12, 0x6f, 0xac, 0x54, 0xe6, 0x0d, 0xa8, 0xef, 0x33, 0xdf, 0xa5,
+	0x7c, 0x48, 0x47, 0xe1, 0x75, 0x4a, 0x2c, 0xca, 0xd0, 0x3a, 0x94, 0x6e, 0x12, 0x97, 0x36, 0xb4,
+	0x4d, 0x6d, 0x6b, 0xa9, 0x5d, 0x8e, 0x23, 0x43, 0xbb, 0x80, 0xa5, 0x08, 0xbd, 0x05, 0x95, 0xaf,
+	0x88, 0x33, 0xa2, 0x61, 0xa3, 0xb0, 0x59, 0x9c, 0x29, 0x13, 0xa1, 0xf9, 0x5b, 0x01, 0xd0, 0xcc,
+	0x1c, 0xa6, 0x61, 0xe0, 0x7b, 0x21, 0x45, 0x26, 0x54, 0x3a, 0x9c, 0xf0, 0x51, 0x98, 0x98, 0x84,
+	0x38, 0x32, 0x2a, 0xa1, 0x94, 0xe0, 0x44, 0x83, 0xda, 0x50, 0xba, 0x4a, 0x38, 0x69, 0x14, 0x36,
+	0xb5, 0xad, 0x5a, 0x6b, 0xa3, 0x39, 0xcb, 0x4f, 0x73, 0x66, 0x51, 0x20, 0xda, 0x28, 0x8e, 0x8c,
+	0x15, 0x8b, 0x70, 0x72, 0xde, 0x77, 0x6d, 0x4e, 0xdd, 0x80, 0x8f, 0xb1, 0xe4, 0xa2, 0x8f, 0x61,
+	0x69, 0x97, 0x31, 0x9f, 0x1d, 0x8c, 0x03, 0xda, 0x28, 0x4a, 0x57, 0x67, 0xe2, 0xc8, 0x38, 0x45,
+	0x53, 0x61, 0x86, 0x31, 0x43, 0xa2, 0xf7, 0xa0, 0x2c, 0x17, 0x8d, 0x92, 0xa4, 0x9c, 0x8a, 0x23,
+	0x63, 0x55, 0x52, 0x32, 0x70, 0x85, 0x40, 0x97, 0xa0, 0xaa, 0x92, 0x14, 0x36, 0xca, 0x9b, 0xc5,
+	0xad, 0x5a, 0xeb, 0x6c, 0x7e, 0xa0, 0x0a, 0x94, 0xa6, 0x27, 0xe5, 0xa0, 0x16, 0x2c, 0xde, 0x21,
+	0xcc, 0xb3, 0xbd, 0x41, 0xd8, 0xa8, 0xc8, 0x04, 0xbe, 0x11, 0x47, 0x06, 0x7a, 0x90, 0xc8, 0x32,
+	0xfe, 0xa6, 0x38, 0xf3, 0x77, 0x0d, 0x56, 0x8e, 0x66, 0x00, 0x35, 0x01, 0x30, 0x0d, 0x47, 0x0e,
+	0x97, 0x1b, 0x55, 0x39, 0x5d, 0x89, 0x23, 0x03, 0xd8, 0x54, 0x8a, 0x33, 0x08, 0x74, 0x19, 0x2a,
+	0x6a, 0x25, 0xab, 0x56, 0x6b, 0x35, 0xb2, 0x41, 0x77, 0x88, 0x1b, 0x38, 0xb4, 0xc3, 0x19, 0x25,
+	0x6e, 0x7b, 0xe5, 0x51, 0x64, 0x2c, 0x88, 0xea, 0x28, 0x4b, 0x38, 0xe1, 0xa1, 0x9b, 0x50, 0x16,
+	0x75, 0x0a, 0x65, 0x56, 0x6b, 0xad, 0xb7, 0xf3, 0x77, 0x9d, 0x16, 0x5c, 0x42, 0x55, 0x1e, 0x45,
+	0x95, 0xb3, 0xfb, 0x52, 0x66, 0xcc, 0x5f, 0x35, 0x38, 0xf3, 0x2f, 0x3c, 0x74, 0x1b, 0xaa, 0x2a,
+	0x26, 0x75, 0x5c, 0x6a, 0xad, 0x73, 0xaf, 0xf0, 0xa6, 0xc0, 0xca, 0x69, 0x2d, 0x8e, 0x8c, 0x6a,
+	0xa8, 0x24, 0x38, 0xb5, 0x23, 0x4c, 0xde, 0x1e, 0x51, 0x66, 0x53, 0xd6, 0x28, 0xbc, 0x8e, 0xc9,
+	0x04, 0x9c, 0x31, 0x79, 0x4f, 0x49, 0x70, 0x6a, 0xc7, 0xfc, 0xa9, 0x00, 0xfa, 0xcb, 0x63, 0x41,
+	0xb7, 0xe0, 0xf4, 0x81, 0xcf, 0x89, 0x23, 0x28, 0x63, 0xd2, 0x73, 0x68, 0x76, 0x5b, 0xc5, 0xf6,
+	0x7a, 0x1c, 0x19, 0xa7, 0x79, 0x1e, 0x00, 0xe7, 0xf3, 0xd0, 0x2f, 0x1a, 0x9c, 0xcd, 0xd5, 0xec,
+	0x8b, 0x58, 0x69, 0x90, 0x94, 0xf7, 0xe2, 0xab, 0x37, 0x97, 0x25, 0xcb, 0x60, 0x13, 0x0b, 0xed,
+	0x66, 0x72, 0x00, 0xde, 0xe5, 0x2f, 0xf1, 0x93, 0xa9, 0xe7, 0x4b, 0xe3, 0x31, 0x87, 0xf0, 0x1f,
+	0xfd, 0xa3, 0x4d, 0xa8, 0x1d, 0xd8, 0x2e, 0x0d, 0x39, 0x71, 0x83, 0x2f, 0x93, 0x4c, 0xe1, 0xac,
+	0x08, 0xad, 0x41, 0x59, 0x4e, 0x1b, 0x59, 0xc9, 0x22, 0x56, 0x0b, 0xf3, 0x45, 0x09, 0xf4, 0x7c,
+	0x57, 0x69, 0x1d, 0xd1, 0x25, 0x58, 0xbd, 0x43, 0x1c, 0x47, 0xd8, 0xea, 0xd0, 0xbe, 0xef, 0x59,
+	0xca, 0xbc, 0xa6, 0x0e, 0xea, 0x83, 0xa3, 0x2a, 0x7c, 0x1c, 0x8b, 0xae, 0x01, 0xba, 0x46, 0x79,
+	0x7f, 0x48, 0xad, 0x8e, 0x30, 0x1a, 0xee, 0xf8, 0x23, 0x8f, 0xcb, 0x20, 0x4a, 0xaa, 0x8b, 0x0f,
+	0xe7, 0xb4, 0x38, 0x87, 0x81, 0x76, 0xe0, 0x64, 0x22, 0xdd, 0x19, 0x8e, 0xbc, 0xbb, 0xed, 0x31,
+	0xa7, 0xaa, 0xad, 0x4a, 0xed, 0xd3, 0x71, 0x64, 0x9c, 0x3c, 0x3c, 0xae, 0xc4, 0xf3, 0xf8, 0x4c,
+	0x30, 0x52, 0x98, 0x04, 0x53, 0x9a, 0x0b, 0x26, 0xa3, 0xc5, 0x39, 0x8c, 0x4c, 0x30, 0x7b, 0x9e,
+	0x45, 0x1f, 0xaa, 0x60, 0xca, 0x73, 0xc1, 0xcc, 0x94, 0x78, 0x1e, 0x8f, 0x2e, 0xc2, 0x4a, 0x67,
+	0x48, 0x98, 0x45, 0x2d, 0x95, 0x6f, 0x31, 0xdb, 0xb4, 0xad, 0x13, 0x6a, 0x50, 0x87, 0x47, 0x34,
+	0xf8, 0x18, 0x12, 0x7d, 0x04, 0xcb, 0x9d, 0xc0, 0xb1, 0x79, 0xca, 0xac, 0x4a, 0x66, 0x3d, 0x8e,
+	0x8c, 0xe5, 0x30, 0x23, 0xc7, 0x47, 0x50, 0xe8, 0x06, 0xac, 0xed, 0x86, 0xdc, 0x76, 0x09, 0x3f,
+	0x5a, 0x8d, 0x45, 0x19, 0x79, 0x23, 0x8e, 0x8c, 0x35, 0x9a, 0xa3, 0xc7, 0xb9, 0x2c, 0x74, 0x19,
+	0xea, 0xb7, 0x47, 0x74, 0x44, 0xb3, 0x27, 0x63, 0x49, 0x9e, 0x8c, 0xb5, 0x38, 0x32, 0xea, 0xf7,
+	0x8e, 0xe9, 0xf0, 0x1c, 0xda, 0xfc, 0xbe, 0x00, 0xcb, 0xd9, 0x39, 0x8a, 0x02, 0xa8, 0x38, 0xa4,
+	0x47, 0x1d, 0x71, 0xc4, 0x44, 0x4b, 0x9e, 0x6a, 0xf6, 0x7d, 0xc6, 0xe9, 0xc3, 0xa0, 0xd7, 0xbc,
+	0x21, 0xe4, 0xfb, 0xc4, 0x66, 0xed, 0x1d, 0xd1, 0x6b, 0x7f, 0x46, 0xc6, 0x07, 0xaf, 0x73, 0xa1,
+	0x2b, 0xde, 0x15, 0x8b, 0x04, 0x9c, 0x32, 0x31, 0xa1, 0x5d, 0xca, 0x99, 0xdd, 0xc7, 0x89, 0x1f,
+	0x74, 0x11, 0xd2, 0xb1, 0x97, 0x4c, 0x81, 0xfa, 0xcc, 0xa5, 0x0a, 0x6d, 0x36, 0xdc, 0xef, 0xcb,
+	0xdb, 0x1a, 0xa7, 0x04, 0xb4, 0x0f, 0x30, 0xb4, 0x43, 0xee, 0x0f, 0x18, 0x71, 0xc5, 0x59, 0x54,
+	0x17, 0xdb, 0x94, 0x7e, 0xcd, 0xf1, 0x09, 0xbf, 0x9e, 0x02, 0x64, 0xe8, 0x28, 0x31, 0x95, 0xe1,
+	0xe1, 0xcc, 0xb7, 0xf9, 0x0d, 0xac, 0xec, 0x10, 0x71, 0x4c, 0xa6, 0x6f, 0x80, 0x75, 0x28, 0xde,
+	0xa5, 0xe3, 0xe4, 0xb2, 0xaa, 0xc6, 0x91, 0x21, 0x96, 0x58, 0xfc, 0x11, 0x97, 0x2a, 0x7d, 0xc8,
+	0xa9, 0xc7, 0xd3, 0xd0, 0x51, 0x76, 0x80, 0xed, 0x4a, 0x55, 0x7b, 0x35, 0xf1, 0x98, 0x42, 0x71,
+	0xfa, 0x61, 0xfe, 0x51, 0x80, 0x8a, 0x02, 0x21, 0x03, 0xca, 0x21, 0x27, 0x8c, 0x27, 0x13, 0x76,
+	0x29, 0x8e, 0x0c, 0x25, 0xc0, 0xea, 0x47, 0x44, 0x41, 0x3d, 0x4b, 0x8d, 0x0e, 0x15, 0x05, 0xf5,
+	0x2c, 0x2c, 0xfe, 0xa0, 0x4d, 0x58, 0xe4, 0x8c, 0xf4, 0x69, 0xd7, 0xb6, 0x92, 0x87, 0x40, 0x7a,
+	0x7b, 0x4b, 0xf1, 0x9e, 0x85, 0x3e, 0x83, 0x45, 0x96, 0x6c, 0x47, 0xf6, 0x48, 0xad, 0xb5, 0xd6,
+	0x54, 0x2f, 0xab, 0x66, 0xfa, 0xb2, 0x6a, 0x5e, 0xf1, 0xc6, 0xed, 0xe5, 0x38, 0x32, 0xa6, 0x48,
+	0x3c, 0xfd, 0x42, 0xe7, 0x01, 0xc9, 0x7d, 0x75, 0x79, 0x3a, 0xce, 0xba, 0xae, 0xea, 0x95, 0x22,
+	0xae, 0x4b, 0x4d, 0x76, 0xce, 0x7d, 0x0b, 0x1b, 0x49, 0x7d, 0xba, 0x01, 0xf3, 0xfb, 0x34, 0x0c,
+	0xa9, 0xd5, 0x0d, 0x28, 0xeb, 0x86, 0x62, 0xd2, 0x57, 0xff, 0xf7, 0xa4, 0x2f, 0x89, 0x84, 0xe2,
+	0x33, 0x89, 0x8f, 0xfd, 0xd4, 0x45, 0xa2, 0xfe, 0xbc, 0xb4, 0x58, 0xac, 0x97, 0xcc, 0x17, 0x1a,
+	0x54, 0x6f, 0x05, 0xdc, 0xf6, 0xbd, 0x10, 0xbd, 0x03, 0x27, 0x64, 0x4d, 0xaf, 0xda, 0xa1, 0xb0,
+	0x69, 0xc9, 0x24, 0x2f, 0xe2, 0xa3, 0x42, 0x74, 0x0e, 0xea, 0xb2, 0xc5, 0x6d, 0x6f, 0x30, 0x05,
+	0x16, 0x24, 0x70, 0x4e, 0x2e, 0x87, 0xbd, 0xb8, 0x3e, 0xa4, 0x42, 0x0d, 0xc1, 0x32, 0xce, 0x8a,
+	0x50, 0x0b, 0xd6, 0xf6, 0xbc, 0x90, 0x13, 0x8f, 0xcb, 0xfe, 0x9f, 0x5a, 0x2c, 0x49, 0x8b, 0xb9,
+	0xba, 0xe3, 0x9c, 0x3d, 0x8f, 0x53, 0x76, 0x9f, 0x38, 0xb2, 0x64, 0x45, 0x9c, 0xab, 0x13, 0x97,
+	0x8a, 0x7a, 0xdf, 0x88, 0x6a, 0x2c, 0xa5, 0xaf, 0x94, 0x5d, 0x58, 0x95, 0x29, 0x14, 0x2b, 0x3b,
+	0xe4, 0x76, 0x5f, 0x06, 0x94, 0x3b, 0x79, 0x44, 0x2e, 0x4a, 0xf9, 0xf3, 0xc5, 0xfc, 0x51, 0x03,
+	0xa4, 0xba, 0xe1, 0xfa, 0xc1, 0xc1, 0xfe, 0xb4, 0x23, 0xde, 0x84, 0xa5, 0xbe, 0x90, 0x76, 0xa7,
+	0x7d, 0x81, 0x17, 0xa5, 0xe0, 0x0b, 0x3a, 0x46, 0x06, 0xd4, 0xd4, 0x03, 0xb9, 0xdb, 0xf7, 0x2d,
+	0x75, 0xd7, 0x95, 0x31, 0x28, 0xd1, 0x8e, 0x6f, 0x51, 0xf4, 0x09, 0x54, 0x87, 0xc9, 0x4b, 0xb4,
+	0x38, 0xff, 0x12, 0x9d, 0xb9, 0x53, 0x4f, 0x4f, 0x9c, 0x82, 0x11, 0x82, 0x52, 0xcf, 0xb7, 0xc6,
+	0x32, 0x83, 0xcb, 0x58, 0x7e, 0x9b, 0x9f, 0x42, 0xfd, 0x38, 0x41, 0xe0, 0xbc, 0xe9, 0x3f, 0x01,
+	0x58, 0x7e, 0x8b, 0x2c, 0xdd, 0x9f, 0x5e, 0xbd, 0x4b, 0x58, 0x2d, 0xda, 0xbb, 0x8f, 0x9f, 0xea,
+	0x0b, 0x4f, 0x9e, 0xea, 0x0b, 0xcf, 0x9f, 0xea, 0xda, 0x77, 0x13, 0x5d, 0xfb, 0x79, 0xa2, 0x6b,
+	0x8f, 0x26, 0xba, 0xf6, 0x78, 0xa2, 0x6b, 0x7f, 0x4d, 0x74, 0xed, 0xef, 0x89, 0xbe, 0xf0, 0x7c,
+	0xa2, 0x6b, 0x3f, 0x3c, 0xd3, 0x17, 0x1e, 0x3f, 0xd3, 0x17, 0x9e, 0x3c, 0xd3, 0x17, 0xbe, 0x5e,
+	0x95, 0xd1, 0xba, 0xb6, 0x65, 0x39, 0xf4, 0x01, 0x61, 0xb4, 0x57, 0x91, 0x3d, 0xf4, 0xe1, 0x3f,
+	0x03, 0x00, 0xee, 0x60, 0x0e, 0x3a, 0x27, 0x0d, 0x00, 0x00,
 }
 
 func (this *PrometheusHeader) Equal(that interface{}) bool {
@@ -835,16 +1157,19 @@ func (this *PrometheusData) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if !this.Stats.Equal(that1.Stats) {
+		return false
+	}
 	return true
 }
-func (this *SampleStream) Equal(that interface{}) bool {
+func (this *PrometheusResponseStats) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*SampleStream)
+	that1, ok := that.(*PrometheusResponseStats)
 	if !ok {
-		that2, ok := that.(SampleStream)
+		that2, ok := that.(PrometheusResponseStats)
 		if ok {
 			that1 = &that2
 		} else {
@@ -856,40 +1181,22 @@ func (this *SampleStream) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if len(this.Labels) != len(that1.Labels) {
-		return false
-	}
-	for i := range this.Labels {
-		if !this.Labels[i].Equal(that1.Labels[i]) {
-			return false
-		}
-	}
-	if len(this.Samples) != len(that1.Samples) {
+	if !this.Samples.Equal(that1.Samples) {
 		return false
 	}
-	for i := range this.Samples {
-		if !this.Samples[i].Equal(&that1.Samples[i]) {
-			return false
-		}
-	}
-	if len(this.Histograms) != len(that1.Histograms) {
+	if !this.Querier.Equal(that1.Querier) {
 		return false
 	}
-	for i := range this.Histograms {
-		if !this.Histograms[i].Equal(&that1.Histograms[i]) {
-			return false
-		}
-	}
 	return true
 }
-func (this *CachedResponse) Equal(that interface{}) bool {
+func (this *PrometheusResponseSamplesStats) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*CachedResponse)
+	that1, ok := that.(*PrometheusResponseSamplesStats)
 	if !ok {
-		that2, ok := that.(CachedResponse)
+		that2, ok := that.(PrometheusResponseSamplesStats)
 		if ok {
 			that1 = &that2
 		} else {
@@ -901,27 +1208,27 @@ func (this *CachedResponse) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Key != that1.Key {
+	if this.TotalQueryableSamples != that1.TotalQueryableSamples {
 		return false
 	}
-	if len(this.Extents) != len(that1.Extents) {
+	if len(this.TotalQueryableSamplesPerStep) != len(that1.TotalQueryableSamplesPerStep) {
 		return false
 	}
-	for i := range this.Extents {
-		if !this.Extents[i].Equal(&that1.Extents[i]) {
+	for i := range this.TotalQueryableSamplesPerStep {
+		if !this.TotalQueryableSamplesPerStep[i].Equal(&that1.TotalQueryableSamplesPerStep[i]) {
 			return false
 		}
 	}
 	return true
 }
-func (this *Extent) Equal(that interface{}) bool {
+func (this *PrometheusResponseQueryableSamplesStatsPerStep) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Extent)
+	that1, ok := that.(*PrometheusResponseQueryableSamplesStatsPerStep)
 	if !ok {
-		that2, ok := that.(Extent)
+		that2, ok := that.(PrometheusResponseQueryableSamplesStatsPerStep)
 		if ok {
 			that1 = &that2
 		} else {
@@ -933,31 +1240,22 @@ func (this *Extent) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Start != that1.Start {
-		return false
-	}
-	if this.End != that1.End {
-		return false
-	}
-	if this.TraceId != that1.TraceId {
-		return false
-	}
-	if !this.Response.Equal(that1.Response) {
+	if this.TimestampMs != that1.TimestampMs {
 		return false
 	}
-	if this.QueryTimestampMs != that1.QueryTimestampMs {
+	if this.Value != that1.Value {
 		return false
 	}
 	return true
 }
-func (this *Options) Equal(that interface{}) bool {
+func (this *PrometheusResponseQuerierStats) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Options)
+	that1, ok := that.(*PrometheusResponseQuerierStats)
 	if !ok {
-		that2, ok := that.(Options)
+		that2, ok := that.(PrometheusResponseQuerierStats)
 		if ok {
 			that1 = &that2
 		} else {
@@ -969,19 +1267,191 @@ func (this *Options) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.CacheDisabled != that1.CacheDisabled {
+	if this.WallTimeSeconds != that1.WallTimeSeconds {
 		return false
 	}
-	if this.ShardingDisabled != that1.ShardingDisabled {
+	if this.FetchedSeriesCount != that1.FetchedSeriesCount {
 		return false
 	}
-	if this.TotalShards != that1.TotalShards {
+	if this.FetchedChunkBytes != that1.FetchedChunkBytes {
 		return false
 	}
-	if this.InstantSplitDisabled != that1.InstantSplitDisabled {
+	if this.FetchedChunksCount != that1.FetchedChunksCount {
 		return false
 	}
-	if this.InstantSplitInterval != that1.InstantSplitInterval {
+	if this.FetchedIndexBytes != that1.FetchedIndexBytes {
+		return false
+	}
+	if this.ShardedQueries != that1.ShardedQueries {
+		return false
+	}
+	if this.SplitQueries != that1.SplitQueries {
+		return false
+	}
+	if this.EstimatedSeriesCount != that1.EstimatedSeriesCount {
+		return false
+	}
+	if this.QueueTimeSeconds != that1.QueueTimeSeconds {
+		return false
+	}
+	return true
+}
+func (this *SampleStream) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SampleStream)
+	if !ok {
+		that2, ok := that.(SampleStream)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Labels) != len(that1.Labels) {
+		return false
+	}
+	for i := range this.Labels {
+		if !this.Labels[i].Equal(that1.Labels[i]) {
+			return false
+		}
+	}
+	if len(this.Samples) != len(that1.Samples) {
+		return false
+	}
+	for i := range this.Samples {
+		if !this.Samples[i].Equal(&that1.Samples[i]) {
+			return false
+		}
+	}
+	if len(this.Histograms) != len(that1.Histograms) {
+		return false
+	}
+	for i := range this.Histograms {
+		if !this.Histograms[i].Equal(&that1.Histograms[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *CachedResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CachedResponse)
+	if !ok {
+		that2, ok := that.(CachedResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Key != that1.Key {
+		return false
+	}
+	if len(this.Extents) != len(that1.Extents) {
+		return false
+	}
+	for i := range this.Extents {
+		if !this.Extents[i].Equal(&that1.Extents[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Extent) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Extent)
+	if !ok {
+		that2, ok := that.(Extent)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Start != that1.Start {
+		return false
+	}
+	if this.End != that1.End {
+		return false
+	}
+	if this.TraceId != that1.TraceId {
+		return false
+	}
+	if !this.Response.Equal(that1.Response) {
+		return false
+	}
+	if this.QueryTimestampMs != that1.QueryTimestampMs {
+		return false
+	}
+	if len(this.SamplesProcessedPerStep) != len(that1.SamplesProcessedPerStep) {
+		return false
+	}
+	for i := range this.SamplesProcessedPerStep {
+		if !this.SamplesProcessedPerStep[i].Equal(&that1.SamplesProcessedPerStep[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Options) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Options)
+	if !ok {
+		that2, ok := that.(Options)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.CacheDisabled != that1.CacheDisabled {
+		return false
+	}
+	if this.ShardingDisabled != that1.ShardingDisabled {
+		return false
+	}
+	if this.TotalShards != that1.TotalShards {
+		return false
+	}
+	if this.InstantSplitDisabled != that1.InstantSplitDisabled {
+		return false
+	}
+	if this.InstantSplitInterval != that1.InstantSplitInterval {
+		return false
+	}
+	if this.Stats != that1.Stats {
 		return false
 	}
 	return true
@@ -1109,7 +1579,7 @@ func (this *PrometheusData) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 6)
+	s := make([]string, 0, 7)
 	s = append(s, "&querymiddleware.PrometheusData{")
 	s = append(s, "ResultType: "+fmt.Sprintf("%#v", this.ResultType)+",\n")
 	if this.Result != nil {
@@ -1119,6 +1589,70 @@ func (this *PrometheusData) GoString() string {
 		}
 		s = append(s, "Result: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
+	if this.Stats != nil {
+		s = append(s, "Stats: "+fmt.Sprintf("%#v", this.Stats)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PrometheusResponseStats) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&querymiddleware.PrometheusResponseStats{")
+	if this.Samples != nil {
+		s = append(s, "Samples: "+fmt.Sprintf("%#v", this.Samples)+",\n")
+	}
+	if this.Querier != nil {
+		s = append(s, "Querier: "+fmt.Sprintf("%#v", this.Querier)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PrometheusResponseSamplesStats) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&querymiddleware.PrometheusResponseSamplesStats{")
+	s = append(s, "TotalQueryableSamples: "+fmt.Sprintf("%#v", this.TotalQueryableSamples)+",\n")
+	if this.TotalQueryableSamplesPerStep != nil {
+		vs := make([]*PrometheusResponseQueryableSamplesStatsPerStep, len(this.TotalQueryableSamplesPerStep))
+		for i := range vs {
+			vs[i] = &this.TotalQueryableSamplesPerStep[i]
+		}
+		s = append(s, "TotalQueryableSamplesPerStep: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PrometheusResponseQueryableSamplesStatsPerStep) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&querymiddleware.PrometheusResponseQueryableSamplesStatsPerStep{")
+	s = append(s, "TimestampMs: "+fmt.Sprintf("%#v", this.TimestampMs)+",\n")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PrometheusResponseQuerierStats) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 13)
+	s = append(s, "&querymiddleware.PrometheusResponseQuerierStats{")
+	s = append(s, "WallTimeSeconds: "+fmt.Sprintf("%#v", this.WallTimeSeconds)+",\n")
+	s = append(s, "FetchedSeriesCount: "+fmt.Sprintf("%#v", this.FetchedSeriesCount)+",\n")
+	s = append(s, "FetchedChunkBytes: "+fmt.Sprintf("%#v", this.FetchedChunkBytes)+",\n")
+	s = append(s, "FetchedChunksCount: "+fmt.Sprintf("%#v", this.FetchedChunksCount)+",\n")
+	s = append(s, "FetchedIndexBytes: "+fmt.Sprintf("%#v", this.FetchedIndexBytes)+",\n")
+	s = append(s, "ShardedQueries: "+fmt.Sprintf("%#v", this.ShardedQueries)+",\n")
+	s = append(s, "SplitQueries: "+fmt.Sprintf("%#v", this.SplitQueries)+",\n")
+	s = append(s, "EstimatedSeriesCount: "+fmt.Sprintf("%#v", this.EstimatedSeriesCount)+",\n")
+	s = append(s, "QueueTimeSeconds: "+fmt.Sprintf("%#v", this.QueueTimeSeconds)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1167,7 +1701,7 @@ func (this *Extent) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 9)
+	s := make([]string, 0, 10)
 	s = append(s, "&querymiddleware.Extent{")
 	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
 	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
@@ -1176,6 +1710,13 @@ func (this *Extent) GoString() string {
 		s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
 	}
 	s = append(s, "QueryTimestampMs: "+fmt.Sprintf("%#v", this.QueryTimestampMs)+",\n")
+	if this.SamplesProcessedPerStep != nil {
+		vs := make([]*PrometheusResponseQueryableSamplesStatsPerStep, len(this.SamplesProcessedPerStep))
+		for i := range vs {
+			vs[i] = &this.SamplesProcessedPerStep[i]
+		}
+		s = append(s, "SamplesProcessedPerStep: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1183,13 +1724,14 @@ func (this *Options) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 9)
+	s := make([]string, 0, 10)
 	s = append(s, "&querymiddleware.Options{")
 	s = append(s, "CacheDisabled: "+fmt.Sprintf("%#v", this.CacheDisabled)+",\n")
 	s = append(s, "ShardingDisabled: "+fmt.Sprintf("%#v", this.ShardingDisabled)+",\n")
 	s = append(s, "TotalShards: "+fmt.Sprintf("%#v", this.TotalShards)+",\n")
 	s = append(s, "InstantSplitDisabled: "+fmt.Sprintf("%#v", this.InstantSplitDisabled)+",\n")
 	s = append(s, "InstantSplitInterval: "+fmt.Sprintf("%#v", this.InstantSplitInterval)+",\n")
+	s = append(s, "Stats: "+fmt.Sprintf("%#v", this.Stats)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1375,6 +1917,18 @@ func (m *PrometheusData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Stats != nil {
+		{
+			size, err := m.Stats.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintModel(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.Result) > 0 {
 		for iNdEx := len(m.Result) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1399,7 +1953,7 @@ func (m *PrometheusData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *SampleStream) Marshal() (dAtA []byte, err error) {
+func (m *PrometheusResponseStats) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1409,20 +1963,67 @@ func (m *SampleStream) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SampleStream) MarshalTo(dAtA []byte) (int, error) {
+func (m *PrometheusResponseStats) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SampleStream) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *PrometheusResponseStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Histograms) > 0 {
-		for iNdEx := len(m.Histograms) - 1; iNdEx >= 0; iNdEx-- {
+	if m.Querier != nil {
+		{
+			size, err := m.Querier.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintModel(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Samples != nil {
+		{
+			size, err := m.Samples.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintModel(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PrometheusResponseSamplesStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PrometheusResponseSamplesStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PrometheusResponseSamplesStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TotalQueryableSamplesPerStep) > 0 {
+		for iNdEx := len(m.TotalQueryableSamplesPerStep) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.Histograms[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.TotalQueryableSamplesPerStep[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -1430,7 +2031,152 @@ func (m *SampleStream) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 				i = encodeVarintModel(dAtA, i, uint64(size))
 			}
 			i--
-			dAtA[i] = 0x1a
+			dAtA[i] = 0x12
+		}
+	}
+	if m.TotalQueryableSamples != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.TotalQueryableSamples))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Value != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.Value))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.TimestampMs != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.TimestampMs))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PrometheusResponseQuerierStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PrometheusResponseQuerierStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PrometheusResponseQuerierStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.QueueTimeSeconds != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.QueueTimeSeconds))))
+		i--
+		dAtA[i] = 0x49
+	}
+	if m.EstimatedSeriesCount != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.EstimatedSeriesCount))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.SplitQueries != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.SplitQueries))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.ShardedQueries != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.ShardedQueries))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.FetchedIndexBytes != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.FetchedIndexBytes))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.FetchedChunksCount != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.FetchedChunksCount))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.FetchedChunkBytes != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.FetchedChunkBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.FetchedSeriesCount != 0 {
+		i = encodeVarintModel(dAtA, i, uint64(m.FetchedSeriesCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.WallTimeSeconds != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.WallTimeSeconds))))
+		i--
+		dAtA[i] = 0x9
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SampleStream) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SampleStream) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SampleStream) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Histograms) > 0 {
+		for iNdEx := len(m.Histograms) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Histograms[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
 		}
 	}
 	if len(m.Samples) > 0 {
@@ -1528,6 +2274,20 @@ func (m *Extent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.SamplesProcessedPerStep) > 0 {
+		for iNdEx := len(m.SamplesProcessedPerStep) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.SamplesProcessedPerStep[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
 	if m.QueryTimestampMs != 0 {
 		i = encodeVarintModel(dAtA, i, uint64(m.QueryTimestampMs))
 		i--
@@ -1585,6 +2345,13 @@ func (m *Options) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Stats) > 0 {
+		i -= len(m.Stats)
+		copy(dAtA[i:], m.Stats)
+		i = encodeVarintModel(dAtA, i, uint64(len(m.Stats)))
+		i--
+		dAtA[i] = 0x32
+	}
 	if m.InstantSplitInterval != 0 {
 		i = encodeVarintModel(dAtA, i, uint64(m.InstantSplitInterval))
 		i--
@@ -1832,6 +2599,96 @@ func (m *PrometheusData) Size() (n int) {
 			n += 1 + l + sovModel(uint64(l))
 		}
 	}
+	if m.Stats != nil {
+		l = m.Stats.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func (m *PrometheusResponseStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Samples != nil {
+		l = m.Samples.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	if m.Querier != nil {
+		l = m.Querier.Size()
+		n += 1 + l + sovModel(uint64(l))
+	}
+	return n
+}
+
+func (m *PrometheusResponseSamplesStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TotalQueryableSamples != 0 {
+		n += 1 + sovModel(uint64(m.TotalQueryableSamples))
+	}
+	if len(m.TotalQueryableSamplesPerStep) > 0 {
+		for _, e := range m.TotalQueryableSamplesPerStep {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TimestampMs != 0 {
+		n += 1 + sovModel(uint64(m.TimestampMs))
+	}
+	if m.Value != 0 {
+		n += 1 + sovModel(uint64(m.Value))
+	}
+	return n
+}
+
+func (m *PrometheusResponseQuerierStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.WallTimeSeconds != 0 {
+		n += 9
+	}
+	if m.FetchedSeriesCount != 0 {
+		n += 1 + sovModel(uint64(m.FetchedSeriesCount))
+	}
+	if m.FetchedChunkBytes != 0 {
+		n += 1 + sovModel(uint64(m.FetchedChunkBytes))
+	}
+	if m.FetchedChunksCount != 0 {
+		n += 1 + sovModel(uint64(m.FetchedChunksCount))
+	}
+	if m.FetchedIndexBytes != 0 {
+		n += 1 + sovModel(uint64(m.FetchedIndexBytes))
+	}
+	if m.ShardedQueries != 0 {
+		n += 1 + sovModel(uint64(m.ShardedQueries))
+	}
+	if m.SplitQueries != 0 {
+		n += 1 + sovModel(uint64(m.SplitQueries))
+	}
+	if m.EstimatedSeriesCount != 0 {
+		n += 1 + sovModel(uint64(m.EstimatedSeriesCount))
+	}
+	if m.QueueTimeSeconds != 0 {
+		n += 9
+	}
 	return n
 }
 
@@ -1904,6 +2761,12 @@ func (m *Extent) Size() (n int) {
 	if m.QueryTimestampMs != 0 {
 		n += 1 + sovModel(uint64(m.QueryTimestampMs))
 	}
+	if len(m.SamplesProcessedPerStep) > 0 {
+		for _, e := range m.SamplesProcessedPerStep {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1928,6 +2791,10 @@ func (m *Options) Size() (n int) {
 	if m.InstantSplitInterval != 0 {
 		n += 1 + sovModel(uint64(m.InstantSplitInterval))
 	}
+	l = len(m.Stats)
+	if l > 0 {
+		n += 1 + l + sovModel(uint64(l))
+	}
 	return n
 }
 
@@ -2035,6 +2902,63 @@ func (this *PrometheusData) String() string {
 	s := strings.Join([]string{`&PrometheusData{`,
 		`ResultType:` + fmt.Sprintf("%v", this.ResultType) + `,`,
 		`Result:` + repeatedStringForResult + `,`,
+		`Stats:` + strings.Replace(this.Stats.String(), "PrometheusResponseStats", "PrometheusResponseStats", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PrometheusResponseStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PrometheusResponseStats{`,
+		`Samples:` + strings.Replace(this.Samples.String(), "PrometheusResponseSamplesStats", "PrometheusResponseSamplesStats", 1) + `,`,
+		`Querier:` + strings.Replace(this.Querier.String(), "PrometheusResponseQuerierStats", "PrometheusResponseQuerierStats", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PrometheusResponseSamplesStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForTotalQueryableSamplesPerStep := "[]PrometheusResponseQueryableSamplesStatsPerStep{"
+	for _, f := range this.TotalQueryableSamplesPerStep {
+		repeatedStringForTotalQueryableSamplesPerStep += strings.Replace(strings.Replace(f.String(), "PrometheusResponseQueryableSamplesStatsPerStep", "PrometheusResponseQueryableSamplesStatsPerStep", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForTotalQueryableSamplesPerStep += "}"
+	s := strings.Join([]string{`&PrometheusResponseSamplesStats{`,
+		`TotalQueryableSamples:` + fmt.Sprintf("%v", this.TotalQueryableSamples) + `,`,
+		`TotalQueryableSamplesPerStep:` + repeatedStringForTotalQueryableSamplesPerStep + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PrometheusResponseQueryableSamplesStatsPerStep) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PrometheusResponseQueryableSamplesStatsPerStep{`,
+		`TimestampMs:` + fmt.Sprintf("%v", this.TimestampMs) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PrometheusResponseQuerierStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PrometheusResponseQuerierStats{`,
+		`WallTimeSeconds:` + fmt.Sprintf("%v", this.WallTimeSeconds) + `,`,
+		`FetchedSeriesCount:` + fmt.Sprintf("%v", this.FetchedSeriesCount) + `,`,
+		`FetchedChunkBytes:` + fmt.Sprintf("%v", this.FetchedChunkBytes) + `,`,
+		`FetchedChunksCount:` + fmt.Sprintf("%v", this.FetchedChunksCount) + `,`,
+		`FetchedIndexBytes:` + fmt.Sprintf("%v", this.FetchedIndexBytes) + `,`,
+		`ShardedQueries:` + fmt.Sprintf("%v", this.ShardedQueries) + `,`,
+		`SplitQueries:` + fmt.Sprintf("%v", this.SplitQueries) + `,`,
+		`EstimatedSeriesCount:` + fmt.Sprintf("%v", this.EstimatedSeriesCount) + `,`,
+		`QueueTimeSeconds:` + fmt.Sprintf("%v", this.QueueTimeSeconds) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2081,12 +3005,18 @@ func (this *Extent) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForSamplesProcessedPerStep := "[]PrometheusResponseQueryableSamplesStatsPerStep{"
+	for _, f := range this.SamplesProcessedPerStep {
+		repeatedStringForSamplesProcessedPerStep += strings.Replace(strings.Replace(f.String(), "PrometheusResponseQueryableSamplesStatsPerStep", "PrometheusResponseQueryableSamplesStatsPerStep", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForSamplesProcessedPerStep += "}"
 	s := strings.Join([]string{`&Extent{`,
 		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
 		`End:` + fmt.Sprintf("%v", this.End) + `,`,
 		`TraceId:` + fmt.Sprintf("%v", this.TraceId) + `,`,
 		`Response:` + strings.Replace(fmt.Sprintf("%v", this.Response), "Any", "types.Any", 1) + `,`,
 		`QueryTimestampMs:` + fmt.Sprintf("%v", this.QueryTimestampMs) + `,`,
+		`SamplesProcessedPerStep:` + repeatedStringForSamplesProcessedPerStep + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2101,6 +3031,7 @@ func (this *Options) String() string {
 		`TotalShards:` + fmt.Sprintf("%v", this.TotalShards) + `,`,
 		`InstantSplitDisabled:` + fmt.Sprintf("%v", this.InstantSplitDisabled) + `,`,
 		`InstantSplitInterval:` + fmt.Sprintf("%v", this.InstantSplitInterval) + `,`,
+		`Stats:` + fmt.Sprintf("%v", this.Stats) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2425,16 +3356,521 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 			if postIndex < 0 {
 				return ErrInvalidLengthModel
 			}
-			if postIndex > l {
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, &PrometheusHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrometheusData) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusData: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusData: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResultType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResultType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Result = append(m.Result, SampleStream{})
+			if err := m.Result[len(m.Result)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Stats == nil {
+				m.Stats = &PrometheusResponseStats{}
+			}
+			if err := m.Stats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrometheusResponseStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusResponseStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusResponseStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Samples == nil {
+				m.Samples = &PrometheusResponseSamplesStats{}
+			}
+			if err := m.Samples.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Querier", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Querier == nil {
+				m.Querier = &PrometheusResponseQuerierStats{}
+			}
+			if err := m.Querier.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrometheusResponseSamplesStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusResponseSamplesStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusResponseSamplesStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalQueryableSamples", wireType)
+			}
+			m.TotalQueryableSamples = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalQueryableSamples |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalQueryableSamplesPerStep", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TotalQueryableSamplesPerStep = append(m.TotalQueryableSamplesPerStep, PrometheusResponseQueryableSamplesStatsPerStep{})
+			if err := m.TotalQueryableSamplesPerStep[len(m.TotalQueryableSamplesPerStep)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipModel(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthModel
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrometheusResponseQueryableSamplesStatsPerStep) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowModel
+			}
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Error = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			var msglen int
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrometheusResponseQueryableSamplesStatsPerStep: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrometheusResponseQueryableSamplesStatsPerStep: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampMs", wireType)
+			}
+			m.TimestampMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -2444,31 +3880,16 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.TimestampMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Headers = append(m.Headers, &PrometheusHeader{})
-			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
-			var stringLen uint64
+			m.Value = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -2478,24 +3899,11 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Value |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthModel
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
@@ -2520,7 +3928,7 @@ func (m *PrometheusResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PrometheusData) Unmarshal(dAtA []byte) error {
+func (m *PrometheusResponseQuerierStats) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2543,17 +3951,28 @@ func (m *PrometheusData) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PrometheusData: wiretype end group for non-group")
+			return fmt.Errorf("proto: PrometheusResponseQuerierStats: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PrometheusData: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PrometheusResponseQuerierStats: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResultType", wireType)
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WallTimeSeconds", wireType)
 			}
-			var stringLen uint64
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.WallTimeSeconds = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedSeriesCount", wireType)
+			}
+			m.FetchedSeriesCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -2563,29 +3982,54 @@ func (m *PrometheusData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.FetchedSeriesCount |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthModel
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedChunkBytes", wireType)
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
+			m.FetchedChunkBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedChunkBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedChunksCount", wireType)
 			}
-			m.ResultType = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+			m.FetchedChunksCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedChunksCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			var msglen int
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedIndexBytes", wireType)
+			}
+			m.FetchedIndexBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowModel
@@ -2595,26 +4039,79 @@ func (m *PrometheusData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.FetchedIndexBytes |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthModel
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShardedQueries", wireType)
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthModel
+			m.ShardedQueries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ShardedQueries |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplitQueries", wireType)
 			}
-			m.Result = append(m.Result, SampleStream{})
-			if err := m.Result[len(m.Result)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.SplitQueries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SplitQueries |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EstimatedSeriesCount", wireType)
+			}
+			m.EstimatedSeriesCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EstimatedSeriesCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueueTimeSeconds", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.QueueTimeSeconds = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
@@ -3067,6 +4564,40 @@ func (m *Extent) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SamplesProcessedPerStep", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SamplesProcessedPerStep = append(m.SamplesProcessedPerStep, PrometheusResponseQueryableSamplesStatsPerStep{})
+			if err := m.SamplesProcessedPerStep[len(m.SamplesProcessedPerStep)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
@@ -3218,6 +4749,38 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stats", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stats = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
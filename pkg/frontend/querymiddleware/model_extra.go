@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"slices"
 	"time"
@@ -578,8 +579,9 @@ func (r *PrometheusLabelValuesQueryRequest) GetLimit() uint64 {
 
 func (d *PrometheusData) UnmarshalJSON(b []byte) error {
 	v := struct {
-		Type   model.ValueType    `json:"resultType"`
-		Result stdjson.RawMessage `json:"result"`
+		Type   model.ValueType          `json:"resultType"`
+		Result stdjson.RawMessage       `json:"result"`
+		Stats  *PrometheusResponseStats `json:"stats,omitempty"`
 	}{}
 
 	err := json.Unmarshal(b, &v)
@@ -587,6 +589,7 @@ func (d *PrometheusData) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	d.ResultType = v.Type.String()
+	d.Stats = v.Stats
 	switch v.Type {
 	case model.ValString:
 		var sss stringSampleStreams
@@ -628,29 +631,35 @@ func (d *PrometheusData) MarshalJSON() ([]byte, error) {
 	switch d.ResultType {
 	case model.ValString.String():
 		return json.Marshal(struct {
-			Type   model.ValueType     `json:"resultType"`
-			Result stringSampleStreams `json:"result"`
+			Type   model.ValueType          `json:"resultType"`
+			Result stringSampleStreams      `json:"result"`
+			Stats  *PrometheusResponseStats `json:"stats,omitempty"`
 		}{
 			Type:   model.ValString,
 			Result: d.Result,
+			Stats:  d.Stats,
 		})
 
 	case model.ValScalar.String():
 		return json.Marshal(struct {
-			Type   model.ValueType     `json:"resultType"`
-			Result scalarSampleStreams `json:"result"`
+			Type   model.ValueType          `json:"resultType"`
+			Result scalarSampleStreams      `json:"result"`
+			Stats  *PrometheusResponseStats `json:"stats,omitempty"`
 		}{
 			Type:   model.ValScalar,
 			Result: d.Result,
+			Stats:  d.Stats,
 		})
 
 	case model.ValVector.String():
 		return json.Marshal(struct {
-			Type   model.ValueType      `json:"resultType"`
-			Result []vectorSampleStream `json:"result"`
+			Type   model.ValueType          `json:"resultType"`
+			Result []vectorSampleStream     `json:"result"`
+			Stats  *PrometheusResponseStats `json:"stats,omitempty"`
 		}{
 			Type:   model.ValVector,
 			Result: asVectorSampleStreams(d.Result),
+			Stats:  d.Stats,
 		})
 
 	case model.ValMatrix.String():
@@ -819,6 +828,22 @@ func (s *SampleStream) MarshalJSON() ([]byte, error) {
 	return json.Marshal(stream)
 }
 
+// MarshalJSON implements json.Marshaler, encoding the step as [<timestamp in seconds>, <value>], like Prometheus does.
+func (s PrometheusResponseQueryableSamplesStatsPerStep) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]any{float64(s.TimestampMs) / 1000, s.Value})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PrometheusResponseQueryableSamplesStatsPerStep) UnmarshalJSON(b []byte) error {
+	var v [2]float64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	s.TimestampMs = int64(math.Round(v[0] * 1000))
+	s.Value = int64(v[1])
+	return nil
+}
+
 type byFirstTime []*PrometheusResponse
 
 func (a byFirstTime) Len() int           { return len(a) }
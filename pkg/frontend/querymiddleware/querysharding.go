@@ -144,6 +144,18 @@ func (s *querySharding) Do(ctx context.Context, r MetricsQueryRequest) (Response
 	queryStats := stats.FromContext(ctx)
 	queryStats.AddShardedQueries(uint32(shardingStats.GetShardedQueries()))
 
+	if plan := queryPlanFromContext(ctx); plan != nil {
+		plan.addRewrite(queryPlanRewrite{
+			Stage:          "querysharding",
+			OriginalQuery:  r.GetQuery(),
+			RewrittenQuery: shardedQuery,
+			Start:          r.GetStart(),
+			End:            r.GetEnd(),
+			TotalShards:    totalShards,
+			ShardedQueries: shardingStats.GetShardedQueries(),
+		})
+	}
+
 	r, err = r.WithQuery(shardedQuery)
 	if err != nil {
 		return nil, apierror.New(apierror.TypeBadData, err.Error())
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"net/http"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -32,6 +33,7 @@ import (
 	"github.com/uber/jaeger-client-go"
 
 	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/math"
 )
@@ -378,6 +380,14 @@ func mergeCacheExtentsForRequest(ctx context.Context, r MetricsQueryRequest, mer
 			continue
 		}
 		accumulator.TraceId = jaegerTraceID(ctx)
+		if hasSamplesProcessedPerStep(accumulator.Extent) && hasSamplesProcessedPerStep(extents[i]) {
+			// The steps up to the end of the accumulator are already accounted in it. The accumulator's
+			// steps are clipped to not modify the ones of the extent it has been created from.
+			accumulator.SamplesProcessedPerStep = append(slices.Clip(accumulator.SamplesProcessedPerStep), stepsAfter(extents[i].SamplesProcessedPerStep, accumulator.End)...)
+		} else {
+			// The per-step statistics of the merged extent would be incomplete.
+			accumulator.SamplesProcessedPerStep = nil
+		}
 		accumulator.End = extents[i].End
 		currentRes, err := extents[i].toResponse()
 		if err != nil {
@@ -418,6 +428,8 @@ func mergeCacheExtentsWithAccumulator(extents []Extent, acc *accumulator) ([]Ext
 		Response:         marshalled,
 		TraceId:          acc.Extent.TraceId,
 		QueryTimestampMs: acc.QueryTimestampMs,
+
+		SamplesProcessedPerStep: acc.Extent.SamplesProcessedPerStep,
 	}), nil
 }
 
@@ -432,7 +444,9 @@ func newAccumulator(base Extent) (*accumulator, error) {
 	}, nil
 }
 
-func toExtent(ctx context.Context, req MetricsQueryRequest, res Response, queryTime time.Time) (Extent, error) {
+// toExtent returns the extent to cache for the response res of the request req.
+// queryStats are the statistics of the query run to get the response, and can be nil.
+func toExtent(ctx context.Context, req MetricsQueryRequest, res Response, queryStats *stats.Stats, queryTime time.Time) (Extent, error) {
 	marshalled, err := types.MarshalAny(res)
 	if err != nil {
 		return Extent{}, err
@@ -443,14 +457,46 @@ func toExtent(ctx context.Context, req MetricsQueryRequest, res Response, queryT
 		Response:         marshalled,
 		TraceId:          jaegerTraceID(ctx),
 		QueryTimestampMs: queryTime.UnixMilli(),
+
+		SamplesProcessedPerStep: toPrometheusResponseSamplesPerStep(queryStats.LoadSamplesProcessedPerStep()),
 	}, nil
 }
 
+// hasSamplesProcessedPerStep returns whether the number of samples processed at each step has been stored
+// with the extent. Queriers track it only for the requests asking for the query statistics, and only if run
+// with Prometheus' engine. When tracked, there's an entry for each step of the extent.
+func hasSamplesProcessedPerStep(extent Extent) bool {
+	return len(extent.SamplesProcessedPerStep) > 0
+}
+
+// withSamplesProcessedPerStep returns the extents with the number of samples processed at each step.
+func withSamplesProcessedPerStep(extents []Extent) []Extent {
+	return slices.DeleteFunc(slices.Clone(extents), func(extent Extent) bool {
+		return !hasSamplesProcessedPerStep(extent)
+	})
+}
+
+// stepsAfter returns the steps, sorted by timestamp, with a timestamp after ts.
+func stepsAfter(steps []PrometheusResponseQueryableSamplesStatsPerStep, ts int64) []PrometheusResponseQueryableSamplesStatsPerStep {
+	idx := sort.Search(len(steps), func(i int) bool {
+		return steps[i].TimestampMs > ts
+	})
+	return steps[idx:]
+}
+
+// stepsBetween returns the steps, sorted by timestamp, with a timestamp within [start, end].
+func stepsBetween(steps []PrometheusResponseQueryableSamplesStatsPerStep, start, end int64) []PrometheusResponseQueryableSamplesStatsPerStep {
+	steps = stepsAfter(steps, start-1)
+	return steps[:len(steps)-len(stepsAfter(steps, end))]
+}
+
 // partitionCacheExtents calculates the required requests to satisfy req given the cached data.
-// extents must be in order by start time.
-func partitionCacheExtents(req MetricsQueryRequest, extents []Extent, minCacheExtent int64, extractor Extractor) ([]MetricsQueryRequest, []Response, error) {
+// extents must be in order by start time. The number of samples processed at each step of the
+// cached responses, excluding the steps covered by the returned requests, is returned too.
+func partitionCacheExtents(req MetricsQueryRequest, extents []Extent, minCacheExtent int64, extractor Extractor) ([]MetricsQueryRequest, []Response, []stats.StepStat, error) {
 	var requests []MetricsQueryRequest
 	var cachedResponses []Response
+	var cachedSteps []stats.StepStat
 	start := req.GetStart()
 
 	for _, extent := range extents {
@@ -473,18 +519,25 @@ func partitionCacheExtents(req MetricsQueryRequest, extents []Extent, minCacheEx
 		if start < extent.Start {
 			r, err := req.WithStartEnd(start, extent.Start)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 
 			requests = append(requests, r)
 		}
 		res, err := extent.toResponse()
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 		// extract the overlap from the cached extent.
 		cachedResponses = append(cachedResponses, extractor.Extract(start, req.GetEnd(), res))
 
+		// Consecutive extents may share a step, which must be accounted once.
+		for _, step := range stepsBetween(extent.SamplesProcessedPerStep, start, req.GetEnd()) {
+			if len(cachedSteps) == 0 || step.TimestampMs > cachedSteps[len(cachedSteps)-1].Timestamp {
+				cachedSteps = append(cachedSteps, stats.StepStat{Timestamp: step.TimestampMs, Value: step.Value})
+			}
+		}
+
 		// We want next request to start where extent ends, but we must make sure that
 		// next start also has the same offset into the step as original request had, ie.
 		// "start % req.Step" must be the same as "req.GetStart() % req.GetStep()".
@@ -505,7 +558,7 @@ func partitionCacheExtents(req MetricsQueryRequest, extents []Extent, minCacheEx
 	if start < req.GetEnd() {
 		r, err := req.WithStartEnd(start, req.GetEnd())
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		requests = append(requests, r)
@@ -517,7 +570,14 @@ func partitionCacheExtents(req MetricsQueryRequest, extents []Extent, minCacheEx
 		requests = append(requests, req)
 	}
 
-	return requests, cachedResponses, nil
+	// The steps covered by the requests are accounted in the statistics of the requests.
+	cachedSteps = slices.DeleteFunc(cachedSteps, func(step stats.StepStat) bool {
+		return slices.ContainsFunc(requests, func(r MetricsQueryRequest) bool {
+			return step.Timestamp >= r.GetStart() && step.Timestamp <= r.GetEnd()
+		})
+	})
+
+	return requests, cachedResponses, cachedSteps, nil
 }
 
 func filterRecentCacheExtents(req MetricsQueryRequest, maxCacheFreshness time.Duration, extractor Extractor, extents []Extent) ([]Extent, error) {
@@ -526,6 +586,7 @@ func filterRecentCacheExtents(req MetricsQueryRequest, maxCacheFreshness time.Du
 		// Never cache data for the latest freshness period.
 		if extents[i].End > maxCacheTime {
 			extents[i].End = maxCacheTime
+			extents[i].SamplesProcessedPerStep = stepsBetween(extents[i].SamplesProcessedPerStep, extents[i].Start, maxCacheTime)
 			res, err := extents[i].toResponse()
 			if err != nil {
 				return nil, err
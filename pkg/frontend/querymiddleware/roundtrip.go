@@ -83,6 +83,11 @@ type Config struct {
 	ExtraRangeQueryMiddlewares   []MetricsQueryMiddleware `yaml:"-"`
 
 	QueryResultResponseFormat string `yaml:"query_result_response_format"`
+
+	// QueryEngine and EnableQueryEngineFallback are the querier's query engine configuration,
+	// used to report which engine runs each query when explaining queries.
+	QueryEngine               string `yaml:"-"`
+	EnableQueryEngineFallback bool   `yaml:"-"`
 }
 
 // RegisterFlags adds the flags required to config this to the given FlagSet.
@@ -245,10 +250,11 @@ func newQueryTripperware(
 	}
 
 	queryRangeMiddleware, queryInstantMiddleware, remoteReadMiddleware := newQueryMiddlewares(cfg, log, limits, codec, c, cacheKeyGenerator, cacheExtractor, engine, registerer)
+	engineChooser := newQueryEngineChooser(cfg, engineOpts, log)
 
 	return func(next http.RoundTripper) http.RoundTripper {
-		queryrange := newLimitedParallelismRoundTripper(next, codec, limits, queryRangeMiddleware...)
-		instant := newLimitedParallelismRoundTripper(next, codec, limits, queryInstantMiddleware...)
+		queryrange := newQueryExplainRoundTripper(newLimitedParallelismRoundTripper(next, codec, limits, queryRangeMiddleware...), engineChooser)
+		instant := newQueryExplainRoundTripper(newLimitedParallelismRoundTripper(next, codec, limits, queryInstantMiddleware...), engineChooser)
 		remoteRead := newRemoteReadRoundTripper(next, remoteReadMiddleware...)
 
 		// Wrap next for cardinality, labels queries and all other queries.
@@ -153,6 +153,9 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req MetricsQueryReques
 	maxCacheTime := int64(model.Now().Add(-maxCacheFreshness))
 	cacheUnalignedRequests := validation.AllTrueBooleansPerTenant(tenantIDs, s.limits.ResultsCacheForUnalignedQueryEnabled)
 
+	// If the query is run in explain mode, we record the cache lookups but never store anything in the cache.
+	plan := queryPlanFromContext(ctx)
+
 	// Lookup the results cache.
 	if isCacheEnabled {
 		s.metrics.queryResultCacheAttemptedCount.Add(float64(len(splitReqs)))
@@ -167,6 +170,9 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req MetricsQueryReques
 				level.Debug(spanLog).Log("msg", "skipping response cache as query is not cacheable", "query", splitReq.orig.GetQuery(), "reason", reason, "tenants", tenant.JoinTenantIDs(tenantIDs))
 				splitReq.downstreamRequests = []MetricsQueryRequest{splitReq.orig}
 				s.metrics.queryResultCacheSkippedCount.WithLabelValues(reason).Inc()
+				if plan != nil {
+					plan.addCacheLookup("", splitReq.orig, cacheLookupStatusSkipped, reason)
+				}
 				continue
 			}
 
@@ -179,19 +185,31 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req MetricsQueryReques
 		fetchedExtents := s.fetchCacheExtents(ctx, s.currentTime(), tenantIDs, lookupKeys)
 
 		for lookupIdx, extents := range fetchedExtents {
+			// The statistics of the cached responses are computed from the number of samples processed at each
+			// step stored with them. The extents without it can't be used by requests asking for the statistics,
+			// so they're ignored, and replaced in the cache by the ones of the downstream requests.
+			if lookupReqs[lookupIdx].orig.GetOptions().Stats != "" {
+				extents = withSamplesProcessedPerStep(extents)
+			}
+
 			if len(extents) == 0 {
 				// We just need to run the request as is because no part of it has been cached yet.
 				lookupReqs[lookupIdx].downstreamRequests = []MetricsQueryRequest{lookupReqs[lookupIdx].orig}
+				if plan != nil {
+					plan.addCacheLookup(lookupKeys[lookupIdx], lookupReqs[lookupIdx].orig, cacheLookupStatusMiss, "")
+				}
 				continue
 			}
 
 			// We have some extents. This means some parts of the response has been cached and we need
 			// to generate the queries for the missing parts.
-			requests, responses, err := partitionCacheExtents(lookupReqs[lookupIdx].orig, extents, defaultMinCacheExtent, s.extractor)
+			requests, responses, cachedSteps, err := partitionCacheExtents(lookupReqs[lookupIdx].orig, extents, defaultMinCacheExtent, s.extractor)
 			if err != nil {
 				return nil, err
 			}
 
+			lookupReqs[lookupIdx].cachedSamplesProcessedPerStep = cachedSteps
+
 			if len(requests) == 0 {
 				// The full response has been picked up from the cache so we can merge it and store it.
 				response, err := s.merger.MergeResponse(responses...)
@@ -200,9 +218,16 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req MetricsQueryReques
 				}
 
 				lookupReqs[lookupIdx].cachedResponses = []Response{response}
+				if plan != nil {
+					plan.addCacheLookup(lookupKeys[lookupIdx], lookupReqs[lookupIdx].orig, cacheLookupStatusHit, "")
+				}
 				continue
 			}
 
+			if plan != nil {
+				plan.addCacheLookup(lookupKeys[lookupIdx], lookupReqs[lookupIdx].orig, cacheLookupStatusPartial, "")
+			}
+
 			lookupReqs[lookupIdx].downstreamRequests = requests
 			lookupReqs[lookupIdx].cachedResponses = responses
 			lookupReqs[lookupIdx].cachedExtents = extents
@@ -225,6 +250,12 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req MetricsQueryReques
 	queryStats := stats.FromContext(ctx)
 	queryStats.AddSplitQueries(uint32(len(execReqs)))
 
+	// The responses picked up from the cache haven't been processed by any querier, so we
+	// add the statistics stored with them.
+	for _, splitReq := range splitReqs {
+		addCachedSamplesProcessed(queryStats, splitReq.cachedSamplesProcessedPerStep)
+	}
+
 	queryTime := s.currentTime()
 
 	if len(execReqs) > 0 {
@@ -244,7 +275,7 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req MetricsQueryReques
 	}
 
 	// Store the updated response in the results cache.
-	if isCacheEnabled && len(execReqs) > 0 {
+	if isCacheEnabled && len(execReqs) > 0 && plan == nil {
 		for _, splitReq := range splitReqs {
 			// If there are no downstream requests it means the response was entirely picked up from the cache
 			// so there's no need to store it again in the cache (because nothing has changed).
@@ -266,7 +297,7 @@ func (s *splitAndCacheMiddleware) Do(ctx context.Context, req MetricsQueryReques
 					continue
 				}
 
-				extent, err := toExtent(ctx, downstreamReq, s.extractor.ResponseWithoutHeaders(downstreamRes), queryTime)
+				extent, err := toExtent(ctx, downstreamReq, s.extractor.ResponseWithoutHeaders(downstreamRes), splitReq.downstreamStats[downstreamIdx], queryTime)
 				if err != nil {
 					return nil, err
 				}
@@ -486,6 +517,12 @@ type splitRequest struct {
 	// response is stored at the same index.
 	downstreamRequests  []MetricsQueryRequest
 	downstreamResponses []Response
+
+	// The statistics of the downstream requests, stored at the same index of the request.
+	downstreamStats []*stats.Stats
+
+	// The number of samples processed at each step of the responses picked up from the cache.
+	cachedSamplesProcessedPerStep []stats.StepStat
 }
 
 // splitRequests holds a list of splitRequest.
@@ -552,6 +589,7 @@ func (s *splitRequests) prepareDownstreamRequests() ([]MetricsQueryRequest, erro
 
 		execReqs = append(execReqs, splitReq.downstreamRequests...)
 		splitReq.downstreamResponses = make([]Response, len(splitReq.downstreamRequests))
+		splitReq.downstreamStats = make([]*stats.Stats, len(splitReq.downstreamRequests))
 	}
 
 	return execReqs, nil
@@ -561,7 +599,7 @@ func (s *splitRequests) prepareDownstreamRequests() ([]MetricsQueryRequest, erro
 // and stores the associated downstream responses for each request. If returns no error, then it's guaranteed
 // that any downstream request got its response associated.
 func (s *splitRequests) storeDownstreamResponses(responses []requestResponse) error {
-	execRespsByID := make(map[int64]Response, len(responses))
+	execRespsByID := make(map[int64]requestResponse, len(responses))
 
 	// Map responses by (unique) request IDs.
 	for _, resp := range responses {
@@ -571,7 +609,7 @@ func (s *splitRequests) storeDownstreamResponses(responses []requestResponse) er
 			return errors.New("consistency check failed: conflicting downstream request ID")
 		}
 
-		execRespsByID[resp.Request.GetID()] = resp.Response
+		execRespsByID[resp.Request.GetID()] = resp
 	}
 
 	mappedDownstreamRequests := 0
@@ -584,7 +622,8 @@ func (s *splitRequests) storeDownstreamResponses(responses []requestResponse) er
 				return errors.New("consistency check failed: missing downstream response")
 			}
 
-			splitReq.downstreamResponses[downstreamIdx] = downstreamRes
+			splitReq.downstreamResponses[downstreamIdx] = downstreamRes.Response
+			splitReq.downstreamStats[downstreamIdx] = downstreamRes.Stats
 			mappedDownstreamRequests++
 		}
 	}
@@ -601,6 +640,9 @@ func (s *splitRequests) storeDownstreamResponses(responses []requestResponse) er
 type requestResponse struct {
 	Request  MetricsQueryRequest
 	Response Response
+
+	// Stats are the statistics of the query run to get the response.
+	Stats *stats.Stats
 }
 
 // doRequests executes a list of requests in parallel.
@@ -627,7 +669,7 @@ func doRequests(ctx context.Context, downstream MetricsQueryHandler, reqs []Metr
 			}
 
 			mtx.Lock()
-			resps = append(resps, requestResponse{req, resp})
+			resps = append(resps, requestResponse{Request: req, Response: resp, Stats: partialStats})
 			mtx.Unlock()
 
 			return nil
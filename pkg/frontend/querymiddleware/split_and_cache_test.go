@@ -367,6 +367,97 @@ func TestSplitAndCacheMiddleware_ResultsCache(t *testing.T) {
 	`)))
 }
 
+func TestSplitAndCacheMiddleware_ResultsCache_ShouldAccountSamplesProcessedByCachedResponses(t *testing.T) {
+	mw := newSplitAndCacheMiddleware(
+		false,
+		true,
+		24*time.Hour,
+		mockLimits{maxCacheFreshness: 10 * time.Minute, resultsCacheTTL: resultsCacheTTL, resultsCacheOutOfOrderWindowTTL: resultsCacheLowerTTL},
+		newTestPrometheusCodec(),
+		cache.NewInstrumentedMockCache(),
+		DefaultCacheKeyGenerator{interval: day},
+		PrometheusResponseExtractor{},
+		resultsCacheAlwaysEnabled,
+		log.NewNopLogger(),
+		prometheus.NewPedanticRegistry(),
+	)
+
+	// The downstream handler processes 10 samples at each step of the request, and tracks
+	// them per step only if the statistics have been requested, like queriers do.
+	const samplesPerStep = 10
+	downstreamReqs := 0
+	rc := mw.Wrap(HandlerFunc(func(ctx context.Context, req MetricsQueryRequest) (Response, error) {
+		downstreamReqs++
+
+		var steps []stats.StepStat
+		for ts := req.GetStart(); ts <= req.GetEnd(); ts += req.GetStep() {
+			steps = append(steps, stats.StepStat{Timestamp: ts, Value: samplesPerStep})
+		}
+		queryStats := stats.FromContext(ctx)
+		queryStats.AddSamplesProcessed(uint64(len(steps) * samplesPerStep))
+		if req.GetOptions().Stats != "" {
+			queryStats.AddSamplesProcessedPerStep(steps)
+		}
+
+		return &PrometheusResponse{Status: "success", Data: &PrometheusData{ResultType: model.ValMatrix.String()}}, nil
+	}))
+
+	expectedSteps := func(req MetricsQueryRequest) []stats.StepStat {
+		var steps []stats.StepStat
+		for ts := req.GetStart(); ts <= req.GetEnd(); ts += req.GetStep() {
+			steps = append(steps, stats.StepStat{Timestamp: ts, Value: samplesPerStep})
+		}
+		return steps
+	}
+
+	step := int64(120 * 1000)
+	req := MetricsQueryRequest(&PrometheusRangeQueryRequest{
+		path:      "/api/v1/query_range",
+		start:     parseTimeRFC3339(t, "2021-10-15T10:00:00Z").Unix() * 1000,
+		end:       parseTimeRFC3339(t, "2021-10-15T12:00:00Z").Unix() * 1000,
+		step:      step,
+		queryExpr: parseQuery(t, `{__name__=~".+"}`),
+	})
+
+	for _, testCase := range []struct {
+		name                   string
+		end                    int64
+		withoutStats           bool
+		expectedDownstreamReqs int
+	}{
+		{name: "cache miss without stats", end: req.GetEnd(), withoutStats: true, expectedDownstreamReqs: 1},
+		{name: "cache hit without stats", end: req.GetEnd(), withoutStats: true, expectedDownstreamReqs: 1},
+		// The cached extent has no per-step statistics, so it can't be used.
+		{name: "cache miss", end: req.GetEnd(), expectedDownstreamReqs: 2},
+		{name: "cache hit", end: req.GetEnd(), expectedDownstreamReqs: 2},
+		{name: "partial cache hit", end: req.GetEnd() + step, expectedDownstreamReqs: 3},
+		{name: "cache hit of merged extents", end: req.GetEnd() + step, expectedDownstreamReqs: 3},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			options := Options{Stats: "all"}
+			if testCase.withoutStats {
+				options = Options{}
+			}
+			req, err := req.WithStartEnd(req.GetStart(), testCase.end)
+			require.NoError(t, err)
+			req.(*PrometheusRangeQueryRequest).options = options
+
+			queryStats, ctx := stats.ContextWithEmptyStats(context.Background())
+			ctx = user.InjectOrgID(ctx, "1")
+			_, err = rc.Do(ctx, req)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedDownstreamReqs, downstreamReqs)
+
+			if testCase.withoutStats {
+				return
+			}
+			steps := expectedSteps(req)
+			assert.Equal(t, steps, queryStats.LoadSamplesProcessedPerStep())
+			assert.Equal(t, uint64(len(steps)*samplesPerStep), queryStats.LoadSamplesProcessed())
+		})
+	}
+}
+
 func TestSplitAndCacheMiddleware_ResultsCache_ShouldNotLookupCacheIfStepIsNotAligned(t *testing.T) {
 	cacheBackend := cache.NewInstrumentedMockCache()
 	reg := prometheus.NewPedanticRegistry()
@@ -1365,41 +1456,52 @@ func TestSplitRequests_storeDownstreamResponses(t *testing.T) {
 			requests: splitRequests{{
 				downstreamRequests:  []MetricsQueryRequest{&PrometheusRangeQueryRequest{start: 1, id: 1}, &PrometheusRangeQueryRequest{start: 2, id: 2}},
 				downstreamResponses: []Response{nil, nil},
+				downstreamStats:     []*stats.Stats{nil, nil},
 			}, {
 				downstreamRequests:  []MetricsQueryRequest{},
 				downstreamResponses: []Response{},
+				downstreamStats:     []*stats.Stats{},
 			}, {
 				downstreamRequests:  []MetricsQueryRequest{&PrometheusRangeQueryRequest{start: 3, id: 3}},
 				downstreamResponses: []Response{nil},
+				downstreamStats:     []*stats.Stats{nil},
 			}},
 			responses: []requestResponse{{
 				Request:  &PrometheusRangeQueryRequest{start: 3, id: 3},
 				Response: &PrometheusResponse{Status: "response-3"},
+				Stats:    &stats.Stats{SamplesProcessed: 3},
 			}, {
 				Request:  &PrometheusRangeQueryRequest{start: 1, id: 1},
 				Response: &PrometheusResponse{Status: "response-1"},
+				Stats:    &stats.Stats{SamplesProcessed: 1},
 			}, {
 				Request:  &PrometheusRangeQueryRequest{start: 2, id: 2},
 				Response: &PrometheusResponse{Status: "response-2"},
+				Stats:    &stats.Stats{SamplesProcessed: 2},
 			}},
 			expected: splitRequests{{
 				downstreamRequests:  []MetricsQueryRequest{&PrometheusRangeQueryRequest{start: 1, id: 1}, &PrometheusRangeQueryRequest{start: 2, id: 2}},
 				downstreamResponses: []Response{&PrometheusResponse{Status: "response-1"}, &PrometheusResponse{Status: "response-2"}},
+				downstreamStats:     []*stats.Stats{{SamplesProcessed: 1}, {SamplesProcessed: 2}},
 			}, {
 				downstreamRequests:  []MetricsQueryRequest{},
 				downstreamResponses: []Response{},
+				downstreamStats:     []*stats.Stats{},
 			}, {
 				downstreamRequests:  []MetricsQueryRequest{&PrometheusRangeQueryRequest{start: 3, id: 3}},
 				downstreamResponses: []Response{&PrometheusResponse{Status: "response-3"}},
+				downstreamStats:     []*stats.Stats{{SamplesProcessed: 3}},
 			}},
 		},
 		"should return error if a downstream response is missing": {
 			requests: splitRequests{{
 				downstreamRequests:  []MetricsQueryRequest{&PrometheusRangeQueryRequest{start: 1, id: 1}, &PrometheusRangeQueryRequest{start: 2, id: 2}},
 				downstreamResponses: []Response{nil, nil},
+				downstreamStats:     []*stats.Stats{nil, nil},
 			}, {
 				downstreamRequests:  []MetricsQueryRequest{&PrometheusRangeQueryRequest{start: 3, id: 3}},
 				downstreamResponses: []Response{nil},
+				downstreamStats:     []*stats.Stats{nil},
 			}},
 			responses: []requestResponse{{
 				Request:  &PrometheusRangeQueryRequest{start: 3, id: 3},
@@ -1414,9 +1516,11 @@ func TestSplitRequests_storeDownstreamResponses(t *testing.T) {
 			requests: splitRequests{{
 				downstreamRequests:  []MetricsQueryRequest{&PrometheusRangeQueryRequest{start: 1, id: 1}, &PrometheusRangeQueryRequest{start: 2, id: 2}},
 				downstreamResponses: []Response{nil, nil},
+				downstreamStats:     []*stats.Stats{nil, nil},
 			}, {
 				downstreamRequests:  []MetricsQueryRequest{&PrometheusRangeQueryRequest{start: 3, id: 3}},
 				downstreamResponses: []Response{nil},
+				downstreamStats:     []*stats.Stats{nil},
 			}},
 			responses: []requestResponse{{
 				Request:  &PrometheusRangeQueryRequest{start: 3, id: 3},
@@ -1431,9 +1535,11 @@ func TestSplitRequests_storeDownstreamResponses(t *testing.T) {
 			requests: splitRequests{{
 				downstreamRequests:  []MetricsQueryRequest{&PrometheusRangeQueryRequest{start: 1, id: 1}, &PrometheusRangeQueryRequest{start: 2, id: 2}},
 				downstreamResponses: []Response{nil, nil},
+				downstreamStats:     []*stats.Stats{nil, nil},
 			}, {
 				downstreamRequests:  []MetricsQueryRequest{&PrometheusRangeQueryRequest{start: 3, id: 3}},
 				downstreamResponses: []Response{nil},
+				downstreamStats:     []*stats.Stats{nil},
 			}},
 			responses: []requestResponse{{
 				Request:  &PrometheusRangeQueryRequest{start: 3, id: 3},
@@ -170,6 +170,17 @@ func (s *splitInstantQueryByIntervalMiddleware) Do(ctx context.Context, req Metr
 	s.metrics.splitQueries.Add(float64(mapperStats.GetSplitQueries()))
 	s.metrics.splitQueriesPerQuery.Observe(float64(mapperStats.GetSplitQueries()))
 
+	if plan := queryPlanFromContext(ctx); plan != nil {
+		plan.addRewrite(queryPlanRewrite{
+			Stage:          "split_instant_query_by_interval",
+			OriginalQuery:  req.GetQuery(),
+			RewrittenQuery: instantSplitQuery.String(),
+			Start:          req.GetStart(),
+			End:            req.GetEnd(),
+			SplitQueries:   mapperStats.GetSplitQueries(),
+		})
+	}
+
 	// Send hint with number of embedded queries to the sharding middleware
 	req, err = req.WithExpr(instantSplitQuery)
 	if err != nil {
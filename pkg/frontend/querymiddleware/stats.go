@@ -180,3 +180,62 @@ func QueryDetailsFromContext(ctx context.Context) *QueryDetails {
 	}
 	return o.(*QueryDetails)
 }
+
+// newPrometheusResponseStats returns the statistics to render in the response of a query run with the stats parameter.
+// The per-step statistics are included only if includePerStep is true.
+func newPrometheusResponseStats(s *stats.Stats, includePerStep bool) *PrometheusResponseStats {
+	var samplesPerStep []PrometheusResponseQueryableSamplesStatsPerStep
+	if includePerStep {
+		samplesPerStep = toPrometheusResponseSamplesPerStep(s.LoadSamplesProcessedPerStep())
+	}
+
+	return &PrometheusResponseStats{
+		Samples: &PrometheusResponseSamplesStats{
+			TotalQueryableSamples:        int64(s.LoadSamplesProcessed()),
+			TotalQueryableSamplesPerStep: samplesPerStep,
+		},
+		Querier: &PrometheusResponseQuerierStats{
+			WallTimeSeconds:      s.LoadWallTime().Seconds(),
+			FetchedSeriesCount:   s.LoadFetchedSeries(),
+			FetchedChunkBytes:    s.LoadFetchedChunkBytes(),
+			FetchedChunksCount:   s.LoadFetchedChunks(),
+			FetchedIndexBytes:    s.LoadFetchedIndexBytes(),
+			ShardedQueries:       s.LoadShardedQueries(),
+			SplitQueries:         s.LoadSplitQueries(),
+			EstimatedSeriesCount: s.LoadEstimatedSeriesCount(),
+			QueueTimeSeconds:     s.LoadQueueTime().Seconds(),
+		},
+	}
+}
+
+// toPrometheusResponseSamplesPerStep converts the number of samples processed at each step to its protobuf representation.
+func toPrometheusResponseSamplesPerStep(steps []stats.StepStat) []PrometheusResponseQueryableSamplesStatsPerStep {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	out := make([]PrometheusResponseQueryableSamplesStatsPerStep, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, PrometheusResponseQueryableSamplesStatsPerStep{
+			TimestampMs: step.Timestamp,
+			Value:       step.Value,
+		})
+	}
+	return out
+}
+
+// addCachedSamplesProcessed adds the number of samples processed at each step of a cached response
+// to the query stats, as well as their total.
+func addCachedSamplesProcessed(s *stats.Stats, steps []stats.StepStat) {
+	if len(steps) == 0 {
+		return
+	}
+
+	total := uint64(0)
+	for _, step := range steps {
+		total += uint64(step.Value)
+	}
+
+	s.AddSamplesProcessed(total)
+	s.AddSamplesProcessedPerStep(steps)
+}
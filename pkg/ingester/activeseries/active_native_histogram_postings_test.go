@@ -24,7 +24,7 @@ func TestNativeHistogramPostings_Expand(t *testing.T) {
 	}
 	allStorageRefs := []storage.SeriesRef{1, 2, 3, 4, 5}
 	storagePostings := index.NewListPostings(allStorageRefs)
-	activeSeries := NewActiveSeries(&Matchers{}, time.Duration(ttl))
+	activeSeries := NewActiveSeries(&Matchers{}, nil, time.Duration(ttl))
 
 	// Update each series at a different time according to its index.
 	for i := range allStorageRefs {
@@ -60,7 +60,7 @@ func TestNativeHistogramPostings_ExpandWithBucketCount(t *testing.T) {
 	}
 	allStorageRefs := []storage.SeriesRef{1, 2, 3, 4, 5}
 	storagePostings := index.NewListPostings(allStorageRefs)
-	activeSeries := NewActiveSeries(&Matchers{}, time.Duration(ttl))
+	activeSeries := NewActiveSeries(&Matchers{}, nil, time.Duration(ttl))
 
 	// Update each series at a different time according to its index.
 	for i := range allStorageRefs {
@@ -104,7 +104,7 @@ func TestNativeHistogramPostings_SeekSkipsNonNative(t *testing.T) {
 	}
 	allStorageRefs := []storage.SeriesRef{1, 2, 3, 4, 5}
 	storagePostings := index.NewListPostings(allStorageRefs)
-	activeSeries := NewActiveSeries(&Matchers{}, time.Duration(ttl))
+	activeSeries := NewActiveSeries(&Matchers{}, nil, time.Duration(ttl))
 
 	// Update each series at a different time according to its index.
 	for i := range allStorageRefs {
@@ -144,7 +144,7 @@ func TestNativeHistogramPostings_Seek(t *testing.T) {
 	}
 	allStorageRefs := []storage.SeriesRef{1, 2, 3, 4, 5}
 	storagePostings := index.NewListPostings(allStorageRefs)
-	activeSeries := NewActiveSeries(&Matchers{}, time.Duration(ttl))
+	activeSeries := NewActiveSeries(&Matchers{}, nil, time.Duration(ttl))
 
 	// Update each series at a different time according to its index.
 	for i := range allStorageRefs {
@@ -181,7 +181,7 @@ func TestNativeHistogramPostings_SeekToEnd(t *testing.T) {
 	}
 	allStorageRefs := []storage.SeriesRef{1, 2, 3, 4, 5}
 	storagePostings := index.NewListPostings(allStorageRefs)
-	activeSeries := NewActiveSeries(&Matchers{}, time.Duration(ttl))
+	activeSeries := NewActiveSeries(&Matchers{}, nil, time.Duration(ttl))
 
 	// Update each series at a different time according to its index.
 	for i := range allStorageRefs {
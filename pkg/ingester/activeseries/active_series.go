@@ -16,6 +16,8 @@ import (
 	"github.com/prometheus/prometheus/tsdb/chunks"
 	"github.com/prometheus/prometheus/util/zeropool"
 	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/util"
 )
 
 const (
@@ -42,9 +44,10 @@ type ActiveSeries struct {
 	stripes [numStripes]seriesStripe
 	deleted deletedSeries
 
-	// matchersMutex protects matchers and lastMatchersUpdate.
+	// matchersMutex protects matchers, costAttribution and lastMatchersUpdate.
 	matchersMutex      sync.RWMutex
 	matchers           *Matchers
+	costAttribution    *util.CostAttributionTracker
 	lastMatchersUpdate time.Time
 
 	// The duration after which series become inactive.
@@ -54,7 +57,8 @@ type ActiveSeries struct {
 
 // seriesStripe holds a subset of the series timestamps for a single tenant.
 type seriesStripe struct {
-	matchers *Matchers
+	matchers        *Matchers
+	costAttribution *util.CostAttributionTracker
 
 	deleted *deletedSeries
 
@@ -65,12 +69,13 @@ type seriesStripe struct {
 
 	mu                                   sync.RWMutex
 	refs                                 map[storage.SeriesRef]seriesEntry
-	active                               uint32   // Number of active entries in this stripe. Only decreased during purge or clear.
-	activeMatching                       []uint32 // Number of active entries in this stripe matching each matcher of the configured Matchers.
-	activeNativeHistograms               uint32   // Number of active entries (only native histograms) in this stripe. Only decreased during purge or clear.
-	activeMatchingNativeHistograms       []uint32 // Number of active entries (only native histograms) in this stripe matching each matcher of the configured Matchers.
-	activeNativeHistogramBuckets         uint32   // Number of buckets in active native histogram entries in this stripe. Only decreased during purge or clear.
-	activeMatchingNativeHistogramBuckets []uint32 // Number of buckets in active native histogram entries in this stripe matching each matcher of the configured Matchers.
+	active                               uint32            // Number of active entries in this stripe. Only decreased during purge or clear.
+	activeMatching                       []uint32          // Number of active entries in this stripe matching each matcher of the configured Matchers.
+	activeNativeHistograms               uint32            // Number of active entries (only native histograms) in this stripe. Only decreased during purge or clear.
+	activeMatchingNativeHistograms       []uint32          // Number of active entries (only native histograms) in this stripe matching each matcher of the configured Matchers.
+	activeNativeHistogramBuckets         uint32            // Number of buckets in active native histogram entries in this stripe. Only decreased during purge or clear.
+	activeMatchingNativeHistogramBuckets []uint32          // Number of buckets in active native histogram entries in this stripe matching each matcher of the configured Matchers.
+	activeCostAttribution                map[string]uint32 // Number of active entries in this stripe by cost attribution, nil if cost attribution is disabled.
 }
 
 // seriesEntry holds a timestamp for single series.
@@ -78,16 +83,18 @@ type seriesEntry struct {
 	nanos                     *atomic.Int64        // Unix timestamp in nanoseconds. Needs to be a pointer because we don't store pointers to entries in the stripe.
 	matches                   preAllocDynamicSlice //  Index of the matcher matching
 	numNativeHistogramBuckets int                  // Number of buckets in native histogram series, -1 if not a native histogram.
+	attribution               string               // Cost attribution of the series, empty if cost attribution is disabled.
 
 	deleted bool // This series was marked as deleted, so before purging we need to remove the refence to it from the deletedSeries.
 }
 
-func NewActiveSeries(asm *Matchers, timeout time.Duration) *ActiveSeries {
-	c := &ActiveSeries{matchers: asm, timeout: timeout}
+// NewActiveSeries creates a new ActiveSeries. The cost attribution tracker ca can be nil, if cost attribution is disabled.
+func NewActiveSeries(asm *Matchers, ca *util.CostAttributionTracker, timeout time.Duration) *ActiveSeries {
+	c := &ActiveSeries{matchers: asm, costAttribution: ca, timeout: timeout}
 
 	// Stripes are pre-allocated so that we only read on them and no lock is required.
 	for i := 0; i < numStripes; i++ {
-		c.stripes[i].reinitialize(asm, &c.deleted)
+		c.stripes[i].reinitialize(asm, ca, &c.deleted)
 	}
 
 	return c
@@ -104,12 +111,31 @@ func (c *ActiveSeries) ReloadMatchers(asm *Matchers, now time.Time) {
 	defer c.matchersMutex.Unlock()
 
 	for i := 0; i < numStripes; i++ {
-		c.stripes[i].reinitialize(asm, &c.deleted)
+		c.stripes[i].reinitialize(asm, c.costAttribution, &c.deleted)
 	}
 	c.matchers = asm
 	c.lastMatchersUpdate = now
 }
 
+// CurrentCostAttribution returns the cost attribution tracker, or nil if cost attribution is disabled.
+func (c *ActiveSeries) CurrentCostAttribution() *util.CostAttributionTracker {
+	c.matchersMutex.RLock()
+	defer c.matchersMutex.RUnlock()
+	return c.costAttribution
+}
+
+// ReloadCostAttribution replaces the cost attribution tracker. Like ReloadMatchers, it resets the tracked series.
+func (c *ActiveSeries) ReloadCostAttribution(ca *util.CostAttributionTracker, now time.Time) {
+	c.matchersMutex.Lock()
+	defer c.matchersMutex.Unlock()
+
+	for i := 0; i < numStripes; i++ {
+		c.stripes[i].reinitialize(c.matchers, ca, &c.deleted)
+	}
+	c.costAttribution = ca
+	c.lastMatchersUpdate = now
+}
+
 func (c *ActiveSeries) CurrentConfig() CustomTrackersConfig {
 	c.matchersMutex.RLock()
 	defer c.matchersMutex.RUnlock()
@@ -153,6 +179,14 @@ func (c *ActiveSeries) Purge(now time.Time) bool {
 	purgeTime := now.Add(-c.timeout)
 	c.purge(purgeTime)
 
+	if c.costAttribution != nil {
+		// Forget the attributions without active series, so that they don't count towards the cardinality limit.
+		active := c.activeByCostAttribution()
+		c.costAttribution.Retain(func(value string) bool {
+			return active[value] > 0
+		})
+	}
+
 	return !c.lastMatchersUpdate.After(purgeTime)
 }
 
@@ -210,6 +244,27 @@ func (c *ActiveSeries) ActiveWithMatchers() (total int, totalMatching []int, tot
 	return
 }
 
+// ActiveByCostAttribution returns the number of active series by cost attribution,
+// or nil if cost attribution is disabled. This method does not purge expired entries,
+// so Purge should be called periodically.
+func (c *ActiveSeries) ActiveByCostAttribution() map[string]int {
+	c.matchersMutex.RLock()
+	defer c.matchersMutex.RUnlock()
+
+	if c.costAttribution == nil {
+		return nil
+	}
+	return c.activeByCostAttribution()
+}
+
+func (c *ActiveSeries) activeByCostAttribution() map[string]int {
+	active := map[string]int{}
+	for s := 0; s < numStripes; s++ {
+		c.stripes[s].updateActiveByCostAttribution(active)
+	}
+	return active
+}
+
 func (c *ActiveSeries) Delete(ref chunks.HeadSeriesRef) {
 	stripeID := storage.SeriesRef(ref) % numStripes
 	c.stripes[stripeID].remove(storage.SeriesRef(ref))
@@ -290,6 +345,16 @@ func (s *seriesStripe) getTotalAndUpdateMatching(matching []int, matchingNativeH
 	return s.active, s.activeNativeHistograms, s.activeNativeHistogramBuckets
 }
 
+// updateActiveByCostAttribution adds the active series in the stripe by cost attribution to active.
+func (s *seriesStripe) updateActiveByCostAttribution(active map[string]int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for attribution, a := range s.activeCostAttribution {
+		active[attribution] += int(a)
+	}
+}
+
 func (s *seriesStripe) updateSeriesTimestamp(now time.Time, series labels.Labels, ref storage.SeriesRef, numNativeHistogramBuckets int) bool {
 	nowNanos := now.UnixNano()
 
@@ -391,6 +456,10 @@ func (s *seriesStripe) findAndUpdateOrCreateEntryForSeries(ref storage.SeriesRef
 		matches:                   matches,
 		numNativeHistogramBuckets: numNativeHistogramBuckets,
 	}
+	if s.costAttribution != nil {
+		e.attribution = s.costAttribution.Attribute(series.Get(s.costAttribution.Label()), time.Unix(0, nowNanos))
+		s.activeCostAttribution[e.attribution]++
+	}
 
 	s.refs[ref] = e
 	return e.nanos, true
@@ -410,10 +479,11 @@ func (s *seriesStripe) clear() {
 		s.activeMatchingNativeHistograms[i] = 0
 		s.activeMatchingNativeHistogramBuckets[i] = 0
 	}
+	clear(s.activeCostAttribution)
 }
 
-// Reinitialize assigns new matchers and corresponding size activeMatching slices.
-func (s *seriesStripe) reinitialize(asm *Matchers, deleted *deletedSeries) {
+// Reinitialize assigns new matchers and corresponding size activeMatching slices, and the cost attribution tracker.
+func (s *seriesStripe) reinitialize(asm *Matchers, ca *util.CostAttributionTracker, deleted *deletedSeries) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -427,6 +497,11 @@ func (s *seriesStripe) reinitialize(asm *Matchers, deleted *deletedSeries) {
 	s.activeMatching = resizeAndClear(len(asm.MatcherNames()), s.activeMatching)
 	s.activeMatchingNativeHistograms = resizeAndClear(len(asm.MatcherNames()), s.activeMatchingNativeHistograms)
 	s.activeMatchingNativeHistogramBuckets = resizeAndClear(len(asm.MatcherNames()), s.activeMatchingNativeHistogramBuckets)
+	s.costAttribution = ca
+	s.activeCostAttribution = nil
+	if ca != nil {
+		s.activeCostAttribution = map[string]uint32{}
+	}
 }
 
 func (s *seriesStripe) purge(keepUntil time.Time) {
@@ -445,6 +520,7 @@ func (s *seriesStripe) purge(keepUntil time.Time) {
 	s.activeMatching = resizeAndClear(len(s.activeMatching), s.activeMatching)
 	s.activeMatchingNativeHistograms = resizeAndClear(len(s.activeMatchingNativeHistograms), s.activeMatchingNativeHistograms)
 	s.activeMatchingNativeHistogramBuckets = resizeAndClear(len(s.activeMatchingNativeHistogramBuckets), s.activeMatchingNativeHistogramBuckets)
+	clear(s.activeCostAttribution)
 
 	oldest := int64(math.MaxInt64)
 	for ref, entry := range s.refs {
@@ -471,6 +547,9 @@ func (s *seriesStripe) purge(keepUntil time.Time) {
 				s.activeMatchingNativeHistogramBuckets[match] += uint32(entry.numNativeHistogramBuckets)
 			}
 		}
+		if s.activeCostAttribution != nil {
+			s.activeCostAttribution[entry.attribution]++
+		}
 		if ts < oldest {
 			oldest = ts
 		}
@@ -513,6 +592,11 @@ func (s *seriesStripe) remove(ref storage.SeriesRef) {
 			s.activeMatchingNativeHistogramBuckets[match] -= uint32(entry.numNativeHistogramBuckets)
 		}
 	}
+	if s.activeCostAttribution != nil {
+		if s.activeCostAttribution[entry.attribution]--; s.activeCostAttribution[entry.attribution] == 0 {
+			delete(s.activeCostAttribution, entry.attribution)
+		}
+	}
 
 	s.deleted.purge(ref)
 	delete(s.refs, ref)
@@ -18,6 +18,8 @@ import (
 	"github.com/prometheus/prometheus/tsdb/chunks"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/util"
 )
 
 const DefaultTimeout = 5 * time.Minute
@@ -29,7 +31,7 @@ func TestActiveSeries_UpdateSeries_NoMatchers(t *testing.T) {
 	ref4, ls4 := storage.SeriesRef(4), labels.FromStrings("a", "4")
 	ref5 := storage.SeriesRef(5) // will be used for ls1 again.
 
-	c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 	valid := c.Purge(time.Now())
 	assert.True(t, valid)
 	allActive, activeMatching, allActiveHistograms, activeMatchingHistograms, allActiveBuckets, activeMatchingBuckets := c.ActiveWithMatchers()
@@ -194,7 +196,7 @@ func TestActiveSeries_ContainsRef(t *testing.T) {
 	for ttl := 1; ttl <= len(series); ttl++ {
 		t.Run(fmt.Sprintf("ttl: %d", ttl), func(t *testing.T) {
 			mockedTime := time.Unix(int64(ttl), 0)
-			c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+			c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 
 			// Update each series with a different timestamp according to each index
 			for i := 0; i < len(series); i++ {
@@ -221,7 +223,7 @@ func TestActiveSeries_ContainsRef(t *testing.T) {
 
 func TestActiveSeries_UpdateSeries_WithMatchers(t *testing.T) {
 	asm := NewMatchers(mustNewCustomTrackersConfigFromMap(t, map[string]string{"foo": `{a=~"2|3|4"}`}))
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 	testUpdateSeries(t, c)
 }
 
@@ -438,7 +440,7 @@ func testUpdateSeries(t *testing.T, c *ActiveSeries) {
 
 func TestActiveSeries_UpdateSeries_Clear(t *testing.T) {
 	asm := NewMatchers(mustNewCustomTrackersConfigFromMap(t, map[string]string{"foo": `{a=~"2|3|4"}`}))
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 	testUpdateSeries(t, c)
 
 	c.Clear()
@@ -479,7 +481,7 @@ func TestActiveSeries_ShouldCorrectlyHandleHashCollisions(t *testing.T) {
 	ls1, ls2 := labelsWithHashCollision()
 	ref1, ref2 := storage.SeriesRef(1), storage.SeriesRef(2)
 
-	c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 	c.UpdateSeries(ls1, ref1, time.Now(), -1)
 	c.UpdateSeries(ls2, ref2, time.Now(), -1)
 
@@ -507,7 +509,7 @@ func TestActiveSeries_Purge_NoMatchers(t *testing.T) {
 	for ttl := 1; ttl <= len(series); ttl++ {
 		t.Run(fmt.Sprintf("ttl: %d", ttl), func(t *testing.T) {
 			mockedTime := time.Unix(int64(ttl), 0)
-			c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+			c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 
 			for i := 0; i < len(series); i++ {
 				c.UpdateSeries(series[i], refs[i], time.Unix(int64(i), 0), -1)
@@ -553,7 +555,7 @@ func TestActiveSeries_Purge_WithMatchers(t *testing.T) {
 		t.Run(fmt.Sprintf("ttl=%d", ttl), func(t *testing.T) {
 			mockedTime := time.Unix(int64(ttl), 0)
 
-			c := NewActiveSeries(asm, 5*time.Minute)
+			c := NewActiveSeries(asm, nil, 5*time.Minute)
 
 			exp := len(series) - ttl
 			expMatchingSeries := 0
@@ -580,12 +582,50 @@ func TestActiveSeries_Purge_WithMatchers(t *testing.T) {
 	}
 }
 
+func TestActiveSeries_CostAttribution(t *testing.T) {
+	ref1, ls1 := storage.SeriesRef(1), labels.FromStrings("a", "1", "team", "a")
+	ref2, ls2 := storage.SeriesRef(2), labels.FromStrings("a", "2", "team", "a")
+	ref3, ls3 := storage.SeriesRef(3), labels.FromStrings("a", "3", "team", "b")
+	ref4, ls4 := storage.SeriesRef(4), labels.FromStrings("a", "4")
+
+	currentTime := time.Now()
+	c := NewActiveSeries(&Matchers{}, util.NewCostAttributionTracker("team", 2), DefaultTimeout)
+	c.UpdateSeries(ls1, ref1, currentTime, -1)
+	c.UpdateSeries(ls2, ref2, currentTime, -1)
+	c.UpdateSeries(ls3, ref3, currentTime, -1)
+	c.UpdateSeries(ls4, ref4, currentTime.Add(time.Minute), -1)
+	assert.True(t, c.Purge(currentTime.Add(time.Minute)))
+	assert.Equal(t, map[string]int{"a": 2, "b": 1, util.CostAttributionOverflowValue: 1}, c.ActiveByCostAttribution())
+
+	c.Delete(chunks.HeadSeriesRef(ref3))
+	assert.Equal(t, map[string]int{"a": 2, util.CostAttributionOverflowValue: 1}, c.ActiveByCostAttribution())
+
+	// Purging frees the attributions without active series, so that new ones can be tracked.
+	currentTime = currentTime.Add(DefaultTimeout + 30*time.Second)
+	c.UpdateSeries(ls4, ref4, currentTime, -1)
+	assert.True(t, c.Purge(currentTime))
+	assert.Equal(t, map[string]int{util.CostAttributionOverflowValue: 1}, c.ActiveByCostAttribution())
+	c.UpdateSeries(ls3, ref3, currentTime, -1)
+	assert.Equal(t, map[string]int{"b": 1, util.CostAttributionOverflowValue: 1}, c.ActiveByCostAttribution())
+
+	// Reloading the cost attribution resets the tracked series.
+	c.ReloadCostAttribution(util.NewCostAttributionTracker("a", 0), currentTime)
+	assert.False(t, c.Purge(currentTime))
+	c.UpdateSeries(ls3, ref3, currentTime, -1)
+	c.UpdateSeries(ls4, ref4, currentTime, -1)
+	assert.Equal(t, map[string]int{"3": 1, "4": 1}, c.ActiveByCostAttribution())
+	assert.Equal(t, "a", c.CurrentCostAttribution().Label())
+
+	c.ReloadCostAttribution(nil, currentTime)
+	assert.Nil(t, c.ActiveByCostAttribution())
+}
+
 func TestActiveSeries_PurgeOpt(t *testing.T) {
 	ls1, ls2 := labelsWithHashCollision()
 	ref1, ref2 := storage.SeriesRef(1), storage.SeriesRef(2)
 
 	currentTime := time.Now()
-	c := NewActiveSeries(&Matchers{}, 59*time.Second)
+	c := NewActiveSeries(&Matchers{}, nil, 59*time.Second)
 
 	c.UpdateSeries(ls1, ref1, currentTime.Add(-2*time.Minute), -1)
 	c.UpdateSeries(ls2, ref2, currentTime, -1)
@@ -621,7 +661,7 @@ func TestActiveSeries_ReloadSeriesMatchers(t *testing.T) {
 	asm := NewMatchers(mustNewCustomTrackersConfigFromMap(t, map[string]string{"foo": `{a=~.*}`}))
 
 	currentTime := time.Now()
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 
 	valid := c.Purge(currentTime)
 	assert.True(t, valid)
@@ -687,7 +727,7 @@ func TestActiveSeries_ReloadSeriesMatchers_LessMatchers(t *testing.T) {
 	}))
 
 	currentTime := time.Now()
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 	valid := c.Purge(currentTime)
 	assert.True(t, valid)
 	allActive, activeMatching, _, _, _, _ := c.ActiveWithMatchers()
@@ -726,7 +766,7 @@ func TestActiveSeries_ReloadSeriesMatchers_SameSizeNewLabels(t *testing.T) {
 
 	currentTime := time.Now()
 
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 	valid := c.Purge(currentTime)
 	assert.True(t, valid)
 	allActive, activeMatching, _, _, _, _ := c.ActiveWithMatchers()
@@ -779,7 +819,7 @@ func benchmarkActiveSeriesUpdateSeriesConcurrency(b *testing.B, numSeries, numGo
 	var (
 		// Run the active series tracker with an active timeout = 0 so that the Purge() will always
 		// purge the series.
-		c           = NewActiveSeries(&Matchers{}, 0)
+		c           = NewActiveSeries(&Matchers{}, nil, 0)
 		updateGroup = &sync.WaitGroup{}
 		purgeGroup  = &sync.WaitGroup{}
 		start       = make(chan struct{})
@@ -917,7 +957,7 @@ func BenchmarkActiveSeries_UpdateSeries(b *testing.B) {
 
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				c := NewActiveSeries(asm, DefaultTimeout)
+				c := NewActiveSeries(asm, nil, DefaultTimeout)
 				for round := 0; round <= tt.nRounds; round++ {
 					for ix := 0; ix < tt.nSeries; ix++ {
 						c.UpdateSeries(series[ix], refs[ix], time.Unix(0, now), -1)
@@ -942,7 +982,7 @@ func benchmarkPurge(b *testing.B, twice bool) {
 	const numExpiresSeries = numSeries / 25
 
 	currentTime := time.Now()
-	c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 
 	series := [numSeries]labels.Labels{}
 	refs := [numSeries]storage.SeriesRef{}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+)
+
+// cardinalityHistory keeps the latest cardinality samples recorded for a tenant in a bounded ring.
+type cardinalityHistory struct {
+	mtx     sync.Mutex
+	samples []*client.CardinalityHistorySample
+	next    int // Index of the ring where the next sample is written.
+	count   int
+}
+
+func newCardinalityHistory(size int) *cardinalityHistory {
+	return &cardinalityHistory{
+		samples: make([]*client.CardinalityHistorySample, size),
+	}
+}
+
+// add adds the sample to the history, replacing the oldest sample if the history is full.
+// If the latest sample has the same timestamp, it's replaced by the new one.
+func (h *cardinalityHistory) add(sample *client.CardinalityHistorySample) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if h.count > 0 {
+		last := (h.next - 1 + len(h.samples)) % len(h.samples)
+		if h.samples[last].TimestampMs == sample.TimestampMs {
+			h.samples[last] = sample
+			return
+		}
+	}
+
+	h.samples[h.next] = sample
+	h.next = (h.next + 1) % len(h.samples)
+	if h.count < len(h.samples) {
+		h.count++
+	}
+}
+
+// samplesBetween returns the samples recorded between start and end, both included, sorted by timestamp.
+// A 0 start or end means no boundary. The returned samples must not be modified.
+func (h *cardinalityHistory) samplesBetween(start, end int64) []*client.CardinalityHistorySample {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	result := make([]*client.CardinalityHistorySample, 0, h.count)
+	for n := 0; n < h.count; n++ {
+		sample := h.samples[(h.next-h.count+n+len(h.samples))%len(h.samples)]
+		if (start > 0 && sample.TimestampMs < start) || (end > 0 && sample.TimestampMs > end) {
+			continue
+		}
+		result = append(result, sample)
+	}
+	return result
+}
+
+// recordCardinalityHistory records a cardinality sample for each tenant. The sample timestamp is aligned to the
+// cardinality history interval, so that the samples recorded by different ingesters can be merged.
+func (i *Ingester) recordCardinalityHistory(ctx context.Context) error {
+	ts := time.Now().Truncate(i.cfg.CardinalityHistoryInterval).UnixMilli()
+
+	for _, userID := range i.getTSDBUsers() {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		db := i.getTSDB(userID)
+		if db == nil || db.cardinalityHistory == nil {
+			continue
+		}
+
+		db.cardinalityHistory.add(newCardinalityHistorySample(db.Head(), ts, i.cfg.CardinalityHistoryTopN))
+	}
+
+	return nil
+}
+
+// newCardinalityHistorySample returns a sample of the number of series of the head, with the top metric names in
+// the __name__ item, and the top label name-value pairs of the other labels, by number of series.
+func newCardinalityHistorySample(head *tsdb.Head, ts int64, topN int) *client.CardinalityHistorySample {
+	stats := head.PostingsCardinalityStats(labels.MetricName, topN)
+
+	var items []*client.LabelValueSeriesCount
+	itemsByName := map[string]*client.LabelValueSeriesCount{}
+	getItem := func(name string) *client.LabelValueSeriesCount {
+		item, ok := itemsByName[name]
+		if !ok {
+			item = &client.LabelValueSeriesCount{LabelName: name, LabelValueSeries: map[string]uint64{}}
+			itemsByName[name] = item
+			items = append(items, item)
+		}
+		return item
+	}
+
+	for _, stat := range stats.CardinalityMetricsStats {
+		getItem(labels.MetricName).LabelValueSeries[stat.Name] = stat.Count
+	}
+	for _, stat := range stats.LabelValuePairsStats {
+		name, value, ok := strings.Cut(stat.Name, "=")
+		// The metric names are already tracked above.
+		if !ok || name == labels.MetricName {
+			continue
+		}
+		getItem(name).LabelValueSeries[value] = stat.Count
+	}
+
+	return &client.CardinalityHistorySample{
+		TimestampMs: ts,
+		NumSeries:   head.NumSeries(),
+		Items:       items,
+	}
+}
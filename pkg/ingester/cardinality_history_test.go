@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+)
+
+func TestCardinalityHistory(t *testing.T) {
+	sample := func(ts int64) *client.CardinalityHistorySample {
+		return &client.CardinalityHistorySample{TimestampMs: ts, NumSeries: uint64(ts)}
+	}
+	timestamps := func(samples []*client.CardinalityHistorySample) []int64 {
+		var result []int64
+		for _, s := range samples {
+			result = append(result, s.TimestampMs)
+		}
+		return result
+	}
+
+	h := newCardinalityHistory(3)
+	assert.Empty(t, h.samplesBetween(0, 0))
+
+	h.add(sample(10))
+	h.add(sample(20))
+	assert.Equal(t, []int64{10, 20}, timestamps(h.samplesBetween(0, 0)))
+
+	// A sample with the same timestamp of the latest one replaces it.
+	h.add(&client.CardinalityHistorySample{TimestampMs: 20, NumSeries: 100})
+	require.Len(t, h.samplesBetween(0, 0), 2)
+	assert.Equal(t, uint64(100), h.samplesBetween(20, 20)[0].NumSeries)
+
+	// The oldest samples are replaced once the history is full.
+	h.add(sample(30))
+	h.add(sample(40))
+	h.add(sample(50))
+	assert.Equal(t, []int64{30, 40, 50}, timestamps(h.samplesBetween(0, 0)))
+
+	assert.Equal(t, []int64{40, 50}, timestamps(h.samplesBetween(35, 0)))
+	assert.Equal(t, []int64{30, 40}, timestamps(h.samplesBetween(0, 45)))
+	assert.Equal(t, []int64{40}, timestamps(h.samplesBetween(40, 40)))
+	assert.Empty(t, h.samplesBetween(60, 0))
+}
+
+func TestIngester_CardinalityHistory(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.CardinalityHistoryInterval = time.Minute
+	cfg.CardinalityHistorySize = 10
+	cfg.CardinalityHistoryTopN = 2
+
+	i := requireActiveIngesterWithBlocksStorage(t, cfg, prometheus.NewRegistry())
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	require.NoError(t, pushSeriesToIngester(ctx, t, i, []series{
+		{lbls: labels.FromStrings(labels.MetricName, "metric_0", "status", "500"), value: 1, timestamp: 100000},
+		{lbls: labels.FromStrings(labels.MetricName, "metric_0", "status", "200"), value: 1, timestamp: 100000},
+		{lbls: labels.FromStrings(labels.MetricName, "metric_1", "status", "500"), value: 1, timestamp: 100000},
+	}))
+
+	require.NoError(t, i.recordCardinalityHistory(context.Background()))
+
+	res, err := i.CardinalityHistory(ctx, &client.CardinalityHistoryRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Samples, 1)
+
+	sample := res.Samples[0]
+	assert.Zero(t, sample.TimestampMs%time.Minute.Milliseconds())
+	assert.Equal(t, uint64(3), sample.NumSeries)
+	assert.ElementsMatch(t, []*client.LabelValueSeriesCount{
+		{LabelName: labels.MetricName, LabelValueSeries: map[string]uint64{"metric_0": 2, "metric_1": 1}},
+		{LabelName: "status", LabelValueSeries: map[string]uint64{"500": 2}},
+	}, sample.Items)
+
+	// The request time range is honored.
+	res, err = i.CardinalityHistory(ctx, &client.CardinalityHistoryRequest{StartTimestampMs: sample.TimestampMs + 1})
+	require.NoError(t, err)
+	assert.Empty(t, res.Samples)
+
+	// Tenants without series have no history.
+	res, err = i.CardinalityHistory(user.InjectOrgID(context.Background(), "other"), &client.CardinalityHistoryRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, res.Samples)
+}
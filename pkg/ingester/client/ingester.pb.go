@@ -102,7 +102,7 @@ var ActiveSeriesRequest_RequestType_value = map[string]int32{
 }
 
 func (ActiveSeriesRequest_RequestType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{8, 0}
+	return fileDescriptor_60f6df4f3586b478, []int{11, 0}
 }
 
 type LabelNamesAndValuesRequest struct {
@@ -403,6 +403,164 @@ func (m *LabelValueSeriesCount) GetLabelValueSeries() map[string]uint64 {
 	return nil
 }
 
+type CardinalityHistoryRequest struct {
+	// Only the samples recorded between start_timestamp_ms and end_timestamp_ms (both included) are returned.
+	// 0 means no boundary.
+	StartTimestampMs int64 `protobuf:"varint,1,opt,name=start_timestamp_ms,json=startTimestampMs,proto3" json:"start_timestamp_ms,omitempty"`
+	EndTimestampMs   int64 `protobuf:"varint,2,opt,name=end_timestamp_ms,json=endTimestampMs,proto3" json:"end_timestamp_ms,omitempty"`
+}
+
+func (m *CardinalityHistoryRequest) Reset()      { *m = CardinalityHistoryRequest{} }
+func (*CardinalityHistoryRequest) ProtoMessage() {}
+func (*CardinalityHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{6}
+}
+func (m *CardinalityHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CardinalityHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CardinalityHistoryRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CardinalityHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CardinalityHistoryRequest.Merge(m, src)
+}
+func (m *CardinalityHistoryRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CardinalityHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CardinalityHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CardinalityHistoryRequest proto.InternalMessageInfo
+
+func (m *CardinalityHistoryRequest) GetStartTimestampMs() int64 {
+	if m != nil {
+		return m.StartTimestampMs
+	}
+	return 0
+}
+
+func (m *CardinalityHistoryRequest) GetEndTimestampMs() int64 {
+	if m != nil {
+		return m.EndTimestampMs
+	}
+	return 0
+}
+
+type CardinalityHistoryResponse struct {
+	// Samples sorted by timestamp.
+	Samples []*CardinalityHistorySample `protobuf:"bytes,1,rep,name=samples,proto3" json:"samples,omitempty"`
+}
+
+func (m *CardinalityHistoryResponse) Reset()      { *m = CardinalityHistoryResponse{} }
+func (*CardinalityHistoryResponse) ProtoMessage() {}
+func (*CardinalityHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{7}
+}
+func (m *CardinalityHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CardinalityHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CardinalityHistoryResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CardinalityHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CardinalityHistoryResponse.Merge(m, src)
+}
+func (m *CardinalityHistoryResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CardinalityHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CardinalityHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CardinalityHistoryResponse proto.InternalMessageInfo
+
+func (m *CardinalityHistoryResponse) GetSamples() []*CardinalityHistorySample {
+	if m != nil {
+		return m.Samples
+	}
+	return nil
+}
+
+type CardinalityHistorySample struct {
+	TimestampMs int64 `protobuf:"varint,1,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	// Number of in-memory series of the tenant.
+	NumSeries uint64 `protobuf:"varint,2,opt,name=num_series,json=numSeries,proto3" json:"num_series,omitempty"`
+	// Top metric names, in the __name__ item, and top label name-value pairs by number of in-memory series.
+	Items []*LabelValueSeriesCount `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (m *CardinalityHistorySample) Reset()      { *m = CardinalityHistorySample{} }
+func (*CardinalityHistorySample) ProtoMessage() {}
+func (*CardinalityHistorySample) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{8}
+}
+func (m *CardinalityHistorySample) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CardinalityHistorySample) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CardinalityHistorySample.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CardinalityHistorySample) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CardinalityHistorySample.Merge(m, src)
+}
+func (m *CardinalityHistorySample) XXX_Size() int {
+	return m.Size()
+}
+func (m *CardinalityHistorySample) XXX_DiscardUnknown() {
+	xxx_messageInfo_CardinalityHistorySample.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CardinalityHistorySample proto.InternalMessageInfo
+
+func (m *CardinalityHistorySample) GetTimestampMs() int64 {
+	if m != nil {
+		return m.TimestampMs
+	}
+	return 0
+}
+
+func (m *CardinalityHistorySample) GetNumSeries() uint64 {
+	if m != nil {
+		return m.NumSeries
+	}
+	return 0
+}
+
+func (m *CardinalityHistorySample) GetItems() []*LabelValueSeriesCount {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
 type QueryRequest struct {
 	StartTimestampMs int64           `protobuf:"varint,1,opt,name=start_timestamp_ms,json=startTimestampMs,proto3" json:"start_timestamp_ms,omitempty"`
 	EndTimestampMs   int64           `protobuf:"varint,2,opt,name=end_timestamp_ms,json=endTimestampMs,proto3" json:"end_timestamp_ms,omitempty"`
@@ -414,7 +572,7 @@ type QueryRequest struct {
 func (m *QueryRequest) Reset()      { *m = QueryRequest{} }
 func (*QueryRequest) ProtoMessage() {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{6}
+	return fileDescriptor_60f6df4f3586b478, []int{9}
 }
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -480,7 +638,7 @@ type ExemplarQueryRequest struct {
 func (m *ExemplarQueryRequest) Reset()      { *m = ExemplarQueryRequest{} }
 func (*ExemplarQueryRequest) ProtoMessage() {}
 func (*ExemplarQueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{7}
+	return fileDescriptor_60f6df4f3586b478, []int{10}
 }
 func (m *ExemplarQueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -538,7 +696,7 @@ type ActiveSeriesRequest struct {
 func (m *ActiveSeriesRequest) Reset()      { *m = ActiveSeriesRequest{} }
 func (*ActiveSeriesRequest) ProtoMessage() {}
 func (*ActiveSeriesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{8}
+	return fileDescriptor_60f6df4f3586b478, []int{11}
 }
 func (m *ActiveSeriesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -588,7 +746,7 @@ type QueryResponse struct {
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
 func (*QueryResponse) ProtoMessage() {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{9}
+	return fileDescriptor_60f6df4f3586b478, []int{12}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -643,7 +801,7 @@ type QueryStreamResponse struct {
 func (m *QueryStreamResponse) Reset()      { *m = QueryStreamResponse{} }
 func (*QueryStreamResponse) ProtoMessage() {}
 func (*QueryStreamResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{10}
+	return fileDescriptor_60f6df4f3586b478, []int{13}
 }
 func (m *QueryStreamResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -715,7 +873,7 @@ type QueryStreamSeries struct {
 func (m *QueryStreamSeries) Reset()      { *m = QueryStreamSeries{} }
 func (*QueryStreamSeries) ProtoMessage() {}
 func (*QueryStreamSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{11}
+	return fileDescriptor_60f6df4f3586b478, []int{14}
 }
 func (m *QueryStreamSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -759,7 +917,7 @@ type QueryStreamSeriesChunks struct {
 func (m *QueryStreamSeriesChunks) Reset()      { *m = QueryStreamSeriesChunks{} }
 func (*QueryStreamSeriesChunks) ProtoMessage() {}
 func (*QueryStreamSeriesChunks) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{12}
+	return fileDescriptor_60f6df4f3586b478, []int{15}
 }
 func (m *QueryStreamSeriesChunks) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -809,7 +967,7 @@ type ExemplarQueryResponse struct {
 func (m *ExemplarQueryResponse) Reset()      { *m = ExemplarQueryResponse{} }
 func (*ExemplarQueryResponse) ProtoMessage() {}
 func (*ExemplarQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{13}
+	return fileDescriptor_60f6df4f3586b478, []int{16}
 }
 func (m *ExemplarQueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -855,7 +1013,7 @@ type LabelValuesRequest struct {
 func (m *LabelValuesRequest) Reset()      { *m = LabelValuesRequest{} }
 func (*LabelValuesRequest) ProtoMessage() {}
 func (*LabelValuesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{14}
+	return fileDescriptor_60f6df4f3586b478, []int{17}
 }
 func (m *LabelValuesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -919,7 +1077,7 @@ type LabelValuesResponse struct {
 func (m *LabelValuesResponse) Reset()      { *m = LabelValuesResponse{} }
 func (*LabelValuesResponse) ProtoMessage() {}
 func (*LabelValuesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{15}
+	return fileDescriptor_60f6df4f3586b478, []int{18}
 }
 func (m *LabelValuesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -964,7 +1122,7 @@ type LabelNamesRequest struct {
 func (m *LabelNamesRequest) Reset()      { *m = LabelNamesRequest{} }
 func (*LabelNamesRequest) ProtoMessage() {}
 func (*LabelNamesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{16}
+	return fileDescriptor_60f6df4f3586b478, []int{19}
 }
 func (m *LabelNamesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1021,7 +1179,7 @@ type LabelNamesResponse struct {
 func (m *LabelNamesResponse) Reset()      { *m = LabelNamesResponse{} }
 func (*LabelNamesResponse) ProtoMessage() {}
 func (*LabelNamesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{17}
+	return fileDescriptor_60f6df4f3586b478, []int{20}
 }
 func (m *LabelNamesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1064,7 +1222,7 @@ type UserStatsRequest struct {
 func (m *UserStatsRequest) Reset()      { *m = UserStatsRequest{} }
 func (*UserStatsRequest) ProtoMessage() {}
 func (*UserStatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{18}
+	return fileDescriptor_60f6df4f3586b478, []int{21}
 }
 func (m *UserStatsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1105,12 +1263,14 @@ type UserStatsResponse struct {
 	NumSeries         uint64  `protobuf:"varint,2,opt,name=num_series,json=numSeries,proto3" json:"num_series,omitempty"`
 	ApiIngestionRate  float64 `protobuf:"fixed64,3,opt,name=api_ingestion_rate,json=apiIngestionRate,proto3" json:"api_ingestion_rate,omitempty"`
 	RuleIngestionRate float64 `protobuf:"fixed64,4,opt,name=rule_ingestion_rate,json=ruleIngestionRate,proto3" json:"rule_ingestion_rate,omitempty"`
+	// Rate of new in-memory series created per second.
+	SeriesCreationRate float64 `protobuf:"fixed64,5,opt,name=series_creation_rate,json=seriesCreationRate,proto3" json:"series_creation_rate,omitempty"`
 }
 
 func (m *UserStatsResponse) Reset()      { *m = UserStatsResponse{} }
 func (*UserStatsResponse) ProtoMessage() {}
 func (*UserStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{19}
+	return fileDescriptor_60f6df4f3586b478, []int{22}
 }
 func (m *UserStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1167,6 +1327,13 @@ func (m *UserStatsResponse) GetRuleIngestionRate() float64 {
 	return 0
 }
 
+func (m *UserStatsResponse) GetSeriesCreationRate() float64 {
+	if m != nil {
+		return m.SeriesCreationRate
+	}
+	return 0
+}
+
 type UserIDStatsResponse struct {
 	UserId string             `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Data   *UserStatsResponse `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
@@ -1175,7 +1342,7 @@ type UserIDStatsResponse struct {
 func (m *UserIDStatsResponse) Reset()      { *m = UserIDStatsResponse{} }
 func (*UserIDStatsResponse) ProtoMessage() {}
 func (*UserIDStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{20}
+	return fileDescriptor_60f6df4f3586b478, []int{23}
 }
 func (m *UserIDStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1225,7 +1392,7 @@ type UsersStatsResponse struct {
 func (m *UsersStatsResponse) Reset()      { *m = UsersStatsResponse{} }
 func (*UsersStatsResponse) ProtoMessage() {}
 func (*UsersStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{21}
+	return fileDescriptor_60f6df4f3586b478, []int{24}
 }
 func (m *UsersStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1270,7 +1437,7 @@ type MetricsForLabelMatchersRequest struct {
 func (m *MetricsForLabelMatchersRequest) Reset()      { *m = MetricsForLabelMatchersRequest{} }
 func (*MetricsForLabelMatchersRequest) ProtoMessage() {}
 func (*MetricsForLabelMatchersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{22}
+	return fileDescriptor_60f6df4f3586b478, []int{25}
 }
 func (m *MetricsForLabelMatchersRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1327,7 +1494,7 @@ type MetricsForLabelMatchersResponse struct {
 func (m *MetricsForLabelMatchersResponse) Reset()      { *m = MetricsForLabelMatchersResponse{} }
 func (*MetricsForLabelMatchersResponse) ProtoMessage() {}
 func (*MetricsForLabelMatchersResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{23}
+	return fileDescriptor_60f6df4f3586b478, []int{26}
 }
 func (m *MetricsForLabelMatchersResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1372,7 +1539,7 @@ type MetricsMetadataRequest struct {
 func (m *MetricsMetadataRequest) Reset()      { *m = MetricsMetadataRequest{} }
 func (*MetricsMetadataRequest) ProtoMessage() {}
 func (*MetricsMetadataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{24}
+	return fileDescriptor_60f6df4f3586b478, []int{27}
 }
 func (m *MetricsMetadataRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1429,7 +1596,7 @@ type MetricsMetadataResponse struct {
 func (m *MetricsMetadataResponse) Reset()      { *m = MetricsMetadataResponse{} }
 func (*MetricsMetadataResponse) ProtoMessage() {}
 func (*MetricsMetadataResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{25}
+	return fileDescriptor_60f6df4f3586b478, []int{28}
 }
 func (m *MetricsMetadataResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1475,7 +1642,7 @@ type ActiveSeriesResponse struct {
 func (m *ActiveSeriesResponse) Reset()      { *m = ActiveSeriesResponse{} }
 func (*ActiveSeriesResponse) ProtoMessage() {}
 func (*ActiveSeriesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{26}
+	return fileDescriptor_60f6df4f3586b478, []int{29}
 }
 func (m *ActiveSeriesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1528,7 +1695,7 @@ type TimeSeriesChunk struct {
 func (m *TimeSeriesChunk) Reset()      { *m = TimeSeriesChunk{} }
 func (*TimeSeriesChunk) ProtoMessage() {}
 func (*TimeSeriesChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{27}
+	return fileDescriptor_60f6df4f3586b478, []int{30}
 }
 func (m *TimeSeriesChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1588,7 +1755,7 @@ type Chunk struct {
 func (m *Chunk) Reset()      { *m = Chunk{} }
 func (*Chunk) ProtoMessage() {}
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{28}
+	return fileDescriptor_60f6df4f3586b478, []int{31}
 }
 func (m *Chunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1645,7 +1812,7 @@ type LabelMatchers struct {
 func (m *LabelMatchers) Reset()      { *m = LabelMatchers{} }
 func (*LabelMatchers) ProtoMessage() {}
 func (*LabelMatchers) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{29}
+	return fileDescriptor_60f6df4f3586b478, []int{32}
 }
 func (m *LabelMatchers) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1690,7 +1857,7 @@ type LabelMatcher struct {
 func (m *LabelMatcher) Reset()      { *m = LabelMatcher{} }
 func (*LabelMatcher) ProtoMessage() {}
 func (*LabelMatcher) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{30}
+	return fileDescriptor_60f6df4f3586b478, []int{33}
 }
 func (m *LabelMatcher) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1751,6 +1918,9 @@ func init() {
 	proto.RegisterType((*LabelValuesCardinalityResponse)(nil), "cortex.LabelValuesCardinalityResponse")
 	proto.RegisterType((*LabelValueSeriesCount)(nil), "cortex.LabelValueSeriesCount")
 	proto.RegisterMapType((map[string]uint64)(nil), "cortex.LabelValueSeriesCount.LabelValueSeriesEntry")
+	proto.RegisterType((*CardinalityHistoryRequest)(nil), "cortex.CardinalityHistoryRequest")
+	proto.RegisterType((*CardinalityHistoryResponse)(nil), "cortex.CardinalityHistoryResponse")
+	proto.RegisterType((*CardinalityHistorySample)(nil), "cortex.CardinalityHistorySample")
 	proto.RegisterType((*QueryRequest)(nil), "cortex.QueryRequest")
 	proto.RegisterType((*ExemplarQueryRequest)(nil), "cortex.ExemplarQueryRequest")
 	proto.RegisterType((*ActiveSeriesRequest)(nil), "cortex.ActiveSeriesRequest")
@@ -1781,117 +1951,123 @@ func init() {
 func init() { proto.RegisterFile("ingester.proto", fileDescriptor_60f6df4f3586b478) }
 
 var fileDescriptor_60f6df4f3586b478 = []byte{
-	// 1748 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0x4b, 0x6f, 0x1b, 0xc9,
-	0x11, 0x66, 0xf3, 0xb5, 0x62, 0x91, 0x92, 0xa8, 0xa6, 0x24, 0x72, 0x47, 0xab, 0x91, 0x76, 0x02,
-	0xef, 0x32, 0x9b, 0x5d, 0xc9, 0xaf, 0x2c, 0xbc, 0x9b, 0x0d, 0x02, 0x4a, 0xa6, 0x2d, 0xda, 0xa6,
-	0x24, 0x0f, 0x25, 0xe7, 0x01, 0x18, 0x83, 0x21, 0xd9, 0x92, 0x06, 0xe2, 0x0c, 0x99, 0x99, 0xa6,
-	0x61, 0xf9, 0x94, 0x53, 0xce, 0xf9, 0x01, 0x41, 0x80, 0xdc, 0x82, 0x1c, 0x73, 0xc9, 0x25, 0xc8,
-	0xd9, 0x97, 0x00, 0xbe, 0xc5, 0x08, 0x10, 0x23, 0x96, 0x2f, 0xc9, 0xcd, 0x40, 0xfe, 0x40, 0x30,
-	0xdd, 0x3d, 0x4f, 0x52, 0x12, 0x65, 0xc4, 0x3e, 0x91, 0x5d, 0xaf, 0xfe, 0xaa, 0xba, 0xba, 0xaa,
-	0xa6, 0x61, 0xc6, 0xb0, 0x0e, 0x89, 0x43, 0x89, 0xbd, 0x36, 0xb0, 0xfb, 0xb4, 0x8f, 0xb3, 0x9d,
-	0xbe, 0x4d, 0xc9, 0x53, 0xe9, 0xab, 0x43, 0x83, 0x1e, 0x0d, 0xdb, 0x6b, 0x9d, 0xbe, 0xb9, 0x7e,
-	0xd8, 0x3f, 0xec, 0xaf, 0x33, 0x76, 0x7b, 0x78, 0xc0, 0x56, 0x6c, 0xc1, 0xfe, 0x71, 0x35, 0xe9,
-	0x6a, 0x58, 0xdc, 0xd6, 0x0f, 0x74, 0x4b, 0x5f, 0x37, 0x0d, 0xd3, 0xb0, 0xd7, 0x07, 0xc7, 0x87,
-	0xfc, 0xdf, 0xa0, 0xcd, 0x7f, 0xb9, 0x86, 0xf2, 0x6b, 0x04, 0xd2, 0x03, 0xbd, 0x4d, 0x7a, 0xdb,
-	0xba, 0x49, 0x9c, 0x9a, 0xd5, 0x7d, 0xa4, 0xf7, 0x86, 0xc4, 0x51, 0xc9, 0x2f, 0x87, 0xc4, 0xa1,
-	0xf8, 0x2a, 0x4c, 0x99, 0x3a, 0xed, 0x1c, 0x11, 0xdb, 0xa9, 0xa0, 0xd5, 0x54, 0x35, 0x7f, 0x7d,
-	0x7e, 0x8d, 0x43, 0x5b, 0x63, 0x5a, 0x4d, 0xce, 0x54, 0x7d, 0x29, 0xfc, 0x35, 0x14, 0x3a, 0xfd,
-	0xa1, 0x45, 0x35, 0x93, 0xd0, 0xa3, 0x7e, 0xb7, 0x92, 0x5c, 0x45, 0xd5, 0x99, 0xeb, 0x25, 0x4f,
-	0x6b, 0xd3, 0xe5, 0x35, 0x19, 0x4b, 0xcd, 0x77, 0x82, 0x85, 0xb2, 0x05, 0x4b, 0x63, 0x71, 0x38,
-	0x83, 0xbe, 0xe5, 0x10, 0xfc, 0x7d, 0xc8, 0x18, 0x94, 0x98, 0x1e, 0x8a, 0x52, 0x04, 0x85, 0x90,
-	0xe5, 0x12, 0xca, 0x6d, 0xc8, 0x87, 0xa8, 0x78, 0x19, 0xa0, 0xe7, 0x2e, 0x35, 0x4b, 0x37, 0x49,
-	0x05, 0xad, 0xa2, 0x6a, 0x4e, 0xcd, 0xf5, 0xbc, 0xad, 0xf0, 0x22, 0x64, 0x9f, 0x30, 0xc1, 0x4a,
-	0x72, 0x35, 0x55, 0xcd, 0xa9, 0x62, 0xa5, 0xfc, 0x11, 0xc1, 0x72, 0xc8, 0xcc, 0xa6, 0x6e, 0x77,
-	0x0d, 0x4b, 0xef, 0x19, 0xf4, 0xc4, 0x8b, 0xcd, 0x0a, 0xe4, 0x03, 0xc3, 0x1c, 0x58, 0x4e, 0x05,
-	0xdf, 0xb2, 0x13, 0x09, 0x5e, 0xf2, 0x9d, 0x82, 0x97, 0x9a, 0x30, 0x78, 0xfb, 0x20, 0x9f, 0x85,
-	0x55, 0xc4, 0xef, 0x46, 0x34, 0x7e, 0xcb, 0xa3, 0xf1, 0x6b, 0x11, 0xdb, 0x20, 0x0e, 0xdb, 0xc2,
-	0x8b, 0xe4, 0x2b, 0x04, 0x0b, 0x63, 0x05, 0x2e, 0x0a, 0xaa, 0x0e, 0x98, 0xb3, 0x59, 0x30, 0x35,
-	0x87, 0x69, 0x8a, 0x18, 0xdc, 0x38, 0x77, 0xeb, 0x11, 0x6a, 0xdd, 0xa2, 0xf6, 0x89, 0x5a, 0xec,
-	0xc5, 0xc8, 0xd2, 0xe6, 0x28, 0x34, 0x26, 0x8a, 0x8b, 0x90, 0x3a, 0x26, 0x27, 0x02, 0x93, 0xfb,
-	0x17, 0xcf, 0x43, 0x86, 0xe1, 0x60, 0xb9, 0x98, 0x56, 0xf9, 0xe2, 0xdb, 0xe4, 0x2d, 0xa4, 0xfc,
-	0x1d, 0x41, 0xe1, 0xe1, 0x90, 0xd8, 0xfe, 0x99, 0x7e, 0x09, 0xd8, 0xa1, 0xba, 0x4d, 0x35, 0x6a,
-	0x98, 0xc4, 0xa1, 0xba, 0x39, 0xd0, 0x58, 0xcc, 0x50, 0x35, 0xa5, 0x16, 0x19, 0x67, 0xcf, 0x63,
-	0x34, 0x1d, 0x5c, 0x85, 0x22, 0xb1, 0xba, 0x51, 0xd9, 0x24, 0x93, 0x9d, 0x21, 0x56, 0x37, 0x2c,
-	0x19, 0x4e, 0x85, 0xd4, 0x44, 0xa9, 0xf0, 0x63, 0x58, 0x72, 0xa8, 0x4d, 0x74, 0xd3, 0xb0, 0x0e,
-	0xb5, 0xce, 0xd1, 0xd0, 0x3a, 0x76, 0xb4, 0xb6, 0xcb, 0xd4, 0x1c, 0xe3, 0x19, 0xa9, 0x74, 0x99,
-	0x2b, 0x15, 0x5f, 0x64, 0x93, 0x49, 0x6c, 0xb8, 0x02, 0x2d, 0xe3, 0x19, 0x51, 0x7e, 0x8f, 0x60,
-	0xbe, 0xfe, 0x94, 0x98, 0x83, 0x9e, 0x6e, 0x7f, 0x10, 0x0f, 0xaf, 0x8d, 0x78, 0xb8, 0x30, 0xce,
-	0x43, 0x27, 0x70, 0x51, 0xf9, 0x0b, 0x82, 0x52, 0xad, 0x43, 0x8d, 0x27, 0xe2, 0xfc, 0xde, 0xbd,
-	0xe8, 0xfc, 0x08, 0xd2, 0xf4, 0x64, 0x40, 0x44, 0xb1, 0xf9, 0xdc, 0x93, 0x1e, 0x63, 0x7c, 0x4d,
-	0xfc, 0xee, 0x9d, 0x0c, 0x88, 0xca, 0x94, 0x94, 0xaf, 0x21, 0x1f, 0x22, 0x62, 0x80, 0x6c, 0xab,
-	0xae, 0x36, 0xea, 0xad, 0x62, 0x02, 0x2f, 0x41, 0x79, 0xbb, 0xb6, 0xd7, 0x78, 0x54, 0xd7, 0xb6,
-	0x1a, 0xad, 0xbd, 0x9d, 0xbb, 0x6a, 0xad, 0xa9, 0x09, 0x26, 0x52, 0xee, 0xc3, 0xb4, 0x88, 0xac,
-	0xb8, 0x63, 0xdf, 0x02, 0xb0, 0x40, 0xf1, 0x6c, 0x8f, 0x22, 0x1f, 0xb4, 0xd7, 0xdc, 0x68, 0x71,
-	0x2c, 0x1b, 0xe9, 0xe7, 0xaf, 0x56, 0x12, 0x6a, 0x48, 0x5a, 0xf9, 0x6f, 0x12, 0x4a, 0xcc, 0x5a,
-	0x8b, 0x9d, 0xa8, 0x6f, 0xf3, 0x27, 0x90, 0xe7, 0x87, 0x1f, 0x36, 0x5a, 0xf6, 0x1c, 0x0c, 0x4c,
-	0xb2, 0xf3, 0x17, 0x76, 0xc3, 0x1a, 0x31, 0x50, 0xc9, 0xcb, 0x80, 0xc2, 0xf7, 0xa0, 0x18, 0xe4,
-	0xa0, 0xb0, 0xc0, 0xcf, 0xf6, 0x63, 0x0f, 0x41, 0x08, 0x73, 0xc4, 0xcc, 0xac, 0xaf, 0xc8, 0xc9,
-	0xf8, 0x26, 0x94, 0x0d, 0x47, 0x73, 0x93, 0xa9, 0x7f, 0x20, 0x6c, 0x69, 0x5c, 0xa6, 0x92, 0x5e,
-	0x45, 0xd5, 0x29, 0xb5, 0x64, 0x38, 0x75, 0xab, 0xbb, 0x73, 0xc0, 0xe5, 0xb9, 0x49, 0xfc, 0x18,
-	0xca, 0x71, 0x04, 0xe2, 0x32, 0x54, 0x32, 0x0c, 0xc8, 0xca, 0x99, 0x40, 0xc4, 0x8d, 0xe0, 0x70,
-	0x16, 0x62, 0x70, 0x38, 0x53, 0xf9, 0x2d, 0x82, 0xb9, 0x11, 0x45, 0x7c, 0x00, 0x59, 0x56, 0x6e,
-	0xe2, 0xcd, 0x66, 0xd0, 0xe6, 0xf9, 0xb7, 0xab, 0x1b, 0xf6, 0xc6, 0x37, 0xae, 0xdd, 0x7f, 0xbc,
-	0x5a, 0xb9, 0x36, 0x49, 0xcb, 0xe5, 0x7a, 0xb5, 0xae, 0x3e, 0xa0, 0xc4, 0x56, 0x85, 0x75, 0xb7,
-	0x81, 0x30, 0x5f, 0x34, 0x56, 0xca, 0xc5, 0xbd, 0x02, 0x46, 0x62, 0xb5, 0x50, 0x31, 0xa0, 0x7c,
-	0x86, 0x5b, 0xf8, 0x53, 0x28, 0x88, 0x70, 0x18, 0x56, 0x97, 0x3c, 0x65, 0x17, 0x38, 0xad, 0xe6,
-	0x39, 0xad, 0xe1, 0x92, 0xf0, 0x0f, 0x20, 0x2b, 0x42, 0xc5, 0x4f, 0x7d, 0xda, 0x6f, 0x23, 0xa1,
-	0x5c, 0x11, 0x22, 0x4a, 0x0b, 0x16, 0x62, 0xe5, 0xe2, 0xff, 0x90, 0xd4, 0x7f, 0x45, 0x80, 0xc3,
-	0x0d, 0x5a, 0xdc, 0xef, 0x0b, 0x9a, 0xc7, 0xf8, 0x0a, 0x95, 0xbc, 0x44, 0x85, 0x4a, 0x5d, 0x58,
-	0xa1, 0xdc, 0x94, 0x9b, 0xa0, 0x42, 0xdd, 0x82, 0x52, 0x04, 0xbf, 0x88, 0xc9, 0xa7, 0x50, 0x08,
-	0xb5, 0x37, 0xaf, 0xf5, 0xe7, 0x83, 0x1e, 0xe5, 0x28, 0xbf, 0x43, 0x30, 0x17, 0xcc, 0x33, 0x1f,
-	0xb6, 0xf8, 0x4e, 0xe4, 0xda, 0x0f, 0xc5, 0xd1, 0x08, 0x7c, 0xc2, 0xb3, 0x8b, 0x66, 0x1a, 0xe5,
-	0x1e, 0x14, 0xf7, 0x1d, 0x62, 0xb7, 0xa8, 0x4e, 0x7d, 0xaf, 0xe2, 0x53, 0x0b, 0x9a, 0x70, 0x6a,
-	0xf9, 0x33, 0x82, 0xb9, 0x90, 0x31, 0x01, 0xe1, 0x8a, 0x37, 0x0c, 0x1b, 0x7d, 0x4b, 0xb3, 0x75,
-	0xca, 0x33, 0x04, 0xa9, 0xd3, 0x3e, 0x55, 0xd5, 0x29, 0x71, 0x93, 0xc8, 0x1a, 0x9a, 0xc1, 0x68,
-	0xe1, 0xa6, 0x7f, 0xce, 0x1a, 0x7a, 0x77, 0xf8, 0x4b, 0xc0, 0xfa, 0xc0, 0xd0, 0x62, 0x96, 0x52,
-	0xcc, 0x52, 0x51, 0x1f, 0x18, 0x8d, 0x88, 0xb1, 0x35, 0x28, 0xd9, 0xc3, 0x1e, 0x89, 0x8b, 0xa7,
-	0x99, 0xf8, 0x9c, 0xcb, 0x8a, 0xc8, 0x2b, 0x8f, 0xa1, 0xe4, 0x02, 0x6f, 0xdc, 0x8e, 0x42, 0x2f,
-	0xc3, 0x47, 0x43, 0x87, 0xd8, 0x9a, 0xd1, 0x15, 0x59, 0x9d, 0x75, 0x97, 0x8d, 0x2e, 0xfe, 0x0a,
-	0xd2, 0x5d, 0x9d, 0xea, 0x0c, 0x66, 0xa8, 0x78, 0x8e, 0x38, 0xaf, 0x32, 0x31, 0xe5, 0x2e, 0x60,
-	0x97, 0xe5, 0x44, 0xad, 0x5f, 0x83, 0x8c, 0xe3, 0x12, 0xc4, 0x25, 0x5c, 0x0a, 0x5b, 0x89, 0x21,
-	0x51, 0xb9, 0xa4, 0xf2, 0x27, 0x04, 0x72, 0x93, 0x50, 0xdb, 0xe8, 0x38, 0x77, 0xfa, 0x76, 0x34,
-	0x15, 0xde, 0x73, 0x4a, 0xde, 0x82, 0x82, 0x97, 0x6b, 0x9a, 0x43, 0xe8, 0xf9, 0x33, 0x41, 0xde,
-	0x13, 0x6d, 0x11, 0xaa, 0xdc, 0x87, 0x95, 0x33, 0x31, 0x8b, 0x50, 0x54, 0x21, 0x6b, 0x32, 0x11,
-	0x11, 0x8b, 0x62, 0x50, 0x90, 0xb8, 0xaa, 0x2a, 0xf8, 0xca, 0x00, 0x16, 0x85, 0xb1, 0x26, 0xa1,
-	0xba, 0x1b, 0x5d, 0xcf, 0xf1, 0x79, 0xc8, 0xf4, 0x0c, 0xd3, 0xa0, 0xcc, 0xd7, 0x39, 0x95, 0x2f,
-	0x5c, 0x07, 0xd9, 0x1f, 0x6d, 0x40, 0x6c, 0x4d, 0xec, 0x91, 0x64, 0x02, 0x33, 0x8c, 0xbe, 0x4b,
-	0x6c, 0x6e, 0xcf, 0xfd, 0x70, 0x10, 0xfc, 0x14, 0x3f, 0x6b, 0xb1, 0xe3, 0x0e, 0x94, 0x47, 0x76,
-	0x14, 0xb0, 0x6f, 0xc2, 0x94, 0x29, 0x68, 0x02, 0x78, 0x25, 0x0e, 0xdc, 0xd7, 0xf1, 0x25, 0x95,
-	0x0e, 0xcc, 0x47, 0x07, 0x99, 0xcb, 0x06, 0xc1, 0xad, 0x57, 0xed, 0x61, 0xe7, 0x98, 0x50, 0xbf,
-	0xd3, 0xa4, 0xdc, 0x66, 0xc1, 0x69, 0xbc, 0xd5, 0xfc, 0x07, 0xc1, 0x6c, 0x6c, 0x9a, 0x70, 0x63,
-	0x71, 0x60, 0xf7, 0x4d, 0xcd, 0xfb, 0x36, 0x0d, 0xf2, 0x7a, 0xc6, 0xa5, 0x37, 0x04, 0xb9, 0xd1,
-	0x0d, 0x27, 0x7e, 0x32, 0x92, 0xf8, 0x41, 0x2b, 0x4d, 0xbd, 0xd7, 0x56, 0x1a, 0xf4, 0xba, 0xf4,
-	0xc5, 0xbd, 0xee, 0x6f, 0x08, 0x32, 0xdc, 0xc3, 0xf7, 0x95, 0xfc, 0x12, 0x4c, 0x11, 0xab, 0xd3,
-	0xef, 0x1a, 0xd6, 0x21, 0xcb, 0x8e, 0x8c, 0xea, 0xaf, 0xf1, 0xae, 0xa8, 0x05, 0x6e, 0x71, 0x29,
-	0x6c, 0x7c, 0x27, 0x7c, 0xbf, 0x39, 0x91, 0xef, 0xfb, 0x96, 0xa3, 0x1f, 0x90, 0x8d, 0x13, 0x4a,
-	0x5a, 0x3d, 0xa3, 0xe3, 0x95, 0x8b, 0x1a, 0x4c, 0x47, 0xae, 0xc9, 0xe5, 0x07, 0x68, 0x45, 0x83,
-	0x42, 0x98, 0x83, 0xaf, 0x88, 0x81, 0x9a, 0x97, 0xf2, 0x39, 0x4f, 0x9b, 0xb1, 0x83, 0xd1, 0x19,
-	0x63, 0x48, 0xb3, 0x1e, 0xce, 0x0f, 0x9d, 0xfd, 0x0f, 0xbe, 0xb6, 0xf8, 0xb5, 0xe0, 0x8b, 0x2f,
-	0xaa, 0x90, 0x0f, 0xf5, 0x01, 0x3c, 0x0d, 0xb9, 0xc6, 0xb6, 0xd6, 0xac, 0x37, 0x77, 0xd4, 0x9f,
-	0x17, 0x13, 0xee, 0xcc, 0x5d, 0xdb, 0x74, 0xe7, 0xec, 0x22, 0xfa, 0xe2, 0x1e, 0xe4, 0xfc, 0x6d,
-	0x70, 0x0e, 0x32, 0xf5, 0x87, 0xfb, 0xb5, 0x07, 0xc5, 0x84, 0xab, 0xb2, 0xbd, 0xb3, 0xa7, 0xf1,
-	0x25, 0xc2, 0xb3, 0x90, 0x57, 0xeb, 0x77, 0xeb, 0x3f, 0xd3, 0x9a, 0xb5, 0xbd, 0xcd, 0xad, 0x62,
-	0x12, 0x63, 0x98, 0xe1, 0x84, 0xed, 0x1d, 0x41, 0x4b, 0x5d, 0xff, 0xe7, 0x47, 0x30, 0xe5, 0xa5,
-	0x29, 0xfe, 0x06, 0xd2, 0xbb, 0x43, 0xe7, 0x08, 0x2f, 0x06, 0x39, 0xf8, 0x53, 0xdb, 0xa0, 0x44,
-	0x14, 0x04, 0xa9, 0x3c, 0x42, 0xe7, 0x17, 0x4d, 0x49, 0xe0, 0xdb, 0x90, 0x0f, 0x0d, 0x62, 0x78,
-	0x3e, 0x32, 0x74, 0x7a, 0xfa, 0x4b, 0x63, 0x46, 0xd1, 0xc0, 0xc6, 0x55, 0x84, 0x77, 0x60, 0x86,
-	0xb1, 0xbc, 0x41, 0xcb, 0xc1, 0x9f, 0x78, 0x2a, 0xe3, 0x3e, 0xd5, 0xa4, 0xe5, 0x33, 0xb8, 0x3e,
-	0xac, 0xad, 0xe8, 0x4b, 0x87, 0x34, 0xee, 0x51, 0x24, 0x0e, 0x6e, 0xcc, 0x3c, 0xa3, 0x24, 0x70,
-	0x1d, 0x20, 0x98, 0x06, 0xf0, 0xc7, 0x11, 0xe1, 0xf0, 0x04, 0x23, 0x49, 0xe3, 0x58, 0xbe, 0x99,
-	0x0d, 0xc8, 0xf9, 0x3d, 0x0d, 0x57, 0xc6, 0xb4, 0x39, 0x6e, 0xe4, 0xec, 0x06, 0xa8, 0x24, 0xf0,
-	0x1d, 0x28, 0xd4, 0x7a, 0xbd, 0x49, 0xcc, 0x48, 0x61, 0x8e, 0x13, 0xb7, 0xd3, 0xf3, 0xeb, 0x70,
-	0xbc, 0x8d, 0xe0, 0xcf, 0xfc, 0x7c, 0x3e, 0xb7, 0x37, 0x4a, 0x9f, 0x5f, 0x28, 0xe7, 0xef, 0xb6,
-	0x07, 0xb3, 0xb1, 0xaa, 0x8f, 0xe5, 0x98, 0x76, 0xac, 0x01, 0x49, 0x2b, 0x67, 0xf2, 0x7d, 0xab,
-	0x6d, 0x31, 0x7f, 0x46, 0x1f, 0xc5, 0xb0, 0x32, 0x7a, 0x08, 0xf1, 0x97, 0x3b, 0xe9, 0x7b, 0xe7,
-	0xca, 0x84, 0xb2, 0xf2, 0x18, 0x16, 0xc7, 0xbf, 0x1d, 0xe1, 0x2b, 0x63, 0x72, 0x66, 0xf4, 0x1d,
-	0x4c, 0xfa, 0xec, 0x22, 0xb1, 0xd0, 0x66, 0x4d, 0x28, 0x84, 0x7b, 0x19, 0x5e, 0x3a, 0xe7, 0x53,
-	0x5d, 0xfa, 0x64, 0x3c, 0x33, 0x30, 0xb7, 0xf1, 0xdd, 0x8b, 0xd7, 0x72, 0xe2, 0xe5, 0x6b, 0x39,
-	0xf1, 0xf6, 0xb5, 0x8c, 0x7e, 0x75, 0x2a, 0xa3, 0x3f, 0x9c, 0xca, 0xe8, 0xf9, 0xa9, 0x8c, 0x5e,
-	0x9c, 0xca, 0xe8, 0x5f, 0xa7, 0x32, 0xfa, 0xf7, 0xa9, 0x9c, 0x78, 0x7b, 0x2a, 0xa3, 0xdf, 0xbc,
-	0x91, 0x13, 0x2f, 0xde, 0xc8, 0x89, 0x97, 0x6f, 0xe4, 0xc4, 0x2f, 0xb2, 0x9d, 0x9e, 0x41, 0x2c,
-	0xda, 0xce, 0xb2, 0x27, 0xd0, 0x1b, 0xff, 0x0b, 0x00, 0x00, 0xff, 0xff, 0x69, 0x8a, 0xb2, 0x58,
-	0x7d, 0x15, 0x00, 0x00,
+	// 1841 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xcd, 0x6f, 0x1b, 0xc7,
+	0x15, 0xe7, 0xf0, 0x2b, 0xe2, 0x23, 0x25, 0x53, 0x43, 0xc9, 0xa4, 0x57, 0x11, 0x25, 0x6f, 0xe1,
+	0x84, 0x4d, 0x13, 0xf9, 0xb3, 0x81, 0x93, 0xa6, 0x28, 0x28, 0x99, 0xb1, 0xe8, 0x84, 0x92, 0xb3,
+	0x94, 0xd3, 0xb4, 0x80, 0xb1, 0x58, 0x92, 0x23, 0x69, 0x21, 0xee, 0x92, 0xdd, 0x19, 0x06, 0x56,
+	0x4e, 0x3d, 0xf5, 0xdc, 0xde, 0x8b, 0x02, 0xbd, 0x15, 0x3d, 0xf6, 0x1c, 0xf4, 0x9c, 0x4b, 0x01,
+	0xdf, 0x1a, 0xf4, 0x60, 0xd4, 0xf2, 0xa5, 0xb9, 0x05, 0xe8, 0x3f, 0x50, 0xec, 0xcc, 0xec, 0x27,
+	0x97, 0xfa, 0x08, 0x2a, 0x9f, 0xb8, 0xf3, 0xde, 0xef, 0xbd, 0x79, 0xef, 0xcd, 0x9b, 0xf7, 0x1e,
+	0x07, 0x16, 0x4c, 0xfb, 0x80, 0x50, 0x46, 0x9c, 0x8d, 0xb1, 0x33, 0x62, 0x23, 0x9c, 0xef, 0x8f,
+	0x1c, 0x46, 0x9e, 0x29, 0xef, 0x1d, 0x98, 0xec, 0x70, 0xd2, 0xdb, 0xe8, 0x8f, 0xac, 0x9b, 0x07,
+	0xa3, 0x83, 0xd1, 0x4d, 0xce, 0xee, 0x4d, 0xf6, 0xf9, 0x8a, 0x2f, 0xf8, 0x97, 0x10, 0x53, 0x6e,
+	0x85, 0xe1, 0x8e, 0xb1, 0x6f, 0xd8, 0xc6, 0x4d, 0xcb, 0xb4, 0x4c, 0xe7, 0xe6, 0xf8, 0xe8, 0x40,
+	0x7c, 0x8d, 0x7b, 0xe2, 0x57, 0x48, 0xa8, 0xbf, 0x43, 0xa0, 0x7c, 0x6a, 0xf4, 0xc8, 0x70, 0xc7,
+	0xb0, 0x08, 0x6d, 0xda, 0x83, 0xcf, 0x8d, 0xe1, 0x84, 0x50, 0x8d, 0xfc, 0x66, 0x42, 0x28, 0xc3,
+	0xb7, 0x60, 0xce, 0x32, 0x58, 0xff, 0x90, 0x38, 0xb4, 0x86, 0xd6, 0x33, 0x8d, 0xe2, 0x9d, 0xa5,
+	0x0d, 0x61, 0xda, 0x06, 0x97, 0xea, 0x08, 0xa6, 0xe6, 0xa3, 0xf0, 0xfb, 0x50, 0xea, 0x8f, 0x26,
+	0x36, 0xd3, 0x2d, 0xc2, 0x0e, 0x47, 0x83, 0x5a, 0x7a, 0x1d, 0x35, 0x16, 0xee, 0x54, 0x3c, 0xa9,
+	0x2d, 0x97, 0xd7, 0xe1, 0x2c, 0xad, 0xd8, 0x0f, 0x16, 0xea, 0x36, 0xac, 0x24, 0xda, 0x41, 0xc7,
+	0x23, 0x9b, 0x12, 0xfc, 0x63, 0xc8, 0x99, 0x8c, 0x58, 0x9e, 0x15, 0x95, 0x88, 0x15, 0x12, 0x2b,
+	0x10, 0xea, 0x03, 0x28, 0x86, 0xa8, 0x78, 0x15, 0x60, 0xe8, 0x2e, 0x75, 0xdb, 0xb0, 0x48, 0x0d,
+	0xad, 0xa3, 0x46, 0x41, 0x2b, 0x0c, 0xbd, 0xad, 0xf0, 0x55, 0xc8, 0x7f, 0xc9, 0x81, 0xb5, 0xf4,
+	0x7a, 0xa6, 0x51, 0xd0, 0xe4, 0x4a, 0xfd, 0x2b, 0x82, 0xd5, 0x90, 0x9a, 0x2d, 0xc3, 0x19, 0x98,
+	0xb6, 0x31, 0x34, 0xd9, 0xb1, 0x17, 0x9b, 0x35, 0x28, 0x06, 0x8a, 0x85, 0x61, 0x05, 0x0d, 0x7c,
+	0xcd, 0x34, 0x12, 0xbc, 0xf4, 0x0f, 0x0a, 0x5e, 0xe6, 0x9c, 0xc1, 0x7b, 0x02, 0xf5, 0x59, 0xb6,
+	0xca, 0xf8, 0xdd, 0x8d, 0xc6, 0x6f, 0x75, 0x3a, 0x7e, 0x5d, 0xe2, 0x98, 0x84, 0xf2, 0x2d, 0xbc,
+	0x48, 0xbe, 0x40, 0xb0, 0x9c, 0x08, 0x38, 0x2b, 0xa8, 0x06, 0x60, 0xc1, 0xe6, 0xc1, 0xd4, 0x29,
+	0x97, 0x94, 0x31, 0xb8, 0x7b, 0xea, 0xd6, 0x53, 0xd4, 0x96, 0xcd, 0x9c, 0x63, 0xad, 0x3c, 0x8c,
+	0x91, 0x95, 0x2d, 0x58, 0x4e, 0x84, 0xe2, 0x32, 0x64, 0x8e, 0xc8, 0xb1, 0xb4, 0xc9, 0xfd, 0xc4,
+	0x4b, 0x90, 0xe3, 0x76, 0xf0, 0x5c, 0xcc, 0x6a, 0x62, 0xf1, 0x61, 0xfa, 0x3e, 0x52, 0x29, 0x5c,
+	0x0b, 0x05, 0x6b, 0xdb, 0xa4, 0x6c, 0xe4, 0xf8, 0xe7, 0xfb, 0x2e, 0x60, 0xca, 0x0c, 0x87, 0xe9,
+	0xcc, 0xb4, 0x08, 0x65, 0x86, 0x35, 0xd6, 0x79, 0xfc, 0x50, 0x23, 0xa3, 0x95, 0x39, 0x67, 0xcf,
+	0x63, 0x74, 0x28, 0x6e, 0x40, 0x99, 0xd8, 0x83, 0x28, 0x36, 0xcd, 0xb1, 0x0b, 0xc4, 0x1e, 0x84,
+	0x90, 0xea, 0x17, 0xa0, 0x24, 0x6d, 0x2a, 0x0f, 0xea, 0x43, 0x78, 0x83, 0x1a, 0xd6, 0x78, 0x48,
+	0xbc, 0xa3, 0x5a, 0xf7, 0x4f, 0x7f, 0x4a, 0xa8, 0xcb, 0x81, 0x9a, 0x27, 0xa0, 0xfe, 0x01, 0x41,
+	0x6d, 0x16, 0x0a, 0x5f, 0x87, 0x52, 0x82, 0x23, 0x45, 0x16, 0xf2, 0x61, 0x15, 0xc0, 0x9e, 0x58,
+	0xc1, 0x71, 0xb9, 0xd1, 0x2a, 0xd8, 0x13, 0x4b, 0x84, 0x37, 0xc8, 0xa1, 0xcc, 0x05, 0x72, 0xe8,
+	0x9f, 0x08, 0x4a, 0x9f, 0x4d, 0xc8, 0xa5, 0x87, 0x35, 0x72, 0xdb, 0x32, 0xe7, 0xba, 0x6d, 0x3f,
+	0x87, 0x15, 0xca, 0x1c, 0x62, 0x58, 0xa6, 0x7d, 0xa0, 0xf7, 0x0f, 0x27, 0xf6, 0x11, 0xd5, 0x7b,
+	0x2e, 0x53, 0xa7, 0xe6, 0x57, 0xa4, 0x36, 0xe0, 0xfe, 0xd7, 0x7c, 0xc8, 0x16, 0x47, 0x6c, 0xba,
+	0x80, 0xae, 0xf9, 0x15, 0x51, 0xff, 0x8c, 0x60, 0xa9, 0xf5, 0x8c, 0x58, 0xe3, 0xa1, 0xe1, 0xbc,
+	0x16, 0x0f, 0x6f, 0x4f, 0x79, 0xb8, 0x9c, 0xe4, 0x21, 0x0d, 0x5c, 0x54, 0xbf, 0x46, 0x50, 0x69,
+	0xf6, 0x99, 0xf9, 0xa5, 0x3c, 0x9a, 0x1f, 0x5e, 0xd7, 0x7f, 0x06, 0x59, 0x76, 0x3c, 0x26, 0xb2,
+	0x9e, 0xbf, 0xed, 0xa1, 0x13, 0x94, 0x6f, 0xc8, 0xdf, 0xbd, 0xe3, 0x31, 0xd1, 0xb8, 0x90, 0xfa,
+	0x3e, 0x14, 0x43, 0x44, 0x0c, 0x90, 0xef, 0xb6, 0xb4, 0x76, 0xab, 0x5b, 0x4e, 0xe1, 0x15, 0xa8,
+	0xee, 0x34, 0xf7, 0xda, 0x9f, 0xb7, 0xf4, 0xed, 0x76, 0x77, 0x6f, 0xf7, 0xa1, 0xd6, 0xec, 0xe8,
+	0x92, 0x89, 0xd4, 0x4f, 0x60, 0x5e, 0x46, 0xd6, 0xbf, 0x1d, 0xc0, 0x03, 0x25, 0x32, 0x34, 0x6a,
+	0xf9, 0xb8, 0xb7, 0xe1, 0x46, 0x4b, 0xd8, 0xb2, 0x99, 0xfd, 0xe6, 0xc5, 0x5a, 0x4a, 0x0b, 0xa1,
+	0xd5, 0xff, 0xa6, 0xa1, 0xc2, 0xb5, 0x75, 0xf9, 0x89, 0xfa, 0x3a, 0x7f, 0x01, 0x45, 0x71, 0xf8,
+	0x61, 0xa5, 0x55, 0xcf, 0xc1, 0x40, 0x25, 0x3f, 0x7f, 0xa9, 0x37, 0x2c, 0x11, 0x33, 0x2a, 0x7d,
+	0x11, 0xa3, 0xf0, 0x23, 0x28, 0x07, 0x39, 0x28, 0x35, 0x88, 0xb3, 0xbd, 0xe6, 0x59, 0x10, 0xb2,
+	0x39, 0xa2, 0xe6, 0x8a, 0x2f, 0x28, 0xef, 0xe7, 0x3d, 0xa8, 0x9a, 0x54, 0x77, 0x93, 0x69, 0xb4,
+	0x2f, 0x75, 0xe9, 0x02, 0x53, 0xcb, 0xae, 0xa3, 0xc6, 0x9c, 0x56, 0x31, 0x69, 0xcb, 0x1e, 0xec,
+	0xee, 0x0b, 0xbc, 0x50, 0x89, 0x9f, 0x42, 0x35, 0x6e, 0x81, 0xbc, 0x0c, 0xb5, 0x1c, 0x37, 0x64,
+	0x6d, 0xa6, 0x21, 0xf2, 0x46, 0x08, 0x73, 0x96, 0x63, 0xe6, 0x08, 0xa6, 0xfa, 0x47, 0x04, 0x8b,
+	0x53, 0x82, 0x78, 0x1f, 0xf2, 0xbc, 0xa2, 0xc7, 0xfb, 0xf9, 0xb8, 0x27, 0xf2, 0xef, 0xb1, 0x61,
+	0x3a, 0x9b, 0x1f, 0xb8, 0x7a, 0xff, 0xf5, 0x62, 0xed, 0xf6, 0x79, 0xa6, 0x1a, 0x21, 0xd7, 0x1c,
+	0x18, 0x63, 0x46, 0x1c, 0x4d, 0x6a, 0x77, 0x7b, 0x34, 0xf7, 0x45, 0xe7, 0xdd, 0x52, 0xde, 0x2b,
+	0xe0, 0x24, 0x5e, 0xa5, 0x54, 0x13, 0xaa, 0x33, 0xdc, 0x72, 0x0b, 0xa6, 0x0c, 0x87, 0x69, 0x0f,
+	0xc8, 0x33, 0x7e, 0x81, 0xb3, 0x5a, 0x51, 0xd0, 0xda, 0x2e, 0x09, 0xff, 0x04, 0xf2, 0x32, 0x54,
+	0xe2, 0xd4, 0xe7, 0xfd, 0x5a, 0x1d, 0xca, 0x15, 0x09, 0x51, 0xbb, 0xb0, 0x1c, 0x2b, 0x17, 0xff,
+	0x87, 0xa4, 0xfe, 0x3b, 0x02, 0x1c, 0x9e, 0x81, 0xe4, 0xfd, 0x3e, 0xa3, 0x3f, 0x27, 0x57, 0xa8,
+	0xf4, 0x05, 0x2a, 0x54, 0xe6, 0xcc, 0x0a, 0xe5, 0xa6, 0xdc, 0x39, 0x2a, 0xd4, 0x7d, 0xa8, 0x44,
+	0xec, 0x97, 0x31, 0xb9, 0x0e, 0xa5, 0xd0, 0x04, 0xe1, 0x4d, 0x57, 0xc5, 0x60, 0x0c, 0xa0, 0xea,
+	0x9f, 0x10, 0x2c, 0x06, 0x23, 0xe3, 0xeb, 0x2d, 0xbe, 0xe7, 0x72, 0xed, 0xa7, 0x80, 0xc3, 0xf6,
+	0x49, 0xcf, 0xce, 0x1a, 0x1b, 0xd5, 0x47, 0x50, 0x7e, 0x42, 0x89, 0xd3, 0x65, 0x06, 0xf3, 0xbd,
+	0x8a, 0x0f, 0x86, 0xe8, 0x9c, 0x83, 0xe1, 0x77, 0x08, 0x16, 0x43, 0xca, 0xa4, 0x09, 0x37, 0xbc,
+	0xff, 0x1b, 0xe6, 0xc8, 0xd6, 0x1d, 0x83, 0x89, 0x0c, 0x41, 0xda, 0xbc, 0x4f, 0xd5, 0x0c, 0x46,
+	0xce, 0x1a, 0x07, 0xde, 0x05, 0x6c, 0x8c, 0x4d, 0x3d, 0xa6, 0x29, 0xc3, 0x35, 0x95, 0x8d, 0xb1,
+	0xd9, 0x8e, 0x28, 0xdb, 0x80, 0x8a, 0x33, 0x19, 0x92, 0x38, 0x3c, 0xcb, 0xe1, 0x8b, 0x2e, 0x2b,
+	0x8a, 0xbf, 0x05, 0x4b, 0x5e, 0x31, 0x72, 0x88, 0x11, 0x08, 0xe4, 0xb8, 0x00, 0x16, 0xbc, 0x2d,
+	0xc9, 0x72, 0x25, 0xd4, 0xa7, 0x50, 0x71, 0x5d, 0x6d, 0x3f, 0x88, 0x3a, 0x5b, 0x85, 0x37, 0x26,
+	0x94, 0x38, 0xba, 0x39, 0x90, 0xf7, 0x20, 0xef, 0x2e, 0xdb, 0x03, 0xfc, 0x1e, 0x64, 0x07, 0x06,
+	0x33, 0xb8, 0x63, 0xa1, 0x72, 0x3b, 0x15, 0x2e, 0x8d, 0xc3, 0xd4, 0x87, 0x80, 0x5d, 0x16, 0x8d,
+	0x6a, 0xbf, 0x0d, 0x39, 0xea, 0x12, 0xe4, 0xb5, 0x5d, 0x09, 0x6b, 0x89, 0x59, 0xa2, 0x09, 0xa4,
+	0xfa, 0x37, 0x04, 0xf5, 0x0e, 0x61, 0x8e, 0xd9, 0xa7, 0x1f, 0x8f, 0x9c, 0x68, 0xf2, 0x5c, 0x72,
+	0x12, 0xdf, 0x87, 0x92, 0x97, 0x9d, 0x3a, 0x25, 0xec, 0xf4, 0x29, 0xa2, 0xe8, 0x41, 0xbb, 0x84,
+	0xa9, 0x9f, 0xc0, 0xda, 0x4c, 0x9b, 0x65, 0x28, 0x1a, 0x90, 0xb7, 0x38, 0x44, 0xc6, 0xa2, 0x1c,
+	0x94, 0x30, 0x21, 0xaa, 0x49, 0xbe, 0x3a, 0x86, 0xab, 0x52, 0x59, 0x87, 0x30, 0xc3, 0x8d, 0xae,
+	0xe7, 0xf8, 0x12, 0xe4, 0x86, 0xa6, 0x65, 0x32, 0xee, 0xeb, 0xa2, 0x26, 0x16, 0xae, 0x83, 0xfc,
+	0x43, 0x1f, 0x13, 0x47, 0x97, 0x7b, 0xa4, 0x39, 0x60, 0x81, 0xd3, 0x1f, 0x13, 0x47, 0xe8, 0x73,
+	0xff, 0xcd, 0x49, 0x7e, 0x46, 0x9c, 0xb5, 0xdc, 0x71, 0x17, 0xaa, 0x53, 0x3b, 0x4a, 0xb3, 0xef,
+	0xc1, 0x9c, 0x25, 0x69, 0xd2, 0xf0, 0x5a, 0xdc, 0x70, 0x5f, 0xc6, 0x47, 0xaa, 0x7d, 0x58, 0x8a,
+	0x8e, 0x3e, 0x17, 0x0d, 0x82, 0x5b, 0xe1, 0x7a, 0x93, 0xfe, 0x11, 0x61, 0x7e, 0x6f, 0xca, 0xb8,
+	0xed, 0x45, 0xd0, 0x44, 0x73, 0xfa, 0x0e, 0xc1, 0x95, 0xd8, 0xfc, 0xe1, 0xc6, 0x62, 0xdf, 0x19,
+	0x59, 0xba, 0xf7, 0x60, 0x10, 0xe4, 0xf5, 0x82, 0x4b, 0x6f, 0x4b, 0x72, 0x7b, 0x10, 0x4e, 0xfc,
+	0x74, 0x24, 0xf1, 0x83, 0xe6, 0x9b, 0xb9, 0xd4, 0xe6, 0x1b, 0x74, 0xc7, 0xec, 0xd9, 0xdd, 0xf1,
+	0x1f, 0x08, 0x72, 0xc2, 0xc3, 0xcb, 0x4a, 0x7e, 0x05, 0xe6, 0x88, 0xdd, 0x1f, 0x0d, 0x4c, 0xfb,
+	0x80, 0x67, 0x47, 0x4e, 0xf3, 0xd7, 0xf8, 0xb1, 0xac, 0x05, 0x6e, 0x39, 0x2a, 0x6d, 0x7e, 0x24,
+	0x7d, 0xbf, 0x77, 0x2e, 0xdf, 0x9f, 0xd8, 0xd4, 0xd8, 0x27, 0x9b, 0xc7, 0x8c, 0x74, 0x87, 0x66,
+	0xdf, 0x2b, 0x17, 0x4d, 0x98, 0x8f, 0x5c, 0x93, 0x8b, 0x8f, 0xdc, 0xaa, 0x0e, 0xa5, 0x30, 0x07,
+	0xdf, 0x90, 0x23, 0xb8, 0x28, 0xfe, 0x8b, 0x9e, 0x34, 0x67, 0x07, 0xc3, 0x36, 0xc6, 0x90, 0xe5,
+	0x5d, 0x5f, 0x1c, 0x3a, 0xff, 0x0e, 0xfe, 0x02, 0x8b, 0x6b, 0x21, 0x16, 0xef, 0x34, 0xa0, 0x18,
+	0xea, 0x1c, 0x78, 0x1e, 0x0a, 0xed, 0x1d, 0xbd, 0xd3, 0xea, 0xec, 0x6a, 0xbf, 0x2a, 0xa7, 0xdc,
+	0x29, 0xbd, 0xb9, 0xe5, 0x4e, 0xe6, 0x65, 0xf4, 0xce, 0x23, 0x28, 0xf8, 0xdb, 0xe0, 0x02, 0xe4,
+	0x5a, 0x9f, 0x3d, 0x69, 0x7e, 0x5a, 0x4e, 0xb9, 0x22, 0x3b, 0xbb, 0x7b, 0xba, 0x58, 0x22, 0x7c,
+	0x05, 0x8a, 0x5a, 0xeb, 0x61, 0xeb, 0x0b, 0xbd, 0xd3, 0xdc, 0xdb, 0xda, 0x2e, 0xa7, 0x31, 0x86,
+	0x05, 0x41, 0xd8, 0xd9, 0x95, 0xb4, 0xcc, 0x9d, 0xaf, 0xe7, 0x60, 0xce, 0x4b, 0x53, 0xfc, 0x01,
+	0x64, 0x1f, 0x4f, 0xe8, 0x21, 0xbe, 0x1a, 0xe4, 0xe0, 0x2f, 0x1d, 0x93, 0x11, 0x59, 0x10, 0x94,
+	0xea, 0x14, 0x5d, 0x5c, 0x34, 0x35, 0x85, 0x1f, 0x40, 0x31, 0x34, 0xba, 0xe1, 0xa5, 0xc8, 0x98,
+	0xea, 0xc9, 0xaf, 0x24, 0x0c, 0xaf, 0x81, 0x8e, 0x5b, 0x08, 0xef, 0xc2, 0x02, 0x67, 0x79, 0xa3,
+	0x19, 0xc5, 0x6f, 0x7a, 0x22, 0x49, 0x7f, 0xee, 0x94, 0xd5, 0x19, 0x5c, 0xdf, 0xac, 0xed, 0xe8,
+	0xf3, 0x93, 0x92, 0xf4, 0x52, 0x15, 0x37, 0x2e, 0x61, 0x02, 0x52, 0x53, 0xb8, 0x05, 0x10, 0xcc,
+	0x0f, 0xf8, 0x5a, 0x04, 0x1c, 0x9e, 0x79, 0x14, 0x25, 0x89, 0xe5, 0xab, 0xd9, 0x84, 0x82, 0xdf,
+	0xd3, 0x70, 0x2d, 0xa1, 0xcd, 0x09, 0x25, 0xb3, 0x1b, 0xa0, 0x9a, 0xc2, 0x1f, 0x43, 0xa9, 0x39,
+	0x1c, 0x9e, 0x47, 0x8d, 0x12, 0xe6, 0xd0, 0xb8, 0x9e, 0x21, 0x54, 0x67, 0xb4, 0x11, 0xfc, 0x96,
+	0x9f, 0xcf, 0xa7, 0xf6, 0x46, 0xe5, 0xed, 0x33, 0x71, 0xfe, 0x6e, 0x7b, 0x70, 0x25, 0x56, 0xf5,
+	0x71, 0x3d, 0x26, 0x1d, 0x6b, 0x40, 0xca, 0xda, 0x4c, 0xbe, 0xaf, 0xb5, 0x07, 0x95, 0x20, 0xce,
+	0xfe, 0x4b, 0x25, 0x56, 0xa7, 0x0f, 0x21, 0xfe, 0x9c, 0xaa, 0xfc, 0xe8, 0x54, 0x4c, 0x28, 0x2b,
+	0x8f, 0xe0, 0x6a, 0xf2, 0x83, 0x1e, 0xbe, 0x91, 0x90, 0x33, 0xd3, 0x8f, 0x93, 0xca, 0x5b, 0x67,
+	0xc1, 0x42, 0x9b, 0x3d, 0x05, 0x3c, 0xfd, 0x6a, 0x84, 0xaf, 0xcf, 0x7e, 0x77, 0xf2, 0x36, 0x51,
+	0x4f, 0x83, 0xf8, 0xf1, 0xea, 0x40, 0x29, 0xdc, 0x2a, 0xf1, 0xca, 0x29, 0x6f, 0x07, 0xca, 0x9b,
+	0xc9, 0xcc, 0xc0, 0xda, 0xcd, 0x8f, 0x9e, 0xbf, 0xac, 0xa7, 0xbe, 0x7d, 0x59, 0x4f, 0x7d, 0xff,
+	0xb2, 0x8e, 0x7e, 0x7b, 0x52, 0x47, 0x7f, 0x39, 0xa9, 0xa3, 0x6f, 0x4e, 0xea, 0xe8, 0xf9, 0x49,
+	0x1d, 0xfd, 0xfb, 0xa4, 0x8e, 0xfe, 0x73, 0x52, 0x4f, 0x7d, 0x7f, 0x52, 0x47, 0xbf, 0x7f, 0x55,
+	0x4f, 0x3d, 0x7f, 0x55, 0x4f, 0x7d, 0xfb, 0xaa, 0x9e, 0xfa, 0x75, 0xbe, 0x3f, 0x34, 0x89, 0xcd,
+	0x7a, 0x79, 0xfe, 0xec, 0x7d, 0xf7, 0x7f, 0x03, 0x00, 0x28, 0xd4, 0x64, 0x8e, 0x71, 0x17, 0x00,
+	0x00,
 }
 
 func (x CountMethod) String() string {
@@ -2109,6 +2285,97 @@ func (this *LabelValueSeriesCount) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *CardinalityHistoryRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CardinalityHistoryRequest)
+	if !ok {
+		that2, ok := that.(CardinalityHistoryRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.StartTimestampMs != that1.StartTimestampMs {
+		return false
+	}
+	if this.EndTimestampMs != that1.EndTimestampMs {
+		return false
+	}
+	return true
+}
+func (this *CardinalityHistoryResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CardinalityHistoryResponse)
+	if !ok {
+		that2, ok := that.(CardinalityHistoryResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Samples) != len(that1.Samples) {
+		return false
+	}
+	for i := range this.Samples {
+		if !this.Samples[i].Equal(that1.Samples[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *CardinalityHistorySample) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CardinalityHistorySample)
+	if !ok {
+		that2, ok := that.(CardinalityHistorySample)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.TimestampMs != that1.TimestampMs {
+		return false
+	}
+	if this.NumSeries != that1.NumSeries {
+		return false
+	}
+	if len(this.Items) != len(that1.Items) {
+		return false
+	}
+	for i := range this.Items {
+		if !this.Items[i].Equal(that1.Items[i]) {
+			return false
+		}
+	}
+	return true
+}
 func (this *QueryRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -2568,6 +2835,9 @@ func (this *UserStatsResponse) Equal(that interface{}) bool {
 	if this.RuleIngestionRate != that1.RuleIngestionRate {
 		return false
 	}
+	if this.SeriesCreationRate != that1.SeriesCreationRate {
+		return false
+	}
 	return true
 }
 func (this *UserIDStatsResponse) Equal(that interface{}) bool {
@@ -3006,46 +3276,83 @@ func (this *LabelValueSeriesCount) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
-func (this *QueryRequest) GoString() string {
+func (this *CardinalityHistoryRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
-	s = append(s, "&client.QueryRequest{")
+	s := make([]string, 0, 6)
+	s = append(s, "&client.CardinalityHistoryRequest{")
 	s = append(s, "StartTimestampMs: "+fmt.Sprintf("%#v", this.StartTimestampMs)+",\n")
 	s = append(s, "EndTimestampMs: "+fmt.Sprintf("%#v", this.EndTimestampMs)+",\n")
-	if this.Matchers != nil {
-		s = append(s, "Matchers: "+fmt.Sprintf("%#v", this.Matchers)+",\n")
-	}
-	s = append(s, "StreamingChunksBatchSize: "+fmt.Sprintf("%#v", this.StreamingChunksBatchSize)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
-func (this *ExemplarQueryRequest) GoString() string {
+func (this *CardinalityHistoryResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 7)
-	s = append(s, "&client.ExemplarQueryRequest{")
-	s = append(s, "StartTimestampMs: "+fmt.Sprintf("%#v", this.StartTimestampMs)+",\n")
-	s = append(s, "EndTimestampMs: "+fmt.Sprintf("%#v", this.EndTimestampMs)+",\n")
-	if this.Matchers != nil {
-		s = append(s, "Matchers: "+fmt.Sprintf("%#v", this.Matchers)+",\n")
+	s := make([]string, 0, 5)
+	s = append(s, "&client.CardinalityHistoryResponse{")
+	if this.Samples != nil {
+		s = append(s, "Samples: "+fmt.Sprintf("%#v", this.Samples)+",\n")
 	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
-func (this *ActiveSeriesRequest) GoString() string {
+func (this *CardinalityHistorySample) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&client.ActiveSeriesRequest{")
-	if this.Matchers != nil {
-		s = append(s, "Matchers: "+fmt.Sprintf("%#v", this.Matchers)+",\n")
-	}
-	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
-	s = append(s, "}")
+	s := make([]string, 0, 7)
+	s = append(s, "&client.CardinalityHistorySample{")
+	s = append(s, "TimestampMs: "+fmt.Sprintf("%#v", this.TimestampMs)+",\n")
+	s = append(s, "NumSeries: "+fmt.Sprintf("%#v", this.NumSeries)+",\n")
+	if this.Items != nil {
+		s = append(s, "Items: "+fmt.Sprintf("%#v", this.Items)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *QueryRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&client.QueryRequest{")
+	s = append(s, "StartTimestampMs: "+fmt.Sprintf("%#v", this.StartTimestampMs)+",\n")
+	s = append(s, "EndTimestampMs: "+fmt.Sprintf("%#v", this.EndTimestampMs)+",\n")
+	if this.Matchers != nil {
+		s = append(s, "Matchers: "+fmt.Sprintf("%#v", this.Matchers)+",\n")
+	}
+	s = append(s, "StreamingChunksBatchSize: "+fmt.Sprintf("%#v", this.StreamingChunksBatchSize)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ExemplarQueryRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&client.ExemplarQueryRequest{")
+	s = append(s, "StartTimestampMs: "+fmt.Sprintf("%#v", this.StartTimestampMs)+",\n")
+	s = append(s, "EndTimestampMs: "+fmt.Sprintf("%#v", this.EndTimestampMs)+",\n")
+	if this.Matchers != nil {
+		s = append(s, "Matchers: "+fmt.Sprintf("%#v", this.Matchers)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ActiveSeriesRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&client.ActiveSeriesRequest{")
+	if this.Matchers != nil {
+		s = append(s, "Matchers: "+fmt.Sprintf("%#v", this.Matchers)+",\n")
+	}
+	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
+	s = append(s, "}")
 	return strings.Join(s, "")
 }
 func (this *QueryResponse) GoString() string {
@@ -3209,12 +3516,13 @@ func (this *UserStatsResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
+	s := make([]string, 0, 9)
 	s = append(s, "&client.UserStatsResponse{")
 	s = append(s, "IngestionRate: "+fmt.Sprintf("%#v", this.IngestionRate)+",\n")
 	s = append(s, "NumSeries: "+fmt.Sprintf("%#v", this.NumSeries)+",\n")
 	s = append(s, "ApiIngestionRate: "+fmt.Sprintf("%#v", this.ApiIngestionRate)+",\n")
 	s = append(s, "RuleIngestionRate: "+fmt.Sprintf("%#v", this.RuleIngestionRate)+",\n")
+	s = append(s, "SeriesCreationRate: "+fmt.Sprintf("%#v", this.SeriesCreationRate)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -3399,6 +3707,9 @@ type IngesterClient interface {
 	// that match the matchers.
 	// The listing order of the labels is not guaranteed.
 	LabelValuesCardinality(ctx context.Context, in *LabelValuesCardinalityRequest, opts ...grpc.CallOption) (Ingester_LabelValuesCardinalityClient, error)
+	// CardinalityHistory returns the periodic samples of the tenant's top metric names and label name-value pairs
+	// by number of in-memory series, recorded by the ingester.
+	CardinalityHistory(ctx context.Context, in *CardinalityHistoryRequest, opts ...grpc.CallOption) (*CardinalityHistoryResponse, error)
 	ActiveSeries(ctx context.Context, in *ActiveSeriesRequest, opts ...grpc.CallOption) (Ingester_ActiveSeriesClient, error)
 }
 
@@ -3578,6 +3889,15 @@ func (x *ingesterLabelValuesCardinalityClient) Recv() (*LabelValuesCardinalityRe
 	return m, nil
 }
 
+func (c *ingesterClient) CardinalityHistory(ctx context.Context, in *CardinalityHistoryRequest, opts ...grpc.CallOption) (*CardinalityHistoryResponse, error) {
+	out := new(CardinalityHistoryResponse)
+	err := c.cc.Invoke(ctx, "/cortex.Ingester/CardinalityHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ingesterClient) ActiveSeries(ctx context.Context, in *ActiveSeriesRequest, opts ...grpc.CallOption) (Ingester_ActiveSeriesClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_Ingester_serviceDesc.Streams[3], "/cortex.Ingester/ActiveSeries", opts...)
 	if err != nil {
@@ -3628,6 +3948,9 @@ type IngesterServer interface {
 	// that match the matchers.
 	// The listing order of the labels is not guaranteed.
 	LabelValuesCardinality(*LabelValuesCardinalityRequest, Ingester_LabelValuesCardinalityServer) error
+	// CardinalityHistory returns the periodic samples of the tenant's top metric names and label name-value pairs
+	// by number of in-memory series, recorded by the ingester.
+	CardinalityHistory(context.Context, *CardinalityHistoryRequest) (*CardinalityHistoryResponse, error)
 	ActiveSeries(*ActiveSeriesRequest, Ingester_ActiveSeriesServer) error
 }
 
@@ -3668,6 +3991,9 @@ func (*UnimplementedIngesterServer) LabelNamesAndValues(req *LabelNamesAndValues
 func (*UnimplementedIngesterServer) LabelValuesCardinality(req *LabelValuesCardinalityRequest, srv Ingester_LabelValuesCardinalityServer) error {
 	return status.Errorf(codes.Unimplemented, "method LabelValuesCardinality not implemented")
 }
+func (*UnimplementedIngesterServer) CardinalityHistory(ctx context.Context, req *CardinalityHistoryRequest) (*CardinalityHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CardinalityHistory not implemented")
+}
 func (*UnimplementedIngesterServer) ActiveSeries(req *ActiveSeriesRequest, srv Ingester_ActiveSeriesServer) error {
 	return status.Errorf(codes.Unimplemented, "method ActiveSeries not implemented")
 }
@@ -3883,6 +4209,24 @@ func (x *ingesterLabelValuesCardinalityServer) Send(m *LabelValuesCardinalityRes
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Ingester_CardinalityHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CardinalityHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngesterServer).CardinalityHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cortex.Ingester/CardinalityHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngesterServer).CardinalityHistory(ctx, req.(*CardinalityHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Ingester_ActiveSeries_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(ActiveSeriesRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -3940,6 +4284,10 @@ var _Ingester_serviceDesc = grpc.ServiceDesc{
 			MethodName: "MetricsMetadata",
 			Handler:    _Ingester_MetricsMetadata_Handler,
 		},
+		{
+			MethodName: "CardinalityHistory",
+			Handler:    _Ingester_CardinalityHistory_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -4219,6 +4567,123 @@ func (m *LabelValueSeriesCount) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *CardinalityHistoryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CardinalityHistoryRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CardinalityHistoryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.EndTimestampMs != 0 {
+		i = encodeVarintIngester(dAtA, i, uint64(m.EndTimestampMs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.StartTimestampMs != 0 {
+		i = encodeVarintIngester(dAtA, i, uint64(m.StartTimestampMs))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CardinalityHistoryResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CardinalityHistoryResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CardinalityHistoryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Samples) > 0 {
+		for iNdEx := len(m.Samples) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Samples[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CardinalityHistorySample) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CardinalityHistorySample) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CardinalityHistorySample) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Items) > 0 {
+		for iNdEx := len(m.Items) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Items[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.NumSeries != 0 {
+		i = encodeVarintIngester(dAtA, i, uint64(m.NumSeries))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.TimestampMs != 0 {
+		i = encodeVarintIngester(dAtA, i, uint64(m.TimestampMs))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -4818,6 +5283,12 @@ func (m *UserStatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SeriesCreationRate != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.SeriesCreationRate))))
+		i--
+		dAtA[i] = 0x29
+	}
 	if m.RuleIngestionRate != 0 {
 		i -= 8
 		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.RuleIngestionRate))))
@@ -5454,6 +5925,57 @@ func (m *LabelValueSeriesCount) Size() (n int) {
 	return n
 }
 
+func (m *CardinalityHistoryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.StartTimestampMs != 0 {
+		n += 1 + sovIngester(uint64(m.StartTimestampMs))
+	}
+	if m.EndTimestampMs != 0 {
+		n += 1 + sovIngester(uint64(m.EndTimestampMs))
+	}
+	return n
+}
+
+func (m *CardinalityHistoryResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Samples) > 0 {
+		for _, e := range m.Samples {
+			l = e.Size()
+			n += 1 + l + sovIngester(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *CardinalityHistorySample) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TimestampMs != 0 {
+		n += 1 + sovIngester(uint64(m.TimestampMs))
+	}
+	if m.NumSeries != 0 {
+		n += 1 + sovIngester(uint64(m.NumSeries))
+	}
+	if len(m.Items) > 0 {
+		for _, e := range m.Items {
+			l = e.Size()
+			n += 1 + l + sovIngester(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *QueryRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -5721,6 +6243,9 @@ func (m *UserStatsResponse) Size() (n int) {
 	if m.RuleIngestionRate != 0 {
 		n += 9
 	}
+	if m.SeriesCreationRate != 0 {
+		n += 9
+	}
 	return n
 }
 
@@ -6033,6 +6558,49 @@ func (this *LabelValueSeriesCount) String() string {
 	}, "")
 	return s
 }
+func (this *CardinalityHistoryRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CardinalityHistoryRequest{`,
+		`StartTimestampMs:` + fmt.Sprintf("%v", this.StartTimestampMs) + `,`,
+		`EndTimestampMs:` + fmt.Sprintf("%v", this.EndTimestampMs) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CardinalityHistoryResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSamples := "[]*CardinalityHistorySample{"
+	for _, f := range this.Samples {
+		repeatedStringForSamples += strings.Replace(f.String(), "CardinalityHistorySample", "CardinalityHistorySample", 1) + ","
+	}
+	repeatedStringForSamples += "}"
+	s := strings.Join([]string{`&CardinalityHistoryResponse{`,
+		`Samples:` + repeatedStringForSamples + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CardinalityHistorySample) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForItems := "[]*LabelValueSeriesCount{"
+	for _, f := range this.Items {
+		repeatedStringForItems += strings.Replace(f.String(), "LabelValueSeriesCount", "LabelValueSeriesCount", 1) + ","
+	}
+	repeatedStringForItems += "}"
+	s := strings.Join([]string{`&CardinalityHistorySample{`,
+		`TimestampMs:` + fmt.Sprintf("%v", this.TimestampMs) + `,`,
+		`NumSeries:` + fmt.Sprintf("%v", this.NumSeries) + `,`,
+		`Items:` + repeatedStringForItems + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *QueryRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -6239,6 +6807,7 @@ func (this *UserStatsResponse) String() string {
 		`NumSeries:` + fmt.Sprintf("%v", this.NumSeries) + `,`,
 		`ApiIngestionRate:` + fmt.Sprintf("%v", this.ApiIngestionRate) + `,`,
 		`RuleIngestionRate:` + fmt.Sprintf("%v", this.RuleIngestionRate) + `,`,
+		`SeriesCreationRate:` + fmt.Sprintf("%v", this.SeriesCreationRate) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -7125,7 +7694,301 @@ func (m *LabelValueSeriesCount) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CardinalityHistoryRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CardinalityHistoryRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CardinalityHistoryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimestampMs", wireType)
+			}
+			m.StartTimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndTimestampMs", wireType)
+			}
+			m.EndTimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EndTimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CardinalityHistoryResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CardinalityHistoryResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CardinalityHistoryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Samples = append(m.Samples, &CardinalityHistorySample{})
+			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CardinalityHistorySample) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CardinalityHistorySample: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CardinalityHistorySample: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampMs", wireType)
+			}
+			m.TimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NumSeries", wireType)
+			}
+			m.NumSeries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NumSeries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Items = append(m.Items, &LabelValueSeriesCount{})
+			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthIngester
 			}
 			if (iNdEx + skippy) < 0 {
@@ -8722,6 +9585,17 @@ func (m *UserStatsResponse) Unmarshal(dAtA []byte) error {
 			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
 			iNdEx += 8
 			m.RuleIngestionRate = float64(math.Float64frombits(v))
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SeriesCreationRate", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.SeriesCreationRate = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipIngester(dAtA[iNdEx:])
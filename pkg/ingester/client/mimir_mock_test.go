@@ -77,6 +77,11 @@ func (m *IngesterServerMock) LabelValuesCardinality(req *LabelValuesCardinalityR
 	return args.Error(0)
 }
 
+func (m *IngesterServerMock) CardinalityHistory(ctx context.Context, r *CardinalityHistoryRequest) (*CardinalityHistoryResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CardinalityHistoryResponse), args.Error(1)
+}
+
 func (m *IngesterServerMock) ActiveSeries(req *ActiveSeriesRequest, srv Ingester_ActiveSeriesServer) error {
 	args := m.Called(req, srv)
 	return args.Error(0)
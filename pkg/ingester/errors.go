@@ -335,6 +335,120 @@ var _ ingesterError = perMetricSeriesLimitReachedError{}
 // Ensure that perMetricSeriesLimitReachedError is an softError.
 var _ softError = perMetricSeriesLimitReachedError{}
 
+// perLabelValueSeriesLimitReachedError is an ingesterError indicating that a per-label-value series limit has been reached.
+type perLabelValueSeriesLimitReachedError struct {
+	limit     int
+	labelName string
+	series    string
+}
+
+// newPerLabelValueSeriesLimitReachedError creates a new perLabelValueSeriesLimitReachedError indicating that a per-label-value series limit has been reached.
+func newPerLabelValueSeriesLimitReachedError(limit int, labelName string, labels []mimirpb.LabelAdapter) perLabelValueSeriesLimitReachedError {
+	return perLabelValueSeriesLimitReachedError{
+		limit:     limit,
+		labelName: labelName,
+		series:    mimirpb.FromLabelAdaptersToString(labels),
+	}
+}
+
+func (e perLabelValueSeriesLimitReachedError) Error() string {
+	return fmt.Sprintf("%s This is for series %s",
+		globalerror.MaxSeriesPerLabelValue.MessageWithPerTenantLimitConfig(
+			fmt.Sprintf("per-label-value series limit of %d for label name %s exceeded", e.limit, e.labelName),
+			validation.MaxSeriesPerLabelValueFlag,
+		),
+		e.series,
+	)
+}
+
+func (e perLabelValueSeriesLimitReachedError) errorCause() mimirpb.ErrorCause {
+	return mimirpb.BAD_DATA
+}
+
+func (e perLabelValueSeriesLimitReachedError) soft() {}
+
+// Ensure that perLabelValueSeriesLimitReachedError is an ingesterError.
+var _ ingesterError = perLabelValueSeriesLimitReachedError{}
+
+// Ensure that perLabelValueSeriesLimitReachedError is an softError.
+var _ softError = perLabelValueSeriesLimitReachedError{}
+
+// perLabelNameValuesLimitReachedError is an ingesterError indicating that a per-label-name values limit has been reached.
+type perLabelNameValuesLimitReachedError struct {
+	limit     int
+	labelName string
+	series    string
+}
+
+// newPerLabelNameValuesLimitReachedError creates a new perLabelNameValuesLimitReachedError indicating that a per-label-name values limit has been reached.
+func newPerLabelNameValuesLimitReachedError(limit int, labelName string, labels []mimirpb.LabelAdapter) perLabelNameValuesLimitReachedError {
+	return perLabelNameValuesLimitReachedError{
+		limit:     limit,
+		labelName: labelName,
+		series:    mimirpb.FromLabelAdaptersToString(labels),
+	}
+}
+
+func (e perLabelNameValuesLimitReachedError) Error() string {
+	return fmt.Sprintf("%s This is for series %s",
+		globalerror.MaxLabelValuesPerLabelName.MessageWithPerTenantLimitConfig(
+			fmt.Sprintf("per-label-name values limit of %d for label name %s exceeded", e.limit, e.labelName),
+			validation.MaxLabelValuesPerLabelNameFlag,
+		),
+		e.series,
+	)
+}
+
+func (e perLabelNameValuesLimitReachedError) errorCause() mimirpb.ErrorCause {
+	return mimirpb.BAD_DATA
+}
+
+func (e perLabelNameValuesLimitReachedError) soft() {}
+
+// Ensure that perLabelNameValuesLimitReachedError is an ingesterError.
+var _ ingesterError = perLabelNameValuesLimitReachedError{}
+
+// Ensure that perLabelNameValuesLimitReachedError is an softError.
+var _ softError = perLabelNameValuesLimitReachedError{}
+
+// seriesCreationRateLimitedError is an ingesterError indicating that the series creation rate limit has been reached.
+type seriesCreationRateLimitedError struct {
+	limit  float64
+	burst  int
+	series string
+}
+
+// newSeriesCreationRateLimitedError creates a new seriesCreationRateLimitedError indicating that the series creation rate limit has been reached.
+func newSeriesCreationRateLimitedError(limit float64, burst int, labels []mimirpb.LabelAdapter) seriesCreationRateLimitedError {
+	return seriesCreationRateLimitedError{
+		limit:  limit,
+		burst:  burst,
+		series: mimirpb.FromLabelAdaptersToString(labels),
+	}
+}
+
+func (e seriesCreationRateLimitedError) Error() string {
+	return fmt.Sprintf("%s This is for series %s",
+		globalerror.MaxSeriesCreationRate.MessageWithPerTenantLimitConfig(
+			fmt.Sprintf("the series creation rate limit (limit: %g series/sec, burst: %d) has been exceeded", e.limit, e.burst),
+			validation.MaxSeriesCreationRateFlag,
+		),
+		e.series,
+	)
+}
+
+func (e seriesCreationRateLimitedError) errorCause() mimirpb.ErrorCause {
+	return mimirpb.BAD_DATA
+}
+
+func (e seriesCreationRateLimitedError) soft() {}
+
+// Ensure that seriesCreationRateLimitedError is an ingesterError.
+var _ ingesterError = seriesCreationRateLimitedError{}
+
+// Ensure that seriesCreationRateLimitedError is an softError.
+var _ softError = seriesCreationRateLimitedError{}
+
 // perMetricMetadataLimitReachedError is an ingesterError indicating that a per-metric metadata limit has been reached.
 type perMetricMetadataLimitReachedError struct {
 	limit  int
@@ -513,16 +627,19 @@ func (e circuitBreakerOpenError) errorCause() mimirpb.ErrorCause {
 var _ ingesterError = circuitBreakerOpenError{}
 
 type ingesterErrSamplers struct {
-	sampleTimestampTooOld             *log.Sampler
-	sampleTimestampTooOldOOOEnabled   *log.Sampler
-	sampleTimestampTooFarInFuture     *log.Sampler
-	sampleOutOfOrder                  *log.Sampler
-	sampleDuplicateTimestamp          *log.Sampler
-	maxSeriesPerMetricLimitExceeded   *log.Sampler
-	maxMetadataPerMetricLimitExceeded *log.Sampler
-	maxSeriesPerUserLimitExceeded     *log.Sampler
-	maxMetadataPerUserLimitExceeded   *log.Sampler
-	nativeHistogramValidationError    *log.Sampler
+	sampleTimestampTooOld                   *log.Sampler
+	sampleTimestampTooOldOOOEnabled         *log.Sampler
+	sampleTimestampTooFarInFuture           *log.Sampler
+	sampleOutOfOrder                        *log.Sampler
+	sampleDuplicateTimestamp                *log.Sampler
+	maxSeriesPerMetricLimitExceeded         *log.Sampler
+	maxSeriesPerLabelValueLimitExceeded     *log.Sampler
+	maxLabelValuesPerLabelNameLimitExceeded *log.Sampler
+	seriesCreationRateLimitExceeded         *log.Sampler
+	maxMetadataPerMetricLimitExceeded       *log.Sampler
+	maxSeriesPerUserLimitExceeded           *log.Sampler
+	maxMetadataPerUserLimitExceeded         *log.Sampler
+	nativeHistogramValidationError          *log.Sampler
 }
 
 func newIngesterErrSamplers(freq int64) ingesterErrSamplers {
@@ -537,6 +654,9 @@ func newIngesterErrSamplers(freq int64) ingesterErrSamplers {
 		log.NewSampler(freq),
 		log.NewSampler(freq),
 		log.NewSampler(freq),
+		log.NewSampler(freq),
+		log.NewSampler(freq),
+		log.NewSampler(freq),
 	}
 }
 
@@ -0,0 +1,498 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/concurrency"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/record"
+	"github.com/prometheus/prometheus/tsdb/wlog"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+)
+
+const (
+	// headSnapshotsDirname is the directory of the tenant in the storage where the ingesters upload
+	// their TSDB head snapshots, in a subdirectory named after the ingester ID.
+	headSnapshotsDirname = "head-snapshots"
+
+	// headSnapshotPrefix is the prefix of the TSDB head snapshot directories, followed by the WAL
+	// segment and offset up to which the snapshot has been written.
+	headSnapshotPrefix = "chunk_snapshot."
+
+	// headSnapshotUploadCompleteFilename is the name of the file uploaded with a head snapshot once
+	// all the other files of the head snapshot have been uploaded.
+	headSnapshotUploadCompleteFilename = "upload-complete"
+
+	// headSnapshotUploadDirname and headSnapshotDownloadDirname are the directories in the TSDB directory
+	// where the files of a head snapshot are prepared for the upload and downloaded, respectively.
+	headSnapshotUploadDirname   = "head-snapshot-upload"
+	headSnapshotDownloadDirname = "head-snapshot-download"
+
+	// headChunksDirname and walDirname are the directories of the m-mapped head chunks and the WAL
+	// in the TSDB directory.
+	headChunksDirname = "chunks_head"
+	walDirname        = "wal"
+)
+
+// snapshotHeads writes a snapshot of the TSDB head of all tenants on disk, and uploads them to the
+// storage if enabled. Tenants are snapshotted with the same concurrency as head compaction.
+func (i *Ingester) snapshotHeads(ctx context.Context) error {
+	_ = concurrency.ForEachUser(ctx, i.getTSDBUsers(), i.cfg.BlocksStorageConfig.TSDB.HeadCompactionConcurrency, func(ctx context.Context, userID string) error {
+		userDB := i.getTSDB(userID)
+		if userDB == nil {
+			return nil
+		}
+
+		// Make sure the TSDB is not closed while it's snapshotted. The TSDB state is not changed,
+		// so that the blocks shipping and the head compactions are not blocked by the snapshot.
+		if s, ok := userDB.acquireHeadSnapshotLock(); !ok {
+			level.Info(i.logger).Log("msg", "head snapshot skipped because the TSDB is not active", "user", userID, "state", s.String())
+			return nil
+		}
+		defer userDB.releaseHeadSnapshotLock()
+
+		start := time.Now()
+		stats, err := userDB.Head().ChunkSnapshot()
+		if err != nil {
+			i.metrics.headSnapshotsFailed.Inc()
+			level.Warn(i.logger).Log("msg", "failed to snapshot TSDB head", "user", userID, "err", err)
+			return nil
+		}
+		i.metrics.headSnapshots.Inc()
+		userDB.lastHeadSnapshot.Store(start.Unix())
+
+		// The directory is empty if nothing has been written to the TSDB head since the previous snapshot.
+		if stats.Dir == "" {
+			return nil
+		}
+		level.Debug(i.logger).Log("msg", "TSDB head snapshot complete", "user", userID, "duration", time.Since(start), "series", stats.TotalSeries, "dir", stats.Dir)
+
+		if i.cfg.BlocksStorageConfig.TSDB.HeadSnapshotUploadEnabled {
+			if err := i.uploadHeadSnapshot(ctx, userID, stats.Dir); err != nil {
+				i.metrics.headSnapshotUploadsFailed.Inc()
+				level.Warn(i.logger).Log("msg", "failed to upload TSDB head snapshot", "user", userID, "dir", stats.Dir, "err", err)
+			}
+		}
+		return nil
+	})
+
+	return nil
+}
+
+// uploadHeadSnapshot uploads the head snapshot directory to the storage, along with the m-mapped head
+// chunks and the WAL segments written after the snapshot, which are required to restore the TSDB head
+// from the snapshot. Then, the head snapshots previously uploaded by the ingester for the tenant are deleted.
+// Like after a crash, the head chunks which the TSDB head hasn't written to disk yet are not restored.
+func (i *Ingester) uploadHeadSnapshot(ctx context.Context, userID, dir string) error {
+	udir := i.cfg.BlocksStorageConfig.TSDB.BlocksDir(userID)
+	name := filepath.Base(dir)
+
+	walSegment, _, err := parseHeadSnapshotName(name)
+	if err != nil {
+		return err
+	}
+
+	// The files are hard-linked into a directory first, so that they're not deleted by a concurrent
+	// head compaction while they're uploaded. The last WAL segment may end with a partially written
+	// record, which is repaired when the WAL is replayed.
+	stagingDir := filepath.Join(udir, headSnapshotUploadDirname)
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(stagingDir); err != nil {
+			level.Warn(i.logger).Log("msg", "failed to remove TSDB head snapshot upload directory", "user", userID, "dir", stagingDir, "err", err)
+		}
+	}()
+
+	if err := linkFiles(dir, filepath.Join(stagingDir, name), nil); err != nil {
+		return errors.Wrap(err, "link head snapshot")
+	}
+	if err := linkHeadChunks(filepath.Join(udir, headChunksDirname), filepath.Join(stagingDir, headChunksDirname)); err != nil {
+		return errors.Wrap(err, "link head chunks")
+	}
+	walSegmentsAfterSnapshot := func(name string) bool {
+		segment, err := strconv.Atoi(name)
+		return err == nil && segment >= walSegment
+	}
+	if err := linkFiles(filepath.Join(udir, walDirname), filepath.Join(stagingDir, walDirname), walSegmentsAfterSnapshot); err != nil {
+		return errors.Wrap(err, "link WAL")
+	}
+	// The out-of-order samples are replayed from the whole WBL, which only exists if out-of-order ingestion is enabled.
+	if err := linkFiles(filepath.Join(udir, wlog.WblDirName), filepath.Join(stagingDir, wlog.WblDirName), isSegmentName); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "link WBL")
+	}
+
+	bkt := bucket.NewUserBucketClient(userID, i.bucket, i.limits)
+	dst := i.headSnapshotsPath(name)
+	if err := objstore.UploadDir(ctx, i.logger, bkt, stagingDir, dst); err != nil {
+		return err
+	}
+	if err := bkt.Upload(ctx, path.Join(dst, headSnapshotUploadCompleteFilename), strings.NewReader("")); err != nil {
+		return errors.Wrap(err, "upload head snapshot completion marker")
+	}
+	return i.deleteUploadedHeadSnapshots(ctx, userID, name)
+}
+
+// linkFiles hard-links the files of the src directory accepted by the filter, or all of them if the filter
+// is nil, into the dst directory.
+func linkFiles(src, dst string, filter func(name string) bool) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0o750); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() || (filter != nil && !filter(e.Name())) {
+			continue
+		}
+		if err := os.Link(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkHeadChunks hard-links the head chunks files of the src directory into the dst directory, except
+// the last one which is still written by the TSDB head: it's copied up to the end of its last complete chunk.
+func linkHeadChunks(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if os.IsNotExist(err) {
+		// No chunk has been m-mapped yet.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	last, lastSeq := "", -1
+	for _, e := range entries {
+		if seq, err := strconv.Atoi(e.Name()); err == nil && e.Type().IsRegular() && seq > lastSeq {
+			last, lastSeq = e.Name(), seq
+		}
+	}
+	if last == "" {
+		return nil
+	}
+
+	if err := linkFiles(src, dst, func(name string) bool {
+		return isSegmentName(name) && name != last
+	}); err != nil {
+		return err
+	}
+	return copyCompleteHeadChunks(filepath.Join(src, last), filepath.Join(dst, last))
+}
+
+// isSegmentName returns whether the file name is the name of a WAL segment or a head chunks file.
+func isSegmentName(name string) bool {
+	_, err := strconv.Atoi(name)
+	return err == nil
+}
+
+// copyCompleteHeadChunks copies the head chunks file src into dst, up to the end of its last complete chunk.
+func copyCompleteHeadChunks(src, dst string) (returnErr error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	size, err := completeHeadChunksSize(bufio.NewReader(in))
+	if err != nil {
+		return err
+	}
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := out.Close(); err != nil && returnErr == nil {
+			returnErr = err
+		}
+	}()
+
+	_, err = io.CopyN(out, in, size)
+	return err
+}
+
+// completeHeadChunksSize returns the size of the head chunks file read from r, up to the end of its last
+// complete chunk. The file written by the TSDB head can end with a partially written chunk, or zeros.
+func completeHeadChunksSize(r *bufio.Reader) (int64, error) {
+	if _, err := r.Discard(chunks.HeadChunkFileHeaderSize); err != nil {
+		return 0, ignoreEOF(err)
+	}
+
+	var (
+		size = int64(chunks.HeadChunkFileHeaderSize)
+		meta = make([]byte, chunks.SeriesRefSize+2*chunks.MintMaxtSize+chunks.ChunkEncodingSize)
+		sum  = make([]byte, chunks.CRCSize)
+		crc  = crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	)
+	for {
+		if _, err := io.ReadFull(r, meta); err != nil {
+			return size, ignoreEOF(err)
+		}
+		dataLen, err := binary.ReadUvarint(r)
+		if err != nil || dataLen == 0 || dataLen > chunks.MaxHeadChunkFileSize {
+			// The chunk length is missing or invalid, because the chunk hasn't been written yet.
+			return size, nil
+		}
+		dataLenField := binary.AppendUvarint(nil, dataLen)
+
+		crc.Reset()
+		_, _ = crc.Write(meta)
+		_, _ = crc.Write(dataLenField)
+		if _, err := io.CopyN(crc, r, int64(dataLen)); err != nil {
+			return size, ignoreEOF(err)
+		}
+		if _, err := io.ReadFull(r, sum); err != nil {
+			return size, ignoreEOF(err)
+		}
+		if binary.BigEndian.Uint32(sum) != crc.Sum32() {
+			return size, nil
+		}
+
+		size += int64(len(meta)+len(dataLenField)) + int64(dataLen) + chunks.CRCSize
+	}
+}
+
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil
+	}
+	return err
+}
+
+// deleteUploadedHeadSnapshots deletes the head snapshots uploaded by the ingester for the tenant,
+// except the one named keep.
+func (i *Ingester) deleteUploadedHeadSnapshots(ctx context.Context, userID, keep string) error {
+	bkt := bucket.NewUserBucketClient(userID, i.bucket, i.limits)
+
+	var toDelete []string
+	err := bkt.Iter(ctx, i.headSnapshotsPath(""), func(name string) error {
+		if keep == "" || !strings.HasPrefix(name, i.headSnapshotsPath(keep)) {
+			toDelete = append(toDelete, name)
+		}
+		return nil
+	}, objstore.WithRecursiveIter)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range toDelete {
+		if err := bkt.Delete(ctx, name); err != nil && !bkt.IsObjNotFoundErr(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// findUserIDsWithUploadedHeadSnapshot returns the tenants without a TSDB on the filesystem for which
+// the ingester has uploaded a head snapshot to the storage.
+func (i *Ingester) findUserIDsWithUploadedHeadSnapshot(ctx context.Context, localUserIDs []string) ([]string, error) {
+	userIDs, err := mimir_tsdb.ListUsers(ctx, i.bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []string
+	for _, userID := range userIDs {
+		if slices.Contains(localUserIDs, userID) {
+			continue
+		}
+
+		name, err := i.latestUploadedHeadSnapshot(ctx, bucket.NewUserBucketClient(userID, i.bucket, i.limits))
+		if err != nil {
+			return nil, errors.Wrapf(err, "find uploaded head snapshot for user %s", userID)
+		}
+		if name != "" {
+			found = append(found, userID)
+		}
+	}
+	return found, nil
+}
+
+// prepareHeadSnapshotRestore returns whether the TSDB of the tenant has a head snapshot to restore on disk.
+// If the tenant has no TSDB on disk and the head snapshots upload is enabled, the latest head snapshot
+// uploaded by the ingester is downloaded first, along with the head chunks and the WAL uploaded with it.
+func (i *Ingester) prepareHeadSnapshotRestore(ctx context.Context, userID string) bool {
+	udir := i.cfg.BlocksStorageConfig.TSDB.BlocksDir(userID)
+
+	// A download interrupted by a previous restart leaves an incomplete TSDB, which can't be opened.
+	if _, err := os.Stat(filepath.Join(udir, headSnapshotDownloadDirname)); err == nil {
+		level.Warn(i.logger).Log("msg", "removing TSDB partially downloaded from an uploaded head snapshot", "user", userID)
+		if err := os.RemoveAll(udir); err != nil {
+			level.Warn(i.logger).Log("msg", "failed to remove partially downloaded TSDB", "user", userID, "err", err)
+			return false
+		}
+	}
+
+	_, _, _, err := tsdb.LastChunkSnapshot(udir)
+	if err == nil {
+		return true
+	}
+	if !errors.Is(err, record.ErrNotFound) && !os.IsNotExist(err) {
+		level.Warn(i.logger).Log("msg", "failed to find TSDB head snapshot", "user", userID, "err", err)
+		return false
+	}
+	if !i.cfg.BlocksStorageConfig.TSDB.HeadSnapshotUploadEnabled {
+		return false
+	}
+
+	// The TSDB on disk is more recent than any uploaded head snapshot.
+	if entries, err := os.ReadDir(udir); err == nil && len(entries) > 0 {
+		return false
+	}
+
+	name, err := i.downloadLatestHeadSnapshot(ctx, userID, udir)
+	if err != nil {
+		level.Warn(i.logger).Log("msg", "failed to download TSDB head snapshot", "user", userID, "err", err)
+		if err := os.RemoveAll(udir); err != nil {
+			level.Warn(i.logger).Log("msg", "failed to remove partially downloaded TSDB", "user", userID, "err", err)
+		}
+		return false
+	}
+	if name == "" {
+		return false
+	}
+	level.Info(i.logger).Log("msg", "downloaded TSDB head snapshot from the storage", "user", userID, "snapshot", name)
+	return true
+}
+
+// downloadLatestHeadSnapshot downloads the latest head snapshot uploaded by the ingester for the tenant,
+// along with the head chunks and the WAL uploaded with it, into the TSDB directory and returns its name.
+// An empty name is returned if there is no uploaded snapshot.
+func (i *Ingester) downloadLatestHeadSnapshot(ctx context.Context, userID, udir string) (string, error) {
+	bkt := bucket.NewUserBucketClient(userID, i.bucket, i.limits)
+
+	name, err := i.latestUploadedHeadSnapshot(ctx, bkt)
+	if err != nil || name == "" {
+		return "", err
+	}
+
+	// Download into a temporary directory first, so that a partially downloaded TSDB is never opened.
+	tmp := filepath.Join(udir, headSnapshotDownloadDirname)
+	src := i.headSnapshotsPath(name)
+	if err := objstore.DownloadDir(ctx, i.logger, bkt, src, src, tmp); err != nil {
+		return "", err
+	}
+
+	// The head snapshot is moved last, given it's restored only if it exists.
+	for _, dirname := range []string{headChunksDirname, walDirname, wlog.WblDirName, name} {
+		if err := os.Rename(filepath.Join(tmp, dirname), filepath.Join(udir, dirname)); err != nil && !os.IsNotExist(err) {
+			return "", errors.Wrapf(err, "move downloaded %s", dirname)
+		}
+	}
+	return name, os.RemoveAll(tmp)
+}
+
+// latestUploadedHeadSnapshot returns the name of the latest head snapshot whose upload by the ingester
+// has been completed, or an empty name if there is none.
+func (i *Ingester) latestUploadedHeadSnapshot(ctx context.Context, bkt objstore.Bucket) (string, error) {
+	type uploadedHeadSnapshot struct {
+		name               string
+		walSegment, offset int
+	}
+
+	var uploaded []uploadedHeadSnapshot
+	err := bkt.Iter(ctx, i.headSnapshotsPath(""), func(name string) error {
+		name = path.Base(strings.TrimSuffix(name, objstore.DirDelim))
+		walSegment, offset, err := parseHeadSnapshotName(name)
+		if err != nil {
+			level.Warn(i.logger).Log("msg", "ignoring uploaded TSDB head snapshot with invalid name", "snapshot", name, "err", err)
+			return nil
+		}
+		uploaded = append(uploaded, uploadedHeadSnapshot{name: name, walSegment: walSegment, offset: offset})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Sort the head snapshots from the latest to the oldest.
+	slices.SortFunc(uploaded, func(a, b uploadedHeadSnapshot) int {
+		if a.walSegment != b.walSegment {
+			return b.walSegment - a.walSegment
+		}
+		return b.offset - a.offset
+	})
+
+	for _, s := range uploaded {
+		complete, err := bkt.Exists(ctx, path.Join(i.headSnapshotsPath(s.name), headSnapshotUploadCompleteFilename))
+		if err != nil {
+			return "", err
+		}
+		if complete {
+			return s.name, nil
+		}
+	}
+	return "", nil
+}
+
+// headSnapshotsPath returns the path of the head snapshot in the tenant bucket, or the path of the
+// head snapshots directory of the ingester if name is empty.
+func (i *Ingester) headSnapshotsPath(name string) string {
+	return path.Join(headSnapshotsDirname, i.cfg.IngesterRing.InstanceID, name) + objstore.DirDelim
+}
+
+// parseHeadSnapshotName returns the WAL segment and offset of the head snapshot directory name.
+func parseHeadSnapshotName(name string) (int, int, error) {
+	if !strings.HasPrefix(name, headSnapshotPrefix) {
+		return 0, 0, fmt.Errorf("missing %q prefix", headSnapshotPrefix)
+	}
+	idxStr, offsetStr, ok := strings.Cut(strings.TrimPrefix(name, headSnapshotPrefix), ".")
+	if !ok {
+		return 0, 0, errors.New("missing WAL offset")
+	}
+	idx, err := strconv.Atoi(idxStr)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid WAL segment")
+	}
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid WAL offset")
+	}
+	return idx, offset, nil
+}
+
+// getOldestHeadSnapshotMetric returns the unix timestamp of the oldest last successful head snapshot
+// among the tenants which have been snapshotted, or 0 if no tenant has been snapshotted yet.
+func (i *Ingester) getOldestHeadSnapshotMetric() float64 {
+	i.tsdbsMtx.RLock()
+	defer i.tsdbsMtx.RUnlock()
+
+	oldest := int64(0)
+	for _, db := range i.tsdbs {
+		if ts := db.lastHeadSnapshot.Load(); ts > 0 && (oldest == 0 || ts < oldest) {
+			oldest = ts
+		}
+	}
+
+	return float64(oldest)
+}
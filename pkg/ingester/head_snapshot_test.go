@@ -0,0 +1,339 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestIngester_HeadSnapshot(t *testing.T) {
+	const userID = "user-1"
+
+	dataDir := t.TempDir()
+	bucketDir := t.TempDir()
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.IngesterRing.ReplicationFactor = 1
+	cfg.BlocksStorageConfig.TSDB.HeadSnapshotInterval = time.Hour
+
+	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	udir := filepath.Join(dataDir, userID)
+	ctx := user.InjectOrgID(context.Background(), userID)
+	now := time.Now().UnixMilli()
+	series := labels.FromStrings(labels.MetricName, "test", "pod", "a")
+
+	startIngester := func() (*Ingester, *prometheus.Registry) {
+		reg := prometheus.NewPedanticRegistry()
+		ing, err := prepareIngesterWithBlockStorageAndOverrides(t, cfg, overrides, nil, dataDir, bucketDir, reg)
+		require.NoError(t, err)
+		require.NoError(t, services.StartAndAwaitRunning(context.Background(), ing))
+
+		test.Poll(t, time.Second, 1, func() interface{} {
+			return ing.lifecycler.HealthyInstancesCount()
+		})
+		return ing, reg
+	}
+
+	push := func(ing *Ingester, value float64) {
+		req, _, _, _ := mockWriteRequest(t, series, value, now+int64(value))
+		_, err := ing.Push(ctx, req)
+		require.NoError(t, err)
+	}
+
+	// snapshotAndCrash writes a periodic head snapshot, pushes the input value which is only written to
+	// the WAL, and stops the ingester like a crash would: the snapshot written on shutdown is replaced
+	// by the periodic one.
+	snapshotAndCrash := func(ing *Ingester, value float64) {
+		require.NoError(t, ing.snapshotHeads(context.Background()))
+		require.NotZero(t, ing.getOldestHeadSnapshotMetric())
+		snapshot, _, _, err := tsdb.LastChunkSnapshot(udir)
+		require.NoError(t, err)
+
+		backup := filepath.Join(t.TempDir(), filepath.Base(snapshot))
+		copyDir(t, snapshot, backup)
+
+		push(ing, value)
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), ing))
+
+		shutdownSnapshot, _, _, err := tsdb.LastChunkSnapshot(udir)
+		require.NoError(t, err)
+		require.NotEqual(t, snapshot, shutdownSnapshot)
+		require.NoError(t, os.RemoveAll(shutdownSnapshot))
+		copyDir(t, backup, snapshot)
+	}
+
+	restoredValues := func(ing *Ingester, reg *prometheus.Registry) []float64 {
+		require.Equal(t, uint64(1), headSnapshotRestores(t, reg))
+		return headSnapshotTestValues(t, ing, userID)
+	}
+
+	ing, reg := startIngester()
+	push(ing, 1)
+	snapshotAndCrash(ing, 2)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_ingester_tsdb_head_snapshots_total Total number of TSDB head snapshots written on disk.
+		# TYPE cortex_ingester_tsdb_head_snapshots_total counter
+		cortex_ingester_tsdb_head_snapshots_total 1
+
+		# HELP cortex_ingester_tsdb_head_snapshots_failed_total Total number of TSDB head snapshots that failed.
+		# TYPE cortex_ingester_tsdb_head_snapshots_failed_total counter
+		cortex_ingester_tsdb_head_snapshots_failed_total 0
+	`), "cortex_ingester_tsdb_head_snapshots_total", "cortex_ingester_tsdb_head_snapshots_failed_total"))
+
+	// The samples are restored from the snapshot and the WAL written after it.
+	ing, reg = startIngester()
+	require.Equal(t, []float64{1, 2}, restoredValues(ing, reg))
+
+	// The samples written between the restarts survive a second restart.
+	push(ing, 3)
+	snapshotAndCrash(ing, 4)
+
+	ing, reg = startIngester()
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), ing)
+	})
+	require.Equal(t, []float64{1, 2, 3, 4}, restoredValues(ing, reg))
+}
+
+func TestIngester_HeadSnapshotUpload(t *testing.T) {
+	const userID = "user-1"
+
+	dataDir := t.TempDir()
+	bucketDir := t.TempDir()
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.IngesterRing.ReplicationFactor = 1
+	cfg.BlocksStorageConfig.TSDB.HeadSnapshotInterval = time.Hour
+	cfg.BlocksStorageConfig.TSDB.HeadSnapshotUploadEnabled = true
+
+	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	now := time.Now().UnixMilli()
+	series := labels.FromStrings(labels.MetricName, "test", "pod", "a")
+	uploadsDir := filepath.Join(bucketDir, userID, headSnapshotsDirname, cfg.IngesterRing.InstanceID)
+
+	startIngester := func() (*Ingester, *prometheus.Registry) {
+		reg := prometheus.NewPedanticRegistry()
+		ing, err := prepareIngesterWithBlockStorageAndOverrides(t, cfg, overrides, nil, dataDir, bucketDir, reg)
+		require.NoError(t, err)
+		require.NoError(t, services.StartAndAwaitRunning(context.Background(), ing))
+
+		test.Poll(t, time.Second, 1, func() interface{} {
+			return ing.lifecycler.HealthyInstancesCount()
+		})
+		return ing, reg
+	}
+
+	push := func(ing *Ingester, value float64) {
+		req, _, _, _ := mockWriteRequest(t, series, value, now+int64(value))
+		_, err := ing.Push(ctx, req)
+		require.NoError(t, err)
+	}
+
+	uploadedSnapshots := func() []string {
+		entries, err := os.ReadDir(uploadsDir)
+		require.NoError(t, err)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		return names
+	}
+
+	ing, reg := startIngester()
+	push(ing, 1)
+	require.NoError(t, ing.snapshotHeads(context.Background()))
+	first, _, _, err := tsdb.LastChunkSnapshot(filepath.Join(dataDir, userID))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Base(first)}, uploadedSnapshots())
+
+	// The sample written after the snapshot and before the upload is uploaded with the WAL.
+	push(ing, 2)
+	stats, err := ing.getTSDB(userID).Head().ChunkSnapshot()
+	require.NoError(t, err)
+	push(ing, 3)
+	require.NoError(t, ing.uploadHeadSnapshot(context.Background(), userID, stats.Dir))
+
+	// The new snapshot replaces the previously uploaded one, and is uploaded with the head chunks and the WAL.
+	name := filepath.Base(stats.Dir)
+	require.Equal(t, []string{name}, uploadedSnapshots())
+	for _, file := range []string{headSnapshotUploadCompleteFilename, filepath.Join(name, "00000000"), filepath.Join(walDirname, "00000000")} {
+		require.FileExists(t, filepath.Join(uploadsDir, name, file))
+	}
+	require.NoDirExists(t, filepath.Join(dataDir, userID, headSnapshotUploadDirname))
+	push(ing, 4)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_ingester_tsdb_head_snapshot_uploads_failed_total Total number of TSDB head snapshots that failed to be uploaded to the storage.
+		# TYPE cortex_ingester_tsdb_head_snapshot_uploads_failed_total counter
+		cortex_ingester_tsdb_head_snapshot_uploads_failed_total 0
+	`), "cortex_ingester_tsdb_head_snapshot_uploads_failed_total"))
+
+	// The ingester loses its disk: the TSDB is restored from the uploaded snapshot, head chunks and WAL.
+	// The sample written after the upload is lost.
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), ing))
+	require.NoError(t, os.RemoveAll(dataDir))
+
+	ing, reg = startIngester()
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), ing)
+	})
+	require.Equal(t, uint64(1), headSnapshotRestores(t, reg))
+	require.Equal(t, []float64{1, 2, 3}, headSnapshotTestValues(t, ing, userID))
+	require.NoDirExists(t, filepath.Join(dataDir, userID, headSnapshotDownloadDirname))
+}
+
+func TestIngester_HeadSnapshotShouldNotBlockCompaction(t *testing.T) {
+	const userID = "user-1"
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.IngesterRing.ReplicationFactor = 1
+	cfg.BlocksStorageConfig.TSDB.HeadSnapshotInterval = time.Hour
+
+	ing, err := prepareIngesterWithBlocksStorage(t, cfg, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), ing))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), ing)
+	})
+	test.Poll(t, time.Second, 1, func() interface{} {
+		return ing.lifecycler.HealthyInstancesCount()
+	})
+
+	req, _, _, _ := mockWriteRequest(t, labels.FromStrings(labels.MetricName, "test"), 1, time.Now().UnixMilli())
+	_, err = ing.Push(user.InjectOrgID(context.Background(), userID), req)
+	require.NoError(t, err)
+
+	// Simulate a head snapshot in progress.
+	db := ing.getTSDB(userID)
+	_, ok := db.acquireHeadSnapshotLock()
+	require.True(t, ok)
+
+	ing.compactBlocks(context.Background(), true, math.MaxInt64, nil)
+	require.Equal(t, uint64(0), db.Head().NumSeries())
+	require.Len(t, db.Blocks(), 1)
+
+	// The TSDB can't be closed until the head snapshot is done.
+	ok, _ = db.changeState(active, closing)
+	require.True(t, ok)
+	_, ok = db.acquireHeadSnapshotLock()
+	require.False(t, ok)
+	db.changeState(closing, active)
+
+	db.releaseHeadSnapshotLock()
+}
+
+func TestCopyCompleteHeadChunks(t *testing.T) {
+	dir := t.TempDir()
+
+	cdm, err := chunks.NewChunkDiskMapper(nil, dir, chunkenc.NewPool(), chunks.DefaultWriteBufferSize, chunks.DefaultWriteQueueSize)
+	require.NoError(t, err)
+	size := 0
+	for i := 0; i < 3; i++ {
+		chk := chunkenc.NewXORChunk()
+		app, err := chk.Appender()
+		require.NoError(t, err)
+		app.Append(int64(i), float64(i))
+		ref := cdm.WriteChunk(chunks.HeadSeriesRef(i), int64(i), int64(i), chk, false, func(err error) {
+			require.NoError(t, err)
+		})
+
+		_, offset := ref.Unpack()
+		size = offset + chunks.SeriesRefSize + 2*chunks.MintMaxtSize + chunks.ChunkEncodingSize + len(binary.AppendUvarint(nil, uint64(len(chk.Bytes())))) + len(chk.Bytes()) + chunks.CRCSize
+	}
+	require.NoError(t, cdm.Close())
+
+	// The head chunks file is preallocated, so it may be followed by zeros even once closed.
+	src := filepath.Join(dir, "000001")
+	complete, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(complete), size)
+	complete = complete[:size]
+
+	for name, tail := range map[string][]byte{
+		"no tail":                 nil,
+		"partially written chunk": complete[chunks.HeadChunkFileHeaderSize : chunks.HeadChunkFileHeaderSize+20],
+		"zeros":                   make([]byte, 1024),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(src, append(slices.Clone(complete), tail...), 0o640))
+
+			dst := filepath.Join(t.TempDir(), "000001")
+			require.NoError(t, copyCompleteHeadChunks(src, dst))
+
+			copied, err := os.ReadFile(dst)
+			require.NoError(t, err)
+			require.Equal(t, complete, copied)
+		})
+	}
+}
+
+// headSnapshotRestores returns the number of TSDBs restored from a head snapshot tracked in the registry.
+func headSnapshotRestores(t *testing.T, reg prometheus.Gatherer) uint64 {
+	metrics, err := reg.Gather()
+	require.NoError(t, err)
+	for _, mf := range metrics {
+		if mf.GetName() == "cortex_ingester_tsdb_head_snapshot_restore_duration_seconds" {
+			return mf.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+// headSnapshotTestValues returns the values of the only series in the TSDB of the tenant.
+func headSnapshotTestValues(t *testing.T, ing *Ingester, userID string) []float64 {
+	q, err := ing.getTSDB(userID).Querier(math.MinInt64, math.MaxInt64)
+	require.NoError(t, err)
+	defer q.Close()
+
+	set := q.Select(context.Background(), false, nil, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "test"))
+	require.True(t, set.Next())
+	var values []float64
+	it := set.At().Iterator(nil)
+	for it.Next() != 0 {
+		_, v := it.At()
+		values = append(values, v)
+	}
+	require.NoError(t, it.Err())
+	require.False(t, set.Next())
+	return values
+}
+
+// copyDir copies the files of the src directory into the dst directory.
+func copyDir(t *testing.T, src, dst string) {
+	require.NoError(t, os.MkdirAll(dst, 0o750))
+
+	entries, err := os.ReadDir(src)
+	require.NoError(t, err)
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(src, e.Name()))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dst, e.Name()), data, 0o640))
+	}
+}
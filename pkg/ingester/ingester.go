@@ -99,14 +99,17 @@ const (
 	instanceIngestionRateTickInterval = time.Second
 
 	// Reasons for discarding samples
-	reasonSampleOutOfOrder       = "sample-out-of-order"
-	reasonSampleTooOld           = "sample-too-old"
-	reasonSampleTooFarInFuture   = "sample-too-far-in-future"
-	reasonNewValueForTimestamp   = "new-value-for-timestamp"
-	reasonSampleOutOfBounds      = "sample-out-of-bounds"
-	reasonPerUserSeriesLimit     = "per_user_series_limit"
-	reasonPerMetricSeriesLimit   = "per_metric_series_limit"
-	reasonInvalidNativeHistogram = "invalid-native-histogram"
+	reasonSampleOutOfOrder         = "sample-out-of-order"
+	reasonSampleTooOld             = "sample-too-old"
+	reasonSampleTooFarInFuture     = "sample-too-far-in-future"
+	reasonNewValueForTimestamp     = "new-value-for-timestamp"
+	reasonSampleOutOfBounds        = "sample-out-of-bounds"
+	reasonPerUserSeriesLimit       = "per_user_series_limit"
+	reasonPerMetricSeriesLimit     = "per_metric_series_limit"
+	reasonPerLabelValueSeriesLimit = "per_label_value_series_limit"
+	reasonPerLabelNameValuesLimit  = "per_label_name_values_limit"
+	reasonSeriesCreationRateLimit  = "series_creation_rate_limit"
+	reasonInvalidNativeHistogram   = "invalid-native-histogram"
 
 	replicationFactorStatsName             = "ingester_replication_factor"
 	ringStoreStatsName                     = "ingester_ring_store"
@@ -132,6 +135,7 @@ var (
 	reasonIngesterMaxInMemorySeries            = globalerror.IngesterMaxInMemorySeries.LabelValue()
 	reasonIngesterMaxInflightPushRequests      = globalerror.IngesterMaxInflightPushRequests.LabelValue()
 	reasonIngesterMaxInflightPushRequestsBytes = globalerror.IngesterMaxInflightPushRequestsBytes.LabelValue()
+	reasonIngesterHeapLimit                    = globalerror.IngesterHeapLimit.LabelValue()
 )
 
 // Usage-stats expvars. Initialized as package-global in order to avoid race conditions and panics
@@ -216,6 +220,13 @@ type Config struct {
 
 	PushGrpcMethodEnabled bool `yaml:"push_grpc_method_enabled" category:"experimental" doc:"hidden"`
 
+	CardinalityHistoryInterval time.Duration `yaml:"cardinality_history_interval" category:"experimental"`
+	CardinalityHistorySize     int           `yaml:"cardinality_history_size" category:"experimental"`
+	CardinalityHistoryTopN     int           `yaml:"cardinality_history_top_n" category:"experimental"`
+
+	MemoryPressureHeapTargetBytes uint64 `yaml:"memory_pressure_heap_target_bytes" category:"experimental"`
+	MemoryPressureHeapLimitBytes  uint64 `yaml:"memory_pressure_heap_limit_bytes" category:"experimental"`
+
 	// This config is dynamically injected because defined outside the ingester config.
 	IngestStorageConfig ingest.Config `yaml:"-"`
 
@@ -246,6 +257,11 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	f.BoolVar(&cfg.UpdateIngesterOwnedSeries, "ingester.track-ingester-owned-series", false, "This option enables tracking of ingester-owned series based on ring state, even if -ingester.use-ingester-owned-series-for-limits is disabled.")
 	f.DurationVar(&cfg.OwnedSeriesUpdateInterval, "ingester.owned-series-update-interval", 15*time.Second, "How often to check for ring changes and possibly recompute owned series as a result of detected change.")
 	f.BoolVar(&cfg.PushGrpcMethodEnabled, "ingester.push-grpc-method-enabled", true, "Enables Push gRPC method on ingester. Can be only disabled when using ingest-storage to make sure ingesters only receive data from Kafka.")
+	f.DurationVar(&cfg.CardinalityHistoryInterval, "ingester.cardinality-history-interval", 0, "How frequently the ingester records the top metric names and label name-value pairs by number of in-memory series of each tenant, returned by the cardinality history API. 0 to disable.")
+	f.IntVar(&cfg.CardinalityHistorySize, "ingester.cardinality-history-size", 288, "Maximum number of cardinality samples kept in memory for each tenant. When the limit is reached, the oldest sample is discarded.")
+	f.IntVar(&cfg.CardinalityHistoryTopN, "ingester.cardinality-history-top-n", 20, "Number of top metric names and top label name-value pairs recorded in each cardinality sample.")
+	f.Uint64Var(&cfg.MemoryPressureHeapTargetBytes, "ingester.memory-pressure-heap-target-bytes", 0, "Go heap in use, in bytes, above which the ingester compacts early the oldest TSDB head block range of the tenants with the biggest heads, to reduce the memory usage. After an early compaction, the ingester doesn't accept samples older than the compacted block range. The Go heap in use is checked every second. 0 to disable.")
+	f.Uint64Var(&cfg.MemoryPressureHeapLimitBytes, memoryPressureHeapLimitFlag, 0, "Go heap in use, in bytes, above which the ingester rejects write requests with a retryable error. The Go heap in use is checked every second. 0 to disable.")
 
 	// The ingester.return-only-grpc-errors flag has been deprecated.
 	// According to the migration plan (https://github.com/grafana/mimir/issues/6008#issuecomment-1854320098)
@@ -266,6 +282,17 @@ func (cfg *Config) Validate(logger log.Logger) error {
 		util.WarnDeprecatedConfig(deprecatedReturnOnlyGRPCErrorsFlag, logger)
 	}
 
+	if cfg.CardinalityHistoryInterval < 0 {
+		return fmt.Errorf("cardinality history interval cannot be a negative number")
+	}
+	if cfg.CardinalityHistoryInterval > 0 && (cfg.CardinalityHistorySize <= 0 || cfg.CardinalityHistoryTopN <= 0) {
+		return fmt.Errorf("cardinality history size and top N must be positive numbers when the cardinality history is enabled")
+	}
+
+	if cfg.MemoryPressureHeapTargetBytes > 0 && cfg.MemoryPressureHeapLimitBytes > 0 && cfg.MemoryPressureHeapLimitBytes < cfg.MemoryPressureHeapTargetBytes {
+		return fmt.Errorf("memory pressure heap limit must be greater than or equal to the heap target")
+	}
+
 	return cfg.IngesterRing.Validate()
 }
 
@@ -328,8 +355,9 @@ type Ingester struct {
 
 	tsdbMetrics *tsdbMetrics
 
-	forceCompactTrigger chan requestWithUsersAndCallback
-	shipTrigger         chan requestWithUsersAndCallback
+	forceCompactTrigger          chan requestWithUsersAndCallback
+	shipTrigger                  chan requestWithUsersAndCallback
+	memoryPressureCompactTrigger chan struct{}
 
 	// Maps the per-block series ID with its labels hash.
 	seriesHashCache *hashcache.SeriesHashCache
@@ -340,6 +368,13 @@ type Ingester struct {
 	// Number of series in memory, across all tenants.
 	seriesCount atomic.Int64
 
+	// Go heap in use, sampled when the memory pressure mode is enabled.
+	readHeapStats func() (heapInUse uint64, numGC uint64)
+	heapInUse     atomic.Uint64
+
+	// Number of completed GC cycles when the last memory pressure compaction finished.
+	memoryPressureCompactionNumGC atomic.Uint64
+
 	// For storing metadata ingested.
 	usersMetadataMtx sync.RWMutex
 	usersMetadata    map[string]*userMetricsMetadata
@@ -389,6 +424,9 @@ func newIngester(cfg Config, limits *validation.Overrides, registerer prometheus
 		shipTrigger:         make(chan requestWithUsersAndCallback),
 		seriesHashCache:     hashcache.NewSeriesHashCache(cfg.BlocksStorageConfig.TSDB.SeriesHashCacheMaxBytes),
 
+		memoryPressureCompactTrigger: make(chan struct{}, 1),
+		readHeapStats:                readHeapStats,
+
 		errorSamplers: newIngesterErrSamplers(cfg.ErrorSampleRate),
 	}, nil
 }
@@ -412,6 +450,13 @@ func New(cfg Config, limits *validation.Overrides, ingestersRing ring.ReadRing,
 			Help: "Unix timestamp of the oldest TSDB block not shipped to the storage yet. 0 if ingester has no blocks or all blocks have been shipped.",
 		}, i.getOldestUnshippedBlockMetric)
 
+		if cfg.BlocksStorageConfig.TSDB.IsHeadSnapshotEnabled() {
+			promauto.With(registerer).NewGaugeFunc(prometheus.GaugeOpts{
+				Name: "cortex_ingester_tsdb_oldest_head_snapshot_timestamp_seconds",
+				Help: "Unix timestamp of the oldest last successful TSDB head snapshot across the tenants snapshotted at least once. 0 if no tenant has been snapshotted yet.",
+			}, i.getOldestHeadSnapshotMetric)
+		}
+
 		promauto.With(registerer).NewGaugeFunc(prometheus.GaugeOpts{
 			Name: "cortex_ingester_tsdb_head_min_timestamp_seconds",
 			Help: "Minimum timestamp of the head block across all tenants.",
@@ -619,6 +664,16 @@ func (i *Ingester) starting(ctx context.Context) (err error) {
 		servs = append(servs, shippingService)
 	}
 
+	if i.cfg.BlocksStorageConfig.TSDB.IsHeadSnapshotEnabled() {
+		headSnapshotService := services.NewTimerService(util.DurationWithJitter(i.cfg.BlocksStorageConfig.TSDB.HeadSnapshotInterval, 0.05), nil, i.snapshotHeads, nil)
+		servs = append(servs, headSnapshotService)
+	}
+
+	if i.cfg.CardinalityHistoryInterval > 0 {
+		cardinalityHistoryService := services.NewTimerService(i.cfg.CardinalityHistoryInterval, nil, i.recordCardinalityHistory, nil)
+		servs = append(servs, cardinalityHistoryService)
+	}
+
 	if i.cfg.BlocksStorageConfig.TSDB.CloseIdleTSDBTimeout > 0 {
 		interval := i.cfg.BlocksStorageConfig.TSDB.CloseIdleTSDBInterval
 		if interval == 0 {
@@ -749,6 +804,13 @@ func (i *Ingester) metricsUpdaterServiceRunning(ctx context.Context) error {
 		defer t.Stop()
 	}
 
+	var memoryPressureTickerChan <-chan time.Time
+	if i.cfg.MemoryPressureHeapTargetBytes > 0 || i.cfg.MemoryPressureHeapLimitBytes > 0 {
+		t := time.NewTicker(memoryPressureCheckInterval)
+		memoryPressureTickerChan = t.C
+		defer t.Stop()
+	}
+
 	usageStatsUpdateTicker := time.NewTicker(usageStatsUpdateInterval)
 	defer usageStatsUpdateTicker.Stop()
 
@@ -764,10 +826,13 @@ func (i *Ingester) metricsUpdaterServiceRunning(ctx context.Context) error {
 			for _, db := range i.tsdbs {
 				db.ingestedAPISamples.Tick()
 				db.ingestedRuleSamples.Tick()
+				db.createdSeries.Tick()
 			}
 			i.tsdbsMtx.RUnlock()
 		case <-activeSeriesTickerChan:
 			i.updateActiveSeries(time.Now())
+		case <-memoryPressureTickerChan:
+			i.updateMemoryPressure()
 		case <-usageStatsUpdateTicker.C:
 			i.updateUsageStats()
 		case <-limitMetricsUpdateTicker.C:
@@ -783,6 +848,39 @@ func (i *Ingester) replaceMatchers(asm *activeseries.Matchers, userDB *userTSDB,
 	userDB.activeSeries.ReloadMatchers(asm, now)
 }
 
+// newCostAttributionTracker returns the cost attribution tracker for the user's active series, or nil if cost attribution is disabled.
+func (i *Ingester) newCostAttributionTracker(userID string) *util.CostAttributionTracker {
+	return util.NewCostAttributionTracker(i.limits.CostAttributionLabel(userID), i.limits.MaxCostAttributionCardinalityPerUser(userID))
+}
+
+func (i *Ingester) replaceCostAttribution(ca *util.CostAttributionTracker, userDB *userTSDB, now time.Time) {
+	i.metrics.deletePerUserCustomTrackerMetrics(userDB.userID, userDB.activeSeries.CurrentMatcherNames())
+	userDB.activeSeriesAttributions = nil
+	userDB.activeSeries.ReloadCostAttribution(ca, now)
+}
+
+// updateCostAttributionMetrics updates the active series metrics by cost attribution,
+// removing the ones of the attributions which are no longer active.
+func (i *Ingester) updateCostAttributionMetrics(userDB *userTSDB) {
+	active := userDB.activeSeries.ActiveByCostAttribution()
+	for attribution := range userDB.activeSeriesAttributions {
+		if active[attribution] == 0 {
+			i.metrics.activeSeriesPerUserCostAttribution.DeleteLabelValues(userDB.userID, attribution)
+			delete(userDB.activeSeriesAttributions, attribution)
+		}
+	}
+	for attribution, count := range active {
+		if count == 0 {
+			continue
+		}
+		if userDB.activeSeriesAttributions == nil {
+			userDB.activeSeriesAttributions = map[string]struct{}{}
+		}
+		userDB.activeSeriesAttributions[attribution] = struct{}{}
+		i.metrics.activeSeriesPerUserCostAttribution.WithLabelValues(userDB.userID, attribution).Set(float64(count))
+	}
+}
+
 func (i *Ingester) updateActiveSeries(now time.Time) {
 	for _, userID := range i.getTSDBUsers() {
 		userDB := i.getTSDB(userID)
@@ -794,6 +892,10 @@ func (i *Ingester) updateActiveSeries(now time.Time) {
 		if newMatchersConfig.String() != userDB.activeSeries.CurrentConfig().String() {
 			i.replaceMatchers(activeseries.NewMatchers(newMatchersConfig), userDB, now)
 		}
+		current := userDB.activeSeries.CurrentCostAttribution()
+		if i.limits.CostAttributionLabel(userID) != current.Label() || (current != nil && i.limits.MaxCostAttributionCardinalityPerUser(userID) != current.MaxValues()) {
+			i.replaceCostAttribution(i.newCostAttributionTracker(userID), userDB, now)
+		}
 		valid := userDB.activeSeries.Purge(now)
 		if !valid {
 			// Active series config has been reloaded, exposing loading metric until MetricsIdleTimeout passes.
@@ -835,6 +937,8 @@ func (i *Ingester) updateActiveSeries(now time.Time) {
 					i.metrics.activeNativeHistogramBucketsCustomTrackersPerUser.DeleteLabelValues(userID, name)
 				}
 			}
+
+			i.updateCostAttributionMetrics(userDB)
 		}
 	}
 }
@@ -892,6 +996,7 @@ func (i *Ingester) updateUsageStats() {
 // applyTSDBSettings goes through all tenants and applies
 // * The current max-exemplars setting. If it changed, tsdb will resize the buffer; if it didn't change tsdb will return quickly.
 // * The current out-of-order time window. If it changes from 0 to >0, then a new Write-Behind-Log gets created for that tenant.
+// * The label names limited by the per-label-value series limit and the per-label-name values limit.
 func (i *Ingester) applyTSDBSettings() {
 	for _, userID := range i.getTSDBUsers() {
 		oooTW := i.limits.OutOfOrderTimeWindow(userID)
@@ -926,6 +1031,9 @@ func (i *Ingester) applyTSDBSettings() {
 		} else {
 			db.db.DisableNativeHistograms()
 		}
+		if err := db.updateLimitedLabelNames(context.Background()); err != nil {
+			level.Error(i.logger).Log("msg", "failed to update label names limited by per-label limits", "user", userID, "err", err)
+		}
 	}
 }
 
@@ -958,18 +1066,21 @@ type extendedAppender interface {
 }
 
 type pushStats struct {
-	succeededSamplesCount       int
-	failedSamplesCount          int
-	succeededExemplarsCount     int
-	failedExemplarsCount        int
-	sampleOutOfBoundsCount      int
-	sampleOutOfOrderCount       int
-	sampleTooOldCount           int
-	sampleTooFarInFutureCount   int
-	newValueForTimestampCount   int
-	perUserSeriesLimitCount     int
-	perMetricSeriesLimitCount   int
-	invalidNativeHistogramCount int
+	succeededSamplesCount         int
+	failedSamplesCount            int
+	succeededExemplarsCount       int
+	failedExemplarsCount          int
+	sampleOutOfBoundsCount        int
+	sampleOutOfOrderCount         int
+	sampleTooOldCount             int
+	sampleTooFarInFutureCount     int
+	newValueForTimestampCount     int
+	perUserSeriesLimitCount       int
+	perMetricSeriesLimitCount     int
+	perLabelValueSeriesLimitCount int
+	perLabelNameValuesLimitCount  int
+	seriesCreationRateLimitCount  int
+	invalidNativeHistogramCount   int
 }
 
 type ctxKey int
@@ -1067,6 +1178,11 @@ func (i *Ingester) startPushRequest(ctx context.Context, reqSize int64) (context
 }
 
 func (i *Ingester) checkInstanceLimits(inflight int64, inflightBytes int64, rejectEqualInflightBytes bool) error {
+	if limit := i.cfg.MemoryPressureHeapLimitBytes; limit > 0 && i.heapInUse.Load() >= limit {
+		i.metrics.rejected.WithLabelValues(reasonIngesterHeapLimit).Inc()
+		return errHeapLimitReached
+	}
+
 	il := i.getInstanceLimits()
 	if il == nil {
 		return nil
@@ -1273,6 +1389,15 @@ func (i *Ingester) updateMetricsFromPushStats(userID string, group string, stats
 	if stats.perMetricSeriesLimitCount > 0 {
 		discarded.perMetricSeriesLimit.WithLabelValues(userID, group).Add(float64(stats.perMetricSeriesLimitCount))
 	}
+	if stats.perLabelValueSeriesLimitCount > 0 {
+		discarded.perLabelValueSeriesLimit.WithLabelValues(userID, group).Add(float64(stats.perLabelValueSeriesLimitCount))
+	}
+	if stats.perLabelNameValuesLimitCount > 0 {
+		discarded.perLabelNameValuesLimit.WithLabelValues(userID, group).Add(float64(stats.perLabelNameValuesLimitCount))
+	}
+	if stats.seriesCreationRateLimitCount > 0 {
+		discarded.seriesCreationRateLimit.WithLabelValues(userID, group).Add(float64(stats.seriesCreationRateLimitCount))
+	}
 	if stats.invalidNativeHistogramCount > 0 {
 		discarded.invalidNativeHistogram.WithLabelValues(userID, group).Add(float64(stats.invalidNativeHistogramCount))
 	}
@@ -1352,6 +1477,30 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 			})
 			return true
 
+		case errors.Is(err, globalerror.MaxSeriesPerLabelValue):
+			stats.perLabelValueSeriesLimitCount++
+			updateFirstPartial(i.errorSamplers.maxSeriesPerLabelValueLimitExceeded, func() softError {
+				labelName := labelLimitErrorLabelName(err)
+				return newPerLabelValueSeriesLimitReachedError(i.limiter.limits.MaxGlobalSeriesPerLabelValue(userID)[labelName], labelName, labels)
+			})
+			return true
+
+		case errors.Is(err, globalerror.MaxLabelValuesPerLabelName):
+			stats.perLabelNameValuesLimitCount++
+			updateFirstPartial(i.errorSamplers.maxLabelValuesPerLabelNameLimitExceeded, func() softError {
+				labelName := labelLimitErrorLabelName(err)
+				return newPerLabelNameValuesLimitReachedError(i.limiter.limits.MaxLabelValuesPerLabelName(userID)[labelName], labelName, labels)
+			})
+			return true
+
+		case errors.Is(err, globalerror.MaxSeriesCreationRate):
+			stats.seriesCreationRateLimitCount++
+			updateFirstPartial(i.errorSamplers.seriesCreationRateLimitExceeded, func() softError {
+				limit, burst := i.limiter.seriesCreationRateLimit(userID)
+				return newSeriesCreationRateLimitedError(limit, burst, labels)
+			})
+			return true
+
 		// Map TSDB native histogram validation errors to soft errors.
 		case errors.Is(err, histogram.ErrHistogramCountMismatch):
 			stats.invalidNativeHistogramCount++
@@ -2038,6 +2187,33 @@ func (i *Ingester) LabelValuesCardinality(req *client.LabelValuesCardinalityRequ
 	)
 }
 
+// CardinalityHistory returns the cardinality samples recorded for the tenant.
+//
+// When using the experimental ingest storage, this function doesn't support the read consistency setting
+// because it returns the samples recorded in the past.
+func (i *Ingester) CardinalityHistory(ctx context.Context, req *client.CardinalityHistoryRequest) (resp *client.CardinalityHistoryResponse, err error) {
+	defer func() { err = i.mapReadErrorToErrorWithStatus(err) }()
+	finishReadRequest, err := i.startReadRequest()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { finishReadRequest(err) }()
+
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := i.getTSDB(userID)
+	if db == nil || db.cardinalityHistory == nil {
+		return &client.CardinalityHistoryResponse{}, nil
+	}
+
+	return &client.CardinalityHistoryResponse{
+		Samples: db.cardinalityHistory.samplesBetween(req.GetStartTimestampMs(), req.GetEndTimestampMs()),
+	}, nil
+}
+
 func createUserStats(db *userTSDB, req *client.UserStatsRequest) (*client.UserStatsResponse, error) {
 	apiRate := db.ingestedAPISamples.Rate()
 	ruleRate := db.ingestedRuleSamples.Rate()
@@ -2054,10 +2230,11 @@ func createUserStats(db *userTSDB, req *client.UserStatsRequest) (*client.UserSt
 	}
 
 	return &client.UserStatsResponse{
-		IngestionRate:     apiRate + ruleRate,
-		ApiIngestionRate:  apiRate,
-		RuleIngestionRate: ruleRate,
-		NumSeries:         series,
+		IngestionRate:      apiRate + ruleRate,
+		ApiIngestionRate:   apiRate,
+		RuleIngestionRate:  ruleRate,
+		NumSeries:          series,
+		SeriesCreationRate: db.createdSeries.Rate(),
 	}, nil
 }
 
@@ -2631,8 +2808,10 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 
 	// flusher doesn't actually start the ingester services
 	initialLocalLimit := 0
+	var limitedLabelNames []string
 	if i.limiter != nil {
 		initialLocalLimit = i.limiter.maxSeriesPerUser(userID, 0)
+		limitedLabelNames = i.limiter.limitedLabelNames(userID)
 	}
 	ownedSeriedStateShardSize := 0
 	if i.ownedSeriesService != nil {
@@ -2641,10 +2820,12 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 
 	userDB := &userTSDB{
 		userID:                  userID,
-		activeSeries:            activeseries.NewActiveSeries(activeseries.NewMatchers(matchersConfig), i.cfg.ActiveSeriesMetrics.IdleTimeout),
+		activeSeries:            activeseries.NewActiveSeries(activeseries.NewMatchers(matchersConfig), i.newCostAttributionTracker(userID), i.cfg.ActiveSeriesMetrics.IdleTimeout),
 		seriesInMetric:          newMetricCounter(i.limiter, i.cfg.getIgnoreSeriesLimitForMetricNamesMap()),
+		labelValues:             newLabelValueCounter(i.limiter, limitedLabelNames),
 		ingestedAPISamples:      util_math.NewEWMARate(0.2, i.cfg.RateUpdatePeriod),
 		ingestedRuleSamples:     util_math.NewEWMARate(0.2, i.cfg.RateUpdatePeriod),
+		createdSeries:           util_math.NewEWMARate(0.2, i.cfg.RateUpdatePeriod),
 		instanceLimitsFn:        i.getInstanceLimits,
 		instanceSeriesCount:     &i.seriesCount,
 		instanceErrors:          i.metrics.rejected,
@@ -2656,6 +2837,9 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 			localSeriesLimit: initialLocalLimit,
 		},
 	}
+	if i.cfg.CardinalityHistoryInterval > 0 {
+		userDB.cardinalityHistory = newCardinalityHistory(i.cfg.CardinalityHistorySize)
+	}
 	userDB.triggerRecomputeOwnedSeries(recomputeOwnedSeriesReasonNewUser)
 
 	oooTW := i.limits.OutOfOrderTimeWindow(userID)
@@ -2676,7 +2860,7 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 		EnableExemplarStorage:                 true, // enable for everyone so we can raise the limit later
 		MaxExemplars:                          int64(i.limiter.maxExemplarsPerUser(userID)),
 		SeriesHashCache:                       i.seriesHashCache,
-		EnableMemorySnapshotOnShutdown:        i.cfg.BlocksStorageConfig.TSDB.MemorySnapshotOnShutdown,
+		EnableMemorySnapshotOnShutdown:        i.cfg.BlocksStorageConfig.TSDB.MemorySnapshotOnShutdown || i.cfg.BlocksStorageConfig.TSDB.IsHeadSnapshotEnabled(), // Snapshots are only restored when enabled.
 		IsolationDisabled:                     true,
 		HeadChunksWriteQueueSize:              i.cfg.BlocksStorageConfig.TSDB.HeadChunksWriteQueueSize,
 		EnableOverlappingCompaction:           false,                // always false since Mimir only uploads lvl 1 compacted blocks
@@ -2804,6 +2988,15 @@ func (i *Ingester) openExistingTSDB(ctx context.Context) error {
 		return err
 	}
 
+	if i.cfg.BlocksStorageConfig.TSDB.HeadSnapshotUploadEnabled {
+		uploadedUserIDs, err := i.findUserIDsWithUploadedHeadSnapshot(ctx, userIDs)
+		if err != nil {
+			// The TSDBs on the filesystem can be opened anyway.
+			level.Warn(i.logger).Log("msg", "error while finding TSDB head snapshots uploaded to the storage", "err", err)
+		}
+		userIDs = append(userIDs, uploadedUserIDs...)
+	}
+
 	if len(userIDs) == 0 {
 		return nil
 	}
@@ -2814,11 +3007,20 @@ func (i *Ingester) openExistingTSDB(ctx context.Context) error {
 	for n := 0; n < tsdbOpenConcurrency; n++ {
 		group.Go(func() error {
 			for userID := range queue {
+				hasHeadSnapshot := false
+				if i.cfg.BlocksStorageConfig.TSDB.IsHeadSnapshotEnabled() {
+					hasHeadSnapshot = i.prepareHeadSnapshotRestore(groupCtx, userID)
+				}
+
+				openStart := time.Now()
 				db, err := i.createTSDB(userID, tsdbWALReplayConcurrency)
 				if err != nil {
 					level.Error(i.logger).Log("msg", "unable to open TSDB", "err", err, "user", userID)
 					return errors.Wrapf(err, "unable to open TSDB for user %s", userID)
 				}
+				if hasHeadSnapshot {
+					i.metrics.headSnapshotRestoreDuration.Observe(time.Since(openStart).Seconds())
+				}
 
 				// Add the database to the map of user databases
 				i.tsdbsMtx.Lock()
@@ -3106,6 +3308,9 @@ func (i *Ingester) compactionServiceRunning(ctx context.Context) error {
 			i.compactBlocks(ctx, true, math.MaxInt64, req.users)
 			close(req.callback) // Notify back.
 
+		case <-i.memoryPressureCompactTrigger:
+			i.compactBlocksToReduceMemoryPressure(ctx)
+
 		case <-ctx.Done():
 			return nil
 		}
@@ -3336,8 +3541,9 @@ func (i *Ingester) closeAndDeleteUserTSDBIfIdle(userID string) tsdbCloseCheckRes
 	// If TSDB is fully closed, we will set state to 'closed', which will prevent this defered closing -> active transition.
 	defer userDB.changeState(closing, active)
 
-	// Make sure we don't ignore any possible inflight pushes.
+	// Make sure we don't ignore any possible inflight pushes, and the head snapshots are done.
 	userDB.inFlightAppends.Wait()
+	userDB.inFlightHeadSnapshots.Wait()
 
 	// Verify again, things may have changed during the checks and pushes.
 	tenantDeleted := false
@@ -3389,6 +3595,13 @@ func (i *Ingester) closeAndDeleteUserTSDBIfIdle(userID string) tsdbCloseCheckRes
 		return tsdbDataRemovalFailed
 	}
 
+	// The uploaded head snapshots of the closed TSDB won't be restored anymore.
+	if i.cfg.BlocksStorageConfig.TSDB.HeadSnapshotUploadEnabled {
+		if err := i.deleteUploadedHeadSnapshots(context.Background(), userID, ""); err != nil {
+			level.Warn(i.logger).Log("msg", "failed to delete uploaded TSDB head snapshots", "user", userID, "err", err)
+		}
+	}
+
 	if tenantDeleted {
 		level.Info(i.logger).Log("msg", "deleted local TSDB, user marked for deletion", "user", userID, "dir", dir)
 		return tsdbTenantMarkedForDeletion
@@ -125,6 +125,15 @@ func (i *ActivityTrackerWrapper) LabelValuesCardinality(request *client.LabelVal
 	return i.ing.LabelValuesCardinality(request, server)
 }
 
+func (i *ActivityTrackerWrapper) CardinalityHistory(ctx context.Context, request *client.CardinalityHistoryRequest) (*client.CardinalityHistoryResponse, error) {
+	ix := i.tracker.Insert(func() string {
+		return requestActivity(ctx, "Ingester/CardinalityHistory", request)
+	})
+	defer i.tracker.Delete(ix)
+
+	return i.ing.CardinalityHistory(ctx, request)
+}
+
 func (i *ActivityTrackerWrapper) ActiveSeries(request *client.ActiveSeriesRequest, server client.Ingester_ActiveSeriesServer) error {
 	ix := i.tracker.Insert(func() string {
 		return requestActivity(server.Context(), "Ingester/ActiveSeries", request)
@@ -7961,6 +7961,7 @@ func testIngesterInflightPushRequests(t *testing.T, i *Ingester, reg prometheus.
 		cortex_ingester_inflight_push_requests 0
 		# HELP cortex_ingester_instance_rejected_requests_total Requests rejected for hitting per-instance limits
 		# TYPE cortex_ingester_instance_rejected_requests_total counter
+		cortex_ingester_instance_rejected_requests_total{reason="ingester_heap_limit"} 0
 		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_inflight_push_requests"} 1
 		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_inflight_push_requests_bytes"} 0
 		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_ingestion_rate"} 0
@@ -8083,6 +8084,7 @@ func TestIngester_inflightPushRequestsBytes(t *testing.T) {
 			require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
 		# HELP cortex_ingester_instance_rejected_requests_total Requests rejected for hitting per-instance limits
 		# TYPE cortex_ingester_instance_rejected_requests_total counter
+		cortex_ingester_instance_rejected_requests_total{reason="ingester_heap_limit"} 0
 		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_inflight_push_requests"} 0
 		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_inflight_push_requests_bytes"} 3
 		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_ingestion_rate"} 0
@@ -9046,6 +9048,57 @@ func TestIngesterActiveSeries(t *testing.T) {
 	}
 }
 
+func TestIngesterActiveSeriesCostAttribution(t *testing.T) {
+	labelsToPush := [][]mimirpb.LabelAdapter{
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "bool", Value: "false"}, {Name: "team", Value: "a"}},
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "bool", Value: "true"}, {Name: "team", Value: "a"}},
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "bool", Value: "false"}, {Name: "team", Value: "b"}},
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "bool", Value: "false"}},
+	}
+	req := func(lbls []mimirpb.LabelAdapter, t time.Time) *mimirpb.WriteRequest {
+		return mimirpb.ToWriteRequest(
+			[][]mimirpb.LabelAdapter{lbls},
+			[]mimirpb.Sample{{Value: 1, TimestampMs: t.UnixMilli()}},
+			nil,
+			nil,
+			mimirpb.API,
+		)
+	}
+	userID := "test_user"
+
+	registry := prometheus.NewRegistry()
+	cfg := defaultIngesterTestConfig(t)
+	limits := defaultLimitsTestConfig()
+	limits.CostAttributionLabel = "team"
+	limits.MaxCostAttributionCardinalityPerUser = 2
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	ing, err := prepareIngesterWithBlockStorageAndOverrides(t, cfg, overrides, nil, "", "", registry)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), ing))
+	defer services.StopAndAwaitTerminated(context.Background(), ing) //nolint:errcheck
+
+	test.Poll(t, 100*time.Millisecond, 1, func() interface{} {
+		return ing.lifecycler.HealthyInstancesCount()
+	})
+
+	pushWithUser(t, ing, labelsToPush, userID, req)
+	ing.updateActiveSeries(time.Now())
+
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+		# HELP cortex_ingester_attributed_active_series Number of currently active series per user and cost attribution.
+		# TYPE cortex_ingester_attributed_active_series gauge
+		cortex_ingester_attributed_active_series{attribution="__overflow__",user="test_user"} 1
+		cortex_ingester_attributed_active_series{attribution="a",user="test_user"} 2
+		cortex_ingester_attributed_active_series{attribution="b",user="test_user"} 1
+	`), "cortex_ingester_attributed_active_series"))
+
+	// The metrics of inactive attributions are removed.
+	ing.updateActiveSeries(time.Now().Add(cfg.ActiveSeriesMetrics.IdleTimeout + time.Second))
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(""), "cortex_ingester_attributed_active_series"))
+}
+
 func TestIngesterActiveSeriesConfigChanges(t *testing.T) {
 	labelsToPush := [][]mimirpb.LabelAdapter{
 		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "bool", Value: "false"}, {Name: "team", Value: "a"}},
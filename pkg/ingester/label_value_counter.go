@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+
+	"github.com/grafana/mimir/pkg/util/globalerror"
+)
+
+// labelLimitError is returned by labelValueCounter when a series can't be created because of a per-label limit.
+type labelLimitError struct {
+	id        globalerror.ID
+	labelName string
+}
+
+func (e labelLimitError) Error() string {
+	return e.id.Error()
+}
+
+func (e labelLimitError) Unwrap() error {
+	return e.id
+}
+
+// labelLimitErrorLabelName returns the label name whose limit has been reached, if err is a labelLimitError.
+func labelLimitErrorLabelName(err error) string {
+	var labelErr labelLimitError
+	if errors.As(err, &labelErr) {
+		return labelErr.labelName
+	}
+	return ""
+}
+
+// labelLimitStats holds the number of in-memory series and values of a label name limited by
+// the per-label-value series limit or the per-label-name values limit.
+type labelLimitStats struct {
+	Name              string
+	Values            int
+	MaxValues         int // 0 if unlimited.
+	TopValue          string
+	TopValueSeries    int
+	MaxSeriesPerValue int // 0 if unlimited.
+}
+
+// labelValueCounter counts the in-memory series by value of the label names limited by the
+// per-label-value series limit or the per-label-name values limit, and enforces these limits.
+type labelValueCounter struct {
+	limiter *Limiter
+
+	mtx    sync.Mutex
+	series map[string]map[string]int // Label name -> label value -> number of in-memory series.
+}
+
+// newLabelValueCounter creates a labelValueCounter tracking the given label names.
+func newLabelValueCounter(limiter *Limiter, names []string) *labelValueCounter {
+	c := &labelValueCounter{
+		limiter: limiter,
+		series:  make(map[string]map[string]int, len(names)),
+	}
+	for _, name := range names {
+		c.series[name] = map[string]int{}
+	}
+	return c
+}
+
+// canAddSeries returns a labelLimitError if creating a series with the given labels would exceed
+// a per-label limit.
+func (c *labelValueCounter) canAddSeries(userID string, metric labels.Labels) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for name, values := range c.series {
+		value := metric.Get(name)
+		if value == "" {
+			continue
+		}
+
+		series, ok := values[value]
+		if !c.limiter.IsWithinMaxSeriesPerLabelValue(userID, name, series) {
+			return labelLimitError{id: globalerror.MaxSeriesPerLabelValue, labelName: name}
+		}
+		if !ok && !c.limiter.IsWithinMaxLabelValuesPerLabelName(userID, name, len(values)) {
+			return labelLimitError{id: globalerror.MaxLabelValuesPerLabelName, labelName: name}
+		}
+	}
+	return nil
+}
+
+func (c *labelValueCounter) increaseSeries(metric labels.Labels) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for name, values := range c.series {
+		if value := metric.Get(name); value != "" {
+			values[value]++
+		}
+	}
+}
+
+func (c *labelValueCounter) decreaseSeries(metric labels.Labels) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for name, values := range c.series {
+		value := metric.Get(name)
+		if value == "" {
+			continue
+		}
+		// The count could be missing if the series was created while the label name started to be tracked.
+		if values[value] <= 1 {
+			delete(values, value)
+		} else {
+			values[value]--
+		}
+	}
+}
+
+// updateLimitedLabelNames starts tracking the label names which have become limited, counting their
+// in-memory series from the index, and stops tracking the label names which are no longer limited.
+// Series created while a label name starts to be tracked may not be counted.
+func (c *labelValueCounter) updateLimitedLabelNames(ctx context.Context, names []string, idx tsdb.IndexReader) error {
+	c.mtx.Lock()
+	var added []string
+	for _, name := range names {
+		if _, ok := c.series[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	for name := range c.series {
+		if !slices.Contains(names, name) {
+			delete(c.series, name)
+		}
+	}
+	c.mtx.Unlock()
+
+	for _, name := range added {
+		// The index is read without holding the lock, to not block series creation meanwhile.
+		values, err := countSeriesByLabelValue(ctx, idx, name)
+		if err != nil {
+			return err
+		}
+
+		c.mtx.Lock()
+		c.series[name] = values
+		c.mtx.Unlock()
+	}
+	return nil
+}
+
+// stats returns the number of in-memory series and values of each tracked label name, and their limits.
+func (c *labelValueCounter) stats(userID string) []labelLimitStats {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	result := make([]labelLimitStats, 0, len(c.series))
+	for name, values := range c.series {
+		s := labelLimitStats{Name: name, Values: len(values)}
+		if limit := c.limiter.maxLabelValuesPerLabelName(userID, name); limit < math.MaxInt32 {
+			s.MaxValues = limit
+		}
+		if limit := c.limiter.maxSeriesPerLabelValue(userID, name); limit < math.MaxInt32 {
+			s.MaxSeriesPerValue = limit
+		}
+		for value, series := range values {
+			if series > s.TopValueSeries || (series == s.TopValueSeries && value < s.TopValue) {
+				s.TopValue, s.TopValueSeries = value, series
+			}
+		}
+		result = append(result, s)
+	}
+	slices.SortFunc(result, func(a, b labelLimitStats) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result
+}
+
+// countSeriesByLabelValue returns the number of series in the index by value of the given label name.
+func countSeriesByLabelValue(ctx context.Context, idx tsdb.IndexReader, name string) (map[string]int, error) {
+	values, err := idx.LabelValues(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(values))
+	for _, value := range values {
+		p, err := idx.Postings(ctx, name, value)
+		if err != nil {
+			return nil, err
+		}
+		series := 0
+		for p.Next() {
+			series++
+		}
+		if err := p.Err(); err != nil {
+			return nil, err
+		}
+		if series > 0 {
+			counts[value] = series
+		}
+	}
+	return counts, nil
+}
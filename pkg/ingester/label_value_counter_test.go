@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/util/globalerror"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestLabelValueCounter(t *testing.T) {
+	limits := defaultLimitsTestConfig()
+	require.NoError(t, limits.MaxGlobalSeriesPerLabelValue.Set(`{"pod": 2}`))
+	require.NoError(t, limits.MaxLabelValuesPerLabelName.Set(`{"pod": 2, "zone": 1}`))
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	ring := &ringCountMock{instancesCount: 1, zonesCount: 1}
+	limiter := NewLimiter(overrides, newIngesterRingLimiterStrategy(ring, 1, false, "", overrides.IngestionTenantShardSize))
+	require.Equal(t, []string{"pod", "zone"}, limiter.limitedLabelNames("test"))
+
+	c := newLabelValueCounter(limiter, limiter.limitedLabelNames("test"))
+	addSeries := func(lbls ...string) error {
+		series := labels.FromStrings(lbls...)
+		if err := c.canAddSeries("test", series); err != nil {
+			return err
+		}
+		c.increaseSeries(series)
+		return nil
+	}
+
+	require.NoError(t, addSeries("__name__", "up", "pod", "a"))
+	require.NoError(t, addSeries("__name__", "down", "pod", "a", "zone", "z1"))
+	require.NoError(t, addSeries("__name__", "up", "pod", "b"))
+
+	// Series without the limited labels aren't limited.
+	require.NoError(t, addSeries("__name__", "up"))
+
+	err = addSeries("__name__", "other", "pod", "a")
+	require.ErrorIs(t, err, globalerror.MaxSeriesPerLabelValue)
+	require.Equal(t, "pod", labelLimitErrorLabelName(err))
+
+	err = addSeries("__name__", "up", "pod", "c")
+	require.ErrorIs(t, err, globalerror.MaxLabelValuesPerLabelName)
+	require.Equal(t, "pod", labelLimitErrorLabelName(err))
+
+	err = addSeries("__name__", "up", "pod", "b", "zone", "z2")
+	require.ErrorIs(t, err, globalerror.MaxLabelValuesPerLabelName)
+	require.Equal(t, "zone", labelLimitErrorLabelName(err))
+
+	require.Equal(t, []labelLimitStats{
+		{Name: "pod", Values: 2, MaxValues: 2, TopValue: "a", TopValueSeries: 2, MaxSeriesPerValue: 2},
+		{Name: "zone", Values: 1, MaxValues: 1, TopValue: "z1", TopValueSeries: 1},
+	}, c.stats("test"))
+
+	// Deleting series frees up room for new series and values.
+	c.decreaseSeries(labels.FromStrings("__name__", "down", "pod", "a", "zone", "z1"))
+	require.NoError(t, addSeries("__name__", "other", "pod", "a"))
+	require.NoError(t, addSeries("__name__", "up", "pod", "b", "zone", "z2"))
+}
+
+func TestIngester_PushLabelLimits(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.IngesterRing.ReplicationFactor = 1
+	limits := defaultLimitsTestConfig()
+	require.NoError(t, limits.MaxGlobalSeriesPerLabelValue.Set(`{"pod": 2}`))
+	require.NoError(t, limits.MaxLabelValuesPerLabelName.Set(`{"pod": 2}`))
+
+	registry := prometheus.NewRegistry()
+	i, err := prepareIngesterWithBlocksStorageAndLimits(t, cfg, limits, nil, "", registry)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	test.Poll(t, 1*time.Second, 1, func() interface{} {
+		return i.lifecycler.HealthyInstancesCount()
+	})
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	push := func(lbls ...string) error {
+		req, _, _, _ := mockWriteRequest(t, labels.FromStrings(lbls...), 1, time.Now().UnixMilli())
+		_, err := i.Push(ctx, req)
+		return err
+	}
+
+	require.NoError(t, push("__name__", "up", "pod", "a", "zone", "z1"))
+	require.NoError(t, push("__name__", "down", "pod", "a", "zone", "z2"))
+	require.NoError(t, push("__name__", "up", "pod", "b", "zone", "z1"))
+
+	err = push("__name__", "other", "pod", "a")
+	require.ErrorContains(t, err, globalerror.MaxSeriesPerLabelValue.Error())
+	require.ErrorContains(t, err, "per-label-value series limit of 2 for label name pod exceeded")
+
+	err = push("__name__", "up", "pod", "c")
+	require.ErrorContains(t, err, globalerror.MaxLabelValuesPerLabelName.Error())
+	require.ErrorContains(t, err, "per-label-name values limit of 2 for label name pod exceeded")
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+		# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+		# TYPE cortex_discarded_samples_total counter
+		cortex_discarded_samples_total{group="",reason="per_label_name_values_limit",user="1"} 1
+		cortex_discarded_samples_total{group="",reason="per_label_value_series_limit",user="1"} 1
+	`), "cortex_discarded_samples_total"))
+
+	// Limiting a new label name counts the existing series.
+	require.NoError(t, limits.MaxLabelValuesPerLabelName.Set(`{"pod": 2, "zone": 2}`))
+	i.limiter.limits, err = validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+	i.applyTSDBSettings()
+
+	require.Equal(t, []labelLimitStats{
+		{Name: "pod", Values: 2, MaxValues: 2, TopValue: "a", TopValueSeries: 2, MaxSeriesPerValue: 2},
+		{Name: "zone", Values: 2, MaxValues: 2, TopValue: "z1", TopValueSeries: 2},
+	}, i.getTSDB(userID).labelValues.stats(userID))
+	require.ErrorContains(t, push("__name__", "up", "pod", "b", "zone", "z3"), globalerror.MaxLabelValuesPerLabelName.Error())
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/tenants", nil)
+	require.NoError(t, err)
+	i.TenantsHandler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `pod: 2/2 values, top value "a" with 2/2 series`)
+}
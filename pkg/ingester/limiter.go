@@ -7,6 +7,7 @@ package ingester
 
 import (
 	"math"
+	"slices"
 
 	"github.com/grafana/dskit/ring"
 
@@ -18,6 +19,10 @@ import (
 type limiterTenantLimits interface {
 	MaxGlobalSeriesPerUser(userID string) int
 	MaxGlobalSeriesPerMetric(userID string) int
+	MaxGlobalSeriesPerLabelValue(userID string) map[string]int
+	MaxLabelValuesPerLabelName(userID string) map[string]int
+	MaxSeriesCreationRate(userID string) float64
+	MaxSeriesCreationBurstSize(userID string) int
 	MaxGlobalMetadataPerMetric(userID string) int
 	MaxGlobalMetricsWithMetadataPerUser(userID string) int
 	MaxGlobalExemplarsPerUser(userID string) int
@@ -45,6 +50,20 @@ func (l *Limiter) IsWithinMaxSeriesPerMetric(userID string, series int) bool {
 	return series < actualLimit
 }
 
+// IsWithinMaxSeriesPerLabelValue returns true if limit has not been reached compared to the current
+// number of series with a value of the given label name in input; otherwise returns false.
+func (l *Limiter) IsWithinMaxSeriesPerLabelValue(userID, labelName string, series int) bool {
+	actualLimit := l.maxSeriesPerLabelValue(userID, labelName)
+	return series < actualLimit
+}
+
+// IsWithinMaxLabelValuesPerLabelName returns true if limit has not been reached compared to the current
+// number of values of the given label name in input; otherwise returns false.
+func (l *Limiter) IsWithinMaxLabelValuesPerLabelName(userID, labelName string, values int) bool {
+	actualLimit := l.maxLabelValuesPerLabelName(userID, labelName)
+	return values < actualLimit
+}
+
 // IsWithinMaxMetadataPerMetric returns true if limit has not been reached compared to the current
 // number of metadata per metric in input; otherwise returns false.
 func (l *Limiter) IsWithinMaxMetadataPerMetric(userID string, metadata int) bool {
@@ -70,6 +89,50 @@ func (l *Limiter) maxSeriesPerMetric(userID string) int {
 	return l.convertGlobalToLocalLimitOrUnlimited(userID, l.limits.MaxGlobalSeriesPerMetric, 0)
 }
 
+// maxSeriesPerLabelValue returns the local limit on the number of series with the same value of the given label name.
+func (l *Limiter) maxSeriesPerLabelValue(userID, labelName string) int {
+	return l.convertGlobalToLocalLimitOrUnlimited(userID, func(userID string) int {
+		return l.limits.MaxGlobalSeriesPerLabelValue(userID)[labelName]
+	}, 0)
+}
+
+// maxLabelValuesPerLabelName returns the limit on the number of values of the given label name.
+// The limit isn't converted to a local one, because each value is typically found in the series of every ingester.
+func (l *Limiter) maxLabelValuesPerLabelName(userID, labelName string) int {
+	if limit := l.limits.MaxLabelValuesPerLabelName(userID)[labelName]; limit > 0 {
+		return limit
+	}
+	return math.MaxInt32
+}
+
+// limitedLabelNames returns the label names limited by either the per-label-value series limit or the per-label-name values limit.
+func (l *Limiter) limitedLabelNames(userID string) []string {
+	var names []string
+	for name, limit := range l.limits.MaxGlobalSeriesPerLabelValue(userID) {
+		if limit > 0 {
+			names = append(names, name)
+		}
+	}
+	for name, limit := range l.limits.MaxLabelValuesPerLabelName(userID) {
+		if limit > 0 && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// seriesCreationRateLimit returns the limit on the number of new series created per second, and its burst size.
+// The limit is applied by each ingester to the series it creates, and it isn't converted from a global one.
+// A limit of 0 means no limit.
+func (l *Limiter) seriesCreationRateLimit(userID string) (float64, int) {
+	limit := l.limits.MaxSeriesCreationRate(userID)
+	if limit <= 0 {
+		return 0, 0
+	}
+	return limit, max(l.limits.MaxSeriesCreationBurstSize(userID), int(math.Ceil(limit)))
+}
+
 func (l *Limiter) maxMetadataPerMetric(userID string) int {
 	return l.convertGlobalToLocalLimitOrUnlimited(userID, l.limits.MaxGlobalMetadataPerMetric, 0)
 }
@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"math"
+	"runtime/metrics"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"golang.org/x/exp/slices"
+
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/globalerror"
+)
+
+const (
+	memoryPressureHeapLimitFlag = "ingester.memory-pressure-heap-limit-bytes"
+
+	// Interval for sampling the Go heap in use when the memory pressure mode is enabled.
+	memoryPressureCheckInterval = time.Second
+)
+
+var errHeapLimitReached = newInstanceLimitReachedError(globalerror.IngesterHeapLimit.MessageWithPerInstanceLimitConfig("the write request has been rejected because the ingester exceeded the allowed Go heap in use", memoryPressureHeapLimitFlag))
+
+// heapStatsMetrics are the runtime metrics read by readHeapStats. The sum of the heap objects and unused heap
+// bytes is the equivalent of runtime.MemStats.HeapInuse, but reading them doesn't stop the world.
+var heapStatsMetrics = []string{
+	"/memory/classes/heap/objects:bytes",
+	"/memory/classes/heap/unused:bytes",
+	"/gc/cycles/total:gc-cycles",
+}
+
+// readHeapStats returns the Go heap in use, in bytes, and the number of completed GC cycles.
+func readHeapStats() (uint64, uint64) {
+	samples := make([]metrics.Sample, len(heapStatsMetrics))
+	for i, name := range heapStatsMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	return sampleUint64(samples[0]) + sampleUint64(samples[1]), sampleUint64(samples[2])
+}
+
+func sampleUint64(s metrics.Sample) uint64 {
+	if s.Value.Kind() != metrics.KindUint64 {
+		// The metric isn't supported by the Go runtime.
+		return 0
+	}
+	return s.Value.Uint64()
+}
+
+// updateMemoryPressure samples the Go heap in use, which is used to enforce the memory pressure heap limit,
+// and triggers a memory pressure compaction if the heap in use is higher than the memory pressure target.
+func (i *Ingester) updateMemoryPressure() {
+	heapInUse, numGC := i.readHeapStats()
+	i.heapInUse.Store(heapInUse)
+
+	if !i.isMemoryPressureCompactionRequired(heapInUse, numGC) {
+		return
+	}
+
+	select {
+	case i.memoryPressureCompactTrigger <- struct{}{}:
+	default:
+		// A memory pressure compaction is already pending.
+	}
+}
+
+// isMemoryPressureCompactionRequired returns whether the heap in use is higher than the memory pressure target.
+// The memory released by a compaction is reclaimed by the garbage collector, so a new compaction isn't required
+// until a GC cycle has completed after the previous one.
+func (i *Ingester) isMemoryPressureCompactionRequired(heapInUse uint64, numGC uint64) bool {
+	heapTarget := i.cfg.MemoryPressureHeapTargetBytes
+	return heapTarget > 0 && heapInUse >= heapTarget && numGC != i.memoryPressureCompactionNumGC.Load()
+}
+
+// compactBlocksToReduceMemoryPressure compacts the oldest block range of the TSDB Head of the tenants with the
+// biggest heads, in order to reduce the Go heap in use.
+func (i *Ingester) compactBlocksToReduceMemoryPressure(ctx context.Context) {
+	// The heap in use may have changed since the compaction has been triggered.
+	heapInUse, numGC := i.readHeapStats()
+	if !i.isMemoryPressureCompactionRequired(heapInUse, numGC) {
+		return
+	}
+
+	defer func() {
+		_, numGC := i.readHeapStats()
+		i.memoryPressureCompactionNumGC.Store(numGC)
+	}()
+
+	heapTarget := i.cfg.MemoryPressureHeapTargetBytes
+	level.Info(i.logger).Log("msg", "the Go heap in use is higher than the configured memory pressure target", "heap_in_use_bytes", heapInUse, "heap_target_bytes", heapTarget)
+
+	blockDuration := i.cfg.BlocksStorageConfig.TSDB.BlockRanges[0].Milliseconds()
+	var candidates []memoryPressureCompactionCandidate
+
+	for _, userID := range i.getTSDBUsers() {
+		db := i.getTSDB(userID)
+		if db == nil {
+			continue
+		}
+
+		h := db.Head()
+		numSeries := h.NumSeries()
+		if numSeries == 0 {
+			continue
+		}
+
+		forcedCompactionMaxTime, ok := oldestHeadRangeMaxTime(blockDuration, h.MinTime(), h.MaxTime())
+		if !ok {
+			continue
+		}
+
+		candidates = append(candidates, memoryPressureCompactionCandidate{
+			userID:                  userID,
+			numSeries:               numSeries,
+			forcedCompactionMaxTime: forcedCompactionMaxTime,
+		})
+	}
+
+	usersToCompact := filterUsersToCompactToReduceMemoryPressure(heapInUse, heapTarget, uint64(i.seriesCount.Load()), candidates)
+	if len(usersToCompact) == 0 {
+		level.Info(i.logger).Log("msg", "no viable per-tenant TSDB found to early compact in order to reduce memory pressure")
+		return
+	}
+
+	// Tenants are compacted one by one, to not increase the memory pressure with concurrent compactions.
+	for _, c := range usersToCompact {
+		if ctx.Err() != nil {
+			return
+		}
+
+		level.Info(i.logger).Log("msg", "running TSDB head compaction to reduce memory pressure", "user", c.userID, "in_memory_series", c.numSeries, "forced_compaction_max_time", formatMillisTime(c.forcedCompactionMaxTime))
+		i.metrics.compactionsTriggeredMemoryPressure.Inc()
+		i.compactBlocks(ctx, true, c.forcedCompactionMaxTime, util.NewAllowedTenants([]string{c.userID}, nil))
+	}
+
+	after, _ := i.readHeapStats()
+	level.Info(i.logger).Log("msg", "run TSDB head compaction to reduce memory pressure", "before_heap_in_use_bytes", heapInUse, "after_heap_in_use_bytes", after)
+}
+
+type memoryPressureCompactionCandidate struct {
+	userID                  string
+	numSeries               uint64
+	forcedCompactionMaxTime int64
+}
+
+// oldestHeadRangeMaxTime returns the max time, included, of the oldest block range of a TSDB Head. It returns false if
+// the Head spans a single block range, because the latest block range is still being appended and it's not compacted
+// early.
+func oldestHeadRangeMaxTime(blockDuration, headMinTime, headMaxTime int64) (int64, bool) {
+	// Nothing to compact if the head is empty.
+	if headMinTime == math.MaxInt64 || headMaxTime == math.MinInt64 {
+		return 0, false
+	}
+
+	// Block max time is exclusive, so we do a -1 here.
+	maxTime := ((headMinTime/blockDuration)+1)*blockDuration - 1
+	if maxTime >= headMaxTime {
+		return 0, false
+	}
+
+	return maxTime, true
+}
+
+// filterUsersToCompactToReduceMemoryPressure returns the candidates to compact, picking the ones with the biggest heads
+// first. The heap in use is assumed to be proportional to the number of in-memory series, so candidates are picked
+// until their series account for the share of the heap in use exceeding the target.
+func filterUsersToCompactToReduceMemoryPressure(heapInUse, heapTarget, numMemorySeries uint64, candidates []memoryPressureCompactionCandidate) []memoryPressureCompactionCandidate {
+	if heapInUse < heapTarget || len(candidates) == 0 {
+		return nil
+	}
+
+	var (
+		usersToCompact        []memoryPressureCompactionCandidate
+		seriesReductionSum    = uint64(0)
+		seriesReductionTarget = uint64(float64(numMemorySeries) * float64(heapInUse-heapTarget) / float64(heapInUse))
+	)
+
+	slices.SortFunc(candidates, func(a, b memoryPressureCompactionCandidate) int {
+		switch {
+		case b.numSeries < a.numSeries:
+			return -1
+		case b.numSeries > a.numSeries:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	for _, c := range candidates {
+		if len(usersToCompact) > 0 && seriesReductionSum >= seriesReductionTarget {
+			break
+		}
+		usersToCompact = append(usersToCompact, c)
+		seriesReductionSum += c.numSeries
+	}
+
+	return usersToCompact
+}
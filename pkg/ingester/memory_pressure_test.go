@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/util/globalerror"
+)
+
+func TestReadHeapStats(t *testing.T) {
+	heapInUse, numGC := readHeapStats()
+	require.Greater(t, heapInUse, uint64(0))
+
+	runtime.GC()
+
+	_, numGCAfter := readHeapStats()
+	require.Greater(t, numGCAfter, numGC)
+}
+
+func TestOldestHeadRangeMaxTime(t *testing.T) {
+	const blockDuration = 100
+
+	tests := map[string]struct {
+		headMinTime     int64
+		headMaxTime     int64
+		expectedMaxTime int64
+		expectedOK      bool
+	}{
+		"empty head": {
+			headMinTime: math.MaxInt64,
+			headMaxTime: math.MinInt64,
+		},
+		"head spanning a single block range": {
+			headMinTime: 110,
+			headMaxTime: 199,
+		},
+		"head spanning two block ranges": {
+			headMinTime:     110,
+			headMaxTime:     200,
+			expectedMaxTime: 199,
+			expectedOK:      true,
+		},
+		"head spanning multiple block ranges": {
+			headMinTime:     0,
+			headMaxTime:     450,
+			expectedMaxTime: 99,
+			expectedOK:      true,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			maxTime, ok := oldestHeadRangeMaxTime(blockDuration, testData.headMinTime, testData.headMaxTime)
+			assert.Equal(t, testData.expectedOK, ok)
+			assert.Equal(t, testData.expectedMaxTime, maxTime)
+		})
+	}
+}
+
+func TestFilterUsersToCompactToReduceMemoryPressure(t *testing.T) {
+	candidates := func() []memoryPressureCompactionCandidate {
+		return []memoryPressureCompactionCandidate{
+			{userID: "1", numSeries: 10},
+			{userID: "2", numSeries: 40},
+			{userID: "3", numSeries: 20},
+		}
+	}
+	userIDs := func(candidates []memoryPressureCompactionCandidate) []string {
+		var result []string
+		for _, c := range candidates {
+			result = append(result, c.userID)
+		}
+		return result
+	}
+
+	tests := map[string]struct {
+		heapInUse       uint64
+		heapTarget      uint64
+		numMemorySeries uint64
+		candidates      []memoryPressureCompactionCandidate
+		expected        []string
+	}{
+		"should return no tenant if the heap in use is below the target": {
+			heapInUse:       80,
+			heapTarget:      100,
+			numMemorySeries: 100,
+			candidates:      candidates(),
+		},
+		"should return no tenant if there are no candidates": {
+			heapInUse:       200,
+			heapTarget:      100,
+			numMemorySeries: 100,
+		},
+		"should return the tenant with the biggest head if it's enough to reach the target": {
+			heapInUse:       110,
+			heapTarget:      100,
+			numMemorySeries: 100,
+			candidates:      candidates(),
+			expected:        []string{"2"},
+		},
+		"should return the tenants with the biggest heads required to reach the target": {
+			heapInUse:       200,
+			heapTarget:      100,
+			numMemorySeries: 100,
+			candidates:      candidates(),
+			expected:        []string{"2", "3"},
+		},
+		"should return all tenants if required to reach the target": {
+			heapInUse:       1000,
+			heapTarget:      100,
+			numMemorySeries: 100,
+			candidates:      candidates(),
+			expected:        []string{"2", "3", "1"},
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			actual := filterUsersToCompactToReduceMemoryPressure(testData.heapInUse, testData.heapTarget, testData.numMemorySeries, testData.candidates)
+			assert.Equal(t, testData.expected, userIDs(actual))
+		})
+	}
+}
+
+func TestIngester_compactBlocksToReduceMemoryPressure(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.BlocksStorageConfig.TSDB.HeadCompactionInterval = time.Hour // Do not trigger it during the test, so that we trigger it manually.
+
+	ingester, err := prepareIngesterWithBlocksStorage(t, cfg, nil, nil)
+	require.NoError(t, err)
+
+	var heapInUse, numGC atomic.Uint64
+	ingester.readHeapStats = func() (uint64, uint64) {
+		return heapInUse.Load(), numGC.Load()
+	}
+
+	require.NoError(t, services.StartAndAwaitRunning(ctx, ingester))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(ctx, ingester))
+	})
+
+	// Wait until it's ACTIVE.
+	test.Poll(t, time.Second, ring.ACTIVE, func() interface{} {
+		return ingester.lifecycler.GetState()
+	})
+
+	// Enable the memory pressure target after the ingester has started, so that the memory pressure
+	// compaction isn't triggered in background during the test.
+	ingester.cfg.MemoryPressureHeapTargetBytes = 900
+
+	// Push samples spanning across two block ranges for both tenants. The "big" tenant has more series.
+	startTime, err := time.Parse(time.RFC3339, "2023-06-24T00:00:00Z")
+	require.NoError(t, err)
+
+	for _, ts := range []time.Time{startTime.Add(time.Hour), startTime.Add(150 * time.Minute)} {
+		require.NoError(t, pushSeriesToIngester(user.InjectOrgID(ctx, "big"), t, ingester, []series{
+			{labels.FromStrings(labels.MetricName, "metric_1"), 1, ts.UnixMilli()},
+			{labels.FromStrings(labels.MetricName, "metric_2"), 1, ts.UnixMilli()},
+			{labels.FromStrings(labels.MetricName, "metric_3"), 1, ts.UnixMilli()},
+		}))
+		require.NoError(t, pushSeriesToIngester(user.InjectOrgID(ctx, "small"), t, ingester, []series{
+			{labels.FromStrings(labels.MetricName, "metric_1"), 1, ts.UnixMilli()},
+		}))
+	}
+
+	bigBlocksDir := filepath.Join(ingester.cfg.BlocksStorageConfig.TSDB.Dir, "big")
+	smallBlocksDir := filepath.Join(ingester.cfg.BlocksStorageConfig.TSDB.Dir, "small")
+
+	// No compaction while the heap in use is below the target.
+	heapInUse.Store(800)
+	numGC.Store(1)
+	ingester.compactBlocksToReduceMemoryPressure(ctx)
+	require.Len(t, listBlocksInDir(t, bigBlocksDir), 0)
+	require.Len(t, listBlocksInDir(t, smallBlocksDir), 0)
+
+	// Only the oldest block range of the biggest head is compacted, because it's enough to reach the target.
+	heapInUse.Store(1000)
+	ingester.compactBlocksToReduceMemoryPressure(ctx)
+	require.Len(t, listBlocksInDir(t, bigBlocksDir), 1)
+	require.Len(t, listBlocksInDir(t, smallBlocksDir), 0)
+	require.Equal(t, startTime.Add(2*time.Hour).UnixMilli(), ingester.getTSDB("big").Head().MinTime())
+
+	// No compaction until a GC cycle has completed after the previous compaction.
+	heapInUse.Store(10000)
+	ingester.compactBlocksToReduceMemoryPressure(ctx)
+	require.Len(t, listBlocksInDir(t, smallBlocksDir), 0)
+
+	// The head of the "big" tenant spans a single block range now, so the "small" tenant is compacted.
+	numGC.Store(2)
+	ingester.compactBlocksToReduceMemoryPressure(ctx)
+	require.Len(t, listBlocksInDir(t, bigBlocksDir), 1)
+	require.Len(t, listBlocksInDir(t, smallBlocksDir), 1)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(ingester.metrics.compactionsTriggeredMemoryPressure))
+}
+
+func TestIngester_PushHeapLimit(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), userID)
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.MemoryPressureHeapLimitBytes = 1000
+
+	registry := prometheus.NewRegistry()
+	ingester, err := prepareIngesterWithBlocksStorage(t, cfg, nil, registry)
+	require.NoError(t, err)
+
+	var heapInUse atomic.Uint64
+	ingester.readHeapStats = func() (uint64, uint64) {
+		return heapInUse.Load(), 0
+	}
+
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), ingester))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), ingester))
+	})
+
+	test.Poll(t, time.Second, ring.ACTIVE, func() interface{} {
+		return ingester.lifecycler.GetState()
+	})
+
+	push := func() error {
+		req, _, _, _ := mockWriteRequest(t, labels.FromStrings(labels.MetricName, "metric_1"), 1, time.Now().UnixMilli())
+		_, err := pushWithSimulatedGRPCHandler(ctx, ingester, req)
+		return err
+	}
+
+	heapInUse.Store(900)
+	ingester.updateMemoryPressure()
+	require.NoError(t, push())
+
+	// Pushes are rejected while the heap in use is above the limit.
+	heapInUse.Store(1100)
+	ingester.updateMemoryPressure()
+	err = push()
+	require.ErrorIs(t, err, errHeapLimitReached)
+	require.ErrorContains(t, err, globalerror.IngesterHeapLimit.Error())
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+		# HELP cortex_ingester_instance_rejected_requests_total Requests rejected for hitting per-instance limits
+		# TYPE cortex_ingester_instance_rejected_requests_total counter
+		cortex_ingester_instance_rejected_requests_total{reason="ingester_heap_limit"} 1
+		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_inflight_push_requests"} 0
+		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_inflight_push_requests_bytes"} 0
+		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_ingestion_rate"} 0
+		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_series"} 0
+		cortex_ingester_instance_rejected_requests_total{reason="ingester_max_tenants"} 0
+	`), "cortex_ingester_instance_rejected_requests_total"))
+
+	// Pushes are accepted again once the heap in use is below the limit.
+	heapInUse.Store(900)
+	ingester.updateMemoryPressure()
+	require.NoError(t, push())
+}
@@ -43,6 +43,7 @@ type ingesterMetrics struct {
 	activeSeriesCustomTrackersPerUserNativeHistograms *prometheus.GaugeVec
 	activeNativeHistogramBucketsPerUser               *prometheus.GaugeVec
 	activeNativeHistogramBucketsCustomTrackersPerUser *prometheus.GaugeVec
+	activeSeriesPerUserCostAttribution                *prometheus.GaugeVec
 
 	// Owned series
 	ownedSeriesPerUser *prometheus.GaugeVec
@@ -62,15 +63,22 @@ type ingesterMetrics struct {
 	maxLocalSeriesPerUser *prometheus.GaugeVec
 
 	// Head compactions metrics.
-	compactionsTriggered   prometheus.Counter
-	compactionsFailed      prometheus.Counter
-	appenderAddDuration    prometheus.Histogram
-	appenderCommitDuration prometheus.Histogram
-	idleTsdbChecks         *prometheus.CounterVec
+	compactionsTriggered               prometheus.Counter
+	compactionsFailed                  prometheus.Counter
+	compactionsTriggeredMemoryPressure prometheus.Counter
+	appenderAddDuration                prometheus.Histogram
+	appenderCommitDuration             prometheus.Histogram
+	idleTsdbChecks                     *prometheus.CounterVec
 
 	// Open all existing TSDBs metrics
 	openExistingTSDB prometheus.Counter
 
+	// Head snapshots metrics.
+	headSnapshots               prometheus.Counter
+	headSnapshotsFailed         prometheus.Counter
+	headSnapshotUploadsFailed   prometheus.Counter
+	headSnapshotRestoreDuration prometheus.Histogram
+
 	discarded *discardedMetrics
 	rejected  *prometheus.CounterVec
 
@@ -323,6 +331,12 @@ func newIngesterMetrics(
 			Help: "Number of currently active native histogram series matching a pre-configured label matchers per user.",
 		}, []string{"user", "name"}),
 
+		// Not registered automatically, but only if activeSeriesEnabled is true.
+		activeSeriesPerUserCostAttribution: promauto.With(activeSeriesReg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cortex_ingester_attributed_active_series",
+			Help: "Number of currently active series per user and cost attribution.",
+		}, []string{"user", "attribution"}),
+
 		// Not registered automatically, but only if activeSeriesEnabled is true.
 		activeNativeHistogramBucketsPerUser: promauto.With(activeSeriesReg).NewGaugeVec(prometheus.GaugeOpts{
 			Name: "cortex_ingester_active_native_histogram_buckets",
@@ -344,6 +358,11 @@ func newIngesterMetrics(
 			Name: "cortex_ingester_tsdb_compactions_failed_total",
 			Help: "Total number of compactions that failed.",
 		}),
+
+		compactionsTriggeredMemoryPressure: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_ingester_tsdb_memory_pressure_compactions_triggered_total",
+			Help: "Total number of per-tenant TSDB head compactions triggered because the Go heap in use was above the memory pressure target.",
+		}),
 		appenderAddDuration: promauto.With(r).NewHistogram(prometheus.HistogramOpts{
 			Name:    "cortex_ingester_tsdb_appender_add_duration_seconds",
 			Help:    "The total time it takes for a push request to add samples to the TSDB appender.",
@@ -362,6 +381,24 @@ func newIngesterMetrics(
 			Help: "The total time it takes to open all existing TSDBs at ingester startup. This time also includes the TSDBs WAL replay duration.",
 		}),
 
+		headSnapshots: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_ingester_tsdb_head_snapshots_total",
+			Help: "Total number of TSDB head snapshots written on disk.",
+		}),
+		headSnapshotsFailed: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_ingester_tsdb_head_snapshots_failed_total",
+			Help: "Total number of TSDB head snapshots that failed.",
+		}),
+		headSnapshotUploadsFailed: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_ingester_tsdb_head_snapshot_uploads_failed_total",
+			Help: "Total number of TSDB head snapshots that failed to be uploaded to the storage.",
+		}),
+		headSnapshotRestoreDuration: promauto.With(r).NewHistogram(prometheus.HistogramOpts{
+			Name:    "cortex_ingester_tsdb_head_snapshot_restore_duration_seconds",
+			Help:    "The time it takes to open a TSDB restored from a head snapshot at ingester startup. This time also includes the replay of the WAL written after the snapshot.",
+			Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200},
+		}),
+
 		discarded: newDiscardedMetrics(r),
 		rejected: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
 			Name: "cortex_ingester_instance_rejected_requests_total",
@@ -383,6 +420,7 @@ func newIngesterMetrics(
 	m.rejected.WithLabelValues(reasonIngesterMaxInMemorySeries)
 	m.rejected.WithLabelValues(reasonIngesterMaxInflightPushRequests)
 	m.rejected.WithLabelValues(reasonIngesterMaxInflightPushRequestsBytes)
+	m.rejected.WithLabelValues(reasonIngesterHeapLimit)
 
 	return m
 }
@@ -412,6 +450,7 @@ func (m *ingesterMetrics) deletePerUserCustomTrackerMetrics(userID string, custo
 	m.activeSeriesPerUser.DeleteLabelValues(userID)
 	m.activeSeriesPerUserNativeHistograms.DeleteLabelValues(userID)
 	m.activeNativeHistogramBucketsPerUser.DeleteLabelValues(userID)
+	m.activeSeriesPerUserCostAttribution.DeletePartialMatch(prometheus.Labels{"user": userID})
 	for _, name := range customTrackerMetrics {
 		m.activeSeriesCustomTrackersPerUser.DeleteLabelValues(userID, name)
 		m.activeSeriesCustomTrackersPerUserNativeHistograms.DeleteLabelValues(userID, name)
@@ -420,26 +459,32 @@ func (m *ingesterMetrics) deletePerUserCustomTrackerMetrics(userID string, custo
 }
 
 type discardedMetrics struct {
-	sampleOutOfBounds      *prometheus.CounterVec
-	sampleOutOfOrder       *prometheus.CounterVec
-	sampleTooOld           *prometheus.CounterVec
-	sampleTooFarInFuture   *prometheus.CounterVec
-	newValueForTimestamp   *prometheus.CounterVec
-	perUserSeriesLimit     *prometheus.CounterVec
-	perMetricSeriesLimit   *prometheus.CounterVec
-	invalidNativeHistogram *prometheus.CounterVec
+	sampleOutOfBounds        *prometheus.CounterVec
+	sampleOutOfOrder         *prometheus.CounterVec
+	sampleTooOld             *prometheus.CounterVec
+	sampleTooFarInFuture     *prometheus.CounterVec
+	newValueForTimestamp     *prometheus.CounterVec
+	perUserSeriesLimit       *prometheus.CounterVec
+	perMetricSeriesLimit     *prometheus.CounterVec
+	perLabelValueSeriesLimit *prometheus.CounterVec
+	perLabelNameValuesLimit  *prometheus.CounterVec
+	seriesCreationRateLimit  *prometheus.CounterVec
+	invalidNativeHistogram   *prometheus.CounterVec
 }
 
 func newDiscardedMetrics(r prometheus.Registerer) *discardedMetrics {
 	return &discardedMetrics{
-		sampleOutOfBounds:      validation.DiscardedSamplesCounter(r, reasonSampleOutOfBounds),
-		sampleOutOfOrder:       validation.DiscardedSamplesCounter(r, reasonSampleOutOfOrder),
-		sampleTooOld:           validation.DiscardedSamplesCounter(r, reasonSampleTooOld),
-		sampleTooFarInFuture:   validation.DiscardedSamplesCounter(r, reasonSampleTooFarInFuture),
-		newValueForTimestamp:   validation.DiscardedSamplesCounter(r, reasonNewValueForTimestamp),
-		perUserSeriesLimit:     validation.DiscardedSamplesCounter(r, reasonPerUserSeriesLimit),
-		perMetricSeriesLimit:   validation.DiscardedSamplesCounter(r, reasonPerMetricSeriesLimit),
-		invalidNativeHistogram: validation.DiscardedSamplesCounter(r, reasonInvalidNativeHistogram),
+		sampleOutOfBounds:        validation.DiscardedSamplesCounter(r, reasonSampleOutOfBounds),
+		sampleOutOfOrder:         validation.DiscardedSamplesCounter(r, reasonSampleOutOfOrder),
+		sampleTooOld:             validation.DiscardedSamplesCounter(r, reasonSampleTooOld),
+		sampleTooFarInFuture:     validation.DiscardedSamplesCounter(r, reasonSampleTooFarInFuture),
+		newValueForTimestamp:     validation.DiscardedSamplesCounter(r, reasonNewValueForTimestamp),
+		perUserSeriesLimit:       validation.DiscardedSamplesCounter(r, reasonPerUserSeriesLimit),
+		perMetricSeriesLimit:     validation.DiscardedSamplesCounter(r, reasonPerMetricSeriesLimit),
+		perLabelValueSeriesLimit: validation.DiscardedSamplesCounter(r, reasonPerLabelValueSeriesLimit),
+		perLabelNameValuesLimit:  validation.DiscardedSamplesCounter(r, reasonPerLabelNameValuesLimit),
+		seriesCreationRateLimit:  validation.DiscardedSamplesCounter(r, reasonSeriesCreationRateLimit),
+		invalidNativeHistogram:   validation.DiscardedSamplesCounter(r, reasonInvalidNativeHistogram),
 	}
 }
 
@@ -451,6 +496,9 @@ func (m *discardedMetrics) DeletePartialMatch(filter prometheus.Labels) {
 	m.newValueForTimestamp.DeletePartialMatch(filter)
 	m.perUserSeriesLimit.DeletePartialMatch(filter)
 	m.perMetricSeriesLimit.DeletePartialMatch(filter)
+	m.perLabelValueSeriesLimit.DeletePartialMatch(filter)
+	m.perLabelNameValuesLimit.DeletePartialMatch(filter)
+	m.seriesCreationRateLimit.DeletePartialMatch(filter)
 	m.invalidNativeHistogram.DeletePartialMatch(filter)
 }
 
@@ -462,6 +510,9 @@ func (m *discardedMetrics) DeleteLabelValues(userID string, group string) {
 	m.newValueForTimestamp.DeleteLabelValues(userID, group)
 	m.perUserSeriesLimit.DeleteLabelValues(userID, group)
 	m.perMetricSeriesLimit.DeleteLabelValues(userID, group)
+	m.perLabelValueSeriesLimit.DeleteLabelValues(userID, group)
+	m.perLabelNameValuesLimit.DeleteLabelValues(userID, group)
+	m.seriesCreationRateLimit.DeleteLabelValues(userID, group)
 	m.invalidNativeHistogram.DeleteLabelValues(userID, group)
 }
 
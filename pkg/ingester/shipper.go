@@ -8,6 +8,7 @@ package ingester
 import (
 	"context"
 	"encoding/json"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
@@ -22,9 +23,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/tsdb"
 	"github.com/prometheus/prometheus/tsdb/fileutil"
 	"github.com/thanos-io/objstore"
 
+	"github.com/grafana/mimir/pkg/storage/sharding"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 )
@@ -56,6 +59,8 @@ func newShipperMetrics(reg prometheus.Registerer) *shipperMetrics {
 
 type ShipperConfigProvider interface {
 	OutOfOrderBlocksExternalLabelEnabled(userID string) bool
+	ShipperSplitBlocksEnabled(userID string) bool
+	CompactorSplitAndMergeShards(userID string) int
 }
 
 // shipper watches a directory for matching files and directories and uploads
@@ -197,10 +202,69 @@ func (s *shipper) upload(ctx context.Context, meta *block.Meta) error {
 		meta.Thanos.Labels[mimir_tsdb.OutOfOrderExternalLabel] = mimir_tsdb.OutOfOrderExternalLabelValue
 	}
 
+	if shards := s.cfgProvider.CompactorSplitAndMergeShards(s.userID); shards > 1 && s.cfgProvider.ShipperSplitBlocksEnabled(s.userID) {
+		return s.uploadSplit(ctx, meta, shards)
+	}
+
 	// Upload block with custom metadata.
 	return block.Upload(ctx, s.logger, s.bucket, blockDir, meta)
 }
 
+// uploadSplit splits the block by series hash into the given number of shards and uploads each shard as a
+// separate block, with the same shard ID external label the compactor adds to the blocks it splits. This way
+// the compactor doesn't need to split the blocks uploaded by the shipper, and it can directly merge them.
+//
+// If the upload fails after some shards have been uploaded, the block is split again with new block IDs on the
+// next Sync. The duplicated series are deduplicated by the compactor when merging the blocks of each shard.
+func (s *shipper) uploadSplit(ctx context.Context, meta *block.Meta, shards int) error {
+	splitDir := filepath.Join(s.dir, shipperSplitDirname)
+	if err := os.RemoveAll(splitDir); err != nil {
+		return errors.Wrap(err, "remove split blocks directory")
+	}
+	defer func() {
+		if err := os.RemoveAll(splitDir); err != nil {
+			level.Warn(s.logger).Log("msg", "failed to remove split blocks directory", "dir", splitDir, "err", err)
+		}
+	}()
+
+	comp, err := tsdb.NewLeveledCompactor(ctx, nil, s.logger, []int64{meta.MaxTime - meta.MinTime}, nil, nil)
+	if err != nil {
+		return errors.Wrap(err, "create compactor")
+	}
+
+	splitIDs, err := comp.CompactWithSplitting(splitDir, []string{filepath.Join(s.dir, meta.ULID.String())}, nil, uint64(shards))
+	if err != nil {
+		return errors.Wrap(err, "split block")
+	}
+
+	for shardIndex, splitID := range splitIDs {
+		// The split block ID is zero if the shard has no series.
+		if splitID == (ulid.ULID{}) {
+			continue
+		}
+
+		splitBlockDir := filepath.Join(splitDir, splitID.String())
+		splitMeta, err := block.ReadMetaFromDir(splitBlockDir)
+		if err != nil {
+			return errors.Wrapf(err, "read metadata for split block %s", splitID)
+		}
+
+		splitMeta.Thanos = block.ThanosMeta{
+			Labels:       maps.Clone(meta.Thanos.Labels),
+			Source:       meta.Thanos.Source,
+			SegmentFiles: block.GetSegmentFiles(splitBlockDir),
+		}
+		splitMeta.Thanos.Labels[mimir_tsdb.CompactorShardIDExternalLabel] = sharding.FormatShardIDLabelValue(uint64(shardIndex), uint64(shards))
+
+		level.Info(s.logger).Log("msg", "uploading split block to long-term storage", "block", meta.ULID, "split_block", splitID, "shard", splitMeta.Thanos.Labels[mimir_tsdb.CompactorShardIDExternalLabel])
+		if err := block.Upload(ctx, s.logger, s.bucket, splitBlockDir, splitMeta); err != nil {
+			return errors.Wrapf(err, "upload split block %s", splitID)
+		}
+	}
+
+	return nil
+}
+
 // blockMetasFromOldest returns the block meta of each block found in dir
 // sorted by minTime asc.
 func (s *shipper) blockMetasFromOldest() (metas []*block.Meta, _ error) {
@@ -267,6 +331,10 @@ const (
 
 	// shipperMetaVersion1 represents 1 version of meta.
 	shipperMetaVersion1 = 1
+
+	// shipperSplitDirname is the name of the directory, within the data directory, where the shipper
+	// temporarily writes the split blocks before uploading them.
+	shipperSplitDirname = "shipper-split"
 )
 
 // writeShipperMetaFile writes the given meta into <dir>/mimir.shipper.json.
@@ -13,6 +13,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/grafana/dskit/concurrency"
 	"github.com/oklog/ulid"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/stretchr/testify/require"
 	"github.com/thanos-io/objstore"
@@ -435,6 +437,75 @@ func TestShipper_AddOOOLabel(t *testing.T) {
 	}
 }
 
+func TestShipper_SplitBlocks(t *testing.T) {
+	const shards = 4
+
+	blocksDir := t.TempDir()
+	bucketDir := t.TempDir()
+
+	bkt, err := filesystem.NewBucketClient(filesystem.Config{Directory: bucketDir})
+	require.NoError(t, err)
+
+	tenantLimits := map[string]*validation.Limits{
+		"": {
+			ShipperSplitBlocksEnabled:    true,
+			CompactorSplitAndMergeShards: shards,
+		},
+	}
+	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), validation.NewMockTenantLimits(tenantLimits))
+	require.NoError(t, err)
+	s := newShipper(log.NewNopLogger(), overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource)
+
+	var series []labels.Labels
+	for i := 0; i < 30; i++ {
+		series = append(series, labels.FromStrings(labels.MetricName, "metric", "series", strconv.Itoa(i)))
+	}
+	id, err := block.CreateBlock(context.Background(), blocksDir, series, 10, 0, 1000, labels.FromStrings("a", "b"))
+	require.NoError(t, err)
+
+	uploaded, err := s.Sync(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, uploaded)
+
+	// The block is tracked as shipped, but it's uploaded as split blocks only.
+	shipped, err := readShippedBlocks(blocksDir)
+	require.NoError(t, err)
+	require.Contains(t, shipped, id)
+
+	exists, err := bkt.Exists(context.Background(), path.Join(id.String(), block.MetaFilename))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	var splitIDs []ulid.ULID
+	require.NoError(t, bkt.Iter(context.Background(), "", func(name string) error {
+		if splitID, ok := block.IsBlockDir(name); ok {
+			splitIDs = append(splitIDs, splitID)
+		}
+		return nil
+	}))
+	require.Len(t, splitIDs, shards)
+
+	shardIDs := map[string]struct{}{}
+	numSeries := uint64(0)
+	for _, splitID := range splitIDs {
+		meta, err := block.DownloadMeta(context.Background(), log.NewNopLogger(), bkt, splitID)
+		require.NoError(t, err)
+
+		shardID := meta.Thanos.Labels[mimir_tsdb.CompactorShardIDExternalLabel]
+		require.Equal(t, map[string]string{"a": "b", mimir_tsdb.CompactorShardIDExternalLabel: shardID}, meta.Thanos.Labels)
+		require.Equal(t, block.TestSource, meta.Thanos.Source)
+		require.Equal(t, []ulid.ULID{id}, meta.Compaction.Sources)
+		shardIDs[shardID] = struct{}{}
+		numSeries += meta.Stats.NumSeries
+	}
+
+	require.Equal(t, map[string]struct{}{"1_of_4": {}, "2_of_4": {}, "3_of_4": {}, "4_of_4": {}}, shardIDs)
+	require.Equal(t, uint64(len(series)), numSeries)
+
+	// The split blocks are not left on the local disk.
+	require.NoDirExists(t, filepath.Join(blocksDir, shipperSplitDirname))
+}
+
 func metaWithOOOHint(meta block.Meta) block.Meta {
 	meta.Compaction.SetOutOfOrder()
 	return meta
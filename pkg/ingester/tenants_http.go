@@ -28,6 +28,12 @@ type tenantStats struct {
 	MinTime string
 	MaxTime string
 
+	// Rate of new in-memory series created per second.
+	SeriesCreationRate float64
+
+	// In-memory series and values of the label names limited by per-label limits.
+	LabelLimits []labelLimitStats
+
 	Warning string
 }
 
@@ -86,6 +92,8 @@ func (i *Ingester) TenantsHandler(w http.ResponseWriter, req *http.Request) {
 		s.MinTime = formatMillisTime(db.Head().MinTime())
 		maxMillis := db.Head().MaxTime()
 		s.MaxTime = formatMillisTime(maxMillis)
+		s.SeriesCreationRate = db.createdSeries.Rate()
+		s.LabelLimits = db.labelValues.stats(t)
 
 		if maxMillis-nowMillis > i.limits.CreationGracePeriod(t).Milliseconds() {
 			s.Warning = "TSDB Head max timestamp too far in the future"
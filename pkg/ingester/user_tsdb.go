@@ -23,6 +23,7 @@ import (
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/prometheus/prometheus/tsdb/chunks"
 	"go.uber.org/atomic"
+	"golang.org/x/time/rate"
 
 	"github.com/grafana/mimir/pkg/ingester/activeseries"
 	"github.com/grafana/mimir/pkg/util/extract"
@@ -96,7 +97,11 @@ type userTSDB struct {
 	userID         string
 	activeSeries   *activeseries.ActiveSeries
 	seriesInMetric *metricCounter
-	limiter        *Limiter
+	labelValues    *labelValueCounter
+
+	// Cost attributions exported by the active series metrics. Only accessed by Ingester.updateActiveSeries.
+	activeSeriesAttributions map[string]struct{}
+	limiter                  *Limiter
 
 	instanceSeriesCount *atomic.Int64 // Shared across all userTSDB instances created by ingester.
 	instanceLimitsFn    func() *InstanceLimits
@@ -107,6 +112,7 @@ type userTSDB struct {
 	inFlightAppends                              sync.WaitGroup // Increased with stateMtx read lock held.
 	inFlightAppendsStartedBeforeForcedCompaction sync.WaitGroup // Increased with stateMtx read lock held.
 	forcedCompactionMaxTime                      int64          // Max timestamp of samples that will be compacted from the TSDB head during a forced o early compaction.
+	inFlightHeadSnapshots                        sync.WaitGroup // Increased with stateMtx read lock held.
 
 	// Used to detect idle TSDBs.
 	lastUpdate atomic.Int64
@@ -121,9 +127,21 @@ type userTSDB struct {
 	// Unix timestamp of last deletion mark check.
 	lastDeletionMarkCheck atomic.Int64
 
+	// Unix timestamp of the last successful head snapshot.
+	lastHeadSnapshot atomic.Int64
+
+	// Cardinality samples recorded periodically. Nil if the cardinality history is disabled.
+	cardinalityHistory *cardinalityHistory
+
 	// for statistics
 	ingestedAPISamples  *util_math.EwmaRate
 	ingestedRuleSamples *util_math.EwmaRate
+	createdSeries       *util_math.EwmaRate
+
+	// Token bucket enforcing the series creation rate limit. Its rate and burst are kept in sync with the tenant limits.
+	// Nil until the limit is enabled for the tenant.
+	seriesCreationLimiterMtx sync.Mutex
+	seriesCreationLimiter    *rate.Limiter
 
 	// Block min retention
 	blockMinRetention time.Duration
@@ -308,9 +326,59 @@ func (u *userTSDB) PreCreation(metric labels.Labels) error {
 		return globalerror.MaxSeriesPerMetric
 	}
 
+	// Series per label value and values per label name limits.
+	if err := u.labelValues.canAddSeries(u.userID, metric); err != nil {
+		return err
+	}
+
+	// Series creation rate limit. The token is taken in PostCreation(), once the series has actually been created,
+	// because the series may still not be created after this check, for example when it's concurrently created
+	// by another request.
+	if !u.canCreateSeries(time.Now()) {
+		return globalerror.MaxSeriesCreationRate
+	}
+
 	return nil
 }
 
+// canCreateSeries returns whether a new series can be created at the given time according to the series
+// creation rate limit, without taking a token from the bucket.
+func (u *userTSDB) canCreateSeries(now time.Time) bool {
+	limiter := u.getSeriesCreationLimiter(now)
+	return limiter == nil || limiter.TokensAt(now) >= 1
+}
+
+// takeSeriesCreationToken takes a token from the series creation rate limit bucket for a series created at
+// the given time. Series concurrently allowed by canCreateSeries() may take more tokens than available:
+// the bucket then goes into debt, delaying the creation of the following series.
+func (u *userTSDB) takeSeriesCreationToken(now time.Time) {
+	if limiter := u.getSeriesCreationLimiter(now); limiter != nil {
+		limiter.ReserveN(now, 1)
+	}
+}
+
+// getSeriesCreationLimiter returns the series creation rate limiter updated with the current limits, or nil
+// if the series creation rate limit is disabled.
+func (u *userTSDB) getSeriesCreationLimiter(now time.Time) *rate.Limiter {
+	limit, burst := u.limiter.seriesCreationRateLimit(u.userID)
+	if limit <= 0 {
+		return nil
+	}
+
+	u.seriesCreationLimiterMtx.Lock()
+	defer u.seriesCreationLimiterMtx.Unlock()
+
+	switch {
+	case u.seriesCreationLimiter == nil:
+		// The bucket starts full.
+		u.seriesCreationLimiter = rate.NewLimiter(rate.Limit(limit), burst)
+	case u.seriesCreationLimiter.Limit() != rate.Limit(limit) || u.seriesCreationLimiter.Burst() != burst:
+		u.seriesCreationLimiter.SetLimitAt(now, rate.Limit(limit))
+		u.seriesCreationLimiter.SetBurstAt(now, burst)
+	}
+	return u.seriesCreationLimiter
+}
+
 // getSeriesCountAndMinLocalLimit returns current number of series and minimum local limit that should be used for computing
 // series limit.
 func (u *userTSDB) getSeriesCountAndMinLocalLimit() (int, int) {
@@ -333,6 +401,14 @@ func (u *userTSDB) PostCreation(metric labels.Labels) {
 	u.ownedState.ownedSeriesCount++
 	u.ownedStateMtx.Unlock()
 
+	u.labelValues.increaseSeries(metric)
+
+	// The limiter is nil while replaying the WAL, when series aren't really created.
+	if u.limiter != nil {
+		u.createdSeries.Inc()
+		u.takeSeriesCreationToken(time.Now())
+	}
+
 	metricName, err := extract.MetricNameFromLabels(metric)
 	if err != nil {
 		// This should never happen because it has already been checked in PreCreation().
@@ -345,6 +421,8 @@ func (u *userTSDB) PostDeletion(metrics map[chunks.HeadSeriesRef]labels.Labels)
 	u.instanceSeriesCount.Sub(int64(len(metrics)))
 
 	for _, lbls := range metrics {
+		u.labelValues.decreaseSeries(lbls)
+
 		metricName, err := extract.MetricNameFromLabels(lbls)
 		if err != nil {
 			// This should never happen because it has already been checked in PreCreation().
@@ -359,6 +437,17 @@ func (u *userTSDB) PostDeletion(metrics map[chunks.HeadSeriesRef]labels.Labels)
 	u.activeSeries.PostDeletion(metrics)
 }
 
+// updateLimitedLabelNames updates the label names tracked for the per-label limits, based on the current limits.
+func (u *userTSDB) updateLimitedLabelNames(ctx context.Context) error {
+	idx, err := u.Head().Index()
+	if err != nil {
+		return err
+	}
+	defer idx.Close()
+
+	return u.labelValues.updateLimitedLabelNames(ctx, u.limiter.limitedLabelNames(u.userID), idx)
+}
+
 // blocksToDelete filters the input blocks and returns the blocks which are safe to be deleted from the ingester.
 func (u *userTSDB) blocksToDelete(blocks []*tsdb.Block) map[ulid.ULID]struct{} {
 	if u.db == nil {
@@ -511,6 +600,27 @@ func (u *userTSDB) releaseAppendLock(acquireState tsdbState) {
 	}
 }
 
+// acquireHeadSnapshotLock acquires a lock to snapshot the TSDB head, preventing the TSDB from being closed
+// until the snapshot is done. Pushes, blocks shipping and compactions are not blocked by head snapshots.
+func (u *userTSDB) acquireHeadSnapshotLock() (tsdbState, bool) {
+	u.stateMtx.RLock()
+	defer u.stateMtx.RUnlock()
+
+	switch u.state {
+	case active, activeShipping, forceCompacting:
+	default:
+		return u.state, false
+	}
+
+	u.inFlightHeadSnapshots.Add(1)
+	return u.state, true
+}
+
+// releaseHeadSnapshotLock releases the lock acquired calling acquireHeadSnapshotLock().
+func (u *userTSDB) releaseHeadSnapshotLock() {
+	u.inFlightHeadSnapshots.Done()
+}
+
 // ownedSeriesState returns a copy of the current state
 func (u *userTSDB) ownedSeriesState() ownedSeriesState {
 	u.ownedStateMtx.Lock()
@@ -5,15 +5,25 @@ package ingester
 import (
 	"context"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/util/globalerror"
 	"github.com/grafana/mimir/pkg/util/validation"
 )
 
@@ -311,3 +321,93 @@ func TestRecomputeOwnedSeries(t *testing.T) {
 		require.Equal(t, math.MaxInt32, db.ownedState.localSeriesLimit)
 	})
 }
+
+func TestIngester_PushSeriesCreationRateLimit(t *testing.T) {
+	cfg := defaultIngesterTestConfig(t)
+	cfg.IngesterRing.ReplicationFactor = 1
+	limits := defaultLimitsTestConfig()
+	// The rate is low enough to never refill the bucket during the test.
+	limits.MaxSeriesCreationRate = 0.001
+	limits.MaxSeriesCreationBurstSize = 2
+
+	registry := prometheus.NewRegistry()
+	i, err := prepareIngesterWithBlocksStorageAndLimits(t, cfg, limits, nil, "", registry)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	test.Poll(t, 1*time.Second, 1, func() interface{} {
+		return i.lifecycler.HealthyInstancesCount()
+	})
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+	push := func(lbls ...string) error {
+		req, _, _, _ := mockWriteRequest(t, labels.FromStrings(lbls...), 1, time.Now().UnixMilli())
+		_, err := i.Push(ctx, req)
+		return err
+	}
+
+	require.NoError(t, push("__name__", "up", "pod", "a"))
+	require.NoError(t, push("__name__", "up", "pod", "b"))
+
+	err = push("__name__", "up", "pod", "c")
+	require.ErrorContains(t, err, globalerror.MaxSeriesCreationRate.Error())
+	require.ErrorContains(t, err, "the series creation rate limit (limit: 0.001 series/sec, burst: 2) has been exceeded")
+
+	// Samples of existing series are still accepted.
+	require.NoError(t, push("__name__", "up", "pod", "a"))
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+		# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+		# TYPE cortex_discarded_samples_total counter
+		cortex_discarded_samples_total{group="",reason="series_creation_rate_limit",user="1"} 1
+	`), "cortex_discarded_samples_total"))
+
+	// The rate of created series is exposed in the user stats and the tenants page.
+	i.getTSDB(userID).createdSeries.Tick()
+
+	res, err := i.UserStats(ctx, &client.UserStatsRequest{})
+	require.NoError(t, err)
+	assert.Greater(t, res.SeriesCreationRate, float64(0))
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/tenants", nil)
+	require.NoError(t, err)
+	i.TenantsHandler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "<th>Series creation rate</th>")
+}
+
+func TestUserTSDB_SeriesCreationRateLimit_ShouldTakeTokensOnlyForCreatedSeries(t *testing.T) {
+	limits := defaultLimitsTestConfig()
+	// The rate is low enough to never refill the bucket during the test.
+	limits.MaxSeriesCreationRate = 0.001
+	limits.MaxSeriesCreationBurstSize = 2
+
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	db := &userTSDB{
+		userID:  userID,
+		limiter: NewLimiter(overrides, nil),
+	}
+	now := time.Now()
+
+	// Series allowed but not created, for example because they've been concurrently created
+	// by another request, don't take a token.
+	for i := 0; i < 5; i++ {
+		require.True(t, db.canCreateSeries(now))
+	}
+
+	db.takeSeriesCreationToken(now)
+	require.True(t, db.canCreateSeries(now))
+
+	db.takeSeriesCreationToken(now)
+	require.False(t, db.canCreateSeries(now))
+
+	// Series concurrently allowed when the last token was available put the bucket into debt.
+	db.takeSeriesCreationToken(now)
+	refillPeriod := time.Duration(float64(time.Second) / limits.MaxSeriesCreationRate)
+	require.False(t, db.canCreateSeries(now.Add(refillPeriod)))
+	require.True(t, db.canCreateSeries(now.Add(2*refillPeriod)))
+}
@@ -70,7 +70,7 @@ func (g *grpcInflightMethodLimiter) RPCCallStarting(ctx context.Context, methodN
 		httpMethod := getSingleMetadata(md, httpgrpc.MetadataMethod)
 		httpURL := getSingleMetadata(md, httpgrpc.MetadataURL)
 
-		if httpMethod == http.MethodPost && (strings.HasSuffix(httpURL, api.PrometheusPushEndpoint) || strings.HasSuffix(httpURL, api.OTLPPushEndpoint)) {
+		if isDistributorPushRequest(httpMethod, httpURL) {
 			dist := g.getDistributor()
 			if dist == nil {
 				return ctx, errNoDistributor
@@ -88,6 +88,15 @@ func (g *grpcInflightMethodLimiter) RPCCallStarting(ctx context.Context, methodN
 	return ctx, nil
 }
 
+// isDistributorPushRequest returns whether the httpgrpc request is sent to one of the distributor push endpoints.
+func isDistributorPushRequest(httpMethod, httpURL string) bool {
+	if httpMethod == http.MethodPost && (strings.HasSuffix(httpURL, api.PrometheusPushEndpoint) || strings.HasSuffix(httpURL, api.OTLPPushEndpoint) || strings.HasSuffix(httpURL, api.InfluxPushEndpoint)) {
+		return true
+	}
+	// The Pushgateway compatible endpoint path ends with the grouping key.
+	return (httpMethod == http.MethodPost || httpMethod == http.MethodPut) && strings.Contains(httpURL, api.PushgatewayPushEndpoint+"/metrics/")
+}
+
 func (g *grpcInflightMethodLimiter) RPCCallFinished(ctx context.Context) {
 	if pt, ok := ctx.Value(pushTypeCtxKey).(int); ok {
 		switch pt {
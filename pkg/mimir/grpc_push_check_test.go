@@ -174,6 +174,34 @@ func TestGrpcInflightMethodLimiter(t *testing.T) {
 		require.Nil(t, ctx.Value(pushTypeCtxKey)) // Original context expected in case of errors.
 	})
 
+	t.Run("distributor InfluxDB push via httpgrpc", func(t *testing.T) {
+		m := &mockDistributorReceiver{}
+		l := newGrpcInflightMethodLimiter(nil, func() pushReceiver { return m })
+
+		_, err := l.RPCCallStarting(context.Background(), httpgrpcHandleMethod, metadata.New(map[string]string{
+			httpgrpc.MetadataMethod:      "POST",
+			httpgrpc.MetadataURL:         "prefix" + api.InfluxPushEndpoint,
+			grpcutil.MetadataMessageSize: "123456",
+		}))
+		require.NoError(t, err)
+		require.Equal(t, 1, m.startCalls)
+		require.Equal(t, int64(123456), m.lastRequestSize)
+	})
+
+	t.Run("distributor Pushgateway push via httpgrpc", func(t *testing.T) {
+		m := &mockDistributorReceiver{}
+		l := newGrpcInflightMethodLimiter(nil, func() pushReceiver { return m })
+
+		_, err := l.RPCCallStarting(context.Background(), httpgrpcHandleMethod, metadata.New(map[string]string{
+			httpgrpc.MetadataMethod:      "PUT",
+			httpgrpc.MetadataURL:         "prefix" + api.PushgatewayPushEndpoint + "/metrics/job/test",
+			grpcutil.MetadataMessageSize: "123456",
+		}))
+		require.NoError(t, err)
+		require.Equal(t, 1, m.startCalls)
+		require.Equal(t, int64(123456), m.lastRequestSize)
+	})
+
 	t.Run("distributor push via httpgrpc, GET", func(t *testing.T) {
 		m := &mockDistributorReceiver{}
 
@@ -709,6 +709,10 @@ func (t *Mimir) initQueryFrontendTripperware() (serv services.Service, err error
 
 	engineOpts, engineExperimentalFunctionsEnabled := engine.NewPromQLEngineOptions(t.Cfg.Querier.EngineConfig, t.ActivityTracker, util_log.Logger, promqlEngineRegisterer)
 
+	// The query-frontend reports which engine queriers use to run each query when explaining queries.
+	t.Cfg.Frontend.QueryMiddleware.QueryEngine = t.Cfg.Querier.QueryEngine
+	t.Cfg.Frontend.QueryMiddleware.EnableQueryEngineFallback = t.Cfg.Querier.EnableQueryEngineFallback
+
 	tripperware, err := querymiddleware.NewTripperware(
 		t.Cfg.Frontend.QueryMiddleware,
 		util_log.Logger,
@@ -179,6 +179,37 @@ func TestMetricMetadataToMetricTypeToMetricType(t *testing.T) {
 	}
 }
 
+func TestMetricTypeToMetricMetadataMetricType(t *testing.T) {
+	tc := []struct {
+		desc     string
+		input    model.MetricType
+		expected MetricMetadata_MetricType
+	}{
+		{
+			desc:     "with a single-word metric",
+			input:    model.MetricTypeCounter,
+			expected: COUNTER,
+		},
+		{
+			desc:     "with a two-word metric",
+			input:    model.MetricTypeGaugeHistogram,
+			expected: GAUGEHISTOGRAM,
+		},
+		{
+			desc:     "with an unknown metric",
+			input:    model.MetricType("foo"),
+			expected: UNKNOWN,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.desc, func(t *testing.T) {
+			m := MetricTypeToMetricMetadataMetricType(tt.input)
+			assert.Equal(t, tt.expected, m)
+		})
+	}
+}
+
 func TestFromLabelAdaptersToLabels(t *testing.T) {
 	input := []LabelAdapter{{Name: "hello", Value: "world"}}
 	expected := labels.FromStrings("hello", "world")
@@ -1347,6 +1347,8 @@ type QueryResponse struct {
 	//	*QueryResponse_Matrix
 	Data     isQueryResponse_Data `protobuf_oneof:"data"`
 	Warnings []string             `protobuf:"bytes,8,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// Only set when requested with the stats parameter.
+	Stats *QueryStats `protobuf:"bytes,9,opt,name=stats,proto3" json:"stats,omitempty"`
 }
 
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
@@ -1469,6 +1471,13 @@ func (m *QueryResponse) GetWarnings() []string {
 	return nil
 }
 
+func (m *QueryResponse) GetStats() *QueryStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -1479,6 +1488,183 @@ func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 	}
 }
 
+type QueryStats struct {
+	// The number of samples processed by the PromQL engine.
+	TotalQueryableSamples int64 `protobuf:"varint,1,opt,name=total_queryable_samples,json=totalQueryableSamples,proto3" json:"total_queryable_samples,omitempty"`
+	// The number of samples processed by the PromQL engine at each step. Only set when requested with stats=all.
+	TotalQueryableSamplesPerStep []QueryStepStat `protobuf:"bytes,2,rep,name=total_queryable_samples_per_step,json=totalQueryableSamplesPerStep,proto3" json:"total_queryable_samples_per_step"`
+	// The following statistics are tracked by queriers, and merged for all the queries run to execute a request.
+	WallTimeSeconds      float64 `protobuf:"fixed64,3,opt,name=wall_time_seconds,json=wallTimeSeconds,proto3" json:"wall_time_seconds,omitempty"`
+	FetchedSeriesCount   uint64  `protobuf:"varint,4,opt,name=fetched_series_count,json=fetchedSeriesCount,proto3" json:"fetched_series_count,omitempty"`
+	FetchedChunkBytes    uint64  `protobuf:"varint,5,opt,name=fetched_chunk_bytes,json=fetchedChunkBytes,proto3" json:"fetched_chunk_bytes,omitempty"`
+	FetchedChunksCount   uint64  `protobuf:"varint,6,opt,name=fetched_chunks_count,json=fetchedChunksCount,proto3" json:"fetched_chunks_count,omitempty"`
+	FetchedIndexBytes    uint64  `protobuf:"varint,7,opt,name=fetched_index_bytes,json=fetchedIndexBytes,proto3" json:"fetched_index_bytes,omitempty"`
+	ShardedQueries       uint32  `protobuf:"varint,8,opt,name=sharded_queries,json=shardedQueries,proto3" json:"sharded_queries,omitempty"`
+	SplitQueries         uint32  `protobuf:"varint,9,opt,name=split_queries,json=splitQueries,proto3" json:"split_queries,omitempty"`
+	EstimatedSeriesCount uint64  `protobuf:"varint,10,opt,name=estimated_series_count,json=estimatedSeriesCount,proto3" json:"estimated_series_count,omitempty"`
+	QueueTimeSeconds     float64 `protobuf:"fixed64,11,opt,name=queue_time_seconds,json=queueTimeSeconds,proto3" json:"queue_time_seconds,omitempty"`
+}
+
+func (m *QueryStats) Reset()      { *m = QueryStats{} }
+func (*QueryStats) ProtoMessage() {}
+func (*QueryStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86d4d7485f544059, []int{17}
+}
+func (m *QueryStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryStats.Merge(m, src)
+}
+func (m *QueryStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryStats proto.InternalMessageInfo
+
+func (m *QueryStats) GetTotalQueryableSamples() int64 {
+	if m != nil {
+		return m.TotalQueryableSamples
+	}
+	return 0
+}
+
+func (m *QueryStats) GetTotalQueryableSamplesPerStep() []QueryStepStat {
+	if m != nil {
+		return m.TotalQueryableSamplesPerStep
+	}
+	return nil
+}
+
+func (m *QueryStats) GetWallTimeSeconds() float64 {
+	if m != nil {
+		return m.WallTimeSeconds
+	}
+	return 0
+}
+
+func (m *QueryStats) GetFetchedSeriesCount() uint64 {
+	if m != nil {
+		return m.FetchedSeriesCount
+	}
+	return 0
+}
+
+func (m *QueryStats) GetFetchedChunkBytes() uint64 {
+	if m != nil {
+		return m.FetchedChunkBytes
+	}
+	return 0
+}
+
+func (m *QueryStats) GetFetchedChunksCount() uint64 {
+	if m != nil {
+		return m.FetchedChunksCount
+	}
+	return 0
+}
+
+func (m *QueryStats) GetFetchedIndexBytes() uint64 {
+	if m != nil {
+		return m.FetchedIndexBytes
+	}
+	return 0
+}
+
+func (m *QueryStats) GetShardedQueries() uint32 {
+	if m != nil {
+		return m.ShardedQueries
+	}
+	return 0
+}
+
+func (m *QueryStats) GetSplitQueries() uint32 {
+	if m != nil {
+		return m.SplitQueries
+	}
+	return 0
+}
+
+func (m *QueryStats) GetEstimatedSeriesCount() uint64 {
+	if m != nil {
+		return m.EstimatedSeriesCount
+	}
+	return 0
+}
+
+func (m *QueryStats) GetQueueTimeSeconds() float64 {
+	if m != nil {
+		return m.QueueTimeSeconds
+	}
+	return 0
+}
+
+type QueryStepStat struct {
+	TimestampMs int64 `protobuf:"varint,1,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	Value       int64 `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *QueryStepStat) Reset()      { *m = QueryStepStat{} }
+func (*QueryStepStat) ProtoMessage() {}
+func (*QueryStepStat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86d4d7485f544059, []int{18}
+}
+func (m *QueryStepStat) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryStepStat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryStepStat.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryStepStat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryStepStat.Merge(m, src)
+}
+func (m *QueryStepStat) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryStepStat) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryStepStat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryStepStat proto.InternalMessageInfo
+
+func (m *QueryStepStat) GetTimestampMs() int64 {
+	if m != nil {
+		return m.TimestampMs
+	}
+	return 0
+}
+
+func (m *QueryStepStat) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
 type StringData struct {
 	Value       string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
 	TimestampMs int64  `protobuf:"varint,2,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
@@ -1487,7 +1673,7 @@ type StringData struct {
 func (m *StringData) Reset()      { *m = StringData{} }
 func (*StringData) ProtoMessage() {}
 func (*StringData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_86d4d7485f544059, []int{17}
+	return fileDescriptor_86d4d7485f544059, []int{19}
 }
 func (m *StringData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1538,7 +1724,7 @@ type VectorData struct {
 func (m *VectorData) Reset()      { *m = VectorData{} }
 func (*VectorData) ProtoMessage() {}
 func (*VectorData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_86d4d7485f544059, []int{18}
+	return fileDescriptor_86d4d7485f544059, []int{20}
 }
 func (m *VectorData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1591,7 +1777,7 @@ type VectorSample struct {
 func (m *VectorSample) Reset()      { *m = VectorSample{} }
 func (*VectorSample) ProtoMessage() {}
 func (*VectorSample) Descriptor() ([]byte, []int) {
-	return fileDescriptor_86d4d7485f544059, []int{19}
+	return fileDescriptor_86d4d7485f544059, []int{21}
 }
 func (m *VectorSample) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1651,7 +1837,7 @@ type VectorHistogram struct {
 func (m *VectorHistogram) Reset()      { *m = VectorHistogram{} }
 func (*VectorHistogram) ProtoMessage() {}
 func (*VectorHistogram) Descriptor() ([]byte, []int) {
-	return fileDescriptor_86d4d7485f544059, []int{20}
+	return fileDescriptor_86d4d7485f544059, []int{22}
 }
 func (m *VectorHistogram) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1709,7 +1895,7 @@ type ScalarData struct {
 func (m *ScalarData) Reset()      { *m = ScalarData{} }
 func (*ScalarData) ProtoMessage() {}
 func (*ScalarData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_86d4d7485f544059, []int{21}
+	return fileDescriptor_86d4d7485f544059, []int{23}
 }
 func (m *ScalarData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1759,7 +1945,7 @@ type MatrixData struct {
 func (m *MatrixData) Reset()      { *m = MatrixData{} }
 func (*MatrixData) ProtoMessage() {}
 func (*MatrixData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_86d4d7485f544059, []int{22}
+	return fileDescriptor_86d4d7485f544059, []int{24}
 }
 func (m *MatrixData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1805,7 +1991,7 @@ type MatrixSeries struct {
 func (m *MatrixSeries) Reset()      { *m = MatrixSeries{} }
 func (*MatrixSeries) ProtoMessage() {}
 func (*MatrixSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_86d4d7485f544059, []int{23}
+	return fileDescriptor_86d4d7485f544059, []int{25}
 }
 func (m *MatrixSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1879,6 +2065,8 @@ func init() {
 	proto.RegisterType((*HistogramBucket)(nil), "cortexpb.HistogramBucket")
 	proto.RegisterType((*SampleHistogramPair)(nil), "cortexpb.SampleHistogramPair")
 	proto.RegisterType((*QueryResponse)(nil), "cortexpb.QueryResponse")
+	proto.RegisterType((*QueryStats)(nil), "cortexpb.QueryStats")
+	proto.RegisterType((*QueryStepStat)(nil), "cortexpb.QueryStepStat")
 	proto.RegisterType((*StringData)(nil), "cortexpb.StringData")
 	proto.RegisterType((*VectorData)(nil), "cortexpb.VectorData")
 	proto.RegisterType((*VectorSample)(nil), "cortexpb.VectorSample")
@@ -1891,131 +2079,148 @@ func init() {
 func init() { proto.RegisterFile("mimir.proto", fileDescriptor_86d4d7485f544059) }
 
 var fileDescriptor_86d4d7485f544059 = []byte{
-	// 1975 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcd, 0x73, 0xdc, 0x48,
-	0x15, 0x1f, 0xcd, 0x68, 0x3e, 0xf4, 0x3c, 0x63, 0x77, 0x3a, 0xc6, 0x3b, 0xeb, 0xda, 0x8c, 0x1d,
-	0x51, 0x2c, 0x26, 0x05, 0x0e, 0xb5, 0x0b, 0xd9, 0xda, 0x54, 0x28, 0xd0, 0xcc, 0x28, 0xf1, 0x38,
-	0xf3, 0xe1, 0x6d, 0x69, 0x12, 0xc2, 0x45, 0x25, 0x8f, 0xdb, 0xb6, 0x6a, 0xa5, 0xd1, 0x20, 0x69,
-	0xb2, 0x31, 0x27, 0x2e, 0x50, 0x14, 0x27, 0x2e, 0x1c, 0xa0, 0xb8, 0x71, 0xe1, 0x2f, 0xe0, 0x1f,
-	0xe0, 0x92, 0x2a, 0x8a, 0xaa, 0x1c, 0xb7, 0x38, 0xa4, 0x88, 0x73, 0xd9, 0xe3, 0x1e, 0x38, 0x71,
-	0xa2, 0xba, 0x5b, 0x1f, 0xa3, 0xb1, 0x0d, 0x81, 0xcd, 0x4d, 0xfd, 0xde, 0xaf, 0x5f, 0xff, 0xfa,
-	0xf5, 0xef, 0xb5, 0x9e, 0x04, 0x2b, 0x9e, 0xe3, 0x39, 0xc1, 0xee, 0x2c, 0xf0, 0x23, 0x1f, 0xd7,
-	0x26, 0x7e, 0x10, 0xd1, 0x67, 0xb3, 0xc3, 0xcd, 0xef, 0x9c, 0x38, 0xd1, 0xe9, 0xfc, 0x70, 0x77,
-	0xe2, 0x7b, 0xb7, 0x4f, 0xfc, 0x13, 0xff, 0x36, 0x07, 0x1c, 0xce, 0x8f, 0xf9, 0x88, 0x0f, 0xf8,
-	0x93, 0x98, 0xa8, 0xfe, 0xb9, 0x08, 0xf5, 0xc7, 0x81, 0x13, 0x51, 0x42, 0x7f, 0x3a, 0xa7, 0x61,
-	0x84, 0x0f, 0x00, 0x22, 0xc7, 0xa3, 0x21, 0x0d, 0x1c, 0x1a, 0x36, 0xa5, 0xed, 0xd2, 0xce, 0xca,
-	0x07, 0xeb, 0xbb, 0x49, 0xf8, 0x5d, 0xd3, 0xf1, 0xa8, 0xc1, 0x7d, 0xed, 0xcd, 0xe7, 0x2f, 0xb7,
-	0x0a, 0x7f, 0x7f, 0xb9, 0x85, 0x0f, 0x02, 0x6a, 0xbb, 0xae, 0x3f, 0x31, 0xd3, 0x79, 0x64, 0x21,
-	0x06, 0xfe, 0x18, 0x2a, 0x86, 0x3f, 0x0f, 0x26, 0xb4, 0x59, 0xdc, 0x96, 0x76, 0x56, 0x3f, 0xb8,
-	0x99, 0x45, 0x5b, 0x5c, 0x79, 0x57, 0x80, 0xf4, 0xe9, 0xdc, 0x23, 0xf1, 0x04, 0x7c, 0x17, 0x6a,
-	0x1e, 0x8d, 0xec, 0x23, 0x3b, 0xb2, 0x9b, 0x25, 0x4e, 0xa5, 0x99, 0x4d, 0x1e, 0xd0, 0x28, 0x70,
-	0x26, 0x83, 0xd8, 0xdf, 0x96, 0x9f, 0xbf, 0xdc, 0x92, 0x48, 0x8a, 0xc7, 0xf7, 0x60, 0x33, 0xfc,
-	0xd4, 0x99, 0x59, 0xae, 0x7d, 0x48, 0x5d, 0x6b, 0x6a, 0x7b, 0xd4, 0x7a, 0x6a, 0xbb, 0xce, 0x91,
-	0x1d, 0x39, 0xfe, 0xb4, 0xf9, 0x45, 0x75, 0x5b, 0xda, 0xa9, 0x91, 0x77, 0x18, 0xa4, 0xcf, 0x10,
-	0x43, 0xdb, 0xa3, 0x8f, 0x52, 0xbf, 0xba, 0x05, 0x90, 0xf1, 0xc1, 0x55, 0x28, 0x69, 0x07, 0x3d,
-	0x54, 0xc0, 0x35, 0x90, 0xc9, 0xb8, 0xaf, 0x23, 0x49, 0x5d, 0x83, 0x46, 0xcc, 0x3e, 0x9c, 0xf9,
-	0xd3, 0x90, 0xaa, 0x77, 0xa1, 0xae, 0x07, 0x81, 0x1f, 0x74, 0x69, 0x64, 0x3b, 0x6e, 0x88, 0x6f,
-	0x41, 0xb9, 0x63, 0xcf, 0x43, 0xda, 0x94, 0xf8, 0xae, 0x17, 0x72, 0xc8, 0x61, 0xdc, 0x47, 0x04,
-	0x44, 0xfd, 0xa7, 0x04, 0x90, 0x65, 0x16, 0x6b, 0x50, 0xe1, 0xac, 0x93, 0xfc, 0x5f, 0xcf, 0xe6,
-	0x72, 0xae, 0x07, 0xb6, 0x13, 0xb4, 0xd7, 0xe3, 0xf4, 0xd7, 0xb9, 0x49, 0x3b, 0xb2, 0x67, 0x11,
-	0x0d, 0x48, 0x3c, 0x11, 0x7f, 0x17, 0xaa, 0xa1, 0xed, 0xcd, 0x5c, 0x1a, 0x36, 0x8b, 0x3c, 0x06,
-	0xca, 0x62, 0x18, 0xdc, 0xc1, 0x13, 0x56, 0x20, 0x09, 0x0c, 0xdf, 0x01, 0x85, 0x3e, 0xa3, 0xde,
-	0xcc, 0xb5, 0x83, 0x30, 0x4e, 0x36, 0x5e, 0xe0, 0x1c, 0xbb, 0xe2, 0x59, 0x19, 0x14, 0x7f, 0x0c,
-	0x70, 0xea, 0x84, 0x91, 0x7f, 0x12, 0xd8, 0x5e, 0xd8, 0x94, 0x97, 0x09, 0xef, 0x25, 0xbe, 0x78,
-	0xe6, 0x02, 0x58, 0xfd, 0x3e, 0x28, 0xe9, 0x7e, 0x30, 0x06, 0x99, 0x1d, 0x12, 0x4f, 0x57, 0x9d,
-	0xf0, 0x67, 0xbc, 0x0e, 0xe5, 0xa7, 0xb6, 0x3b, 0x17, 0xca, 0xa9, 0x13, 0x31, 0x50, 0x35, 0xa8,
-	0x88, 0x2d, 0xe0, 0x9b, 0x50, 0xe7, 0x42, 0x8b, 0x6c, 0x6f, 0x66, 0x79, 0x21, 0x87, 0x95, 0xc8,
-	0x4a, 0x6a, 0x1b, 0x84, 0x59, 0x08, 0x16, 0x57, 0x4a, 0x42, 0xfc, 0xbe, 0x08, 0xab, 0x79, 0xfd,
-	0xe0, 0x8f, 0x40, 0x8e, 0xce, 0x66, 0xc9, 0x71, 0x7d, 0xfd, 0x2a, 0x9d, 0xc5, 0x43, 0xf3, 0x6c,
-	0x46, 0x09, 0x9f, 0x80, 0xbf, 0x0d, 0xd8, 0xe3, 0x36, 0xeb, 0xd8, 0xf6, 0x1c, 0xf7, 0x8c, 0x6b,
-	0x8d, 0x53, 0x51, 0x08, 0x12, 0x9e, 0xfb, 0xdc, 0xc1, 0x24, 0xc6, 0xb6, 0x79, 0x4a, 0xdd, 0x59,
-	0x53, 0xe6, 0x7e, 0xfe, 0xcc, 0x6c, 0xf3, 0xa9, 0x13, 0x35, 0xcb, 0xc2, 0xc6, 0x9e, 0xd5, 0x33,
-	0x80, 0x6c, 0x25, 0xbc, 0x02, 0xd5, 0xf1, 0xf0, 0xe1, 0x70, 0xf4, 0x78, 0x88, 0x0a, 0x6c, 0xd0,
-	0x19, 0x8d, 0x87, 0xa6, 0x4e, 0x90, 0x84, 0x15, 0x28, 0x3f, 0xd0, 0xc6, 0x0f, 0x74, 0x54, 0xc4,
-	0x0d, 0x50, 0xf6, 0x7a, 0x86, 0x39, 0x7a, 0x40, 0xb4, 0x01, 0x2a, 0x61, 0x0c, 0xab, 0xdc, 0x93,
-	0xd9, 0x64, 0x36, 0xd5, 0x18, 0x0f, 0x06, 0x1a, 0x79, 0x82, 0xca, 0x4c, 0xcc, 0xbd, 0xe1, 0xfd,
-	0x11, 0xaa, 0xe0, 0x3a, 0xd4, 0x0c, 0x53, 0x33, 0x75, 0x43, 0x37, 0x51, 0x55, 0x7d, 0x08, 0x15,
-	0xb1, 0xf4, 0x5b, 0x10, 0xa2, 0xfa, 0x4b, 0x09, 0x6a, 0x89, 0x78, 0xde, 0x86, 0xb0, 0x73, 0x92,
-	0x48, 0xce, 0xf3, 0x82, 0x10, 0x4a, 0x17, 0x84, 0xa0, 0xfe, 0xb5, 0x0c, 0x4a, 0x2a, 0x46, 0x7c,
-	0x03, 0x94, 0x89, 0x3f, 0x9f, 0x46, 0x96, 0x33, 0x8d, 0xf8, 0x91, 0xcb, 0x7b, 0x05, 0x52, 0xe3,
-	0xa6, 0xde, 0x34, 0xc2, 0x37, 0x61, 0x45, 0xb8, 0x8f, 0x5d, 0xdf, 0x8e, 0xc4, 0x5a, 0x7b, 0x05,
-	0x02, 0xdc, 0x78, 0x9f, 0xd9, 0x30, 0x82, 0x52, 0x38, 0xf7, 0xf8, 0x4a, 0x12, 0x61, 0x8f, 0x78,
-	0x03, 0x2a, 0xe1, 0xe4, 0x94, 0x7a, 0x36, 0x3f, 0xdc, 0x6b, 0x24, 0x1e, 0xe1, 0x6f, 0xc0, 0xea,
-	0xcf, 0x68, 0xe0, 0x5b, 0xd1, 0x69, 0x40, 0xc3, 0x53, 0xdf, 0x3d, 0xe2, 0x07, 0x2d, 0x91, 0x06,
-	0xb3, 0x9a, 0x89, 0x11, 0xbf, 0x1f, 0xc3, 0x32, 0x5e, 0x15, 0xce, 0x4b, 0x22, 0x75, 0x66, 0xef,
-	0x24, 0xdc, 0x6e, 0x01, 0x5a, 0xc0, 0x09, 0x82, 0x55, 0x4e, 0x50, 0x22, 0xab, 0x29, 0x52, 0x90,
-	0xd4, 0x60, 0x75, 0x4a, 0x4f, 0xec, 0xc8, 0x79, 0x4a, 0xad, 0x70, 0x66, 0x4f, 0xc3, 0x66, 0x6d,
-	0xf9, 0x46, 0x6f, 0xcf, 0x27, 0x9f, 0xd2, 0xc8, 0x98, 0xd9, 0xd3, 0xb8, 0x42, 0x1b, 0xc9, 0x0c,
-	0x66, 0x0b, 0xf1, 0x37, 0x61, 0x2d, 0x0d, 0x71, 0x44, 0xdd, 0xc8, 0x0e, 0x9b, 0xca, 0x76, 0x69,
-	0x07, 0x93, 0x34, 0x72, 0x97, 0x5b, 0x73, 0x40, 0xce, 0x2d, 0x6c, 0xc2, 0x76, 0x69, 0x47, 0xca,
-	0x80, 0x9c, 0x18, 0xbb, 0xde, 0x56, 0x67, 0x7e, 0xe8, 0x2c, 0x90, 0x5a, 0xf9, 0xef, 0xa4, 0x92,
-	0x19, 0x29, 0xa9, 0x34, 0x44, 0x4c, 0xaa, 0x2e, 0x48, 0x25, 0xe6, 0x8c, 0x54, 0x0a, 0x8c, 0x49,
-	0x35, 0x04, 0xa9, 0xc4, 0x1c, 0x93, 0xba, 0x07, 0x10, 0xd0, 0x90, 0x46, 0xd6, 0x29, 0xcb, 0xfc,
-	0x2a, 0xbf, 0x04, 0x6e, 0x5c, 0x72, 0x8d, 0xed, 0x12, 0x86, 0xda, 0x73, 0xa6, 0x11, 0x51, 0x82,
-	0xe4, 0x11, 0xbf, 0x07, 0x4a, 0xaa, 0xb5, 0xe6, 0x1a, 0x17, 0x5f, 0x66, 0x50, 0xef, 0x82, 0x92,
-	0xce, 0xca, 0x97, 0x72, 0x15, 0x4a, 0x4f, 0x74, 0x03, 0x49, 0xb8, 0x02, 0xc5, 0xe1, 0x08, 0x15,
-	0xb3, 0x72, 0x2e, 0x6d, 0xca, 0xbf, 0xfa, 0x63, 0x4b, 0x6a, 0x57, 0xa1, 0xcc, 0x79, 0xb7, 0xeb,
-	0x00, 0xd9, 0xb1, 0xab, 0x7f, 0x93, 0x61, 0x95, 0x1f, 0x71, 0x26, 0xe9, 0x10, 0x30, 0xf7, 0xd1,
-	0xc0, 0x5a, 0xda, 0x49, 0xa3, 0xad, 0xff, 0xeb, 0xe5, 0x96, 0xb6, 0xd0, 0x19, 0xcc, 0x02, 0xdf,
-	0xa3, 0xd1, 0x29, 0x9d, 0x87, 0x8b, 0x8f, 0x9e, 0x7f, 0x44, 0xdd, 0xdb, 0xe9, 0x05, 0xbd, 0xdb,
-	0x11, 0xe1, 0xb2, 0x1d, 0xa3, 0xc9, 0x92, 0xe5, 0xab, 0x6a, 0xfe, 0xc6, 0xe2, 0xa6, 0x84, 0x8a,
-	0x89, 0x92, 0x6a, 0x98, 0x15, 0xbb, 0xf0, 0xc4, 0xc5, 0xce, 0x07, 0x97, 0x54, 0xde, 0x5b, 0x50,
-	0xd4, 0x5b, 0xa8, 0x94, 0x6f, 0x01, 0x4a, 0x59, 0x1c, 0x72, 0x6c, 0x22, 0xb6, 0x54, 0x83, 0x22,
-	0x04, 0x87, 0xa6, 0xab, 0x25, 0x50, 0x51, 0x2c, 0x69, 0x0d, 0xc5, 0xd0, 0x7d, 0xb9, 0x26, 0xa1,
-	0xe2, 0xbe, 0x5c, 0xab, 0xa0, 0xea, 0xbe, 0x5c, 0x53, 0x10, 0xec, 0xcb, 0xb5, 0x3a, 0x6a, 0xec,
-	0xcb, 0xb5, 0x35, 0x84, 0x48, 0x76, 0x8b, 0x91, 0xa5, 0xdb, 0x83, 0x2c, 0x97, 0x2d, 0x59, 0x2e,
-	0x99, 0x45, 0x89, 0xde, 0x03, 0xc8, 0xb6, 0xc7, 0x4e, 0xd5, 0x3f, 0x3e, 0x0e, 0xa9, 0xb8, 0x1a,
-	0xaf, 0x91, 0x78, 0xc4, 0xec, 0x2e, 0x9d, 0x9e, 0x44, 0xa7, 0xfc, 0x40, 0x1a, 0x24, 0x1e, 0xa9,
-	0x73, 0xc0, 0x79, 0x31, 0xf2, 0x37, 0xfa, 0x1b, 0xbc, 0x9d, 0xef, 0x81, 0x92, 0xca, 0x8d, 0xaf,
-	0x95, 0xeb, 0xf0, 0xf2, 0x31, 0xe3, 0x0e, 0x2f, 0x9b, 0xa0, 0x4e, 0x61, 0x4d, 0x34, 0x02, 0x59,
-	0x11, 0xa4, 0x8a, 0x91, 0x2e, 0x51, 0x4c, 0x31, 0x53, 0xcc, 0x87, 0x50, 0x4d, 0xf2, 0x2e, 0x7a,
-	0x9d, 0x77, 0x2f, 0x6b, 0x59, 0x38, 0x82, 0x24, 0x48, 0x35, 0x84, 0xb5, 0x25, 0x1f, 0x6e, 0x01,
-	0x1c, 0xfa, 0xf3, 0xe9, 0x91, 0x1d, 0xb7, 0xcb, 0xd2, 0x4e, 0x99, 0x2c, 0x58, 0x18, 0x1f, 0xd7,
-	0xff, 0x8c, 0x06, 0x89, 0x82, 0xf9, 0x80, 0x59, 0xe7, 0xb3, 0x19, 0x0d, 0x62, 0x0d, 0x8b, 0x41,
-	0xc6, 0x5d, 0x5e, 0xe0, 0xae, 0xba, 0x70, 0x7d, 0x69, 0x93, 0x3c, 0xb9, 0xb9, 0x1b, 0xa7, 0xb8,
-	0x74, 0xe3, 0xe0, 0x8f, 0x2e, 0xe6, 0xf5, 0xdd, 0xe5, 0x06, 0x30, 0x8d, 0xb7, 0x98, 0xd2, 0xbf,
-	0xc8, 0xd0, 0xf8, 0x64, 0x4e, 0x83, 0xb3, 0xa4, 0xaf, 0xc5, 0x77, 0xa0, 0x12, 0x46, 0x76, 0x34,
-	0x0f, 0xe3, 0xce, 0xa8, 0x95, 0xc5, 0xc9, 0x01, 0x77, 0x0d, 0x8e, 0x22, 0x31, 0x1a, 0xff, 0x08,
-	0x80, 0xb2, 0x46, 0xd7, 0xe2, 0x5d, 0xd5, 0x85, 0xd6, 0x3f, 0x3f, 0x97, 0xb7, 0xc4, 0xbc, 0xa7,
-	0x52, 0x68, 0xf2, 0xc8, 0xf2, 0xc1, 0x07, 0x3c, 0x4b, 0x0a, 0x11, 0x03, 0xbc, 0xcb, 0xf8, 0x04,
-	0xce, 0xf4, 0x84, 0xa7, 0x29, 0x57, 0xa0, 0x06, 0xb7, 0x77, 0xed, 0xc8, 0xde, 0x2b, 0x90, 0x18,
-	0xc5, 0xf0, 0x4f, 0xe9, 0x24, 0xf2, 0x03, 0x7e, 0x03, 0xe5, 0xf0, 0x8f, 0xb8, 0x3d, 0xc1, 0x0b,
-	0x14, 0x8f, 0x3f, 0xb1, 0x5d, 0x3b, 0xe0, 0xaf, 0xdf, 0x7c, 0x7c, 0x6e, 0x4f, 0xe3, 0xf3, 0x11,
-	0xc3, 0x7b, 0x76, 0x14, 0x38, 0xcf, 0xf8, 0xf5, 0x95, 0xc3, 0x0f, 0xb8, 0x3d, 0xc1, 0x0b, 0x14,
-	0xde, 0x84, 0xda, 0x67, 0x76, 0x30, 0x75, 0xa6, 0x27, 0xe2, 0x8a, 0x51, 0x48, 0x3a, 0x56, 0xdf,
-	0x87, 0x8a, 0xc8, 0x22, 0x7b, 0x0f, 0xe8, 0x84, 0x8c, 0x88, 0x68, 0xf7, 0x8c, 0x71, 0xa7, 0xa3,
-	0x1b, 0x06, 0x92, 0xc4, 0x4b, 0x41, 0xfd, 0xad, 0x04, 0x4a, 0x9a, 0x32, 0xd6, 0xc7, 0x0d, 0x47,
-	0x43, 0x5d, 0x40, 0xcd, 0xde, 0x40, 0x1f, 0x8d, 0x4d, 0x24, 0xb1, 0xa6, 0xae, 0xa3, 0x0d, 0x3b,
-	0x7a, 0x5f, 0xef, 0x8a, 0xe6, 0x50, 0xff, 0xb1, 0xde, 0x19, 0x9b, 0xbd, 0xd1, 0x10, 0x95, 0x98,
-	0xb3, 0xad, 0x75, 0xad, 0xae, 0x66, 0x6a, 0x48, 0x66, 0xa3, 0x1e, 0xeb, 0x27, 0x87, 0x5a, 0x1f,
-	0x95, 0xf1, 0x1a, 0xac, 0x8c, 0x87, 0xda, 0x23, 0xad, 0xd7, 0xd7, 0xda, 0x7d, 0x1d, 0x55, 0xd8,
-	0xdc, 0xe1, 0xc8, 0xb4, 0xee, 0x8f, 0xc6, 0xc3, 0x2e, 0xaa, 0xb2, 0xc6, 0x92, 0x0d, 0xb5, 0x4e,
-	0x47, 0x3f, 0x30, 0x39, 0xa4, 0x16, 0xbf, 0xac, 0x2a, 0x20, 0xb3, 0x1e, 0x59, 0xd5, 0x01, 0xb2,
-	0xb3, 0xc8, 0xb7, 0xe0, 0xca, 0x55, 0x2d, 0xdb, 0xc5, 0xdb, 0x41, 0xfd, 0x85, 0x04, 0x90, 0x9d,
-	0x11, 0xbe, 0x93, 0x7d, 0xd3, 0x88, 0xf6, 0x71, 0x63, 0xf9, 0x28, 0x2f, 0xff, 0xb2, 0xf9, 0x61,
-	0xee, 0x0b, 0xa5, 0xb8, 0x5c, 0xee, 0x62, 0xea, 0x7f, 0xfa, 0x4e, 0xb1, 0xa0, 0xbe, 0x18, 0x9f,
-	0x5d, 0x83, 0xa2, 0xaf, 0xe7, 0x3c, 0x14, 0x12, 0x8f, 0xfe, 0xff, 0xde, 0xf4, 0xd7, 0x12, 0xac,
-	0x2d, 0xd1, 0xb8, 0x72, 0x91, 0xdc, 0x95, 0x59, 0x7c, 0x83, 0x2b, 0xb3, 0xb0, 0x50, 0xdf, 0x6f,
-	0x42, 0x86, 0x1d, 0x5e, 0x2a, 0xf4, 0xcb, 0xbf, 0x9f, 0xde, 0xe4, 0xf0, 0xda, 0x00, 0x99, 0xfe,
-	0xf1, 0xf7, 0xa0, 0x92, 0xfb, 0xa5, 0xb0, 0xb1, 0x5c, 0x25, 0xf1, 0x4f, 0x05, 0x41, 0x38, 0xc6,
-	0xaa, 0x7f, 0x90, 0xa0, 0xbe, 0xe8, 0xbe, 0x32, 0x29, 0xff, 0xfb, 0xe7, 0x6e, 0x3b, 0x27, 0x0a,
-	0xf1, 0x0e, 0x78, 0xef, 0xaa, 0x3c, 0xf2, 0xef, 0x92, 0x0b, 0xba, 0xb8, 0xf5, 0xbb, 0x22, 0x40,
-	0xf6, 0x31, 0x8f, 0xaf, 0x41, 0x23, 0xee, 0xec, 0xac, 0x8e, 0x36, 0x36, 0x58, 0x41, 0x6e, 0xc2,
-	0x06, 0xd1, 0x0f, 0xfa, 0xbd, 0x8e, 0x66, 0x58, 0xdd, 0x5e, 0xd7, 0x62, 0x75, 0x33, 0xd0, 0xcc,
-	0xce, 0x1e, 0x92, 0xf0, 0xd7, 0xe0, 0x9a, 0x39, 0x1a, 0x59, 0x03, 0x6d, 0xf8, 0xc4, 0xea, 0xf4,
-	0xc7, 0x86, 0xa9, 0x13, 0x03, 0x15, 0x73, 0x95, 0x59, 0x62, 0x01, 0x7a, 0xc3, 0x07, 0xba, 0xc1,
-	0xca, 0xd6, 0x22, 0x9a, 0xa9, 0x5b, 0xfd, 0xde, 0xa0, 0x67, 0xea, 0x5d, 0x24, 0xe3, 0x26, 0xac,
-	0x13, 0xfd, 0x93, 0xb1, 0x6e, 0x98, 0x79, 0x4f, 0x99, 0x55, 0x68, 0x6f, 0x68, 0x98, 0xac, 0xfa,
-	0x85, 0x15, 0x55, 0xf0, 0x3b, 0x70, 0xdd, 0xd0, 0xc9, 0xa3, 0x5e, 0x47, 0xb7, 0x16, 0xab, 0xbb,
-	0x8a, 0xd7, 0x01, 0x99, 0x46, 0xb7, 0x9d, 0xb3, 0xd6, 0x18, 0x0d, 0xc6, 0xae, 0x3d, 0x36, 0x9e,
-	0x20, 0x85, 0x2d, 0xd5, 0xe9, 0x91, 0xce, 0xb8, 0x67, 0x5a, 0x6d, 0xa2, 0x6b, 0x0f, 0x75, 0x62,
-	0x8d, 0x0e, 0xf4, 0x21, 0x02, 0xbc, 0x01, 0x78, 0xa0, 0x9b, 0x7b, 0x23, 0xb1, 0x37, 0xad, 0xdf,
-	0x1f, 0x3d, 0xd6, 0xbb, 0x68, 0xa5, 0xfd, 0x83, 0x17, 0xaf, 0x5a, 0x85, 0xcf, 0x5f, 0xb5, 0x0a,
-	0x5f, 0xbe, 0x6a, 0x49, 0x3f, 0x3f, 0x6f, 0x49, 0x7f, 0x3a, 0x6f, 0x49, 0xcf, 0xcf, 0x5b, 0xd2,
-	0x8b, 0xf3, 0x96, 0xf4, 0x8f, 0xf3, 0x96, 0xf4, 0xc5, 0x79, 0xab, 0xf0, 0xe5, 0x79, 0x4b, 0xfa,
-	0xcd, 0xeb, 0x56, 0xe1, 0xc5, 0xeb, 0x56, 0xe1, 0xf3, 0xd7, 0xad, 0xc2, 0x4f, 0xaa, 0xfc, 0xa7,
-	0xd6, 0xec, 0xf0, 0xb0, 0xc2, 0x7f, 0x4f, 0x7d, 0xf8, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0xdd,
-	0x9d, 0x98, 0x5d, 0xe6, 0x12, 0x00, 0x00,
+	// 2243 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x4f, 0x6f, 0xdb, 0xd8,
+	0x11, 0x17, 0x25, 0x5a, 0x12, 0xc7, 0xb2, 0x4d, 0xbf, 0x78, 0x1d, 0xad, 0x91, 0x28, 0x0e, 0x17,
+	0xdd, 0x75, 0x83, 0xad, 0xb3, 0xd8, 0xdd, 0x66, 0xb1, 0x41, 0x8a, 0x96, 0x92, 0x98, 0x58, 0x89,
+	0xfe, 0x38, 0x8f, 0x54, 0xd2, 0xf4, 0x42, 0xd0, 0xd2, 0xb3, 0x45, 0x84, 0x14, 0x19, 0x92, 0x4a,
+	0xe2, 0x9e, 0x7a, 0x69, 0x51, 0xf4, 0xd4, 0x4b, 0x0f, 0x2d, 0x7a, 0xeb, 0xa5, 0x9f, 0xa0, 0x9f,
+	0x21, 0x40, 0x51, 0x20, 0xc7, 0x45, 0x0f, 0x41, 0xe3, 0x5c, 0xf6, 0xd6, 0x3d, 0xf4, 0xd4, 0x53,
+	0xf1, 0xde, 0xe3, 0x1f, 0x51, 0xb6, 0xb7, 0x69, 0x37, 0x37, 0xce, 0xcc, 0x6f, 0xe6, 0xcd, 0x9b,
+	0x37, 0x6f, 0x66, 0x1e, 0x61, 0xd9, 0xb5, 0x5d, 0x3b, 0xd8, 0xf5, 0x03, 0x2f, 0xf2, 0x50, 0x75,
+	0xe4, 0x05, 0x11, 0x79, 0xee, 0x1f, 0x6c, 0xfd, 0xe0, 0xc8, 0x8e, 0x26, 0xb3, 0x83, 0xdd, 0x91,
+	0xe7, 0x5e, 0x3f, 0xf2, 0x8e, 0xbc, 0xeb, 0x0c, 0x70, 0x30, 0x3b, 0x64, 0x14, 0x23, 0xd8, 0x17,
+	0x57, 0x54, 0xfe, 0x52, 0x84, 0xda, 0xc3, 0xc0, 0x8e, 0x08, 0x26, 0x4f, 0x66, 0x24, 0x8c, 0xd0,
+	0x3e, 0x40, 0x64, 0xbb, 0x24, 0x24, 0x81, 0x4d, 0xc2, 0xba, 0xb0, 0x5d, 0xda, 0x59, 0xfe, 0x74,
+	0x63, 0x37, 0x31, 0xbf, 0x6b, 0xd8, 0x2e, 0xd1, 0x99, 0xac, 0xb9, 0xf5, 0xe2, 0xd5, 0x95, 0xc2,
+	0xdf, 0x5f, 0x5d, 0x41, 0xfb, 0x01, 0xb1, 0x1c, 0xc7, 0x1b, 0x19, 0xa9, 0x1e, 0x9e, 0xb3, 0x81,
+	0xbe, 0x84, 0xb2, 0xee, 0xcd, 0x82, 0x11, 0xa9, 0x17, 0xb7, 0x85, 0x9d, 0xd5, 0x4f, 0xaf, 0x66,
+	0xd6, 0xe6, 0x57, 0xde, 0xe5, 0x20, 0x6d, 0x3a, 0x73, 0x71, 0xac, 0x80, 0x6e, 0x42, 0xd5, 0x25,
+	0x91, 0x35, 0xb6, 0x22, 0xab, 0x5e, 0x62, 0xae, 0xd4, 0x33, 0xe5, 0x1e, 0x89, 0x02, 0x7b, 0xd4,
+	0x8b, 0xe5, 0x4d, 0xf1, 0xc5, 0xab, 0x2b, 0x02, 0x4e, 0xf1, 0xe8, 0x16, 0x6c, 0x85, 0x8f, 0x6d,
+	0xdf, 0x74, 0xac, 0x03, 0xe2, 0x98, 0x53, 0xcb, 0x25, 0xe6, 0x53, 0xcb, 0xb1, 0xc7, 0x56, 0x64,
+	0x7b, 0xd3, 0xfa, 0xd7, 0x95, 0x6d, 0x61, 0xa7, 0x8a, 0x2f, 0x52, 0x48, 0x97, 0x22, 0xfa, 0x96,
+	0x4b, 0x1e, 0xa4, 0x72, 0xe5, 0x0a, 0x40, 0xe6, 0x0f, 0xaa, 0x40, 0x49, 0xdd, 0xef, 0xc8, 0x05,
+	0x54, 0x05, 0x11, 0x0f, 0xbb, 0x9a, 0x2c, 0x28, 0x6b, 0xb0, 0x12, 0x7b, 0x1f, 0xfa, 0xde, 0x34,
+	0x24, 0xca, 0x4d, 0xa8, 0x69, 0x41, 0xe0, 0x05, 0x6d, 0x12, 0x59, 0xb6, 0x13, 0xa2, 0x6b, 0xb0,
+	0xd4, 0xb2, 0x66, 0x21, 0xa9, 0x0b, 0x6c, 0xd7, 0x73, 0x31, 0x64, 0x30, 0x26, 0xc3, 0x1c, 0xa2,
+	0xfc, 0x4b, 0x00, 0xc8, 0x22, 0x8b, 0x54, 0x28, 0x33, 0xaf, 0x93, 0xf8, 0x5f, 0xc8, 0x74, 0x99,
+	0xaf, 0xfb, 0x96, 0x1d, 0x34, 0x37, 0xe2, 0xf0, 0xd7, 0x18, 0x4b, 0x1d, 0x5b, 0x7e, 0x44, 0x02,
+	0x1c, 0x2b, 0xa2, 0x4f, 0xa0, 0x12, 0x5a, 0xae, 0xef, 0x90, 0xb0, 0x5e, 0x64, 0x36, 0xe4, 0xcc,
+	0x86, 0xce, 0x04, 0x2c, 0x60, 0x05, 0x9c, 0xc0, 0xd0, 0x0d, 0x90, 0xc8, 0x73, 0xe2, 0xfa, 0x8e,
+	0x15, 0x84, 0x71, 0xb0, 0xd1, 0x9c, 0xcf, 0xb1, 0x28, 0xd6, 0xca, 0xa0, 0xe8, 0x4b, 0x80, 0x89,
+	0x1d, 0x46, 0xde, 0x51, 0x60, 0xb9, 0x61, 0x5d, 0x5c, 0x74, 0x78, 0x2f, 0x91, 0xc5, 0x9a, 0x73,
+	0x60, 0xe5, 0x87, 0x20, 0xa5, 0xfb, 0x41, 0x08, 0x44, 0x7a, 0x48, 0x2c, 0x5c, 0x35, 0xcc, 0xbe,
+	0xd1, 0x06, 0x2c, 0x3d, 0xb5, 0x9c, 0x19, 0xcf, 0x9c, 0x1a, 0xe6, 0x84, 0xa2, 0x42, 0x99, 0x6f,
+	0x01, 0x5d, 0x85, 0x1a, 0x4b, 0xb4, 0xc8, 0x72, 0x7d, 0xd3, 0x0d, 0x19, 0xac, 0x84, 0x97, 0x53,
+	0x5e, 0x2f, 0xcc, 0x4c, 0x50, 0xbb, 0x42, 0x62, 0xe2, 0x0f, 0x45, 0x58, 0xcd, 0xe7, 0x0f, 0xfa,
+	0x02, 0xc4, 0xe8, 0xd8, 0x4f, 0x8e, 0xeb, 0x83, 0xf3, 0xf2, 0x2c, 0x26, 0x8d, 0x63, 0x9f, 0x60,
+	0xa6, 0x80, 0x3e, 0x06, 0xe4, 0x32, 0x9e, 0x79, 0x68, 0xb9, 0xb6, 0x73, 0xcc, 0x72, 0x8d, 0xb9,
+	0x22, 0x61, 0x99, 0x4b, 0x6e, 0x33, 0x01, 0x4d, 0x31, 0xba, 0xcd, 0x09, 0x71, 0xfc, 0xba, 0xc8,
+	0xe4, 0xec, 0x9b, 0xf2, 0x66, 0x53, 0x3b, 0xaa, 0x2f, 0x71, 0x1e, 0xfd, 0x56, 0x8e, 0x01, 0xb2,
+	0x95, 0xd0, 0x32, 0x54, 0x86, 0xfd, 0x7b, 0xfd, 0xc1, 0xc3, 0xbe, 0x5c, 0xa0, 0x44, 0x6b, 0x30,
+	0xec, 0x1b, 0x1a, 0x96, 0x05, 0x24, 0xc1, 0xd2, 0x1d, 0x75, 0x78, 0x47, 0x93, 0x8b, 0x68, 0x05,
+	0xa4, 0xbd, 0x8e, 0x6e, 0x0c, 0xee, 0x60, 0xb5, 0x27, 0x97, 0x10, 0x82, 0x55, 0x26, 0xc9, 0x78,
+	0x22, 0x55, 0xd5, 0x87, 0xbd, 0x9e, 0x8a, 0x1f, 0xc9, 0x4b, 0x34, 0x99, 0x3b, 0xfd, 0xdb, 0x03,
+	0xb9, 0x8c, 0x6a, 0x50, 0xd5, 0x0d, 0xd5, 0xd0, 0x74, 0xcd, 0x90, 0x2b, 0xca, 0x3d, 0x28, 0xf3,
+	0xa5, 0xdf, 0x41, 0x22, 0x2a, 0xbf, 0x12, 0xa0, 0x9a, 0x24, 0xcf, 0xbb, 0x48, 0xec, 0x5c, 0x4a,
+	0x24, 0xe7, 0x79, 0x2a, 0x11, 0x4a, 0xa7, 0x12, 0x41, 0xf9, 0xeb, 0x12, 0x48, 0x69, 0x32, 0xa2,
+	0xcb, 0x20, 0x8d, 0xbc, 0xd9, 0x34, 0x32, 0xed, 0x69, 0xc4, 0x8e, 0x5c, 0xdc, 0x2b, 0xe0, 0x2a,
+	0x63, 0x75, 0xa6, 0x11, 0xba, 0x0a, 0xcb, 0x5c, 0x7c, 0xe8, 0x78, 0x56, 0xc4, 0xd7, 0xda, 0x2b,
+	0x60, 0x60, 0xcc, 0xdb, 0x94, 0x87, 0x64, 0x28, 0x85, 0x33, 0x97, 0xad, 0x24, 0x60, 0xfa, 0x89,
+	0x36, 0xa1, 0x1c, 0x8e, 0x26, 0xc4, 0xb5, 0xd8, 0xe1, 0xae, 0xe3, 0x98, 0x42, 0xdf, 0x83, 0xd5,
+	0x9f, 0x93, 0xc0, 0x33, 0xa3, 0x49, 0x40, 0xc2, 0x89, 0xe7, 0x8c, 0xd9, 0x41, 0x0b, 0x78, 0x85,
+	0x72, 0x8d, 0x84, 0x89, 0x3e, 0x8c, 0x61, 0x99, 0x5f, 0x65, 0xe6, 0x97, 0x80, 0x6b, 0x94, 0xdf,
+	0x4a, 0x7c, 0xbb, 0x06, 0xf2, 0x1c, 0x8e, 0x3b, 0x58, 0x61, 0x0e, 0x0a, 0x78, 0x35, 0x45, 0x72,
+	0x27, 0x55, 0x58, 0x9d, 0x92, 0x23, 0x2b, 0xb2, 0x9f, 0x12, 0x33, 0xf4, 0xad, 0x69, 0x58, 0xaf,
+	0x2e, 0x56, 0xf4, 0xe6, 0x6c, 0xf4, 0x98, 0x44, 0xba, 0x6f, 0x4d, 0xe3, 0x1b, 0xba, 0x92, 0x68,
+	0x50, 0x5e, 0x88, 0x3e, 0x82, 0xb5, 0xd4, 0xc4, 0x98, 0x38, 0x91, 0x15, 0xd6, 0xa5, 0xed, 0xd2,
+	0x0e, 0xc2, 0xa9, 0xe5, 0x36, 0xe3, 0xe6, 0x80, 0xcc, 0xb7, 0xb0, 0x0e, 0xdb, 0xa5, 0x1d, 0x21,
+	0x03, 0x32, 0xc7, 0x68, 0x79, 0x5b, 0xf5, 0xbd, 0xd0, 0x9e, 0x73, 0x6a, 0xf9, 0xbf, 0x3b, 0x95,
+	0x68, 0xa4, 0x4e, 0xa5, 0x26, 0x62, 0xa7, 0x6a, 0xdc, 0xa9, 0x84, 0x9d, 0x39, 0x95, 0x02, 0x63,
+	0xa7, 0x56, 0xb8, 0x53, 0x09, 0x3b, 0x76, 0xea, 0x16, 0x40, 0x40, 0x42, 0x12, 0x99, 0x13, 0x1a,
+	0xf9, 0x55, 0x56, 0x04, 0x2e, 0x9f, 0x51, 0xc6, 0x76, 0x31, 0x45, 0xed, 0xd9, 0xd3, 0x08, 0x4b,
+	0x41, 0xf2, 0x89, 0x2e, 0x81, 0x94, 0xe6, 0x5a, 0x7d, 0x8d, 0x25, 0x5f, 0xc6, 0x50, 0x6e, 0x82,
+	0x94, 0x6a, 0xe5, 0xaf, 0x72, 0x05, 0x4a, 0x8f, 0x34, 0x5d, 0x16, 0x50, 0x19, 0x8a, 0xfd, 0x81,
+	0x5c, 0xcc, 0xae, 0x73, 0x69, 0x4b, 0xfc, 0xf5, 0x9f, 0x1a, 0x42, 0xb3, 0x02, 0x4b, 0xcc, 0xef,
+	0x66, 0x0d, 0x20, 0x3b, 0x76, 0xe5, 0x6f, 0x22, 0xac, 0xb2, 0x23, 0xce, 0x52, 0x3a, 0x04, 0xc4,
+	0x64, 0x24, 0x30, 0x17, 0x76, 0xb2, 0xd2, 0xd4, 0xfe, 0xfd, 0xea, 0x8a, 0x3a, 0x37, 0x19, 0xf8,
+	0x81, 0xe7, 0x92, 0x68, 0x42, 0x66, 0xe1, 0xfc, 0xa7, 0xeb, 0x8d, 0x89, 0x73, 0x3d, 0x2d, 0xd0,
+	0xbb, 0x2d, 0x6e, 0x2e, 0xdb, 0xb1, 0x3c, 0x5a, 0xe0, 0x7c, 0xd7, 0x9c, 0xbf, 0x3c, 0xbf, 0x29,
+	0x9e, 0xc5, 0x58, 0x4a, 0x73, 0x98, 0x5e, 0x76, 0x2e, 0x89, 0x2f, 0x3b, 0x23, 0xce, 0xb8, 0x79,
+	0xef, 0x20, 0xa3, 0xde, 0xc1, 0x4d, 0xf9, 0x3e, 0xc8, 0xa9, 0x17, 0x07, 0x0c, 0x9b, 0x24, 0x5b,
+	0x9a, 0x83, 0xdc, 0x04, 0x83, 0xa6, 0xab, 0x25, 0x50, 0x7e, 0x59, 0xd2, 0x3b, 0x14, 0x43, 0xef,
+	0x8a, 0x55, 0x41, 0x2e, 0xde, 0x15, 0xab, 0x65, 0xb9, 0x72, 0x57, 0xac, 0x4a, 0x32, 0xdc, 0x15,
+	0xab, 0x35, 0x79, 0xe5, 0xae, 0x58, 0x5d, 0x93, 0x65, 0x9c, 0x55, 0x31, 0xbc, 0x50, 0x3d, 0xf0,
+	0xe2, 0xb5, 0xc5, 0x8b, 0x57, 0x66, 0x3e, 0x45, 0x6f, 0x01, 0x64, 0xdb, 0xa3, 0xa7, 0xea, 0x1d,
+	0x1e, 0x86, 0x84, 0x97, 0xc6, 0x75, 0x1c, 0x53, 0x94, 0xef, 0x90, 0xe9, 0x51, 0x34, 0x61, 0x07,
+	0xb2, 0x82, 0x63, 0x4a, 0x99, 0x01, 0xca, 0x27, 0x23, 0xeb, 0xe8, 0x6f, 0xd1, 0x9d, 0x6f, 0x81,
+	0x94, 0xa6, 0x1b, 0x5b, 0x2b, 0x37, 0xe1, 0xe5, 0x6d, 0xc6, 0x13, 0x5e, 0xa6, 0xa0, 0x4c, 0x61,
+	0x8d, 0x0f, 0x02, 0xd9, 0x25, 0x48, 0x33, 0x46, 0x38, 0x23, 0x63, 0x8a, 0x59, 0xc6, 0x7c, 0x06,
+	0x95, 0x24, 0xee, 0x7c, 0xd6, 0x79, 0xff, 0xac, 0x91, 0x85, 0x21, 0x70, 0x82, 0x54, 0x42, 0x58,
+	0x5b, 0x90, 0xa1, 0x06, 0xc0, 0x81, 0x37, 0x9b, 0x8e, 0xad, 0x78, 0x5c, 0x16, 0x76, 0x96, 0xf0,
+	0x1c, 0x87, 0xfa, 0xe3, 0x78, 0xcf, 0x48, 0x90, 0x64, 0x30, 0x23, 0x28, 0x77, 0xe6, 0xfb, 0x24,
+	0x88, 0x73, 0x98, 0x13, 0x99, 0xef, 0xe2, 0x9c, 0xef, 0x8a, 0x03, 0x17, 0x16, 0x36, 0xc9, 0x82,
+	0x9b, 0xab, 0x38, 0xc5, 0x85, 0x8a, 0x83, 0xbe, 0x38, 0x1d, 0xd7, 0xf7, 0x17, 0x07, 0xc0, 0xd4,
+	0xde, 0x7c, 0x48, 0xff, 0x29, 0xc2, 0xca, 0xfd, 0x19, 0x09, 0x8e, 0x93, 0xb9, 0x16, 0xdd, 0x80,
+	0x72, 0x18, 0x59, 0xd1, 0x2c, 0x8c, 0x27, 0xa3, 0x46, 0x66, 0x27, 0x07, 0xdc, 0xd5, 0x19, 0x0a,
+	0xc7, 0x68, 0xf4, 0x13, 0x00, 0x42, 0x07, 0x5d, 0x93, 0x4d, 0x55, 0xa7, 0x46, 0xff, 0xbc, 0x2e,
+	0x1b, 0x89, 0xd9, 0x4c, 0x25, 0x91, 0xe4, 0x93, 0xc6, 0x83, 0x11, 0x2c, 0x4a, 0x12, 0xe6, 0x04,
+	0xda, 0xa5, 0xfe, 0x04, 0xf6, 0xf4, 0x88, 0x85, 0x29, 0x77, 0x41, 0x75, 0xc6, 0x6f, 0x5b, 0x91,
+	0xb5, 0x57, 0xc0, 0x31, 0x8a, 0xe2, 0x9f, 0x92, 0x51, 0xe4, 0x05, 0xf5, 0xa5, 0x45, 0xfc, 0x03,
+	0xc6, 0x4f, 0xf0, 0x1c, 0xc5, 0xec, 0x8f, 0x2c, 0xc7, 0x0a, 0xea, 0xe5, 0x45, 0xbc, 0xce, 0xf8,
+	0xa9, 0x7d, 0x46, 0x51, 0xbc, 0x6b, 0x45, 0x81, 0xfd, 0xbc, 0x5e, 0x59, 0xc4, 0xf7, 0x18, 0x3f,
+	0xc1, 0x73, 0x14, 0xda, 0x82, 0xea, 0x33, 0x2b, 0x98, 0xda, 0xd3, 0x23, 0x5e, 0x62, 0x24, 0x9c,
+	0xd2, 0xf4, 0xcd, 0x40, 0xa3, 0x47, 0x3b, 0xec, 0x82, 0x29, 0x16, 0x2e, 0x1a, 0xe1, 0x10, 0x73,
+	0x88, 0xf2, 0x21, 0x94, 0x79, 0xc4, 0x69, 0xcf, 0xd0, 0x30, 0x1e, 0x60, 0x3e, 0x1a, 0xea, 0xc3,
+	0x56, 0x4b, 0xd3, 0x75, 0x59, 0xe0, 0x0d, 0x44, 0xf9, 0x9d, 0x00, 0x52, 0x1a, 0x5e, 0x3a, 0xf3,
+	0xf5, 0x07, 0x7d, 0x8d, 0x43, 0x8d, 0x4e, 0x4f, 0x1b, 0x0c, 0x0d, 0x59, 0xa0, 0x03, 0x60, 0x4b,
+	0xed, 0xb7, 0xb4, 0xae, 0xd6, 0xe6, 0x83, 0xa4, 0xf6, 0x53, 0xad, 0x35, 0x34, 0x3a, 0x83, 0xbe,
+	0x5c, 0xa2, 0xc2, 0xa6, 0xda, 0x36, 0xdb, 0xaa, 0xa1, 0xca, 0x22, 0xa5, 0x3a, 0x74, 0xf6, 0xec,
+	0xab, 0x5d, 0x79, 0x09, 0xad, 0xc1, 0xf2, 0xb0, 0xaf, 0x3e, 0x50, 0x3b, 0x5d, 0xb5, 0xd9, 0xd5,
+	0xe4, 0x32, 0xd5, 0xed, 0x0f, 0x0c, 0xf3, 0xf6, 0x60, 0xd8, 0x6f, 0xcb, 0x15, 0x3a, 0x84, 0x52,
+	0x52, 0x6d, 0xb5, 0xb4, 0x7d, 0x83, 0x41, 0xaa, 0x71, 0x63, 0x2b, 0x83, 0x48, 0xe7, 0x69, 0xda,
+	0xc9, 0x20, 0xdb, 0x1d, 0xba, 0x01, 0x17, 0x23, 0x2f, 0xb2, 0x1c, 0xf3, 0x09, 0xe5, 0x59, 0x07,
+	0x0e, 0x31, 0x93, 0x87, 0x8c, 0xc0, 0xb2, 0xfc, 0x3d, 0x26, 0xbe, 0x9f, 0x48, 0x79, 0x2e, 0x87,
+	0x88, 0xc0, 0xf6, 0x39, 0x7a, 0xa6, 0x4f, 0x02, 0x33, 0x8c, 0x88, 0x1f, 0xbf, 0x84, 0x2e, 0x9e,
+	0x8a, 0x2a, 0xf1, 0xe9, 0xda, 0x71, 0x51, 0xbf, 0x74, 0xa6, 0xfd, 0x7d, 0x12, 0x50, 0x1c, 0xba,
+	0x06, 0xeb, 0xcf, 0x2c, 0xc7, 0x31, 0xe9, 0x55, 0x33, 0x43, 0x32, 0xf2, 0xa6, 0xe3, 0x30, 0xbe,
+	0xc5, 0x6b, 0x54, 0xc0, 0x5f, 0x71, 0x8c, 0x8d, 0x3e, 0x81, 0x8d, 0x43, 0x12, 0x8d, 0x26, 0x64,
+	0x6c, 0xf2, 0xa7, 0xb0, 0x99, 0x5d, 0x6f, 0x11, 0xa3, 0x58, 0xc6, 0xdf, 0x7c, 0xbc, 0xdf, 0xed,
+	0xc2, 0x85, 0x44, 0x63, 0x34, 0x99, 0x4d, 0x1f, 0x9b, 0x07, 0xc7, 0x11, 0x09, 0x59, 0xe2, 0x8a,
+	0x78, 0x3d, 0x16, 0xb5, 0xa8, 0xa4, 0x49, 0x05, 0xf3, 0x2b, 0x30, 0x7c, 0xb2, 0x42, 0x39, 0xb7,
+	0x02, 0x53, 0x38, 0xbd, 0x82, 0x3d, 0x1d, 0x93, 0xe7, 0xf1, 0x0a, 0x95, 0xdc, 0x0a, 0x1d, 0x2a,
+	0xe1, 0x2b, 0x7c, 0x04, 0x6b, 0xe1, 0xc4, 0x0a, 0xc6, 0x64, 0xcc, 0x02, 0x4b, 0x8b, 0x5c, 0x95,
+	0x95, 0xfe, 0xd5, 0x98, 0x7d, 0x9f, 0x73, 0xd1, 0x07, 0xb0, 0x12, 0xfa, 0x8e, 0x1d, 0xa5, 0x30,
+	0x89, 0xc1, 0x6a, 0x8c, 0x99, 0x80, 0x3e, 0x87, 0x4d, 0x12, 0x46, 0xb6, 0x6b, 0x45, 0x8b, 0x31,
+	0x01, 0xe6, 0xc0, 0x46, 0x2a, 0x9d, 0x8f, 0xca, 0xc7, 0x80, 0x9e, 0xcc, 0xc8, 0x8c, 0xe4, 0x83,
+	0xbe, 0xcc, 0x82, 0x2e, 0x33, 0xc9, 0x5c, 0xd4, 0x95, 0xbd, 0xb8, 0x80, 0x25, 0xc7, 0x7a, 0xaa,
+	0x0d, 0x09, 0xdf, 0xf2, 0x48, 0xe4, 0x85, 0x94, 0x13, 0x8a, 0x06, 0x90, 0x55, 0x94, 0xfc, 0x43,
+	0x52, 0x3a, 0xef, 0xe1, 0x71, 0xba, 0xc7, 0x29, 0xbf, 0x14, 0x00, 0xb2, 0x4a, 0x83, 0x6e, 0x64,
+	0x2f, 0x73, 0xfe, 0x08, 0xda, 0x5c, 0x2c, 0x48, 0x67, 0xbf, 0xcf, 0x7f, 0x9c, 0x7b, 0x67, 0x17,
+	0x17, 0x9b, 0x16, 0x57, 0xfd, 0xb6, 0xd7, 0xb6, 0x09, 0xb5, 0x79, 0xfb, 0xb4, 0x99, 0xf3, 0xd7,
+	0x29, 0xf3, 0x43, 0xc2, 0x31, 0xf5, 0xff, 0xbf, 0xb0, 0x7e, 0x23, 0xc0, 0xda, 0x82, 0x1b, 0xe7,
+	0x2e, 0x92, 0x6b, 0xfc, 0xc5, 0xb7, 0x68, 0xfc, 0x85, 0xb9, 0x2e, 0xf5, 0x36, 0xce, 0xd0, 0xc3,
+	0x4b, 0xcb, 0xf5, 0xd9, 0x7f, 0x01, 0xde, 0xe6, 0xf0, 0x9a, 0x00, 0x59, 0x15, 0x47, 0x9f, 0x43,
+	0x39, 0xf7, 0x63, 0x6c, 0x73, 0xb1, 0xd6, 0xc7, 0xbf, 0xc6, 0xb8, 0xc3, 0x31, 0x56, 0xf9, 0xa3,
+	0x00, 0xb5, 0x79, 0xf1, 0xb9, 0x41, 0xf9, 0xdf, 0x7f, 0xda, 0x34, 0x73, 0x49, 0xc1, 0x27, 0x99,
+	0x4b, 0xe7, 0xc5, 0x91, 0xbd, 0xae, 0x4f, 0xe5, 0xc5, 0xb5, 0xdf, 0x17, 0x01, 0xb2, 0x5f, 0x52,
+	0x68, 0x1d, 0x56, 0xe2, 0xf7, 0x89, 0xd9, 0x52, 0x87, 0x3a, 0x6d, 0x15, 0x5b, 0xb0, 0x89, 0xb5,
+	0xfd, 0x6e, 0xa7, 0xa5, 0xea, 0x66, 0xbb, 0xd3, 0x36, 0x69, 0x45, 0xef, 0xa9, 0x46, 0x6b, 0x4f,
+	0x16, 0xd0, 0x7b, 0xb0, 0x6e, 0x0c, 0x06, 0x66, 0x4f, 0xed, 0x3f, 0x32, 0x5b, 0xdd, 0xa1, 0x6e,
+	0x68, 0x58, 0x97, 0x8b, 0xb9, 0x9e, 0x51, 0xa2, 0x06, 0x3a, 0xfd, 0x3b, 0x9a, 0x4e, 0x1b, 0x8a,
+	0x89, 0x55, 0x43, 0x33, 0xbb, 0x9d, 0x5e, 0xc7, 0xd0, 0xda, 0xb2, 0x88, 0xea, 0xb0, 0x81, 0xb5,
+	0xfb, 0x43, 0x4d, 0x37, 0xf2, 0x92, 0x25, 0xda, 0x3b, 0x3a, 0x7d, 0xdd, 0xa0, 0x7d, 0x89, 0x73,
+	0xe5, 0x32, 0xba, 0x08, 0x17, 0x74, 0x0d, 0x3f, 0xe8, 0xb4, 0x34, 0x73, 0xbe, 0xef, 0x54, 0xd0,
+	0x06, 0xc8, 0x86, 0xde, 0x6e, 0xe6, 0xb8, 0x55, 0xea, 0x06, 0xf5, 0xae, 0x39, 0xd4, 0x1f, 0xc9,
+	0x12, 0x5d, 0xaa, 0xd5, 0xc1, 0xad, 0x61, 0xc7, 0x30, 0x9b, 0x58, 0x53, 0xef, 0x69, 0xd8, 0x1c,
+	0xec, 0x6b, 0x7d, 0x19, 0xd0, 0x26, 0xa0, 0x9e, 0x66, 0xec, 0x0d, 0xf8, 0xde, 0xd4, 0x6e, 0x77,
+	0xf0, 0x50, 0x6b, 0xcb, 0xcb, 0xcd, 0x1f, 0xbd, 0x7c, 0xdd, 0x28, 0x7c, 0xf5, 0xba, 0x51, 0xf8,
+	0xe6, 0x75, 0x43, 0xf8, 0xc5, 0x49, 0x43, 0xf8, 0xf3, 0x49, 0x43, 0x78, 0x71, 0xd2, 0x10, 0x5e,
+	0x9e, 0x34, 0x84, 0x7f, 0x9c, 0x34, 0x84, 0xaf, 0x4f, 0x1a, 0x85, 0x6f, 0x4e, 0x1a, 0xc2, 0x6f,
+	0xdf, 0x34, 0x0a, 0x2f, 0xdf, 0x34, 0x0a, 0x5f, 0xbd, 0x69, 0x14, 0x7e, 0x56, 0x61, 0xbf, 0x66,
+	0xfd, 0x83, 0x83, 0x32, 0xfb, 0xc9, 0xfa, 0xd9, 0x7f, 0x06, 0x00, 0x1d, 0x21, 0x0e, 0x43, 0xac,
+	0x15, 0x00, 0x00,
 }
 
 func (x ErrorCause) String() string {
@@ -2815,6 +3020,9 @@ func (this *QueryResponse) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if !this.Stats.Equal(that1.Stats) {
+		return false
+	}
 	return true
 }
 func (this *QueryResponse_String_) Equal(that interface{}) bool {
@@ -2913,6 +3121,92 @@ func (this *QueryResponse_Matrix) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *QueryStats) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryStats)
+	if !ok {
+		that2, ok := that.(QueryStats)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.TotalQueryableSamples != that1.TotalQueryableSamples {
+		return false
+	}
+	if len(this.TotalQueryableSamplesPerStep) != len(that1.TotalQueryableSamplesPerStep) {
+		return false
+	}
+	for i := range this.TotalQueryableSamplesPerStep {
+		if !this.TotalQueryableSamplesPerStep[i].Equal(&that1.TotalQueryableSamplesPerStep[i]) {
+			return false
+		}
+	}
+	if this.WallTimeSeconds != that1.WallTimeSeconds {
+		return false
+	}
+	if this.FetchedSeriesCount != that1.FetchedSeriesCount {
+		return false
+	}
+	if this.FetchedChunkBytes != that1.FetchedChunkBytes {
+		return false
+	}
+	if this.FetchedChunksCount != that1.FetchedChunksCount {
+		return false
+	}
+	if this.FetchedIndexBytes != that1.FetchedIndexBytes {
+		return false
+	}
+	if this.ShardedQueries != that1.ShardedQueries {
+		return false
+	}
+	if this.SplitQueries != that1.SplitQueries {
+		return false
+	}
+	if this.EstimatedSeriesCount != that1.EstimatedSeriesCount {
+		return false
+	}
+	if this.QueueTimeSeconds != that1.QueueTimeSeconds {
+		return false
+	}
+	return true
+}
+func (this *QueryStepStat) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryStepStat)
+	if !ok {
+		that2, ok := that.(QueryStepStat)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.TimestampMs != that1.TimestampMs {
+		return false
+	}
+	if this.Value != that1.Value {
+		return false
+	}
+	return true
+}
 func (this *StringData) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -3439,7 +3733,7 @@ func (this *QueryResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 12)
+	s := make([]string, 0, 13)
 	s = append(s, "&mimirpb.QueryResponse{")
 	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
 	s = append(s, "ErrorType: "+fmt.Sprintf("%#v", this.ErrorType)+",\n")
@@ -3448,6 +3742,9 @@ func (this *QueryResponse) GoString() string {
 		s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
 	}
 	s = append(s, "Warnings: "+fmt.Sprintf("%#v", this.Warnings)+",\n")
+	if this.Stats != nil {
+		s = append(s, "Stats: "+fmt.Sprintf("%#v", this.Stats)+",\n")
+	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -3483,6 +3780,43 @@ func (this *QueryResponse_Matrix) GoString() string {
 		`Matrix:` + fmt.Sprintf("%#v", this.Matrix) + `}`}, ", ")
 	return s
 }
+func (this *QueryStats) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 15)
+	s = append(s, "&mimirpb.QueryStats{")
+	s = append(s, "TotalQueryableSamples: "+fmt.Sprintf("%#v", this.TotalQueryableSamples)+",\n")
+	if this.TotalQueryableSamplesPerStep != nil {
+		vs := make([]*QueryStepStat, len(this.TotalQueryableSamplesPerStep))
+		for i := range vs {
+			vs[i] = &this.TotalQueryableSamplesPerStep[i]
+		}
+		s = append(s, "TotalQueryableSamplesPerStep: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "WallTimeSeconds: "+fmt.Sprintf("%#v", this.WallTimeSeconds)+",\n")
+	s = append(s, "FetchedSeriesCount: "+fmt.Sprintf("%#v", this.FetchedSeriesCount)+",\n")
+	s = append(s, "FetchedChunkBytes: "+fmt.Sprintf("%#v", this.FetchedChunkBytes)+",\n")
+	s = append(s, "FetchedChunksCount: "+fmt.Sprintf("%#v", this.FetchedChunksCount)+",\n")
+	s = append(s, "FetchedIndexBytes: "+fmt.Sprintf("%#v", this.FetchedIndexBytes)+",\n")
+	s = append(s, "ShardedQueries: "+fmt.Sprintf("%#v", this.ShardedQueries)+",\n")
+	s = append(s, "SplitQueries: "+fmt.Sprintf("%#v", this.SplitQueries)+",\n")
+	s = append(s, "EstimatedSeriesCount: "+fmt.Sprintf("%#v", this.EstimatedSeriesCount)+",\n")
+	s = append(s, "QueueTimeSeconds: "+fmt.Sprintf("%#v", this.QueueTimeSeconds)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *QueryStepStat) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&mimirpb.QueryStepStat{")
+	s = append(s, "TimestampMs: "+fmt.Sprintf("%#v", this.TimestampMs)+",\n")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *StringData) GoString() string {
 	if this == nil {
 		return "nil"
@@ -4536,6 +4870,18 @@ func (m *QueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Stats != nil {
+		{
+			size, err := m.Stats.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMimir(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
 	if len(m.Warnings) > 0 {
 		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.Warnings[iNdEx])
@@ -4654,7 +5000,7 @@ func (m *QueryResponse_Matrix) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	}
 	return len(dAtA) - i, nil
 }
-func (m *StringData) Marshal() (dAtA []byte, err error) {
+func (m *QueryStats) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4664,48 +5010,170 @@ func (m *StringData) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *StringData) MarshalTo(dAtA []byte) (int, error) {
+func (m *QueryStats) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *StringData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *QueryStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.TimestampMs != 0 {
-		i = encodeVarintMimir(dAtA, i, uint64(m.TimestampMs))
+	if m.QueueTimeSeconds != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.QueueTimeSeconds))))
 		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x59
 	}
-	if len(m.Value) > 0 {
-		i -= len(m.Value)
-		copy(dAtA[i:], m.Value)
-		i = encodeVarintMimir(dAtA, i, uint64(len(m.Value)))
+	if m.EstimatedSeriesCount != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.EstimatedSeriesCount))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x50
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *VectorData) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if m.SplitQueries != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.SplitQueries))
+		i--
+		dAtA[i] = 0x48
 	}
-	return dAtA[:n], nil
-}
-
-func (m *VectorData) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *VectorData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
+	if m.ShardedQueries != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.ShardedQueries))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.FetchedIndexBytes != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.FetchedIndexBytes))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.FetchedChunksCount != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.FetchedChunksCount))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.FetchedChunkBytes != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.FetchedChunkBytes))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.FetchedSeriesCount != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.FetchedSeriesCount))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.WallTimeSeconds != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.WallTimeSeconds))))
+		i--
+		dAtA[i] = 0x19
+	}
+	if len(m.TotalQueryableSamplesPerStep) > 0 {
+		for iNdEx := len(m.TotalQueryableSamplesPerStep) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.TotalQueryableSamplesPerStep[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMimir(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.TotalQueryableSamples != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.TotalQueryableSamples))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryStepStat) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryStepStat) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryStepStat) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Value != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.Value))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.TimestampMs != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.TimestampMs))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StringData) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StringData) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StringData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.TimestampMs != 0 {
+		i = encodeVarintMimir(dAtA, i, uint64(m.TimestampMs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintMimir(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *VectorData) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VectorData) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *VectorData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
@@ -5402,6 +5870,10 @@ func (m *QueryResponse) Size() (n int) {
 			n += 1 + l + sovMimir(uint64(l))
 		}
 	}
+	if m.Stats != nil {
+		l = m.Stats.Size()
+		n += 1 + l + sovMimir(uint64(l))
+	}
 	return n
 }
 
@@ -5453,6 +5925,66 @@ func (m *QueryResponse_Matrix) Size() (n int) {
 	}
 	return n
 }
+func (m *QueryStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TotalQueryableSamples != 0 {
+		n += 1 + sovMimir(uint64(m.TotalQueryableSamples))
+	}
+	if len(m.TotalQueryableSamplesPerStep) > 0 {
+		for _, e := range m.TotalQueryableSamplesPerStep {
+			l = e.Size()
+			n += 1 + l + sovMimir(uint64(l))
+		}
+	}
+	if m.WallTimeSeconds != 0 {
+		n += 9
+	}
+	if m.FetchedSeriesCount != 0 {
+		n += 1 + sovMimir(uint64(m.FetchedSeriesCount))
+	}
+	if m.FetchedChunkBytes != 0 {
+		n += 1 + sovMimir(uint64(m.FetchedChunkBytes))
+	}
+	if m.FetchedChunksCount != 0 {
+		n += 1 + sovMimir(uint64(m.FetchedChunksCount))
+	}
+	if m.FetchedIndexBytes != 0 {
+		n += 1 + sovMimir(uint64(m.FetchedIndexBytes))
+	}
+	if m.ShardedQueries != 0 {
+		n += 1 + sovMimir(uint64(m.ShardedQueries))
+	}
+	if m.SplitQueries != 0 {
+		n += 1 + sovMimir(uint64(m.SplitQueries))
+	}
+	if m.EstimatedSeriesCount != 0 {
+		n += 1 + sovMimir(uint64(m.EstimatedSeriesCount))
+	}
+	if m.QueueTimeSeconds != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *QueryStepStat) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TimestampMs != 0 {
+		n += 1 + sovMimir(uint64(m.TimestampMs))
+	}
+	if m.Value != 0 {
+		n += 1 + sovMimir(uint64(m.Value))
+	}
+	return n
+}
+
 func (m *StringData) Size() (n int) {
 	if m == nil {
 		return 0
@@ -5890,6 +6422,7 @@ func (this *QueryResponse) String() string {
 		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
 		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
 		`Warnings:` + fmt.Sprintf("%v", this.Warnings) + `,`,
+		`Stats:` + strings.Replace(this.Stats.String(), "QueryStats", "QueryStats", 1) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -5934,6 +6467,42 @@ func (this *QueryResponse_Matrix) String() string {
 	}, "")
 	return s
 }
+func (this *QueryStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForTotalQueryableSamplesPerStep := "[]QueryStepStat{"
+	for _, f := range this.TotalQueryableSamplesPerStep {
+		repeatedStringForTotalQueryableSamplesPerStep += strings.Replace(strings.Replace(f.String(), "QueryStepStat", "QueryStepStat", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForTotalQueryableSamplesPerStep += "}"
+	s := strings.Join([]string{`&QueryStats{`,
+		`TotalQueryableSamples:` + fmt.Sprintf("%v", this.TotalQueryableSamples) + `,`,
+		`TotalQueryableSamplesPerStep:` + repeatedStringForTotalQueryableSamplesPerStep + `,`,
+		`WallTimeSeconds:` + fmt.Sprintf("%v", this.WallTimeSeconds) + `,`,
+		`FetchedSeriesCount:` + fmt.Sprintf("%v", this.FetchedSeriesCount) + `,`,
+		`FetchedChunkBytes:` + fmt.Sprintf("%v", this.FetchedChunkBytes) + `,`,
+		`FetchedChunksCount:` + fmt.Sprintf("%v", this.FetchedChunksCount) + `,`,
+		`FetchedIndexBytes:` + fmt.Sprintf("%v", this.FetchedIndexBytes) + `,`,
+		`ShardedQueries:` + fmt.Sprintf("%v", this.ShardedQueries) + `,`,
+		`SplitQueries:` + fmt.Sprintf("%v", this.SplitQueries) + `,`,
+		`EstimatedSeriesCount:` + fmt.Sprintf("%v", this.EstimatedSeriesCount) + `,`,
+		`QueueTimeSeconds:` + fmt.Sprintf("%v", this.QueueTimeSeconds) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryStepStat) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryStepStat{`,
+		`TimestampMs:` + fmt.Sprintf("%v", this.TimestampMs) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *StringData) String() string {
 	if this == nil {
 		return "nil"
@@ -8731,6 +9300,394 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMimir
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMimir
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Stats == nil {
+				m.Stats = &QueryStats{}
+			}
+			if err := m.Stats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMimir(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthMimir
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthMimir
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMimir
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalQueryableSamples", wireType)
+			}
+			m.TotalQueryableSamples = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalQueryableSamples |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalQueryableSamplesPerStep", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMimir
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMimir
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TotalQueryableSamplesPerStep = append(m.TotalQueryableSamplesPerStep, QueryStepStat{})
+			if err := m.TotalQueryableSamplesPerStep[len(m.TotalQueryableSamplesPerStep)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WallTimeSeconds", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.WallTimeSeconds = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedSeriesCount", wireType)
+			}
+			m.FetchedSeriesCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedSeriesCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedChunkBytes", wireType)
+			}
+			m.FetchedChunkBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedChunkBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedChunksCount", wireType)
+			}
+			m.FetchedChunksCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedChunksCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchedIndexBytes", wireType)
+			}
+			m.FetchedIndexBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchedIndexBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShardedQueries", wireType)
+			}
+			m.ShardedQueries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ShardedQueries |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplitQueries", wireType)
+			}
+			m.SplitQueries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SplitQueries |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EstimatedSeriesCount", wireType)
+			}
+			m.EstimatedSeriesCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EstimatedSeriesCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueueTimeSeconds", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.QueueTimeSeconds = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMimir(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthMimir
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthMimir
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryStepStat) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMimir
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryStepStat: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryStepStat: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampMs", wireType)
+			}
+			m.TimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			m.Value = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMimir
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Value |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMimir(dAtA[iNdEx:])
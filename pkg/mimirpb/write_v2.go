@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package mimirpb
+
+import (
+	"fmt"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// customBucketsHistogramSchema is the schema of the native histograms with custom buckets. They're not supported,
+// because their custom bucket boundaries can't be represented in a Histogram.
+const customBucketsHistogramSchema = -53
+
+// ToWriteRequest converts the remote-write 2.0 request into dst, resolving the symbols references. The series are
+// taken from the pool, and should be returned with ReuseSlice once done. The per-series metadata is converted into
+// one MetricMetadata per metric name.
+//
+// If createdTimestampZeroSamples is true, the created timestamp of each series is converted into a zero sample
+// or histogram at the created timestamp, when it's earlier than the first sample or histogram of the series.
+//
+// The label names and values reference the request symbols, so they're only valid as long as the symbols are.
+// An error is returned if the request has native histograms with custom buckets.
+func (m *WriteRequestV2) ToWriteRequest(dst *WriteRequest, createdTimestampZeroSamples bool) error {
+	dst.Timeseries = PreallocTimeseriesSliceFromPool()
+
+	var seenMetadata map[string]struct{}
+	for i := range m.Timeseries {
+		src := &m.Timeseries[i]
+		for _, h := range src.Histograms {
+			if h.Schema == customBucketsHistogramSchema {
+				return fmt.Errorf("unsupported remote-write 2.0 request: native histograms with custom buckets (schema %d) are not supported", h.Schema)
+			}
+		}
+
+		ts := TimeseriesFromPool()
+		dst.Timeseries = append(dst.Timeseries, PreallocTimeseries{TimeSeries: ts})
+
+		var err error
+		if ts.Labels, err = m.labelsFromRefs(ts.Labels, src.LabelsRefs); err != nil {
+			return err
+		}
+		if createdTimestampZeroSamples && src.CreatedTimestamp != 0 {
+			if len(src.Samples) > 0 && src.CreatedTimestamp < src.Samples[0].TimestampMs {
+				ts.Samples = append(ts.Samples, Sample{TimestampMs: src.CreatedTimestamp})
+			}
+			if len(src.Histograms) > 0 && src.CreatedTimestamp < src.Histograms[0].Timestamp {
+				ts.Histograms = append(ts.Histograms, createdTimestampZeroHistogram(src.CreatedTimestamp, src.Histograms[0]))
+			}
+		}
+		ts.Samples = append(ts.Samples, src.Samples...)
+		ts.Histograms = append(ts.Histograms, src.Histograms...)
+		for _, e := range src.Exemplars {
+			lbls, err := m.labelsFromRefs(nil, e.LabelsRefs)
+			if err != nil {
+				return err
+			}
+			ts.Exemplars = append(ts.Exemplars, Exemplar{Labels: lbls, Value: e.Value, TimestampMs: e.Timestamp})
+		}
+
+		if src.Metadata == (MetadataV2{}) {
+			continue
+		}
+		name := metricNameFromLabelAdapters(ts.Labels)
+		if _, ok := seenMetadata[name]; ok {
+			continue
+		}
+		help, err := m.symbol(src.Metadata.HelpRef)
+		if err != nil {
+			return err
+		}
+		unit, err := m.symbol(src.Metadata.UnitRef)
+		if err != nil {
+			return err
+		}
+		if seenMetadata == nil {
+			seenMetadata = map[string]struct{}{}
+		}
+		seenMetadata[name] = struct{}{}
+		dst.Metadata = append(dst.Metadata, &MetricMetadata{Type: src.Metadata.Type, MetricFamilyName: name, Help: help, Unit: unit})
+	}
+	return nil
+}
+
+func (m *WriteRequestV2) labelsFromRefs(dst []LabelAdapter, refs []uint32) ([]LabelAdapter, error) {
+	if len(refs)%2 != 0 {
+		return dst, fmt.Errorf("invalid remote-write 2.0 request: odd number of label references %d", len(refs))
+	}
+	for i := 0; i < len(refs); i += 2 {
+		name, err := m.symbol(refs[i])
+		if err != nil {
+			return dst, err
+		}
+		value, err := m.symbol(refs[i+1])
+		if err != nil {
+			return dst, err
+		}
+		dst = append(dst, LabelAdapter{Name: name, Value: value})
+	}
+	return dst, nil
+}
+
+func (m *WriteRequestV2) symbol(ref uint32) (string, error) {
+	if int(ref) >= len(m.Symbols) {
+		if ref == 0 {
+			// The first symbol is always the empty string, so it may be omitted from an empty symbols table.
+			return "", nil
+		}
+		return "", fmt.Errorf("invalid remote-write 2.0 request: symbol reference %d out of range of %d symbols", ref, len(m.Symbols))
+	}
+	return m.Symbols[ref], nil
+}
+
+func metricNameFromLabelAdapters(lbls []LabelAdapter) string {
+	for _, l := range lbls {
+		if l.Name == model.MetricNameLabel {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+// createdTimestampZeroHistogram returns the zero histogram of a series created at the input timestamp, whose first
+// histogram is first. The zero histogram is a counter reset by definition, and keeps the schema and zero threshold
+// of the first histogram to avoid cutting a new chunk.
+func createdTimestampZeroHistogram(createdTimestamp int64, first Histogram) Histogram {
+	h := Histogram{
+		Schema:        first.Schema,
+		ZeroThreshold: first.ZeroThreshold,
+		ResetHint:     Histogram_YES,
+		Timestamp:     createdTimestamp,
+	}
+	if first.IsFloatHistogram() {
+		h.Count = &Histogram_CountFloat{}
+		h.ZeroCount = &Histogram_ZeroCountFloat{}
+	} else {
+		h.Count = &Histogram_CountInt{}
+		h.ZeroCount = &Histogram_ZeroCountInt{}
+	}
+	return h
+}
+
+// FromPrometheusTimeseriesToWriteRequestV2 converts the input Prometheus series and metadata into a remote-write 2.0
+// request, building its symbols table. It's used by the clients writing remote-write 2.0 requests.
+func FromPrometheusTimeseriesToWriteRequestV2(timeseries []prompb.TimeSeries, metadata []MetricMetadata) *WriteRequestV2 {
+	req := &WriteRequestV2{Symbols: []string{""}}
+	refs := map[string]uint32{"": 0}
+	ref := func(s string) uint32 {
+		if r, ok := refs[s]; ok {
+			return r
+		}
+		r := uint32(len(req.Symbols))
+		refs[s] = r
+		req.Symbols = append(req.Symbols, s)
+		return r
+	}
+	labelsRefs := func(lbls []prompb.Label) []uint32 {
+		result := make([]uint32, 0, 2*len(lbls))
+		for _, l := range lbls {
+			result = append(result, ref(l.Name), ref(l.Value))
+		}
+		return result
+	}
+
+	for _, ts := range timeseries {
+		series := TimeSeriesV2{LabelsRefs: labelsRefs(ts.Labels)}
+		for _, s := range ts.Samples {
+			series.Samples = append(series.Samples, Sample{Value: s.Value, TimestampMs: s.Timestamp})
+		}
+		for _, h := range ts.Histograms {
+			series.Histograms = append(series.Histograms, fromPrometheusHistogram(h))
+		}
+		for _, e := range ts.Exemplars {
+			series.Exemplars = append(series.Exemplars, ExemplarV2{LabelsRefs: labelsRefs(e.Labels), Value: e.Value, Timestamp: e.Timestamp})
+		}
+
+		name := prometheusMetricName(ts.Labels)
+		for _, m := range metadata {
+			if m.MetricFamilyName == name {
+				series.Metadata = MetadataV2{Type: m.Type, HelpRef: ref(m.Help), UnitRef: ref(m.Unit)}
+				break
+			}
+		}
+
+		req.Timeseries = append(req.Timeseries, series)
+	}
+
+	return req
+}
+
+func fromPrometheusHistogram(h prompb.Histogram) Histogram {
+	result := Histogram{
+		Sum:            h.Sum,
+		Schema:         h.Schema,
+		ZeroThreshold:  h.ZeroThreshold,
+		NegativeSpans:  fromPrometheusBucketSpans(h.NegativeSpans),
+		NegativeDeltas: h.NegativeDeltas,
+		NegativeCounts: h.NegativeCounts,
+		PositiveSpans:  fromPrometheusBucketSpans(h.PositiveSpans),
+		PositiveDeltas: h.PositiveDeltas,
+		PositiveCounts: h.PositiveCounts,
+		ResetHint:      Histogram_ResetHint(h.ResetHint),
+		Timestamp:      h.Timestamp,
+	}
+	if h.IsFloatHistogram() {
+		result.Count = &Histogram_CountFloat{CountFloat: h.GetCountFloat()}
+		result.ZeroCount = &Histogram_ZeroCountFloat{ZeroCountFloat: h.GetZeroCountFloat()}
+	} else {
+		result.Count = &Histogram_CountInt{CountInt: h.GetCountInt()}
+		result.ZeroCount = &Histogram_ZeroCountInt{ZeroCountInt: h.GetZeroCountInt()}
+	}
+	return result
+}
+
+func fromPrometheusBucketSpans(spans []prompb.BucketSpan) []BucketSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	result := make([]BucketSpan, 0, len(spans))
+	for _, s := range spans {
+		result = append(result, BucketSpan{Offset: s.Offset, Length: s.Length})
+	}
+	return result
+}
+
+func prometheusMetricName(lbls []prompb.Label) string {
+	for _, l := range lbls {
+		if l.Name == model.MetricNameLabel {
+			return l.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package mimirpb
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestWriteRequestV2_MarshalUnmarshal(t *testing.T) {
+	req := &WriteRequestV2{
+		Symbols: []string{"", "__name__", "foo", "job", "test", "trace_id", "abc", "Foo help.", "seconds"},
+		Timeseries: []TimeSeriesV2{
+			{
+				LabelsRefs: []uint32{1, 2, 3, 4},
+				Samples:    []Sample{{Value: 1, TimestampMs: 1000}, {Value: 2, TimestampMs: 2000}},
+				Histograms: []Histogram{{
+					Count:          &Histogram_CountInt{CountInt: 5},
+					Sum:            10,
+					Schema:         1,
+					ZeroCount:      &Histogram_ZeroCountInt{ZeroCountInt: 1},
+					PositiveSpans:  []BucketSpan{{Offset: 0, Length: 2}},
+					PositiveDeltas: []int64{2, 0},
+					Timestamp:      3000,
+				}},
+				Exemplars:        []ExemplarV2{{LabelsRefs: []uint32{5, 6}, Value: 1, Timestamp: 1000}},
+				Metadata:         MetadataV2{Type: COUNTER, HelpRef: 7, UnitRef: 8},
+				CreatedTimestamp: 500,
+			},
+		},
+	}
+
+	data, err := req.Marshal()
+	require.NoError(t, err)
+
+	var actual WriteRequestV2
+	require.NoError(t, actual.Unmarshal(data))
+	require.Equal(t, req, &actual)
+}
+
+func TestWriteRequestV2_Unmarshal_UnpackedLabelsRefs(t *testing.T) {
+	var ts []byte
+	for _, ref := range []uint32{1, 2} {
+		ts = protowire.AppendTag(ts, 1, protowire.VarintType)
+		ts = protowire.AppendVarint(ts, uint64(ref))
+	}
+	// Unknown fields are ignored.
+	ts = protowire.AppendTag(ts, 100, protowire.BytesType)
+	ts = protowire.AppendString(ts, "unknown")
+
+	var data []byte
+	data = protowire.AppendTag(data, 4, protowire.BytesType)
+	data = protowire.AppendString(data, "")
+	data = protowire.AppendTag(data, 5, protowire.BytesType)
+	data = protowire.AppendBytes(data, ts)
+
+	var actual WriteRequestV2
+	require.NoError(t, actual.Unmarshal(data))
+	require.Equal(t, WriteRequestV2{Symbols: []string{""}, Timeseries: []TimeSeriesV2{{LabelsRefs: []uint32{1, 2}}}}, actual)
+}
+
+func TestWriteRequestV2_Unmarshal_Invalid(t *testing.T) {
+	var actual WriteRequestV2
+
+	// Truncated message.
+	require.Error(t, actual.Unmarshal([]byte{0x22, 0x05, 'a'}))
+
+	// Wrong wire type for the symbols.
+	actual.Reset()
+	require.ErrorContains(t, actual.Unmarshal(protowire.AppendVarint(protowire.AppendTag(nil, 4, protowire.VarintType), 1)), "wrong wireType")
+}
+
+func TestWriteRequestV2_ToWriteRequest(t *testing.T) {
+	req := &WriteRequestV2{
+		Symbols: []string{"", "__name__", "foo", "job", "test", "trace_id", "abc", "Foo help.", "seconds", "bar"},
+		Timeseries: []TimeSeriesV2{
+			{
+				LabelsRefs: []uint32{1, 2, 3, 4},
+				Samples:    []Sample{{Value: 1, TimestampMs: 1000}},
+				Exemplars:  []ExemplarV2{{LabelsRefs: []uint32{5, 6}, Value: 1, Timestamp: 1000}},
+				Metadata:   MetadataV2{Type: COUNTER, HelpRef: 7, UnitRef: 8},
+			},
+			{
+				LabelsRefs: []uint32{1, 2, 3, 9},
+				Samples:    []Sample{{Value: 2, TimestampMs: 1000}},
+				Metadata:   MetadataV2{Type: COUNTER, HelpRef: 7, UnitRef: 8},
+			},
+			{
+				LabelsRefs: []uint32{1, 9},
+				Histograms: []Histogram{{Count: &Histogram_CountInt{CountInt: 1}, Timestamp: 1000}},
+				Metadata:   MetadataV2{Type: HISTOGRAM},
+			},
+		},
+	}
+
+	var actual WriteRequest
+	require.NoError(t, req.ToWriteRequest(&actual, false))
+	t.Cleanup(func() { ReuseSlice(actual.Timeseries) })
+
+	require.Len(t, actual.Timeseries, 3)
+	require.Equal(t, []LabelAdapter{{Name: "__name__", Value: "foo"}, {Name: "job", Value: "test"}}, actual.Timeseries[0].Labels)
+	require.Equal(t, []Sample{{Value: 1, TimestampMs: 1000}}, actual.Timeseries[0].Samples)
+	require.Equal(t, []Exemplar{{Labels: []LabelAdapter{{Name: "trace_id", Value: "abc"}}, Value: 1, TimestampMs: 1000}}, actual.Timeseries[0].Exemplars)
+	require.Equal(t, []LabelAdapter{{Name: "__name__", Value: "foo"}, {Name: "job", Value: "bar"}}, actual.Timeseries[1].Labels)
+	require.Equal(t, []LabelAdapter{{Name: "__name__", Value: "bar"}}, actual.Timeseries[2].Labels)
+	require.Equal(t, []Histogram{{Count: &Histogram_CountInt{CountInt: 1}, Timestamp: 1000}}, actual.Timeseries[2].Histograms)
+
+	// The metadata is deduplicated by metric name.
+	require.Equal(t, []*MetricMetadata{
+		{Type: COUNTER, MetricFamilyName: "foo", Help: "Foo help.", Unit: "seconds"},
+		{Type: HISTOGRAM, MetricFamilyName: "bar"},
+	}, actual.Metadata)
+}
+
+func TestWriteRequestV2_ToWriteRequest_CreatedTimestamp(t *testing.T) {
+	intHistogram := Histogram{Count: &Histogram_CountInt{CountInt: 1}, ZeroCount: &Histogram_ZeroCountInt{}, Schema: 2, ZeroThreshold: 0.001, Timestamp: 1000}
+	floatHistogram := Histogram{Count: &Histogram_CountFloat{CountFloat: 1}, ZeroCount: &Histogram_ZeroCountFloat{}, Schema: 3, Timestamp: 1000}
+
+	req := &WriteRequestV2{
+		Symbols: []string{"", "__name__", "foo", "bar", "baz"},
+		Timeseries: []TimeSeriesV2{
+			{LabelsRefs: []uint32{1, 2}, Samples: []Sample{{Value: 1, TimestampMs: 1000}}, CreatedTimestamp: 500},
+			{LabelsRefs: []uint32{1, 3}, Histograms: []Histogram{intHistogram, floatHistogram}, CreatedTimestamp: 500},
+			// The created timestamp isn't earlier than the first sample.
+			{LabelsRefs: []uint32{1, 4}, Samples: []Sample{{Value: 1, TimestampMs: 1000}}, CreatedTimestamp: 1000},
+		},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		var actual WriteRequest
+		require.NoError(t, req.ToWriteRequest(&actual, false))
+		t.Cleanup(func() { ReuseSlice(actual.Timeseries) })
+
+		require.Equal(t, []Sample{{Value: 1, TimestampMs: 1000}}, actual.Timeseries[0].Samples)
+		require.Equal(t, []Histogram{intHistogram, floatHistogram}, actual.Timeseries[1].Histograms)
+		require.Equal(t, []Sample{{Value: 1, TimestampMs: 1000}}, actual.Timeseries[2].Samples)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		var actual WriteRequest
+		require.NoError(t, req.ToWriteRequest(&actual, true))
+		t.Cleanup(func() { ReuseSlice(actual.Timeseries) })
+
+		require.Equal(t, []Sample{{Value: 0, TimestampMs: 500}, {Value: 1, TimestampMs: 1000}}, actual.Timeseries[0].Samples)
+		require.Equal(t, []Histogram{
+			{Count: &Histogram_CountInt{}, ZeroCount: &Histogram_ZeroCountInt{}, Schema: 2, ZeroThreshold: 0.001, ResetHint: Histogram_YES, Timestamp: 500},
+			intHistogram,
+			floatHistogram,
+		}, actual.Timeseries[1].Histograms)
+		require.Equal(t, []Sample{{Value: 1, TimestampMs: 1000}}, actual.Timeseries[2].Samples)
+	})
+}
+
+func TestFromPrometheusTimeseriesToWriteRequestV2(t *testing.T) {
+	req := FromPrometheusTimeseriesToWriteRequestV2([]prompb.TimeSeries{
+		{
+			Labels:    []prompb.Label{{Name: "__name__", Value: "foo"}, {Name: "job", Value: "test"}},
+			Samples:   []prompb.Sample{{Value: 1, Timestamp: 1000}},
+			Exemplars: []prompb.Exemplar{{Labels: []prompb.Label{{Name: "trace_id", Value: "abc"}}, Value: 1, Timestamp: 1000}},
+		},
+		{
+			Labels: []prompb.Label{{Name: "__name__", Value: "bar"}, {Name: "job", Value: "test"}},
+			Histograms: []prompb.Histogram{{
+				Count:          &prompb.Histogram_CountFloat{CountFloat: 2},
+				ZeroCount:      &prompb.Histogram_ZeroCountFloat{ZeroCountFloat: 1},
+				Sum:            3,
+				Schema:         1,
+				PositiveSpans:  []prompb.BucketSpan{{Offset: 0, Length: 1}},
+				PositiveCounts: []float64{1},
+				Timestamp:      1000,
+			}},
+		},
+	}, []MetricMetadata{{Type: COUNTER, MetricFamilyName: "foo", Help: "Foo help."}})
+
+	require.Equal(t, &WriteRequestV2{
+		Symbols: []string{"", "__name__", "foo", "job", "test", "trace_id", "abc", "Foo help.", "bar"},
+		Timeseries: []TimeSeriesV2{
+			{
+				LabelsRefs: []uint32{1, 2, 3, 4},
+				Samples:    []Sample{{Value: 1, TimestampMs: 1000}},
+				Exemplars:  []ExemplarV2{{LabelsRefs: []uint32{5, 6}, Value: 1, Timestamp: 1000}},
+				Metadata:   MetadataV2{Type: COUNTER, HelpRef: 7},
+			},
+			{
+				LabelsRefs: []uint32{1, 8, 3, 4},
+				Histograms: []Histogram{{
+					Count:          &Histogram_CountFloat{CountFloat: 2},
+					ZeroCount:      &Histogram_ZeroCountFloat{ZeroCountFloat: 1},
+					Sum:            3,
+					Schema:         1,
+					PositiveSpans:  []BucketSpan{{Offset: 0, Length: 1}},
+					PositiveCounts: []float64{1},
+					Timestamp:      1000,
+				}},
+			},
+		},
+	}, req)
+}
+
+func TestWriteRequestV2_ToWriteRequest_InvalidReferences(t *testing.T) {
+	for name, series := range map[string]TimeSeriesV2{
+		"odd number of labels references":   {LabelsRefs: []uint32{1}},
+		"labels reference out of range":     {LabelsRefs: []uint32{1, 5}},
+		"exemplar reference out of range":   {LabelsRefs: []uint32{1, 1}, Exemplars: []ExemplarV2{{LabelsRefs: []uint32{1, 5}}}},
+		"metadata help reference out range": {LabelsRefs: []uint32{1, 1}, Metadata: MetadataV2{HelpRef: 5}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := &WriteRequestV2{Symbols: []string{"", "__name__"}, Timeseries: []TimeSeriesV2{series}}
+
+			var actual WriteRequest
+			require.ErrorContains(t, req.ToWriteRequest(&actual, false), "invalid remote-write 2.0 request")
+			ReuseSlice(actual.Timeseries)
+		})
+	}
+}
+
+func TestWriteRequestV2_ToWriteRequest_CustomBucketsHistograms(t *testing.T) {
+	req := &WriteRequestV2{Symbols: []string{"", "__name__", "foo"}, Timeseries: []TimeSeriesV2{{
+		LabelsRefs: []uint32{1, 2},
+		Histograms: []Histogram{{Schema: customBucketsHistogramSchema, Count: &Histogram_CountFloat{CountFloat: 1}, Timestamp: 1000}},
+	}}}
+
+	var actual WriteRequest
+	require.ErrorContains(t, req.ToWriteRequest(&actual, false), "native histograms with custom buckets")
+	ReuseSlice(actual.Timeseries)
+}
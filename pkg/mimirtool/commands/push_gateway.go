@@ -6,6 +6,7 @@
 package commands
 
 import (
+	"net/http"
 	"net/url"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 type PushGatewayConfig struct {
 	Endpoint *url.URL
 	JobName  string
+	TenantID string
 	Interval time.Duration
 
 	pusher     *push.Pusher
@@ -29,8 +31,9 @@ type PushGatewayConfig struct {
 // Register configures log related flags
 func (l *PushGatewayConfig) Register(app *kingpin.Application, _ EnvVarNames) {
 	app.PreAction(l.setup)
-	app.Flag("push-gateway.endpoint", "url for the push-gateway to register metrics").URLVar(&l.Endpoint)
+	app.Flag("push-gateway.endpoint", "url for the push-gateway to register metrics, or url of the Grafana Mimir pushgateway endpoint, for example http://mimir/api/v1/push/pushgateway").URLVar(&l.Endpoint)
 	app.Flag("push-gateway.job", "job name to register metrics").StringVar(&l.JobName)
+	app.Flag("push-gateway.tenant-id", "tenant ID sent in the X-Scope-OrgID header, required when pushing metrics to Grafana Mimir").StringVar(&l.TenantID)
 	app.Flag("push-gateway.interval", "interval to forward metrics to the push gateway").Default("1m").DurationVar(&l.Interval)
 }
 
@@ -47,6 +50,9 @@ func (l *PushGatewayConfig) setup(_ *kingpin.ParseContext) error {
 	}).Debugln("push-gateway enabled")
 
 	l.pusher = push.New(l.Endpoint.String(), l.JobName).Gatherer(prometheus.DefaultGatherer)
+	if l.TenantID != "" {
+		l.pusher = l.pusher.Header(http.Header{"X-Scope-OrgID": []string{l.TenantID}})
+	}
 	err := l.pusher.Push()
 	if err != nil {
 		logrus.WithError(err).Errorln("unable to forward metrics to pushgateway")
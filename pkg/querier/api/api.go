@@ -36,6 +36,16 @@ type LabelNamesCardinalityItem struct {
 	LabelValuesCount int    `json:"label_values_count"`
 }
 
+type CardinalityHistoryResponse struct {
+	Samples []CardinalityHistorySample `json:"samples"`
+}
+
+type CardinalityHistorySample struct {
+	Timestamp        int64                   `json:"timestamp"`
+	SeriesCountTotal uint64                  `json:"series_count_total"`
+	Labels           []LabelNamesCardinality `json:"labels"`
+}
+
 type ActiveSeriesResponse struct {
 	Data []labels.Labels `json:"data"`
 }
@@ -158,6 +158,9 @@ type BlocksStoreQueryable struct {
 	limits                   BlocksStoreLimits
 	streamingChunksBatchSize uint64
 
+	// Loads the series deletion requests to apply at query time. Nil if series deletion is disabled.
+	seriesDeletionsLoader *seriesDeletionsLoader
+
 	// Subservices manager.
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
@@ -264,7 +267,24 @@ func NewBlocksStoreQueryableFromConfig(querierCfg Config, gatewayCfg storegatewa
 		streamingBufferSize = 0
 	}
 
-	return NewBlocksStoreQueryable(stores, finder, consistency, limits, querierCfg.QueryStoreAfter, streamingBufferSize, logger, reg)
+	q, err := NewBlocksStoreQueryable(stores, finder, consistency, limits, querierCfg.QueryStoreAfter, streamingBufferSize, logger, reg)
+	if err != nil {
+		return nil, err
+	}
+
+	if querierCfg.SeriesDeletionEnabled {
+		q.seriesDeletionsLoader = newSeriesDeletionsLoader(bucketClient, logger)
+	}
+
+	return q, nil
+}
+
+// seriesDeletions implements seriesDeletionsGetter.
+func (q *BlocksStoreQueryable) seriesDeletions(ctx context.Context, userID string, minT, maxT int64) ([]seriesDeletion, error) {
+	if q.seriesDeletionsLoader == nil {
+		return nil, nil
+	}
+	return q.seriesDeletionsLoader.seriesDeletions(ctx, userID, minT, maxT)
 }
 
 func (q *BlocksStoreQueryable) starting(ctx context.Context) error {
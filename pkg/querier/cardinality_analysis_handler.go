@@ -83,6 +83,37 @@ func LabelValuesCardinalityHandler(distributor Distributor, limits *validation.O
 	})
 }
 
+// CardinalityHistoryHandler creates handler for cardinality history endpoint.
+func CardinalityHistoryHandler(d Distributor, limits *validation.Overrides) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		// Guarantee request's context is for a single tenant id
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !limits.CardinalityAnalysisEnabled(tenantID) {
+			http.Error(w, fmt.Sprintf("cardinality analysis is disabled for the tenant: %v", tenantID), http.StatusBadRequest)
+			return
+		}
+
+		req, err := cardinality.DecodeHistoryRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		res, err := d.CardinalityHistory(ctx, req.Start, req.End)
+		if err != nil {
+			respondFromError(err, w)
+			return
+		}
+
+		util.WriteJSONResponse(w, toCardinalityHistoryResponse(res, req.Limit))
+	})
+}
+
 func ActiveSeriesCardinalityHandler(d Distributor, limits *validation.Overrides) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -252,6 +283,22 @@ func toLabelValuesCardinalityResponse(seriesCountTotal uint64, cardinalityRespon
 	}
 }
 
+func toCardinalityHistoryResponse(historyResponse *ingester_client.CardinalityHistoryResponse, limit int) *api.CardinalityHistoryResponse {
+	samples := make([]api.CardinalityHistorySample, 0, len(historyResponse.Samples))
+
+	for _, sample := range historyResponse.Samples {
+		labelValuesResponse := toLabelValuesCardinalityResponse(sample.NumSeries, &ingester_client.LabelValuesCardinalityResponse{Items: sample.Items}, limit)
+
+		samples = append(samples, api.CardinalityHistorySample{
+			Timestamp:        sample.TimestampMs,
+			SeriesCountTotal: labelValuesResponse.SeriesCountTotal,
+			Labels:           labelValuesResponse.Labels,
+		})
+	}
+
+	return &api.CardinalityHistoryResponse{Samples: samples}
+}
+
 // sortByLabelValuesSeriesCountAndLabelName sorts api.LabelNamesCardinality array in DESC order by SeriesCount and
 // ASC order by LabelName
 func sortByLabelValuesSeriesCountAndLabelName(labelNamesCardinality []api.LabelNamesCardinality) []api.LabelNamesCardinality {
@@ -789,6 +789,122 @@ func TestLabelValuesCardinalityHandler_DistributorError(t *testing.T) {
 	}
 }
 
+func TestCardinalityHistoryHandler(t *testing.T) {
+	historyResponse := &client.CardinalityHistoryResponse{Samples: []*client.CardinalityHistorySample{
+		{
+			TimestampMs: 1000,
+			NumSeries:   10,
+			Items: []*client.LabelValueSeriesCount{
+				{LabelName: labels.MetricName, LabelValueSeries: map[string]uint64{"metric_0": 6, "metric_1": 3}},
+				{LabelName: "job", LabelValueSeries: map[string]uint64{"job-a": 8}},
+			},
+		},
+		{
+			TimestampMs: 2000,
+			NumSeries:   12,
+			Items: []*client.LabelValueSeriesCount{
+				{LabelName: labels.MetricName, LabelValueSeries: map[string]uint64{"metric_0": 7, "metric_1": 5}},
+			},
+		},
+	}}
+
+	tests := map[string]struct {
+		url                string
+		expectedStart      int64
+		expectedEnd        int64
+		expectedStatusCode int
+		expectedResponse   *api.CardinalityHistoryResponse
+		expectedError      string
+	}{
+		"should return all samples": {
+			url:                "/history",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &api.CardinalityHistoryResponse{Samples: []api.CardinalityHistorySample{
+				{
+					Timestamp:        1000,
+					SeriesCountTotal: 10,
+					Labels: []api.LabelNamesCardinality{
+						{LabelName: labels.MetricName, LabelValuesCount: 2, SeriesCount: 9, Cardinality: []api.LabelValuesCardinality{{LabelValue: "metric_0", SeriesCount: 6}, {LabelValue: "metric_1", SeriesCount: 3}}},
+						{LabelName: "job", LabelValuesCount: 1, SeriesCount: 8, Cardinality: []api.LabelValuesCardinality{{LabelValue: "job-a", SeriesCount: 8}}},
+					},
+				},
+				{
+					Timestamp:        2000,
+					SeriesCountTotal: 12,
+					Labels: []api.LabelNamesCardinality{
+						{LabelName: labels.MetricName, LabelValuesCount: 2, SeriesCount: 12, Cardinality: []api.LabelValuesCardinality{{LabelValue: "metric_0", SeriesCount: 7}, {LabelValue: "metric_1", SeriesCount: 5}}},
+					},
+				},
+			}},
+		},
+		"should pass the time range and apply the limit": {
+			url:                "/history?start=1&end=2.5&limit=1",
+			expectedStart:      1000,
+			expectedEnd:        2500,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: &api.CardinalityHistoryResponse{Samples: []api.CardinalityHistorySample{
+				{
+					Timestamp:        1000,
+					SeriesCountTotal: 10,
+					Labels: []api.LabelNamesCardinality{
+						{LabelName: labels.MetricName, LabelValuesCount: 2, SeriesCount: 9, Cardinality: []api.LabelValuesCardinality{{LabelValue: "metric_0", SeriesCount: 6}}},
+						{LabelName: "job", LabelValuesCount: 1, SeriesCount: 8, Cardinality: []api.LabelValuesCardinality{{LabelValue: "job-a", SeriesCount: 8}}},
+					},
+				},
+				{
+					Timestamp:        2000,
+					SeriesCountTotal: 12,
+					Labels: []api.LabelNamesCardinality{
+						{LabelName: labels.MetricName, LabelValuesCount: 2, SeriesCount: 12, Cardinality: []api.LabelValuesCardinality{{LabelValue: "metric_0", SeriesCount: 7}}},
+					},
+				},
+			}},
+		},
+		"should return bad request if the start param is invalid": {
+			url:                "/history?start=foo",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "invalid 'start' param",
+		},
+		"should return bad request if the end param is before the start param": {
+			url:                "/history?start=2&end=1",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "'end' param cannot be before 'start' param",
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			distributor := &mockDistributor{}
+			distributor.On("CardinalityHistory", mock.Anything, testData.expectedStart, testData.expectedEnd).Return(historyResponse, nil)
+			handler := createEnabledHandler(t, CardinalityHistoryHandler, distributor)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, createRequest(testData.url, "test"))
+			require.Equal(t, testData.expectedStatusCode, recorder.Result().StatusCode)
+
+			body, err := io.ReadAll(recorder.Result().Body)
+			require.NoError(t, err)
+			if testData.expectedError != "" {
+				require.Contains(t, string(body), testData.expectedError)
+				return
+			}
+
+			response := &api.CardinalityHistoryResponse{}
+			require.NoError(t, json.Unmarshal(body, response))
+			require.Equal(t, testData.expectedResponse, response)
+		})
+	}
+
+	t.Run("should return bad request if cardinality analysis is disabled", func(t *testing.T) {
+		overrides, err := validation.NewOverrides(validation.Limits{CardinalityAnalysisEnabled: false}, nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		CardinalityHistoryHandler(&mockDistributor{}, overrides).ServeHTTP(recorder, createRequest("/history", "test"))
+		require.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+	})
+}
+
 func TestActiveSeriesCardinalityHandler(t *testing.T) {
 	tests := []struct {
 		name                 string
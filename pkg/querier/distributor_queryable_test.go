@@ -706,6 +706,11 @@ func (m *mockDistributor) LabelValuesCardinality(ctx context.Context, labelNames
 	return args.Get(0).(uint64), args.Get(1).(*client.LabelValuesCardinalityResponse), args.Error(2)
 }
 
+func (m *mockDistributor) CardinalityHistory(ctx context.Context, start, end int64) (*client.CardinalityHistoryResponse, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).(*client.CardinalityHistoryResponse), args.Error(1)
+}
+
 func (m *mockDistributor) ActiveSeries(ctx context.Context, matchers []*labels.Matcher) ([]labels.Labels, error) {
 	args := m.Called(ctx, matchers)
 	return args.Get(0).([]labels.Labels), args.Error(1)
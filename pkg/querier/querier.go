@@ -10,6 +10,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
 	"github.com/prometheus/prometheus/util/annotations"
 	"golang.org/x/sync/errgroup"
 
@@ -60,6 +62,9 @@ type Config struct {
 	QueryEngine               string `yaml:"query_engine" category:"experimental"`
 	EnableQueryEngineFallback bool   `yaml:"enable_query_engine_fallback" category:"experimental"`
 
+	SeriesDeletionEnabled    bool `yaml:"series_deletion_enabled" category:"experimental"`
+	FilterFullyDeletedSeries bool `yaml:"filter_fully_deleted_series" category:"experimental"`
+
 	// PromQL engine config.
 	EngineConfig engine.Config `yaml:",inline"`
 }
@@ -91,6 +96,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 
 	f.StringVar(&cfg.QueryEngine, "querier.query-engine", prometheusEngine, fmt.Sprintf("Query engine to use, either '%v' or '%v'", prometheusEngine, mimirEngine))
 	f.BoolVar(&cfg.EnableQueryEngineFallback, "querier.enable-query-engine-fallback", true, "If set to true and the Mimir query engine is in use, fall back to using the Prometheus query engine for any queries not supported by the Mimir query engine.")
+	f.BoolVar(&cfg.SeriesDeletionEnabled, "querier.series-deletion-enabled", false, "If true, queriers filter out the samples deleted by series deletion requests from the results of queries to ingesters and store-gateways. Series deletion requests are created with the compactor series deletion API, and reloaded every minute.")
+	f.BoolVar(&cfg.FilterFullyDeletedSeries, "querier.filter-fully-deleted-series", true, "If true, queriers filter out the series whose samples within the queried time range have all been deleted by series deletion requests or expired by per-series retention rules from the results of series, label names, and label values queries. Finding these series requires fetching the samples of the series matching the deletions overlapping the queried time range, which count towards the query limits.")
 
 	cfg.EngineConfig.RegisterFlags(f)
 }
@@ -158,11 +165,16 @@ func New(cfg Config, limits *validation.Overrides, distributor Distributor, stor
 	// Experimental functions can only be enabled globally, and not on a per-engine basis.
 	parser.EnableExperimentalFunctions = engineExperimentalFunctionsEnabled
 
+	// Prometheus' engine only tracks the samples processed at each step when a query is run with the stats=all
+	// parameter, so it's safe to always enable it. Per-step stats are not supported by the Mimir query engine.
+	prometheusOpts := opts
+	prometheusOpts.EnablePerStepStats = true
+
 	var eng promql.QueryEngine
 
 	switch cfg.QueryEngine {
 	case prometheusEngine:
-		eng = promql.NewEngine(opts)
+		eng = promql.NewEngine(prometheusOpts)
 	case mimirEngine:
 		limitsProvider := &tenantQueryLimitsProvider{limits: limits}
 		streamingEngine, err := streamingpromql.NewEngine(opts, limitsProvider, queryMetrics, logger)
@@ -171,7 +183,7 @@ func New(cfg Config, limits *validation.Overrides, distributor Distributor, stor
 		}
 
 		if cfg.EnableQueryEngineFallback {
-			prometheusEngine := promql.NewEngine(opts)
+			prometheusEngine := promql.NewEngine(prometheusOpts)
 			eng = compat.NewEngineWithFallback(streamingEngine, prometheusEngine, reg, logger)
 		} else {
 			eng = streamingEngine
@@ -217,10 +229,13 @@ func newQueryable(
 	queryMetrics *stats.QueryMetrics,
 	logger log.Logger,
 ) storage.Queryable {
+	deletions, _ := blockStore.(seriesDeletionsGetter)
+
 	return storage.QueryableFunc(func(minT, maxT int64) (storage.Querier, error) {
 		return multiQuerier{
 			distributor:        distributor,
 			blockStore:         blockStore,
+			deletions:          deletions,
 			queryMetrics:       queryMetrics,
 			cfg:                cfg,
 			minT:               minT,
@@ -237,6 +252,7 @@ func newQueryable(
 type multiQuerier struct {
 	distributor  storage.Queryable
 	blockStore   storage.Queryable
+	deletions    seriesDeletionsGetter
 	queryMetrics *stats.QueryMetrics
 	cfg          Config
 	minT, maxT   int64
@@ -357,6 +373,29 @@ func (mq multiQuerier) Select(ctx context.Context, _ bool, sp *storage.SelectHin
 		return storage.ErrSeriesSet(NewMaxQueryLengthError(endTime.Sub(startTime), maxQueryLength))
 	}
 
+	// Filter out the samples expired by per-series retention rules and deleted by series deletion requests,
+	// which may have not been deleted from the storage yet.
+	deletions, err := mq.seriesDeletions(ctx, userID, now, startMs, endMs)
+	if err != nil {
+		return storage.ErrSeriesSet(err)
+	}
+
+	set := newSeriesDeletionsSeriesSet(mq.selectSeries(ctx, queriers, sp, matchers), deletions)
+	if sp.Func != "series" || !mq.cfg.FilterFullyDeletedSeries || len(deletions) == 0 {
+		return set
+	}
+
+	// Series-only queries don't fetch any sample, so we need to find out which series have all their samples
+	// deleted within the queried time range, to filter them out.
+	deleted, err := mq.fullyDeletedSeries(ctx, queriers, sp, matchers, deletions)
+	if err != nil {
+		return storage.ErrSeriesSet(err)
+	}
+	return newFullyDeletedSeriesFilterSeriesSet(set, deleted)
+}
+
+// selectSeries selects the series matching the input matchers from all queriers, and merges them.
+func (mq multiQuerier) selectSeries(ctx context.Context, queriers []storage.Querier, sp *storage.SelectHints, matchers []*labels.Matcher) storage.SeriesSet {
 	if len(queriers) == 1 {
 		return queriers[0].Select(ctx, true, sp, matchers...)
 	}
@@ -384,6 +423,69 @@ func (mq multiQuerier) Select(ctx context.Context, _ bool, sp *storage.SelectHin
 	return mq.mergeSeriesSets(result)
 }
 
+// seriesDeletions returns the deletions of the samples expired by per-series retention rules and deleted by series
+// deletion requests of the tenant, overlapping the [minT, maxT] time range.
+func (mq multiQuerier) seriesDeletions(ctx context.Context, userID string, now time.Time, minT, maxT int64) ([]seriesDeletion, error) {
+	deletions, err := seriesRetentionDeletions(mq.limits.CompactorSeriesRetentionRules(userID), now, minT)
+	if err != nil {
+		return nil, err
+	}
+	if mq.deletions != nil {
+		requested, err := mq.deletions.seriesDeletions(ctx, userID, minT, maxT)
+		if err != nil {
+			return nil, err
+		}
+		deletions = append(deletions, requested...)
+	}
+	return deletions, nil
+}
+
+// fullyDeletedSeries returns the series matching the input matchers whose samples within the hints time range
+// have all been deleted by the input deletions. Only the samples of the series matching a deletion overlapping
+// the hints time range are fetched.
+func (mq multiQuerier) fullyDeletedSeries(ctx context.Context, queriers []storage.Querier, sp *storage.SelectHints, matchers []*labels.Matcher, deletions []seriesDeletion) (fullyDeletedSeries, error) {
+	// Make a copy, to avoid changing shared SelectHints.
+	samplesHints := *sp
+	samplesHints.Func = ""
+
+	var (
+		deleted = fullyDeletedSeries{}
+		checked = map[string]struct{}{}
+		it      chunkenc.Iterator
+	)
+
+	for _, d := range deletions {
+		// The series not matching any deletion of samples within the time range can't have been fully deleted.
+		if !d.overlaps(sp.Start, sp.End) || !d.mayMatch(matchers) {
+			continue
+		}
+
+		set := newSeriesDeletionsSeriesSet(mq.selectSeries(ctx, queriers, &samplesHints, append(slices.Clone(matchers), d.matchers...)), deletions)
+
+		for set.Next() {
+			series := set.At()
+			key := series.Labels().String()
+			if _, ok := checked[key]; ok {
+				continue
+			}
+			checked[key] = struct{}{}
+
+			it = series.Iterator(it)
+			if it.Seek(sp.Start) == chunkenc.ValNone || it.AtT() > sp.End {
+				deleted[key] = series.Labels()
+			}
+			if err := it.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if err := set.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return deleted, nil
+}
+
 // LabelValues implements storage.Querier.
 func (mq multiQuerier) LabelValues(ctx context.Context, name string, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
 	ctx, queriers, err := mq.getQueriers(ctx)
@@ -394,6 +496,21 @@ func (mq multiQuerier) LabelValues(ctx context.Context, name string, matchers ..
 		return nil, nil, err
 	}
 
+	values, warnings, err := mq.labelValues(ctx, queriers, name, matchers)
+	if err != nil || len(values) == 0 {
+		return values, warnings, err
+	}
+
+	deleted, err := mq.fullyDeletedSeriesForLabels(ctx, queriers, matchers)
+	if err != nil || len(deleted) == 0 {
+		return values, warnings, err
+	}
+
+	values, err = mq.filterFullyDeletedLabelValues(ctx, queriers, name, values, matchers, deleted)
+	return values, warnings, err
+}
+
+func (mq multiQuerier) labelValues(ctx context.Context, queriers []storage.Querier, name string, matchers []*labels.Matcher) ([]string, annotations.Annotations, error) {
 	if len(queriers) == 1 {
 		return queriers[0].LabelValues(ctx, name, matchers...)
 	}
@@ -441,6 +558,37 @@ func (mq multiQuerier) LabelNames(ctx context.Context, matchers ...*labels.Match
 		return nil, nil, err
 	}
 
+	names, warnings, err := mq.labelNames(ctx, queriers, matchers)
+	if err != nil || len(names) == 0 {
+		return names, warnings, err
+	}
+
+	deleted, err := mq.fullyDeletedSeriesForLabels(ctx, queriers, matchers)
+	if err != nil || len(deleted) == 0 {
+		return names, warnings, err
+	}
+
+	// A label name is filtered out if it's only found in series whose samples have all been deleted.
+	filtered := make([]string, 0, len(names))
+	for _, name := range names {
+		if !deleted.hasLabel(name) {
+			filtered = append(filtered, name)
+			continue
+		}
+
+		kept, err := mq.hasNotFullyDeletedSeries(ctx, queriers, append(slices.Clone(matchers), labels.MustNewMatcher(labels.MatchNotEqual, name, "")), deleted)
+		if err != nil {
+			return nil, nil, err
+		}
+		if kept {
+			filtered = append(filtered, name)
+		}
+	}
+
+	return filtered, warnings, nil
+}
+
+func (mq multiQuerier) labelNames(ctx context.Context, queriers []storage.Querier, matchers []*labels.Matcher) ([]string, annotations.Annotations, error) {
 	if len(queriers) == 1 {
 		return queriers[0].LabelNames(ctx, matchers...)
 	}
@@ -479,6 +627,65 @@ func (mq multiQuerier) LabelNames(ctx context.Context, matchers ...*labels.Match
 	return util.MergeSlices(sets...), warnings, nil
 }
 
+// fullyDeletedSeriesForLabels returns the series matching the input matchers whose samples within the querier
+// time range have all been deleted, for the label names and values queries.
+func (mq multiQuerier) fullyDeletedSeriesForLabels(ctx context.Context, queriers []storage.Querier, matchers []*labels.Matcher) (fullyDeletedSeries, error) {
+	if !mq.cfg.FilterFullyDeletedSeries {
+		return nil, nil
+	}
+
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deletions, err := mq.seriesDeletions(ctx, userID, time.Now(), mq.minT, mq.maxT)
+	if err != nil || len(deletions) == 0 {
+		return nil, err
+	}
+
+	return mq.fullyDeletedSeries(ctx, queriers, &storage.SelectHints{Start: mq.minT, End: mq.maxT}, matchers, deletions)
+}
+
+// filterFullyDeletedLabelValues filters out the input values of the label name which are only found in
+// series whose samples have all been deleted.
+func (mq multiQuerier) filterFullyDeletedLabelValues(ctx context.Context, queriers []storage.Querier, name string, values []string, matchers []*labels.Matcher, deleted fullyDeletedSeries) ([]string, error) {
+	candidates := deleted.labelValues(name)
+	if len(candidates) == 0 {
+		return values, nil
+	}
+
+	filtered := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := candidates[value]; !ok {
+			filtered = append(filtered, value)
+			continue
+		}
+
+		kept, err := mq.hasNotFullyDeletedSeries(ctx, queriers, append(slices.Clone(matchers), labels.MustNewMatcher(labels.MatchEqual, name, value)), deleted)
+		if err != nil {
+			return nil, err
+		}
+		if kept {
+			filtered = append(filtered, value)
+		}
+	}
+
+	return filtered, nil
+}
+
+// hasNotFullyDeletedSeries returns whether a series matching the input matchers within the querier time range
+// hasn't been fully deleted. Only the series labels are fetched.
+func (mq multiQuerier) hasNotFullyDeletedSeries(ctx context.Context, queriers []storage.Querier, matchers []*labels.Matcher, deleted fullyDeletedSeries) (bool, error) {
+	set := mq.selectSeries(ctx, queriers, &storage.SelectHints{Start: mq.minT, End: mq.maxT, Func: "series"}, matchers)
+	for set.Next() {
+		if !deleted.contains(set.At().Labels()) {
+			return true, nil
+		}
+	}
+	return false, set.Err()
+}
+
 func (multiQuerier) Close() error {
 	return nil
 }
@@ -637,3 +844,12 @@ func (p *tenantQueryLimitsProvider) GetMaxEstimatedMemoryConsumptionPerQuery(ctx
 
 	return p.limits.MaxEstimatedMemoryConsumptionPerQuery(tenantID), nil
 }
+
+func (p *tenantQueryLimitsProvider) GetMaxConcurrencyPerQuery(ctx context.Context) (int, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return p.limits.MaxConcurrencyPerQuery(tenantID), nil
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/storage/chunk"
+	"github.com/grafana/mimir/pkg/storage/series"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/spanlogger"
 	"github.com/grafana/mimir/pkg/util/test"
@@ -1160,6 +1161,10 @@ func (m *errDistributor) LabelValuesCardinality(context.Context, []model.LabelNa
 	return 0, nil, errDistributorError
 }
 
+func (m *errDistributor) CardinalityHistory(context.Context, int64, int64) (*client.CardinalityHistoryResponse, error) {
+	return nil, errDistributorError
+}
+
 func (m *errDistributor) ActiveSeries(context.Context, []*labels.Matcher) ([]labels.Labels, error) {
 	return nil, errDistributorError
 }
@@ -1202,6 +1207,10 @@ func (d *emptyDistributor) LabelValuesCardinality(context.Context, []model.Label
 	return 0, nil, nil
 }
 
+func (d *emptyDistributor) CardinalityHistory(context.Context, int64, int64) (*client.CardinalityHistoryResponse, error) {
+	return &client.CardinalityHistoryResponse{}, nil
+}
+
 func (d *emptyDistributor) ActiveSeries(context.Context, []*labels.Matcher) ([]labels.Labels, error) {
 	return nil, nil
 }
@@ -1308,6 +1317,56 @@ func TestQuerier_QueryStoreAfterConfig(t *testing.T) {
 	}
 }
 
+func TestQuerier_SeriesRetentionRules(t *testing.T) {
+	now := time.Now()
+	samples := []model.SamplePair{
+		{Timestamp: model.TimeFromUnixNano(now.Add(-3 * time.Hour).UnixNano()), Value: 1},
+		{Timestamp: model.TimeFromUnixNano(now.Add(-2 * time.Hour).UnixNano()), Value: 2},
+		{Timestamp: model.TimeFromUnixNano(now.Add(-30 * time.Minute).UnixNano()), Value: 3},
+	}
+
+	querier := &mockBlocksStorageQuerier{}
+	querier.On("Select", mock.Anything, true, mock.Anything, mock.Anything).Return(series.NewConcreteSeriesSetFromUnsortedSeries([]storage.Series{
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "debug_metric"), samples, nil),
+		series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "up"), samples, nil),
+	}))
+
+	cfg := Config{}
+	flagext.DefaultValues(&cfg)
+
+	limits := defaultLimitsConfig()
+	limits.CompactorSeriesRetentionRules = []*validation.SeriesRetentionRule{{Selector: `{__name__=~"debug_.*"}`, Period: model.Duration(time.Hour)}}
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	queryable, _, _, err := New(cfg, overrides, &emptyDistributor{}, newMockBlocksStorageQueryable(querier), nil, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+
+	// Query a time range large enough for the blocks storage to be queried, given -querier.query-store-after.
+	ctx := user.InjectOrgID(context.Background(), "0")
+	q, err := queryable.Querier(now.Add(-24*time.Hour).UnixMilli(), now.UnixMilli())
+	require.NoError(t, err)
+
+	set := q.Select(ctx, true, &storage.SelectHints{Start: now.Add(-24 * time.Hour).UnixMilli(), End: now.UnixMilli()}, labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".+"))
+
+	actual := map[string][]float64{}
+	for set.Next() {
+		it := set.At().Iterator(nil)
+		for it.Next() != chunkenc.ValNone {
+			_, v := it.At()
+			actual[set.At().Labels().Get(labels.MetricName)] = append(actual[set.At().Labels().Get(labels.MetricName)], v)
+		}
+		require.NoError(t, it.Err())
+	}
+	require.NoError(t, set.Err())
+
+	// The samples of the series matching the retention rule older than the rule period are filtered out.
+	require.Equal(t, map[string][]float64{
+		"debug_metric": {3},
+		"up":           {1, 2, 3},
+	}, actual)
+}
+
 func TestConfig_ValidateLimits(t *testing.T) {
 	tests := map[string]struct {
 		setup    func(cfg *Config, limits *validation.Limits)
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/tombstones"
+	"github.com/thanos-io/objstore"
+
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+// seriesDeletionsRefreshInterval is how frequently the series deletion requests of a tenant are reloaded from the bucket.
+const seriesDeletionsRefreshInterval = time.Minute
+
+// seriesDeletion is a single series selector of a series deletion request.
+type seriesDeletion struct {
+	matchers []*labels.Matcher
+	interval tombstones.Interval
+}
+
+func (d seriesDeletion) matches(lbls labels.Labels) bool {
+	for _, m := range d.matchers {
+		if !m.Matches(lbls.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps returns whether the deletion deletes samples within the [minT, maxT] time range.
+func (d seriesDeletion) overlaps(minT, maxT int64) bool {
+	return d.interval.Mint <= maxT && minT <= d.interval.Maxt
+}
+
+// mayMatch returns whether the deletion may match series matching the input matchers too. It only returns false
+// when an equality matcher of either the deletion or the input matchers doesn't match a matcher of the other one.
+func (d seriesDeletion) mayMatch(matchers []*labels.Matcher) bool {
+	return !conflictingMatchers(d.matchers, matchers) && !conflictingMatchers(matchers, d.matchers)
+}
+
+// conflictingMatchers returns whether the value of an equality matcher in a isn't matched by a matcher in b.
+func conflictingMatchers(a, b []*labels.Matcher) bool {
+	for _, ma := range a {
+		if ma.Type != labels.MatchEqual {
+			continue
+		}
+		for _, mb := range b {
+			if mb.Name == ma.Name && !mb.Matches(ma.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// seriesDeletionsGetter is implemented by queryables providing the series deletions of a tenant.
+type seriesDeletionsGetter interface {
+	// seriesDeletions returns the series deletions of the tenant overlapping the [minT, maxT] time range.
+	seriesDeletions(ctx context.Context, userID string, minT, maxT int64) ([]seriesDeletion, error)
+}
+
+// seriesDeletionsLoader loads the series deletion requests of tenants from the bucket, and caches them
+// for seriesDeletionsRefreshInterval.
+type seriesDeletionsLoader struct {
+	bkt    objstore.BucketReader
+	logger log.Logger
+
+	mtx     sync.Mutex
+	tenants map[string]*tenantSeriesDeletions
+}
+
+type tenantSeriesDeletions struct {
+	loadedAt  time.Time
+	deletions []seriesDeletion
+}
+
+func newSeriesDeletionsLoader(bkt objstore.BucketReader, logger log.Logger) *seriesDeletionsLoader {
+	return &seriesDeletionsLoader{
+		bkt:     bkt,
+		logger:  logger,
+		tenants: map[string]*tenantSeriesDeletions{},
+	}
+}
+
+func (l *seriesDeletionsLoader) seriesDeletions(ctx context.Context, userID string, minT, maxT int64) ([]seriesDeletion, error) {
+	all, err := l.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlapping []seriesDeletion
+	for _, d := range all {
+		if d.overlaps(minT, maxT) {
+			overlapping = append(overlapping, d)
+		}
+	}
+	return overlapping, nil
+}
+
+func (l *seriesDeletionsLoader) load(ctx context.Context, userID string) ([]seriesDeletion, error) {
+	l.mtx.Lock()
+	cached := l.tenants[userID]
+	l.mtx.Unlock()
+
+	if cached != nil && time.Since(cached.loadedAt) < seriesDeletionsRefreshInterval {
+		return cached.deletions, nil
+	}
+
+	deletions, err := l.fetch(ctx, userID)
+	if err != nil {
+		// Prefer stale series deletions over failing the query.
+		if cached != nil {
+			level.Warn(l.logger).Log("msg", "failed to reload series deletion requests, using previously loaded ones", "user", userID, "err", err)
+			return cached.deletions, nil
+		}
+		return nil, err
+	}
+
+	l.mtx.Lock()
+	l.tenants[userID] = &tenantSeriesDeletions{loadedAt: time.Now(), deletions: deletions}
+	l.mtx.Unlock()
+
+	return deletions, nil
+}
+
+func (l *seriesDeletionsLoader) fetch(ctx context.Context, userID string) ([]seriesDeletion, error) {
+	requests, err := mimir_tsdb.ReadSeriesDeletionRequests(ctx, l.bkt, userID, l.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var deletions []seriesDeletion
+	for _, r := range requests {
+		matchers, err := r.Matchers()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ms := range matchers {
+			deletions = append(deletions, seriesDeletion{
+				matchers: ms,
+				interval: tombstones.Interval{Mint: r.StartTime, Maxt: r.EndTime},
+			})
+		}
+	}
+
+	return deletions, nil
+}
+
+// seriesRetentionDeletions returns the series deletions of the samples expired by the input per-series retention rules,
+// for a query starting at minT.
+func seriesRetentionDeletions(rules []*validation.SeriesRetentionRule, now time.Time, minT int64) ([]seriesDeletion, error) {
+	var deletions []seriesDeletion
+	for _, r := range rules {
+		cutoff := r.Cutoff(now)
+		if cutoff <= minT {
+			continue
+		}
+
+		matchers, err := r.Matchers()
+		if err != nil {
+			return nil, err
+		}
+
+		deletions = append(deletions, seriesDeletion{
+			matchers: matchers,
+			interval: tombstones.Interval{Mint: math.MinInt64, Maxt: cutoff - 1},
+		})
+	}
+	return deletions, nil
+}
+
+// newSeriesDeletionsSeriesSet returns a SeriesSet filtering out the samples deleted by the input series deletions.
+func newSeriesDeletionsSeriesSet(set storage.SeriesSet, deletions []seriesDeletion) storage.SeriesSet {
+	if len(deletions) == 0 {
+		return set
+	}
+	return &seriesDeletionsSeriesSet{SeriesSet: set, deletions: deletions}
+}
+
+type seriesDeletionsSeriesSet struct {
+	storage.SeriesSet
+	deletions []seriesDeletion
+}
+
+func (s *seriesDeletionsSeriesSet) At() storage.Series {
+	series := s.SeriesSet.At()
+	lbls := series.Labels()
+
+	var intervals tombstones.Intervals
+	for _, d := range s.deletions {
+		if d.matches(lbls) {
+			intervals = intervals.Add(d.interval)
+		}
+	}
+
+	if len(intervals) == 0 {
+		return series
+	}
+	return &seriesWithDeletedSamples{Series: series, intervals: intervals}
+}
+
+type seriesWithDeletedSamples struct {
+	storage.Series
+	intervals tombstones.Intervals
+}
+
+func (s *seriesWithDeletedSamples) Iterator(it chunkenc.Iterator) chunkenc.Iterator {
+	if deletedIt, ok := it.(*tsdb.DeletedIterator); ok {
+		deletedIt.Iter = s.Series.Iterator(deletedIt.Iter)
+		deletedIt.Intervals = s.intervals
+		return deletedIt
+	}
+	return &tsdb.DeletedIterator{Iter: s.Series.Iterator(it), Intervals: s.intervals}
+}
+
+// fullyDeletedSeries is the set of series whose samples within a queried time range have all been deleted,
+// keyed by their labels string.
+type fullyDeletedSeries map[string]labels.Labels
+
+func (s fullyDeletedSeries) contains(lbls labels.Labels) bool {
+	_, ok := s[lbls.String()]
+	return ok
+}
+
+func (s fullyDeletedSeries) hasLabel(name string) bool {
+	for _, lbls := range s {
+		if lbls.Has(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// labelValues returns the values of the label name in the series.
+func (s fullyDeletedSeries) labelValues(name string) map[string]struct{} {
+	values := map[string]struct{}{}
+	for _, lbls := range s {
+		if value := lbls.Get(name); value != "" {
+			values[value] = struct{}{}
+		}
+	}
+	return values
+}
+
+// newFullyDeletedSeriesFilterSeriesSet returns a SeriesSet filtering out the input fully deleted series.
+func newFullyDeletedSeriesFilterSeriesSet(set storage.SeriesSet, deleted fullyDeletedSeries) storage.SeriesSet {
+	if len(deleted) == 0 {
+		return set
+	}
+	return &fullyDeletedSeriesFilterSeriesSet{SeriesSet: set, deleted: deleted}
+}
+
+type fullyDeletedSeriesFilterSeriesSet struct {
+	storage.SeriesSet
+	deleted fullyDeletedSeries
+}
+
+func (s *fullyDeletedSeriesFilterSeriesSet) Next() bool {
+	for s.SeriesSet.Next() {
+		if !s.deleted.contains(s.SeriesSet.At().Labels()) {
+			return true
+		}
+	}
+	return false
+}
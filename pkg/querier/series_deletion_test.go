@@ -0,0 +1,378 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/tombstones"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/mimir/pkg/querier/stats"
+	"github.com/grafana/mimir/pkg/storage/series"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestSeriesDeletionsSeriesSet(t *testing.T) {
+	samples := []model.SamplePair{{Timestamp: 10, Value: 1}, {Timestamp: 20, Value: 2}, {Timestamp: 30, Value: 3}, {Timestamp: 40, Value: 4}}
+
+	newSeriesSet := func() storage.SeriesSet {
+		return series.NewConcreteSeriesSetFromUnsortedSeries([]storage.Series{
+			series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "up", "job", "a"), samples, nil),
+			series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "up", "job", "b"), samples, nil),
+			series.NewConcreteSeries(labels.FromStrings(labels.MetricName, "other", "job", "a"), samples, nil),
+		})
+	}
+
+	for name, tc := range map[string]struct {
+		deletions []seriesDeletion
+		expected  map[string][]int64
+	}{
+		"no deletions": {
+			expected: map[string][]int64{
+				`{__name__="other", job="a"}`: {10, 20, 30, 40},
+				`{__name__="up", job="a"}`:    {10, 20, 30, 40},
+				`{__name__="up", job="b"}`:    {10, 20, 30, 40},
+			},
+		},
+		"deletion matching a single series": {
+			deletions: []seriesDeletion{
+				{matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up"), labels.MustNewMatcher(labels.MatchEqual, "job", "a")}, interval: tombstones.Interval{Mint: 15, Maxt: 30}},
+			},
+			expected: map[string][]int64{
+				`{__name__="other", job="a"}`: {10, 20, 30, 40},
+				`{__name__="up", job="a"}`:    {10, 40},
+				`{__name__="up", job="b"}`:    {10, 20, 30, 40},
+			},
+		},
+		"overlapping deletions matching multiple series": {
+			deletions: []seriesDeletion{
+				{matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "a")}, interval: tombstones.Interval{Mint: 0, Maxt: 10}},
+				{matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up")}, interval: tombstones.Interval{Mint: 30, Maxt: 100}},
+			},
+			expected: map[string][]int64{
+				`{__name__="other", job="a"}`: {20, 30, 40},
+				`{__name__="up", job="a"}`:    {20},
+				`{__name__="up", job="b"}`:    {10, 20},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			set := newSeriesDeletionsSeriesSet(newSeriesSet(), tc.deletions)
+
+			actual := map[string][]int64{}
+			var it chunkenc.Iterator
+			for set.Next() {
+				s := set.At()
+				it = s.Iterator(it)
+
+				timestamps := []int64{}
+				for it.Next() != chunkenc.ValNone {
+					timestamps = append(timestamps, it.AtT())
+				}
+				require.NoError(t, it.Err())
+
+				actual[s.Labels().String()] = timestamps
+			}
+			require.NoError(t, set.Err())
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestSeriesRetentionDeletions(t *testing.T) {
+	now := time.Now()
+	rules := []*validation.SeriesRetentionRule{
+		{Selector: `{__name__=~"debug_.*"}`, Period: model.Duration(7 * 24 * time.Hour)},
+		{Selector: `{job="test"}`, Period: model.Duration(time.Hour)},
+	}
+
+	deletions, err := seriesRetentionDeletions(rules, now, now.Add(-30*24*time.Hour).UnixMilli())
+	require.NoError(t, err)
+	require.Len(t, deletions, 2)
+	assert.Equal(t, `__name__=~"debug_.*"`, deletions[0].matchers[0].String())
+	assert.Equal(t, tombstones.Interval{Mint: math.MinInt64, Maxt: now.Add(-7*24*time.Hour).UnixMilli() - 1}, deletions[0].interval)
+	assert.Equal(t, `job="test"`, deletions[1].matchers[0].String())
+	assert.Equal(t, tombstones.Interval{Mint: math.MinInt64, Maxt: now.Add(-time.Hour).UnixMilli() - 1}, deletions[1].interval)
+
+	// Rules whose expired samples are all before the query start time are skipped.
+	deletions, err = seriesRetentionDeletions(rules, now, now.Add(-2*time.Hour).UnixMilli())
+	require.NoError(t, err)
+	require.Len(t, deletions, 1)
+	assert.Equal(t, `job="test"`, deletions[0].matchers[0].String())
+
+	deletions, err = seriesRetentionDeletions(rules, now, now.UnixMilli())
+	require.NoError(t, err)
+	require.Empty(t, deletions)
+}
+
+func TestSeriesDeletionsLoader(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	bkt := objstore.NewInMemBucket()
+	loader := newSeriesDeletionsLoader(bkt, log.NewNopLogger())
+
+	deletions, err := loader.seriesDeletions(ctx, userID, 0, 100)
+	require.NoError(t, err)
+	require.Empty(t, deletions)
+
+	req, err := mimir_tsdb.NewSeriesDeletionRequest([]string{`{__name__="up"}`, `{job="test"}`}, 10, 20, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteSeriesDeletionRequest(ctx, bkt, userID, nil, req))
+
+	// Series deletions are cached.
+	deletions, err = loader.seriesDeletions(ctx, userID, 0, 100)
+	require.NoError(t, err)
+	require.Empty(t, deletions)
+
+	// Expire the cached series deletions.
+	loader.tenants[userID].loadedAt = time.Now().Add(-seriesDeletionsRefreshInterval)
+
+	deletions, err = loader.seriesDeletions(ctx, userID, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, deletions, 2)
+	assert.Equal(t, tombstones.Interval{Mint: 10, Maxt: 20}, deletions[0].interval)
+	assert.Equal(t, `__name__="up"`, deletions[0].matchers[0].String())
+	assert.Equal(t, `job="test"`, deletions[1].matchers[0].String())
+
+	// Only deletions overlapping the time range are returned.
+	for _, tr := range [][2]int64{{0, 10}, {15, 16}, {20, 30}} {
+		deletions, err = loader.seriesDeletions(ctx, userID, tr[0], tr[1])
+		require.NoError(t, err)
+		require.Len(t, deletions, 2)
+	}
+	for _, tr := range [][2]int64{{0, 9}, {21, 30}} {
+		deletions, err = loader.seriesDeletions(ctx, userID, tr[0], tr[1])
+		require.NoError(t, err)
+		require.Empty(t, deletions)
+	}
+
+	// Requests of other tenants are not returned.
+	deletions, err = loader.seriesDeletions(ctx, "user-2", 0, 100)
+	require.NoError(t, err)
+	require.Empty(t, deletions)
+}
+
+func TestMultiQuerier_ShouldFilterOutFullyDeletedSeriesFromSeriesAndLabelsQueries(t *testing.T) {
+	now := time.Now()
+	ts := func(ago time.Duration) int64 { return now.Add(-ago).UnixMilli() }
+
+	// The store-gateways have the old samples, while the ingesters have the recent ones.
+	ingesters := newSeriesDeletionsTestHead(t, map[string][]int64{
+		`{__name__="up", job="deleted"}`: {ts(time.Hour)},
+		`{__name__="up", job="partial"}`: {ts(time.Hour)},
+	})
+	storeGateways := newSeriesDeletionsTestHead(t, map[string][]int64{
+		`{__name__="up", job="deleted"}`:       {ts(20 * time.Hour)},
+		`{__name__="up", job="partial"}`:       {ts(20 * time.Hour)},
+		`{__name__="up", job="kept"}`:          {ts(20 * time.Hour)},
+		`{__name__="deleted_only", env="dev"}`: {ts(20 * time.Hour), ts(18 * time.Hour)},
+	})
+
+	blockStore := &seriesDeletionsTestQueryable{
+		Queryable: storeGateways,
+		deletions: []seriesDeletion{
+			{matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "deleted")}, interval: tombstones.Interval{Mint: math.MinInt64, Maxt: math.MaxInt64}},
+			{matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "partial")}, interval: tombstones.Interval{Mint: ts(21 * time.Hour), Maxt: ts(19 * time.Hour)}},
+			{matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "deleted_only")}, interval: tombstones.Interval{Mint: ts(21 * time.Hour), Maxt: ts(17 * time.Hour)}},
+		},
+	}
+
+	cfg := Config{}
+	flagext.DefaultValues(&cfg)
+	overrides, err := validation.NewOverrides(defaultLimitsConfig(), nil)
+	require.NoError(t, err)
+
+	queryable := newQueryable(ingesters, blockStore, cfg, overrides, stats.NewQueryMetrics(nil), log.NewNopLogger())
+	ctx := user.InjectOrgID(context.Background(), "user-1")
+
+	q, err := queryable.Querier(ts(24*time.Hour), now.UnixMilli())
+	require.NoError(t, err)
+
+	t.Run("series", func(t *testing.T) {
+		set := q.Select(ctx, true, &storage.SelectHints{Start: ts(24 * time.Hour), End: now.UnixMilli(), Func: "series"}, labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".+"))
+
+		var actual []string
+		for set.Next() {
+			actual = append(actual, set.At().Labels().String())
+		}
+		require.NoError(t, set.Err())
+		require.Equal(t, []string{`{__name__="up", job="kept"}`, `{__name__="up", job="partial"}`}, actual)
+	})
+
+	t.Run("label names", func(t *testing.T) {
+		names, _, err := q.LabelNames(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{labels.MetricName, "job"}, names)
+	})
+
+	t.Run("label values", func(t *testing.T) {
+		values, _, err := q.LabelValues(ctx, labels.MetricName)
+		require.NoError(t, err)
+		require.Equal(t, []string{"up"}, values)
+
+		values, _, err = q.LabelValues(ctx, "job")
+		require.NoError(t, err)
+		require.Equal(t, []string{"kept", "partial"}, values)
+
+		values, _, err = q.LabelValues(ctx, "job", labels.MustNewMatcher(labels.MatchRegexp, "job", "deleted|kept"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"kept"}, values)
+	})
+}
+
+// newSeriesDeletionsTestHead returns a queryable of a TSDB head with the input series float samples.
+func newSeriesDeletionsTestHead(t *testing.T, series map[string][]int64) storage.Queryable {
+	opts := tsdb.DefaultHeadOptions()
+	opts.ChunkDirRoot = t.TempDir()
+	head, err := tsdb.NewHead(nil, nil, nil, nil, opts, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = head.Close()
+	})
+
+	app := head.Appender(context.Background())
+	for s, timestamps := range series {
+		lbls, err := parser.ParseMetric(s)
+		require.NoError(t, err)
+
+		for _, ts := range timestamps {
+			_, err := app.Append(0, lbls, ts, 1)
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, app.Commit())
+
+	return storage.QueryableFunc(func(mint, maxt int64) (storage.Querier, error) {
+		return tsdb.NewBlockQuerier(head, mint, maxt)
+	})
+}
+
+type seriesDeletionsTestQueryable struct {
+	storage.Queryable
+	deletions []seriesDeletion
+}
+
+func (q *seriesDeletionsTestQueryable) seriesDeletions(context.Context, string, int64, int64) ([]seriesDeletion, error) {
+	return q.deletions, nil
+}
+
+func TestMultiQuerier_ShouldOnlySelectTheSeriesMatchingOverlappingDeletionsToFilterOutFullyDeletedSeries(t *testing.T) {
+	now := time.Now()
+	ts := func(ago time.Duration) int64 { return now.Add(-ago).UnixMilli() }
+
+	ingesters := &selectCountingQueryable{Queryable: newSeriesDeletionsTestHead(t, map[string][]int64{
+		`{__name__="up", job="deleted"}`: {ts(time.Hour)},
+		`{__name__="up", job="kept"}`:    {ts(time.Hour)},
+		`{__name__="other", job="kept"}`: {ts(time.Hour)},
+	})}
+	storeGateways := &selectCountingQueryable{Queryable: newSeriesDeletionsTestHead(t, map[string][]int64{
+		`{__name__="up", job="deleted"}`: {ts(20 * time.Hour)},
+	})}
+	blockStore := &seriesDeletionsTestQueryable{
+		Queryable: storeGateways,
+		deletions: []seriesDeletion{
+			{matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "deleted")}, interval: tombstones.Interval{Mint: math.MinInt64, Maxt: math.MaxInt64}},
+			// Doesn't match the queried metric.
+			{matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "other")}, interval: tombstones.Interval{Mint: math.MinInt64, Maxt: math.MaxInt64}},
+			// Doesn't overlap the queried time range.
+			{matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "kept")}, interval: tombstones.Interval{Mint: ts(30 * time.Hour), Maxt: ts(29 * time.Hour)}},
+		},
+	}
+
+	for _, filteringEnabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("filtering enabled: %t", filteringEnabled), func(t *testing.T) {
+			cfg := Config{}
+			flagext.DefaultValues(&cfg)
+			cfg.FilterFullyDeletedSeries = filteringEnabled
+			overrides, err := validation.NewOverrides(defaultLimitsConfig(), nil)
+			require.NoError(t, err)
+
+			queryable := newQueryable(ingesters, blockStore, cfg, overrides, stats.NewQueryMetrics(nil), log.NewNopLogger())
+			ctx := user.InjectOrgID(context.Background(), "user-1")
+
+			q, err := queryable.Querier(ts(24*time.Hour), now.UnixMilli())
+			require.NoError(t, err)
+
+			ingesters.selects.Store(0)
+			storeGateways.selects.Store(0)
+			set := q.Select(ctx, true, &storage.SelectHints{Start: ts(24 * time.Hour), End: now.UnixMilli(), Func: "series"}, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "up"))
+
+			var actual []string
+			for set.Next() {
+				actual = append(actual, set.At().Labels().String())
+			}
+			require.NoError(t, set.Err())
+
+			if filteringEnabled {
+				require.Equal(t, []string{`{__name__="up", job="kept"}`}, actual)
+				// The series are selected once more for the only deletion overlapping the query and matching its selector.
+				require.Equal(t, int64(2), ingesters.selects.Load())
+				require.Equal(t, int64(2), storeGateways.selects.Load())
+			} else {
+				require.Equal(t, []string{`{__name__="up", job="deleted"}`, `{__name__="up", job="kept"}`}, actual)
+				require.Equal(t, int64(1), ingesters.selects.Load())
+				require.Equal(t, int64(1), storeGateways.selects.Load())
+			}
+
+			ingesters.selects.Store(0)
+			storeGateways.selects.Store(0)
+			values, _, err := q.LabelValues(ctx, "job")
+			require.NoError(t, err)
+
+			if filteringEnabled {
+				require.Equal(t, []string{"kept"}, values)
+				// The series are selected once for each deletion overlapping the query, and once for each
+				// label value of the fully deleted series.
+				require.Equal(t, int64(4), ingesters.selects.Load())
+				require.Equal(t, int64(4), storeGateways.selects.Load())
+			} else {
+				require.Equal(t, []string{"deleted", "kept"}, values)
+				require.Zero(t, ingesters.selects.Load())
+				require.Zero(t, storeGateways.selects.Load())
+			}
+		})
+	}
+}
+
+// selectCountingQueryable counts the Select calls to the queriers it returns.
+type selectCountingQueryable struct {
+	storage.Queryable
+	selects atomic.Int64
+}
+
+func (q *selectCountingQueryable) Querier(mint, maxt int64) (storage.Querier, error) {
+	querier, err := q.Queryable.Querier(mint, maxt)
+	if err != nil {
+		return nil, err
+	}
+	return &selectCountingQuerier{Querier: querier, selects: &q.selects}, nil
+}
+
+type selectCountingQuerier struct {
+	storage.Querier
+	selects *atomic.Int64
+}
+
+func (q *selectCountingQuerier) Select(ctx context.Context, sortSeries bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	q.selects.Inc()
+	return q.Querier.Select(ctx, sortSeries, hints, matchers...)
+}
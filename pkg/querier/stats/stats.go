@@ -7,6 +7,8 @@ package stats
 
 import (
 	"context"
+	"slices"
+	"sync"
 	"sync/atomic" //lint:ignore faillint we can't use go.uber.org/atomic with a protobuf struct without wrapping it.
 	"time"
 
@@ -17,6 +19,37 @@ type contextKey int
 
 var ctxKey = contextKey(0)
 
+// Stats holds the statistics of a query. The protobuf code is generated from stats.proto, but the type is declared
+// here, because SamplesProcessedPerStep can't be updated atomically and needs a lock.
+type Stats struct {
+	// The sum of all wall time spent in the querier to execute the query.
+	WallTime time.Duration `protobuf:"bytes,1,opt,name=wall_time,json=wallTime,proto3,stdduration" json:"wall_time"`
+	// The number of series fetched for the query
+	FetchedSeriesCount uint64 `protobuf:"varint,2,opt,name=fetched_series_count,json=fetchedSeriesCount,proto3" json:"fetched_series_count,omitempty"`
+	// The number of bytes of the chunks fetched for the query, after any deduplication
+	FetchedChunkBytes uint64 `protobuf:"varint,3,opt,name=fetched_chunk_bytes,json=fetchedChunkBytes,proto3" json:"fetched_chunk_bytes,omitempty"`
+	// The number of chunks fetched for the query, after any deduplication
+	FetchedChunksCount uint64 `protobuf:"varint,4,opt,name=fetched_chunks_count,json=fetchedChunksCount,proto3" json:"fetched_chunks_count,omitempty"`
+	// The number of sharded queries executed. 0 if sharding is disabled or the query can't be sharded.
+	ShardedQueries uint32 `protobuf:"varint,5,opt,name=sharded_queries,json=shardedQueries,proto3" json:"sharded_queries,omitempty"`
+	// The number of split partial queries executed. 0 if splitting is disabled or the query can't be split.
+	SplitQueries uint32 `protobuf:"varint,6,opt,name=split_queries,json=splitQueries,proto3" json:"split_queries,omitempty"`
+	// The number of index bytes fetched on the store-gateway for the query
+	FetchedIndexBytes uint64 `protobuf:"varint,7,opt,name=fetched_index_bytes,json=fetchedIndexBytes,proto3" json:"fetched_index_bytes,omitempty"`
+	// The estimated number of series to be fetched for the query
+	EstimatedSeriesCount uint64 `protobuf:"varint,8,opt,name=estimated_series_count,json=estimatedSeriesCount,proto3" json:"estimated_series_count,omitempty"`
+	// The sum of durations that the query spent in the queue, before it was handled by querier.
+	QueueTime time.Duration `protobuf:"bytes,9,opt,name=queue_time,json=queueTime,proto3,stdduration" json:"queue_time"`
+	// The number of samples processed by the PromQL engine to execute the query.
+	SamplesProcessed uint64 `protobuf:"varint,10,opt,name=samples_processed,json=samplesProcessed,proto3" json:"samples_processed,omitempty"`
+	// The number of samples processed by the PromQL engine at each step of the query, sorted by timestamp.
+	// Only tracked when the query is run with the stats=all parameter.
+	SamplesProcessedPerStep []StepStat `protobuf:"bytes,11,rep,name=samples_processed_per_step,json=samplesProcessedPerStep,proto3" json:"samples_processed_per_step"`
+
+	// samplesProcessedPerStepMtx protects SamplesProcessedPerStep.
+	samplesProcessedPerStepMtx sync.Mutex
+}
+
 // ContextWithEmptyStats returns a context with empty stats.
 func ContextWithEmptyStats(ctx context.Context) (*Stats, context.Context) {
 	stats := &Stats{}
@@ -187,6 +220,73 @@ func (s *Stats) LoadQueueTime() time.Duration {
 	return time.Duration(atomic.LoadInt64((*int64)(&s.QueueTime)))
 }
 
+func (s *Stats) AddSamplesProcessed(c uint64) {
+	if s == nil {
+		return
+	}
+
+	atomic.AddUint64(&s.SamplesProcessed, c)
+}
+
+func (s *Stats) LoadSamplesProcessed() uint64 {
+	if s == nil {
+		return 0
+	}
+
+	return atomic.LoadUint64(&s.SamplesProcessed)
+}
+
+// AddSamplesProcessedPerStep adds the number of samples processed at each step. steps must be sorted by timestamp.
+func (s *Stats) AddSamplesProcessedPerStep(steps []StepStat) {
+	if s == nil || len(steps) == 0 {
+		return
+	}
+
+	s.samplesProcessedPerStepMtx.Lock()
+	defer s.samplesProcessedPerStepMtx.Unlock()
+
+	s.SamplesProcessedPerStep = mergeStepStats(s.SamplesProcessedPerStep, steps)
+}
+
+// LoadSamplesProcessedPerStep returns a copy of the number of samples processed at each step, sorted by timestamp.
+func (s *Stats) LoadSamplesProcessedPerStep() []StepStat {
+	if s == nil {
+		return nil
+	}
+
+	s.samplesProcessedPerStepMtx.Lock()
+	defer s.samplesProcessedPerStepMtx.Unlock()
+
+	if len(s.SamplesProcessedPerStep) == 0 {
+		return nil
+	}
+
+	return append([]StepStat(nil), s.SamplesProcessedPerStep...)
+}
+
+// mergeStepStats returns the sorted union of a and b, both sorted by timestamp,
+// summing the values of the steps with the same timestamp.
+func mergeStepStats(a, b []StepStat) []StepStat {
+	merged := make([]StepStat, 0, max(len(a), len(b)))
+
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0].Timestamp < b[0].Timestamp:
+			merged = append(merged, a[0])
+			a = a[1:]
+		case a[0].Timestamp > b[0].Timestamp:
+			merged = append(merged, b[0])
+			b = b[1:]
+		default:
+			merged = append(merged, StepStat{Timestamp: a[0].Timestamp, Value: a[0].Value + b[0].Value})
+			a, b = a[1:], b[1:]
+		}
+	}
+
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
+
 // Merge the provided Stats into this one.
 func (s *Stats) Merge(other *Stats) {
 	if s == nil || other == nil {
@@ -202,6 +302,8 @@ func (s *Stats) Merge(other *Stats) {
 	s.AddFetchedIndexBytes(other.LoadFetchedIndexBytes())
 	s.AddEstimatedSeriesCount(other.LoadEstimatedSeriesCount())
 	s.AddQueueTime(other.LoadQueueTime())
+	s.AddSamplesProcessed(other.LoadSamplesProcessed())
+	s.AddSamplesProcessedPerStep(other.LoadSamplesProcessedPerStep())
 }
 
 // Copy returns a copy of the stats. Use this rather than regular struct assignment
@@ -219,3 +321,34 @@ func ShouldTrackHTTPGRPCResponse(r *httpgrpc.HTTPResponse) bool {
 	// Do no track statistics for requests failed because of a server error.
 	return r.Code < 500
 }
+
+// Equal returns whether the stats are equal to that, which must be a *Stats.
+// Unlike the generated protobuf code, it doesn't accept a Stats value, because it would copy the lock.
+func (s *Stats) Equal(that interface{}) bool {
+	if that == nil {
+		return s == nil
+	}
+
+	other, ok := that.(*Stats)
+	if !ok {
+		return false
+	}
+	if other == nil || s == nil {
+		return other == nil && s == nil
+	}
+
+	if s.LoadWallTime() != other.LoadWallTime() ||
+		s.LoadFetchedSeries() != other.LoadFetchedSeries() ||
+		s.LoadFetchedChunkBytes() != other.LoadFetchedChunkBytes() ||
+		s.LoadFetchedChunks() != other.LoadFetchedChunks() ||
+		s.LoadShardedQueries() != other.LoadShardedQueries() ||
+		s.LoadSplitQueries() != other.LoadSplitQueries() ||
+		s.LoadFetchedIndexBytes() != other.LoadFetchedIndexBytes() ||
+		s.LoadEstimatedSeriesCount() != other.LoadEstimatedSeriesCount() ||
+		s.LoadQueueTime() != other.LoadQueueTime() ||
+		s.LoadSamplesProcessed() != other.LoadSamplesProcessed() {
+		return false
+	}
+
+	return slices.Equal(s.LoadSamplesProcessedPerStep(), other.LoadSamplesProcessedPerStep())
+}
@@ -29,27 +29,6 @@ var _ = time.Kitchen
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
-type Stats struct {
-	// The sum of all wall time spent in the querier to execute the query.
-	WallTime time.Duration `protobuf:"bytes,1,opt,name=wall_time,json=wallTime,proto3,stdduration" json:"wall_time"`
-	// The number of series fetched for the query
-	FetchedSeriesCount uint64 `protobuf:"varint,2,opt,name=fetched_series_count,json=fetchedSeriesCount,proto3" json:"fetched_series_count,omitempty"`
-	// The number of bytes of the chunks fetched for the query, after any deduplication
-	FetchedChunkBytes uint64 `protobuf:"varint,3,opt,name=fetched_chunk_bytes,json=fetchedChunkBytes,proto3" json:"fetched_chunk_bytes,omitempty"`
-	// The number of chunks fetched for the query, after any deduplication
-	FetchedChunksCount uint64 `protobuf:"varint,4,opt,name=fetched_chunks_count,json=fetchedChunksCount,proto3" json:"fetched_chunks_count,omitempty"`
-	// The number of sharded queries executed. 0 if sharding is disabled or the query can't be sharded.
-	ShardedQueries uint32 `protobuf:"varint,5,opt,name=sharded_queries,json=shardedQueries,proto3" json:"sharded_queries,omitempty"`
-	// The number of split partial queries executed. 0 if splitting is disabled or the query can't be split.
-	SplitQueries uint32 `protobuf:"varint,6,opt,name=split_queries,json=splitQueries,proto3" json:"split_queries,omitempty"`
-	// The number of index bytes fetched on the store-gateway for the query
-	FetchedIndexBytes uint64 `protobuf:"varint,7,opt,name=fetched_index_bytes,json=fetchedIndexBytes,proto3" json:"fetched_index_bytes,omitempty"`
-	// The estimated number of series to be fetched for the query
-	EstimatedSeriesCount uint64 `protobuf:"varint,8,opt,name=estimated_series_count,json=estimatedSeriesCount,proto3" json:"estimated_series_count,omitempty"`
-	// The sum of durations that the query spent in the queue, before it was handled by querier.
-	QueueTime time.Duration `protobuf:"bytes,9,opt,name=queue_time,json=queueTime,proto3,stdduration" json:"queue_time"`
-}
-
 func (m *Stats) Reset()      { *m = Stats{} }
 func (*Stats) ProtoMessage() {}
 func (*Stats) Descriptor() ([]byte, []int) {
@@ -145,48 +124,122 @@ func (m *Stats) GetQueueTime() time.Duration {
 	return 0
 }
 
+func (m *Stats) GetSamplesProcessed() uint64 {
+	if m != nil {
+		return m.SamplesProcessed
+	}
+	return 0
+}
+
+func (m *Stats) GetSamplesProcessedPerStep() []StepStat {
+	if m != nil {
+		return m.SamplesProcessedPerStep
+	}
+	return nil
+}
+
+type StepStat struct {
+	// The timestamp of the step, in milliseconds.
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Value     int64 `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *StepStat) Reset()      { *m = StepStat{} }
+func (*StepStat) ProtoMessage() {}
+func (*StepStat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b4756a0aec8b9d44, []int{1}
+}
+func (m *StepStat) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StepStat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StepStat.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StepStat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StepStat.Merge(m, src)
+}
+func (m *StepStat) XXX_Size() int {
+	return m.Size()
+}
+func (m *StepStat) XXX_DiscardUnknown() {
+	xxx_messageInfo_StepStat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StepStat proto.InternalMessageInfo
+
+func (m *StepStat) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *StepStat) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Stats)(nil), "stats.Stats")
+	proto.RegisterType((*StepStat)(nil), "stats.StepStat")
 }
 
 func init() { proto.RegisterFile("stats.proto", fileDescriptor_b4756a0aec8b9d44) }
 
 var fileDescriptor_b4756a0aec8b9d44 = []byte{
-	// 381 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0xbf, 0x4e, 0xfa, 0x50,
-	0x14, 0xc7, 0x7b, 0x7f, 0xfc, 0xf9, 0xc1, 0x45, 0x34, 0x56, 0x62, 0x2a, 0xc3, 0x81, 0xe8, 0x20,
-	0x53, 0x31, 0xea, 0xe6, 0x62, 0x8a, 0x8b, 0xa3, 0xe0, 0xe4, 0xd2, 0x14, 0x7a, 0x29, 0x8d, 0xa5,
-	0x17, 0x7a, 0x6f, 0xa3, 0x6e, 0x3e, 0x82, 0xa3, 0x8f, 0xe0, 0xa3, 0x30, 0xb2, 0x98, 0x30, 0xa9,
-	0x94, 0xc5, 0x91, 0x47, 0x30, 0xf7, 0xb6, 0x25, 0xe0, 0xe4, 0xd6, 0x7b, 0x3e, 0xe7, 0x93, 0xef,
-	0xc9, 0x39, 0xc5, 0x25, 0xc6, 0x2d, 0xce, 0xf4, 0x51, 0x40, 0x39, 0x55, 0x73, 0xf2, 0x51, 0xad,
-	0x38, 0xd4, 0xa1, 0xb2, 0xd2, 0x14, 0x5f, 0x31, 0xac, 0x82, 0x43, 0xa9, 0xe3, 0x91, 0xa6, 0x7c,
-	0x75, 0xc3, 0x7e, 0xd3, 0x0e, 0x03, 0x8b, 0xbb, 0xd4, 0x8f, 0xf9, 0xe1, 0x7b, 0x06, 0xe7, 0x3a,
-	0xc2, 0x57, 0x2f, 0x71, 0xf1, 0xc1, 0xf2, 0x3c, 0x93, 0xbb, 0x43, 0xa2, 0xa1, 0x3a, 0x6a, 0x94,
-	0x4e, 0x0f, 0xf4, 0xd8, 0xd6, 0x53, 0x5b, 0xbf, 0x4a, 0x6c, 0xa3, 0x30, 0xf9, 0xa8, 0x29, 0xaf,
-	0x9f, 0x35, 0xd4, 0x2e, 0x08, 0xeb, 0xd6, 0x1d, 0x12, 0xf5, 0x04, 0x57, 0xfa, 0x84, 0xf7, 0x06,
-	0xc4, 0x36, 0x19, 0x09, 0x5c, 0xc2, 0xcc, 0x1e, 0x0d, 0x7d, 0xae, 0xfd, 0xab, 0xa3, 0x46, 0xb6,
-	0xad, 0x26, 0xac, 0x23, 0x51, 0x4b, 0x10, 0x55, 0xc7, 0x7b, 0xa9, 0xd1, 0x1b, 0x84, 0xfe, 0xbd,
-	0xd9, 0x7d, 0xe2, 0x84, 0x69, 0x19, 0x29, 0xec, 0x26, 0xa8, 0x25, 0x88, 0x21, 0xc0, 0x7a, 0x82,
-	0xec, 0x4f, 0x13, 0xb2, 0x1b, 0x09, 0x52, 0x48, 0x12, 0x8e, 0xf1, 0x0e, 0x1b, 0x58, 0x81, 0x4d,
-	0x6c, 0x73, 0x1c, 0xca, 0x64, 0x2d, 0x57, 0x47, 0x8d, 0x72, 0x7b, 0x3b, 0x29, 0xdf, 0xc4, 0x55,
-	0xf5, 0x08, 0x97, 0xd9, 0xc8, 0x73, 0xf9, 0xaa, 0x2d, 0x2f, 0xdb, 0xb6, 0x64, 0x31, 0x6d, 0x5a,
-	0x9b, 0xd7, 0xf5, 0x6d, 0xf2, 0x98, 0xcc, 0xfb, 0x7f, 0x63, 0xde, 0x6b, 0x41, 0xe2, 0x79, 0xcf,
-	0xf1, 0x3e, 0x61, 0xdc, 0x1d, 0x5a, 0xfc, 0xf7, 0x4e, 0x0a, 0x52, 0xa9, 0xac, 0xe8, 0xfa, 0x56,
-	0x0c, 0x8c, 0xc7, 0x21, 0x09, 0x49, 0x7c, 0x8a, 0xe2, 0xdf, 0x4f, 0x51, 0x94, 0x9a, 0xb8, 0x85,
-	0x71, 0x31, 0x9d, 0x83, 0x32, 0x9b, 0x83, 0xb2, 0x9c, 0x03, 0x7a, 0x8e, 0x00, 0xbd, 0x45, 0x80,
-	0x26, 0x11, 0xa0, 0x69, 0x04, 0xe8, 0x2b, 0x02, 0xf4, 0x1d, 0x81, 0xb2, 0x8c, 0x00, 0xbd, 0x2c,
-	0x40, 0x99, 0x2e, 0x40, 0x99, 0x2d, 0x40, 0xb9, 0x8b, 0x7f, 0xa5, 0x6e, 0x5e, 0x86, 0x9c, 0xfd,
-	0x04, 0x00, 0x00, 0xff, 0xff, 0x86, 0xcb, 0x53, 0xdd, 0x67, 0x02, 0x00, 0x00,
-}
-
-func (this *Stats) Equal(that interface{}) bool {
+	// 487 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x3f, 0x6f, 0xd3, 0x40,
+	0x14, 0xbf, 0x23, 0x49, 0x71, 0x2e, 0x94, 0xd2, 0x23, 0x02, 0x13, 0xa1, 0x4b, 0x54, 0x06, 0x22,
+	0x21, 0x39, 0xa8, 0x30, 0x81, 0x84, 0x90, 0xcb, 0xc2, 0x56, 0x1c, 0x26, 0x16, 0xcb, 0x89, 0x5f,
+	0x13, 0x0b, 0xdb, 0xe7, 0xfa, 0xce, 0xfc, 0xd9, 0xf8, 0x08, 0x8c, 0x8c, 0xb0, 0xf1, 0x51, 0x3a,
+	0x66, 0xec, 0x04, 0x8d, 0xb3, 0x74, 0xcc, 0x47, 0x40, 0x77, 0x67, 0x97, 0xb4, 0x2c, 0x6c, 0x7e,
+	0xbf, 0x3f, 0xfe, 0x3d, 0xbf, 0xf7, 0x4c, 0x3a, 0x42, 0x06, 0x52, 0x38, 0x59, 0xce, 0x25, 0xa7,
+	0x2d, 0x5d, 0xf4, 0xba, 0x33, 0x3e, 0xe3, 0x1a, 0x19, 0xa9, 0x27, 0x43, 0xf6, 0xd8, 0x8c, 0xf3,
+	0x59, 0x0c, 0x23, 0x5d, 0x4d, 0x8a, 0xa3, 0x51, 0x58, 0xe4, 0x81, 0x8c, 0x78, 0x6a, 0xf8, 0xbd,
+	0xb3, 0x26, 0x69, 0x8d, 0x95, 0x9f, 0xbe, 0x24, 0xed, 0x8f, 0x41, 0x1c, 0xfb, 0x32, 0x4a, 0xc0,
+	0xc6, 0x03, 0x3c, 0xec, 0xec, 0xdf, 0x73, 0x8c, 0xdb, 0xa9, 0xdd, 0xce, 0xab, 0xca, 0xed, 0x5a,
+	0x27, 0xbf, 0xfa, 0xe8, 0xdb, 0xef, 0x3e, 0xf6, 0x2c, 0xe5, 0x7a, 0x1b, 0x25, 0x40, 0x1f, 0x93,
+	0xee, 0x11, 0xc8, 0xe9, 0x1c, 0x42, 0x5f, 0x40, 0x1e, 0x81, 0xf0, 0xa7, 0xbc, 0x48, 0xa5, 0x7d,
+	0x6d, 0x80, 0x87, 0x4d, 0x8f, 0x56, 0xdc, 0x58, 0x53, 0x07, 0x8a, 0xa1, 0x0e, 0xb9, 0x5d, 0x3b,
+	0xa6, 0xf3, 0x22, 0x7d, 0xef, 0x4f, 0x3e, 0x4b, 0x10, 0x76, 0x43, 0x1b, 0x76, 0x2b, 0xea, 0x40,
+	0x31, 0xae, 0x22, 0x36, 0x13, 0xb4, 0xbe, 0x4e, 0x68, 0x5e, 0x4a, 0xd0, 0x86, 0x2a, 0xe1, 0x21,
+	0xd9, 0x11, 0xf3, 0x20, 0x0f, 0x21, 0xf4, 0x8f, 0x0b, 0x9d, 0x6c, 0xb7, 0x06, 0x78, 0xb8, 0xed,
+	0xdd, 0xac, 0xe0, 0x37, 0x06, 0xa5, 0x0f, 0xc8, 0xb6, 0xc8, 0xe2, 0x48, 0x5e, 0xc8, 0xb6, 0xb4,
+	0xec, 0x86, 0x06, 0x6b, 0xd1, 0x46, 0xbf, 0x51, 0x1a, 0xc2, 0xa7, 0xaa, 0xdf, 0xeb, 0x97, 0xfa,
+	0x7d, 0xad, 0x18, 0xd3, 0xef, 0x53, 0x72, 0x07, 0x84, 0x8c, 0x92, 0x40, 0x5e, 0x9d, 0x89, 0xa5,
+	0x2d, 0xdd, 0x0b, 0x76, 0x73, 0x2a, 0x2e, 0x21, 0xc7, 0x05, 0x14, 0x60, 0x56, 0xd1, 0xfe, 0xff,
+	0x55, 0xb4, 0xb5, 0x4d, 0xef, 0xe2, 0x11, 0xd9, 0x15, 0x41, 0x92, 0xc5, 0x20, 0xfc, 0x2c, 0xe7,
+	0x53, 0x10, 0x02, 0x42, 0x9b, 0xe8, 0xd0, 0x5b, 0x15, 0x71, 0x58, 0xe3, 0xd4, 0x23, 0xbd, 0x7f,
+	0xc4, 0x7e, 0x06, 0xb9, 0x2f, 0x24, 0x64, 0x76, 0x67, 0xd0, 0x18, 0x76, 0xf6, 0x77, 0x1c, 0x73,
+	0x73, 0x63, 0x09, 0x99, 0x3a, 0x18, 0xb7, 0xa9, 0x62, 0xbd, 0xbb, 0x57, 0x5f, 0x76, 0x08, 0xb9,
+	0x92, 0x3c, 0xb3, 0xce, 0xbf, 0xf7, 0xd1, 0xfa, 0x47, 0x1f, 0xed, 0xbd, 0x20, 0x56, 0x6d, 0xa2,
+	0xf7, 0x49, 0x5b, 0x7d, 0x94, 0x90, 0x41, 0x92, 0xe9, 0x23, 0x6b, 0x78, 0x7f, 0x01, 0xda, 0x25,
+	0xad, 0x0f, 0x41, 0x5c, 0x80, 0xbe, 0x98, 0x86, 0x67, 0x0a, 0xf7, 0xf9, 0x62, 0xc9, 0xd0, 0xe9,
+	0x92, 0xa1, 0xf5, 0x92, 0xe1, 0x2f, 0x25, 0xc3, 0x3f, 0x4b, 0x86, 0x4f, 0x4a, 0x86, 0x17, 0x25,
+	0xc3, 0x67, 0x25, 0xc3, 0xe7, 0x25, 0x43, 0xeb, 0x92, 0xe1, 0xaf, 0x2b, 0x86, 0x16, 0x2b, 0x86,
+	0x4e, 0x57, 0x0c, 0xbd, 0x33, 0x7f, 0xc5, 0x64, 0x4b, 0xcf, 0xeb, 0xc9, 0x9f, 0x01, 0x00, 0x93,
+	0x66, 0x28, 0x6f, 0x32, 0x03, 0x00, 0x00,
+}
+
+func (this *StepStat) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Stats)
+	that1, ok := that.(*StepStat)
 	if !ok {
-		that2, ok := that.(Stats)
+		that2, ok := that.(StepStat)
 		if ok {
 			that1 = &that2
 		} else {
@@ -198,31 +251,10 @@ func (this *Stats) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.WallTime != that1.WallTime {
-		return false
-	}
-	if this.FetchedSeriesCount != that1.FetchedSeriesCount {
+	if this.Timestamp != that1.Timestamp {
 		return false
 	}
-	if this.FetchedChunkBytes != that1.FetchedChunkBytes {
-		return false
-	}
-	if this.FetchedChunksCount != that1.FetchedChunksCount {
-		return false
-	}
-	if this.ShardedQueries != that1.ShardedQueries {
-		return false
-	}
-	if this.SplitQueries != that1.SplitQueries {
-		return false
-	}
-	if this.FetchedIndexBytes != that1.FetchedIndexBytes {
-		return false
-	}
-	if this.EstimatedSeriesCount != that1.EstimatedSeriesCount {
-		return false
-	}
-	if this.QueueTime != that1.QueueTime {
+	if this.Value != that1.Value {
 		return false
 	}
 	return true
@@ -231,7 +263,7 @@ func (this *Stats) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 13)
+	s := make([]string, 0, 15)
 	s = append(s, "&stats.Stats{")
 	s = append(s, "WallTime: "+fmt.Sprintf("%#v", this.WallTime)+",\n")
 	s = append(s, "FetchedSeriesCount: "+fmt.Sprintf("%#v", this.FetchedSeriesCount)+",\n")
@@ -242,6 +274,25 @@ func (this *Stats) GoString() string {
 	s = append(s, "FetchedIndexBytes: "+fmt.Sprintf("%#v", this.FetchedIndexBytes)+",\n")
 	s = append(s, "EstimatedSeriesCount: "+fmt.Sprintf("%#v", this.EstimatedSeriesCount)+",\n")
 	s = append(s, "QueueTime: "+fmt.Sprintf("%#v", this.QueueTime)+",\n")
+	s = append(s, "SamplesProcessed: "+fmt.Sprintf("%#v", this.SamplesProcessed)+",\n")
+	if this.SamplesProcessedPerStep != nil {
+		vs := make([]*StepStat, len(this.SamplesProcessedPerStep))
+		for i := range vs {
+			vs[i] = &this.SamplesProcessedPerStep[i]
+		}
+		s = append(s, "SamplesProcessedPerStep: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StepStat) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&stats.StepStat{")
+	s = append(s, "Timestamp: "+fmt.Sprintf("%#v", this.Timestamp)+",\n")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -273,6 +324,25 @@ func (m *Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.SamplesProcessedPerStep) > 0 {
+		for iNdEx := len(m.SamplesProcessedPerStep) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.SamplesProcessedPerStep[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintStats(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if m.SamplesProcessed != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.SamplesProcessed))
+		i--
+		dAtA[i] = 0x50
+	}
 	n1, err1 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.QueueTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.QueueTime):])
 	if err1 != nil {
 		return 0, err1
@@ -327,6 +397,39 @@ func (m *Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *StepStat) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StepStat) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StepStat) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Value != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.Value))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintStats(dAtA []byte, offset int, v uint64) int {
 	offset -= sovStats(v)
 	base := offset
@@ -369,6 +472,30 @@ func (m *Stats) Size() (n int) {
 	}
 	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.QueueTime)
 	n += 1 + l + sovStats(uint64(l))
+	if m.SamplesProcessed != 0 {
+		n += 1 + sovStats(uint64(m.SamplesProcessed))
+	}
+	if len(m.SamplesProcessedPerStep) > 0 {
+		for _, e := range m.SamplesProcessedPerStep {
+			l = e.Size()
+			n += 1 + l + sovStats(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *StepStat) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Timestamp != 0 {
+		n += 1 + sovStats(uint64(m.Timestamp))
+	}
+	if m.Value != 0 {
+		n += 1 + sovStats(uint64(m.Value))
+	}
 	return n
 }
 
@@ -382,6 +509,11 @@ func (this *Stats) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForSamplesProcessedPerStep := "[]StepStat{"
+	for _, f := range this.SamplesProcessedPerStep {
+		repeatedStringForSamplesProcessedPerStep += strings.Replace(strings.Replace(f.String(), "StepStat", "StepStat", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForSamplesProcessedPerStep += "}"
 	s := strings.Join([]string{`&Stats{`,
 		`WallTime:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.WallTime), "Duration", "durationpb.Duration", 1), `&`, ``, 1) + `,`,
 		`FetchedSeriesCount:` + fmt.Sprintf("%v", this.FetchedSeriesCount) + `,`,
@@ -392,6 +524,19 @@ func (this *Stats) String() string {
 		`FetchedIndexBytes:` + fmt.Sprintf("%v", this.FetchedIndexBytes) + `,`,
 		`EstimatedSeriesCount:` + fmt.Sprintf("%v", this.EstimatedSeriesCount) + `,`,
 		`QueueTime:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.QueueTime), "Duration", "durationpb.Duration", 1), `&`, ``, 1) + `,`,
+		`SamplesProcessed:` + fmt.Sprintf("%v", this.SamplesProcessed) + `,`,
+		`SamplesProcessedPerStep:` + repeatedStringForSamplesProcessedPerStep + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StepStat) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StepStat{`,
+		`Timestamp:` + fmt.Sprintf("%v", this.Timestamp) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -632,6 +777,150 @@ func (m *Stats) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SamplesProcessed", wireType)
+			}
+			m.SamplesProcessed = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SamplesProcessed |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SamplesProcessedPerStep", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SamplesProcessedPerStep = append(m.SamplesProcessedPerStep, StepStat{})
+			if err := m.SamplesProcessedPerStep[len(m.SamplesProcessedPerStep)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StepStat) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StepStat: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StepStat: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			m.Value = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Value |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStats(dAtA[iNdEx:])
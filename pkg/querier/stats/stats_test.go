@@ -7,6 +7,7 @@ package stats
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -142,6 +143,8 @@ func TestStats_Merge(t *testing.T) {
 		stats1.AddShardedQueries(20)
 		stats1.AddSplitQueries(10)
 		stats1.AddQueueTime(5 * time.Second)
+		stats1.AddSamplesProcessed(100)
+		stats1.AddSamplesProcessedPerStep([]StepStat{{Timestamp: 1000, Value: 10}, {Timestamp: 2000, Value: 20}})
 
 		stats2 := &Stats{}
 		stats2.AddWallTime(time.Second)
@@ -151,6 +154,8 @@ func TestStats_Merge(t *testing.T) {
 		stats2.AddShardedQueries(21)
 		stats2.AddSplitQueries(11)
 		stats2.AddQueueTime(10 * time.Second)
+		stats2.AddSamplesProcessed(50)
+		stats2.AddSamplesProcessedPerStep([]StepStat{{Timestamp: 2000, Value: 5}, {Timestamp: 3000, Value: 15}})
 
 		stats1.Merge(stats2)
 
@@ -161,6 +166,8 @@ func TestStats_Merge(t *testing.T) {
 		assert.Equal(t, uint32(41), stats1.LoadShardedQueries())
 		assert.Equal(t, uint32(21), stats1.LoadSplitQueries())
 		assert.Equal(t, 15*time.Second, stats1.LoadQueueTime())
+		assert.Equal(t, uint64(150), stats1.LoadSamplesProcessed())
+		assert.Equal(t, []StepStat{{Timestamp: 1000, Value: 10}, {Timestamp: 2000, Value: 25}, {Timestamp: 3000, Value: 15}}, stats1.LoadSamplesProcessedPerStep())
 	})
 
 	t.Run("merge two nil stats objects", func(t *testing.T) {
@@ -176,6 +183,8 @@ func TestStats_Merge(t *testing.T) {
 		assert.Equal(t, uint32(0), stats1.LoadShardedQueries())
 		assert.Equal(t, uint32(0), stats1.LoadSplitQueries())
 		assert.Equal(t, time.Duration(0), stats1.LoadQueueTime())
+		assert.Equal(t, uint64(0), stats1.LoadSamplesProcessed())
+		assert.Nil(t, stats1.LoadSamplesProcessedPerStep())
 	})
 }
 
@@ -190,6 +199,11 @@ func TestStats_Copy(t *testing.T) {
 		FetchedIndexBytes:    7,
 		EstimatedSeriesCount: 8,
 		QueueTime:            9,
+		SamplesProcessed:     10,
+		SamplesProcessedPerStep: []StepStat{
+			{Timestamp: 1000, Value: 4},
+			{Timestamp: 2000, Value: 6},
+		},
 	}
 	s2 := s1.Copy()
 	assert.NotSame(t, s1, s2)
@@ -197,3 +211,32 @@ func TestStats_Copy(t *testing.T) {
 
 	assert.Nil(t, (*Stats)(nil).Copy())
 }
+
+func TestStats_Equal(t *testing.T) {
+	s1 := &Stats{FetchedSeriesCount: 1, SamplesProcessedPerStep: []StepStat{{Timestamp: 1000, Value: 4}}}
+
+	assert.True(t, s1.Equal(s1.Copy()))
+	assert.False(t, s1.Equal(&Stats{FetchedSeriesCount: 1}))
+	assert.False(t, s1.Equal(&Stats{SamplesProcessedPerStep: []StepStat{{Timestamp: 1000, Value: 4}}}))
+	assert.False(t, s1.Equal(nil))
+	assert.True(t, (*Stats)(nil).Equal(nil))
+	assert.True(t, (*Stats)(nil).Equal((*Stats)(nil)))
+}
+
+func TestStats_AddSamplesProcessedPerStepConcurrently(t *testing.T) {
+	const concurrency = 10
+
+	s := &Stats{}
+	wg := sync.WaitGroup{}
+	wg.Add(concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			s.AddSamplesProcessedPerStep([]StepStat{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}})
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, []StepStat{{Timestamp: 1000, Value: concurrency}, {Timestamp: 2000, Value: 2 * concurrency}}, s.LoadSamplesProcessedPerStep())
+}
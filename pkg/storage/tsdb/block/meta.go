@@ -73,6 +73,10 @@ type ThanosMeta struct {
 	// Useful to avoid API call to get size of each file, as well as for debugging purposes.
 	// Optional, added in v0.17.0.
 	Files []File `json:"files,omitempty"`
+
+	// SeriesRetentionRules is the list of per-series retention rules, in the "<selector>[<period>]" format,
+	// whose expired samples have all been deleted from the block by the compactor. Optional.
+	SeriesRetentionRules []string `json:"series_retention_rules,omitempty"`
 }
 
 type Matchers []*labels.Matcher
@@ -123,6 +123,8 @@ var (
 	errInvalidEarlyHeadCompactionMinSeriesReduction = errors.New("early compaction minimum series reduction percentage must be a value between 0 and 100 (included)")
 	errEarlyCompactionRequiresActiveSeries          = fmt.Errorf("early compaction requires -%s to be enabled", activeseries.EnabledFlag)
 	errEmptyBlockranges                             = errors.New("empty block ranges for TSDB")
+	errInvalidHeadSnapshotInterval                  = errors.New("invalid TSDB head snapshot interval")
+	errHeadSnapshotUploadRequiresInterval           = errors.New("TSDB head snapshot upload requires the head snapshot interval to be set")
 )
 
 // BlocksStorageConfig holds the config information for the blocks storage.
@@ -210,6 +212,8 @@ type TSDBConfig struct {
 	FlushBlocksOnShutdown     bool          `yaml:"flush_blocks_on_shutdown" category:"advanced"`
 	CloseIdleTSDBTimeout      time.Duration `yaml:"close_idle_tsdb_timeout" category:"advanced"`
 	MemorySnapshotOnShutdown  bool          `yaml:"memory_snapshot_on_shutdown" category:"experimental"`
+	HeadSnapshotInterval      time.Duration `yaml:"head_snapshot_interval" category:"experimental"`
+	HeadSnapshotUploadEnabled bool          `yaml:"head_snapshot_upload_enabled" category:"experimental"`
 	HeadChunksWriteQueueSize  int           `yaml:"head_chunks_write_queue_size" category:"advanced"`
 
 	// Series hash cache.
@@ -302,6 +306,8 @@ func (cfg *TSDBConfig) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.FlushBlocksOnShutdown, "blocks-storage.tsdb.flush-blocks-on-shutdown", false, "True to flush blocks to storage on shutdown. If false, incomplete blocks will be reused after restart.")
 	f.DurationVar(&cfg.CloseIdleTSDBTimeout, "blocks-storage.tsdb.close-idle-tsdb-timeout", 13*time.Hour, "If TSDB has not received any data for this duration, and all blocks from TSDB have been shipped, TSDB is closed and deleted from local disk. If set to positive value, this value should be equal or higher than -querier.query-ingesters-within flag to make sure that TSDB is not closed prematurely, which could cause partial query results. 0 or negative value disables closing of idle TSDB.")
 	f.BoolVar(&cfg.MemorySnapshotOnShutdown, "blocks-storage.tsdb.memory-snapshot-on-shutdown", false, "True to enable snapshotting of in-memory TSDB data on disk when shutting down.")
+	f.DurationVar(&cfg.HeadSnapshotInterval, "blocks-storage.tsdb.head-snapshot-interval", 0, "How frequently the ingester writes a snapshot of the in-memory TSDB head of each tenant on disk. On startup, the TSDB head is restored from the latest snapshot, and only the WAL written after the snapshot is replayed. When enabled, a snapshot is written on shutdown too. 0 to disable.")
+	f.BoolVar(&cfg.HeadSnapshotUploadEnabled, "blocks-storage.tsdb.head-snapshot-upload-enabled", false, "True to upload the TSDB head snapshots to the storage, along with the m-mapped head chunks and the WAL written after the snapshot. On startup, the TSDB of the tenants without a TSDB on disk is restored from the latest snapshot uploaded by the ingester. Requires -blocks-storage.tsdb.head-snapshot-interval to be set.")
 	f.IntVar(&cfg.HeadChunksWriteQueueSize, "blocks-storage.tsdb.head-chunks-write-queue-size", 1000000, headChunksWriteQueueSizeHelp)
 	f.IntVar(&cfg.OutOfOrderCapacityMax, "blocks-storage.tsdb.out-of-order-capacity-max", 32, "Maximum capacity for out of order chunks, in samples between 1 and 255.")
 	f.DurationVar(&cfg.HeadPostingsForMatchersCacheTTL, "blocks-storage.tsdb.head-postings-for-matchers-cache-ttl", tsdb.DefaultPostingsForMatchersCacheTTL, "How long to cache postings for matchers in the Head and OOOHead. 0 disables the cache and just deduplicates the in-flight calls.")
@@ -354,6 +360,14 @@ func (cfg *TSDBConfig) Validate(activeSeriesCfg activeseries.Config) error {
 		return errInvalidWALReplayConcurrency
 	}
 
+	if cfg.HeadSnapshotInterval < 0 {
+		return errInvalidHeadSnapshotInterval
+	}
+
+	if cfg.HeadSnapshotUploadEnabled && !cfg.IsHeadSnapshotEnabled() {
+		return errHeadSnapshotUploadRequiresInterval
+	}
+
 	if cfg.EarlyHeadCompactionMinInMemorySeries > 0 && !activeSeriesCfg.Enabled {
 		return errEarlyCompactionRequiresActiveSeries
 	}
@@ -384,6 +398,11 @@ func (cfg *TSDBConfig) IsBlocksShippingEnabled() bool {
 	return cfg.ShipInterval > 0
 }
 
+// IsHeadSnapshotEnabled returns whether the TSDB head is periodically snapshotted on disk.
+func (cfg *TSDBConfig) IsHeadSnapshotEnabled() bool {
+	return cfg.HeadSnapshotInterval > 0
+}
+
 // BucketStoreConfig holds the config information for Bucket Stores used by the querier and store-gateway.
 type BucketStoreConfig struct {
 	SyncDir                   string              `yaml:"sync_dir"`
@@ -134,6 +134,24 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: errInvalidEarlyHeadCompactionMinSeriesReduction,
 		},
+		"should fail on negative head snapshot interval": {
+			setup: func(cfg *BlocksStorageConfig, _ *activeseries.Config) {
+				cfg.TSDB.HeadSnapshotInterval = -time.Minute
+			},
+			expectedErr: errInvalidHeadSnapshotInterval,
+		},
+		"should fail on head snapshot upload without head snapshot interval": {
+			setup: func(cfg *BlocksStorageConfig, _ *activeseries.Config) {
+				cfg.TSDB.HeadSnapshotUploadEnabled = true
+			},
+			expectedErr: errHeadSnapshotUploadRequiresInterval,
+		},
+		"should pass on head snapshot upload with head snapshot interval": {
+			setup: func(cfg *BlocksStorageConfig, _ *activeseries.Config) {
+				cfg.TSDB.HeadSnapshotInterval = 15 * time.Minute
+				cfg.TSDB.HeadSnapshotUploadEnabled = true
+			},
+		},
 	}
 
 	for testName, testData := range tests {
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tsdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+// SeriesDeletionRequestsPath is the location of series deletion requests. Relative to user-specific prefix.
+const SeriesDeletionRequestsPath = "series-deletion-requests"
+
+const (
+	SeriesDeletionRequestStatusPending   = "pending"
+	SeriesDeletionRequestStatusProcessed = "processed"
+)
+
+var (
+	errNoSeriesDeletionSelectors    = errors.New("at least one series selector is required")
+	errInvalidSeriesDeletionRange   = errors.New("end time must be greater than or equal to start time")
+	errEmptySeriesDeletionSelectors = errors.New("series selectors must contain at least one non-empty matcher")
+)
+
+// SeriesDeletionRequest is a request to delete the samples of the series matching any of
+// the selectors within the time range. Deleted samples are filtered out at query time, and
+// physically removed from blocks by the compactor.
+type SeriesDeletionRequest struct {
+	// RequestID is a ULID, generated when the request is created.
+	RequestID string `json:"request_id"`
+
+	// Selectors are the series selectors matching the series to delete.
+	Selectors []string `json:"selectors"`
+
+	// StartTime and EndTime are the time range of the samples to delete, in milliseconds, inclusive.
+	StartTime int64 `json:"start_time"`
+	EndTime   int64 `json:"end_time"`
+
+	// Unix timestamp when the request was created.
+	CreationTime util.UnixSeconds `json:"creation_time"`
+
+	// Unix timestamp when the compactor finished deleting the samples from the blocks that existed when the request was created.
+	ProcessedTime util.UnixSeconds `json:"processed_time,omitempty"`
+}
+
+// NewSeriesDeletionRequest validates the input selectors and time range, and returns a new series deletion request.
+func NewSeriesDeletionRequest(selectors []string, startTime, endTime int64, creationTime time.Time) (*SeriesDeletionRequest, error) {
+	if len(selectors) == 0 {
+		return nil, errNoSeriesDeletionSelectors
+	}
+	if endTime < startTime {
+		return nil, errInvalidSeriesDeletionRange
+	}
+
+	r := &SeriesDeletionRequest{
+		RequestID:    ulid.MustNew(ulid.Timestamp(creationTime), rand.Reader).String(),
+		Selectors:    selectors,
+		StartTime:    startTime,
+		EndTime:      endTime,
+		CreationTime: util.UnixSecondsFromTime(creationTime),
+	}
+
+	// Ensure the selectors are valid.
+	if _, err := r.Matchers(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Matchers returns the parsed matchers of each selector of the request.
+func (r *SeriesDeletionRequest) Matchers() ([][]*labels.Matcher, error) {
+	matchers := make([][]*labels.Matcher, 0, len(r.Selectors))
+
+	for _, selector := range r.Selectors {
+		ms, err := parser.ParseMetricSelector(selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid series selector %q", selector)
+		}
+
+		// Deleting all series of a tenant must be done with the tenant deletion API.
+		if matchesEmptyLabelSet(ms) {
+			return nil, errEmptySeriesDeletionSelectors
+		}
+
+		matchers = append(matchers, ms)
+	}
+
+	return matchers, nil
+}
+
+// Overlaps returns whether the time range of the request overlaps with the [minT, maxT] time range, inclusive.
+func (r *SeriesDeletionRequest) Overlaps(minT, maxT int64) bool {
+	return r.StartTime <= maxT && minT <= r.EndTime
+}
+
+// Status returns the status of the request.
+func (r *SeriesDeletionRequest) Status() string {
+	if r.ProcessedTime > 0 {
+		return SeriesDeletionRequestStatusProcessed
+	}
+	return SeriesDeletionRequestStatusPending
+}
+
+func matchesEmptyLabelSet(matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		if !m.Matches("") {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteSeriesDeletionRequest uploads the series deletion request to the tenant location in the bucket.
+func WriteSeriesDeletionRequest(ctx context.Context, bkt objstore.Bucket, userID string, cfgProvider bucket.TenantConfigProvider, r *SeriesDeletionRequest) error {
+	bkt = bucket.NewUserBucketClient(userID, bkt, cfgProvider)
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrap(err, "serialize series deletion request")
+	}
+
+	return errors.Wrap(bkt.Upload(ctx, seriesDeletionRequestPath(r.RequestID), bytes.NewReader(data)), "upload series deletion request")
+}
+
+// ReadSeriesDeletionRequests returns all series deletion requests of the given user, sorted by creation time.
+func ReadSeriesDeletionRequests(ctx context.Context, bkt objstore.BucketReader, userID string, logger log.Logger) ([]*SeriesDeletionRequest, error) {
+	var requests []*SeriesDeletionRequest
+
+	err := bkt.Iter(ctx, path.Join(userID, SeriesDeletionRequestsPath)+"/", func(name string) error {
+		if !strings.HasSuffix(name, ".json") {
+			return nil
+		}
+
+		r, err := readSeriesDeletionRequest(ctx, bkt, name, logger)
+		if err != nil {
+			return err
+		}
+
+		requests = append(requests, r)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "list series deletion requests")
+	}
+
+	// Request IDs are ULIDs, so sorting them sorts the requests by creation time.
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].RequestID < requests[j].RequestID
+	})
+
+	return requests, nil
+}
+
+func readSeriesDeletionRequest(ctx context.Context, bkt objstore.BucketReader, name string, logger log.Logger) (*SeriesDeletionRequest, error) {
+	r, err := bkt.Get(ctx, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read series deletion request object: %s", name)
+	}
+
+	req := &SeriesDeletionRequest{}
+	err = json.NewDecoder(r).Decode(req)
+
+	// Close reader before dealing with decode error.
+	if closeErr := r.Close(); closeErr != nil {
+		level.Warn(logger).Log("msg", "failed to close bucket reader", "err", closeErr)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode series deletion request object: %s", name)
+	}
+
+	return req, nil
+}
+
+func seriesDeletionRequestPath(requestID string) string {
+	return path.Join(SeriesDeletionRequestsPath, requestID+".json")
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tsdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+)
+
+func TestNewSeriesDeletionRequest(t *testing.T) {
+	now := time.Now()
+
+	for name, tc := range map[string]struct {
+		selectors   []string
+		start, end  int64
+		expectedErr string
+	}{
+		"valid request": {
+			selectors: []string{`{__name__="up"}`, `{job="test", pod=~"pod-.*"}`},
+			start:     10,
+			end:       20,
+		},
+		"no selectors": {
+			start:       10,
+			end:         20,
+			expectedErr: errNoSeriesDeletionSelectors.Error(),
+		},
+		"end before start": {
+			selectors:   []string{`{__name__="up"}`},
+			start:       20,
+			end:         10,
+			expectedErr: errInvalidSeriesDeletionRange.Error(),
+		},
+		"invalid selector": {
+			selectors:   []string{`{__name__="up"`},
+			start:       10,
+			end:         20,
+			expectedErr: "invalid series selector",
+		},
+		"selector matching all series": {
+			selectors:   []string{`{job=~".*"}`},
+			start:       10,
+			end:         20,
+			expectedErr: errEmptySeriesDeletionSelectors.Error(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewSeriesDeletionRequest(tc.selectors, tc.start, tc.end, now)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, r.RequestID)
+			assert.Equal(t, tc.selectors, r.Selectors)
+			assert.Equal(t, tc.start, r.StartTime)
+			assert.Equal(t, tc.end, r.EndTime)
+			assert.Equal(t, now.Unix(), int64(r.CreationTime))
+			assert.Equal(t, SeriesDeletionRequestStatusPending, r.Status())
+
+			matchers, err := r.Matchers()
+			require.NoError(t, err)
+			assert.Len(t, matchers, len(tc.selectors))
+		})
+	}
+}
+
+func TestSeriesDeletionRequest_Overlaps(t *testing.T) {
+	r := &SeriesDeletionRequest{StartTime: 10, EndTime: 20}
+
+	assert.True(t, r.Overlaps(0, 10))
+	assert.True(t, r.Overlaps(15, 16))
+	assert.True(t, r.Overlaps(20, 30))
+	assert.True(t, r.Overlaps(0, 30))
+	assert.False(t, r.Overlaps(0, 9))
+	assert.False(t, r.Overlaps(21, 30))
+}
+
+func TestWriteAndReadSeriesDeletionRequests(t *testing.T) {
+	const userID = "user"
+
+	ctx := context.Background()
+	bkt := objstore.NewInMemBucket()
+
+	requests, err := ReadSeriesDeletionRequests(ctx, bkt, userID, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Empty(t, requests)
+
+	first, err := NewSeriesDeletionRequest([]string{`{__name__="first"}`}, 10, 20, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	second, err := NewSeriesDeletionRequest([]string{`{__name__="second"}`}, 30, 40, time.Now())
+	require.NoError(t, err)
+
+	require.NoError(t, WriteSeriesDeletionRequest(ctx, bkt, userID, nil, second))
+	require.NoError(t, WriteSeriesDeletionRequest(ctx, bkt, userID, nil, first))
+
+	requests, err = ReadSeriesDeletionRequests(ctx, bkt, userID, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Equal(t, []*SeriesDeletionRequest{first, second}, requests)
+
+	// Requests of other tenants are not returned.
+	requests, err = ReadSeriesDeletionRequests(ctx, bkt, "another-user", log.NewNopLogger())
+	require.NoError(t, err)
+	require.Empty(t, requests)
+
+	// Updating a request overwrites it.
+	first.ProcessedTime = first.CreationTime + 10
+	require.NoError(t, WriteSeriesDeletionRequest(ctx, bkt, userID, nil, first))
+
+	requests, err = ReadSeriesDeletionRequests(ctx, bkt, userID, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Equal(t, []*SeriesDeletionRequest{first, second}, requests)
+	require.Equal(t, SeriesDeletionRequestStatusProcessed, requests[0].Status())
+}
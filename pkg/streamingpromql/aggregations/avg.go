@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package aggregations
+
+import (
+	"math"
+
+	"github.com/prometheus/prometheus/model/histogram"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+type AvgAggregationGroup struct {
+	floatMeans   []float64
+	floatCounts  []float64
+	floatPresent []bool
+
+	histogramMeans  []*histogram.FloatHistogram
+	histogramCounts []float64
+}
+
+func (g *AvgAggregationGroup) AccumulateSeries(data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error {
+	if err := g.accumulateFloats(data, steps, start, interval, pool); err != nil {
+		return err
+	}
+
+	return g.accumulateHistograms(data, steps, start, interval, pool)
+}
+
+func (g *AvgAggregationGroup) accumulateFloats(data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error {
+	if len(data.Floats) == 0 {
+		return nil
+	}
+
+	var err error
+	if g.floatMeans == nil {
+		// First series with float values for this group, populate it.
+		if g.floatMeans, err = getZeroedFloatSlice(steps, pool); err != nil {
+			return err
+		}
+
+		if g.floatCounts, err = getZeroedFloatSlice(steps, pool); err != nil {
+			return err
+		}
+
+		if g.floatPresent, err = getZeroedBoolSlice(steps, pool); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range data.Floats {
+		idx := (p.T - start) / interval
+		g.floatCounts[idx]++
+
+		if !g.floatPresent[idx] {
+			g.floatPresent[idx] = true
+			g.floatMeans[idx] = p.F
+			continue
+		}
+
+		if math.IsInf(g.floatMeans[idx], 0) {
+			if math.IsInf(p.F, 0) && (g.floatMeans[idx] > 0) == (p.F > 0) {
+				// The mean and the new value are infinities of the same sign. They can't be subtracted,
+				// but the value of the mean is correct already.
+				continue
+			}
+
+			if !math.IsInf(p.F, 0) && !math.IsNaN(p.F) {
+				// At this stage, the mean is an infinite. If the added value is neither an Inf or a NaN,
+				// we can keep that mean value.
+				// This is required because our calculation below removes the mean value, which would
+				// look like Inf += x - Inf and end up as a NaN.
+				continue
+			}
+		}
+
+		// Divide each side of the `-` by the count to avoid float64 overflows.
+		g.floatMeans[idx] += p.F/g.floatCounts[idx] - g.floatMeans[idx]/g.floatCounts[idx]
+	}
+
+	return nil
+}
+
+func (g *AvgAggregationGroup) accumulateHistograms(data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error {
+	if len(data.Histograms) == 0 {
+		return nil
+	}
+
+	var err error
+	if g.histogramMeans == nil {
+		// First series with histogram values for this group, populate it.
+		if g.histogramMeans, err = getNilHistogramSlice(steps, pool); err != nil {
+			return err
+		}
+
+		if g.histogramCounts, err = getZeroedFloatSlice(steps, pool); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range data.Histograms {
+		idx := (p.T - start) / interval
+		g.histogramCounts[idx]++
+
+		if g.histogramMeans[idx] == nil {
+			// We copy here because we modify the histogram later on.
+			// It is necessary to preserve the original histogram in case of any range queries using lookback.
+			g.histogramMeans[idx] = p.H.Copy()
+			continue
+		}
+
+		count := g.histogramCounts[idx]
+		left := p.H.Copy().Div(count)
+		right := g.histogramMeans[idx].Copy().Div(count)
+		g.histogramMeans[idx] = g.histogramMeans[idx].Add(left.Sub(right))
+	}
+
+	return nil
+}
+
+func (g *AvgAggregationGroup) ComputeOutputSeries(start int64, interval int64, pool *pooling.LimitingPool) (types.InstantVectorSeriesData, error) {
+	removeMixedFloatsAndHistograms(g.floatPresent, g.histogramMeans)
+
+	for i, h := range g.histogramMeans {
+		if h != nil {
+			g.histogramMeans[i] = h.Compact(0)
+		}
+	}
+
+	pool.PutFloatSlice(g.floatCounts)
+	pool.PutFloatSlice(g.histogramCounts)
+
+	floatPoints, err := floatPointsFromPresentValues(g.floatMeans, g.floatPresent, start, interval, pool)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	histogramPoints, err := histogramPointsFromValues(g.histogramMeans, start, interval, pool)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	return types.InstantVectorSeriesData{Floats: floatPoints, Histograms: histogramPoints}, nil
+}
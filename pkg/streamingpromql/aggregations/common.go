@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package aggregations
+
+import (
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// AggregationGroup accumulates series that have been grouped together and computes the output series data.
+type AggregationGroup interface {
+	// AccumulateSeries takes in a series as part of the group.
+	// AccumulateSeries must not retain data or any of its slices: the caller returns them to the pool once AccumulateSeries returns.
+	AccumulateSeries(data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error
+
+	// ComputeOutputSeries does any final calculations and returns the grouped series data.
+	// ComputeOutputSeries also returns all resources held by this group to the pool, so the group must not be used after calling it.
+	ComputeOutputSeries(start int64, interval int64, pool *pooling.LimitingPool) (types.InstantVectorSeriesData, error)
+}
+
+type AggregationGroupFactory func() AggregationGroup
+
+// AggregationGroupFactories contains factories for all aggregations that produce exactly one output series per group
+// and do not take a parameter.
+//
+// Aggregations that take a parameter (eg. quantile) have their factories created when the aggregation's parameter is known.
+var AggregationGroupFactories = map[parser.ItemType]AggregationGroupFactory{
+	parser.AVG:    func() AggregationGroup { return &AvgAggregationGroup{} },
+	parser.COUNT:  func() AggregationGroup { return &CountAggregationGroup{} },
+	parser.GROUP:  func() AggregationGroup { return &GroupAggregationGroup{} },
+	parser.MAX:    func() AggregationGroup { return NewMaxAggregationGroup() },
+	parser.MIN:    func() AggregationGroup { return NewMinAggregationGroup() },
+	parser.STDDEV: func() AggregationGroup { return &StddevStdvarAggregationGroup{Stddev: true} },
+	parser.STDVAR: func() AggregationGroup { return &StddevStdvarAggregationGroup{Stddev: false} },
+	parser.SUM:    func() AggregationGroup { return &SumAggregationGroup{} },
+}
+
+// floatPointsFromPresentValues returns a slice of points for each step with a value present.
+// It returns values and present to the pool.
+func floatPointsFromPresentValues(values []float64, present []bool, start int64, interval int64, pool *pooling.LimitingPool) ([]promql.FPoint, error) {
+	defer pool.PutFloatSlice(values)
+	defer pool.PutBoolSlice(present)
+
+	count := 0
+	for _, p := range present {
+		if p {
+			count++
+		}
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	points, err := pool.GetFPointSlice(count)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, havePoint := range present {
+		if havePoint {
+			t := start + int64(i)*interval
+			points = append(points, promql.FPoint{T: t, F: values[i]})
+		}
+	}
+
+	return points, nil
+}
+
+// histogramPointsFromValues returns a slice of points for each step with a non-nil histogram.
+// It returns values to the pool.
+func histogramPointsFromValues(values []*histogram.FloatHistogram, start int64, interval int64, pool *pooling.LimitingPool) ([]promql.HPoint, error) {
+	defer pool.PutHistogramPointerSlice(values)
+
+	count := 0
+	for _, h := range values {
+		if h != nil {
+			count++
+		}
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	points, err := pool.GetHPointSlice(count)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, h := range values {
+		if h != nil {
+			t := start + int64(i)*interval
+			points = append(points, promql.HPoint{T: t, H: h})
+		}
+	}
+
+	return points, nil
+}
+
+// getZeroedFloatSlice returns a slice of length steps with all values set to 0.
+func getZeroedFloatSlice(steps int, pool *pooling.LimitingPool) ([]float64, error) {
+	s, err := pool.GetFloatSlice(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	return s[:steps], nil
+}
+
+// getZeroedBoolSlice returns a slice of length steps with all values set to false.
+func getZeroedBoolSlice(steps int, pool *pooling.LimitingPool) ([]bool, error) {
+	s, err := pool.GetBoolSlice(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	return s[:steps], nil
+}
+
+// getNilHistogramSlice returns a slice of length steps with all values set to nil.
+func getNilHistogramSlice(steps int, pool *pooling.LimitingPool) ([]*histogram.FloatHistogram, error) {
+	s, err := pool.GetHistogramPointerSlice(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	return s[:steps], nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package aggregations
+
+import (
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// CountAggregationGroup counts the number of float and histogram samples at each step.
+type CountAggregationGroup struct {
+	counts []float64
+}
+
+func (g *CountAggregationGroup) AccumulateSeries(data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error {
+	return accumulateCounts(&g.counts, data, steps, start, interval, pool)
+}
+
+func (g *CountAggregationGroup) ComputeOutputSeries(start int64, interval int64, pool *pooling.LimitingPool) (types.InstantVectorSeriesData, error) {
+	return countsToSeriesData(g.counts, false, start, interval, pool)
+}
+
+// GroupAggregationGroup produces 1 for each step where at least one float or histogram sample is present.
+type GroupAggregationGroup struct {
+	counts []float64
+}
+
+func (g *GroupAggregationGroup) AccumulateSeries(data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error {
+	return accumulateCounts(&g.counts, data, steps, start, interval, pool)
+}
+
+func (g *GroupAggregationGroup) ComputeOutputSeries(start int64, interval int64, pool *pooling.LimitingPool) (types.InstantVectorSeriesData, error) {
+	return countsToSeriesData(g.counts, true, start, interval, pool)
+}
+
+func accumulateCounts(counts *[]float64, data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error {
+	if len(data.Floats) == 0 && len(data.Histograms) == 0 {
+		return nil
+	}
+
+	if *counts == nil {
+		var err error
+		if *counts, err = getZeroedFloatSlice(steps, pool); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range data.Floats {
+		idx := (p.T - start) / interval
+		(*counts)[idx]++
+	}
+
+	for _, p := range data.Histograms {
+		idx := (p.T - start) / interval
+		(*counts)[idx]++
+	}
+
+	return nil
+}
+
+// countsToSeriesData returns a point for each step with a non-zero count.
+// If groupOnly is true, each point has value 1, otherwise each point's value is the count.
+// It returns counts to the pool.
+func countsToSeriesData(counts []float64, groupOnly bool, start int64, interval int64, pool *pooling.LimitingPool) (types.InstantVectorSeriesData, error) {
+	defer pool.PutFloatSlice(counts)
+
+	pointCount := 0
+	for _, c := range counts {
+		if c > 0 {
+			pointCount++
+		}
+	}
+
+	if pointCount == 0 {
+		return types.InstantVectorSeriesData{}, nil
+	}
+
+	points, err := pool.GetFPointSlice(pointCount)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	for i, c := range counts {
+		if c == 0 {
+			continue
+		}
+
+		if groupOnly {
+			c = 1
+		}
+
+		t := start + int64(i)*interval
+		points = append(points, promql.FPoint{T: t, F: c})
+	}
+
+	return types.InstantVectorSeriesData{Floats: points}, nil
+}
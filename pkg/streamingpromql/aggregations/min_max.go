@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package aggregations
+
+import (
+	"math"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// MinMaxAggregationGroup computes min or max over float samples.
+// Native histograms are ignored, as they have no natural ordering.
+type MinMaxAggregationGroup struct {
+	floatValues  []float64
+	floatPresent []bool
+
+	accumulatePoint func(idx int64, f float64)
+}
+
+func NewMaxAggregationGroup() *MinMaxAggregationGroup {
+	g := &MinMaxAggregationGroup{}
+	g.accumulatePoint = g.maxAccumulatePoint
+	return g
+}
+
+func NewMinAggregationGroup() *MinMaxAggregationGroup {
+	g := &MinMaxAggregationGroup{}
+	g.accumulatePoint = g.minAccumulatePoint
+	return g
+}
+
+func (g *MinMaxAggregationGroup) maxAccumulatePoint(idx int64, f float64) {
+	if !g.floatPresent[idx] || g.floatValues[idx] < f || math.IsNaN(g.floatValues[idx]) {
+		g.floatValues[idx] = f
+		g.floatPresent[idx] = true
+	}
+}
+
+func (g *MinMaxAggregationGroup) minAccumulatePoint(idx int64, f float64) {
+	if !g.floatPresent[idx] || g.floatValues[idx] > f || math.IsNaN(g.floatValues[idx]) {
+		g.floatValues[idx] = f
+		g.floatPresent[idx] = true
+	}
+}
+
+func (g *MinMaxAggregationGroup) AccumulateSeries(data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error {
+	if len(data.Floats) == 0 {
+		return nil
+	}
+
+	var err error
+	if g.floatValues == nil {
+		// First series with float values for this group, populate it.
+		if g.floatValues, err = getZeroedFloatSlice(steps, pool); err != nil {
+			return err
+		}
+
+		if g.floatPresent, err = getZeroedBoolSlice(steps, pool); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range data.Floats {
+		idx := (p.T - start) / interval
+		g.accumulatePoint(idx, p.F)
+	}
+
+	return nil
+}
+
+func (g *MinMaxAggregationGroup) ComputeOutputSeries(start int64, interval int64, pool *pooling.LimitingPool) (types.InstantVectorSeriesData, error) {
+	floatPoints, err := floatPointsFromPresentValues(g.floatValues, g.floatPresent, start, interval, pool)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	return types.InstantVectorSeriesData{Floats: floatPoints}, nil
+}
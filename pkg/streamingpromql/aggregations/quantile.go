@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/quantile.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package aggregations
+
+import (
+	"math"
+	"slices"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// QuantileAggregationGroup computes the φ-quantile (0 ≤ φ ≤ 1) over float samples. Native histograms are ignored.
+type QuantileAggregationGroup struct {
+	q float64
+
+	// All float values seen for each step.
+	values [][]float64
+}
+
+func NewQuantileAggregationGroupFactory(q float64) AggregationGroupFactory {
+	return func() AggregationGroup {
+		return &QuantileAggregationGroup{q: q}
+	}
+}
+
+func (g *QuantileAggregationGroup) AccumulateSeries(data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error {
+	if len(data.Floats) == 0 {
+		return nil
+	}
+
+	if g.values == nil {
+		g.values = make([][]float64, steps)
+	}
+
+	for _, p := range data.Floats {
+		idx := (p.T - start) / interval
+		values := g.values[idx]
+
+		if len(values) == cap(values) {
+			// Either this is the first value for this step, or the slice is full. Get a bigger slice from the pool.
+			// We can't rely on append here, as that would bypass the pool's memory consumption tracking.
+			newValues, err := pool.GetFloatSlice(max(2*len(values), 1))
+			if err != nil {
+				return err
+			}
+
+			newValues = append(newValues, values...)
+			pool.PutFloatSlice(values)
+			values = newValues
+		}
+
+		g.values[idx] = append(values, p.F)
+	}
+
+	return nil
+}
+
+func (g *QuantileAggregationGroup) ComputeOutputSeries(start int64, interval int64, pool *pooling.LimitingPool) (types.InstantVectorSeriesData, error) {
+	if g.values == nil {
+		return types.InstantVectorSeriesData{}, nil
+	}
+
+	results, err := getZeroedFloatSlice(len(g.values), pool)
+	if err != nil {
+		g.close(pool)
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	present, err := getZeroedBoolSlice(len(g.values), pool)
+	if err != nil {
+		pool.PutFloatSlice(results)
+		g.close(pool)
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	for i, values := range g.values {
+		if len(values) == 0 {
+			continue
+		}
+
+		results[i] = Quantile(g.q, values)
+		present[i] = true
+	}
+
+	g.close(pool)
+
+	floatPoints, err := floatPointsFromPresentValues(results, present, start, interval, pool)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	return types.InstantVectorSeriesData{Floats: floatPoints}, nil
+}
+
+func (g *QuantileAggregationGroup) close(pool *pooling.LimitingPool) {
+	for _, values := range g.values {
+		pool.PutFloatSlice(values)
+	}
+
+	g.values = nil
+}
+
+// Quantile calculates the given quantile of values.
+//
+// values will be sorted in place.
+// If values has zero elements, NaN is returned.
+// If q==NaN, NaN is returned.
+// If q<0, -Inf is returned.
+// If q>1, +Inf is returned.
+func Quantile(q float64, values []float64) float64 {
+	if len(values) == 0 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+
+	// slices.Sort orders NaNs before all other values, consistent with Prometheus' engine.
+	slices.Sort(values)
+
+	n := float64(len(values))
+	// When the quantile lies between two samples,
+	// we use a weighted average of the two samples.
+	rank := q * (n - 1)
+
+	lowerIndex := math.Max(0, math.Floor(rank))
+	upperIndex := math.Min(n-1, lowerIndex+1)
+
+	weight := rank - math.Floor(rank)
+	return values[int(lowerIndex)]*(1-weight) + values[int(upperIndex)]*weight
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package aggregations
+
+import (
+	"math"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// StddevStdvarAggregationGroup computes the population standard deviation or variance over float samples
+// using Welford's online algorithm. Native histograms are ignored.
+type StddevStdvarAggregationGroup struct {
+	Stddev bool
+
+	// Sum of squared differences from the mean, for each step.
+	floats     []float64
+	floatMeans []float64
+	counts     []float64
+}
+
+func (g *StddevStdvarAggregationGroup) AccumulateSeries(data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error {
+	if len(data.Floats) == 0 {
+		return nil
+	}
+
+	var err error
+	if g.floats == nil {
+		// First series with float values for this group, populate it.
+		if g.floats, err = getZeroedFloatSlice(steps, pool); err != nil {
+			return err
+		}
+
+		if g.floatMeans, err = getZeroedFloatSlice(steps, pool); err != nil {
+			return err
+		}
+
+		if g.counts, err = getZeroedFloatSlice(steps, pool); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range data.Floats {
+		idx := (p.T - start) / interval
+		g.counts[idx]++
+		delta := p.F - g.floatMeans[idx]
+		g.floatMeans[idx] += delta / g.counts[idx]
+		g.floats[idx] += delta * (p.F - g.floatMeans[idx])
+	}
+
+	return nil
+}
+
+func (g *StddevStdvarAggregationGroup) ComputeOutputSeries(start int64, interval int64, pool *pooling.LimitingPool) (types.InstantVectorSeriesData, error) {
+	defer pool.PutFloatSlice(g.floatMeans)
+	defer pool.PutFloatSlice(g.counts)
+
+	var present []bool
+	if g.floats != nil {
+		var err error
+		if present, err = getZeroedBoolSlice(len(g.floats), pool); err != nil {
+			pool.PutFloatSlice(g.floats)
+			return types.InstantVectorSeriesData{}, err
+		}
+	}
+
+	for i, c := range g.counts {
+		if c == 0 {
+			continue
+		}
+
+		present[i] = true
+		g.floats[i] /= c
+
+		if g.Stddev {
+			g.floats[i] = math.Sqrt(g.floats[i])
+		}
+	}
+
+	floatPoints, err := floatPointsFromPresentValues(g.floats, present, start, interval, pool)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	return types.InstantVectorSeriesData{Floats: floatPoints}, nil
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package aggregations
+
+import (
+	"github.com/prometheus/prometheus/model/histogram"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+type SumAggregationGroup struct {
+	floatSums     []float64
+	floatPresent  []bool
+	histogramSums []*histogram.FloatHistogram
+}
+
+func (g *SumAggregationGroup) AccumulateSeries(data types.InstantVectorSeriesData, steps int, start int64, interval int64, pool *pooling.LimitingPool) error {
+	var err error
+	if len(data.Floats) > 0 && g.floatSums == nil {
+		// First series with float values for this group, populate it.
+		if g.floatSums, err = getZeroedFloatSlice(steps, pool); err != nil {
+			return err
+		}
+
+		if g.floatPresent, err = getZeroedBoolSlice(steps, pool); err != nil {
+			return err
+		}
+	}
+
+	if len(data.Histograms) > 0 && g.histogramSums == nil {
+		// First series with histogram values for this group, populate it.
+		if g.histogramSums, err = getNilHistogramSlice(steps, pool); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range data.Floats {
+		idx := (p.T - start) / interval
+		g.floatSums[idx] += p.F
+		g.floatPresent[idx] = true
+	}
+
+	for _, p := range data.Histograms {
+		idx := (p.T - start) / interval
+		if g.histogramSums[idx] == nil {
+			// We copy here because we modify the histogram through Add later on.
+			// It is necessary to preserve the original Histogram in case of any range-queries using lookback.
+			g.histogramSums[idx] = p.H.Copy()
+		} else {
+			g.histogramSums[idx] = g.histogramSums[idx].Add(p.H)
+		}
+	}
+
+	return nil
+}
+
+func (g *SumAggregationGroup) ComputeOutputSeries(start int64, interval int64, pool *pooling.LimitingPool) (types.InstantVectorSeriesData, error) {
+	removeMixedFloatsAndHistograms(g.floatPresent, g.histogramSums)
+
+	floatPoints, err := floatPointsFromPresentValues(g.floatSums, g.floatPresent, start, interval, pool)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	histogramPoints, err := histogramPointsFromValues(g.histogramSums, start, interval, pool)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	return types.InstantVectorSeriesData{Floats: floatPoints, Histograms: histogramPoints}, nil
+}
+
+// removeMixedFloatsAndHistograms removes both the float and histogram value for any step that has both.
+//
+// If an aggregation has to aggregate a mix of histogram samples and float samples, the corresponding vector element
+// is removed from the output vector entirely.
+func removeMixedFloatsAndHistograms(floatPresent []bool, histograms []*histogram.FloatHistogram) {
+	if len(floatPresent) == 0 || len(histograms) == 0 {
+		return
+	}
+
+	for idx, present := range floatPresent {
+		if present && histograms[idx] != nil {
+			floatPresent[idx] = false
+			histograms[idx] = nil
+		}
+	}
+}
@@ -94,19 +94,22 @@ func TestCases(metricSizes []int) []BenchCase {
 			Expr:  "rate(a_X[1m])",
 			Steps: 10000,
 		},
+		{
+			Expr: "rate(nh_X[1m])",
+		},
 		//// Holt-Winters and long ranges.
 		//{
 		//	Expr: "holt_winters(a_X[1d], 0.3, 0.3)",
 		//},
-		//{
-		//	Expr: "changes(a_X[1d])",
-		//},
+		{
+			Expr: "changes(a_X[1d])",
+		},
 		{
 			Expr: "rate(a_X[1d])",
 		},
-		//{
-		//	Expr: "absent_over_time(a_X[1d])",
-		//},
+		{
+			Expr: "absent_over_time(a_X[1d])",
+		},
 		//// Unary operators.
 		//{
 		//	Expr: "-a_X",
@@ -132,18 +135,18 @@ func TestCases(metricSizes []int) []BenchCase {
 		{
 			Expr: `a_2000 - b_2000{l="1234"}`,
 		},
-		//{
-		//	Expr: "a_X and b_X{l=~'.*[0-4]$'}",
-		//},
-		//{
-		//	Expr: "a_X or b_X{l=~'.*[0-4]$'}",
-		//},
-		//{
-		//	Expr: "a_X unless b_X{l=~'.*[0-4]$'}",
-		//},
-		//{
-		//	Expr: "a_X and b_X{l='notfound'}",
-		//},
+		{
+			Expr: "a_X and b_X{l=~'.*[0-4]$'}",
+		},
+		{
+			Expr: "a_X or b_X{l=~'.*[0-4]$'}",
+		},
+		{
+			Expr: "a_X unless b_X{l=~'.*[0-4]$'}",
+		},
+		{
+			Expr: "a_X and b_X{l='notfound'}",
+		},
 		//// Simple functions.
 		//{
 		//	Expr: "abs(a_X)",
@@ -158,28 +161,28 @@ func TestCases(metricSizes []int) []BenchCase {
 		{
 			Expr: "sum(a_X)",
 		},
-		//{
-		//	Expr: "sum without (l)(h_X)",
-		//},
-		//{
-		//	Expr: "sum without (le)(h_X)",
-		//},
+		{
+			Expr: "sum without (l)(h_X)",
+		},
+		{
+			Expr: "sum without (le)(h_X)",
+		},
 		{
 			Expr: "sum by (l)(h_X)",
 		},
 		{
 			Expr: "sum by (le)(h_X)",
 		},
-		//{
-		//	Expr: "count_values('value', h_X)",
-		//  Steps: 100,
-		//},
-		//{
-		//	Expr: "topk(1, a_X)",
-		//},
-		//{
-		//	Expr: "topk(5, a_X)",
-		//},
+		{
+			Expr:  "count_values('value', h_X)",
+			Steps: 100,
+		},
+		{
+			Expr: "topk(1, a_X)",
+		},
+		{
+			Expr: "topk(5, a_X)",
+		},
 		//// Combinations.
 		{
 			Expr: "rate(a_X[1m]) + rate(b_X[1m])",
@@ -190,19 +193,26 @@ func TestCases(metricSizes []int) []BenchCase {
 		{
 			Expr: "sum by (le)(rate(h_X[1m]))",
 		},
-		//{
-		//	Expr: "sum without (l)(rate(a_X[1m]))",
-		//},
-		//{
-		//	Expr: "sum without (l)(rate(a_X[1m])) / sum without (l)(rate(b_X[1m]))",
-		//},
-		//{
-		//	Expr: "histogram_quantile(0.9, rate(h_X[5m]))",
-		//},
-		//// Many-to-one join.
-		//{
-		//	Expr: "a_X + on(l) group_right a_one",
-		//},
+		{
+			Expr: "sum without (l)(rate(a_X[1m]))",
+		},
+		{
+			Expr: "sum without (l)(rate(a_X[1m])) / sum without (l)(rate(b_X[1m]))",
+		},
+		{
+			Expr: "histogram_quantile(0.9, rate(h_X[5m]))",
+		},
+		// Subqueries.
+		{
+			Expr: "sum_over_time(a_X[10m:3m])",
+		},
+		{
+			Expr: "max_over_time(rate(a_X[1m])[10m:1m])",
+		},
+		// Many-to-one join.
+		{
+			Expr: "a_X + on(l) group_right a_1",
+		},
 		//// Label compared to blank string.
 		//{
 		//	Expr:  "count({__name__!=\"\"})",
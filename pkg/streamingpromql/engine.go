@@ -41,10 +41,11 @@ func NewEngine(opts promql.EngineOpts, limitsProvider QueryLimitsProvider, metri
 	}
 
 	return &Engine{
-		lookbackDelta:      lookbackDelta,
-		timeout:            opts.Timeout,
-		limitsProvider:     limitsProvider,
-		activeQueryTracker: opts.ActiveQueryTracker,
+		lookbackDelta:            lookbackDelta,
+		timeout:                  opts.Timeout,
+		limitsProvider:           limitsProvider,
+		activeQueryTracker:       opts.ActiveQueryTracker,
+		noStepSubqueryIntervalFn: opts.NoStepSubqueryIntervalFn,
 
 		logger: logger,
 		estimatedPeakMemoryConsumption: promauto.With(opts.Reg).NewHistogram(prometheus.HistogramOpts{
@@ -57,10 +58,11 @@ func NewEngine(opts promql.EngineOpts, limitsProvider QueryLimitsProvider, metri
 }
 
 type Engine struct {
-	lookbackDelta      time.Duration
-	timeout            time.Duration
-	limitsProvider     QueryLimitsProvider
-	activeQueryTracker promql.QueryTracker
+	lookbackDelta            time.Duration
+	timeout                  time.Duration
+	limitsProvider           QueryLimitsProvider
+	activeQueryTracker       promql.QueryTracker
+	noStepSubqueryIntervalFn func(rangeMillis int64) int64
 
 	logger                                    log.Logger
 	estimatedPeakMemoryConsumption            prometheus.Histogram
@@ -86,21 +88,31 @@ func (e *Engine) NewRangeQuery(ctx context.Context, q storage.Queryable, opts pr
 type QueryLimitsProvider interface {
 	// GetMaxEstimatedMemoryConsumptionPerQuery returns the maximum estimated memory allowed to be consumed by a query in bytes, or 0 to disable the limit.
 	GetMaxEstimatedMemoryConsumptionPerQuery(ctx context.Context) (uint64, error)
+
+	// GetMaxConcurrencyPerQuery returns the maximum number of goroutines allowed to evaluate a query at once, or 0 or 1 to evaluate
+	// each query in a single goroutine.
+	GetMaxConcurrencyPerQuery(ctx context.Context) (int, error)
 }
 
 // NewStaticQueryLimitsProvider returns a QueryLimitsProvider that always returns the provided limits.
 //
 // This should generally only be used in tests.
-func NewStaticQueryLimitsProvider(maxEstimatedMemoryConsumptionPerQuery uint64) QueryLimitsProvider {
+func NewStaticQueryLimitsProvider(maxEstimatedMemoryConsumptionPerQuery uint64, maxConcurrencyPerQuery int) QueryLimitsProvider {
 	return staticQueryLimitsProvider{
 		maxEstimatedMemoryConsumptionPerQuery: maxEstimatedMemoryConsumptionPerQuery,
+		maxConcurrencyPerQuery:                maxConcurrencyPerQuery,
 	}
 }
 
 type staticQueryLimitsProvider struct {
 	maxEstimatedMemoryConsumptionPerQuery uint64
+	maxConcurrencyPerQuery                int
 }
 
 func (p staticQueryLimitsProvider) GetMaxEstimatedMemoryConsumptionPerQuery(_ context.Context) (uint64, error) {
 	return p.maxEstimatedMemoryConsumptionPerQuery, nil
 }
+
+func (p staticQueryLimitsProvider) GetMaxConcurrencyPerQuery(_ context.Context) (int, error) {
+	return p.maxConcurrencyPerQuery, nil
+}
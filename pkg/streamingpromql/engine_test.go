@@ -36,28 +36,14 @@ import (
 
 func TestUnsupportedPromQLFeatures(t *testing.T) {
 	opts := NewTestEngineOpts()
-	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
 	require.NoError(t, err)
 	ctx := context.Background()
 
 	// The goal of this is not to list every conceivable expression that is unsupported, but to cover all the
 	// different cases and make sure we produce a reasonable error message when these cases are encountered.
 	unsupportedExpressions := map[string]string{
-		"1 + 2":                      "scalar value as top-level expression",
-		"1 + metric{}":               "binary expression with scalars",
-		"metric{} + 1":               "binary expression with scalars",
-		"metric{} < other_metric{}":  "binary expression with '<'",
-		"metric{} or other_metric{}": "binary expression with many-to-many matching",
-		"metric{} + on() group_left() other_metric{}":  "binary expression with many-to-one matching",
-		"metric{} + on() group_right() other_metric{}": "binary expression with one-to-many matching",
-		"1":                            "scalar value as top-level expression",
-		"metric{} offset 2h":           "instant vector selector with 'offset'",
-		"avg(metric{})":                "'avg' aggregation",
-		"sum without(l) (metric{})":    "grouping with 'without'",
-		"rate(metric{}[5m] offset 2h)": "range vector selector with 'offset'",
-		"rate(metric{}[5m:1m])":        "PromQL expression type *parser.SubqueryExpr",
-		"avg_over_time(metric{}[5m])":  "'avg_over_time' function",
-		"-sum(metric{})":               "PromQL expression type *parser.UnaryExpr",
+		"holt_winters(metric{}[5m], 0.3, 0.3)": "'holt_winters' function",
 	}
 
 	for expression, expectedError := range unsupportedExpressions {
@@ -78,9 +64,7 @@ func TestUnsupportedPromQLFeatures(t *testing.T) {
 
 	// These expressions are also unsupported, but are only valid as instant queries.
 	unsupportedInstantQueryExpressions := map[string]string{
-		"'a'":                    "string value as top-level expression",
-		"metric{}[5m] offset 2h": "range vector selector with 'offset'",
-		"metric{}[5m:1m]":        "PromQL expression type *parser.SubqueryExpr",
+		"'a'": "string value as top-level expression",
 	}
 
 	for expression, expectedError := range unsupportedInstantQueryExpressions {
@@ -96,7 +80,7 @@ func TestUnsupportedPromQLFeatures(t *testing.T) {
 
 func TestNewRangeQuery_InvalidQueryTime(t *testing.T) {
 	opts := NewTestEngineOpts()
-	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
 	require.NoError(t, err)
 	ctx := context.Background()
 
@@ -110,7 +94,7 @@ func TestNewRangeQuery_InvalidQueryTime(t *testing.T) {
 
 func TestNewRangeQuery_InvalidExpressionTypes(t *testing.T) {
 	opts := NewTestEngineOpts()
-	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
 	require.NoError(t, err)
 	ctx := context.Background()
 
@@ -126,7 +110,10 @@ func TestNewRangeQuery_InvalidExpressionTypes(t *testing.T) {
 // Once the streaming engine supports all PromQL features exercised by Prometheus' test cases, we can remove these files and instead call promql.RunBuiltinTests here instead.
 func TestUpstreamTestCases(t *testing.T) {
 	opts := NewTestEngineOpts()
-	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	require.NoError(t, err)
+
+	concurrentEngine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 4), stats.NewQueryMetrics(nil), log.NewNopLogger())
 	require.NoError(t, err)
 
 	testdataFS := os.DirFS("./testdata")
@@ -142,14 +129,23 @@ func TestUpstreamTestCases(t *testing.T) {
 			testScript, err := io.ReadAll(f)
 			require.NoError(t, err)
 
-			promqltest.RunTest(t, string(testScript), engine)
+			t.Run("single goroutine", func(t *testing.T) {
+				promqltest.RunTest(t, string(testScript), engine)
+			})
+
+			t.Run("concurrent evaluation", func(t *testing.T) {
+				promqltest.RunTest(t, string(testScript), concurrentEngine)
+			})
 		})
 	}
 }
 
 func TestOurTestCases(t *testing.T) {
 	opts := NewTestEngineOpts()
-	mimirEngine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	mimirEngine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	require.NoError(t, err)
+
+	mimirConcurrentEngine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 4), stats.NewQueryMetrics(nil), log.NewNopLogger())
 	require.NoError(t, err)
 
 	prometheusEngine := promql.NewEngine(opts)
@@ -173,6 +169,10 @@ func TestOurTestCases(t *testing.T) {
 				promqltest.RunTest(t, testScript, mimirEngine)
 			})
 
+			t.Run("Mimir's engine with concurrent evaluation", func(t *testing.T) {
+				promqltest.RunTest(t, testScript, mimirConcurrentEngine)
+			})
+
 			// Run the tests against Prometheus' engine to ensure our test cases are valid.
 			t.Run("Prometheus' engine", func(t *testing.T) {
 				promqltest.RunTest(t, testScript, prometheusEngine)
@@ -187,7 +187,7 @@ func TestOurTestCases(t *testing.T) {
 // So instead, we test these few cases here instead.
 func TestRangeVectorSelectors(t *testing.T) {
 	opts := NewTestEngineOpts()
-	mimirEngine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	mimirEngine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
 	require.NoError(t, err)
 
 	prometheusEngine := promql.NewEngine(opts)
@@ -300,7 +300,7 @@ func TestRangeVectorSelectors(t *testing.T) {
 
 func TestQueryCancellation(t *testing.T) {
 	opts := NewTestEngineOpts()
-	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
 	require.NoError(t, err)
 
 	// Simulate the query being cancelled by another goroutine by waiting for the Select() call to be made,
@@ -328,7 +328,7 @@ func TestQueryCancellation(t *testing.T) {
 func TestQueryTimeout(t *testing.T) {
 	opts := NewTestEngineOpts()
 	opts.Timeout = 20 * time.Millisecond
-	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
 	require.NoError(t, err)
 
 	// Simulate the query doing some work and check that the query context has been cancelled.
@@ -394,7 +394,7 @@ func (w cancellationQuerier) waitForCancellation(ctx context.Context) error {
 
 func TestQueryContextCancelledOnceQueryFinished(t *testing.T) {
 	opts := NewTestEngineOpts()
-	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
 	require.NoError(t, err)
 
 	storage := promqltest.LoadedStorage(t, `
@@ -601,12 +601,12 @@ func TestMemoryConsumptionLimit_SingleQueries(t *testing.T) {
 		},
 	}
 
-	createEngine := func(t *testing.T, limit uint64) (promql.QueryEngine, *prometheus.Registry, opentracing.Span, context.Context) {
+	createEngine := func(t *testing.T, limit uint64, maxConcurrency int) (promql.QueryEngine, *prometheus.Registry, opentracing.Span, context.Context) {
 		reg := prometheus.NewPedanticRegistry()
 		opts := NewTestEngineOpts()
 		opts.Reg = reg
 
-		engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(limit), stats.NewQueryMetrics(reg), log.NewNopLogger())
+		engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(limit, maxConcurrency), stats.NewQueryMetrics(reg), log.NewNopLogger())
 		require.NoError(t, err)
 
 		tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), jaeger.NewNullReporter())
@@ -634,39 +634,44 @@ func TestMemoryConsumptionLimit_SingleQueries(t *testing.T) {
 
 	start := timestamp.Time(0)
 
-	for name, testCase := range testCases {
-		t.Run(name, func(t *testing.T) {
-			queryTypes := map[string]func(t *testing.T) (promql.Query, *prometheus.Registry, opentracing.Span, context.Context, uint64){
-				"range query": func(t *testing.T) (promql.Query, *prometheus.Registry, opentracing.Span, context.Context, uint64) {
-					engine, reg, span, ctx := createEngine(t, testCase.rangeQueryLimit)
-					q, err := engine.NewRangeQuery(ctx, storage, nil, testCase.expr, start, start.Add(4*time.Minute), time.Minute)
-					require.NoError(t, err)
-					return q, reg, span, ctx, testCase.rangeQueryExpectedPeak
-				},
-				"instant query": func(t *testing.T) (promql.Query, *prometheus.Registry, opentracing.Span, context.Context, uint64) {
-					engine, reg, span, ctx := createEngine(t, testCase.instantQueryLimit)
-					q, err := engine.NewInstantQuery(ctx, storage, nil, testCase.expr, start)
-					require.NoError(t, err)
-					return q, reg, span, ctx, testCase.instantQueryExpectedPeak
-				},
-			}
+	// The result and peak memory consumption of each query must be the same regardless of whether it is evaluated concurrently.
+	for _, maxConcurrency := range []int{1, 4} {
+		t.Run(fmt.Sprintf("max concurrency=%v", maxConcurrency), func(t *testing.T) {
+			for name, testCase := range testCases {
+				t.Run(name, func(t *testing.T) {
+					queryTypes := map[string]func(t *testing.T) (promql.Query, *prometheus.Registry, opentracing.Span, context.Context, uint64){
+						"range query": func(t *testing.T) (promql.Query, *prometheus.Registry, opentracing.Span, context.Context, uint64) {
+							engine, reg, span, ctx := createEngine(t, testCase.rangeQueryLimit, maxConcurrency)
+							q, err := engine.NewRangeQuery(ctx, storage, nil, testCase.expr, start, start.Add(4*time.Minute), time.Minute)
+							require.NoError(t, err)
+							return q, reg, span, ctx, testCase.rangeQueryExpectedPeak
+						},
+						"instant query": func(t *testing.T) (promql.Query, *prometheus.Registry, opentracing.Span, context.Context, uint64) {
+							engine, reg, span, ctx := createEngine(t, testCase.instantQueryLimit, maxConcurrency)
+							q, err := engine.NewInstantQuery(ctx, storage, nil, testCase.expr, start)
+							require.NoError(t, err)
+							return q, reg, span, ctx, testCase.instantQueryExpectedPeak
+						},
+					}
 
-			for queryType, createQuery := range queryTypes {
-				t.Run(queryType, func(t *testing.T) {
-					q, reg, span, ctx, expectedPeakMemoryConsumption := createQuery(t)
-					t.Cleanup(q.Close)
+					for queryType, createQuery := range queryTypes {
+						t.Run(queryType, func(t *testing.T) {
+							q, reg, span, ctx, expectedPeakMemoryConsumption := createQuery(t)
+							t.Cleanup(q.Close)
 
-					res := q.Exec(ctx)
+							res := q.Exec(ctx)
 
-					if testCase.shouldSucceed {
-						require.NoError(t, res.Err)
-						require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(rejectedMetrics(0)), "cortex_querier_queries_rejected_total"))
-					} else {
-						require.ErrorContains(t, res.Err, globalerror.MaxEstimatedMemoryConsumptionPerQuery.Error())
-						require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(rejectedMetrics(1)), "cortex_querier_queries_rejected_total"))
-					}
+							if testCase.shouldSucceed {
+								require.NoError(t, res.Err)
+								require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(rejectedMetrics(0)), "cortex_querier_queries_rejected_total"))
+							} else {
+								require.ErrorContains(t, res.Err, globalerror.MaxEstimatedMemoryConsumptionPerQuery.Error())
+								require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(rejectedMetrics(1)), "cortex_querier_queries_rejected_total"))
+							}
 
-					assertEstimatedPeakMemoryConsumption(t, reg, span, expectedPeakMemoryConsumption)
+							assertEstimatedPeakMemoryConsumption(t, reg, span, expectedPeakMemoryConsumption)
+						})
+					}
 				})
 			}
 		})
@@ -684,12 +689,20 @@ func TestMemoryConsumptionLimit_MultipleQueries(t *testing.T) {
 	`)
 	t.Cleanup(func() { require.NoError(t, storage.Close()) })
 
+	for _, maxConcurrency := range []int{1, 4} {
+		t.Run(fmt.Sprintf("max concurrency=%v", maxConcurrency), func(t *testing.T) {
+			testMemoryConsumptionLimitMultipleQueries(t, storage, maxConcurrency)
+		})
+	}
+}
+
+func testMemoryConsumptionLimitMultipleQueries(t *testing.T, storage storage.Queryable, maxConcurrency int) {
 	reg := prometheus.NewPedanticRegistry()
 	opts := NewTestEngineOpts()
 	opts.Reg = reg
 
 	limit := 3 * 8 * pooling.FPointSize // Allow up to three series with five points (which will be rounded up to 8, the nearest power of 2)
-	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(limit), stats.NewQueryMetrics(reg), log.NewNopLogger())
+	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(limit, maxConcurrency), stats.NewQueryMetrics(reg), log.NewNopLogger())
 	require.NoError(t, err)
 
 	runQuery := func(expr string, shouldSucceed bool) {
@@ -756,7 +769,7 @@ func TestActiveQueryTracker(t *testing.T) {
 			opts := NewTestEngineOpts()
 			tracker := &testQueryTracker{}
 			opts.ActiveQueryTracker = tracker
-			engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+			engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
 			require.NoError(t, err)
 
 			innerStorage := promqltest.LoadedStorage(t, "")
@@ -863,7 +876,7 @@ func TestActiveQueryTracker_WaitingForTrackerIncludesQueryTimeout(t *testing.T)
 	opts := NewTestEngineOpts()
 	opts.Timeout = 10 * time.Millisecond
 	opts.ActiveQueryTracker = tracker
-	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0), stats.NewQueryMetrics(nil), log.NewNopLogger())
+	engine, err := NewEngine(opts, NewStaticQueryLimitsProvider(0, 0), stats.NewQueryMetrics(nil), log.NewNopLogger())
 	require.NoError(t, err)
 
 	queryTypes := map[string]func() (promql.Query, error){
@@ -4,6 +4,7 @@ package streamingpromql
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/grafana/mimir/pkg/streamingpromql/functions"
 	"github.com/grafana/mimir/pkg/streamingpromql/operators"
@@ -11,7 +12,7 @@ import (
 	"github.com/grafana/mimir/pkg/streamingpromql/types"
 )
 
-type InstantVectorFunctionOperatorFactory func(args []types.Operator, pool *pooling.LimitingPool) (types.InstantVectorOperator, error)
+type InstantVectorFunctionOperatorFactory func(args []types.Operator, start time.Time, end time.Time, interval time.Duration, pool *pooling.LimitingPool) (types.InstantVectorOperator, error)
 
 // SingleInputVectorFunctionOperatorFactory creates an InstantVectorFunctionOperatorFactory for functions
 // that have exactly 1 argument (v instant-vector).
@@ -21,7 +22,7 @@ type InstantVectorFunctionOperatorFactory func(args []types.Operator, pool *pool
 //   - metadataFunc: The function for handling metadata
 //   - seriesDataFunc: The function to handle series data
 func SingleInputVectorFunctionOperatorFactory(name string, metadataFunc functions.SeriesMetadataFunction, seriesDataFunc functions.InstantVectorFunction) InstantVectorFunctionOperatorFactory {
-	return func(args []types.Operator, pool *pooling.LimitingPool) (types.InstantVectorOperator, error) {
+	return func(args []types.Operator, _ time.Time, _ time.Time, _ time.Duration, pool *pooling.LimitingPool) (types.InstantVectorOperator, error) {
 		if len(args) != 1 {
 			// Should be caught by the PromQL parser, but we check here for safety.
 			return nil, fmt.Errorf("expected exactly 1 argument for %s, got %v", name, len(args))
@@ -33,13 +34,17 @@ func SingleInputVectorFunctionOperatorFactory(name string, metadataFunc function
 			return nil, fmt.Errorf("expected an instant vector argument for %s, got %T", name, args[0])
 		}
 
-		return &operators.FunctionOverInstantVector{
+		var o types.InstantVectorOperator = &operators.FunctionOverInstantVector{
 			Inner: inner,
 			Pool:  pool,
 
 			MetadataFunc:   metadataFunc,
 			SeriesDataFunc: seriesDataFunc,
-		}, nil
+		}
+
+		// The function may produce multiple series with the same labels (eg. if it drops the metric name),
+		// which Prometheus' engine merges if they don't have points at the same time.
+		return &operators.DeduplicateAndMerge{Inner: o, Pool: pool, MergeSeriesWithDistinctTimestamps: true}, nil
 	}
 }
 
@@ -69,52 +74,181 @@ func LabelManipulationFunctionOperatorFactory(name string, metadataFunc function
 	return SingleInputVectorFunctionOperatorFactory(name, metadataFunc, functions.Passthrough)
 }
 
-func createRateFunctionOperator(args []types.Operator, pool *pooling.LimitingPool) (types.InstantVectorOperator, error) {
+// FunctionOverRangeVectorOperatorFactory creates an InstantVectorFunctionOperatorFactory for functions
+// that have exactly 1 argument (v range-vector).
+//
+// Parameters:
+//   - name: The name of the function.
+//   - metadataFunc: The function for handling metadata
+//   - stepFunc: The function to compute the output for each time step
+func FunctionOverRangeVectorOperatorFactory(name string, metadataFunc functions.SeriesMetadataFunction, stepFunc functions.RangeVectorStepFunction) InstantVectorFunctionOperatorFactory {
+	return func(args []types.Operator, _ time.Time, _ time.Time, _ time.Duration, pool *pooling.LimitingPool) (types.InstantVectorOperator, error) {
+		if len(args) != 1 {
+			// Should be caught by the PromQL parser, but we check here for safety.
+			return nil, fmt.Errorf("expected exactly 1 argument for %s, got %v", name, len(args))
+		}
+
+		inner, ok := args[0].(types.RangeVectorOperator)
+		if !ok {
+			// Should be caught by the PromQL parser, but we check here for safety.
+			return nil, fmt.Errorf("expected a range vector argument for %s, got %T", name, args[0])
+		}
+
+		var o types.InstantVectorOperator = &operators.FunctionOverRangeVector{
+			Inner: inner,
+			Pool:  pool,
+
+			MetadataFunc: metadataFunc,
+			StepFunc:     stepFunc,
+		}
+
+		// The function may produce multiple series with the same labels (eg. if it drops the metric name),
+		// which Prometheus' engine rejects if more than one of them has any points.
+		return &operators.DeduplicateAndMerge{Inner: o, Pool: pool, MergeSeriesWithDistinctTimestamps: false}, nil
+	}
+}
+
+// FunctionOverRangeVectorWithScalarOperatorFactory creates an InstantVectorFunctionOperatorFactory for functions
+// that have exactly 2 arguments: a range vector and a scalar.
+//
+// Parameters:
+//   - name: The name of the function.
+//   - scalarArgIndex: The index of the scalar argument (eg. 0 for quantile_over_time(φ scalar, v range-vector))
+//   - metadataFunc: The function for handling metadata
+//   - stepFunc: The function to compute the output for each time step
+func FunctionOverRangeVectorWithScalarOperatorFactory(name string, scalarArgIndex int, metadataFunc functions.SeriesMetadataFunction, stepFunc functions.RangeVectorStepFunction) InstantVectorFunctionOperatorFactory {
+	return func(args []types.Operator, _ time.Time, _ time.Time, _ time.Duration, pool *pooling.LimitingPool) (types.InstantVectorOperator, error) {
+		if len(args) != 2 {
+			// Should be caught by the PromQL parser, but we check here for safety.
+			return nil, fmt.Errorf("expected exactly 2 arguments for %s, got %v", name, len(args))
+		}
+
+		scalarArg, ok := args[scalarArgIndex].(types.ScalarOperator)
+		if !ok {
+			// Should be caught by the PromQL parser, but we check here for safety.
+			return nil, fmt.Errorf("expected a scalar argument for %s, got %T", name, args[scalarArgIndex])
+		}
+
+		inner, ok := args[1-scalarArgIndex].(types.RangeVectorOperator)
+		if !ok {
+			// Should be caught by the PromQL parser, but we check here for safety.
+			return nil, fmt.Errorf("expected a range vector argument for %s, got %T", name, args[1-scalarArgIndex])
+		}
+
+		var o types.InstantVectorOperator = &operators.FunctionOverRangeVector{
+			Inner:     inner,
+			ScalarArg: scalarArg,
+			Pool:      pool,
+
+			MetadataFunc: metadataFunc,
+			StepFunc:     stepFunc,
+		}
+
+		return &operators.DeduplicateAndMerge{Inner: o, Pool: pool, MergeSeriesWithDistinctTimestamps: false}, nil
+	}
+}
+
+func createAbsentOverTimeFunctionOperator(args []types.Operator, start time.Time, end time.Time, interval time.Duration, pool *pooling.LimitingPool) (types.InstantVectorOperator, error) {
 	if len(args) != 1 {
 		// Should be caught by the PromQL parser, but we check here for safety.
-		return nil, fmt.Errorf("expected exactly 1 argument for rate, got %v", len(args))
+		return nil, fmt.Errorf("expected exactly 1 argument for absent_over_time, got %v", len(args))
 	}
 
 	inner, ok := args[0].(types.RangeVectorOperator)
 	if !ok {
 		// Should be caught by the PromQL parser, but we check here for safety.
-		return nil, fmt.Errorf("expected a range vector argument for rate, got %T", args[0])
+		return nil, fmt.Errorf("expected a range vector argument for absent_over_time, got %T", args[0])
+	}
+
+	return operators.NewAbsentOverTime(inner, start, end, interval, pool), nil
+}
+
+func createHistogramQuantileFunctionOperator(args []types.Operator, start time.Time, end time.Time, interval time.Duration, pool *pooling.LimitingPool) (types.InstantVectorOperator, error) {
+	if len(args) != 2 {
+		// Should be caught by the PromQL parser, but we check here for safety.
+		return nil, fmt.Errorf("expected exactly 2 arguments for histogram_quantile, got %v", len(args))
+	}
+
+	phi, ok := args[0].(types.ScalarOperator)
+	if !ok {
+		// Should be caught by the PromQL parser, but we check here for safety.
+		return nil, fmt.Errorf("expected a scalar argument for histogram_quantile, got %T", args[0])
+	}
+
+	inner, ok := args[1].(types.InstantVectorOperator)
+	if !ok {
+		// Should be caught by the PromQL parser, but we check here for safety.
+		return nil, fmt.Errorf("expected an instant vector argument for histogram_quantile, got %T", args[1])
+	}
+
+	return operators.NewHistogramQuantileFunction(phi, inner, start, end, interval, pool), nil
+}
+
+func createVectorFunctionOperator(args []types.Operator, _ time.Time, _ time.Time, _ time.Duration, _ *pooling.LimitingPool) (types.InstantVectorOperator, error) {
+	if len(args) != 1 {
+		// Should be caught by the PromQL parser, but we check here for safety.
+		return nil, fmt.Errorf("expected exactly 1 argument for vector, got %v", len(args))
+	}
+
+	inner, ok := args[0].(types.ScalarOperator)
+	if !ok {
+		// Should be caught by the PromQL parser, but we check here for safety.
+		return nil, fmt.Errorf("expected a scalar argument for vector, got %T", args[0])
 	}
 
-	return &operators.FunctionOverRangeVector{
-		Inner: inner,
-		Pool:  pool,
-	}, nil
+	return &operators.ScalarToInstantVector{Scalar: inner}, nil
 }
 
 // These functions return an instant-vector.
 var instantVectorFunctionOperatorFactories = map[string]InstantVectorFunctionOperatorFactory{
-	"abs":             TransformationFunctionOperatorFactory("abs", functions.Abs),
-	"acos":            TransformationFunctionOperatorFactory("acos", functions.Acos),
-	"acosh":           TransformationFunctionOperatorFactory("acosh", functions.Acosh),
-	"asin":            TransformationFunctionOperatorFactory("asin", functions.Asin),
-	"asinh":           TransformationFunctionOperatorFactory("asinh", functions.Asinh),
-	"atan":            TransformationFunctionOperatorFactory("atan", functions.Atan),
-	"atanh":           TransformationFunctionOperatorFactory("atanh", functions.Atanh),
-	"ceil":            TransformationFunctionOperatorFactory("ceil", functions.Ceil),
-	"cos":             TransformationFunctionOperatorFactory("cos", functions.Cos),
-	"cosh":            TransformationFunctionOperatorFactory("cosh", functions.Cosh),
-	"deg":             TransformationFunctionOperatorFactory("deg", functions.Deg),
-	"exp":             TransformationFunctionOperatorFactory("exp", functions.Exp),
-	"floor":           TransformationFunctionOperatorFactory("floor", functions.Floor),
-	"histogram_count": TransformationFunctionOperatorFactory("histogram_count", functions.HistogramCount),
-	"histogram_sum":   TransformationFunctionOperatorFactory("histogram_sum", functions.HistogramSum),
-	"ln":              TransformationFunctionOperatorFactory("ln", functions.Ln),
-	"log10":           TransformationFunctionOperatorFactory("log10", functions.Log10),
-	"log2":            TransformationFunctionOperatorFactory("log2", functions.Log2),
-	"rad":             TransformationFunctionOperatorFactory("rad", functions.Rad),
-	"rate":            createRateFunctionOperator,
-	"sgn":             TransformationFunctionOperatorFactory("sgn", functions.Sgn),
-	"sin":             TransformationFunctionOperatorFactory("sin", functions.Sin),
-	"sinh":            TransformationFunctionOperatorFactory("sinh", functions.Sinh),
-	"sqrt":            TransformationFunctionOperatorFactory("sqrt", functions.Sqrt),
-	"tan":             TransformationFunctionOperatorFactory("tan", functions.Tan),
-	"tanh":            TransformationFunctionOperatorFactory("tanh", functions.Tanh),
+	"abs":                TransformationFunctionOperatorFactory("abs", functions.Abs),
+	"absent_over_time":   createAbsentOverTimeFunctionOperator,
+	"acos":               TransformationFunctionOperatorFactory("acos", functions.Acos),
+	"acosh":              TransformationFunctionOperatorFactory("acosh", functions.Acosh),
+	"asin":               TransformationFunctionOperatorFactory("asin", functions.Asin),
+	"asinh":              TransformationFunctionOperatorFactory("asinh", functions.Asinh),
+	"atan":               TransformationFunctionOperatorFactory("atan", functions.Atan),
+	"atanh":              TransformationFunctionOperatorFactory("atanh", functions.Atanh),
+	"avg_over_time":      FunctionOverRangeVectorOperatorFactory("avg_over_time", functions.DropSeriesName, functions.AvgOverTime),
+	"ceil":               TransformationFunctionOperatorFactory("ceil", functions.Ceil),
+	"changes":            FunctionOverRangeVectorOperatorFactory("changes", functions.DropSeriesName, functions.Changes),
+	"cos":                TransformationFunctionOperatorFactory("cos", functions.Cos),
+	"cosh":               TransformationFunctionOperatorFactory("cosh", functions.Cosh),
+	"count_over_time":    FunctionOverRangeVectorOperatorFactory("count_over_time", functions.DropSeriesName, functions.CountOverTime),
+	"deg":                TransformationFunctionOperatorFactory("deg", functions.Deg),
+	"delta":              FunctionOverRangeVectorOperatorFactory("delta", functions.DropSeriesName, functions.Delta),
+	"deriv":              FunctionOverRangeVectorOperatorFactory("deriv", functions.DropSeriesName, functions.Deriv),
+	"exp":                TransformationFunctionOperatorFactory("exp", functions.Exp),
+	"floor":              TransformationFunctionOperatorFactory("floor", functions.Floor),
+	"histogram_count":    TransformationFunctionOperatorFactory("histogram_count", functions.HistogramCount),
+	"histogram_quantile": createHistogramQuantileFunctionOperator,
+	"histogram_sum":      TransformationFunctionOperatorFactory("histogram_sum", functions.HistogramSum),
+	"idelta":             FunctionOverRangeVectorOperatorFactory("idelta", functions.DropSeriesName, functions.Idelta),
+	"increase":           FunctionOverRangeVectorOperatorFactory("increase", functions.DropSeriesName, functions.Increase),
+	"irate":              FunctionOverRangeVectorOperatorFactory("irate", functions.DropSeriesName, functions.Irate),
+	"last_over_time":     FunctionOverRangeVectorOperatorFactory("last_over_time", functions.PassthroughSeriesMetadata, functions.LastOverTime),
+	"ln":                 TransformationFunctionOperatorFactory("ln", functions.Ln),
+	"log10":              TransformationFunctionOperatorFactory("log10", functions.Log10),
+	"log2":               TransformationFunctionOperatorFactory("log2", functions.Log2),
+	"mad_over_time":      FunctionOverRangeVectorOperatorFactory("mad_over_time", functions.DropSeriesName, functions.MadOverTime),
+	"max_over_time":      FunctionOverRangeVectorOperatorFactory("max_over_time", functions.DropSeriesName, functions.MaxOverTime),
+	"min_over_time":      FunctionOverRangeVectorOperatorFactory("min_over_time", functions.DropSeriesName, functions.MinOverTime),
+	"predict_linear":     FunctionOverRangeVectorWithScalarOperatorFactory("predict_linear", 1, functions.DropSeriesName, functions.PredictLinear),
+	"present_over_time":  FunctionOverRangeVectorOperatorFactory("present_over_time", functions.DropSeriesName, functions.PresentOverTime),
+	"quantile_over_time": FunctionOverRangeVectorWithScalarOperatorFactory("quantile_over_time", 0, functions.DropSeriesName, functions.QuantileOverTime),
+	"rad":                TransformationFunctionOperatorFactory("rad", functions.Rad),
+	"rate":               FunctionOverRangeVectorOperatorFactory("rate", functions.DropSeriesName, functions.Rate),
+	"resets":             FunctionOverRangeVectorOperatorFactory("resets", functions.DropSeriesName, functions.Resets),
+	"sgn":                TransformationFunctionOperatorFactory("sgn", functions.Sgn),
+	"sin":                TransformationFunctionOperatorFactory("sin", functions.Sin),
+	"sinh":               TransformationFunctionOperatorFactory("sinh", functions.Sinh),
+	"sqrt":               TransformationFunctionOperatorFactory("sqrt", functions.Sqrt),
+	"stddev_over_time":   FunctionOverRangeVectorOperatorFactory("stddev_over_time", functions.DropSeriesName, functions.StddevOverTime),
+	"stdvar_over_time":   FunctionOverRangeVectorOperatorFactory("stdvar_over_time", functions.DropSeriesName, functions.StdvarOverTime),
+	"sum_over_time":      FunctionOverRangeVectorOperatorFactory("sum_over_time", functions.DropSeriesName, functions.SumOverTime),
+	"tan":                TransformationFunctionOperatorFactory("tan", functions.Tan),
+	"tanh":               TransformationFunctionOperatorFactory("tanh", functions.Tanh),
+	"vector":             createVectorFunctionOperator,
 }
 
 func RegisterInstantVectorFunctionOperatorFactory(functionName string, factory InstantVectorFunctionOperatorFactory) error {
@@ -125,3 +259,35 @@ func RegisterInstantVectorFunctionOperatorFactory(functionName string, factory I
 	instantVectorFunctionOperatorFactories[functionName] = factory
 	return nil
 }
+
+type ScalarFunctionOperatorFactory func(args []types.Operator, start time.Time, end time.Time, interval time.Duration, pool *pooling.LimitingPool) (types.ScalarOperator, error)
+
+func createScalarFunctionOperator(args []types.Operator, start time.Time, end time.Time, interval time.Duration, pool *pooling.LimitingPool) (types.ScalarOperator, error) {
+	if len(args) != 1 {
+		// Should be caught by the PromQL parser, but we check here for safety.
+		return nil, fmt.Errorf("expected exactly 1 argument for scalar, got %v", len(args))
+	}
+
+	inner, ok := args[0].(types.InstantVectorOperator)
+	if !ok {
+		// Should be caught by the PromQL parser, but we check here for safety.
+		return nil, fmt.Errorf("expected an instant vector argument for scalar, got %T", args[0])
+	}
+
+	return operators.NewInstantVectorToScalar(inner, start, end, interval, pool), nil
+}
+
+func createTimeFunctionOperator(args []types.Operator, start time.Time, end time.Time, interval time.Duration, pool *pooling.LimitingPool) (types.ScalarOperator, error) {
+	if len(args) != 0 {
+		// Should be caught by the PromQL parser, but we check here for safety.
+		return nil, fmt.Errorf("expected no arguments for time, got %v", len(args))
+	}
+
+	return operators.NewTimeFunction(start, end, interval, pool), nil
+}
+
+// These functions return a scalar.
+var scalarFunctionOperatorFactories = map[string]ScalarFunctionOperatorFactory{
+	"scalar": createScalarFunctionOperator,
+	"time":   createTimeFunctionOperator,
+}
@@ -0,0 +1,313 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/functions.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package functions
+
+import (
+	"math"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/aggregations"
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+func AvgOverTime(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, histograms *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	fHead, fTail := floats.UnsafePoints(step.RangeEnd)
+	hHead, hTail := histograms.UnsafePoints(step.RangeEnd)
+
+	haveFloats := len(fHead) > 0 || len(fTail) > 0
+	haveHistograms := len(hHead) > 0 || len(hTail) > 0
+
+	if haveFloats && haveHistograms {
+		// Mixed float and histogram samples in the range: Prometheus returns nothing for this step.
+		return 0, false, nil, nil
+	}
+
+	if haveFloats {
+		return avgFloats(fHead, fTail), true, nil, nil
+	}
+
+	return 0, false, avgHistograms(hHead, hTail), nil
+}
+
+func avgFloats(head, tail []promql.FPoint) float64 {
+	var mean, count, c float64
+
+	accumulate := func(points []promql.FPoint) {
+		for _, p := range points {
+			count++
+
+			if math.IsInf(mean, 0) {
+				if math.IsInf(p.F, 0) && (mean > 0) == (p.F > 0) {
+					// The mean and p.F are both Inf of the same sign. They can't be subtracted,
+					// but the value of mean is already correct.
+					continue
+				}
+
+				if !math.IsInf(p.F, 0) && !math.IsNaN(p.F) {
+					// The mean is infinite, and p.F is neither Inf nor NaN, so the mean remains infinite.
+					// This is required because the calculation below would otherwise produce NaN (Inf += x - Inf).
+					continue
+				}
+			}
+
+			mean, c = kahanSumInc(p.F/count-mean/count, mean, c)
+		}
+	}
+
+	accumulate(head)
+	accumulate(tail)
+
+	if math.IsInf(mean, 0) {
+		return mean
+	}
+
+	return mean + c
+}
+
+func avgHistograms(head, tail []promql.HPoint) *histogram.FloatHistogram {
+	mean := head[0].H.Copy() // We must make a copy of the histogram, as the ring buffer may reuse the FloatHistogram instance on subsequent steps.
+	count := 1.0
+
+	accumulate := func(points []promql.HPoint) {
+		for _, p := range points {
+			count++
+			left := p.H.Copy().Div(count)
+			right := mean.Copy().Div(count)
+			mean.Add(left.Sub(right))
+		}
+	}
+
+	accumulate(head[1:])
+	accumulate(tail)
+
+	return mean
+}
+
+func CountOverTime(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, histograms *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	fHead, fTail := floats.UnsafePoints(step.RangeEnd)
+	hHead, hTail := histograms.UnsafePoints(step.RangeEnd)
+
+	return float64(len(fHead) + len(fTail) + len(hHead) + len(hTail)), true, nil, nil
+}
+
+func LastOverTime(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, histograms *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	lastFloat, floatAvailable := floats.LastAtOrBefore(step.RangeEnd)
+	lastHistogram, histogramAvailable := histograms.LastAtOrBefore(step.RangeEnd)
+
+	if !histogramAvailable || (floatAvailable && lastHistogram.T < lastFloat.T) {
+		return lastFloat.F, true, nil, nil
+	}
+
+	// We must make a copy of the histogram, as the ring buffer may reuse the FloatHistogram instance on subsequent steps.
+	return 0, false, lastHistogram.H.Copy(), nil
+}
+
+func MaxOverTime(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, _ *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	head, tail := floats.UnsafePoints(step.RangeEnd)
+
+	if len(head) == 0 {
+		// Only histograms, which are ignored by max_over_time.
+		return 0, false, nil, nil
+	}
+
+	maxSoFar := head[0].F
+
+	accumulate := func(points []promql.FPoint) {
+		for _, p := range points {
+			if p.F > maxSoFar || math.IsNaN(maxSoFar) {
+				maxSoFar = p.F
+			}
+		}
+	}
+
+	accumulate(head)
+	accumulate(tail)
+
+	return maxSoFar, true, nil, nil
+}
+
+func MinOverTime(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, _ *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	head, tail := floats.UnsafePoints(step.RangeEnd)
+
+	if len(head) == 0 {
+		// Only histograms, which are ignored by min_over_time.
+		return 0, false, nil, nil
+	}
+
+	minSoFar := head[0].F
+
+	accumulate := func(points []promql.FPoint) {
+		for _, p := range points {
+			if p.F < minSoFar || math.IsNaN(minSoFar) {
+				minSoFar = p.F
+			}
+		}
+	}
+
+	accumulate(head)
+	accumulate(tail)
+
+	return minSoFar, true, nil, nil
+}
+
+func SumOverTime(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, histograms *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	fHead, fTail := floats.UnsafePoints(step.RangeEnd)
+	hHead, hTail := histograms.UnsafePoints(step.RangeEnd)
+
+	haveFloats := len(fHead) > 0 || len(fTail) > 0
+	haveHistograms := len(hHead) > 0 || len(hTail) > 0
+
+	if haveFloats && haveHistograms {
+		// Mixed float and histogram samples in the range: Prometheus returns nothing for this step.
+		return 0, false, nil, nil
+	}
+
+	if haveFloats {
+		return sumFloats(fHead, fTail), true, nil, nil
+	}
+
+	return 0, false, sumHistograms(hHead, hTail), nil
+}
+
+func sumFloats(head, tail []promql.FPoint) float64 {
+	sum, c := 0.0, 0.0
+
+	for _, p := range head {
+		sum, c = kahanSumInc(p.F, sum, c)
+	}
+
+	for _, p := range tail {
+		sum, c = kahanSumInc(p.F, sum, c)
+	}
+
+	if math.IsInf(sum, 0) {
+		return sum
+	}
+
+	return sum + c
+}
+
+func sumHistograms(head, tail []promql.HPoint) *histogram.FloatHistogram {
+	sum := head[0].H.Copy() // We must make a copy of the histogram, as the ring buffer may reuse the FloatHistogram instance on subsequent steps.
+
+	for _, p := range head[1:] {
+		sum.Add(p.H)
+	}
+
+	for _, p := range tail {
+		sum.Add(p.H)
+	}
+
+	return sum
+}
+
+func QuantileOverTime(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, _ *types.HPointRingBuffer, q float64, pool *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	head, tail := floats.UnsafePoints(step.RangeEnd)
+
+	if len(head) == 0 {
+		// Only histograms, which are ignored by quantile_over_time.
+		return 0, false, nil, nil
+	}
+
+	values, err := pool.GetFloatSlice(len(head) + len(tail))
+	if err != nil {
+		return 0, false, nil, err
+	}
+
+	defer pool.PutFloatSlice(values)
+
+	for _, p := range head {
+		values = append(values, p.F)
+	}
+
+	for _, p := range tail {
+		values = append(values, p.F)
+	}
+
+	return aggregations.Quantile(q, values), true, nil, nil
+}
+
+func MadOverTime(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, _ *types.HPointRingBuffer, _ float64, pool *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	head, tail := floats.UnsafePoints(step.RangeEnd)
+
+	if len(head) == 0 {
+		// Only histograms, which are ignored by mad_over_time.
+		return 0, false, nil, nil
+	}
+
+	values, err := pool.GetFloatSlice(len(head) + len(tail))
+	if err != nil {
+		return 0, false, nil, err
+	}
+
+	defer pool.PutFloatSlice(values)
+
+	for _, p := range head {
+		values = append(values, p.F)
+	}
+
+	for _, p := range tail {
+		values = append(values, p.F)
+	}
+
+	median := aggregations.Quantile(0.5, values)
+
+	for i, f := range values {
+		values[i] = math.Abs(f - median)
+	}
+
+	return aggregations.Quantile(0.5, values), true, nil, nil
+}
+
+func StddevOverTime(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, _ *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	head, tail := floats.UnsafePoints(step.RangeEnd)
+
+	if len(head) == 0 {
+		// Only histograms, which are ignored by stddev_over_time.
+		return 0, false, nil, nil
+	}
+
+	return math.Sqrt(variance(head, tail)), true, nil, nil
+}
+
+func StdvarOverTime(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, _ *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	head, tail := floats.UnsafePoints(step.RangeEnd)
+
+	if len(head) == 0 {
+		// Only histograms, which are ignored by stdvar_over_time.
+		return 0, false, nil, nil
+	}
+
+	return variance(head, tail), true, nil, nil
+}
+
+// variance computes the population variance of the points in head and tail using Welford's online algorithm.
+func variance(head, tail []promql.FPoint) float64 {
+	var count float64
+	var mean, cMean float64
+	var aux, cAux float64
+
+	accumulate := func(points []promql.FPoint) {
+		for _, p := range points {
+			count++
+			delta := p.F - (mean + cMean)
+			mean, cMean = kahanSumInc(delta/count, mean, cMean)
+			aux, cAux = kahanSumInc(delta*(p.F-(mean+cMean)), aux, cAux)
+		}
+	}
+
+	accumulate(head)
+	accumulate(tail)
+
+	return (aux + cAux) / count
+}
+
+func PresentOverTime(_ types.RangeVectorStepData, _ float64, _ *types.FPointRingBuffer, _ *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	return 1, true, nil, nil
+}
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/quantile.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package functions
+
+import (
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/util/almost"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// smallDeltaTolerance is the threshold for relative deltas between classic
+// histogram buckets that will be ignored by histogram_quantile because they
+// are most likely artifacts of floating point precision issues.
+// See the corresponding constant in Prometheus' promql/quantile.go for a
+// detailed explanation of how this value was chosen.
+const smallDeltaTolerance = 1e-12
+
+// BucketQuantile calculates the quantile q based on the given classic histogram buckets.
+// The buckets will be sorted by upper bound by this function (ie. no sorting is needed before calling this function).
+//
+// The quantile value is interpolated assuming a linear distribution within a bucket. However, if the quantile
+// falls into the highest bucket, the upper bound of the second-highest bucket is returned. A natural lower bound
+// of 0 is assumed if the upper bound of the lowest bucket is greater than 0. In that case, interpolation in the lowest bucket
+// happens linearly between 0 and the upper bound of the lowest bucket. However, if the lowest bucket has an upper bound
+// less than or equal to 0, this upper bound is returned if the quantile falls into the lowest bucket.
+//
+// Special cases:
+//   - If buckets has 0 observations, NaN is returned.
+//   - If buckets has fewer than 2 elements, NaN is returned.
+//   - If the highest bucket is not +Inf, NaN is returned.
+//   - If q is NaN, NaN is returned.
+//   - If q<0, -Inf is returned.
+//   - If q>1, +Inf is returned.
+func BucketQuantile(q float64, buckets []types.HistogramBucket) float64 {
+	if math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+	slices.SortFunc(buckets, func(a, b types.HistogramBucket) int {
+		// We don't expect the bucket boundary to be a NaN.
+		if a.UpperBound < b.UpperBound {
+			return -1
+		}
+		if a.UpperBound > b.UpperBound {
+			return +1
+		}
+		return 0
+	})
+	if !math.IsInf(buckets[len(buckets)-1].UpperBound, +1) {
+		return math.NaN()
+	}
+
+	buckets = coalesceBuckets(buckets)
+	ensureMonotonicAndIgnoreSmallDeltas(buckets, smallDeltaTolerance)
+
+	if len(buckets) < 2 {
+		return math.NaN()
+	}
+	observations := buckets[len(buckets)-1].Count
+	if observations == 0 {
+		return math.NaN()
+	}
+	rank := q * observations
+	b := sort.Search(len(buckets)-1, func(i int) bool { return buckets[i].Count >= rank })
+
+	if b == len(buckets)-1 {
+		return buckets[len(buckets)-2].UpperBound
+	}
+	if b == 0 && buckets[0].UpperBound <= 0 {
+		return buckets[0].UpperBound
+	}
+	var (
+		bucketStart float64
+		bucketEnd   = buckets[b].UpperBound
+		count       = buckets[b].Count
+	)
+	if b > 0 {
+		bucketStart = buckets[b-1].UpperBound
+		count -= buckets[b-1].Count
+		rank -= buckets[b-1].Count
+	}
+	return bucketStart + (bucketEnd-bucketStart)*(rank/count)
+}
+
+// HistogramQuantile calculates the quantile q based on the given native histogram.
+//
+// The quantile value is interpolated assuming a linear distribution within a bucket.
+//
+// A natural lower bound of 0 is assumed if the histogram has only positive buckets.
+// Likewise, a natural upper bound of 0 is assumed if the histogram has only negative buckets.
+//
+// Special cases:
+//   - If the histogram has 0 observations, NaN is returned.
+//   - If q<0, -Inf is returned.
+//   - If q>1, +Inf is returned.
+//   - If q is NaN, NaN is returned.
+func HistogramQuantile(q float64, h *histogram.FloatHistogram) float64 {
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+
+	if h.Count == 0 || math.IsNaN(q) {
+		return math.NaN()
+	}
+
+	var (
+		bucket histogram.Bucket[float64]
+		count  float64
+		it     histogram.BucketIterator[float64]
+		rank   float64
+	)
+
+	// If there are NaN observations in the histogram (h.Sum is NaN), use the forward iterator.
+	// If q < 0.5, use the forward iterator.
+	// If q >= 0.5, use the reverse iterator.
+	if math.IsNaN(h.Sum) || q < 0.5 {
+		it = h.AllBucketIterator()
+		rank = q * h.Count
+	} else {
+		it = h.AllReverseBucketIterator()
+		rank = (1 - q) * h.Count
+	}
+
+	for it.Next() {
+		bucket = it.At()
+		count += bucket.Count
+		if count >= rank {
+			break
+		}
+	}
+	if bucket.Lower < 0 && bucket.Upper > 0 {
+		switch {
+		case len(h.NegativeBuckets) == 0 && len(h.PositiveBuckets) > 0:
+			// The result is in the zero bucket and the histogram has only
+			// positive buckets. So we consider 0 to be the lower bound.
+			bucket.Lower = 0
+		case len(h.PositiveBuckets) == 0 && len(h.NegativeBuckets) > 0:
+			// The result is in the zero bucket and the histogram has only
+			// negative buckets. So we consider 0 to be the upper bound.
+			bucket.Upper = 0
+		}
+	}
+	// Due to numerical inaccuracies, we could end up with a higher count
+	// than h.Count. Thus, make sure count is never higher than h.Count.
+	if count > h.Count {
+		count = h.Count
+	}
+	// We could have hit the highest bucket without even reaching the rank
+	// (this should only happen if the histogram contains observations of
+	// the value NaN), in which case we simply return the upper limit of the
+	// highest explicit bucket.
+	if count < rank {
+		return bucket.Upper
+	}
+
+	// NaN observations increase h.Count but not the total number of
+	// observations in the buckets. Therefore, we have to use the forward
+	// iterator to find percentiles. We recognize histograms containing NaN
+	// observations by checking if their h.Sum is NaN.
+	if math.IsNaN(h.Sum) || q < 0.5 {
+		rank -= count - bucket.Count
+	} else {
+		rank = count - rank
+	}
+
+	return bucket.Lower + (bucket.Upper-bucket.Lower)*(rank/bucket.Count)
+}
+
+// coalesceBuckets merges buckets with the same upper bound.
+//
+// The input buckets must be sorted.
+func coalesceBuckets(buckets []types.HistogramBucket) []types.HistogramBucket {
+	last := buckets[0]
+	i := 0
+	for _, b := range buckets[1:] {
+		if b.UpperBound == last.UpperBound {
+			last.Count += b.Count
+		} else {
+			buckets[i] = last
+			last = b
+			i++
+		}
+	}
+	buckets[i] = last
+	return buckets[:i+1]
+}
+
+// ensureMonotonicAndIgnoreSmallDeltas ensures bucket counts increase monotonically with increasing upper bound,
+// as BucketQuantile depends on this to perform a binary search for the bucket containing the quantile.
+//
+// Numerically insignificant differences between successive buckets (relative delta below tolerance, likely
+// caused by floating point precision errors) are ignored regardless of their direction, and then any
+// remaining decreases in count between successive buckets are removed.
+func ensureMonotonicAndIgnoreSmallDeltas(buckets []types.HistogramBucket, tolerance float64) {
+	prev := buckets[0].Count
+	for i := 1; i < len(buckets); i++ {
+		curr := buckets[i].Count // Assumed always positive.
+		if curr == prev {
+			// No correction needed if the counts are identical between buckets.
+			continue
+		}
+		if almost.Equal(prev, curr, tolerance) {
+			// Silently correct numerically insignificant differences from floating
+			// point precision errors, regardless of direction.
+			// Do not update the 'prev' value as we are ignoring the difference.
+			buckets[i].Count = prev
+			continue
+		}
+		if curr < prev {
+			// Force monotonicity by removing any decreases regardless of magnitude.
+			// Do not update the 'prev' value as we are ignoring the decrease.
+			buckets[i].Count = prev
+			continue
+		}
+		prev = curr
+	}
+}
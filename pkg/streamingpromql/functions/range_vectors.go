@@ -0,0 +1,413 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/functions.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package functions
+
+import (
+	"math"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// RangeVectorStepFunction computes the output of a range vector function for a single time step.
+//
+// floats and histograms contain the points selected for this time step, and may also contain points after
+// step.RangeEnd, which must be ignored.
+// scalarArg is the value of the function's scalar argument at this time step (eg. the quantile for
+// quantile_over_time), or 0 if the function has no scalar argument.
+//
+// It returns hasFloat=true if the output for this step is a float, or a non-nil histogram if the output is a histogram.
+// If neither is returned, no point is produced for this step.
+type RangeVectorStepFunction func(
+	step types.RangeVectorStepData,
+	rangeSeconds float64,
+	floats *types.FPointRingBuffer,
+	histograms *types.HPointRingBuffer,
+	scalarArg float64,
+	pool *pooling.LimitingPool,
+) (f float64, hasFloat bool, h *histogram.FloatHistogram, err error)
+
+var Rate = extrapolatedRate(true, true)
+var Increase = extrapolatedRate(true, false)
+var Delta = extrapolatedRate(false, false)
+
+// extrapolatedRate returns a RangeVectorStepFunction for rate, increase and delta.
+// It calculates the rate (allowing for counter resets if isCounter is true), extrapolates if the first or last point
+// is close to the boundary of the range, and returns the result as either per-second (if isRate is true) or overall.
+//
+// https://github.com/prometheus/prometheus/pull/13725 has a good explanation of the intended behaviour here.
+func extrapolatedRate(isCounter, isRate bool) RangeVectorStepFunction {
+	return func(step types.RangeVectorStepData, rangeSeconds float64, floats *types.FPointRingBuffer, histograms *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+		fHead, fTail := floats.UnsafePoints(step.RangeEnd)
+		fCount := len(fHead) + len(fTail)
+
+		hHead, hTail := histograms.UnsafePoints(step.RangeEnd)
+		hCount := len(hHead) + len(hTail)
+
+		if fCount > 0 && hCount > 0 {
+			// We need either at least two histograms and no floats, or at least two floats and no histograms to calculate a rate.
+			return 0, false, nil, nil
+		}
+
+		if fCount >= 2 {
+			return floatRate(isCounter, isRate, step, rangeSeconds, fHead, fTail), true, nil, nil
+		}
+
+		if hCount >= 2 {
+			return 0, false, histogramRate(isCounter, isRate, step, rangeSeconds, hHead, hTail), nil
+		}
+
+		return 0, false, nil, nil
+	}
+}
+
+func floatRate(isCounter, isRate bool, step types.RangeVectorStepData, rangeSeconds float64, head, tail []promql.FPoint) float64 {
+	firstPoint := head[0]
+	lastPoint := lastPoint(head, tail)
+	delta := lastPoint.F - firstPoint.F
+
+	if isCounter {
+		previousValue := firstPoint.F
+
+		accumulate := func(points []promql.FPoint) {
+			for _, p := range points {
+				if p.F < previousValue {
+					// Counter reset.
+					delta += previousValue
+				}
+
+				previousValue = p.F
+			}
+		}
+
+		accumulate(head[1:])
+		accumulate(tail)
+	}
+
+	durationToZero := math.Inf(+1)
+
+	if isCounter && delta > 0 && firstPoint.F >= 0 {
+		// Counters cannot be negative. If we have any slope at all (ie. delta went up), we can extrapolate the zero
+		// point of the counter. If the duration to the zero point is shorter than the duration to the start of the
+		// range, we take the zero point as the start of the series, thereby avoiding extrapolation to negative counter values.
+		sampledInterval := float64(lastPoint.T-firstPoint.T) / 1000
+		durationToZero = sampledInterval * (firstPoint.F / delta)
+	}
+
+	return delta * extrapolationFactor(isRate, step, rangeSeconds, firstPoint.T, lastPoint.T, len(head)+len(tail), durationToZero)
+}
+
+func histogramRate(isCounter, isRate bool, step types.RangeVectorStepData, rangeSeconds float64, head, tail []promql.HPoint) *histogram.FloatHistogram {
+	firstPoint := head[0]
+	lastPoint := lastPoint(head, tail)
+	first := firstPoint.H
+	last := lastPoint.H
+	minSchema := min(first.Schema, last.Schema)
+
+	if isCounter {
+		findMinSchema := func(points []promql.HPoint) {
+			for _, p := range points {
+				minSchema = min(minSchema, p.H.Schema)
+			}
+		}
+
+		findMinSchema(head)
+		findMinSchema(tail)
+	}
+
+	delta := last.CopyToSchema(minSchema)
+	delta.Sub(first)
+
+	if isCounter {
+		previous := first
+
+		accumulate := func(points []promql.HPoint) {
+			for _, p := range points {
+				if p.H.DetectReset(previous) {
+					// Counter reset.
+					delta.Add(previous)
+				}
+
+				previous = p.H
+			}
+		}
+
+		accumulate(head[1:])
+		accumulate(tail)
+	}
+
+	delta.CounterResetHint = histogram.GaugeType
+	delta = delta.Compact(0)
+
+	return delta.Mul(extrapolationFactor(isRate, step, rangeSeconds, firstPoint.T, lastPoint.T, len(head)+len(tail), math.Inf(+1)))
+}
+
+// extrapolationFactor returns the factor to multiply the difference between the first and last points by to extrapolate
+// it to the whole range, and convert it to a per-second rate if isRate is true.
+func extrapolationFactor(isRate bool, step types.RangeVectorStepData, rangeSeconds float64, firstT, lastT int64, count int, durationToZero float64) float64 {
+	durationToStart := float64(firstT-step.RangeStart) / 1000
+	durationToEnd := float64(step.RangeEnd-lastT) / 1000
+
+	sampledInterval := float64(lastT-firstT) / 1000
+	averageDurationBetweenSamples := sampledInterval / float64(count-1)
+
+	// If the first or last samples are close to the boundaries of the range, extrapolate the result.
+	// This is as we expect that another sample will exist given the spacing between samples we've seen thus far,
+	// with an allowance for noise.
+	extrapolationThreshold := averageDurationBetweenSamples * 1.1
+	extrapolateToInterval := sampledInterval
+
+	if durationToStart >= extrapolationThreshold {
+		durationToStart = averageDurationBetweenSamples / 2
+	}
+
+	if durationToZero < durationToStart {
+		durationToStart = durationToZero
+	}
+
+	extrapolateToInterval += durationToStart
+
+	if durationToEnd >= extrapolationThreshold {
+		durationToEnd = averageDurationBetweenSamples / 2
+	}
+
+	extrapolateToInterval += durationToEnd
+
+	factor := extrapolateToInterval / sampledInterval
+
+	if isRate {
+		factor /= rangeSeconds
+	}
+
+	return factor
+}
+
+var Irate = instantValue(true)
+var Idelta = instantValue(false)
+
+// instantValue returns a RangeVectorStepFunction for irate and idelta, which use only the last two float points in the range.
+func instantValue(isRate bool) RangeVectorStepFunction {
+	return func(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, _ *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+		head, tail := floats.UnsafePoints(step.RangeEnd)
+
+		if len(head)+len(tail) < 2 {
+			// No sense in trying to compute a rate without at least two points.
+			return 0, false, nil, nil
+		}
+
+		lastSample := lastPoint(head, tail)
+		previousSample := secondLastPoint(head, tail)
+
+		var result float64
+		if isRate && lastSample.F < previousSample.F {
+			// Counter reset.
+			result = lastSample.F
+		} else {
+			result = lastSample.F - previousSample.F
+		}
+
+		sampledInterval := lastSample.T - previousSample.T
+		if sampledInterval == 0 {
+			// Avoid dividing by 0.
+			return 0, false, nil, nil
+		}
+
+		if isRate {
+			// Convert to per-second.
+			result /= float64(sampledInterval) / 1000
+		}
+
+		return result, true, nil, nil
+	}
+}
+
+func Deriv(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, _ *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	head, tail := floats.UnsafePoints(step.RangeEnd)
+
+	if len(head)+len(tail) < 2 {
+		// No sense in trying to compute a derivative without at least two points.
+		return 0, false, nil, nil
+	}
+
+	// We pass in an arbitrary timestamp that is near the values in use to avoid floating point accuracy issues,
+	// see https://github.com/prometheus/prometheus/issues/2674
+	slope, _ := linearRegression(head, tail, head[0].T)
+	return slope, true, nil, nil
+}
+
+func PredictLinear(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, _ *types.HPointRingBuffer, duration float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	head, tail := floats.UnsafePoints(step.RangeEnd)
+
+	if len(head)+len(tail) < 2 {
+		// No sense in trying to predict anything without at least two points.
+		return 0, false, nil, nil
+	}
+
+	slope, intercept := linearRegression(head, tail, step.StepT)
+	return slope*duration + intercept, true, nil, nil
+}
+
+// linearRegression performs a least-square linear regression analysis on the points in head and tail.
+// It returns the slope, and the intercept value at the provided time.
+func linearRegression(head, tail []promql.FPoint, interceptTime int64) (slope, intercept float64) {
+	var (
+		n          float64
+		sumX, cX   float64
+		sumY, cY   float64
+		sumXY, cXY float64
+		sumX2, cX2 float64
+		initY      float64
+		constY     bool
+	)
+
+	initY = head[0].F
+	constY = true
+
+	accumulate := func(points []promql.FPoint) {
+		for _, p := range points {
+			// Set constY to false if any new y values are encountered.
+			if constY && p.F != initY {
+				constY = false
+			}
+
+			n += 1.0
+			x := float64(p.T-interceptTime) / 1e3
+			sumX, cX = kahanSumInc(x, sumX, cX)
+			sumY, cY = kahanSumInc(p.F, sumY, cY)
+			sumXY, cXY = kahanSumInc(x*p.F, sumXY, cXY)
+			sumX2, cX2 = kahanSumInc(x*x, sumX2, cX2)
+		}
+	}
+
+	accumulate(head)
+	accumulate(tail)
+
+	if constY {
+		if math.IsInf(initY, 0) {
+			return math.NaN(), math.NaN()
+		}
+
+		return 0, initY
+	}
+
+	sumX += cX
+	sumY += cY
+	sumXY += cXY
+	sumX2 += cX2
+
+	covXY := sumXY - sumX*sumY/n
+	varX := sumX2 - sumX*sumX/n
+
+	slope = covXY / varX
+	intercept = sumY/n - slope*sumX/n
+	return slope, intercept
+}
+
+func Resets(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, histograms *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	fHead, fTail := floats.UnsafePoints(step.RangeEnd)
+	hHead, hTail := histograms.UnsafePoints(step.RangeEnd)
+	resets := 0
+
+	if len(fHead) > 0 {
+		previous := fHead[0].F
+
+		accumulate := func(points []promql.FPoint) {
+			for _, p := range points {
+				if p.F < previous {
+					resets++
+				}
+
+				previous = p.F
+			}
+		}
+
+		accumulate(fHead[1:])
+		accumulate(fTail)
+	}
+
+	if len(hHead) > 0 {
+		previous := hHead[0].H
+
+		accumulate := func(points []promql.HPoint) {
+			for _, p := range points {
+				if p.H.DetectReset(previous) {
+					resets++
+				}
+
+				previous = p.H
+			}
+		}
+
+		accumulate(hHead[1:])
+		accumulate(hTail)
+	}
+
+	return float64(resets), true, nil, nil
+}
+
+func Changes(step types.RangeVectorStepData, _ float64, floats *types.FPointRingBuffer, _ *types.HPointRingBuffer, _ float64, _ *pooling.LimitingPool) (float64, bool, *histogram.FloatHistogram, error) {
+	head, tail := floats.UnsafePoints(step.RangeEnd)
+
+	if len(head) == 0 {
+		// Only histograms, which are ignored by changes.
+		return 0, false, nil, nil
+	}
+
+	changes := 0
+	previous := head[0].F
+
+	accumulate := func(points []promql.FPoint) {
+		for _, p := range points {
+			if p.F != previous && !(math.IsNaN(p.F) && math.IsNaN(previous)) {
+				changes++
+			}
+
+			previous = p.F
+		}
+	}
+
+	accumulate(head[1:])
+	accumulate(tail)
+
+	return float64(changes), true, nil, nil
+}
+
+// lastPoint returns the last point in head and tail, which must not both be empty.
+func lastPoint[P any](head, tail []P) P {
+	if len(tail) > 0 {
+		return tail[len(tail)-1]
+	}
+
+	return head[len(head)-1]
+}
+
+// secondLastPoint returns the second-last point in head and tail, which must contain at least two points between them.
+func secondLastPoint[P any](head, tail []P) P {
+	switch len(tail) {
+	case 0:
+		return head[len(head)-2]
+	case 1:
+		return head[len(head)-1]
+	default:
+		return tail[len(tail)-2]
+	}
+}
+
+func kahanSumInc(inc, sum, c float64) (newSum, newC float64) {
+	t := sum + inc
+
+	// Using Neumaier improvement, swap if next term larger than sum.
+	if math.Abs(sum) >= math.Abs(inc) {
+		c += (sum - t) + inc
+	} else {
+		c += (inc - t) + sum
+	}
+
+	return t, c
+}
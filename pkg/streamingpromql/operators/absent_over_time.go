@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/functions.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// AbsentOverTime performs the absent_over_time function.
+//
+// It produces a single series with the value 1 at each step where none of the series selected by Inner have any points in range,
+// or no series at all if every step has at least one point.
+type AbsentOverTime struct {
+	Inner    types.RangeVectorOperator
+	Start    int64 // Milliseconds since Unix epoch
+	End      int64 // Milliseconds since Unix epoch
+	Interval int64 // In milliseconds
+	Steps    int
+	Pool     *pooling.LimitingPool
+
+	// The labels of the output series, derived from the selector's matchers.
+	Labels labels.Labels
+
+	exhausted bool
+}
+
+var _ types.InstantVectorOperator = &AbsentOverTime{}
+
+func NewAbsentOverTime(inner types.RangeVectorOperator, start time.Time, end time.Time, interval time.Duration, pool *pooling.LimitingPool) *AbsentOverTime {
+	s, e, i := timestamp.FromTime(start), timestamp.FromTime(end), interval.Milliseconds()
+
+	return &AbsentOverTime{
+		Inner:    inner,
+		Start:    s,
+		End:      e,
+		Interval: i,
+		Steps:    stepCount(s, e, i),
+		Pool:     pool,
+		Labels:   labelsForAbsentFunction(inner),
+	}
+}
+
+// labelsForAbsentFunction returns the labels of the series produced by absent_over_time for the given inner operator.
+//
+// Labels are only derived from the matchers of range vector selectors: if a label appears in multiple matchers, or is matched
+// with anything other than an equality matcher, it is not included.
+func labelsForAbsentFunction(inner types.RangeVectorOperator) labels.Labels {
+	selector, ok := inner.(*RangeVectorSelector)
+	if !ok {
+		return labels.EmptyLabels()
+	}
+
+	b := labels.NewBuilder(labels.EmptyLabels())
+
+	// The 'has' map implements backwards-compatibility for historic behaviour:
+	// e.g. in `absent(x{job="a",job="b",foo="bar"})` then `job` is removed from the output.
+	// Note this gives arguably wrong behaviour for `absent(x{job="a",job="a",foo="bar"})`.
+	has := make(map[string]bool, len(selector.Selector.Matchers))
+	for _, m := range selector.Selector.Matchers {
+		if m.Name == labels.MetricName {
+			continue
+		}
+
+		if m.Type == labels.MatchEqual && !has[m.Name] {
+			b.Set(m.Name, m.Value)
+			has[m.Name] = true
+		} else {
+			b.Del(m.Name)
+		}
+	}
+
+	return b.Labels()
+}
+
+func (a *AbsentOverTime) SeriesMetadata(_ context.Context) ([]types.SeriesMetadata, error) {
+	metadata := pooling.GetSeriesMetadataSlice(1)
+	metadata = append(metadata, types.SeriesMetadata{Labels: a.Labels})
+
+	return metadata, nil
+}
+
+func (a *AbsentOverTime) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	if a.exhausted {
+		return types.InstantVectorSeriesData{}, types.EOS
+	}
+
+	a.exhausted = true
+
+	innerMetadata, err := a.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	defer pooling.PutSeriesMetadataSlice(innerMetadata)
+
+	present, err := a.Pool.GetBoolSlice(a.Steps)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	defer a.Pool.PutBoolSlice(present)
+	present = present[:a.Steps]
+
+	floatBuffer := types.NewFPointRingBuffer(a.Pool)
+	defer floatBuffer.Close()
+	histogramBuffer := types.NewHPointRingBuffer(a.Pool)
+	defer histogramBuffer.Close()
+
+	for range innerMetadata {
+		if err := a.Inner.NextSeries(ctx); err != nil {
+			return types.InstantVectorSeriesData{}, err
+		}
+
+		floatBuffer.Reset()
+		histogramBuffer.Reset()
+
+		for stepIdx := 0; ; stepIdx++ {
+			step, err := a.Inner.NextStepSamples(floatBuffer, histogramBuffer)
+
+			// nolint:errorlint // errors.Is introduces a performance overhead, and NextStepSamples is guaranteed to return exactly EOS, never a wrapped error.
+			if err == types.EOS {
+				break
+			} else if err != nil {
+				return types.InstantVectorSeriesData{}, err
+			}
+
+			if floatBuffer.AnyAtOrBefore(step.RangeEnd) || histogramBuffer.AnyAtOrBefore(step.RangeEnd) {
+				present[stepIdx] = true
+			}
+		}
+	}
+
+	var floats []promql.FPoint
+
+	for stepIdx, isPresent := range present {
+		if isPresent {
+			continue
+		}
+
+		if floats == nil {
+			floats, err = a.Pool.GetFPointSlice(a.Steps)
+			if err != nil {
+				return types.InstantVectorSeriesData{}, err
+			}
+		}
+
+		floats = append(floats, promql.FPoint{T: a.Start + int64(stepIdx)*a.Interval, F: 1})
+	}
+
+	return types.InstantVectorSeriesData{Floats: floats}, nil
+}
+
+func (a *AbsentOverTime) Close() {
+	a.Inner.Close()
+}
@@ -9,15 +9,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"time"
 
-	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/timestamp"
-	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/prometheus/prometheus/util/zeropool"
 
+	"github.com/grafana/mimir/pkg/streamingpromql/aggregations"
+	"github.com/grafana/mimir/pkg/streamingpromql/compat"
 	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
 	"github.com/grafana/mimir/pkg/streamingpromql/types"
 )
@@ -28,9 +30,18 @@ type Aggregation struct {
 	End      int64 // Milliseconds since Unix epoch
 	Interval int64 // In milliseconds
 	Steps    int
-	Grouping []string
+	Grouping []string // Sorted. If Without is true, these are the labels to drop, otherwise these are the labels to keep.
+	Without  bool
 	Pool     *pooling.LimitingPool
 
+	// Param is the parameter of the aggregation (eg. φ for quantile), or nil if the aggregation does not take a parameter.
+	Param types.ScalarOperator
+
+	aggregationGroupFactory aggregations.AggregationGroupFactory
+
+	// Used to create aggregationGroupFactory once Param has been evaluated, if Param is not nil.
+	aggregationGroupFactoryForParam func(param float64) aggregations.AggregationGroupFactory
+
 	remainingInnerSeriesToGroup []*group // One entry per series produced by Inner, value is the group for that series
 	remainingGroups             []*group // One entry per group, in the order we want to return them
 }
@@ -41,9 +52,49 @@ func NewAggregation(
 	end time.Time,
 	interval time.Duration,
 	grouping []string,
+	without bool,
+	op parser.ItemType,
+	pool *pooling.LimitingPool,
+) (*Aggregation, error) {
+	groupFactory, exists := aggregations.AggregationGroupFactories[op]
+	if !exists {
+		return nil, compat.NewNotSupportedError(fmt.Sprintf("aggregation operation with '%s'", op))
+	}
+
+	return newAggregationWithFactory(inner, start, end, interval, grouping, without, groupFactory, pool), nil
+}
+
+// NewQuantileAggregation returns an Aggregation that computes the φ-quantile of each group.
+func NewQuantileAggregation(
+	inner types.InstantVectorOperator,
+	start time.Time,
+	end time.Time,
+	interval time.Duration,
+	grouping []string,
+	without bool,
+	q types.ScalarOperator,
+	pool *pooling.LimitingPool,
+) *Aggregation {
+	a := newAggregationWithFactory(inner, start, end, interval, grouping, without, nil, pool)
+	a.Param = q
+	a.aggregationGroupFactoryForParam = aggregations.NewQuantileAggregationGroupFactory
+
+	return a
+}
+
+func newAggregationWithFactory(
+	inner types.InstantVectorOperator,
+	start time.Time,
+	end time.Time,
+	interval time.Duration,
+	grouping []string,
+	without bool,
+	groupFactory aggregations.AggregationGroupFactory,
 	pool *pooling.LimitingPool,
 ) *Aggregation {
 	s, e, i := timestamp.FromTime(start), timestamp.FromTime(end), interval.Milliseconds()
+	slices.Sort(grouping)
+
 	return &Aggregation{
 		Inner:    inner,
 		Start:    s,
@@ -51,7 +102,10 @@ func NewAggregation(
 		Interval: i,
 		Steps:    stepCount(s, e, i),
 		Grouping: grouping,
+		Without:  without,
 		Pool:     pool,
+
+		aggregationGroupFactory: groupFactory,
 	}
 }
 
@@ -68,11 +122,8 @@ type group struct {
 	// Used to sort groups in the order that they'll be completed in.
 	lastSeriesIndex int
 
-	// Sum, presence, and histograms for each step.
-	floatSums           []float64
-	floatPresent        []bool
-	histogramSums       []*histogram.FloatHistogram
-	histogramPointCount int
+	// The aggregation for this group of series.
+	aggregation aggregations.AggregationGroup
 }
 
 var _ types.InstantVectorOperator = &Aggregation{}
@@ -82,6 +133,16 @@ var groupPool = zeropool.New(func() *group {
 })
 
 func (a *Aggregation) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	if a.Param != nil {
+		// Evaluate the parameter before the source series, consistent with Prometheus' engine.
+		param, err := evaluateAggregationParam(ctx, a.Param, a.Pool)
+		if err != nil {
+			return nil, err
+		}
+
+		a.aggregationGroupFactory = a.aggregationGroupFactoryForParam(param)
+	}
+
 	// Fetch the source series
 	innerSeries, err := a.Inner.SeriesMetadata(ctx)
 	if err != nil {
@@ -97,21 +158,21 @@ func (a *Aggregation) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadat
 
 	// Determine the groups we'll return
 	groups := map[uint64]groupWithLabels{}
-	buf := make([]byte, 0, 1024)
-	lb := labels.NewBuilder(labels.EmptyLabels())
+	groupingKeyFunc := newGroupingKeyFunc(a.Grouping, a.Without)
+	groupLabelsFunc := newGroupLabelsFunc(a.Grouping, a.Without)
 	a.remainingInnerSeriesToGroup = make([]*group, 0, len(innerSeries))
 
 	for seriesIdx, series := range innerSeries {
 		// Note that this doesn't handle potential hash collisions between groups.
 		// This is something we should likely fix, but at present, Prometheus' PromQL engine doesn't handle collisions either,
 		// so at least both engines will be incorrect in the same way.
-		var groupingKey uint64
-		groupingKey, buf = series.Labels.HashForLabels(buf, a.Grouping...)
+		groupingKey := groupingKeyFunc(series.Labels)
 		g, groupExists := groups[groupingKey]
 
 		if !groupExists {
-			g.labels = a.labelsForGroup(series.Labels, lb)
+			g.labels = groupLabelsFunc(series.Labels)
 			g.group = groupPool.Get()
+			g.group.aggregation = a.aggregationGroupFactory()
 			g.group.remainingSeriesCount = 0
 
 			groups[groupingKey] = g
@@ -136,14 +197,59 @@ func (a *Aggregation) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadat
 	return seriesMetadata, nil
 }
 
-func (a *Aggregation) labelsForGroup(m labels.Labels, lb *labels.Builder) labels.Labels {
-	if len(a.Grouping) == 0 {
-		return labels.EmptyLabels()
+// newGroupingKeyFunc returns a function that computes the grouping key of a series for the given grouping labels.
+//
+// grouping must be sorted.
+func newGroupingKeyFunc(grouping []string, without bool) func(labels.Labels) uint64 {
+	buf := make([]byte, 0, 1024)
+
+	if without {
+		return func(m labels.Labels) uint64 {
+			// HashWithoutLabels also ignores __name__, so we don't need to add it to grouping.
+			var key uint64
+			key, buf = m.HashWithoutLabels(buf, grouping...)
+			return key
+		}
 	}
 
-	lb.Reset(m)
-	lb.Keep(a.Grouping...)
-	return lb.Labels()
+	if len(grouping) == 0 {
+		// No need to compute a hash if there are no grouping labels: all series belong to the same group.
+		return func(_ labels.Labels) uint64 {
+			return 0
+		}
+	}
+
+	return func(m labels.Labels) uint64 {
+		var key uint64
+		key, buf = m.HashForLabels(buf, grouping...)
+		return key
+	}
+}
+
+// newGroupLabelsFunc returns a function that computes the labels of the group a series belongs to for the given grouping labels.
+func newGroupLabelsFunc(grouping []string, without bool) func(labels.Labels) labels.Labels {
+	lb := labels.NewBuilder(labels.EmptyLabels())
+
+	if without {
+		return func(m labels.Labels) labels.Labels {
+			lb.Reset(m)
+			lb.Del(grouping...)
+			lb.Del(labels.MetricName)
+			return lb.Labels()
+		}
+	}
+
+	if len(grouping) == 0 {
+		return func(_ labels.Labels) labels.Labels {
+			return labels.EmptyLabels()
+		}
+	}
+
+	return func(m labels.Labels) labels.Labels {
+		lb.Reset(m)
+		lb.Keep(grouping...)
+		return lb.Labels()
+	}
 }
 
 func (a *Aggregation) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
@@ -162,11 +268,12 @@ func (a *Aggregation) NextSeries(ctx context.Context) (types.InstantVectorSeries
 	}
 
 	// Construct the group and return it
-	seriesData, err := a.constructSeriesData(thisGroup, a.Start, a.Interval)
+	seriesData, err := thisGroup.aggregation.ComputeOutputSeries(a.Start, a.Interval, a.Pool)
 	if err != nil {
 		return types.InstantVectorSeriesData{}, err
 	}
 
+	thisGroup.aggregation = nil
 	groupPool.Put(thisGroup)
 	return seriesData, nil
 }
@@ -184,149 +291,42 @@ func (a *Aggregation) accumulateUntilGroupComplete(ctx context.Context, g *group
 
 		thisSeriesGroup := a.remainingInnerSeriesToGroup[0]
 		a.remainingInnerSeriesToGroup = a.remainingInnerSeriesToGroup[1:]
-		err = a.accumulateSeriesIntoGroup(s, thisSeriesGroup, a.Steps, a.Start, a.Interval)
+		err = thisSeriesGroup.aggregation.AccumulateSeries(s, a.Steps, a.Start, a.Interval, a.Pool)
+		a.Pool.PutInstantVectorSeriesData(s)
 		if err != nil {
 			return err
 		}
+
+		thisSeriesGroup.remainingSeriesCount--
 	}
 	return nil
 }
 
-func (a *Aggregation) constructSeriesData(thisGroup *group, start int64, interval int64) (types.InstantVectorSeriesData, error) {
-	floatPointCount := thisGroup.reconcileAndCountFloatPoints()
-	var floatPoints []promql.FPoint
-	var err error
-	if floatPointCount > 0 {
-		floatPoints, err = a.Pool.GetFPointSlice(floatPointCount)
-		if err != nil {
-			return types.InstantVectorSeriesData{}, err
-		}
-
-		for i, havePoint := range thisGroup.floatPresent {
-			if havePoint {
-				t := start + int64(i)*interval
-				floatPoints = append(floatPoints, promql.FPoint{T: t, F: thisGroup.floatSums[i]})
-			}
-		}
-	}
-
-	var histogramPoints []promql.HPoint
-	if thisGroup.histogramPointCount > 0 {
-		histogramPoints, err = a.Pool.GetHPointSlice(thisGroup.histogramPointCount)
-		if err != nil {
-			return types.InstantVectorSeriesData{}, err
-		}
-
-		for i, h := range thisGroup.histogramSums {
-			if h != nil {
-				t := start + int64(i)*interval
-				histogramPoints = append(histogramPoints, promql.HPoint{T: t, H: thisGroup.histogramSums[i]})
-			}
-		}
-	}
-
-	a.Pool.PutFloatSlice(thisGroup.floatSums)
-	a.Pool.PutBoolSlice(thisGroup.floatPresent)
-	a.Pool.PutHistogramPointerSlice(thisGroup.histogramSums)
-	thisGroup.floatSums = nil
-	thisGroup.floatPresent = nil
-	thisGroup.histogramSums = nil
-	thisGroup.histogramPointCount = 0
-
-	return types.InstantVectorSeriesData{Floats: floatPoints, Histograms: histogramPoints}, nil
-}
+func (a *Aggregation) Close() {
+	a.Inner.Close()
 
-// reconcileAndCountFloatPoints will return the number of points with a float present.
-// It also takes the opportunity whilst looping through the floats to check if there
-// is a conflicting Histogram present. If both are present, an empty vector should
-// be returned. So this method removes the float+histogram where they conflict.
-func (g *group) reconcileAndCountFloatPoints() int {
-	// It would be possible to calculate the number of points when constructing
-	// the series groups. However, it requires checking each point at each input
-	// series which is more costly than looping again here and just checking each
-	// point of the already grouped series.
-	// See: https://github.com/grafana/mimir/pull/8442
-	// We also take two different approaches here: One with extra checks if we
-	// have both Floats and Histograms present, and one without these checks
-	// so we don't have to do it at every point.
-	floatPointCount := 0
-	if len(g.floatPresent) > 0 && len(g.histogramSums) > 0 {
-		for idx, present := range g.floatPresent {
-			if present {
-				if g.histogramSums[idx] != nil {
-					// If a mix of histogram samples and float samples, the corresponding vector element is removed from the output vector entirely.
-					g.floatPresent[idx] = false
-					g.histogramSums[idx] = nil
-					g.histogramPointCount--
-				} else {
-					floatPointCount++
-				}
-			}
-		}
-	} else {
-		for _, p := range g.floatPresent {
-			if p {
-				floatPointCount++
-			}
-		}
+	if a.Param != nil {
+		a.Param.Close()
 	}
-	return floatPointCount
 }
 
-func (a *Aggregation) accumulateSeriesIntoGroup(s types.InstantVectorSeriesData, seriesGroup *group, steps int, start int64, interval int64) error {
-	var err error
-	if len(s.Floats) > 0 && seriesGroup.floatSums == nil {
-		// First series with float values for this group, populate it.
-		seriesGroup.floatSums, err = a.Pool.GetFloatSlice(steps)
-		if err != nil {
-			return err
-		}
-
-		seriesGroup.floatPresent, err = a.Pool.GetBoolSlice(steps)
-		if err != nil {
-			return err
-		}
-		seriesGroup.floatSums = seriesGroup.floatSums[:steps]
-		seriesGroup.floatPresent = seriesGroup.floatPresent[:steps]
-	}
-
-	if len(s.Histograms) > 0 && seriesGroup.histogramSums == nil {
-		// First series with histogram values for this group, populate it.
-		seriesGroup.histogramSums, err = a.Pool.GetHistogramPointerSlice(steps)
-		if err != nil {
-			return err
-		}
-		seriesGroup.histogramSums = seriesGroup.histogramSums[:steps]
+// evaluateAggregationParam returns the value of the parameter of an aggregation such as topk or quantile.
+//
+// Like Prometheus' engine, the value of the parameter at the first step is used for all steps.
+func evaluateAggregationParam(ctx context.Context, param types.ScalarOperator, pool *pooling.LimitingPool) (float64, error) {
+	data, err := param.GetValues(ctx)
+	if err != nil {
+		return 0, err
 	}
 
-	for _, p := range s.Floats {
-		idx := (p.T - start) / interval
-		seriesGroup.floatSums[idx] += p.F
-		seriesGroup.floatPresent[idx] = true
-	}
+	defer pool.PutFPointSlice(data.Samples)
 
-	for _, p := range s.Histograms {
-		idx := (p.T - start) / interval
-		if seriesGroup.histogramSums[idx] == nil {
-			// We copy here because we modify the histogram through Add later on.
-			// It is necessary to preserve the original Histogram in case of any range-queries using lookback.
-			seriesGroup.histogramSums[idx] = p.H.Copy()
-			// We already have to do the check if the histogram exists at this idx,
-			// so we can count the histogram points present at this point instead
-			// of needing to loop again later like we do for floats.
-			seriesGroup.histogramPointCount++
-		} else {
-			seriesGroup.histogramSums[idx] = seriesGroup.histogramSums[idx].Add(p.H)
-		}
+	if len(data.Samples) == 0 {
+		// Should never happen: scalars always have a value at every step.
+		return 0, errors.New("expected aggregation parameter to have at least one sample, but it has none")
 	}
 
-	a.Pool.PutInstantVectorSeriesData(s)
-	seriesGroup.remainingSeriesCount--
-	return nil
-}
-
-func (a *Aggregation) Close() {
-	a.Inner.Close()
+	return data.Samples[0].F, nil
 }
 
 type groupSorter struct {
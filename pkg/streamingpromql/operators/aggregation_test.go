@@ -7,8 +7,10 @@ import (
 	"testing"
 
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/mimir/pkg/streamingpromql/aggregations"
 	"github.com/grafana/mimir/pkg/streamingpromql/types"
 )
 
@@ -77,6 +79,8 @@ func TestAggregation_ReturnsGroupsFinishedFirstEarliest(t *testing.T) {
 			aggregator := &Aggregation{
 				Inner:    &testOperator{series: testCase.inputSeries},
 				Grouping: testCase.grouping,
+
+				aggregationGroupFactory: aggregations.AggregationGroupFactories[parser.SUM],
 			}
 
 			outputSeries, err := aggregator.SeriesMetadata(context.Background())
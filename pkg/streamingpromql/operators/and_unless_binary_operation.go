@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// AndUnlessBinaryOperation represents a logical 'and' or 'unless' between two instant vectors.
+//
+// 'and' returns the points from the left side that have a matching point on the right side at the same time step,
+// and 'unless' returns the points from the left side that do not.
+// Output series always have the labels of the left side series.
+type AndUnlessBinaryOperation struct {
+	Left               types.InstantVectorOperator
+	Right              types.InstantVectorOperator
+	VectorMatching     parser.VectorMatching
+	IsUnless           bool  // If true, this operator represents an 'unless', if false, this operator represents an 'and'
+	Start              int64 // Milliseconds since Unix epoch
+	End                int64 // Milliseconds since Unix epoch
+	Interval           int64 // In milliseconds
+	Steps              int
+	Pool               *pooling.LimitingPool
+	ConcurrencyLimiter *ConcurrencyLimiter
+
+	remainingSeries       []*andUnlessOutputSeries
+	nextLeftSeriesToRead  int
+	rightSideSeriesBuffer *binaryOperationSeriesBuffer
+}
+
+var _ types.InstantVectorOperator = &AndUnlessBinaryOperation{}
+
+type andUnlessOutputSeries struct {
+	leftSeriesIndex int
+	rightSide       *andUnlessRightSideGroup // nil if there are no matching series on the right side.
+}
+
+// andUnlessRightSideGroup represents all the series on the right side that share a match group.
+type andUnlessRightSideGroup struct {
+	rightSeriesIndices []int
+
+	// Time steps at which at least one series in this group has a point.
+	// Populated when the first left side series in this group is computed, and released after the last
+	// left side series in this group is computed.
+	presence []bool
+
+	remainingLeftSeriesCount int
+}
+
+func NewAndUnlessBinaryOperation(
+	left types.InstantVectorOperator,
+	right types.InstantVectorOperator,
+	vectorMatching parser.VectorMatching,
+	isUnless bool,
+	start time.Time,
+	end time.Time,
+	interval time.Duration,
+	pool *pooling.LimitingPool,
+	concurrencyLimiter *ConcurrencyLimiter,
+) *AndUnlessBinaryOperation {
+	s, e, i := timestamp.FromTime(start), timestamp.FromTime(end), interval.Milliseconds()
+
+	return &AndUnlessBinaryOperation{
+		Left:           left,
+		Right:          right,
+		VectorMatching: vectorMatching,
+		IsUnless:       isUnless,
+		Start:          s,
+		End:            e,
+		Interval:       i,
+		Steps:          stepCount(s, e, i),
+		Pool:           pool,
+
+		ConcurrencyLimiter: concurrencyLimiter,
+	}
+}
+
+func (a *AndUnlessBinaryOperation) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	leftMetadata, rightMetadata, err := loadSeriesMetadataForBothSides(ctx, a.Left, a.Right, true, a.ConcurrencyLimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	defer pooling.PutSeriesMetadataSlice(rightMetadata)
+
+	if len(leftMetadata) == 0 {
+		// No series on left-hand side, we'll never have any output series.
+		pooling.PutSeriesMetadataSlice(leftMetadata)
+		return nil, nil
+	}
+
+	if len(rightMetadata) == 0 && !a.IsUnless {
+		// No series on right-hand side, so 'and' will never have any output series.
+		pooling.PutSeriesMetadataSlice(leftMetadata)
+		return nil, nil
+	}
+
+	groupKeyFunc := vectorMatchingGroupKeyFunc(a.VectorMatching)
+	rightSideGroups := map[string]*andUnlessRightSideGroup{}
+
+	for idx, s := range rightMetadata {
+		groupKey := groupKeyFunc(s.Labels)
+		group, exists := rightSideGroups[string(groupKey)] // Important: don't extract the string(...) call here - passing it directly allows us to avoid allocating it.
+
+		if !exists {
+			group = &andUnlessRightSideGroup{}
+			rightSideGroups[string(groupKey)] = group
+		}
+
+		group.rightSeriesIndices = append(group.rightSeriesIndices, idx)
+	}
+
+	rightSeriesUsed, err := a.Pool.GetBoolSlice(len(rightMetadata))
+	if err != nil {
+		return nil, err
+	}
+
+	rightSeriesUsed = rightSeriesUsed[:len(rightMetadata)]
+	a.remainingSeries = make([]*andUnlessOutputSeries, 0, len(leftMetadata))
+	nextOutputSeriesIndex := 0
+
+	for leftIdx, s := range leftMetadata {
+		group := rightSideGroups[string(groupKeyFunc(s.Labels))]
+
+		if group == nil && !a.IsUnless {
+			// No matching series on the right side, so this series will never produce any output for 'and'.
+			continue
+		}
+
+		if group != nil {
+			group.remainingLeftSeriesCount++
+
+			for _, rightIdx := range group.rightSeriesIndices {
+				rightSeriesUsed[rightIdx] = true
+			}
+		}
+
+		a.remainingSeries = append(a.remainingSeries, &andUnlessOutputSeries{leftSeriesIndex: leftIdx, rightSide: group})
+
+		// Remove series we won't return from the metadata slice, in place.
+		leftMetadata[nextOutputSeriesIndex] = s
+		nextOutputSeriesIndex++
+	}
+
+	if nextOutputSeriesIndex == 0 {
+		pooling.PutSeriesMetadataSlice(leftMetadata)
+		a.Pool.PutBoolSlice(rightSeriesUsed)
+		return nil, nil
+	}
+
+	a.rightSideSeriesBuffer = newBinaryOperationSeriesBuffer(a.Right, rightSeriesUsed, a.Pool)
+
+	return leftMetadata[:nextOutputSeriesIndex], nil
+}
+
+func (a *AndUnlessBinaryOperation) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	if len(a.remainingSeries) == 0 {
+		return types.InstantVectorSeriesData{}, types.EOS
+	}
+
+	thisSeries := a.remainingSeries[0]
+	a.remainingSeries = a.remainingSeries[1:]
+
+	leftData, err := a.nextLeftSeries(ctx, thisSeries.leftSeriesIndex)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	if thisSeries.rightSide == nil {
+		// No matching series on the right side, so this must be 'unless': return the left side unchanged.
+		return leftData, nil
+	}
+
+	if err := a.computeRightSidePresence(ctx, thisSeries.rightSide); err != nil {
+		a.Pool.PutInstantVectorSeriesData(leftData)
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	presence := thisSeries.rightSide.presence
+	filteredFloats := leftData.Floats[:0]
+
+	for _, p := range leftData.Floats {
+		if presence[(p.T-a.Start)/a.Interval] != a.IsUnless {
+			filteredFloats = append(filteredFloats, p)
+		}
+	}
+
+	filteredHistograms := leftData.Histograms[:0]
+
+	for _, p := range leftData.Histograms {
+		if presence[(p.T-a.Start)/a.Interval] != a.IsUnless {
+			filteredHistograms = append(filteredHistograms, p)
+		}
+	}
+
+	thisSeries.rightSide.remainingLeftSeriesCount--
+	if thisSeries.rightSide.remainingLeftSeriesCount == 0 {
+		a.Pool.PutBoolSlice(thisSeries.rightSide.presence)
+		thisSeries.rightSide.presence = nil
+	}
+
+	return types.InstantVectorSeriesData{Floats: filteredFloats, Histograms: filteredHistograms}, nil
+}
+
+// nextLeftSeries returns the data for the left side series with index idx, discarding any skipped series.
+func (a *AndUnlessBinaryOperation) nextLeftSeries(ctx context.Context, idx int) (types.InstantVectorSeriesData, error) {
+	for a.nextLeftSeriesToRead < idx {
+		d, err := a.Left.NextSeries(ctx)
+		if err != nil {
+			return types.InstantVectorSeriesData{}, err
+		}
+
+		a.Pool.PutInstantVectorSeriesData(d)
+		a.nextLeftSeriesToRead++
+	}
+
+	a.nextLeftSeriesToRead++
+	return a.Left.NextSeries(ctx)
+}
+
+// computeRightSidePresence populates group.presence, if it hasn't already been populated.
+func (a *AndUnlessBinaryOperation) computeRightSidePresence(ctx context.Context, group *andUnlessRightSideGroup) error {
+	if group.presence != nil {
+		return nil
+	}
+
+	var err error
+	group.presence, err = a.Pool.GetBoolSlice(a.Steps)
+	if err != nil {
+		return err
+	}
+
+	group.presence = group.presence[:a.Steps]
+
+	data, err := a.rightSideSeriesBuffer.getSeries(ctx, group.rightSeriesIndices)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range data {
+		for _, p := range d.Floats {
+			group.presence[(p.T-a.Start)/a.Interval] = true
+		}
+
+		for _, p := range d.Histograms {
+			group.presence[(p.T-a.Start)/a.Interval] = true
+		}
+
+		a.Pool.PutInstantVectorSeriesData(d)
+	}
+
+	return nil
+}
+
+func (a *AndUnlessBinaryOperation) Close() {
+	a.Left.Close()
+	a.Right.Close()
+
+	if a.rightSideSeriesBuffer != nil {
+		a.rightSideSeriesBuffer.close()
+	}
+
+	for _, s := range a.remainingSeries {
+		if s.rightSide != nil && s.rightSide.presence != nil {
+			a.Pool.PutBoolSlice(s.rightSide.presence)
+			s.rightSide.presence = nil
+		}
+	}
+}
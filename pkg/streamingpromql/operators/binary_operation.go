@@ -7,6 +7,7 @@ package operators
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"slices"
@@ -23,14 +24,23 @@ import (
 	"github.com/grafana/mimir/pkg/streamingpromql/types"
 )
 
-// BinaryOperation represents a binary operation between instant vectors such as "<expr> + <expr>" or "<expr> - <expr>".
+// BinaryOperation represents a one-to-one binary operation between instant vectors such as "<expr> + <expr>" or "<expr> > <expr>".
+//
+// Binary operations with many-to-one or one-to-many matching are handled by GroupedBinaryOperation, and
+// set operations ('and', 'or' and 'unless') are handled by AndUnlessBinaryOperation and OrBinaryOperation.
 type BinaryOperation struct {
-	Left  types.InstantVectorOperator
-	Right types.InstantVectorOperator
-	Op    parser.ItemType
-	Pool  *pooling.LimitingPool
-
-	VectorMatching parser.VectorMatching
+	Left       types.InstantVectorOperator
+	Right      types.InstantVectorOperator
+	Op         parser.ItemType
+	ReturnBool bool
+	Start      int64 // Milliseconds since Unix epoch
+	End        int64 // Milliseconds since Unix epoch
+	Interval   int64 // In milliseconds
+	Steps      int
+	Pool       *pooling.LimitingPool
+
+	VectorMatching     parser.VectorMatching
+	ConcurrencyLimiter *ConcurrencyLimiter
 
 	// We need to retain these so that NextSeries() can return an error message with the series labels when
 	// multiple points match on a single side.
@@ -50,6 +60,25 @@ var _ types.InstantVectorOperator = &BinaryOperation{}
 type binaryOperationOutputSeries struct {
 	leftSeriesIndices  []int
 	rightSeriesIndices []int
+
+	// All output series from the same match group share the same right side.
+	// There is only ever more than one output series for a match group for comparison operations without the
+	// 'bool' modifier, as the output series retain the labels of the series from the left side.
+	rightSide *binaryOperationRightSide
+}
+
+// binaryOperationRightSide holds the merged data from the right side of a match group.
+type binaryOperationRightSide struct {
+	// Populated when the first output series that uses this right side is computed, and retained until the last
+	// output series that uses this right side is computed.
+	mergedData types.InstantVectorSeriesData
+	populated  bool
+
+	remainingOutputSeriesCount int
+
+	// If more than one output series uses this right side, leftSidePresence[i] is true if an earlier output series
+	// produced a point at time step i. This allows us to detect multiple matches on the left side that span output series.
+	leftSidePresence []bool
 }
 
 // latestLeftSeries returns the index of the last series from the left source needed for this output series.
@@ -66,19 +95,49 @@ func (s binaryOperationOutputSeries) latestRightSeries() int {
 	return s.rightSeriesIndices[len(s.rightSeriesIndices)-1]
 }
 
-func NewBinaryOperation(left types.InstantVectorOperator, right types.InstantVectorOperator, vectorMatching parser.VectorMatching, op parser.ItemType, pool *pooling.LimitingPool) (*BinaryOperation, error) {
-	opFunc := arithmeticOperationFuncs[op]
-	if opFunc == nil {
-		return nil, compat.NewNotSupportedError(fmt.Sprintf("binary expression with '%s'", op))
+func NewBinaryOperation(
+	left types.InstantVectorOperator,
+	right types.InstantVectorOperator,
+	vectorMatching parser.VectorMatching,
+	op parser.ItemType,
+	returnBool bool,
+	start time.Time,
+	end time.Time,
+	interval time.Duration,
+	pool *pooling.LimitingPool,
+	concurrencyLimiter *ConcurrencyLimiter,
+) (*BinaryOperation, error) {
+	var opFunc binaryOperationFunc
+
+	switch {
+	case returnBool:
+		opFunc = boolComparisonOperationFuncs[op]
+	case op.IsComparisonOperator():
+		// Comparison operations without the 'bool' modifier filter points from the left side rather than computing
+		// a new value, so there's no opFunc.
+	default:
+		opFunc = arithmeticOperationFuncs[op]
+		if opFunc == nil {
+			return nil, compat.NewNotSupportedError(fmt.Sprintf("binary expression with '%s'", op))
+		}
 	}
 
+	s, e, i := timestamp.FromTime(start), timestamp.FromTime(end), interval.Milliseconds()
+
 	return &BinaryOperation{
 		Left:           left,
 		Right:          right,
 		VectorMatching: vectorMatching,
 		Op:             op,
+		ReturnBool:     returnBool,
+		Start:          s,
+		End:            e,
+		Interval:       i,
+		Steps:          stepCount(s, e, i),
 		Pool:           pool,
 
+		ConcurrencyLimiter: concurrencyLimiter,
+
 		opFunc: opFunc,
 	}, nil
 }
@@ -127,24 +186,17 @@ func (b *BinaryOperation) loadSeriesMetadata(ctx context.Context) (bool, error)
 	// We'll return them to the pool in Close().
 
 	var err error
-	b.leftMetadata, err = b.Left.SeriesMetadata(ctx)
+	b.leftMetadata, b.rightMetadata, err = loadSeriesMetadataForBothSides(ctx, b.Left, b.Right, true, b.ConcurrencyLimiter)
 	if err != nil {
 		return false, err
 	}
 
 	if len(b.leftMetadata) == 0 {
-		// FIXME: this is incorrect for 'or'
 		// No series on left-hand side, we'll never have any output series.
 		return false, nil
 	}
 
-	b.rightMetadata, err = b.Right.SeriesMetadata(ctx)
-	if err != nil {
-		return false, err
-	}
-
 	if len(b.rightMetadata) == 0 {
-		// FIXME: this is incorrect for 'or' and 'unless'
 		// No series on right-hand side, we'll never have any output series.
 		return false, nil
 	}
@@ -167,7 +219,6 @@ func (b *BinaryOperation) computeOutputSeries() ([]types.SeriesMetadata, []*bina
 	// Use the smaller side to populate the map of possible output series first.
 	// This should ensure we don't unnecessarily populate the output series map with series that will never match in most cases.
 	// (It's possible that all the series on the larger side all belong to the same group, but this is expected to be rare.)
-	// FIXME: this doesn't work as-is for 'unless'.
 	smallerSide := b.leftMetadata
 	largerSide := b.rightMetadata
 	smallerSideIsLeftSide := len(b.leftMetadata) < len(b.rightMetadata)
@@ -204,14 +255,11 @@ func (b *BinaryOperation) computeOutputSeries() ([]types.SeriesMetadata, []*bina
 				series.leftSeriesIndices = append(series.leftSeriesIndices, idx)
 			}
 		}
-
-		// FIXME: if this is an 'or' operation, then we need to create the right side even if the left doesn't exist (or vice-versa)
 	}
 
 	// Remove series that cannot produce samples.
 	for seriesLabels, outputSeries := range outputSeriesMap {
 		if len(outputSeries.leftSeriesIndices) == 0 || len(outputSeries.rightSeriesIndices) == 0 {
-			// FIXME: this is incorrect for 'or' and 'unless'
 			// No matching series on at least one side for this output series, so output series will have no samples. Remove it.
 			delete(outputSeriesMap, seriesLabels)
 		}
@@ -219,6 +267,7 @@ func (b *BinaryOperation) computeOutputSeries() ([]types.SeriesMetadata, []*bina
 
 	allMetadata := make([]types.SeriesMetadata, 0, len(outputSeriesMap))
 	allSeries := make([]*binaryOperationOutputSeries, 0, len(outputSeriesMap))
+	outputLabelsFunc := b.outputLabelsFunc()
 
 	leftSeriesUsed, err := b.Pool.GetBoolSlice(len(b.leftMetadata))
 	if err != nil {
@@ -234,10 +283,6 @@ func (b *BinaryOperation) computeOutputSeries() ([]types.SeriesMetadata, []*bina
 	rightSeriesUsed = rightSeriesUsed[:len(b.rightMetadata)]
 
 	for _, outputSeries := range outputSeriesMap {
-		firstSeriesLabels := b.leftMetadata[outputSeries.leftSeriesIndices[0]].Labels
-		allMetadata = append(allMetadata, types.SeriesMetadata{Labels: labelsFunc(firstSeriesLabels)})
-		allSeries = append(allSeries, outputSeries)
-
 		for _, leftSeriesIndex := range outputSeries.leftSeriesIndices {
 			leftSeriesUsed[leftSeriesIndex] = true
 		}
@@ -245,6 +290,37 @@ func (b *BinaryOperation) computeOutputSeries() ([]types.SeriesMetadata, []*bina
 		for _, rightSeriesIndex := range outputSeries.rightSeriesIndices {
 			rightSeriesUsed[rightSeriesIndex] = true
 		}
+
+		rightSide := &binaryOperationRightSide{}
+
+		if outputLabelsFunc == nil {
+			// All series from the left side in this match group produce the same output series.
+			firstSeriesLabels := b.leftMetadata[outputSeries.leftSeriesIndices[0]].Labels
+			outputSeries.rightSide = rightSide
+			rightSide.remainingOutputSeriesCount = 1
+			allMetadata = append(allMetadata, types.SeriesMetadata{Labels: labelsFunc(firstSeriesLabels)})
+			allSeries = append(allSeries, outputSeries)
+			continue
+		}
+
+		// Series from the left side in this match group may produce different output series, so split them up.
+		outputSeriesForGroup := map[string]*binaryOperationOutputSeries{}
+
+		for _, leftSeriesIndex := range outputSeries.leftSeriesIndices {
+			outputLabels := outputLabelsFunc(b.leftMetadata[leftSeriesIndex].Labels)
+			key := outputLabels.String()
+			series, exists := outputSeriesForGroup[key]
+
+			if !exists {
+				series = &binaryOperationOutputSeries{rightSeriesIndices: outputSeries.rightSeriesIndices, rightSide: rightSide}
+				outputSeriesForGroup[key] = series
+				rightSide.remainingOutputSeriesCount++
+				allMetadata = append(allMetadata, types.SeriesMetadata{Labels: outputLabels})
+				allSeries = append(allSeries, series)
+			}
+
+			series.leftSeriesIndices = append(series.leftSeriesIndices, leftSeriesIndex)
+		}
 	}
 
 	return allMetadata, allSeries, leftSeriesUsed, rightSeriesUsed, nil
@@ -263,10 +339,6 @@ func (b *BinaryOperation) sortSeries(metadata []types.SeriesMetadata, series []*
 	// If we do this, then in the worst case, we'll have to buffer the whole of the lower cardinality side.
 	// (Compare this with sorting so that we read the lowest cardinality side in order: in the worst case, we'll have
 	// to buffer the whole of the higher cardinality side.)
-	//
-	// FIXME: this is reasonable for one-to-one matching, but likely not for one-to-many / many-to-one.
-	// For one-to-many / many-to-one, it would likely be best to buffer the side used for multiple output series (the "one" side),
-	// as we'll need to retain these series for multiple output series anyway.
 
 	var sortInterface sort.Interface
 
@@ -349,6 +421,51 @@ func (b *BinaryOperation) labelsFunc() func(labels.Labels) labels.Labels {
 	}
 }
 
+// outputLabelsFunc returns a function that computes the labels of the output series produced by a series from the left side,
+// or nil if all series in a match group produce the same output series with the labels returned by labelsFunc.
+func (b *BinaryOperation) outputLabelsFunc() func(labels.Labels) labels.Labels {
+	if !b.Op.IsComparisonOperator() || b.ReturnBool || b.VectorMatching.On {
+		return nil
+	}
+
+	// Comparison operations without the 'bool' modifier retain the metric name.
+	lb := labels.NewBuilder(labels.EmptyLabels())
+
+	return func(l labels.Labels) labels.Labels {
+		lb.Reset(l)
+		lb.Del(b.VectorMatching.MatchingLabels...)
+		return lb.Labels()
+	}
+}
+
+// vectorMatchingGroupKeyFunc returns a function that computes the key of the match group the series with the given labels
+// belongs to, based on the 'on' or 'ignoring' clause in vectorMatching.
+//
+// The returned slice is only valid until the returned function is called again.
+func vectorMatchingGroupKeyFunc(vectorMatching parser.VectorMatching) func(labels.Labels) []byte {
+	buf := make([]byte, 0, 1024)
+
+	if vectorMatching.On {
+		names := slices.Clone(vectorMatching.MatchingLabels)
+		slices.Sort(names)
+
+		return func(l labels.Labels) []byte {
+			buf = l.BytesWithLabels(buf, names...)
+			return buf
+		}
+	}
+
+	names := make([]string, 0, len(vectorMatching.MatchingLabels)+1)
+	names = append(names, labels.MetricName)
+	names = append(names, vectorMatching.MatchingLabels...)
+	slices.Sort(names)
+
+	return func(l labels.Labels) []byte {
+		buf = l.BytesWithoutLabels(buf, names...)
+		return buf
+	}
+}
+
 func (b *BinaryOperation) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
 	if len(b.remainingSeries) == 0 {
 		return types.InstantVectorSeriesData{}, types.EOS
@@ -356,28 +473,116 @@ func (b *BinaryOperation) NextSeries(ctx context.Context) (types.InstantVectorSe
 
 	thisSeries := b.remainingSeries[0]
 	b.remainingSeries = b.remainingSeries[1:]
+	rightSide := thisSeries.rightSide
+
+	// We merge the right side first so that, like Prometheus' engine, we report conflicts on the right side
+	// in preference to conflicts on the left side.
+	if !rightSide.populated {
+		allRightSeries, err := b.rightBuffer.getSeries(ctx, thisSeries.rightSeriesIndices)
+		if err != nil {
+			return types.InstantVectorSeriesData{}, err
+		}
+
+		rightSide.mergedData, err = b.mergeOneSide(allRightSeries, thisSeries.rightSeriesIndices, b.rightMetadata, b.rightSideConflictError)
+		if err != nil {
+			return types.InstantVectorSeriesData{}, err
+		}
+
+		rightSide.populated = true
+	}
+
+	defer func() {
+		rightSide.remainingOutputSeriesCount--
+		if rightSide.remainingOutputSeriesCount == 0 {
+			b.releaseRightSide(rightSide)
+		}
+	}()
 
 	allLeftSeries, err := b.leftBuffer.getSeries(ctx, thisSeries.leftSeriesIndices)
 	if err != nil {
 		return types.InstantVectorSeriesData{}, err
 	}
 
-	mergedLeftSide, err := b.mergeOneSide(allLeftSeries, thisSeries.leftSeriesIndices, b.leftMetadata, "left")
-	if err != nil {
-		return types.InstantVectorSeriesData{}, err
+	// Compute the result for each series from the left side before merging them, so that we only consider multiple
+	// matches on the left side to be an error if they produce output points at the same time step.
+	// Like Prometheus' engine, this means a conflict is only an error if there is also a matching point on the right
+	// side, and, for comparison operations without the 'bool' modifier, if the comparison is true for both points.
+	for i, d := range allLeftSeries {
+		allLeftSeries[i] = b.computeResult(d, rightSide.mergedData)
 	}
 
-	allRightSeries, err := b.rightBuffer.getSeries(ctx, thisSeries.rightSeriesIndices)
+	result, err := b.mergeOneSide(allLeftSeries, thisSeries.leftSeriesIndices, b.leftMetadata, func(_ int64, _, _ labels.Labels) error {
+		return errMultipleMatchesForOneToOneMatching
+	})
 	if err != nil {
 		return types.InstantVectorSeriesData{}, err
 	}
 
-	mergedRightSide, err := b.mergeOneSide(allRightSeries, thisSeries.rightSeriesIndices, b.rightMetadata, "right")
-	if err != nil {
-		return types.InstantVectorSeriesData{}, err
+	if rightSide.remainingOutputSeriesCount > 1 || rightSide.leftSidePresence != nil {
+		// Other output series share this right side, so check that we haven't already produced a point at the same
+		// time step from another series on the left side.
+		if err := b.checkForConflictsWithOtherOutputSeries(rightSide, result); err != nil {
+			b.Pool.PutInstantVectorSeriesData(result)
+			return types.InstantVectorSeriesData{}, err
+		}
+	}
+
+	return result, nil
+}
+
+func (b *BinaryOperation) checkForConflictsWithOtherOutputSeries(rightSide *binaryOperationRightSide, result types.InstantVectorSeriesData) error {
+	if rightSide.leftSidePresence == nil {
+		var err error
+		rightSide.leftSidePresence, err = b.Pool.GetBoolSlice(b.Steps)
+		if err != nil {
+			return err
+		}
+
+		rightSide.leftSidePresence = rightSide.leftSidePresence[:b.Steps]
+	}
+
+	for _, p := range result.Floats {
+		idx := (p.T - b.Start) / b.Interval
+
+		if rightSide.leftSidePresence[idx] {
+			return errMultipleMatchesForOneToOneMatching
+		}
+
+		rightSide.leftSidePresence[idx] = true
+	}
+
+	return nil
+}
+
+func (b *BinaryOperation) releaseRightSide(rightSide *binaryOperationRightSide) {
+	b.Pool.PutInstantVectorSeriesData(rightSide.mergedData)
+	rightSide.mergedData = types.InstantVectorSeriesData{}
+
+	if rightSide.leftSidePresence != nil {
+		b.Pool.PutBoolSlice(rightSide.leftSidePresence)
+		rightSide.leftSidePresence = nil
 	}
+}
+
+var errMultipleMatchesForOneToOneMatching = errors.New("multiple matches for labels: many-to-one matching must be explicit (group_left/group_right)")
+
+// mergeConflictFunc is called by mergeOneSide when two source series have points at the same timestamp.
+//
+// If it returns nil, the point from the second series is ignored.
+type mergeConflictFunc func(t int64, first, second labels.Labels) error
+
+func (b *BinaryOperation) rightSideConflictError(_ int64, first, second labels.Labels) error {
+	return newDuplicateSeriesOnOneSideError(first, second, b.VectorMatching, "right")
+}
+
+// newDuplicateSeriesOnOneSideError returns the same error as Prometheus' engine when multiple series on the "one" side
+// of a binary operation belong to the same match group at the same time step.
+//
+// first and second should be in the order the series were read from the operator.
+func newDuplicateSeriesOnOneSideError(first, second labels.Labels, vectorMatching parser.VectorMatching, side string) error {
+	matchedLabels := second.MatchLabels(vectorMatching.On, vectorMatching.MatchingLabels...)
 
-	return b.computeResult(mergedLeftSide, mergedRightSide), nil
+	return fmt.Errorf("found duplicate series for the match group %s on the %s hand-side of the operation: [%s, %s];many-to-many matching not allowed: matching labels must be unique on one side", matchedLabels.String(), side, second.String(), first.String())
 }
 
 // mergeOneSide exists to handle the case where one side of an output series has different source series at different time steps.
@@ -392,20 +597,42 @@ func (b *BinaryOperation) NextSeries(ctx context.Context) (types.InstantVectorSe
 //
 // mergeOneSide is optimised for the case where there is only one source series, or the source series do not overlap, as in the example above.
 //
-// FIXME: for many-to-one / one-to-many matching, we could avoid re-merging each time for the side used multiple times
-func (b *BinaryOperation) mergeOneSide(data []types.InstantVectorSeriesData, sourceSeriesIndices []int, sourceSeriesMetadata []types.SeriesMetadata, side string) (types.InstantVectorSeriesData, error) {
+// If multiple source series have points at the same timestamp, onConflict is called to determine if this is an error.
+func (b *BinaryOperation) mergeOneSide(data []types.InstantVectorSeriesData, sourceSeriesIndices []int, sourceSeriesMetadata []types.SeriesMetadata, onConflict mergeConflictFunc) (types.InstantVectorSeriesData, error) {
 	if len(data) == 1 {
 		// Fast path: if there's only one series on this side, there's no merging required.
 		return data[0], nil
 	}
 
+	// Remove any series with no points, as they can't contribute anything to the merged series.
+	// Note that this modifies data and sourceSeriesIndices in place.
+	nonEmptySeriesCount := 0
+	for i, d := range data {
+		if len(d.Floats) == 0 {
+			b.Pool.PutInstantVectorSeriesData(d)
+			continue
+		}
+
+		data[nonEmptySeriesCount] = d
+		sourceSeriesIndices[nonEmptySeriesCount] = sourceSeriesIndices[i]
+		nonEmptySeriesCount++
+	}
+
+	data = data[:nonEmptySeriesCount]
+	sourceSeriesIndices = sourceSeriesIndices[:nonEmptySeriesCount]
+
+	if len(data) == 1 {
+		return data[0], nil
+	}
+
 	if len(data) == 0 {
 		return types.InstantVectorSeriesData{}, nil
 	}
 
-	slices.SortFunc(data, func(a, b types.InstantVectorSeriesData) int {
-		return int(a.Floats[0].T - b.Floats[0].T)
-	})
+	// Sort the source series by their first point, keeping track of the index of each source series
+	// so that we can report the correct series if there is a conflict.
+	// Note that this modifies data and sourceSeriesIndices in place.
+	sort.Sort(seriesDataSorter{data, sourceSeriesIndices})
 
 	mergedSize := len(data[0].Floats)
 	haveOverlaps := false
@@ -413,7 +640,6 @@ func (b *BinaryOperation) mergeOneSide(data []types.InstantVectorSeriesData, sou
 	// We're going to create a new slice, so return this one to the pool.
 	// We'll return the other slices in the for loop below.
 	// We must defer here, rather than at the end, as the merge loop below reslices Floats.
-	// FIXME: this isn't correct for many-to-one / one-to-many matching - we'll need the series again (unless we store the result of the merge)
 	defer b.Pool.PutFPointSlice(data[0].Floats)
 
 	for i := 0; i < len(data)-1; i++ {
@@ -423,7 +649,6 @@ func (b *BinaryOperation) mergeOneSide(data []types.InstantVectorSeriesData, sou
 
 		// We're going to create a new slice, so return this one to the pool.
 		// We must defer here, rather than at the end, as the merge loop below reslices Floats.
-		// FIXME: this isn't correct for many-to-one / one-to-many matching - we'll need the series again (unless we store the result of the merge)
 		defer b.Pool.PutFPointSlice(second.Floats)
 
 		// Check if first overlaps with second.
@@ -454,6 +679,10 @@ func (b *BinaryOperation) mergeOneSide(data []types.InstantVectorSeriesData, sou
 	remainingSeries := len(data)
 
 	for {
+		if remainingSeries == 0 {
+			return types.InstantVectorSeriesData{Floats: output}, nil
+		}
+
 		if remainingSeries == 1 {
 			// Only one series left, just copy remaining points.
 			for _, d := range data {
@@ -475,11 +704,23 @@ func (b *BinaryOperation) mergeOneSide(data []types.InstantVectorSeriesData, sou
 			nextPointInSeries := d.Floats[0]
 			if nextPointInSeries.T == nextT {
 				// Another series has a point with the same timestamp. We have a conflict.
-				firstConflictingSeriesLabels := sourceSeriesMetadata[sourceSeriesIndices[sourceSeriesIndexInData]].Labels
-				secondConflictingSeriesLabels := sourceSeriesMetadata[sourceSeriesIndices[seriesIndexInData]].Labels
-				groupLabels := b.labelsFunc()(firstConflictingSeriesLabels)
+				firstSeriesIndex, secondSeriesIndex := sourceSeriesIndices[sourceSeriesIndexInData], sourceSeriesIndices[seriesIndexInData]
+				if firstSeriesIndex > secondSeriesIndex {
+					firstSeriesIndex, secondSeriesIndex = secondSeriesIndex, firstSeriesIndex
+				}
 
-				return types.InstantVectorSeriesData{}, fmt.Errorf("found duplicate series for the match group %s on the %s side of the operation at timestamp %s: %s and %s", groupLabels, side, timestamp.Time(nextT).Format(time.RFC3339Nano), firstConflictingSeriesLabels, secondConflictingSeriesLabels)
+				if err := onConflict(nextT, sourceSeriesMetadata[firstSeriesIndex].Labels, sourceSeriesMetadata[secondSeriesIndex].Labels); err != nil {
+					return types.InstantVectorSeriesData{}, err
+				}
+
+				// The conflict is not an error, so ignore this point.
+				data[seriesIndexInData].Floats = d.Floats[1:]
+
+				if len(data[seriesIndexInData].Floats) == 0 {
+					remainingSeries--
+				}
+
+				continue
 			}
 
 			if d.Floats[0].T < nextT {
@@ -497,21 +738,34 @@ func (b *BinaryOperation) mergeOneSide(data []types.InstantVectorSeriesData, sou
 	}
 }
 
-func (b *BinaryOperation) computeResult(left types.InstantVectorSeriesData, right types.InstantVectorSeriesData) types.InstantVectorSeriesData {
-	var output []promql.FPoint
-
-	// For one-to-one matching for arithmetic operators, reuse one of the input slices to avoid allocating another slice.
-	// We'll never produce more points than the smaller input side, so use that as our output slice.
-	//
-	// FIXME: this is not safe to do for one-to-many, many-to-one or many-to-many matching, as we may need the input series for later output series.
-	if len(left.Floats) < len(right.Floats) {
-		output = left.Floats[:0]
-		defer b.Pool.PutFPointSlice(right.Floats)
-	} else {
-		output = right.Floats[:0]
-		defer b.Pool.PutFPointSlice(left.Floats)
-	}
+type seriesDataSorter struct {
+	data                []types.InstantVectorSeriesData
+	sourceSeriesIndices []int
+}
+
+func (s seriesDataSorter) Len() int {
+	return len(s.data)
+}
+
+func (s seriesDataSorter) Swap(i, j int) {
+	s.data[i], s.data[j] = s.data[j], s.data[i]
+	s.sourceSeriesIndices[i], s.sourceSeriesIndices[j] = s.sourceSeriesIndices[j], s.sourceSeriesIndices[i]
+}
+
+func (s seriesDataSorter) Less(i, j int) bool {
+	return s.data[i].Floats[0].T < s.data[j].Floats[0].T
+}
 
+// computeResult computes the result of this operation for a single series from the left side and the merged series
+// from the right side.
+//
+// It reuses the slice from left for the result, and does not modify right.
+func (b *BinaryOperation) computeResult(left types.InstantVectorSeriesData, right types.InstantVectorSeriesData) types.InstantVectorSeriesData {
+	// We'll never produce more points than the left side has, so reuse its slice for the output.
+	// This is safe because we never write to an index we haven't already read from.
+	output := left.Floats[:0]
+	b.Pool.PutHPointSlice(left.Histograms)
+	filterOnly := b.opFunc == nil // Comparison operation without the 'bool' modifier.
 	nextRightIndex := 0
 
 	for _, leftPoint := range left.Floats {
@@ -524,13 +778,25 @@ func (b *BinaryOperation) computeResult(left types.InstantVectorSeriesData, righ
 			break
 		}
 
-		if leftPoint.T == right.Floats[nextRightIndex].T {
-			// We have matching points on both sides, compute the result.
-			output = append(output, promql.FPoint{
-				F: b.opFunc(leftPoint.F, right.Floats[nextRightIndex].F),
-				T: leftPoint.T,
-			})
+		if leftPoint.T != right.Floats[nextRightIndex].T {
+			continue
 		}
+
+		// We have matching points on both sides, compute the result.
+		rightPoint := right.Floats[nextRightIndex]
+
+		if filterOnly {
+			if _, _, keep := vectorElementBinaryOperation(b.Op, leftPoint.F, rightPoint.F, nil, nil); keep {
+				output = append(output, leftPoint)
+			}
+
+			continue
+		}
+
+		output = append(output, promql.FPoint{
+			F: b.opFunc(leftPoint.F, rightPoint.F),
+			T: leftPoint.T,
+		})
 	}
 
 	return types.InstantVectorSeriesData{
@@ -557,6 +823,13 @@ func (b *BinaryOperation) Close() {
 	if b.rightBuffer != nil {
 		b.rightBuffer.close()
 	}
+
+	for _, s := range b.remainingSeries {
+		if s.rightSide.populated {
+			b.releaseRightSide(s.rightSide)
+			s.rightSide.populated = false
+		}
+	}
 }
 
 // binaryOperationSeriesBuffer buffers series data until it is needed by BinaryOperation.
@@ -625,8 +898,8 @@ func (b *binaryOperationSeriesBuffer) getSingleSeries(ctx context.Context, serie
 			// We need this series later, but not right now. Store it for later.
 			b.buffer[b.nextIndexToRead] = d
 		} else {
-			// We don't need this series at all, return the slice to the pool now.
-			b.pool.PutFPointSlice(d.Floats)
+			// We don't need this series at all, return the slices to the pool now.
+			b.pool.PutInstantVectorSeriesData(d)
 		}
 
 		b.nextIndexToRead++
@@ -669,3 +942,32 @@ var arithmeticOperationFuncs = map[parser.ItemType]binaryOperationFunc{
 	parser.POW:   math.Pow,
 	parser.ATAN2: math.Atan2,
 }
+
+var boolComparisonOperationFuncs = map[parser.ItemType]binaryOperationFunc{
+	parser.EQLC: func(left, right float64) float64 {
+		return boolToFloat(left == right)
+	},
+	parser.NEQ: func(left, right float64) float64 {
+		return boolToFloat(left != right)
+	},
+	parser.GTR: func(left, right float64) float64 {
+		return boolToFloat(left > right)
+	},
+	parser.LSS: func(left, right float64) float64 {
+		return boolToFloat(left < right)
+	},
+	parser.GTE: func(left, right float64) float64 {
+		return boolToFloat(left >= right)
+	},
+	parser.LTE: func(left, right float64) float64 {
+		return boolToFloat(left <= right)
+	},
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
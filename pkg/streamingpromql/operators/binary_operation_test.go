@@ -241,7 +241,30 @@ func TestBinaryOperation_SeriesMerging(t *testing.T) {
 				{Labels: labels.FromStrings("__name__", "right_side", "env", "test", "pod", "i")},
 				{Labels: labels.FromStrings("__name__", "right_side", "env", "test", "pod", "j")},
 			},
-			expectedError: `found duplicate series for the match group {env="test"} on the right side of the operation at timestamp 1970-01-01T00:00:00.002Z: {__name__="right_side", env="test", pod="g"} and {__name__="right_side", env="test", pod="j"}`,
+			expectedError: `found duplicate series for the match group {env="test"} on the right hand-side of the operation: [{__name__="right_side", env="test", pod="g"}, {__name__="right_side", env="test", pod="e"}];many-to-many matching not allowed: matching labels must be unique on one side`,
+		},
+		"input series with no points": {
+			input: []types.InstantVectorSeriesData{
+				{
+					Floats: []promql.FPoint{
+						{T: 1, F: 10},
+						{T: 2, F: 20},
+					},
+				},
+				{},
+				{
+					Floats: []promql.FPoint{
+						{T: 3, F: 30},
+					},
+				},
+			},
+			expectedOutput: types.InstantVectorSeriesData{
+				Floats: []promql.FPoint{
+					{T: 1, F: 10},
+					{T: 2, F: 20},
+					{T: 3, F: 30},
+				},
+			},
 		},
 	}
 
@@ -257,7 +280,15 @@ func TestBinaryOperation_SeriesMerging(t *testing.T) {
 				Pool: pooling.NewLimitingPool(0, nil),
 			}
 
-			result, err := o.mergeOneSide(testCase.input, testCase.sourceSeriesIndices, testCase.sourceSeriesMetadata, "right")
+			sourceSeriesIndices := testCase.sourceSeriesIndices
+			if sourceSeriesIndices == nil {
+				// Test case doesn't care about the source series, so just use the index of each input series.
+				for i := range testCase.input {
+					sourceSeriesIndices = append(sourceSeriesIndices, i)
+				}
+			}
+
+			result, err := o.mergeOneSide(testCase.input, sourceSeriesIndices, testCase.sourceSeriesMetadata, o.rightSideConflictError)
 
 			if testCase.expectedError == "" {
 				require.NoError(t, err)
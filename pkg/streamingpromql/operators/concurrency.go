@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package operators
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// ConcurrencyLimiter limits the number of goroutines used to evaluate a single query.
+//
+// Operators never block waiting for a goroutine: if none are available, they evaluate their inputs sequentially
+// in the calling goroutine instead.
+//
+// A nil ConcurrencyLimiter is valid, and never permits any additional goroutines.
+type ConcurrencyLimiter struct {
+	available atomic.Int64
+}
+
+// NewConcurrencyLimiter returns a ConcurrencyLimiter that permits at most maxConcurrency goroutines to evaluate a query,
+// including the goroutine that started evaluating it.
+//
+// It returns nil if maxConcurrency is less than 2, as this means no additional goroutines can be started.
+func NewConcurrencyLimiter(maxConcurrency int) *ConcurrencyLimiter {
+	if maxConcurrency < 2 {
+		return nil
+	}
+
+	l := &ConcurrencyLimiter{}
+	l.available.Store(int64(maxConcurrency - 1))
+
+	return l
+}
+
+// TryAcquire returns true if another goroutine can be started, in which case Release must be called once that
+// goroutine is finished.
+func (l *ConcurrencyLimiter) TryAcquire() bool {
+	if l == nil {
+		return false
+	}
+
+	for {
+		available := l.available.Load()
+		if available <= 0 {
+			return false
+		}
+
+		if l.available.CompareAndSwap(available, available-1) {
+			return true
+		}
+	}
+}
+
+func (l *ConcurrencyLimiter) Release() {
+	l.available.Add(1)
+}
+
+// loadSeriesMetadataForBothSides returns the series metadata of the left and right sides of a binary operation.
+//
+// If limiter permits, the right side is evaluated in another goroutine while the left side is evaluated in the calling goroutine.
+// Otherwise, the left side is evaluated first, and the right side is only evaluated once the left side is complete.
+//
+// If skipRightIfLeftEmpty is true and the left side has no series, rightMetadata is nil and any error from the right side
+// is ignored, regardless of whether the right side was evaluated concurrently.
+//
+// If either side returns an error, any metadata already loaded is returned to the pool, and the error from the left
+// side takes precedence, so that the same error is returned regardless of whether the sides were evaluated concurrently.
+func loadSeriesMetadataForBothSides(ctx context.Context, left, right types.InstantVectorOperator, skipRightIfLeftEmpty bool, limiter *ConcurrencyLimiter) (leftMetadata, rightMetadata []types.SeriesMetadata, err error) {
+	if !limiter.TryAcquire() {
+		leftMetadata, err = left.SeriesMetadata(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(leftMetadata) == 0 && skipRightIfLeftEmpty {
+			return leftMetadata, nil, nil
+		}
+
+		rightMetadata, err = right.SeriesMetadata(ctx)
+		if err != nil {
+			pooling.PutSeriesMetadataSlice(leftMetadata)
+			return nil, nil, err
+		}
+
+		return leftMetadata, rightMetadata, nil
+	}
+
+	var rightErr error
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer limiter.Release()
+		defer recoverPanic(&rightErr)
+
+		rightMetadata, rightErr = right.SeriesMetadata(ctx)
+	}()
+
+	leftMetadata, err = left.SeriesMetadata(ctx)
+	wg.Wait()
+
+	if err != nil {
+		pooling.PutSeriesMetadataSlice(leftMetadata)
+		pooling.PutSeriesMetadataSlice(rightMetadata)
+		return nil, nil, err
+	}
+
+	if len(leftMetadata) == 0 && skipRightIfLeftEmpty {
+		// Behave exactly as if the right side was never evaluated, as would be the case if we'd evaluated the sides sequentially.
+		// The right side itself is still closed when the binary operation is closed, just as it would be if it had not been evaluated,
+		// so we must not close it here.
+		pooling.PutSeriesMetadataSlice(rightMetadata)
+		return leftMetadata, nil, nil
+	}
+
+	if rightErr != nil {
+		pooling.PutSeriesMetadataSlice(leftMetadata)
+		pooling.PutSeriesMetadataSlice(rightMetadata)
+		return nil, nil, rightErr
+	}
+
+	return leftMetadata, rightMetadata, nil
+}
+
+// recoverPanic converts a panic in a goroutine started to evaluate part of a query into an error, so that the panic
+// fails only that query rather than crashing the whole process.
+//
+// It must be called directly with defer.
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("unexpected panic while evaluating query: %v", r)
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package operators
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	require.Nil(t, NewConcurrencyLimiter(0))
+	require.Nil(t, NewConcurrencyLimiter(1))
+
+	var nilLimiter *ConcurrencyLimiter
+	require.False(t, nilLimiter.TryAcquire())
+
+	limiter := NewConcurrencyLimiter(3)
+	require.True(t, limiter.TryAcquire())
+	require.True(t, limiter.TryAcquire())
+	require.False(t, limiter.TryAcquire(), "should not permit more than two additional goroutines")
+
+	limiter.Release()
+	require.True(t, limiter.TryAcquire())
+	require.False(t, limiter.TryAcquire())
+}
+
+func TestLoadSeriesMetadataForBothSides(t *testing.T) {
+	rightErr := errors.New("right side failed")
+	leftErr := errors.New("left side failed")
+
+	testCases := map[string]struct {
+		left                 types.InstantVectorOperator
+		right                types.InstantVectorOperator
+		skipRightIfLeftEmpty bool
+
+		expectedLeft  []types.SeriesMetadata
+		expectedRight []types.SeriesMetadata
+		expectedErr   error
+	}{
+		"both sides have series": {
+			left:          &testOperator{series: []labels.Labels{labels.FromStrings("side", "left")}},
+			right:         &testOperator{series: []labels.Labels{labels.FromStrings("side", "right")}},
+			expectedLeft:  []types.SeriesMetadata{{Labels: labels.FromStrings("side", "left")}},
+			expectedRight: []types.SeriesMetadata{{Labels: labels.FromStrings("side", "right")}},
+		},
+		"left side is empty and right side fails, skipping right side if left side is empty": {
+			left:                 &testOperator{},
+			right:                &failingMetadataOperator{err: rightErr},
+			skipRightIfLeftEmpty: true,
+			expectedLeft:         nil,
+			expectedRight:        nil,
+		},
+		"left side is empty and right side fails, not skipping right side": {
+			left:        &testOperator{},
+			right:       &failingMetadataOperator{err: rightErr},
+			expectedErr: rightErr,
+		},
+		"both sides fail": {
+			left:        &failingMetadataOperator{err: leftErr},
+			right:       &failingMetadataOperator{err: rightErr},
+			expectedErr: leftErr,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, maxConcurrency := range []int{1, 2} {
+				left, right, err := loadSeriesMetadataForBothSides(context.Background(), testCase.left, testCase.right, testCase.skipRightIfLeftEmpty, NewConcurrencyLimiter(maxConcurrency))
+
+				if testCase.expectedErr != nil {
+					require.Equal(t, testCase.expectedErr, err, "max concurrency: %v", maxConcurrency)
+					continue
+				}
+
+				require.NoError(t, err, "max concurrency: %v", maxConcurrency)
+				require.Equal(t, testCase.expectedLeft, left, "max concurrency: %v", maxConcurrency)
+				require.Equal(t, testCase.expectedRight, right, "max concurrency: %v", maxConcurrency)
+			}
+		})
+	}
+}
+
+type failingMetadataOperator struct {
+	err error
+}
+
+func (o *failingMetadataOperator) SeriesMetadata(_ context.Context) ([]types.SeriesMetadata, error) {
+	return nil, o.err
+}
+
+func (o *failingMetadataOperator) NextSeries(_ context.Context) (types.InstantVectorSeriesData, error) {
+	panic("NextSeries() not supported")
+}
+
+func (o *failingMetadataOperator) Close() {}
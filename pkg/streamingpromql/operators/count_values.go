@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// CountValues implements the count_values() aggregation.
+//
+// The output series of count_values depend on the values of the input series, so CountValues reads all input series
+// in SeriesMetadata, and retains only the count for each output series at each step.
+type CountValues struct {
+	Inner     types.InstantVectorOperator
+	Start     int64 // Milliseconds since Unix epoch
+	End       int64 // Milliseconds since Unix epoch
+	Interval  int64 // In milliseconds
+	Steps     int
+	Grouping  []string // Sorted. If Without is false, this includes LabelName.
+	Without   bool
+	LabelName string
+	Pool      *pooling.LimitingPool
+
+	// The count for each output series at each step, in the same order as the series returned by SeriesMetadata.
+	remainingSeriesCounts [][]float64
+}
+
+var _ types.InstantVectorOperator = &CountValues{}
+
+func NewCountValues(
+	inner types.InstantVectorOperator,
+	start time.Time,
+	end time.Time,
+	interval time.Duration,
+	grouping []string,
+	without bool,
+	labelName string,
+	pool *pooling.LimitingPool,
+) *CountValues {
+	s, e, i := timestamp.FromTime(start), timestamp.FromTime(end), interval.Milliseconds()
+
+	if !without {
+		grouping = append(slices.Clone(grouping), labelName)
+	}
+
+	slices.Sort(grouping)
+
+	return &CountValues{
+		Inner:     inner,
+		Start:     s,
+		End:       e,
+		Interval:  i,
+		Steps:     stepCount(s, e, i),
+		Grouping:  grouping,
+		Without:   without,
+		LabelName: labelName,
+		Pool:      pool,
+	}
+}
+
+func (c *CountValues) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	if !model.LabelName(c.LabelName).IsValid() {
+		return nil, fmt.Errorf("invalid label name %q", strconv.Quote(c.LabelName))
+	}
+
+	innerSeries, err := c.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer pooling.PutSeriesMetadataSlice(innerSeries)
+
+	outputSeriesIndices := map[string]int{}
+	outputSeries := pooling.GetSeriesMetadataSlice(len(innerSeries))
+	groupLabelsFunc := newGroupLabelsFunc(c.Grouping, c.Without)
+	lb := labels.NewBuilder(labels.EmptyLabels())
+
+	accumulate := func(seriesLabels labels.Labels, t int64, value string) error {
+		lb.Reset(seriesLabels)
+		lb.Set(c.LabelName, value)
+		groupLabels := groupLabelsFunc(lb.Labels())
+		key := groupLabels.String()
+
+		idx, exists := outputSeriesIndices[key]
+		if !exists {
+			counts, err := c.Pool.GetFloatSlice(c.Steps)
+			if err != nil {
+				return err
+			}
+
+			idx = len(outputSeries)
+			outputSeriesIndices[key] = idx
+			outputSeries = append(outputSeries, types.SeriesMetadata{Labels: groupLabels})
+			c.remainingSeriesCounts = append(c.remainingSeriesCounts, counts[:c.Steps])
+		}
+
+		c.remainingSeriesCounts[idx][(t-c.Start)/c.Interval]++
+		return nil
+	}
+
+	for _, series := range innerSeries {
+		d, err := c.Inner.NextSeries(ctx)
+		if err != nil {
+			if errors.Is(err, types.EOS) {
+				return nil, fmt.Errorf("exhausted series before all groups were completed: %w", err)
+			}
+
+			return nil, err
+		}
+
+		for _, p := range d.Floats {
+			if err := accumulate(series.Labels, p.T, strconv.FormatFloat(p.F, 'f', -1, 64)); err != nil {
+				c.Pool.PutInstantVectorSeriesData(d)
+				return nil, err
+			}
+		}
+
+		for _, p := range d.Histograms {
+			if err := accumulate(series.Labels, p.T, p.H.String()); err != nil {
+				c.Pool.PutInstantVectorSeriesData(d)
+				return nil, err
+			}
+		}
+
+		c.Pool.PutInstantVectorSeriesData(d)
+	}
+
+	return outputSeries, nil
+}
+
+func (c *CountValues) NextSeries(_ context.Context) (types.InstantVectorSeriesData, error) {
+	if len(c.remainingSeriesCounts) == 0 {
+		return types.InstantVectorSeriesData{}, types.EOS
+	}
+
+	counts := c.remainingSeriesCounts[0]
+	c.remainingSeriesCounts = c.remainingSeriesCounts[1:]
+	defer c.Pool.PutFloatSlice(counts)
+
+	pointCount := 0
+	for _, count := range counts {
+		if count > 0 {
+			pointCount++
+		}
+	}
+
+	floats, err := c.Pool.GetFPointSlice(pointCount)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	for i, count := range counts {
+		if count > 0 {
+			floats = append(floats, promql.FPoint{T: c.Start + int64(i)*c.Interval, F: count})
+		}
+	}
+
+	return types.InstantVectorSeriesData{Floats: floats}, nil
+}
+
+func (c *CountValues) Close() {
+	c.Inner.Close()
+
+	for _, counts := range c.remainingSeriesCounts {
+		c.Pool.PutFloatSlice(counts)
+	}
+
+	c.remainingSeriesCounts = nil
+}
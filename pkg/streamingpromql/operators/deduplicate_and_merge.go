@@ -0,0 +1,282 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// DeduplicateAndMerge merges series from Inner that have the same labels into a single series.
+//
+// This is required after operations that may produce multiple series with the same labels, such as functions or
+// binary operations that drop the metric name.
+//
+// If MergeSeriesWithDistinctTimestamps is true, series with the same labels are merged into a single output series
+// if they do not have points at the same time step, and an error is returned otherwise. This is consistent with how
+// Prometheus' engine evaluates functions over instant vectors and binary operations between a vector and a scalar.
+//
+// If MergeSeriesWithDistinctTimestamps is false, an error is returned if more than one series with the same labels has
+// any points at all. This is consistent with how Prometheus' engine evaluates functions over range vectors and unary
+// negation.
+type DeduplicateAndMerge struct {
+	Inner                             types.InstantVectorOperator
+	Pool                              *pooling.LimitingPool
+	MergeSeriesWithDistinctTimestamps bool
+
+	// If true, Inner did not return any series with the same labels, so we can return series from Inner unchanged.
+	passthrough bool
+
+	remainingInnerSeriesToGroup []*deduplicationGroup // One entry per series produced by Inner, value is the group for that series
+	remainingGroups             []*deduplicationGroup // One entry per group, in the order we want to return them
+}
+
+var _ types.InstantVectorOperator = &DeduplicateAndMerge{}
+
+type deduplicationGroup struct {
+	// The number of input series that belong to this group that we haven't yet seen.
+	remainingSeriesCount int
+
+	// The index of the last series that contributes to this group.
+	// Used to sort groups in the order that they'll be completed in.
+	lastSeriesIndex int
+
+	// The data for each series in this group that we've seen so far.
+	series []types.InstantVectorSeriesData
+}
+
+var errDuplicateLabelset = errors.New("vector cannot contain metrics with the same labelset")
+
+func (d *DeduplicateAndMerge) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	innerMetadata, err := d.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note that, like Prometheus' engine, this doesn't handle potential hash collisions between series.
+	groups := make(map[uint64]*deduplicationGroup, len(innerMetadata))
+	seriesToGroup := make([]*deduplicationGroup, 0, len(innerMetadata))
+	outputMetadata := pooling.GetSeriesMetadataSlice(len(innerMetadata))
+	outputGroups := make([]*deduplicationGroup, 0, len(innerMetadata))
+
+	for seriesIdx, series := range innerMetadata {
+		key := series.Labels.Hash()
+		g, exists := groups[key]
+
+		if !exists {
+			g = &deduplicationGroup{}
+			groups[key] = g
+			outputMetadata = append(outputMetadata, series)
+			outputGroups = append(outputGroups, g)
+		}
+
+		g.remainingSeriesCount++
+		g.lastSeriesIndex = seriesIdx
+		seriesToGroup = append(seriesToGroup, g)
+	}
+
+	if len(outputGroups) == len(innerMetadata) {
+		// Fast path: no duplicates, so there's nothing to do.
+		d.passthrough = true
+		pooling.PutSeriesMetadataSlice(outputMetadata)
+
+		return innerMetadata, nil
+	}
+
+	pooling.PutSeriesMetadataSlice(innerMetadata)
+
+	// Return groups in the order they'll be completed in, so that we buffer as few series as possible.
+	sort.Sort(deduplicationGroupSorter{outputMetadata, outputGroups})
+
+	d.remainingGroups = outputGroups
+	d.remainingInnerSeriesToGroup = seriesToGroup
+
+	return outputMetadata, nil
+}
+
+func (d *DeduplicateAndMerge) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	if d.passthrough {
+		return d.Inner.NextSeries(ctx)
+	}
+
+	if len(d.remainingGroups) == 0 {
+		return types.InstantVectorSeriesData{}, types.EOS
+	}
+
+	thisGroup := d.remainingGroups[0]
+	d.remainingGroups = d.remainingGroups[1:]
+
+	// Read inner series until this group is complete.
+	// Series that belong to other groups are buffered in those groups until we need them.
+	for thisGroup.remainingSeriesCount > 0 {
+		g := d.remainingInnerSeriesToGroup[0]
+		d.remainingInnerSeriesToGroup = d.remainingInnerSeriesToGroup[1:]
+
+		s, err := d.Inner.NextSeries(ctx)
+		if err != nil {
+			if errors.Is(err, types.EOS) {
+				return types.InstantVectorSeriesData{}, fmt.Errorf("exhausted series before all groups were completed: %w", err)
+			}
+
+			return types.InstantVectorSeriesData{}, err
+		}
+
+		g.series = append(g.series, s)
+		g.remainingSeriesCount--
+	}
+
+	series := thisGroup.series
+	thisGroup.series = nil
+
+	return d.merge(series)
+}
+
+// merge merges the data from all series with the same labels into a single series.
+func (d *DeduplicateAndMerge) merge(series []types.InstantVectorSeriesData) (types.InstantVectorSeriesData, error) {
+	if len(series) == 1 {
+		// Fast path: there's only one series, so there's nothing to merge.
+		return series[0], nil
+	}
+
+	// Remove any series with no points, as they can't contribute anything to the merged series.
+	nonEmptySeries := series[:0]
+	floatCount, histogramCount := 0, 0
+
+	for _, s := range series {
+		if len(s.Floats) == 0 && len(s.Histograms) == 0 {
+			d.Pool.PutInstantVectorSeriesData(s)
+			continue
+		}
+
+		nonEmptySeries = append(nonEmptySeries, s)
+		floatCount += len(s.Floats)
+		histogramCount += len(s.Histograms)
+	}
+
+	switch len(nonEmptySeries) {
+	case 0:
+		return types.InstantVectorSeriesData{}, nil
+	case 1:
+		return nonEmptySeries[0], nil
+	}
+
+	// We're going to create new slices, so return the source slices to the pool once we're done.
+	defer func() {
+		for _, s := range nonEmptySeries {
+			d.Pool.PutInstantVectorSeriesData(s)
+		}
+	}()
+
+	if !d.MergeSeriesWithDistinctTimestamps {
+		return types.InstantVectorSeriesData{}, errDuplicateLabelset
+	}
+
+	merged := types.InstantVectorSeriesData{}
+	var err error
+
+	if floatCount > 0 {
+		merged.Floats, err = d.Pool.GetFPointSlice(floatCount)
+		if err != nil {
+			return types.InstantVectorSeriesData{}, err
+		}
+	}
+
+	if histogramCount > 0 {
+		merged.Histograms, err = d.Pool.GetHPointSlice(histogramCount)
+		if err != nil {
+			d.Pool.PutFPointSlice(merged.Floats)
+			return types.InstantVectorSeriesData{}, err
+		}
+	}
+
+	for _, s := range nonEmptySeries {
+		merged.Floats = append(merged.Floats, s.Floats...)
+		merged.Histograms = append(merged.Histograms, s.Histograms...)
+	}
+
+	slices.SortFunc(merged.Floats, func(a, b promql.FPoint) int { return cmp.Compare(a.T, b.T) })
+	slices.SortFunc(merged.Histograms, func(a, b promql.HPoint) int { return cmp.Compare(a.T, b.T) })
+
+	if haveConflictingTimestamps(merged) {
+		d.Pool.PutInstantVectorSeriesData(merged)
+		return types.InstantVectorSeriesData{}, errDuplicateLabelset
+	}
+
+	return merged, nil
+}
+
+// haveConflictingTimestamps returns true if d has more than one point at the same timestamp.
+//
+// d.Floats and d.Histograms must be sorted in timestamp order.
+func haveConflictingTimestamps(d types.InstantVectorSeriesData) bool {
+	for i := 1; i < len(d.Floats); i++ {
+		if d.Floats[i].T == d.Floats[i-1].T {
+			return true
+		}
+	}
+
+	for i := 1; i < len(d.Histograms); i++ {
+		if d.Histograms[i].T == d.Histograms[i-1].T {
+			return true
+		}
+	}
+
+	nextHistogramIdx := 0
+
+	for _, p := range d.Floats {
+		for nextHistogramIdx < len(d.Histograms) && d.Histograms[nextHistogramIdx].T < p.T {
+			nextHistogramIdx++
+		}
+
+		if nextHistogramIdx < len(d.Histograms) && d.Histograms[nextHistogramIdx].T == p.T {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *DeduplicateAndMerge) Close() {
+	d.Inner.Close()
+
+	for _, g := range d.remainingGroups {
+		for _, s := range g.series {
+			d.Pool.PutInstantVectorSeriesData(s)
+		}
+
+		g.series = nil
+	}
+
+	d.remainingGroups = nil
+	d.remainingInnerSeriesToGroup = nil
+}
+
+type deduplicationGroupSorter struct {
+	metadata []types.SeriesMetadata
+	groups   []*deduplicationGroup
+}
+
+func (g deduplicationGroupSorter) Len() int {
+	return len(g.metadata)
+}
+
+func (g deduplicationGroupSorter) Less(i, j int) bool {
+	return g.groups[i].lastSeriesIndex < g.groups[j].lastSeriesIndex
+}
+
+func (g deduplicationGroupSorter) Swap(i, j int) {
+	g.metadata[i], g.metadata[j] = g.metadata[j], g.metadata[i]
+	g.groups[i], g.groups[j] = g.groups[j], g.groups[i]
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package operators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// Most of the functionality of DeduplicateAndMerge is tested through the test scripts in
+// pkg/streamingpromql/testdata.
+//
+// The output sorting behaviour is impossible to test through these scripts, so we instead test it here.
+func TestDeduplicateAndMerge(t *testing.T) {
+	testCases := map[string]struct {
+		inputSeries    []labels.Labels
+		inputData      []types.InstantVectorSeriesData
+		strict         bool
+		expectedSeries []labels.Labels
+		expectedData   []types.InstantVectorSeriesData
+		expectedError  error
+	}{
+		"no duplicates": {
+			inputSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+				labels.FromStrings("pod", "2"),
+			},
+			inputData: []types.InstantVectorSeriesData{
+				{Floats: []promql.FPoint{{T: 0, F: 1}}},
+				{Floats: []promql.FPoint{{T: 0, F: 2}}},
+			},
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+				labels.FromStrings("pod", "2"),
+			},
+			expectedData: []types.InstantVectorSeriesData{
+				{Floats: []promql.FPoint{{T: 0, F: 1}}},
+				{Floats: []promql.FPoint{{T: 0, F: 2}}},
+			},
+		},
+		"duplicates with distinct timestamps": {
+			inputSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+				labels.FromStrings("pod", "2"),
+				labels.FromStrings("pod", "1"),
+			},
+			inputData: []types.InstantVectorSeriesData{
+				{Floats: []promql.FPoint{{T: 10, F: 1}}},
+				{Floats: []promql.FPoint{{T: 0, F: 2}}},
+				{Floats: []promql.FPoint{{T: 0, F: 3}}},
+			},
+			expectedSeries: []labels.Labels{
+				// Should return the group that is completed first first.
+				labels.FromStrings("pod", "2"),
+				labels.FromStrings("pod", "1"),
+			},
+			expectedData: []types.InstantVectorSeriesData{
+				{Floats: []promql.FPoint{{T: 0, F: 2}}},
+				{Floats: []promql.FPoint{{T: 0, F: 3}, {T: 10, F: 1}}},
+			},
+		},
+		"duplicates with a float and a histogram at the same timestamp": {
+			inputSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+				labels.FromStrings("pod", "1"),
+			},
+			inputData: []types.InstantVectorSeriesData{
+				{Floats: []promql.FPoint{{T: 10, F: 1}}},
+				{Histograms: []promql.HPoint{{T: 10}}},
+			},
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+			},
+			expectedError: errDuplicateLabelset,
+		},
+		"duplicates with conflicting timestamps": {
+			inputSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+				labels.FromStrings("pod", "1"),
+			},
+			inputData: []types.InstantVectorSeriesData{
+				{Floats: []promql.FPoint{{T: 0, F: 1}, {T: 10, F: 1}}},
+				{Floats: []promql.FPoint{{T: 10, F: 2}}},
+			},
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+			},
+			expectedError: errDuplicateLabelset,
+		},
+		"duplicates with distinct timestamps in strict mode": {
+			inputSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+				labels.FromStrings("pod", "1"),
+			},
+			inputData: []types.InstantVectorSeriesData{
+				{Floats: []promql.FPoint{{T: 0, F: 1}}},
+				{Floats: []promql.FPoint{{T: 10, F: 2}}},
+			},
+			strict: true,
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+			},
+			expectedError: errDuplicateLabelset,
+		},
+		"duplicates where only one series has points in strict mode": {
+			inputSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+				labels.FromStrings("pod", "1"),
+			},
+			inputData: []types.InstantVectorSeriesData{
+				{},
+				{Floats: []promql.FPoint{{T: 10, F: 2}}},
+			},
+			strict: true,
+			expectedSeries: []labels.Labels{
+				labels.FromStrings("pod", "1"),
+			},
+			expectedData: []types.InstantVectorSeriesData{
+				{Floats: []promql.FPoint{{T: 10, F: 2}}},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			pool := pooling.NewLimitingPool(0, nil)
+			o := &DeduplicateAndMerge{
+				Inner:                             &testOperator{series: testCase.inputSeries, data: testCase.inputData},
+				Pool:                              pool,
+				MergeSeriesWithDistinctTimestamps: !testCase.strict,
+			}
+
+			metadata, err := o.SeriesMetadata(ctx)
+			require.NoError(t, err)
+			require.Equal(t, labelsToSeriesMetadata(testCase.expectedSeries), metadata)
+
+			if testCase.expectedError != nil {
+				_, err := o.NextSeries(ctx)
+				require.Equal(t, testCase.expectedError, err)
+				return
+			}
+
+			for _, expected := range testCase.expectedData {
+				d, err := o.NextSeries(ctx)
+				require.NoError(t, err)
+				require.Equal(t, expected, d)
+			}
+		})
+	}
+}
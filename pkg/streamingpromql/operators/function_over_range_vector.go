@@ -11,36 +11,50 @@ import (
 
 	"github.com/prometheus/prometheus/promql"
 
+	"github.com/grafana/mimir/pkg/streamingpromql/functions"
 	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
 	"github.com/grafana/mimir/pkg/streamingpromql/types"
 )
 
-// FunctionOverRangeVector performs a rate calculation over a range vector.
+// FunctionOverRangeVector performs a function over each series in a range vector.
 type FunctionOverRangeVector struct {
 	Inner types.RangeVectorOperator
 	Pool  *pooling.LimitingPool
 
+	// ScalarArg is the scalar argument to the function, if any (eg. the quantile for quantile_over_time).
+	ScalarArg types.ScalarOperator
+
+	MetadataFunc functions.SeriesMetadataFunction
+	StepFunc     functions.RangeVectorStepFunction
+
 	numSteps     int
 	rangeSeconds float64
-	buffer       *types.RingBuffer
+	scalarValues types.ScalarData
+
+	floatBuffer     *types.FPointRingBuffer
+	histogramBuffer *types.HPointRingBuffer
 }
 
 var _ types.InstantVectorOperator = &FunctionOverRangeVector{}
 
 func (m *FunctionOverRangeVector) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	if m.ScalarArg != nil {
+		var err error
+		m.scalarValues, err = m.ScalarArg.GetValues(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	metadata, err := m.Inner.SeriesMetadata(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	for i := range metadata {
-		metadata[i].Labels = metadata[i].Labels.DropMetricName()
-	}
-
 	m.numSteps = m.Inner.StepCount()
 	m.rangeSeconds = m.Inner.Range().Seconds()
 
-	return metadata, nil
+	return m.MetadataFunc(metadata, m.Pool)
 }
 
 func (m *FunctionOverRangeVector) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
@@ -48,108 +62,92 @@ func (m *FunctionOverRangeVector) NextSeries(ctx context.Context) (types.Instant
 		return types.InstantVectorSeriesData{}, err
 	}
 
-	if m.buffer == nil {
-		m.buffer = types.NewRingBuffer(m.Pool)
+	if m.floatBuffer == nil {
+		m.floatBuffer = types.NewFPointRingBuffer(m.Pool)
 	}
 
-	m.buffer.Reset()
-
-	floats, err := m.Pool.GetFPointSlice(m.numSteps) // TODO: only allocate this if we have any floats (once we support native histograms)
-	if err != nil {
-		return types.InstantVectorSeriesData{}, err
+	if m.histogramBuffer == nil {
+		m.histogramBuffer = types.NewHPointRingBuffer(m.Pool)
 	}
 
-	data := types.InstantVectorSeriesData{
-		Floats: floats,
-	}
+	m.floatBuffer.Reset()
+	m.histogramBuffer.Reset()
+
+	data := types.InstantVectorSeriesData{}
+	stepIndex := -1
 
 	for {
-		step, err := m.Inner.NextStepSamples(m.buffer)
+		step, err := m.Inner.NextStepSamples(m.floatBuffer, m.histogramBuffer)
 
 		// nolint:errorlint // errors.Is introduces a performance overhead, and NextStepSamples is guaranteed to return exactly EOS, never a wrapped error.
 		if err == types.EOS {
 			return data, nil
 		} else if err != nil {
+			m.Pool.PutInstantVectorSeriesData(data)
 			return types.InstantVectorSeriesData{}, err
 		}
 
-		head, tail := m.buffer.UnsafePoints(step.RangeEnd)
-		count := len(head) + len(tail)
+		stepIndex++
 
-		if count < 2 {
-			// Not enough points, skip.
+		if !m.floatBuffer.AnyAtOrBefore(step.RangeEnd) && !m.histogramBuffer.AnyAtOrBefore(step.RangeEnd) {
+			// No points in the range for this step, so there's no output for this step.
 			continue
 		}
 
-		firstPoint := m.buffer.First()
-		lastPoint, _ := m.buffer.LastAtOrBefore(step.RangeEnd) // We already know there is a point at or before this time, no need to check.
-		delta := lastPoint.F - firstPoint.F
-		previousValue := firstPoint.F
+		scalarArg := 0.0
+		if m.ScalarArg != nil {
+			scalarArg = m.scalarValues.Samples[stepIndex].F
+		}
 
-		accumulate := func(points []promql.FPoint) {
-			for _, p := range points {
-				if p.T > step.RangeEnd { // The buffer is already guaranteed to only contain points >= rangeStart.
-					return
-				}
+		f, hasFloat, h, err := m.StepFunc(step, m.rangeSeconds, m.floatBuffer, m.histogramBuffer, scalarArg, m.Pool)
+		if err != nil {
+			m.Pool.PutInstantVectorSeriesData(data)
+			return types.InstantVectorSeriesData{}, err
+		}
 
-				if p.F < previousValue {
-					// Counter reset.
-					delta += previousValue
+		if hasFloat {
+			if data.Floats == nil {
+				// Only get the float slice once we know we need it.
+				data.Floats, err = m.Pool.GetFPointSlice(m.numSteps)
+				if err != nil {
+					m.Pool.PutInstantVectorSeriesData(data)
+					return types.InstantVectorSeriesData{}, err
 				}
-
-				previousValue = p.F
 			}
-		}
 
-		accumulate(head)
-		accumulate(tail)
+			data.Floats = append(data.Floats, promql.FPoint{T: step.StepT, F: f})
+		}
 
-		val := m.calculateRate(step.RangeStart, step.RangeEnd, firstPoint, lastPoint, delta, count)
+		if h != nil {
+			if data.Histograms == nil {
+				// Only get the histogram slice once we know we need it.
+				data.Histograms, err = m.Pool.GetHPointSlice(m.numSteps)
+				if err != nil {
+					m.Pool.PutInstantVectorSeriesData(data)
+					return types.InstantVectorSeriesData{}, err
+				}
+			}
 
-		data.Floats = append(data.Floats, promql.FPoint{T: step.StepT, F: val})
+			data.Histograms = append(data.Histograms, promql.HPoint{T: step.StepT, H: h})
+		}
 	}
 }
 
-// This is based on extrapolatedRate from promql/functions.go.
-// https://github.com/prometheus/prometheus/pull/13725 has a good explanation of the intended behaviour here.
-func (m *FunctionOverRangeVector) calculateRate(rangeStart, rangeEnd int64, firstPoint, lastPoint promql.FPoint, delta float64, count int) float64 {
-	durationToStart := float64(firstPoint.T-rangeStart) / 1000
-	durationToEnd := float64(rangeEnd-lastPoint.T) / 1000
-
-	sampledInterval := float64(lastPoint.T-firstPoint.T) / 1000
-	averageDurationBetweenSamples := sampledInterval / float64(count-1)
-
-	extrapolationThreshold := averageDurationBetweenSamples * 1.1
-	extrapolateToInterval := sampledInterval
+func (m *FunctionOverRangeVector) Close() {
+	m.Inner.Close()
 
-	if durationToStart >= extrapolationThreshold {
-		durationToStart = averageDurationBetweenSamples / 2
+	if m.ScalarArg != nil {
+		m.ScalarArg.Close()
 	}
 
-	if delta > 0 && firstPoint.F >= 0 {
-		durationToZero := sampledInterval * (firstPoint.F / delta)
-		if durationToZero < durationToStart {
-			durationToStart = durationToZero
-		}
+	if m.floatBuffer != nil {
+		m.floatBuffer.Close()
 	}
 
-	extrapolateToInterval += durationToStart
-
-	if durationToEnd >= extrapolationThreshold {
-		durationToEnd = averageDurationBetweenSamples / 2
+	if m.histogramBuffer != nil {
+		m.histogramBuffer.Close()
 	}
 
-	extrapolateToInterval += durationToEnd
-
-	factor := extrapolateToInterval / sampledInterval
-	factor /= m.rangeSeconds
-	return delta * factor
-}
-
-func (m *FunctionOverRangeVector) Close() {
-	m.Inner.Close()
-
-	if m.buffer != nil {
-		m.buffer.Close()
-	}
+	m.Pool.PutFPointSlice(m.scalarValues.Samples)
+	m.scalarValues.Samples = nil
 }
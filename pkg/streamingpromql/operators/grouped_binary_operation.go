@@ -0,0 +1,754 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/compat"
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// GroupedBinaryOperation represents a binary operation between instant vectors with many-to-one or one-to-many matching,
+// such as "<expr> * on (instance) group_left (version) <expr>".
+//
+// Each series from the "many" side is matched with the series from the "one" side in the same match group.
+// Labels listed in the group_left or group_right clause are copied from the "one" side to the output series.
+type GroupedBinaryOperation struct {
+	Left       types.InstantVectorOperator
+	Right      types.InstantVectorOperator
+	Op         parser.ItemType
+	ReturnBool bool
+	Start      int64 // Milliseconds since Unix epoch
+	End        int64 // Milliseconds since Unix epoch
+	Interval   int64 // In milliseconds
+	Steps      int
+	Pool       *pooling.LimitingPool
+
+	VectorMatching     parser.VectorMatching
+	ConcurrencyLimiter *ConcurrencyLimiter
+
+	// We need to retain these so that NextSeries() can return an error message with the series labels when
+	// multiple series match on the "one" side.
+	leftMetadata  []types.SeriesMetadata
+	rightMetadata []types.SeriesMetadata
+
+	remainingSeries []*groupedBinaryOperationOutputSeries
+	manySideBuffer  *binaryOperationSeriesBuffer
+	oneSideBuffer   *binaryOperationSeriesBuffer
+
+	// Series from the "many" side that are required for later output series, and the number of output series
+	// that use each series from the "many" side.
+	manySideSeriesCache     map[int]types.InstantVectorSeriesData
+	manySideSeriesUseCounts []int
+}
+
+var _ types.InstantVectorOperator = &GroupedBinaryOperation{}
+
+// groupedBinaryOperationMatchGroup represents all the series from the "one" side in a single match group.
+type groupedBinaryOperationMatchGroup struct {
+	oneSideSeriesIndices []int
+
+	// Populated when the first output series that uses this match group is computed,
+	// and retained until the last output series that uses this match group is computed.
+	oneSideData []types.InstantVectorSeriesData
+
+	remainingOutputSeriesCount int
+}
+
+func (g *groupedBinaryOperationMatchGroup) latestOneSideSeries() int {
+	return g.oneSideSeriesIndices[len(g.oneSideSeriesIndices)-1]
+}
+
+type groupedBinaryOperationOutputSeries struct {
+	manySideSeriesIndices []int // Sorted in ascending order.
+	matchGroup            *groupedBinaryOperationMatchGroup
+
+	// The series from matchGroup that contribute to this output series, as indices into matchGroup.oneSideSeriesIndices.
+	// This may be a subset of the series in the match group if the group_left or group_right clause includes labels
+	// that differ between series in the match group.
+	oneSideSeriesIndicesInGroup []int
+}
+
+func (s *groupedBinaryOperationOutputSeries) latestManySideSeries() int {
+	return s.manySideSeriesIndices[len(s.manySideSeriesIndices)-1]
+}
+
+type groupedBinaryOperationOutputSeriesKey struct {
+	matchGroup *groupedBinaryOperationMatchGroup
+	labels     string
+}
+
+var errMultipleMatchesForGroupedMatching = errors.New("multiple matches for labels: grouping labels must ensure unique matches")
+
+func NewGroupedBinaryOperation(
+	left types.InstantVectorOperator,
+	right types.InstantVectorOperator,
+	vectorMatching parser.VectorMatching,
+	op parser.ItemType,
+	returnBool bool,
+	start time.Time,
+	end time.Time,
+	interval time.Duration,
+	pool *pooling.LimitingPool,
+	concurrencyLimiter *ConcurrencyLimiter,
+) (*GroupedBinaryOperation, error) {
+	if vectorMatching.Card != parser.CardManyToOne && vectorMatching.Card != parser.CardOneToMany {
+		return nil, fmt.Errorf("expected many-to-one or one-to-many matching, got %v", vectorMatching.Card)
+	}
+
+	if !op.IsComparisonOperator() && arithmeticOperationFuncs[op] == nil {
+		return nil, compat.NewNotSupportedError(fmt.Sprintf("binary expression with '%s'", op))
+	}
+
+	s, e, i := timestamp.FromTime(start), timestamp.FromTime(end), interval.Milliseconds()
+
+	return &GroupedBinaryOperation{
+		Left:           left,
+		Right:          right,
+		Op:             op,
+		ReturnBool:     returnBool,
+		Start:          s,
+		End:            e,
+		Interval:       i,
+		Steps:          stepCount(s, e, i),
+		Pool:           pool,
+		VectorMatching: vectorMatching,
+
+		ConcurrencyLimiter: concurrencyLimiter,
+	}, nil
+}
+
+func (g *GroupedBinaryOperation) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	var err error
+	g.leftMetadata, g.rightMetadata, err = loadSeriesMetadataForBothSides(ctx, g.Left, g.Right, true, g.ConcurrencyLimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(g.leftMetadata) == 0 {
+		// No series on left-hand side, we'll never have any output series.
+		return nil, nil
+	}
+
+	if len(g.rightMetadata) == 0 {
+		// No series on right-hand side, we'll never have any output series.
+		return nil, nil
+	}
+
+	manySideMetadata, oneSideMetadata := g.manySideMetadata(), g.oneSideMetadata()
+	groupKeyFunc := vectorMatchingGroupKeyFunc(g.VectorMatching)
+	matchGroups := map[string]*groupedBinaryOperationMatchGroup{}
+
+	for idx, s := range oneSideMetadata {
+		groupKey := groupKeyFunc(s.Labels)
+		group, exists := matchGroups[string(groupKey)] // Important: don't extract the string(...) call here - passing it directly allows us to avoid allocating it.
+
+		if !exists {
+			group = &groupedBinaryOperationMatchGroup{}
+			matchGroups[string(groupKey)] = group
+		}
+
+		group.oneSideSeriesIndices = append(group.oneSideSeriesIndices, idx)
+	}
+
+	outputSeriesMap := map[groupedBinaryOperationOutputSeriesKey]*groupedBinaryOperationOutputSeries{}
+	outputMetadata := pooling.GetSeriesMetadataSlice(len(manySideMetadata))
+	allSeries := make([]*groupedBinaryOperationOutputSeries, 0, len(manySideMetadata))
+	lb := labels.NewBuilder(labels.EmptyLabels())
+
+	for manySideIdx, s := range manySideMetadata {
+		group := matchGroups[string(groupKeyFunc(s.Labels))]
+		if group == nil {
+			// No matching series on the "one" side, so this series will never produce any output.
+			continue
+		}
+
+		for idxInGroup, oneSideIdx := range group.oneSideSeriesIndices {
+			outputLabels := g.outputLabels(lb, s.Labels, oneSideMetadata[oneSideIdx].Labels)
+			key := groupedBinaryOperationOutputSeriesKey{matchGroup: group, labels: outputLabels.String()}
+			series, exists := outputSeriesMap[key]
+
+			if !exists {
+				series = &groupedBinaryOperationOutputSeries{matchGroup: group}
+				outputSeriesMap[key] = series
+				outputMetadata = append(outputMetadata, types.SeriesMetadata{Labels: outputLabels})
+				allSeries = append(allSeries, series)
+				group.remainingOutputSeriesCount++
+			}
+
+			if len(series.manySideSeriesIndices) == 0 || series.latestManySideSeries() != manySideIdx {
+				series.manySideSeriesIndices = append(series.manySideSeriesIndices, manySideIdx)
+			}
+
+			if !slices.Contains(series.oneSideSeriesIndicesInGroup, idxInGroup) {
+				series.oneSideSeriesIndicesInGroup = append(series.oneSideSeriesIndicesInGroup, idxInGroup)
+			}
+		}
+	}
+
+	// Like Prometheus' engine, multiple series on the "one" side in the same match group at the same time step is an
+	// error, even if there are no series on the "many" side in that match group, so we need to check these match groups
+	// as well.
+	var unmatchedGroupsWithMultipleSeries []*groupedBinaryOperationMatchGroup
+
+	for _, group := range matchGroups {
+		if group.remainingOutputSeriesCount == 0 && len(group.oneSideSeriesIndices) > 1 {
+			unmatchedGroupsWithMultipleSeries = append(unmatchedGroupsWithMultipleSeries, group)
+		}
+	}
+
+	if len(allSeries) == 0 && len(unmatchedGroupsWithMultipleSeries) == 0 {
+		pooling.PutSeriesMetadataSlice(outputMetadata)
+		return nil, nil
+	}
+
+	manySideSeriesUsed, err := g.Pool.GetBoolSlice(len(manySideMetadata))
+	if err != nil {
+		return nil, err
+	}
+
+	oneSideSeriesUsed, err := g.Pool.GetBoolSlice(len(oneSideMetadata))
+	if err != nil {
+		return nil, err
+	}
+
+	manySideSeriesUsed = manySideSeriesUsed[:len(manySideMetadata)]
+	oneSideSeriesUsed = oneSideSeriesUsed[:len(oneSideMetadata)]
+	g.manySideSeriesUseCounts = make([]int, len(manySideMetadata))
+
+	for _, series := range allSeries {
+		for _, idx := range series.manySideSeriesIndices {
+			manySideSeriesUsed[idx] = true
+			g.manySideSeriesUseCounts[idx]++
+		}
+
+		for _, idx := range series.matchGroup.oneSideSeriesIndices {
+			oneSideSeriesUsed[idx] = true
+		}
+	}
+
+	for _, group := range unmatchedGroupsWithMultipleSeries {
+		for _, idx := range group.oneSideSeriesIndices {
+			oneSideSeriesUsed[idx] = true
+		}
+	}
+
+	// Sort the output series so that we read the "many" side in order, as series from the "many" side are generally
+	// used for only one output series. We'll need to buffer the "one" side regardless, as it is generally used for
+	// many output series.
+	sort.Sort(groupedBinaryOperationOutputSorter{outputMetadata, allSeries})
+	g.remainingSeries = allSeries
+
+	manySide, oneSide := g.Left, g.Right
+	if g.VectorMatching.Card == parser.CardOneToMany {
+		manySide, oneSide = g.Right, g.Left
+	}
+
+	g.manySideBuffer = newBinaryOperationSeriesBuffer(manySide, manySideSeriesUsed, g.Pool)
+	g.oneSideBuffer = newBinaryOperationSeriesBuffer(oneSide, oneSideSeriesUsed, g.Pool)
+	g.manySideSeriesCache = map[int]types.InstantVectorSeriesData{}
+
+	if err := g.checkUnmatchedGroups(ctx, unmatchedGroupsWithMultipleSeries); err != nil {
+		return nil, err
+	}
+
+	if len(allSeries) == 0 {
+		pooling.PutSeriesMetadataSlice(outputMetadata)
+		return nil, nil
+	}
+
+	return outputMetadata, nil
+}
+
+// checkUnmatchedGroups checks that the series from the "one" side in each of groups do not have points at the same
+// time step, for match groups with no matching series on the "many" side.
+//
+// This reads these series immediately, and so may cause other series from the "one" side to be buffered until they
+// are needed, but this should be rare: we expect most match groups on the "one" side to have matching series on
+// the "many" side, and to have only a single series.
+func (g *GroupedBinaryOperation) checkUnmatchedGroups(ctx context.Context, groups []*groupedBinaryOperationMatchGroup) error {
+	// Check groups in the order their series are read from the "one" side, so that we buffer as few series as possible.
+	slices.SortFunc(groups, func(a, b *groupedBinaryOperationMatchGroup) int {
+		return cmp.Compare(a.latestOneSideSeries(), b.latestOneSideSeries())
+	})
+
+	for _, group := range groups {
+		err := g.loadOneSide(ctx, group)
+		g.releaseOneSide(group)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *GroupedBinaryOperation) manySideMetadata() []types.SeriesMetadata {
+	if g.VectorMatching.Card == parser.CardOneToMany {
+		return g.rightMetadata
+	}
+
+	return g.leftMetadata
+}
+
+func (g *GroupedBinaryOperation) oneSideMetadata() []types.SeriesMetadata {
+	if g.VectorMatching.Card == parser.CardOneToMany {
+		return g.leftMetadata
+	}
+
+	return g.rightMetadata
+}
+
+func (g *GroupedBinaryOperation) oneSideName() string {
+	if g.VectorMatching.Card == parser.CardOneToMany {
+		return "left"
+	}
+
+	return "right"
+}
+
+// outputLabels returns the labels of the output series produced from the given series from the "many" and "one" sides.
+func (g *GroupedBinaryOperation) outputLabels(lb *labels.Builder, manySideLabels, oneSideLabels labels.Labels) labels.Labels {
+	lb.Reset(manySideLabels)
+
+	// Comparison operations without the 'bool' modifier retain the metric name from the "many" side.
+	if !g.Op.IsComparisonOperator() || g.ReturnBool {
+		lb.Del(labels.MetricName)
+	}
+
+	// Labels from the group_left or group_right clause are always taken from the "one" side.
+	for _, l := range g.VectorMatching.Include {
+		if v := oneSideLabels.Get(l); v != "" {
+			lb.Set(l, v)
+		} else {
+			lb.Del(l)
+		}
+	}
+
+	return lb.Labels()
+}
+
+type groupedBinaryOperationOutputSorter struct {
+	metadata []types.SeriesMetadata
+	series   []*groupedBinaryOperationOutputSeries
+}
+
+func (s groupedBinaryOperationOutputSorter) Len() int {
+	return len(s.metadata)
+}
+
+func (s groupedBinaryOperationOutputSorter) Swap(i, j int) {
+	s.metadata[i], s.metadata[j] = s.metadata[j], s.metadata[i]
+	s.series[i], s.series[j] = s.series[j], s.series[i]
+}
+
+func (s groupedBinaryOperationOutputSorter) Less(i, j int) bool {
+	iMany := s.series[i].latestManySideSeries()
+	jMany := s.series[j].latestManySideSeries()
+	if iMany != jMany {
+		return iMany < jMany
+	}
+
+	return s.series[i].matchGroup.latestOneSideSeries() < s.series[j].matchGroup.latestOneSideSeries()
+}
+
+func (g *GroupedBinaryOperation) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	if len(g.remainingSeries) == 0 {
+		return types.InstantVectorSeriesData{}, types.EOS
+	}
+
+	thisSeries := g.remainingSeries[0]
+	g.remainingSeries = g.remainingSeries[1:]
+
+	if err := g.loadOneSide(ctx, thisSeries.matchGroup); err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	oneSide, err := g.oneSideValues(thisSeries)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	defer oneSide.close(g.Pool)
+
+	output, err := newGroupedBinaryOperationStepValues(g.Steps, g.Pool)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	defer output.close(g.Pool)
+
+	for _, manySideIdx := range thisSeries.manySideSeriesIndices {
+		manySide, err := g.getManySideSeries(ctx, manySideIdx)
+		if err != nil {
+			return types.InstantVectorSeriesData{}, err
+		}
+
+		for _, p := range manySide.Floats {
+			if err := g.accumulatePoint(p.T, p.F, nil, oneSide, output); err != nil {
+				return types.InstantVectorSeriesData{}, err
+			}
+		}
+
+		for _, p := range manySide.Histograms {
+			if err := g.accumulatePoint(p.T, 0, p.H, oneSide, output); err != nil {
+				return types.InstantVectorSeriesData{}, err
+			}
+		}
+
+		g.releaseManySideSeries(manySideIdx, manySide)
+	}
+
+	thisSeries.matchGroup.remainingOutputSeriesCount--
+	if thisSeries.matchGroup.remainingOutputSeriesCount == 0 {
+		g.releaseOneSide(thisSeries.matchGroup)
+	}
+
+	return output.toSeriesData(g.Start, g.Interval, g.Pool)
+}
+
+func (g *GroupedBinaryOperation) accumulatePoint(t int64, manySideF float64, manySideH *histogram.FloatHistogram, oneSide, output *groupedBinaryOperationStepValues) error {
+	idx := (t - g.Start) / g.Interval
+
+	if !oneSide.present[idx] {
+		// No matching point on the "one" side at this time step.
+		return nil
+	}
+
+	oneSideF, oneSideH := oneSide.floats[idx], oneSide.histogramAt(idx)
+	var f float64
+	var h *histogram.FloatHistogram
+	var keep bool
+
+	if g.VectorMatching.Card == parser.CardOneToMany {
+		f, h, keep = vectorElementBinaryOperation(g.Op, oneSideF, manySideF, oneSideH, manySideH)
+	} else {
+		f, h, keep = vectorElementBinaryOperation(g.Op, manySideF, oneSideF, manySideH, oneSideH)
+	}
+
+	if g.ReturnBool {
+		h = nil
+
+		if keep {
+			f = 1
+		} else {
+			f = 0
+		}
+	} else if !keep {
+		// Comparison is false, so this point is not included in the output, and, like Prometheus' engine, it
+		// can't conflict with points from other series on the "many" side.
+		return nil
+	}
+
+	if output.present[idx] {
+		// Another series from the "many" side has already produced a point for this output series at this time step.
+		return errMultipleMatchesForGroupedMatching
+	}
+
+	return output.set(idx, f, h, g.Steps, g.Pool)
+}
+
+// loadOneSide reads all series from the "one" side for the given match group, if they haven't already been read,
+// and checks that there is at most one series with a point at each time step.
+func (g *GroupedBinaryOperation) loadOneSide(ctx context.Context, group *groupedBinaryOperationMatchGroup) error {
+	if group.oneSideData != nil {
+		return nil
+	}
+
+	data, err := g.oneSideBuffer.getSeries(ctx, group.oneSideSeriesIndices)
+	if err != nil {
+		return err
+	}
+
+	// getSeries reuses the returned slice, so we need to make a copy.
+	group.oneSideData = slices.Clone(data)
+
+	if len(group.oneSideData) == 1 {
+		// Fast path: only one series, no need to check for conflicts.
+		return nil
+	}
+
+	present, err := g.Pool.GetBoolSlice(g.Steps)
+	if err != nil {
+		return err
+	}
+
+	defer g.Pool.PutBoolSlice(present)
+	present = present[:g.Steps]
+	oneSideMetadata := g.oneSideMetadata()
+
+	checkForConflict := func(t int64, idxInGroup int) error {
+		idx := (t - g.Start) / g.Interval
+
+		if !present[idx] {
+			present[idx] = true
+			return nil
+		}
+
+		// Find the other series with a point at this time step, so we can include it in the error message.
+		for otherIdxInGroup, other := range group.oneSideData[:idxInGroup] {
+			if hasPointAt(other, t) {
+				first := oneSideMetadata[group.oneSideSeriesIndices[otherIdxInGroup]].Labels
+				second := oneSideMetadata[group.oneSideSeriesIndices[idxInGroup]].Labels
+
+				return newDuplicateSeriesOnOneSideError(first, second, g.VectorMatching, g.oneSideName())
+			}
+		}
+
+		// We should never get here.
+		return fmt.Errorf("found duplicate series on the %s side of the operation at timestamp %d, but could not find the conflicting series", g.oneSideName(), t)
+	}
+
+	for idxInGroup, d := range group.oneSideData {
+		for _, p := range d.Floats {
+			if err := checkForConflict(p.T, idxInGroup); err != nil {
+				return err
+			}
+		}
+
+		for _, p := range d.Histograms {
+			if err := checkForConflict(p.T, idxInGroup); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *GroupedBinaryOperation) releaseOneSide(group *groupedBinaryOperationMatchGroup) {
+	for _, d := range group.oneSideData {
+		g.Pool.PutInstantVectorSeriesData(d)
+	}
+
+	group.oneSideData = nil
+}
+
+// oneSideValues returns the values from the "one" side for the given output series at each time step.
+func (g *GroupedBinaryOperation) oneSideValues(series *groupedBinaryOperationOutputSeries) (*groupedBinaryOperationStepValues, error) {
+	values, err := newGroupedBinaryOperationStepValues(g.Steps, g.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, idxInGroup := range series.oneSideSeriesIndicesInGroup {
+		d := series.matchGroup.oneSideData[idxInGroup]
+
+		for _, p := range d.Floats {
+			if err := values.set((p.T-g.Start)/g.Interval, p.F, nil, g.Steps, g.Pool); err != nil {
+				values.close(g.Pool)
+				return nil, err
+			}
+		}
+
+		for _, p := range d.Histograms {
+			if err := values.set((p.T-g.Start)/g.Interval, 0, p.H, g.Steps, g.Pool); err != nil {
+				values.close(g.Pool)
+				return nil, err
+			}
+		}
+	}
+
+	return values, nil
+}
+
+func (g *GroupedBinaryOperation) getManySideSeries(ctx context.Context, idx int) (types.InstantVectorSeriesData, error) {
+	if d, cached := g.manySideSeriesCache[idx]; cached {
+		return d, nil
+	}
+
+	d, err := g.manySideBuffer.getSingleSeries(ctx, idx)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	if g.manySideSeriesUseCounts[idx] > 1 {
+		// We'll need this series again for a later output series, so hold onto it.
+		g.manySideSeriesCache[idx] = d
+	}
+
+	return d, nil
+}
+
+func (g *GroupedBinaryOperation) releaseManySideSeries(idx int, d types.InstantVectorSeriesData) {
+	g.manySideSeriesUseCounts[idx]--
+
+	if g.manySideSeriesUseCounts[idx] == 0 {
+		delete(g.manySideSeriesCache, idx)
+		g.Pool.PutInstantVectorSeriesData(d)
+	}
+}
+
+func (g *GroupedBinaryOperation) Close() {
+	g.Left.Close()
+	g.Right.Close()
+
+	if g.leftMetadata != nil {
+		pooling.PutSeriesMetadataSlice(g.leftMetadata)
+	}
+
+	if g.rightMetadata != nil {
+		pooling.PutSeriesMetadataSlice(g.rightMetadata)
+	}
+
+	if g.manySideBuffer != nil {
+		g.manySideBuffer.close()
+	}
+
+	if g.oneSideBuffer != nil {
+		g.oneSideBuffer.close()
+	}
+
+	for _, d := range g.manySideSeriesCache {
+		g.Pool.PutInstantVectorSeriesData(d)
+	}
+
+	for _, s := range g.remainingSeries {
+		if s.matchGroup.oneSideData != nil {
+			g.releaseOneSide(s.matchGroup)
+		}
+	}
+}
+
+// groupedBinaryOperationStepValues holds a value for each time step of a series.
+type groupedBinaryOperationStepValues struct {
+	floats     []float64
+	histograms []*histogram.FloatHistogram // Only populated if there is at least one histogram.
+	present    []bool
+}
+
+func newGroupedBinaryOperationStepValues(steps int, pool *pooling.LimitingPool) (*groupedBinaryOperationStepValues, error) {
+	floats, err := pool.GetFloatSlice(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	present, err := pool.GetBoolSlice(steps)
+	if err != nil {
+		pool.PutFloatSlice(floats)
+		return nil, err
+	}
+
+	return &groupedBinaryOperationStepValues{
+		floats:  floats[:steps],
+		present: present[:steps],
+	}, nil
+}
+
+func (v *groupedBinaryOperationStepValues) set(idx int64, f float64, h *histogram.FloatHistogram, steps int, pool *pooling.LimitingPool) error {
+	if h != nil && v.histograms == nil {
+		var err error
+		v.histograms, err = pool.GetHistogramPointerSlice(steps)
+		if err != nil {
+			return err
+		}
+
+		v.histograms = v.histograms[:steps]
+	}
+
+	v.present[idx] = true
+	v.floats[idx] = f
+
+	if v.histograms != nil {
+		v.histograms[idx] = h
+	}
+
+	return nil
+}
+
+func (v *groupedBinaryOperationStepValues) histogramAt(idx int64) *histogram.FloatHistogram {
+	if v.histograms == nil {
+		return nil
+	}
+
+	return v.histograms[idx]
+}
+
+func (v *groupedBinaryOperationStepValues) toSeriesData(start int64, interval int64, pool *pooling.LimitingPool) (types.InstantVectorSeriesData, error) {
+	floatCount, histogramCount := 0, 0
+
+	for idx, present := range v.present {
+		if !present {
+			continue
+		}
+
+		if v.histogramAt(int64(idx)) != nil {
+			histogramCount++
+		} else {
+			floatCount++
+		}
+	}
+
+	var err error
+	data := types.InstantVectorSeriesData{}
+
+	if floatCount > 0 {
+		data.Floats, err = pool.GetFPointSlice(floatCount)
+		if err != nil {
+			return types.InstantVectorSeriesData{}, err
+		}
+	}
+
+	if histogramCount > 0 {
+		data.Histograms, err = pool.GetHPointSlice(histogramCount)
+		if err != nil {
+			pool.PutFPointSlice(data.Floats)
+			return types.InstantVectorSeriesData{}, err
+		}
+	}
+
+	for idx, present := range v.present {
+		if !present {
+			continue
+		}
+
+		t := start + int64(idx)*interval
+
+		if h := v.histogramAt(int64(idx)); h != nil {
+			data.Histograms = append(data.Histograms, promql.HPoint{T: t, H: h})
+		} else {
+			data.Floats = append(data.Floats, promql.FPoint{T: t, F: v.floats[idx]})
+		}
+	}
+
+	return data, nil
+}
+
+func (v *groupedBinaryOperationStepValues) close(pool *pooling.LimitingPool) {
+	pool.PutFloatSlice(v.floats)
+	pool.PutBoolSlice(v.present)
+	pool.PutHistogramPointerSlice(v.histograms)
+}
+
+// hasPointAt returns true if d contains a float or histogram point with timestamp t.
+func hasPointAt(d types.InstantVectorSeriesData, t int64) bool {
+	if _, found := slices.BinarySearchFunc(d.Floats, t, func(p promql.FPoint, t int64) int {
+		return cmp.Compare(p.T, t)
+	}); found {
+		return true
+	}
+
+	_, found := slices.BinarySearchFunc(d.Histograms, t, func(p promql.HPoint, t int64) int {
+		return cmp.Compare(p.T, t)
+	})
+
+	return found
+}
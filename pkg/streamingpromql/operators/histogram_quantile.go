@@ -0,0 +1,389 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/functions.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/functions"
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// HistogramQuantileFunction performs the histogram_quantile function over both classic and native histograms.
+//
+// Classic histogram buckets are grouped by their labels excluding the le label, and the quantile is computed for
+// each group. The quantile is computed for each native histogram series individually.
+//
+// Series that produce the same output series (ie. that have the same labels once the metric name and, for classic
+// histogram buckets, the le label are removed) are evaluated together, so that we can detect classic and native
+// histograms with the same labels, and multiple histograms that produce the same output series at the same time.
+type HistogramQuantileFunction struct {
+	Phi      types.ScalarOperator
+	Inner    types.InstantVectorOperator
+	Start    int64 // Milliseconds since Unix epoch
+	End      int64 // Milliseconds since Unix epoch
+	Interval int64 // In milliseconds
+	Steps    int
+	Pool     *pooling.LimitingPool
+
+	phiValues                   types.ScalarData
+	remainingInnerSeriesToGroup []histogramQuantileSeries // One entry per series produced by Inner
+	remainingGroups             []*histogramQuantileGroup // One entry per group, in the order we want to return them
+}
+
+var _ types.InstantVectorOperator = &HistogramQuantileFunction{}
+
+// histogramQuantileGroup is a set of input series that all produce the same output series.
+type histogramQuantileGroup struct {
+	// The number of input series that belong to this group that we haven't yet seen.
+	remainingSeriesCount uint
+
+	// The index of the last series that contributes to this group.
+	// Used to sort groups in the order that they'll be completed in.
+	lastSeriesIndex int
+
+	classicHistograms []*classicHistogram
+	nativeHistograms  []nativeHistogram
+
+	// Classic histograms in this group, keyed by their metric name.
+	// All series in a group have the same labels other than the metric name and le, so this is used to detect
+	// native histograms with the same labels as a classic histogram.
+	classicHistogramsByName map[string]*classicHistogram
+}
+
+type classicHistogram struct {
+	seriesCount int
+	buckets     []types.HistogramBucket // seriesCount buckets for each step
+	bucketCount []int                   // Number of buckets present in buckets for each step
+
+	// The index of the last step at which this classic histogram conflicted with a native histogram with the same labels.
+	conflictedAtStep int
+}
+
+type nativeHistogram struct {
+	metricName string // Used to detect classic histograms with the same labels
+	histograms []promql.HPoint
+}
+
+type histogramQuantileSeries struct {
+	group      *histogramQuantileGroup
+	classic    *classicHistogram // nil if this series is not a classic histogram bucket
+	upperBound float64
+	metricName string // Only set if this series is not a classic histogram bucket
+}
+
+func NewHistogramQuantileFunction(
+	phi types.ScalarOperator,
+	inner types.InstantVectorOperator,
+	start time.Time,
+	end time.Time,
+	interval time.Duration,
+	pool *pooling.LimitingPool,
+) *HistogramQuantileFunction {
+	s, e, i := timestamp.FromTime(start), timestamp.FromTime(end), interval.Milliseconds()
+
+	return &HistogramQuantileFunction{
+		Phi:      phi,
+		Inner:    inner,
+		Start:    s,
+		End:      e,
+		Interval: i,
+		Steps:    stepCount(s, e, i),
+		Pool:     pool,
+	}
+}
+
+func (h *HistogramQuantileFunction) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	// We'll return the phi values slice to the pool in Close.
+	var err error
+	h.phiValues, err = h.Phi.GetValues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	innerSeries, err := h.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer pooling.PutSeriesMetadataSlice(innerSeries)
+
+	if len(innerSeries) == 0 {
+		// No input series == no output series.
+		return nil, nil
+	}
+
+	groups := map[string]*histogramQuantileGroup{}
+	groupLabels := map[*histogramQuantileGroup]labels.Labels{}
+	h.remainingInnerSeriesToGroup = make([]histogramQuantileSeries, 0, len(innerSeries))
+	lb := labels.NewBuilder(labels.EmptyLabels())
+	buf := make([]byte, 0, 1024)
+
+	// getGroup returns the group for series, creating it if it doesn't already exist.
+	// The group is identified by the series' labels excluding the labels in excludedLabels, which must be sorted.
+	getGroup := func(series labels.Labels, excludedLabels ...string) *histogramQuantileGroup {
+		buf = series.BytesWithoutLabels(buf, excludedLabels...)
+		g, exists := groups[string(buf)] // Important: don't extract the string(...) call here - passing it directly allows us to avoid allocating it.
+
+		if !exists {
+			g = &histogramQuantileGroup{classicHistogramsByName: map[string]*classicHistogram{}}
+			groups[string(buf)] = g
+
+			lb.Reset(series)
+			lb.Del(excludedLabels...)
+			groupLabels[g] = lb.Labels()
+		}
+
+		return g
+	}
+
+	for seriesIdx, series := range innerSeries {
+		var s histogramQuantileSeries
+		metricName := series.Labels.Get(labels.MetricName)
+		upperBound, err := strconv.ParseFloat(series.Labels.Get(labels.BucketLabel), 64)
+
+		if err == nil {
+			// This series is a classic histogram bucket. Group it with the other buckets with the same labels.
+			// Note that native histograms in series with a valid le label are ignored.
+			s.group = getGroup(series.Labels, labels.MetricName, labels.BucketLabel)
+			s.upperBound = upperBound
+
+			c, exists := s.group.classicHistogramsByName[metricName]
+
+			if !exists {
+				c = &classicHistogram{conflictedAtStep: -1}
+				s.group.classicHistogramsByName[metricName] = c
+				s.group.classicHistograms = append(s.group.classicHistograms, c)
+			}
+
+			c.seriesCount++
+			s.classic = c
+		} else {
+			// This series has no valid le label, so it can only contain native histograms.
+			// Any float samples in this series are ignored.
+			// If this series has no le label at all, then it will be in the same group as any classic histogram
+			// buckets with the same labels.
+			s.group = getGroup(series.Labels, labels.MetricName)
+			s.metricName = metricName
+		}
+
+		s.group.remainingSeriesCount++
+		s.group.lastSeriesIndex = seriesIdx
+		h.remainingInnerSeriesToGroup = append(h.remainingInnerSeriesToGroup, s)
+	}
+
+	seriesMetadata := pooling.GetSeriesMetadataSlice(len(groups))
+	h.remainingGroups = make([]*histogramQuantileGroup, 0, len(groups))
+
+	for _, g := range groups {
+		seriesMetadata = append(seriesMetadata, types.SeriesMetadata{Labels: groupLabels[g]})
+		h.remainingGroups = append(h.remainingGroups, g)
+	}
+
+	sort.Sort(histogramQuantileGroupSorter{seriesMetadata, h.remainingGroups})
+
+	return seriesMetadata, nil
+}
+
+func (h *HistogramQuantileFunction) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	if len(h.remainingGroups) == 0 {
+		// No more groups left.
+		return types.InstantVectorSeriesData{}, types.EOS
+	}
+
+	thisGroup := h.remainingGroups[0]
+	h.remainingGroups = h.remainingGroups[1:]
+
+	// Iterate through inner series until the desired group is complete
+	if err := h.accumulateUntilGroupComplete(ctx, thisGroup); err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	defer h.releaseGroup(thisGroup)
+
+	return h.computeOutputSeries(thisGroup)
+}
+
+// releaseGroup returns all slices held by g to the pool.
+func (h *HistogramQuantileFunction) releaseGroup(g *histogramQuantileGroup) {
+	for _, n := range g.nativeHistograms {
+		h.Pool.PutHPointSlice(n.histograms)
+	}
+
+	g.nativeHistograms = nil
+
+	for _, c := range g.classicHistograms {
+		h.Pool.PutHistogramBucketSlice(c.buckets)
+		h.Pool.PutIntSlice(c.bucketCount)
+		c.buckets = nil
+		c.bucketCount = nil
+	}
+}
+
+func (h *HistogramQuantileFunction) accumulateUntilGroupComplete(ctx context.Context, g *histogramQuantileGroup) error {
+	for g.remainingSeriesCount > 0 {
+		d, err := h.Inner.NextSeries(ctx)
+		if err != nil {
+			if errors.Is(err, types.EOS) {
+				return fmt.Errorf("exhausted series before all groups were completed: %w", err)
+			}
+
+			return err
+		}
+
+		thisSeries := h.remainingInnerSeriesToGroup[0]
+		h.remainingInnerSeriesToGroup = h.remainingInnerSeriesToGroup[1:]
+		thisSeries.group.remainingSeriesCount--
+
+		if thisSeries.classic == nil {
+			h.Pool.PutFPointSlice(d.Floats)
+
+			if len(d.Histograms) == 0 {
+				h.Pool.PutHPointSlice(d.Histograms)
+				continue
+			}
+
+			// We'll return the histograms slice to the pool once we've computed the output for this group.
+			thisSeries.group.nativeHistograms = append(thisSeries.group.nativeHistograms, nativeHistogram{metricName: thisSeries.metricName, histograms: d.Histograms})
+			continue
+		}
+
+		c := thisSeries.classic
+
+		if c.buckets == nil {
+			// We'll return these slices to the pool once we've computed the output for this group.
+			c.buckets, err = h.Pool.GetHistogramBucketSlice(h.Steps * c.seriesCount)
+			if err != nil {
+				h.Pool.PutInstantVectorSeriesData(d)
+				return err
+			}
+
+			c.bucketCount, err = h.Pool.GetIntSlice(h.Steps)
+			if err != nil {
+				h.Pool.PutInstantVectorSeriesData(d)
+				return err
+			}
+
+			c.buckets = c.buckets[:h.Steps*c.seriesCount]
+			c.bucketCount = c.bucketCount[:h.Steps]
+		}
+
+		for _, p := range d.Floats {
+			stepIdx := (p.T - h.Start) / h.Interval
+			c.buckets[int(stepIdx)*c.seriesCount+c.bucketCount[stepIdx]] = types.HistogramBucket{UpperBound: thisSeries.upperBound, Count: p.F}
+			c.bucketCount[stepIdx]++
+		}
+
+		h.Pool.PutInstantVectorSeriesData(d)
+	}
+
+	return nil
+}
+
+func (h *HistogramQuantileFunction) computeOutputSeries(g *histogramQuantileGroup) (types.InstantVectorSeriesData, error) {
+	var floats []promql.FPoint
+	nativeHistogramIndices := make([]int, len(g.nativeHistograms))
+
+	for stepIdx := 0; stepIdx < h.Steps; stepIdx++ {
+		t := h.Start + int64(stepIdx)*h.Interval
+		phi := h.phiValues.Samples[stepIdx].F
+		resultCount := 0
+		var result float64
+
+		for nativeIdx, n := range g.nativeHistograms {
+			pointIdx := nativeHistogramIndices[nativeIdx]
+
+			if pointIdx >= len(n.histograms) || n.histograms[pointIdx].T != t {
+				continue
+			}
+
+			nativeHistogramIndices[nativeIdx]++
+
+			if c, exists := g.classicHistogramsByName[n.metricName]; exists && c.bucketCount != nil && c.bucketCount[stepIdx] > 0 {
+				// At this step, we have classic histogram buckets and a native histogram with the same name and labels.
+				// Do not evaluate either.
+				c.conflictedAtStep = stepIdx
+				continue
+			}
+
+			resultCount++
+			result = functions.HistogramQuantile(phi, n.histograms[pointIdx].H)
+		}
+
+		for _, c := range g.classicHistograms {
+			if c.bucketCount == nil || c.bucketCount[stepIdx] == 0 || c.conflictedAtStep == stepIdx {
+				continue
+			}
+
+			resultCount++
+			firstBucketIdx := stepIdx * c.seriesCount
+			result = functions.BucketQuantile(phi, c.buckets[firstBucketIdx:firstBucketIdx+c.bucketCount[stepIdx]])
+		}
+
+		if resultCount == 0 {
+			continue
+		}
+
+		if resultCount > 1 {
+			h.Pool.PutFPointSlice(floats)
+			return types.InstantVectorSeriesData{}, errors.New("vector cannot contain metrics with the same labelset")
+		}
+
+		if floats == nil {
+			var err error
+			floats, err = h.Pool.GetFPointSlice(h.Steps)
+			if err != nil {
+				return types.InstantVectorSeriesData{}, err
+			}
+		}
+
+		floats = append(floats, promql.FPoint{T: t, F: result})
+	}
+
+	return types.InstantVectorSeriesData{Floats: floats}, nil
+}
+
+func (h *HistogramQuantileFunction) Close() {
+	h.Inner.Close()
+	h.Phi.Close()
+
+	h.Pool.PutFPointSlice(h.phiValues.Samples)
+	h.phiValues.Samples = nil
+
+	for _, g := range h.remainingGroups {
+		h.releaseGroup(g)
+	}
+
+	h.remainingGroups = nil
+}
+
+type histogramQuantileGroupSorter struct {
+	metadata []types.SeriesMetadata
+	groups   []*histogramQuantileGroup
+}
+
+func (g histogramQuantileGroupSorter) Len() int {
+	return len(g.metadata)
+}
+
+func (g histogramQuantileGroupSorter) Less(i, j int) bool {
+	return g.groups[i].lastSeriesIndex < g.groups[j].lastSeriesIndex
+}
+
+func (g histogramQuantileGroupSorter) Swap(i, j int) {
+	g.metadata[i], g.metadata[j] = g.metadata[j], g.metadata[i]
+	g.groups[i], g.groups[j] = g.groups[j], g.groups[i]
+}
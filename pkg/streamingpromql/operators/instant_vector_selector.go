@@ -63,6 +63,8 @@ func (v *InstantVectorSelector) NextSeries(ctx context.Context) (types.InstantVe
 			ts = *v.Selector.Timestamp
 		}
 
+		ts -= v.Selector.Offset
+
 		valueType := v.memoizedIterator.Seek(ts)
 
 		switch valueType {
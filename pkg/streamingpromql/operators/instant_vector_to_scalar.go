@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/functions.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// InstantVectorToScalar is an operator that implements the scalar() function.
+//
+// At each time step, it returns the value of the only series with a sample at that time step, or NaN if there
+// is not exactly one series with a sample at that time step.
+type InstantVectorToScalar struct {
+	Inner    types.InstantVectorOperator
+	Start    int64 // Milliseconds since Unix epoch
+	End      int64 // Milliseconds since Unix epoch
+	Interval int64 // In milliseconds
+	Pool     *pooling.LimitingPool
+}
+
+var _ types.ScalarOperator = &InstantVectorToScalar{}
+
+func NewInstantVectorToScalar(inner types.InstantVectorOperator, start time.Time, end time.Time, interval time.Duration, pool *pooling.LimitingPool) *InstantVectorToScalar {
+	return &InstantVectorToScalar{
+		Inner:    inner,
+		Start:    timestamp.FromTime(start),
+		End:      timestamp.FromTime(end),
+		Interval: interval.Milliseconds(),
+		Pool:     pool,
+	}
+}
+
+func (i *InstantVectorToScalar) GetValues(ctx context.Context) (types.ScalarData, error) {
+	seriesMetadata, err := i.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return types.ScalarData{}, err
+	}
+
+	defer pooling.PutSeriesMetadataSlice(seriesMetadata)
+
+	steps := stepCount(i.Start, i.End, i.Interval)
+	seenPoint, err := i.Pool.GetBoolSlice(steps)
+	if err != nil {
+		return types.ScalarData{}, err
+	}
+
+	defer i.Pool.PutBoolSlice(seenPoint)
+	seenPoint = seenPoint[:steps]
+
+	output, err := i.Pool.GetFPointSlice(steps)
+	if err != nil {
+		return types.ScalarData{}, err
+	}
+
+	for t := i.Start; t <= i.End; t += i.Interval {
+		output = append(output, promql.FPoint{T: t, F: math.NaN()})
+	}
+
+	for range seriesMetadata {
+		seriesData, err := i.Inner.NextSeries(ctx)
+		if err != nil {
+			if errors.Is(err, types.EOS) {
+				err = errors.New("inner operator returned fewer series than expected")
+			}
+
+			i.Pool.PutFPointSlice(output)
+			return types.ScalarData{}, err
+		}
+
+		for _, p := range seriesData.Floats {
+			i.accumulatePoint(p.T, p.F, output, seenPoint)
+		}
+
+		// scalar() uses the float value of histogram samples, which is always 0.
+		for _, p := range seriesData.Histograms {
+			i.accumulatePoint(p.T, 0, output, seenPoint)
+		}
+
+		i.Pool.PutInstantVectorSeriesData(seriesData)
+	}
+
+	return types.ScalarData{Samples: output}, nil
+}
+
+func (i *InstantVectorToScalar) accumulatePoint(t int64, f float64, output []promql.FPoint, seenPoint []bool) {
+	idx := (t - i.Start) / i.Interval
+
+	if seenPoint[idx] {
+		// We've already seen another point at this time step, so the result is NaN.
+		output[idx].F = math.NaN()
+		return
+	}
+
+	output[idx].F = f
+	seenPoint[idx] = true
+}
+
+func (i *InstantVectorToScalar) Close() {
+	i.Inner.Close()
+}
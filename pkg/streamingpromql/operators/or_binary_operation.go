@@ -0,0 +1,408 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// OrBinaryOperation represents a logical 'or' between two instant vectors.
+//
+// 'or' returns all points from the left side, as well as the points from the right side that have no matching point
+// on the left side at the same time step.
+//
+// All series from the left side are returned first, followed by the series from the right side. If a series on the
+// left side has exactly the same labels as a series on the right side, they're returned as a single output series
+// in the position of the right side series.
+type OrBinaryOperation struct {
+	Left               types.InstantVectorOperator
+	Right              types.InstantVectorOperator
+	VectorMatching     parser.VectorMatching
+	Start              int64 // Milliseconds since Unix epoch
+	End                int64 // Milliseconds since Unix epoch
+	Interval           int64 // In milliseconds
+	Steps              int
+	Pool               *pooling.LimitingPool
+	ConcurrencyLimiter *ConcurrencyLimiter
+
+	leftSeriesCount      int
+	nextLeftSeriesToRead int
+	leftSeriesGroups     []*orLeftSideGroup // Match group of each left series, or nil if no right series is in the same match group.
+
+	// Index of the right side series with the same labels as each left side series, or -1 if there is no such series.
+	leftSeriesPartners []int
+
+	// Left side series that will be returned together with a right side series with the same labels.
+	stashedLeftSeries map[int]types.InstantVectorSeriesData
+
+	remainingLeftOnlySeries []int
+	nextRightSeriesToRead   int
+	rightSeriesCount        int
+	rightSeriesGroups       []*orLeftSideGroup // Match group of each right series, or nil if no left series is in the same match group.
+	rightSeriesPartners     []int              // Index of the left side series with the same labels as each right side series, or -1.
+}
+
+var _ types.InstantVectorOperator = &OrBinaryOperation{}
+
+// orLeftSideGroup represents all the series on the left side that share a match group with at least one series on the right side.
+type orLeftSideGroup struct {
+	// Time steps at which at least one left side series in this group has a point.
+	presence []bool
+
+	// Number of right side series in this group that are yet to be returned.
+	remainingRightSeriesCount int
+}
+
+func NewOrBinaryOperation(
+	left types.InstantVectorOperator,
+	right types.InstantVectorOperator,
+	vectorMatching parser.VectorMatching,
+	start time.Time,
+	end time.Time,
+	interval time.Duration,
+	pool *pooling.LimitingPool,
+	concurrencyLimiter *ConcurrencyLimiter,
+) *OrBinaryOperation {
+	s, e, i := timestamp.FromTime(start), timestamp.FromTime(end), interval.Milliseconds()
+
+	return &OrBinaryOperation{
+		Left:           left,
+		Right:          right,
+		VectorMatching: vectorMatching,
+		Start:          s,
+		End:            e,
+		Interval:       i,
+		Steps:          stepCount(s, e, i),
+		Pool:           pool,
+
+		ConcurrencyLimiter: concurrencyLimiter,
+	}
+}
+
+func (o *OrBinaryOperation) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	leftMetadata, rightMetadata, err := loadSeriesMetadataForBothSides(ctx, o.Left, o.Right, false, o.ConcurrencyLimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	o.leftSeriesCount = len(leftMetadata)
+	o.rightSeriesCount = len(rightMetadata)
+
+	if len(leftMetadata) == 0 || len(rightMetadata) == 0 {
+		// One side is empty, so we can return all series from the other side unchanged.
+		o.remainingLeftOnlySeries = make([]int, len(leftMetadata))
+		for i := range leftMetadata {
+			o.remainingLeftOnlySeries[i] = i
+		}
+
+		if len(leftMetadata) == 0 {
+			pooling.PutSeriesMetadataSlice(leftMetadata)
+			return rightMetadata, nil
+		}
+
+		pooling.PutSeriesMetadataSlice(rightMetadata)
+		return leftMetadata, nil
+	}
+
+	defer pooling.PutSeriesMetadataSlice(leftMetadata)
+	defer pooling.PutSeriesMetadataSlice(rightMetadata)
+
+	o.computeGroups(leftMetadata, rightMetadata)
+	o.computePartners(leftMetadata, rightMetadata)
+
+	outputMetadata := pooling.GetSeriesMetadataSlice(len(leftMetadata) + len(rightMetadata))
+	o.remainingLeftOnlySeries = make([]int, 0, len(leftMetadata))
+	o.stashedLeftSeries = map[int]types.InstantVectorSeriesData{}
+
+	for idx, s := range leftMetadata {
+		if o.leftSeriesPartners[idx] == -1 {
+			o.remainingLeftOnlySeries = append(o.remainingLeftOnlySeries, idx)
+			outputMetadata = append(outputMetadata, s)
+		}
+	}
+
+	outputMetadata = append(outputMetadata, rightMetadata...)
+
+	return outputMetadata, nil
+}
+
+func (o *OrBinaryOperation) computeGroups(leftMetadata, rightMetadata []types.SeriesMetadata) {
+	groupKeyFunc := vectorMatchingGroupKeyFunc(o.VectorMatching)
+	groups := map[string]*orLeftSideGroup{}
+
+	for _, s := range rightMetadata {
+		groupKey := groupKeyFunc(s.Labels)
+
+		if _, exists := groups[string(groupKey)]; !exists { // Important: don't extract the string(...) call here - passing it directly allows us to avoid allocating it.
+			groups[string(groupKey)] = &orLeftSideGroup{}
+		}
+	}
+
+	// Only keep track of left side series in groups that also have series on the right side:
+	// we don't need to know where left side series are present if there are no right side series to filter.
+	o.leftSeriesGroups = make([]*orLeftSideGroup, len(leftMetadata))
+	groupsWithLeftSeries := make(map[*orLeftSideGroup]struct{}, len(groups))
+
+	for idx, s := range leftMetadata {
+		if group := groups[string(groupKeyFunc(s.Labels))]; group != nil {
+			o.leftSeriesGroups[idx] = group
+			groupsWithLeftSeries[group] = struct{}{}
+		}
+	}
+
+	o.rightSeriesGroups = make([]*orLeftSideGroup, len(rightMetadata))
+
+	for idx, s := range rightMetadata {
+		group := groups[string(groupKeyFunc(s.Labels))]
+
+		if _, hasLeftSeries := groupsWithLeftSeries[group]; hasLeftSeries {
+			o.rightSeriesGroups[idx] = group
+			group.remainingRightSeriesCount++
+		}
+	}
+}
+
+func (o *OrBinaryOperation) computePartners(leftMetadata, rightMetadata []types.SeriesMetadata) {
+	leftSeriesByLabels := make(map[string]int, len(leftMetadata))
+	buf := make([]byte, 0, 1024)
+
+	for idx, s := range leftMetadata {
+		buf = s.Labels.Bytes(buf)
+		leftSeriesByLabels[string(buf)] = idx
+	}
+
+	o.leftSeriesPartners = make([]int, len(leftMetadata))
+	o.rightSeriesPartners = make([]int, len(rightMetadata))
+
+	for idx := range o.leftSeriesPartners {
+		o.leftSeriesPartners[idx] = -1
+	}
+
+	for idx, s := range rightMetadata {
+		buf = s.Labels.Bytes(buf)
+
+		if leftIdx, exists := leftSeriesByLabels[string(buf)]; exists && o.leftSeriesPartners[leftIdx] == -1 {
+			o.leftSeriesPartners[leftIdx] = idx
+			o.rightSeriesPartners[idx] = leftIdx
+		} else {
+			o.rightSeriesPartners[idx] = -1
+		}
+	}
+}
+
+func (o *OrBinaryOperation) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	if len(o.remainingLeftOnlySeries) > 0 {
+		nextIdx := o.remainingLeftOnlySeries[0]
+		o.remainingLeftOnlySeries = o.remainingLeftOnlySeries[1:]
+
+		if err := o.readLeftSeriesUntil(ctx, nextIdx); err != nil {
+			return types.InstantVectorSeriesData{}, err
+		}
+
+		return o.nextLeftSeries(ctx)
+	}
+
+	if o.nextRightSeriesToRead >= o.rightSeriesCount {
+		return types.InstantVectorSeriesData{}, types.EOS
+	}
+
+	// Make sure we've seen every left side series before returning any right side series.
+	if err := o.readLeftSeriesUntil(ctx, o.leftSeriesCount); err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	rightIdx := o.nextRightSeriesToRead
+	o.nextRightSeriesToRead++
+
+	rightData, err := o.Right.NextSeries(ctx)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	if o.rightSeriesGroups == nil {
+		// Left side is empty, return the right side unchanged.
+		return rightData, nil
+	}
+
+	if group := o.rightSeriesGroups[rightIdx]; group != nil {
+		rightData = o.filterRightSeries(rightData, group.presence)
+
+		group.remainingRightSeriesCount--
+		if group.remainingRightSeriesCount == 0 {
+			o.Pool.PutBoolSlice(group.presence)
+			group.presence = nil
+		}
+	}
+
+	leftIdx := o.rightSeriesPartners[rightIdx]
+	if leftIdx == -1 {
+		return rightData, nil
+	}
+
+	leftData := o.stashedLeftSeries[leftIdx]
+	delete(o.stashedLeftSeries, leftIdx)
+
+	return o.mergeSeries(leftData, rightData)
+}
+
+// readLeftSeriesUntil reads left side series up to, but not including, the series with index idx, stashing any series
+// that will be returned later.
+func (o *OrBinaryOperation) readLeftSeriesUntil(ctx context.Context, idx int) error {
+	for o.nextLeftSeriesToRead < idx {
+		leftIdx := o.nextLeftSeriesToRead
+		d, err := o.nextLeftSeries(ctx)
+		if err != nil {
+			return err
+		}
+
+		// If we're reading a left side series without returning it immediately, then it must have a partner on the right side.
+		o.stashedLeftSeries[leftIdx] = d
+	}
+
+	return nil
+}
+
+// nextLeftSeries reads the next left side series and records the time steps at which it has points.
+func (o *OrBinaryOperation) nextLeftSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	idx := o.nextLeftSeriesToRead
+	o.nextLeftSeriesToRead++
+
+	d, err := o.Left.NextSeries(ctx)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	if o.leftSeriesGroups == nil {
+		// Right side is empty, nothing to record.
+		return d, nil
+	}
+
+	group := o.leftSeriesGroups[idx]
+	if group == nil {
+		return d, nil
+	}
+
+	if group.presence == nil {
+		group.presence, err = o.Pool.GetBoolSlice(o.Steps)
+		if err != nil {
+			return types.InstantVectorSeriesData{}, err
+		}
+
+		group.presence = group.presence[:o.Steps]
+	}
+
+	for _, p := range d.Floats {
+		group.presence[(p.T-o.Start)/o.Interval] = true
+	}
+
+	for _, p := range d.Histograms {
+		group.presence[(p.T-o.Start)/o.Interval] = true
+	}
+
+	return d, nil
+}
+
+// filterRightSeries removes points from d at time steps where there is a point on the left side, in place.
+func (o *OrBinaryOperation) filterRightSeries(d types.InstantVectorSeriesData, leftPresence []bool) types.InstantVectorSeriesData {
+	filteredFloats := d.Floats[:0]
+
+	for _, p := range d.Floats {
+		if !leftPresence[(p.T-o.Start)/o.Interval] {
+			filteredFloats = append(filteredFloats, p)
+		}
+	}
+
+	filteredHistograms := d.Histograms[:0]
+
+	for _, p := range d.Histograms {
+		if !leftPresence[(p.T-o.Start)/o.Interval] {
+			filteredHistograms = append(filteredHistograms, p)
+		}
+	}
+
+	return types.InstantVectorSeriesData{Floats: filteredFloats, Histograms: filteredHistograms}
+}
+
+// mergeSeries merges the points from a left side series and a right side series with the same labels.
+// The right side series must already have been filtered with filterRightSeries, so the two series will never both
+// have a point at the same time step.
+func (o *OrBinaryOperation) mergeSeries(left, right types.InstantVectorSeriesData) (types.InstantVectorSeriesData, error) {
+	floats, err := mergeSortedPoints(left.Floats, right.Floats, o.Pool.GetFPointSlice, o.Pool.PutFPointSlice, func(p promql.FPoint) int64 { return p.T })
+	if err != nil {
+		o.Pool.PutHPointSlice(left.Histograms)
+		o.Pool.PutHPointSlice(right.Histograms)
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	histograms, err := mergeSortedPoints(left.Histograms, right.Histograms, o.Pool.GetHPointSlice, o.Pool.PutHPointSlice, func(p promql.HPoint) int64 { return p.T })
+	if err != nil {
+		o.Pool.PutFPointSlice(floats)
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	return types.InstantVectorSeriesData{Floats: floats, Histograms: histograms}, nil
+}
+
+// mergeSortedPoints merges two slices of points sorted by timestamp, returning a single slice sorted by timestamp.
+// a and b are returned to the pool, unless one of them is returned as the result.
+func mergeSortedPoints[P any](a, b []P, get func(int) ([]P, error), put func([]P), timestamp func(P) int64) ([]P, error) {
+	if len(a) == 0 {
+		put(a)
+		return b, nil
+	}
+
+	if len(b) == 0 {
+		put(b)
+		return a, nil
+	}
+
+	defer put(a)
+	defer put(b)
+
+	output, err := get(len(a) + len(b))
+	if err != nil {
+		return nil, err
+	}
+
+	for len(a) > 0 && len(b) > 0 {
+		if timestamp(a[0]) < timestamp(b[0]) {
+			output = append(output, a[0])
+			a = a[1:]
+		} else {
+			output = append(output, b[0])
+			b = b[1:]
+		}
+	}
+
+	output = append(output, a...)
+	output = append(output, b...)
+
+	return output, nil
+}
+
+func (o *OrBinaryOperation) Close() {
+	o.Left.Close()
+	o.Right.Close()
+
+	for _, d := range o.stashedLeftSeries {
+		o.Pool.PutInstantVectorSeriesData(d)
+	}
+
+	for _, group := range o.rightSeriesGroups {
+		if group != nil && group.presence != nil {
+			o.Pool.PutBoolSlice(group.presence)
+			group.presence = nil
+		}
+	}
+}
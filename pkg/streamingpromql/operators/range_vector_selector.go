@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/tsdb/chunkenc"
@@ -56,7 +57,7 @@ func (m *RangeVectorSelector) NextSeries(ctx context.Context) error {
 	return nil
 }
 
-func (m *RangeVectorSelector) NextStepSamples(floats *types.RingBuffer) (types.RangeVectorStepData, error) {
+func (m *RangeVectorSelector) NextStepSamples(floats *types.FPointRingBuffer, histograms *types.HPointRingBuffer) (types.RangeVectorStepData, error) {
 	if m.nextT > m.Selector.End {
 		return types.RangeVectorStepData{}, types.EOS
 	}
@@ -68,10 +69,12 @@ func (m *RangeVectorSelector) NextStepSamples(floats *types.RingBuffer) (types.R
 		rangeEnd = *m.Selector.Timestamp
 	}
 
+	rangeEnd -= m.Selector.Offset
 	rangeStart := rangeEnd - m.rangeMilliseconds
 	floats.DiscardPointsBefore(rangeStart)
+	histograms.DiscardPointsBefore(rangeStart)
 
-	if err := m.fillBuffer(floats, rangeStart, rangeEnd); err != nil {
+	if err := m.fillBuffer(floats, histograms, rangeStart, rangeEnd); err != nil {
 		return types.RangeVectorStepData{}, err
 	}
 
@@ -84,7 +87,7 @@ func (m *RangeVectorSelector) NextStepSamples(floats *types.RingBuffer) (types.R
 	}, nil
 }
 
-func (m *RangeVectorSelector) fillBuffer(floats *types.RingBuffer, rangeStart, rangeEnd int64) error {
+func (m *RangeVectorSelector) fillBuffer(floats *types.FPointRingBuffer, histograms *types.HPointRingBuffer, rangeStart, rangeEnd int64) error {
 	// Keep filling the buffer until we reach the end of the range or the end of the iterator.
 	for {
 		valueType := m.chunkIterator.Next()
@@ -100,17 +103,37 @@ func (m *RangeVectorSelector) fillBuffer(floats *types.RingBuffer, rangeStart, r
 			}
 
 			// We might append a sample beyond the range end, but this is OK:
-			// - callers of NextStepSamples are expected to pass the same RingBuffer to subsequent calls, so the point is not lost
+			// - callers of NextStepSamples are expected to pass the same ring buffers to subsequent calls, so the point is not lost
 			// - callers of NextStepSamples are expected to handle the case where the buffer contains points beyond the end of the range
 			if err := floats.Append(promql.FPoint{T: t, F: f}); err != nil {
 				return err
 			}
 
+			if t >= rangeEnd {
+				return nil
+			}
+		case chunkenc.ValHistogram, chunkenc.ValFloatHistogram:
+			t := m.chunkIterator.AtT()
+			if t < rangeStart {
+				continue
+			}
+
+			// Points in the buffer may be used for multiple time steps, and returned by functions like last_over_time,
+			// so we must read each histogram into a new FloatHistogram rather than reusing an existing one.
+			_, h := m.chunkIterator.AtFloatHistogram(&histogram.FloatHistogram{})
+			if value.IsStaleNaN(h.Sum) {
+				continue
+			}
+
+			// As above, we might append a sample beyond the range end, but this is OK.
+			if err := histograms.Append(promql.HPoint{T: t, H: h}); err != nil {
+				return err
+			}
+
 			if t >= rangeEnd {
 				return nil
 			}
 		default:
-			// TODO: handle native histograms
 			return fmt.Errorf("unknown value type %s", valueType.String())
 		}
 	}
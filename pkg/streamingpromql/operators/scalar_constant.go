@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package operators
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// ScalarConstant is an operator that returns the same value at every time step, such as a number literal.
+type ScalarConstant struct {
+	Value    float64
+	Start    int64 // Milliseconds since Unix epoch
+	End      int64 // Milliseconds since Unix epoch
+	Interval int64 // In milliseconds
+	Pool     *pooling.LimitingPool
+}
+
+var _ types.ScalarOperator = &ScalarConstant{}
+
+func NewScalarConstant(value float64, start time.Time, end time.Time, interval time.Duration, pool *pooling.LimitingPool) *ScalarConstant {
+	return &ScalarConstant{
+		Value:    value,
+		Start:    timestamp.FromTime(start),
+		End:      timestamp.FromTime(end),
+		Interval: interval.Milliseconds(),
+		Pool:     pool,
+	}
+}
+
+func (s *ScalarConstant) GetValues(_ context.Context) (types.ScalarData, error) {
+	samples, err := s.Pool.GetFPointSlice(stepCount(s.Start, s.End, s.Interval))
+	if err != nil {
+		return types.ScalarData{}, err
+	}
+
+	for t := s.Start; t <= s.End; t += s.Interval {
+		samples = append(samples, promql.FPoint{T: t, F: s.Value})
+	}
+
+	return types.ScalarData{Samples: samples}, nil
+}
+
+func (s *ScalarConstant) Close() {
+	// Nothing to do.
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/compat"
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// ScalarScalarBinaryOperation represents a binary operation between two scalars such as "1 + 2" or "time() > bool 1000".
+type ScalarScalarBinaryOperation struct {
+	Left  types.ScalarOperator
+	Right types.ScalarOperator
+	Op    parser.ItemType
+	Pool  *pooling.LimitingPool
+
+	opFunc binaryOperationFunc
+}
+
+var _ types.ScalarOperator = &ScalarScalarBinaryOperation{}
+
+func NewScalarScalarBinaryOperation(left, right types.ScalarOperator, op parser.ItemType, pool *pooling.LimitingPool) (*ScalarScalarBinaryOperation, error) {
+	opFunc := arithmeticOperationFuncs[op]
+	if opFunc == nil {
+		// Comparisons between scalars must always use the 'bool' modifier, so we don't need to handle the filtering case here.
+		opFunc = boolComparisonOperationFuncs[op]
+	}
+
+	if opFunc == nil {
+		return nil, compat.NewNotSupportedError(fmt.Sprintf("binary expression with '%s'", op))
+	}
+
+	return &ScalarScalarBinaryOperation{
+		Left:  left,
+		Right: right,
+		Op:    op,
+		Pool:  pool,
+
+		opFunc: opFunc,
+	}, nil
+}
+
+func (s *ScalarScalarBinaryOperation) GetValues(ctx context.Context) (types.ScalarData, error) {
+	leftValues, err := s.Left.GetValues(ctx)
+	if err != nil {
+		return types.ScalarData{}, err
+	}
+
+	rightValues, err := s.Right.GetValues(ctx)
+	if err != nil {
+		s.Pool.PutFPointSlice(leftValues.Samples)
+		return types.ScalarData{}, err
+	}
+
+	// Both scalars will have a value at every time step, so we can compute the result in place in the left slice.
+	for i, p := range leftValues.Samples {
+		leftValues.Samples[i].F = s.opFunc(p.F, rightValues.Samples[i].F)
+	}
+
+	s.Pool.PutFPointSlice(rightValues.Samples)
+
+	return leftValues, nil
+}
+
+func (s *ScalarScalarBinaryOperation) Close() {
+	s.Left.Close()
+	s.Right.Close()
+}
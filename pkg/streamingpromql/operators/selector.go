@@ -21,6 +21,7 @@ type Selector struct {
 	Start     int64  // Milliseconds since Unix epoch
 	End       int64  // Milliseconds since Unix epoch
 	Timestamp *int64 // Milliseconds since Unix epoch, only set if selector uses @ modifier (eg. metric{...} @ 123)
+	Offset    int64  // In milliseconds
 	Interval  int64  // In milliseconds
 	Matchers  []*labels.Matcher
 
@@ -54,7 +55,8 @@ func (s *Selector) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata,
 	}
 
 	rangeMilliseconds := s.Range.Milliseconds()
-	start := startTimestamp - s.LookbackDelta.Milliseconds() - rangeMilliseconds
+	start := startTimestamp - s.LookbackDelta.Milliseconds() - rangeMilliseconds - s.Offset
+	endTimestamp -= s.Offset
 
 	hints := &storage.SelectHints{
 		Start: start,
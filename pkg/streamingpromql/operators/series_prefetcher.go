@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package operators
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// SeriesPrefetcher evaluates series from Inner in another goroutine, ahead of when they are requested, so that
+// Inner can be evaluated concurrently with the operator consuming its series.
+//
+// Series are always returned in the same order as Inner returns them, and SeriesPrefetcher never requests more
+// series from Inner than Inner returned from SeriesMetadata.
+//
+// If ConcurrencyLimiter does not permit another goroutine when the first series is requested, SeriesPrefetcher
+// evaluates Inner in the calling goroutine, exactly as if Inner was used directly.
+type SeriesPrefetcher struct {
+	Inner              types.InstantVectorOperator
+	Pool               *pooling.LimitingPool
+	ConcurrencyLimiter *ConcurrencyLimiter
+
+	// Maximum number of series to evaluate ahead of the series most recently returned.
+	BufferSize int
+
+	seriesCount    int
+	seriesReturned int
+
+	started     bool
+	prefetching bool
+	results     chan prefetchedSeries
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+var _ types.InstantVectorOperator = &SeriesPrefetcher{}
+
+type prefetchedSeries struct {
+	data types.InstantVectorSeriesData
+	err  error
+}
+
+func (p *SeriesPrefetcher) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	metadata, err := p.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.seriesCount = len(metadata)
+
+	return metadata, nil
+}
+
+func (p *SeriesPrefetcher) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	if p.seriesReturned >= p.seriesCount {
+		return types.InstantVectorSeriesData{}, types.EOS
+	}
+
+	if !p.started {
+		p.started = true
+
+		if p.ConcurrencyLimiter.TryAcquire() {
+			p.prefetching = true
+			p.results = make(chan prefetchedSeries, p.BufferSize)
+			p.stop = make(chan struct{})
+			p.done = make(chan struct{})
+
+			go p.prefetch(ctx)
+		}
+	}
+
+	if !p.prefetching {
+		d, err := p.Inner.NextSeries(ctx)
+		if err == nil {
+			p.seriesReturned++
+		}
+
+		return d, err
+	}
+
+	select {
+	case r, ok := <-p.results:
+		if !ok {
+			// prefetch() has already returned an error, and the caller didn't stop.
+			return types.InstantVectorSeriesData{}, errors.New("series prefetcher has already returned an error")
+		}
+
+		if r.err == nil {
+			p.seriesReturned++
+		}
+
+		return r.data, r.err
+	case <-ctx.Done():
+		return types.InstantVectorSeriesData{}, context.Cause(ctx)
+	}
+}
+
+func (p *SeriesPrefetcher) prefetch(ctx context.Context) {
+	defer close(p.done)
+	defer close(p.results)
+	defer p.ConcurrencyLimiter.Release()
+
+	for i := 0; i < p.seriesCount; i++ {
+		data, err := p.nextSeriesFromInner(ctx)
+
+		select {
+		case p.results <- prefetchedSeries{data: data, err: err}:
+		case <-p.stop:
+			p.Pool.PutInstantVectorSeriesData(data)
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (p *SeriesPrefetcher) nextSeriesFromInner(ctx context.Context) (data types.InstantVectorSeriesData, err error) {
+	defer recoverPanic(&err)
+
+	return p.Inner.NextSeries(ctx)
+}
+
+func (p *SeriesPrefetcher) Close() {
+	if p.prefetching {
+		// Wait for the prefetching goroutine to stop before closing Inner, as Inner is not safe to use from multiple goroutines.
+		close(p.stop)
+		<-p.done
+
+		for r := range p.results {
+			p.Pool.PutInstantVectorSeriesData(r.data)
+		}
+
+		p.prefetching = false
+	}
+
+	p.Inner.Close()
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package operators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/promqltest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+func TestSeriesPrefetcher(t *testing.T) {
+	testCases := map[string]struct {
+		maxConcurrency      int
+		expectedPrefetching bool
+	}{
+		"no concurrency permitted": {
+			maxConcurrency:      1,
+			expectedPrefetching: false,
+		},
+		"concurrency permitted": {
+			maxConcurrency:      2,
+			expectedPrefetching: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			pool := pooling.NewLimitingPool(0, nil)
+			inner := &pooledTestOperator{seriesCount: 10, pool: pool}
+			prefetcher := &SeriesPrefetcher{
+				Inner:              inner,
+				Pool:               pool,
+				ConcurrencyLimiter: NewConcurrencyLimiter(testCase.maxConcurrency),
+				BufferSize:         3,
+			}
+
+			metadata, err := prefetcher.SeriesMetadata(ctx)
+			require.NoError(t, err)
+			require.Len(t, metadata, inner.seriesCount)
+			pooling.PutSeriesMetadataSlice(metadata)
+
+			for i := 0; i < inner.seriesCount; i++ {
+				d, err := prefetcher.NextSeries(ctx)
+				require.NoError(t, err)
+				require.Equal(t, []promql.FPoint{{T: int64(i), F: float64(i)}}, d.Floats, "series should be returned in the same order as the inner operator returns them")
+				pool.PutInstantVectorSeriesData(d)
+			}
+
+			require.Equal(t, testCase.expectedPrefetching, prefetcher.prefetching)
+
+			_, err = prefetcher.NextSeries(ctx)
+			require.Equal(t, types.EOS, err)
+
+			prefetcher.Close()
+			require.True(t, inner.closed)
+			require.Zero(t, pool.CurrentEstimatedMemoryConsumptionBytes)
+		})
+	}
+}
+
+func TestSeriesPrefetcher_ReturnsErrorFromInnerOperatorInOrder(t *testing.T) {
+	ctx := context.Background()
+	pool := pooling.NewLimitingPool(0, nil)
+	innerErr := errors.New("something went wrong")
+	inner := &pooledTestOperator{seriesCount: 5, errAfter: 2, err: innerErr, pool: pool}
+	prefetcher := &SeriesPrefetcher{
+		Inner:              inner,
+		Pool:               pool,
+		ConcurrencyLimiter: NewConcurrencyLimiter(2),
+		BufferSize:         3,
+	}
+
+	metadata, err := prefetcher.SeriesMetadata(ctx)
+	require.NoError(t, err)
+	pooling.PutSeriesMetadataSlice(metadata)
+
+	for i := 0; i < 2; i++ {
+		d, err := prefetcher.NextSeries(ctx)
+		require.NoError(t, err)
+		pool.PutInstantVectorSeriesData(d)
+	}
+
+	_, err = prefetcher.NextSeries(ctx)
+	require.Equal(t, innerErr, err)
+
+	prefetcher.Close()
+	require.Zero(t, pool.CurrentEstimatedMemoryConsumptionBytes)
+}
+
+func TestSeriesPrefetcher_ClosedBeforeAllSeriesRead(t *testing.T) {
+	ctx := context.Background()
+	pool := pooling.NewLimitingPool(0, nil)
+	limiter := NewConcurrencyLimiter(2)
+	inner := &pooledTestOperator{seriesCount: 100, pool: pool}
+	prefetcher := &SeriesPrefetcher{
+		Inner:              inner,
+		Pool:               pool,
+		ConcurrencyLimiter: limiter,
+		BufferSize:         3,
+	}
+
+	metadata, err := prefetcher.SeriesMetadata(ctx)
+	require.NoError(t, err)
+	pooling.PutSeriesMetadataSlice(metadata)
+
+	d, err := prefetcher.NextSeries(ctx)
+	require.NoError(t, err)
+	pool.PutInstantVectorSeriesData(d)
+
+	prefetcher.Close()
+	require.True(t, inner.closed)
+	require.Zero(t, pool.CurrentEstimatedMemoryConsumptionBytes, "prefetched series that were never read should be returned to the pool")
+	require.True(t, limiter.TryAcquire(), "prefetching goroutine should release its slot once it stops")
+}
+
+func TestSeriesPrefetcher_RecoversFromPanicInInnerOperator(t *testing.T) {
+	ctx := context.Background()
+	pool := pooling.NewLimitingPool(0, nil)
+	inner := &pooledTestOperator{seriesCount: 5, panicAfter: 2, pool: pool}
+	prefetcher := &SeriesPrefetcher{
+		Inner:              inner,
+		Pool:               pool,
+		ConcurrencyLimiter: NewConcurrencyLimiter(2),
+		BufferSize:         3,
+	}
+
+	metadata, err := prefetcher.SeriesMetadata(ctx)
+	require.NoError(t, err)
+	pooling.PutSeriesMetadataSlice(metadata)
+
+	for i := 0; i < 2; i++ {
+		d, err := prefetcher.NextSeries(ctx)
+		require.NoError(t, err)
+		pool.PutInstantVectorSeriesData(d)
+	}
+
+	_, err = prefetcher.NextSeries(ctx)
+	require.EqualError(t, err, "unexpected panic while evaluating query: something went wrong")
+
+	prefetcher.Close()
+	require.Zero(t, pool.CurrentEstimatedMemoryConsumptionBytes)
+}
+
+func TestSeriesPrefetcher_WithInstantVectorSelector(t *testing.T) {
+	storage := promqltest.LoadedStorage(t, `
+		load 1m
+			some_metric{idx="1"} 0+1x10
+			some_metric{idx="2"} 0+2x10
+			some_metric{idx="3"} 0+3x10
+	`)
+	t.Cleanup(func() { require.NoError(t, storage.Close()) })
+
+	for _, maxConcurrency := range []int{1, 2} {
+		t.Run(fmt.Sprintf("max concurrency=%v", maxConcurrency), func(t *testing.T) {
+			ctx := context.Background()
+			pool := pooling.NewLimitingPool(0, nil)
+			prefetcher := &SeriesPrefetcher{
+				Inner: &InstantVectorSelector{
+					Selector: &Selector{
+						Queryable:     storage,
+						Start:         0,
+						End:           (10 * time.Minute).Milliseconds(),
+						Interval:      time.Minute.Milliseconds(),
+						LookbackDelta: 5 * time.Minute,
+						Matchers:      []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "some_metric")},
+					},
+					Pool: pool,
+				},
+				Pool:               pool,
+				ConcurrencyLimiter: NewConcurrencyLimiter(maxConcurrency),
+				BufferSize:         1,
+			}
+
+			metadata, err := prefetcher.SeriesMetadata(ctx)
+			require.NoError(t, err)
+			require.Len(t, metadata, 3)
+
+			for i := range metadata {
+				d, err := prefetcher.NextSeries(ctx)
+				require.NoError(t, err)
+				require.Len(t, d.Floats, 11)
+				require.Equal(t, float64(10*(i+1)), d.Floats[10].F, "series should be returned in the same order as their metadata")
+				pool.PutInstantVectorSeriesData(d)
+			}
+
+			_, err = prefetcher.NextSeries(ctx)
+			require.Equal(t, types.EOS, err, "should not request more series from the selector than it returned metadata for")
+
+			pooling.PutSeriesMetadataSlice(metadata)
+			prefetcher.Close()
+			require.Zero(t, pool.CurrentEstimatedMemoryConsumptionBytes)
+		})
+	}
+}
+
+// pooledTestOperator returns seriesCount series, each with a single point with slices taken from pool.
+// If err is set, it is returned instead of the series with index errAfter.
+// If panicAfter is non-zero, NextSeries panics instead of returning the series with index panicAfter.
+//
+// Like real operators, it panics if NextSeries is called more than seriesCount times.
+type pooledTestOperator struct {
+	seriesCount int
+	errAfter    int
+	err         error
+	panicAfter  int
+	pool        *pooling.LimitingPool
+
+	nextSeries int
+	closed     bool
+}
+
+func (o *pooledTestOperator) SeriesMetadata(_ context.Context) ([]types.SeriesMetadata, error) {
+	return pooling.GetSeriesMetadataSlice(o.seriesCount)[:o.seriesCount], nil
+}
+
+func (o *pooledTestOperator) NextSeries(_ context.Context) (types.InstantVectorSeriesData, error) {
+	if o.err != nil && o.nextSeries == o.errAfter {
+		return types.InstantVectorSeriesData{}, o.err
+	}
+
+	if o.panicAfter != 0 && o.nextSeries == o.panicAfter {
+		panic("something went wrong")
+	}
+
+	if o.nextSeries >= o.seriesCount {
+		panic("no more series")
+	}
+
+	floats, err := o.pool.GetFPointSlice(1)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	floats = append(floats, promql.FPoint{T: int64(o.nextSeries), F: float64(o.nextSeries)})
+	o.nextSeries++
+
+	return types.InstantVectorSeriesData{Floats: floats}, nil
+}
+
+func (o *pooledTestOperator) Close() {
+	o.closed = true
+}
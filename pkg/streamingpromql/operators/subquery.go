@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// Subquery presents the result of evaluating an instant vector expression over the time range of a subquery
+// (eg. the rate(x[5m]) in rate(x[5m])[1h:1m]) as a range vector.
+type Subquery struct {
+	Inner types.InstantVectorOperator
+	Pool  *pooling.LimitingPool
+
+	// Time range of the parent expression, not of the subquery itself.
+	Start    int64 // Milliseconds since Unix epoch
+	End      int64 // Milliseconds since Unix epoch
+	Interval int64 // In milliseconds
+
+	SubqueryTimestamp *int64 // Milliseconds since Unix epoch, only set if subquery uses @ modifier (eg. rate(x[5m])[1h:1m] @ 123)
+	SubqueryOffset    int64  // In milliseconds
+	SubqueryRange     time.Duration
+
+	rangeMilliseconds int64
+	numSteps          int
+	nextT             int64
+
+	// Data for the current series, and the index of the next float and histogram point not yet added to a buffer.
+	data               types.InstantVectorSeriesData
+	nextFloatIndex     int
+	nextHistogramIndex int
+}
+
+var _ types.RangeVectorOperator = &Subquery{}
+
+func (s *Subquery) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	// Compute value we need on every call to NextSeries() once, here.
+	s.rangeMilliseconds = s.SubqueryRange.Milliseconds()
+	s.numSteps = stepCount(s.Start, s.End, s.Interval)
+
+	return s.Inner.SeriesMetadata(ctx)
+}
+
+func (s *Subquery) StepCount() int {
+	return s.numSteps
+}
+
+func (s *Subquery) Range() time.Duration {
+	return s.SubqueryRange
+}
+
+func (s *Subquery) NextSeries(ctx context.Context) error {
+	s.Pool.PutInstantVectorSeriesData(s.data)
+	s.data = types.InstantVectorSeriesData{}
+
+	var err error
+	s.data, err = s.Inner.NextSeries(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.nextFloatIndex = 0
+	s.nextHistogramIndex = 0
+	s.nextT = s.Start
+	return nil
+}
+
+func (s *Subquery) NextStepSamples(floats *types.FPointRingBuffer, histograms *types.HPointRingBuffer) (types.RangeVectorStepData, error) {
+	if s.nextT > s.End {
+		return types.RangeVectorStepData{}, types.EOS
+	}
+
+	stepT := s.nextT
+	rangeEnd := stepT
+
+	if s.SubqueryTimestamp != nil {
+		rangeEnd = *s.SubqueryTimestamp
+	}
+
+	rangeEnd -= s.SubqueryOffset
+	rangeStart := rangeEnd - s.rangeMilliseconds
+	floats.DiscardPointsBefore(rangeStart)
+	histograms.DiscardPointsBefore(rangeStart)
+
+	for ; s.nextFloatIndex < len(s.data.Floats) && s.data.Floats[s.nextFloatIndex].T <= rangeEnd; s.nextFloatIndex++ {
+		p := s.data.Floats[s.nextFloatIndex]
+
+		if p.T < rangeStart {
+			continue
+		}
+
+		if err := floats.Append(p); err != nil {
+			return types.RangeVectorStepData{}, err
+		}
+	}
+
+	for ; s.nextHistogramIndex < len(s.data.Histograms) && s.data.Histograms[s.nextHistogramIndex].T <= rangeEnd; s.nextHistogramIndex++ {
+		p := s.data.Histograms[s.nextHistogramIndex]
+
+		if p.T < rangeStart {
+			continue
+		}
+
+		if err := histograms.Append(p); err != nil {
+			return types.RangeVectorStepData{}, err
+		}
+	}
+
+	s.nextT += s.Interval
+
+	return types.RangeVectorStepData{
+		StepT:      stepT,
+		RangeStart: rangeStart,
+		RangeEnd:   rangeEnd,
+	}, nil
+}
+
+func (s *Subquery) Close() {
+	s.Inner.Close()
+
+	s.Pool.PutInstantVectorSeriesData(s.data)
+	s.data = types.InstantVectorSeriesData{}
+}
@@ -0,0 +1,469 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/prometheus/prometheus/model/timestamp"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// TopKBottomK implements the topk() and bottomk() aggregations.
+//
+// Unlike other aggregations, topk and bottomk return input series unchanged (including the metric name),
+// and may return more than one series for each group.
+//
+// For range queries, all series in a group are returned in SeriesMetadata, as we don't know which series will be
+// selected at each step until we've seen the data for all series in the group. Series that are never selected will
+// have no points.
+//
+// For instant queries, all input series are read in SeriesMetadata so that only selected series are returned, and
+// they are returned in the same order as Prometheus' engine: for topk, in descending order of value, and for bottomk,
+// in ascending order of value.
+type TopKBottomK struct {
+	Inner    types.InstantVectorOperator
+	Start    int64 // Milliseconds since Unix epoch
+	End      int64 // Milliseconds since Unix epoch
+	Interval int64 // In milliseconds
+	Grouping []string
+	Without  bool
+	IsTopK   bool
+	Param    types.ScalarOperator // The value of k, before validation.
+	Pool     *pooling.LimitingPool
+
+	k int
+
+	// Used for range queries.
+	remainingInnerSeriesToGroup []*topKBottomKGroup // One entry per series produced by Inner, value is the group for that series
+	remainingGroups             []*topKBottomKGroup // One entry per group, in the order we want to return them
+	currentGroup                *topKBottomKGroup
+
+	// Used for instant queries.
+	instantQueryOutput []types.InstantVectorSeriesData
+
+	// Reused between groups to avoid allocating for every group.
+	heap           topKBottomKHeap
+	cursors        []int
+	nextWriteIndex []int
+}
+
+type topKBottomKGroup struct {
+	// The number of input series that belong to this group that we haven't yet seen.
+	remainingSeriesCount int
+
+	// The index of the last series that contributes to this group.
+	// Used to sort groups in the order that they'll be completed in.
+	lastSeriesIndex int
+
+	// The index of the first series that contributes to this group.
+	// Used to sort groups for instant queries in the same order as Prometheus' engine.
+	firstSeriesIndex int
+
+	// The data for each series in this group, in the order they were received from Inner.
+	series []types.InstantVectorSeriesData
+
+	// The indices of each series in this group, in the order they were received from Inner.
+	seriesIndices []int
+
+	// The index into series of the next series to return.
+	nextSeriesToReturn int
+}
+
+var _ types.InstantVectorOperator = &TopKBottomK{}
+
+func NewTopKBottomK(
+	inner types.InstantVectorOperator,
+	start time.Time,
+	end time.Time,
+	interval time.Duration,
+	grouping []string,
+	without bool,
+	isTopK bool,
+	param types.ScalarOperator,
+	pool *pooling.LimitingPool,
+) *TopKBottomK {
+	s, e, i := timestamp.FromTime(start), timestamp.FromTime(end), interval.Milliseconds()
+	slices.Sort(grouping)
+
+	return &TopKBottomK{
+		Inner:    inner,
+		Start:    s,
+		End:      e,
+		Interval: i,
+		Grouping: grouping,
+		Without:  without,
+		IsTopK:   isTopK,
+		Param:    param,
+		Pool:     pool,
+	}
+}
+
+func (t *TopKBottomK) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	// Evaluate the parameter before the source series, consistent with Prometheus' engine.
+	param, err := evaluateAggregationParam(ctx, t.Param, t.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	if !convertibleToInt64(param) {
+		return nil, fmt.Errorf("Scalar value %v overflows int64", param)
+	}
+
+	innerSeries, err := t.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.k = int(param)
+	if t.k > len(innerSeries) {
+		t.k = len(innerSeries)
+	}
+
+	if t.k < 1 {
+		// No series can be selected, so there's nothing to return.
+		pooling.PutSeriesMetadataSlice(innerSeries)
+		return nil, nil
+	}
+
+	groups := map[uint64]*topKBottomKGroup{}
+	groupingKeyFunc := newGroupingKeyFunc(t.Grouping, t.Without)
+	seriesToGroup := make([]*topKBottomKGroup, 0, len(innerSeries))
+
+	for seriesIdx, series := range innerSeries {
+		groupingKey := groupingKeyFunc(series.Labels)
+		g, groupExists := groups[groupingKey]
+
+		if !groupExists {
+			g = &topKBottomKGroup{firstSeriesIndex: seriesIdx}
+			groups[groupingKey] = g
+		}
+
+		g.remainingSeriesCount++
+		g.lastSeriesIndex = seriesIdx
+		g.seriesIndices = append(g.seriesIndices, seriesIdx)
+		seriesToGroup = append(seriesToGroup, g)
+	}
+
+	sortedGroups := make([]*topKBottomKGroup, 0, len(groups))
+	for _, g := range groups {
+		sortedGroups = append(sortedGroups, g)
+	}
+
+	if t.Start == t.End {
+		// Instant query.
+		defer pooling.PutSeriesMetadataSlice(innerSeries)
+		return t.computeInstantQueryResult(ctx, innerSeries, seriesToGroup, sortedGroups)
+	}
+
+	// Range query: sort groups so that the group that is completed first is returned first.
+	slices.SortFunc(sortedGroups, func(a, b *topKBottomKGroup) int {
+		return a.lastSeriesIndex - b.lastSeriesIndex
+	})
+
+	outputSeries := pooling.GetSeriesMetadataSlice(len(innerSeries))
+
+	for _, g := range sortedGroups {
+		for _, idx := range g.seriesIndices {
+			outputSeries = append(outputSeries, innerSeries[idx])
+		}
+	}
+
+	pooling.PutSeriesMetadataSlice(innerSeries)
+	t.remainingInnerSeriesToGroup = seriesToGroup
+	t.remainingGroups = sortedGroups
+
+	return outputSeries, nil
+}
+
+func (t *TopKBottomK) computeInstantQueryResult(ctx context.Context, innerSeries []types.SeriesMetadata, seriesToGroup []*topKBottomKGroup, groups []*topKBottomKGroup) ([]types.SeriesMetadata, error) {
+	for _, g := range seriesToGroup {
+		if err := t.readNextSeriesIntoGroup(ctx, g); err != nil {
+			return nil, err
+		}
+	}
+
+	// Return groups in the order they were first seen, consistent with Prometheus' engine.
+	slices.SortFunc(groups, func(a, b *topKBottomKGroup) int {
+		return a.firstSeriesIndex - b.firstSeriesIndex
+	})
+
+	// Each group returns at most k series, and no more series than it contains.
+	outputSize := 0
+	for _, g := range groups {
+		outputSize += min(t.k, len(g.series))
+	}
+
+	outputSeries := pooling.GetSeriesMetadataSlice(outputSize)
+	t.instantQueryOutput = make([]types.InstantVectorSeriesData, 0, outputSize)
+
+	for _, g := range groups {
+		t.selectPoints(g)
+
+		selected := make([]int, 0, min(t.k, len(g.series)))
+		for i, d := range g.series {
+			if len(d.Floats) > 0 {
+				selected = append(selected, i)
+			}
+		}
+
+		// topk returns results in descending order, bottomk returns results in ascending order.
+		// In both cases, NaN values are returned last.
+		sort.SliceStable(selected, func(i, j int) bool {
+			vi, vj := g.series[selected[i]].Floats[0].F, g.series[selected[j]].Floats[0].F
+
+			if math.IsNaN(vj) {
+				return !math.IsNaN(vi)
+			}
+
+			if t.IsTopK {
+				return vi > vj
+			}
+
+			return vi < vj
+		})
+
+		for _, i := range selected {
+			outputSeries = append(outputSeries, innerSeries[g.seriesIndices[i]])
+			t.instantQueryOutput = append(t.instantQueryOutput, g.series[i])
+		}
+	}
+
+	return outputSeries, nil
+}
+
+func (t *TopKBottomK) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	if t.Start == t.End {
+		if len(t.instantQueryOutput) == 0 {
+			return types.InstantVectorSeriesData{}, types.EOS
+		}
+
+		d := t.instantQueryOutput[0]
+		t.instantQueryOutput = t.instantQueryOutput[1:]
+		return d, nil
+	}
+
+	if t.currentGroup == nil || t.currentGroup.nextSeriesToReturn == len(t.currentGroup.series) {
+		if len(t.remainingGroups) == 0 {
+			return types.InstantVectorSeriesData{}, types.EOS
+		}
+
+		t.currentGroup = t.remainingGroups[0]
+		t.remainingGroups = t.remainingGroups[1:]
+
+		// Read inner series until the group is complete.
+		for t.currentGroup.remainingSeriesCount > 0 {
+			g := t.remainingInnerSeriesToGroup[0]
+			t.remainingInnerSeriesToGroup = t.remainingInnerSeriesToGroup[1:]
+
+			if err := t.readNextSeriesIntoGroup(ctx, g); err != nil {
+				return types.InstantVectorSeriesData{}, err
+			}
+		}
+
+		t.selectPoints(t.currentGroup)
+	}
+
+	d := t.currentGroup.series[t.currentGroup.nextSeriesToReturn]
+	t.currentGroup.series[t.currentGroup.nextSeriesToReturn] = types.InstantVectorSeriesData{}
+	t.currentGroup.nextSeriesToReturn++
+
+	return d, nil
+}
+
+func (t *TopKBottomK) readNextSeriesIntoGroup(ctx context.Context, g *topKBottomKGroup) error {
+	d, err := t.Inner.NextSeries(ctx)
+	if err != nil {
+		if errors.Is(err, types.EOS) {
+			return fmt.Errorf("exhausted series before all groups were completed: %w", err)
+		}
+
+		return err
+	}
+
+	// Native histograms are ignored by topk and bottomk.
+	t.Pool.PutHPointSlice(d.Histograms)
+	d.Histograms = nil
+
+	g.series = append(g.series, d)
+	g.remainingSeriesCount--
+
+	return nil
+}
+
+// selectPoints computes the series selected at each step for the group g, and removes all other points from
+// the series in g.
+func (t *TopKBottomK) selectPoints(g *topKBottomKGroup) {
+	t.cursors = resizeAndClearIntSlice(t.cursors, len(g.series))
+	t.nextWriteIndex = resizeAndClearIntSlice(t.nextWriteIndex, len(g.series))
+	t.heap.series = g.series
+	t.heap.cursors = t.cursors
+	t.heap.isTopK = t.IsTopK
+
+	for ts := t.Start; ts <= t.End; ts += t.Interval {
+		t.heap.entries = t.heap.entries[:0]
+
+		for seriesIdx, d := range g.series {
+			if t.cursors[seriesIdx] >= len(d.Floats) || d.Floats[t.cursors[seriesIdx]].T != ts {
+				continue
+			}
+
+			f := d.Floats[t.cursors[seriesIdx]].F
+
+			switch {
+			case len(t.heap.entries) < t.k:
+				heap.Push(&t.heap, seriesIdx)
+			case t.heap.shouldReplaceWorst(f):
+				t.heap.entries[0] = seriesIdx
+				if t.k > 1 {
+					heap.Fix(&t.heap, 0)
+				}
+			}
+		}
+
+		for _, seriesIdx := range t.heap.entries {
+			d := g.series[seriesIdx]
+			d.Floats[t.nextWriteIndex[seriesIdx]] = d.Floats[t.cursors[seriesIdx]]
+			t.nextWriteIndex[seriesIdx]++
+		}
+
+		for seriesIdx, d := range g.series {
+			if t.cursors[seriesIdx] < len(d.Floats) && d.Floats[t.cursors[seriesIdx]].T == ts {
+				t.cursors[seriesIdx]++
+			}
+		}
+	}
+
+	for seriesIdx, d := range g.series {
+		if t.nextWriteIndex[seriesIdx] == 0 {
+			t.Pool.PutFPointSlice(d.Floats)
+			g.series[seriesIdx].Floats = nil
+		} else {
+			g.series[seriesIdx].Floats = d.Floats[:t.nextWriteIndex[seriesIdx]]
+		}
+	}
+
+	t.heap.series = nil
+}
+
+func resizeAndClearIntSlice(s []int, size int) []int {
+	if cap(s) < size {
+		return make([]int, size)
+	}
+
+	s = s[:size]
+	clear(s)
+	return s
+}
+
+func (t *TopKBottomK) Close() {
+	t.Inner.Close()
+	t.Param.Close()
+
+	for _, d := range t.instantQueryOutput {
+		t.Pool.PutInstantVectorSeriesData(d)
+	}
+
+	t.instantQueryOutput = nil
+
+	if t.currentGroup != nil {
+		for _, d := range t.currentGroup.series {
+			t.Pool.PutInstantVectorSeriesData(d)
+		}
+	}
+
+	for _, g := range t.remainingGroups {
+		for _, d := range g.series {
+			t.Pool.PutInstantVectorSeriesData(d)
+		}
+	}
+
+	t.currentGroup = nil
+	t.remainingGroups = nil
+}
+
+// topKBottomKHeap is a heap of series indices, with the worst selected series (the smallest value for topk,
+// or the largest value for bottomk) at the top of the heap.
+type topKBottomKHeap struct {
+	entries []int
+	series  []types.InstantVectorSeriesData
+	cursors []int
+	isTopK  bool
+}
+
+func (h *topKBottomKHeap) value(entryIdx int) float64 {
+	seriesIdx := h.entries[entryIdx]
+	return h.series[seriesIdx].Floats[h.cursors[seriesIdx]].F
+}
+
+func (h *topKBottomKHeap) Len() int {
+	return len(h.entries)
+}
+
+func (h *topKBottomKHeap) Less(i, j int) bool {
+	vi, vj := h.value(i), h.value(j)
+
+	if math.IsNaN(vi) {
+		return true
+	}
+
+	if h.isTopK {
+		return vi < vj
+	}
+
+	return vi > vj
+}
+
+func (h *topKBottomKHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+}
+
+func (h *topKBottomKHeap) Push(x any) {
+	h.entries = append(h.entries, x.(int))
+}
+
+func (h *topKBottomKHeap) Pop() any {
+	last := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+	return last
+}
+
+// shouldReplaceWorst returns true if f should replace the worst selected series at the top of the heap.
+func (h *topKBottomKHeap) shouldReplaceWorst(f float64) bool {
+	worst := h.value(0)
+
+	if math.IsNaN(worst) && !math.IsNaN(f) {
+		return true
+	}
+
+	if h.isTopK {
+		return worst < f
+	}
+
+	return worst > f
+}
+
+const (
+	// The largest float64 value that can be converted to an int64 without overflowing.
+	// math.MaxInt64 can't be used, as it is rounded up to 2^63 when converted to a float64, which overflows an int64.
+	maxInt64 = 9223372036854774784
+	minInt64 = math.MinInt64
+)
+
+// convertibleToInt64 returns true if v does not over- or underflow an int64.
+// It returns false if v is NaN.
+func convertibleToInt64(v float64) bool {
+	return v <= maxInt64 && v >= minInt64
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package operators
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// Most of the functionality of topk and bottomk is tested through the test scripts in
+// pkg/streamingpromql/testdata.
+//
+// This test ensures the output of an instant query is sized by the number of series in each group
+// rather than by k, which would otherwise allocate k entries for each group.
+func TestTopKBottomK_InstantQueryWithLargeKAndManySmallGroups(t *testing.T) {
+	const numSeries = 100000
+
+	ts := time.Unix(0, 0)
+	pool := pooling.NewLimitingPool(0, nil)
+
+	inner := &testOperator{}
+	for i := 0; i < numSeries; i++ {
+		inner.series = append(inner.series, labels.FromStrings("pod", strconv.Itoa(i)))
+		inner.data = append(inner.data, types.InstantVectorSeriesData{Floats: []promql.FPoint{{T: 0, F: float64(i)}}})
+	}
+
+	o := NewTopKBottomK(inner, ts, ts, time.Minute, []string{"pod"}, false, true, NewScalarConstant(numSeries, ts, ts, time.Minute, pool), pool)
+
+	series, err := o.SeriesMetadata(context.Background())
+	require.NoError(t, err)
+	require.Len(t, series, numSeries)
+
+	for i := 0; i < numSeries; i++ {
+		d, err := o.NextSeries(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []promql.FPoint{{T: 0, F: float64(i)}}, d.Floats)
+	}
+
+	_, err = o.NextSeries(context.Background())
+	require.Equal(t, types.EOS, err)
+}
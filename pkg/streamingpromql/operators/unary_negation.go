@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"context"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/functions"
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// UnaryNegationOfInstantVector is an operator that negates each float point of an instant vector, such as "-some_metric".
+//
+// Like Prometheus' engine, native histogram points are returned unchanged, and the metric name is dropped.
+// The returned series may therefore contain duplicate label sets, so UnaryNegationOfInstantVector should be wrapped
+// in DeduplicateAndMerge.
+type UnaryNegationOfInstantVector struct {
+	Inner types.InstantVectorOperator
+	Pool  *pooling.LimitingPool
+}
+
+var _ types.InstantVectorOperator = &UnaryNegationOfInstantVector{}
+
+func (u *UnaryNegationOfInstantVector) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	metadata, err := u.Inner.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return functions.DropSeriesName(metadata, u.Pool)
+}
+
+func (u *UnaryNegationOfInstantVector) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	series, err := u.Inner.NextSeries(ctx)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	for i := range series.Floats {
+		series.Floats[i].F = -series.Floats[i].F
+	}
+
+	return series, nil
+}
+
+func (u *UnaryNegationOfInstantVector) Close() {
+	u.Inner.Close()
+}
+
+// UnaryNegationOfScalar is an operator that negates a scalar, such as "-time()".
+type UnaryNegationOfScalar struct {
+	Inner types.ScalarOperator
+}
+
+var _ types.ScalarOperator = &UnaryNegationOfScalar{}
+
+func (u *UnaryNegationOfScalar) GetValues(ctx context.Context) (types.ScalarData, error) {
+	values, err := u.Inner.GetValues(ctx)
+	if err != nil {
+		return types.ScalarData{}, err
+	}
+
+	for i := range values.Samples {
+		values.Samples[i].F = -values.Samples[i].F
+	}
+
+	return values, nil
+}
+
+func (u *UnaryNegationOfScalar) Close() {
+	u.Inner.Close()
+}
@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/promql/engine.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors
+
+package operators
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/compat"
+	"github.com/grafana/mimir/pkg/streamingpromql/functions"
+	"github.com/grafana/mimir/pkg/streamingpromql/pooling"
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
+)
+
+// VectorScalarBinaryOperation represents a binary operation between an instant vector and a scalar such as "<expr> + 2" or "3 * <expr>".
+type VectorScalarBinaryOperation struct {
+	Scalar           types.ScalarOperator
+	Vector           types.InstantVectorOperator
+	ScalarIsLeftSide bool
+	Op               parser.ItemType
+	ReturnBool       bool
+	Start            int64 // Milliseconds since Unix epoch
+	Interval         int64 // In milliseconds
+	Pool             *pooling.LimitingPool
+
+	scalarData types.ScalarData
+	opFunc     vectorScalarBinaryOperationFunc
+}
+
+var _ types.InstantVectorOperator = &VectorScalarBinaryOperation{}
+
+// vectorScalarBinaryOperationFunc computes the result of a binary operation between a single point from the vector side and the scalar value
+// at the same time step.
+// It returns false if the point should not be included in the output.
+type vectorScalarBinaryOperationFunc func(scalar float64, vectorF float64, vectorH *histogram.FloatHistogram) (float64, *histogram.FloatHistogram, bool)
+
+func NewVectorScalarBinaryOperation(
+	scalar types.ScalarOperator,
+	vector types.InstantVectorOperator,
+	scalarIsLeftSide bool,
+	op parser.ItemType,
+	returnBool bool,
+	start time.Time,
+	interval time.Duration,
+	pool *pooling.LimitingPool,
+) (*VectorScalarBinaryOperation, error) {
+	if !op.IsComparisonOperator() && arithmeticOperationFuncs[op] == nil {
+		return nil, compat.NewNotSupportedError(fmt.Sprintf("binary expression with '%s'", op))
+	}
+
+	b := &VectorScalarBinaryOperation{
+		Scalar:           scalar,
+		Vector:           vector,
+		ScalarIsLeftSide: scalarIsLeftSide,
+		Op:               op,
+		ReturnBool:       returnBool,
+		Start:            timestamp.FromTime(start),
+		Interval:         interval.Milliseconds(),
+		Pool:             pool,
+	}
+
+	b.opFunc = func(scalar float64, vectorF float64, vectorH *histogram.FloatHistogram) (float64, *histogram.FloatHistogram, bool) {
+		var f float64
+		var h *histogram.FloatHistogram
+		var keep bool
+
+		if scalarIsLeftSide {
+			f, h, keep = vectorElementBinaryOperation(op, scalar, vectorF, nil, vectorH)
+
+			// Comparison operations always return the value from the vector side, even if it's on the right side.
+			if op.IsComparisonOperator() {
+				f, h = vectorF, vectorH
+			}
+		} else {
+			f, h, keep = vectorElementBinaryOperation(op, vectorF, scalar, vectorH, nil)
+		}
+
+		if returnBool {
+			h = nil
+
+			if keep {
+				f = 1
+			} else {
+				f = 0
+			}
+
+			keep = true
+		}
+
+		return f, h, keep
+	}
+
+	return b, nil
+}
+
+func (v *VectorScalarBinaryOperation) SeriesMetadata(ctx context.Context) ([]types.SeriesMetadata, error) {
+	metadata, err := v.Vector.SeriesMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metadata) == 0 {
+		// There's nothing to do, so don't bother evaluating the scalar side.
+		return metadata, nil
+	}
+
+	// Retain the scalar values for use in NextSeries.
+	// We'll return the slice to the pool in Close.
+	v.scalarData, err = v.Scalar.GetValues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !v.Op.IsComparisonOperator() || v.ReturnBool {
+		return functions.DropSeriesName(metadata, v.Pool)
+	}
+
+	return metadata, nil
+}
+
+func (v *VectorScalarBinaryOperation) NextSeries(ctx context.Context) (types.InstantVectorSeriesData, error) {
+	series, err := v.Vector.NextSeries(ctx)
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	if len(series.Histograms) == 0 {
+		// Fast path: we only have floats, so we can compute the result in place.
+		outputIdx := 0
+
+		for _, p := range series.Floats {
+			f, _, keep := v.opFunc(v.scalarValueAt(p.T), p.F, nil)
+
+			if keep {
+				series.Floats[outputIdx] = promql.FPoint{T: p.T, F: f}
+				outputIdx++
+			}
+		}
+
+		series.Floats = series.Floats[:outputIdx]
+		return series, nil
+	}
+
+	// Slow path: operations on histograms may produce floats, so we need to build a new float slice and merge the
+	// results from the float and histogram points together in timestamp order.
+	// We can reuse the input histograms slice, as we'll never produce more histograms than we receive.
+	floats, err := v.Pool.GetFPointSlice(len(series.Floats) + len(series.Histograms))
+	if err != nil {
+		return types.InstantVectorSeriesData{}, err
+	}
+
+	histograms := series.Histograms[:0]
+	nextFloatIdx, nextHistogramIdx := 0, 0
+
+	for nextFloatIdx < len(series.Floats) || nextHistogramIdx < len(series.Histograms) {
+		var t int64
+		var f float64
+		var h *histogram.FloatHistogram
+
+		if nextHistogramIdx == len(series.Histograms) || (nextFloatIdx < len(series.Floats) && series.Floats[nextFloatIdx].T < series.Histograms[nextHistogramIdx].T) {
+			p := series.Floats[nextFloatIdx]
+			t, f = p.T, p.F
+			nextFloatIdx++
+		} else {
+			p := series.Histograms[nextHistogramIdx]
+			t, h = p.T, p.H
+			nextHistogramIdx++
+		}
+
+		f, h, keep := v.opFunc(v.scalarValueAt(t), f, h)
+		if !keep {
+			continue
+		}
+
+		if h != nil {
+			histograms = append(histograms, promql.HPoint{T: t, H: h})
+		} else {
+			floats = append(floats, promql.FPoint{T: t, F: f})
+		}
+	}
+
+	v.Pool.PutFPointSlice(series.Floats)
+
+	if len(floats) == 0 {
+		v.Pool.PutFPointSlice(floats)
+		floats = nil
+	}
+
+	if len(histograms) == 0 {
+		v.Pool.PutHPointSlice(histograms)
+		histograms = nil
+	}
+
+	return types.InstantVectorSeriesData{Floats: floats, Histograms: histograms}, nil
+}
+
+func (v *VectorScalarBinaryOperation) scalarValueAt(t int64) float64 {
+	return v.scalarData.Samples[(t-v.Start)/v.Interval].F
+}
+
+func (v *VectorScalarBinaryOperation) Close() {
+	v.Scalar.Close()
+	v.Vector.Close()
+
+	v.Pool.PutFPointSlice(v.scalarData.Samples)
+}
+
+// vectorElementBinaryOperation computes the result of a binary operation between two elements of a vector
+// (or an element of a vector and a scalar).
+//
+// It returns false if the result should not be included in the output, which is only possible for comparison operations.
+func vectorElementBinaryOperation(op parser.ItemType, lhs, rhs float64, hlhs, hrhs *histogram.FloatHistogram) (float64, *histogram.FloatHistogram, bool) {
+	switch op {
+	case parser.ADD:
+		if hlhs != nil && hrhs != nil {
+			return 0, hlhs.Copy().Add(hrhs).Compact(0), true
+		}
+		return lhs + rhs, nil, true
+	case parser.SUB:
+		if hlhs != nil && hrhs != nil {
+			return 0, hlhs.Copy().Sub(hrhs).Compact(0), true
+		}
+		return lhs - rhs, nil, true
+	case parser.MUL:
+		if hlhs != nil && hrhs == nil {
+			return 0, hlhs.Copy().Mul(rhs), true
+		}
+		if hlhs == nil && hrhs != nil {
+			return 0, hrhs.Copy().Mul(lhs), true
+		}
+		return lhs * rhs, nil, true
+	case parser.DIV:
+		if hlhs != nil && hrhs == nil {
+			return 0, hlhs.Copy().Div(rhs), true
+		}
+		return lhs / rhs, nil, true
+	case parser.POW:
+		return math.Pow(lhs, rhs), nil, true
+	case parser.MOD:
+		return math.Mod(lhs, rhs), nil, true
+	case parser.EQLC:
+		return lhs, nil, lhs == rhs
+	case parser.NEQ:
+		return lhs, nil, lhs != rhs
+	case parser.GTR:
+		return lhs, nil, lhs > rhs
+	case parser.LSS:
+		return lhs, nil, lhs < rhs
+	case parser.GTE:
+		return lhs, nil, lhs >= rhs
+	case parser.LTE:
+		return lhs, nil, lhs <= rhs
+	case parser.ATAN2:
+		return math.Atan2(lhs, rhs), nil, true
+	}
+
+	// Should be caught when the operator is created, but we check here for safety.
+	panic(fmt.Errorf("operator %q not allowed for operations between vectors", op))
+}
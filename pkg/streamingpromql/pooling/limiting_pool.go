@@ -3,6 +3,7 @@
 package pooling
 
 import (
+	"sync"
 	"unsafe"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -28,6 +29,8 @@ const (
 	Float64Size          = uint64(unsafe.Sizeof(float64(0)))
 	BoolSize             = uint64(unsafe.Sizeof(false))
 	HistogramPointerSize = uint64(unsafe.Sizeof((*histogram.FloatHistogram)(nil)))
+	HistogramBucketSize  = uint64(unsafe.Sizeof(types.HistogramBucket{}))
+	IntSize              = uint64(unsafe.Sizeof(int(0)))
 )
 
 var (
@@ -54,13 +57,23 @@ var (
 	histogramSlicePool = pool.NewBucketedPool(1, maxExpectedPointsPerSeries, pointsPerSeriesBucketFactor, func(size int) []*histogram.FloatHistogram {
 		return make([]*histogram.FloatHistogram, 0, size)
 	})
+
+	histogramBucketSlicePool = pool.NewBucketedPool(1, maxExpectedPointsPerSeries, pointsPerSeriesBucketFactor, func(size int) []types.HistogramBucket {
+		return make([]types.HistogramBucket, 0, size)
+	})
+
+	intSlicePool = pool.NewBucketedPool(1, maxExpectedPointsPerSeries, pointsPerSeriesBucketFactor, func(size int) []int {
+		return make([]int, 0, size)
+	})
 )
 
 // LimitingPool manages sample slices for a single query evaluation, and applies any max in-memory bytes limit.
 //
 // It also tracks the peak number of in-memory bytes for use in query statistics.
 //
-// It is not safe to use this type from multiple goroutines simultaneously.
+// It is safe to use this type from multiple goroutines simultaneously, so operators that evaluate their inputs
+// concurrently can share a single LimitingPool and the query's memory consumption is still tracked exactly.
+// Callers must not read the exported fields while slices may still be retrieved or returned from other goroutines.
 //
 // LimitingPool only estimates the in-memory size of the slices it returns. For example, it ignores the overhead of slice headers,
 // assumes all native histograms are the same size, and assumes all elements of a promql.Vector are float samples.
@@ -71,6 +84,8 @@ type LimitingPool struct {
 
 	rejectionCount        prometheus.Counter
 	haveRecordedRejection bool
+
+	mtx sync.Mutex
 }
 
 func NewLimitingPool(maxEstimatedMemoryConsumptionBytes uint64, rejectionCount prometheus.Counter) *LimitingPool {
@@ -94,6 +109,9 @@ func getWithElementSize[E any, S ~[]E](p *LimitingPool, pool *pool.BucketedPool[
 	// - there's no guarantee the slice will have size 'size' when it's returned to us in putWithElementSize, so using 'size' would make the accounting below impossible
 	estimatedBytes := uint64(cap(s)) * elementSize
 
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
 	if p.MaxEstimatedMemoryConsumptionBytes > 0 && p.CurrentEstimatedMemoryConsumptionBytes+estimatedBytes > p.MaxEstimatedMemoryConsumptionBytes {
 		pool.Put(s)
 
@@ -116,7 +134,10 @@ func putWithElementSize[E any, S ~[]E](p *LimitingPool, pool *pool.BucketedPool[
 		return
 	}
 
+	p.mtx.Lock()
 	p.CurrentEstimatedMemoryConsumptionBytes -= uint64(cap(s)) * elementSize
+	p.mtx.Unlock()
+
 	pool.Put(s)
 }
 
@@ -237,6 +258,56 @@ func (p *LimitingPool) PutHistogramPointerSlice(s []*histogram.FloatHistogram) {
 	putWithElementSize(p, histogramSlicePool, HistogramPointerSize, s)
 }
 
+// GetHistogramBucketSlice returns a slice of HistogramBucket of length 0 and capacity greater than or equal to size.
+//
+// If the capacity of the returned slice would cause the max memory consumption limit to be exceeded, then an error is returned.
+//
+// Every element of the returned slice up to the requested size will have value 0.
+//
+// Note that the capacity of the returned slice may be significantly larger than size, depending on the configuration of the underlying bucketed pool.
+func (p *LimitingPool) GetHistogramBucketSlice(size int) ([]types.HistogramBucket, error) {
+	s, err := getWithElementSize(p, histogramBucketSlicePool, size, HistogramBucketSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// This is not necessary if we've just created a new slice, it'll already have all elements reset.
+	// But we do it unconditionally for simplicity.
+	clear(s[:size])
+
+	return s, nil
+}
+
+// PutHistogramBucketSlice returns a slice of HistogramBucket to the pool and updates the current number of in-memory samples.
+func (p *LimitingPool) PutHistogramBucketSlice(s []types.HistogramBucket) {
+	putWithElementSize(p, histogramBucketSlicePool, HistogramBucketSize, s)
+}
+
+// GetIntSlice returns a slice of int of length 0 and capacity greater than or equal to size.
+//
+// If the capacity of the returned slice would cause the max memory consumption limit to be exceeded, then an error is returned.
+//
+// Every element of the returned slice up to the requested size will have value 0.
+//
+// Note that the capacity of the returned slice may be significantly larger than size, depending on the configuration of the underlying bucketed pool.
+func (p *LimitingPool) GetIntSlice(size int) ([]int, error) {
+	s, err := getWithElementSize(p, intSlicePool, size, IntSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// This is not necessary if we've just created a new slice, it'll already have all elements reset.
+	// But we do it unconditionally for simplicity.
+	clear(s[:size])
+
+	return s, nil
+}
+
+// PutIntSlice returns a slice of int to the pool and updates the current number of in-memory samples.
+func (p *LimitingPool) PutIntSlice(s []int) {
+	putWithElementSize(p, intSlicePool, IntSize, s)
+}
+
 // PutInstantVectorSeriesData is equivalent to calling PutFPointSlice(d.Floats) and PutHPointSlice(d.Histograms).
 func (p *LimitingPool) PutInstantVectorSeriesData(d types.InstantVectorSeriesData) {
 	p.PutFPointSlice(d.Floats)
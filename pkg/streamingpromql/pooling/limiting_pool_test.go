@@ -12,6 +12,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/streamingpromql/types"
 )
 
 const rejectedQueryName = "rejected_queries"
@@ -52,6 +54,18 @@ func TestLimitingPool_Unlimited(t *testing.T) {
 		pool := NewLimitingPool(0, metric)
 		testUnlimitedPool(t, pool.GetHistogramPointerSlice, pool.PutHistogramPointerSlice, pool, HistogramPointerSize, reg)
 	})
+
+	t.Run("[]types.HistogramBucket", func(t *testing.T) {
+		reg, metric := createRejectedMetric()
+		pool := NewLimitingPool(0, metric)
+		testUnlimitedPool(t, pool.GetHistogramBucketSlice, pool.PutHistogramBucketSlice, pool, HistogramBucketSize, reg)
+	})
+
+	t.Run("[]int", func(t *testing.T) {
+		reg, metric := createRejectedMetric()
+		pool := NewLimitingPool(0, metric)
+		testUnlimitedPool(t, pool.GetIntSlice, pool.PutIntSlice, pool, IntSize, reg)
+	})
 }
 
 func testUnlimitedPool[E any, S ~[]E](t *testing.T, get func(int) (S, error), put func(S), pool *LimitingPool, elementSize uint64, reg *prometheus.Registry) {
@@ -132,6 +146,18 @@ func TestLimitingPool_Limited(t *testing.T) {
 		pool := NewLimitingPool(11*HistogramPointerSize, metric)
 		testLimitedPool(t, pool.GetHistogramPointerSlice, pool.PutHistogramPointerSlice, pool, HistogramPointerSize, reg)
 	})
+
+	t.Run("[]types.HistogramBucket", func(t *testing.T) {
+		reg, metric := createRejectedMetric()
+		pool := NewLimitingPool(11*HistogramBucketSize, metric)
+		testLimitedPool(t, pool.GetHistogramBucketSlice, pool.PutHistogramBucketSlice, pool, HistogramBucketSize, reg)
+	})
+
+	t.Run("[]int", func(t *testing.T) {
+		reg, metric := createRejectedMetric()
+		pool := NewLimitingPool(11*IntSize, metric)
+		testLimitedPool(t, pool.GetIntSlice, pool.PutIntSlice, pool, IntSize, reg)
+	})
 }
 
 func testLimitedPool[E any, S ~[]E](t *testing.T, get func(int) (S, error), put func(S), pool *LimitingPool, elementSize uint64, reg *prometheus.Registry) {
@@ -251,6 +277,36 @@ func TestLimitingPool_ClearsReturnedSlices(t *testing.T) {
 		HistogramPointerSlice = HistogramPointerSlice[:2]
 		require.Equal(t, []*histogram.FloatHistogram{nil, nil}, HistogramPointerSlice)
 	})
+
+	t.Run("[]types.HistogramBucket", func(t *testing.T) {
+		bucketSlice, err := pool.GetHistogramBucketSlice(2)
+		require.NoError(t, err)
+		bucketSlice = bucketSlice[:2]
+		bucketSlice[0] = types.HistogramBucket{UpperBound: 1, Count: 2}
+		bucketSlice[1] = types.HistogramBucket{UpperBound: 3, Count: 4}
+
+		pool.PutHistogramBucketSlice(bucketSlice)
+
+		bucketSlice, err = pool.GetHistogramBucketSlice(2)
+		require.NoError(t, err)
+		bucketSlice = bucketSlice[:2]
+		require.Equal(t, []types.HistogramBucket{{}, {}}, bucketSlice)
+	})
+
+	t.Run("[]int", func(t *testing.T) {
+		intSlice, err := pool.GetIntSlice(2)
+		require.NoError(t, err)
+		intSlice = intSlice[:2]
+		intSlice[0] = 123
+		intSlice[1] = 456
+
+		pool.PutIntSlice(intSlice)
+
+		intSlice, err = pool.GetIntSlice(2)
+		require.NoError(t, err)
+		intSlice = intSlice[:2]
+		require.Equal(t, []int{0, 0}, intSlice)
+	})
 }
 
 func createRejectedMetric() (*prometheus.Registry, prometheus.Counter) {
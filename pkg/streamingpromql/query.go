@@ -42,6 +42,9 @@ type Query struct {
 	cancel    context.CancelCauseFunc
 	pool      *pooling.LimitingPool
 
+	// nil if this query is evaluated in a single goroutine.
+	concurrencyLimiter *operators.ConcurrencyLimiter
+
 	result *promql.Result
 }
 
@@ -55,6 +58,11 @@ func newQuery(ctx context.Context, queryable storage.Queryable, opts promql.Quer
 		return nil, err
 	}
 
+	maxConcurrency, err := engine.limitsProvider.GetMaxConcurrencyPerQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	expr, err := parser.ParseExpr(qs)
 	if err != nil {
 		return nil, err
@@ -68,6 +76,8 @@ func newQuery(ctx context.Context, queryable storage.Queryable, opts promql.Quer
 		engine:    engine,
 		qs:        qs,
 		pool:      pooling.NewLimitingPool(maxInMemorySamples, engine.queriesRejectedDueToPeakMemoryConsumption),
+
+		concurrencyLimiter: operators.NewConcurrencyLimiter(maxConcurrency),
 		statement: &parser.EvalStmt{
 			Expr:          expr,
 			Start:         start,
@@ -82,7 +92,7 @@ func newQuery(ctx context.Context, queryable storage.Queryable, opts promql.Quer
 			return nil, fmt.Errorf("query expression produces a %s, but expression for range queries must produce an instant vector or scalar", parser.DocumentedType(expr.Type()))
 		}
 	}
-	q.root, err = q.convertToOperator(expr)
+	q.root, err = q.convertToOperator(expr, q.queryTimeRange())
 	if err != nil {
 		return nil, err
 	}
@@ -90,28 +100,44 @@ func newQuery(ctx context.Context, queryable storage.Queryable, opts promql.Quer
 	return q, nil
 }
 
-func (q *Query) convertToOperator(expr parser.Expr) (types.Operator, error) {
+// timeRange is the time range and interval over which an expression is evaluated.
+//
+// This is the time range of the query for most expressions, but expressions inside a subquery are evaluated over
+// the time range of the subquery.
+type timeRange struct {
+	start    time.Time
+	end      time.Time
+	interval time.Duration // Set to 1ms for instant queries, to simplify loop conditions in operators.
+}
+
+func (q *Query) queryTimeRange() timeRange {
+	interval := q.statement.Interval
+
+	if q.IsInstant() {
+		interval = time.Millisecond
+	}
+
+	return timeRange{start: q.statement.Start, end: q.statement.End, interval: interval}
+}
+
+func (q *Query) convertToOperator(expr parser.Expr, tr timeRange) (types.Operator, error) {
 	switch expr.Type() {
 	case parser.ValueTypeMatrix:
-		return q.convertToRangeVectorOperator(expr)
+		return q.convertToRangeVectorOperator(expr, tr)
 	case parser.ValueTypeVector:
-		return q.convertToInstantVectorOperator(expr)
+		return q.convertToInstantVectorOperator(expr, tr)
+	case parser.ValueTypeScalar:
+		return q.convertToScalarOperator(expr, tr)
 	default:
 		return nil, compat.NewNotSupportedError(fmt.Sprintf("%s value as top-level expression", parser.DocumentedType(expr.Type())))
 	}
 }
 
-func (q *Query) convertToInstantVectorOperator(expr parser.Expr) (types.InstantVectorOperator, error) {
+func (q *Query) convertToInstantVectorOperator(expr parser.Expr, tr timeRange) (types.InstantVectorOperator, error) {
 	if expr.Type() != parser.ValueTypeVector {
 		return nil, fmt.Errorf("cannot create instant vector operator for expression that produces a %s", parser.DocumentedType(expr.Type()))
 	}
 
-	interval := q.statement.Interval
-
-	if q.IsInstant() {
-		interval = time.Millisecond
-	}
-
 	switch e := expr.(type) {
 	case *parser.VectorSelector:
 		lookbackDelta := q.opts.LookbackDelta()
@@ -119,141 +145,356 @@ func (q *Query) convertToInstantVectorOperator(expr parser.Expr) (types.InstantV
 			lookbackDelta = q.engine.lookbackDelta
 		}
 
-		if e.OriginalOffset != 0 || e.Offset != 0 {
-			return nil, compat.NewNotSupportedError("instant vector selector with 'offset'")
-		}
-
 		return &operators.InstantVectorSelector{
 			Pool: q.pool,
 			Selector: &operators.Selector{
 				Queryable:     q.queryable,
-				Start:         timestamp.FromTime(q.statement.Start),
-				End:           timestamp.FromTime(q.statement.End),
+				Start:         timestamp.FromTime(tr.start),
+				End:           timestamp.FromTime(tr.end),
 				Timestamp:     e.Timestamp,
-				Interval:      interval.Milliseconds(),
+				Offset:        e.OriginalOffset.Milliseconds(),
+				Interval:      tr.interval.Milliseconds(),
 				LookbackDelta: lookbackDelta,
 				Matchers:      e.LabelMatchers,
 			},
 		}, nil
 	case *parser.AggregateExpr:
-		if e.Op != parser.SUM {
-			return nil, compat.NewNotSupportedError(fmt.Sprintf("'%s' aggregation", e.Op))
+		inner, err := q.convertToInstantVectorOperator(e.Expr, tr)
+		if err != nil {
+			return nil, err
 		}
 
-		if e.Param != nil {
-			// Should be caught by the PromQL parser, but we check here for safety.
-			return nil, fmt.Errorf("unexpected parameter for %s aggregation: %s", e.Op, e.Param)
-		}
+		inner = q.prefetchSeries(inner)
 
-		if e.Without {
-			return nil, compat.NewNotSupportedError("grouping with 'without'")
-		}
+		switch e.Op {
+		case parser.TOPK, parser.BOTTOMK:
+			k, err := q.convertToScalarOperator(e.Param, tr)
+			if err != nil {
+				return nil, err
+			}
 
-		slices.Sort(e.Grouping)
+			return operators.NewTopKBottomK(inner, tr.start, tr.end, tr.interval, e.Grouping, e.Without, e.Op == parser.TOPK, k, q.pool), nil
+		case parser.QUANTILE:
+			quantile, err := q.convertToScalarOperator(e.Param, tr)
+			if err != nil {
+				return nil, err
+			}
 
-		inner, err := q.convertToInstantVectorOperator(e.Expr)
-		if err != nil {
-			return nil, err
-		}
+			return operators.NewQuantileAggregation(inner, tr.start, tr.end, tr.interval, e.Grouping, e.Without, quantile, q.pool), nil
+		case parser.COUNT_VALUES:
+			labelName, ok := unwrapParenAndStepInvariantExpr(e.Param).(*parser.StringLiteral)
+			if !ok {
+				// Should be caught by the PromQL parser, but we check here for safety.
+				return nil, fmt.Errorf("expected a string parameter for count_values, got %s", e.Param)
+			}
+
+			return operators.NewCountValues(inner, tr.start, tr.end, tr.interval, e.Grouping, e.Without, labelName.Val, q.pool), nil
+		default:
+			if e.Param != nil {
+				// Should be caught by the PromQL parser, but we check here for safety.
+				return nil, fmt.Errorf("unexpected parameter for %s aggregation: %s", e.Op, e.Param)
+			}
 
-		return operators.NewAggregation(
-			inner,
-			q.statement.Start,
-			q.statement.End,
-			interval,
-			e.Grouping,
-			q.pool,
-		), nil
+			return operators.NewAggregation(inner, tr.start, tr.end, tr.interval, e.Grouping, e.Without, e.Op, q.pool)
+		}
 	case *parser.Call:
-		return q.convertFunctionCallToOperator(e)
+		return q.convertFunctionCallToOperator(e, tr)
 	case *parser.BinaryExpr:
-		if e.LHS.Type() != parser.ValueTypeVector || e.RHS.Type() != parser.ValueTypeVector {
-			return nil, compat.NewNotSupportedError("binary expression with scalars")
+		// We only need to handle vector/vector and vector/scalar operations here:
+		// scalar/scalar operations produce a scalar, and are handled by convertToScalarOperator.
+		if e.LHS.Type() == parser.ValueTypeScalar || e.RHS.Type() == parser.ValueTypeScalar {
+			return q.convertVectorScalarBinaryExprToOperator(e, tr)
 		}
 
-		if e.VectorMatching.Card != parser.CardOneToOne {
-			return nil, compat.NewNotSupportedError(fmt.Sprintf("binary expression with %v matching", e.VectorMatching.Card))
+		lhs, err := q.convertToInstantVectorOperator(e.LHS, tr)
+		if err != nil {
+			return nil, err
 		}
 
-		lhs, err := q.convertToInstantVectorOperator(e.LHS)
+		rhs, err := q.convertToInstantVectorOperator(e.RHS, tr)
 		if err != nil {
 			return nil, err
 		}
 
-		rhs, err := q.convertToInstantVectorOperator(e.RHS)
+		lhs, rhs = q.prefetchSeries(lhs), q.prefetchSeries(rhs)
+
+		switch e.VectorMatching.Card {
+		case parser.CardOneToOne:
+			return operators.NewBinaryOperation(lhs, rhs, *e.VectorMatching, e.Op, e.ReturnBool, tr.start, tr.end, tr.interval, q.pool, q.concurrencyLimiter)
+		case parser.CardManyToOne, parser.CardOneToMany:
+			return operators.NewGroupedBinaryOperation(lhs, rhs, *e.VectorMatching, e.Op, e.ReturnBool, tr.start, tr.end, tr.interval, q.pool, q.concurrencyLimiter)
+		case parser.CardManyToMany:
+			switch e.Op {
+			case parser.LAND, parser.LUNLESS:
+				return operators.NewAndUnlessBinaryOperation(lhs, rhs, *e.VectorMatching, e.Op == parser.LUNLESS, tr.start, tr.end, tr.interval, q.pool, q.concurrencyLimiter), nil
+			case parser.LOR:
+				return operators.NewOrBinaryOperation(lhs, rhs, *e.VectorMatching, tr.start, tr.end, tr.interval, q.pool, q.concurrencyLimiter), nil
+			default:
+				return nil, compat.NewNotSupportedError(fmt.Sprintf("binary expression with '%s'", e.Op))
+			}
+		default:
+			return nil, compat.NewNotSupportedError(fmt.Sprintf("binary expression with %v matching", e.VectorMatching.Card))
+		}
+	case *parser.UnaryExpr:
+		inner, err := q.convertToInstantVectorOperator(e.Expr, tr)
 		if err != nil {
 			return nil, err
 		}
 
-		return operators.NewBinaryOperation(lhs, rhs, *e.VectorMatching, e.Op, q.pool)
+		switch e.Op {
+		case parser.ADD:
+			return inner, nil
+		case parser.SUB:
+			return &operators.DeduplicateAndMerge{
+				Inner: &operators.UnaryNegationOfInstantVector{Inner: inner, Pool: q.pool},
+				Pool:  q.pool,
+			}, nil
+		default:
+			return nil, compat.NewNotSupportedError(fmt.Sprintf("unary expression with '%s'", e.Op))
+		}
 	case *parser.StepInvariantExpr:
 		// One day, we'll do something smarter here.
-		return q.convertToInstantVectorOperator(e.Expr)
+		return q.convertToInstantVectorOperator(e.Expr, tr)
 	case *parser.ParenExpr:
-		return q.convertToInstantVectorOperator(e.Expr)
+		return q.convertToInstantVectorOperator(e.Expr, tr)
 	default:
 		return nil, compat.NewNotSupportedError(fmt.Sprintf("PromQL expression type %T", e))
 	}
 }
 
-func (q *Query) convertFunctionCallToOperator(e *parser.Call) (types.InstantVectorOperator, error) {
+// prefetchSeriesBufferSize is the maximum number of series each prefetching operator evaluates ahead of when
+// they are needed.
+const prefetchSeriesBufferSize = 16
+
+// prefetchSeries returns an operator that evaluates the series of o in another goroutine, ahead of when they are needed,
+// if this query may be evaluated by more than one goroutine.
+// Otherwise, it returns o unchanged.
+//
+// Series are never prefetched if the query has a memory consumption limit: the memory held by prefetched series
+// would depend on how far ahead each prefetching goroutine happened to get, and so whether or not the query exceeds
+// the limit would not be deterministic.
+func (q *Query) prefetchSeries(o types.InstantVectorOperator) types.InstantVectorOperator {
+	if q.concurrencyLimiter == nil || q.pool.MaxEstimatedMemoryConsumptionBytes > 0 {
+		return o
+	}
+
+	return &operators.SeriesPrefetcher{
+		Inner:              o,
+		Pool:               q.pool,
+		ConcurrencyLimiter: q.concurrencyLimiter,
+		BufferSize:         prefetchSeriesBufferSize,
+	}
+}
+
+func (q *Query) convertVectorScalarBinaryExprToOperator(e *parser.BinaryExpr, tr timeRange) (types.InstantVectorOperator, error) {
+	scalarExpr, vectorExpr := e.RHS, e.LHS
+	scalarIsLeftSide := e.LHS.Type() == parser.ValueTypeScalar
+
+	if scalarIsLeftSide {
+		scalarExpr, vectorExpr = e.LHS, e.RHS
+	}
+
+	scalar, err := q.convertToScalarOperator(scalarExpr, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := q.convertToInstantVectorOperator(vectorExpr, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := operators.NewVectorScalarBinaryOperation(scalar, vector, scalarIsLeftSide, e.Op, e.ReturnBool, tr.start, tr.interval, q.pool)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Op.IsComparisonOperator() && !e.ReturnBool {
+		// The metric name is retained, so there's no need to check for duplicate series.
+		return o, nil
+	}
+
+	return &operators.DeduplicateAndMerge{Inner: o, Pool: q.pool, MergeSeriesWithDistinctTimestamps: true}, nil
+}
+
+// unwrapParenAndStepInvariantExpr returns the expression wrapped by any parentheses or step invariant expressions.
+func unwrapParenAndStepInvariantExpr(expr parser.Expr) parser.Expr {
+	for {
+		switch e := expr.(type) {
+		case *parser.ParenExpr:
+			expr = e.Expr
+		case *parser.StepInvariantExpr:
+			expr = e.Expr
+		default:
+			return expr
+		}
+	}
+}
+
+func (q *Query) convertFunctionCallToOperator(e *parser.Call, tr timeRange) (types.InstantVectorOperator, error) {
 	factory, ok := instantVectorFunctionOperatorFactories[e.Func.Name]
 	if !ok {
 		return nil, compat.NewNotSupportedError(fmt.Sprintf("'%s' function", e.Func.Name))
 	}
 
+	args, err := q.convertFunctionArgsToOperators(e, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return factory(args, tr.start, tr.end, tr.interval, q.pool)
+}
+
+func (q *Query) convertFunctionArgsToOperators(e *parser.Call, tr timeRange) ([]types.Operator, error) {
 	args := make([]types.Operator, len(e.Args))
 	for i := range e.Args {
-		a, err := q.convertToOperator(e.Args[i])
+		a, err := q.convertToOperator(e.Args[i], tr)
 		if err != nil {
 			return nil, err
 		}
 		args[i] = a
 	}
 
-	return factory(args, q.pool)
+	return args, nil
 }
 
-func (q *Query) convertToRangeVectorOperator(expr parser.Expr) (types.RangeVectorOperator, error) {
-	if expr.Type() != parser.ValueTypeMatrix {
-		return nil, fmt.Errorf("cannot create range vector operator for expression that produces a %s", parser.DocumentedType(expr.Type()))
+func (q *Query) convertToScalarOperator(expr parser.Expr, tr timeRange) (types.ScalarOperator, error) {
+	if expr.Type() != parser.ValueTypeScalar {
+		return nil, fmt.Errorf("cannot create scalar operator for expression that produces a %s", parser.DocumentedType(expr.Type()))
 	}
 
 	switch e := expr.(type) {
-	case *parser.MatrixSelector:
-		vectorSelector := e.VectorSelector.(*parser.VectorSelector)
+	case *parser.NumberLiteral:
+		return operators.NewScalarConstant(e.Val, tr.start, tr.end, tr.interval, q.pool), nil
+	case *parser.Call:
+		factory, ok := scalarFunctionOperatorFactories[e.Func.Name]
+		if !ok {
+			return nil, compat.NewNotSupportedError(fmt.Sprintf("'%s' function", e.Func.Name))
+		}
 
-		if vectorSelector.OriginalOffset != 0 || vectorSelector.Offset != 0 {
-			return nil, compat.NewNotSupportedError("range vector selector with 'offset'")
+		args, err := q.convertFunctionArgsToOperators(e, tr)
+		if err != nil {
+			return nil, err
 		}
 
-		interval := q.statement.Interval
+		return factory(args, tr.start, tr.end, tr.interval, q.pool)
+	case *parser.BinaryExpr:
+		lhs, err := q.convertToScalarOperator(e.LHS, tr)
+		if err != nil {
+			return nil, err
+		}
 
-		if q.IsInstant() {
-			interval = time.Millisecond
+		rhs, err := q.convertToScalarOperator(e.RHS, tr)
+		if err != nil {
+			return nil, err
 		}
 
+		return operators.NewScalarScalarBinaryOperation(lhs, rhs, e.Op, q.pool)
+	case *parser.UnaryExpr:
+		inner, err := q.convertToScalarOperator(e.Expr, tr)
+		if err != nil {
+			return nil, err
+		}
+
+		switch e.Op {
+		case parser.ADD:
+			return inner, nil
+		case parser.SUB:
+			return &operators.UnaryNegationOfScalar{Inner: inner}, nil
+		default:
+			return nil, compat.NewNotSupportedError(fmt.Sprintf("unary expression with '%s'", e.Op))
+		}
+	case *parser.StepInvariantExpr:
+		// One day, we'll do something smarter here.
+		return q.convertToScalarOperator(e.Expr, tr)
+	case *parser.ParenExpr:
+		return q.convertToScalarOperator(e.Expr, tr)
+	default:
+		return nil, compat.NewNotSupportedError(fmt.Sprintf("PromQL expression type %T", e))
+	}
+}
+
+func (q *Query) convertToRangeVectorOperator(expr parser.Expr, tr timeRange) (types.RangeVectorOperator, error) {
+	if expr.Type() != parser.ValueTypeMatrix {
+		return nil, fmt.Errorf("cannot create range vector operator for expression that produces a %s", parser.DocumentedType(expr.Type()))
+	}
+
+	switch e := expr.(type) {
+	case *parser.MatrixSelector:
+		vectorSelector := e.VectorSelector.(*parser.VectorSelector)
+
 		return &operators.RangeVectorSelector{
 			Selector: &operators.Selector{
 				Queryable: q.queryable,
-				Start:     timestamp.FromTime(q.statement.Start),
-				End:       timestamp.FromTime(q.statement.End),
+				Start:     timestamp.FromTime(tr.start),
+				End:       timestamp.FromTime(tr.end),
 				Timestamp: vectorSelector.Timestamp,
-				Interval:  interval.Milliseconds(),
+				Offset:    vectorSelector.OriginalOffset.Milliseconds(),
+				Interval:  tr.interval.Milliseconds(),
 				Range:     e.Range,
 				Matchers:  vectorSelector.LabelMatchers,
 			},
 		}, nil
+	case *parser.SubqueryExpr:
+		inner, err := q.convertToInstantVectorOperator(e.Expr, q.subqueryTimeRange(e, tr))
+		if err != nil {
+			return nil, err
+		}
+
+		return &operators.Subquery{
+			Inner:             inner,
+			Pool:              q.pool,
+			Start:             timestamp.FromTime(tr.start),
+			End:               timestamp.FromTime(tr.end),
+			Interval:          tr.interval.Milliseconds(),
+			SubqueryTimestamp: e.Timestamp,
+			SubqueryOffset:    e.OriginalOffset.Milliseconds(),
+			SubqueryRange:     e.Range,
+		}, nil
 	case *parser.StepInvariantExpr:
 		// One day, we'll do something smarter here.
-		return q.convertToRangeVectorOperator(e.Expr)
+		return q.convertToRangeVectorOperator(e.Expr, tr)
 	case *parser.ParenExpr:
-		return q.convertToRangeVectorOperator(e.Expr)
+		return q.convertToRangeVectorOperator(e.Expr, tr)
 	default:
 		return nil, compat.NewNotSupportedError(fmt.Sprintf("PromQL expression type %T", e))
 	}
 }
 
+// subqueryTimeRange returns the time range over which the inner expression of subquery e should be evaluated, given
+// e is evaluated over parent.
+//
+// This follows the same rules as Prometheus' engine: steps are aligned to multiples of the subquery's step, starting
+// with the first aligned timestamp at or after the start of the range for the first parent step.
+func (q *Query) subqueryTimeRange(e *parser.SubqueryExpr, parent timeRange) timeRange {
+	offset := e.OriginalOffset.Milliseconds()
+	rangeMilliseconds := e.Range.Milliseconds()
+
+	parentStart := timestamp.FromTime(parent.start)
+	parentEnd := timestamp.FromTime(parent.end)
+
+	if e.Timestamp != nil {
+		parentStart = *e.Timestamp
+		parentEnd = *e.Timestamp
+	}
+
+	interval := e.Step.Milliseconds()
+	if interval == 0 {
+		interval = q.engine.noStepSubqueryIntervalFn(rangeMilliseconds)
+	}
+
+	start := interval * ((parentStart - offset - rangeMilliseconds) / interval)
+	if start < parentStart-offset-rangeMilliseconds {
+		start += interval
+	}
+
+	return timeRange{
+		start:    timestamp.Time(start),
+		end:      timestamp.Time(parentEnd - offset),
+		interval: time.Duration(interval) * time.Millisecond,
+	}
+}
+
 func (q *Query) IsInstant() bool {
 	return q.statement.Start == q.statement.End && q.statement.Interval == 0
 }
@@ -290,36 +531,60 @@ func (q *Query) Exec(ctx context.Context) *promql.Result {
 		q.engine.estimatedPeakMemoryConsumption.Observe(float64(q.pool.PeakEstimatedMemoryConsumptionBytes))
 	}()
 
-	series, err := q.root.SeriesMetadata(ctx)
-	if err != nil {
-		return &promql.Result{Err: err}
-	}
-	defer pooling.PutSeriesMetadataSlice(series)
-
 	switch q.statement.Expr.Type() {
 	case parser.ValueTypeMatrix:
-		v, err := q.populateMatrixFromRangeVectorOperator(ctx, q.root.(types.RangeVectorOperator), series)
+		root := q.root.(types.RangeVectorOperator)
+		series, err := root.SeriesMetadata(ctx)
+		if err != nil {
+			return &promql.Result{Err: err}
+		}
+		defer pooling.PutSeriesMetadataSlice(series)
+
+		v, err := q.populateMatrixFromRangeVectorOperator(ctx, root, series)
 		if err != nil {
 			return &promql.Result{Err: err}
 		}
 
 		q.result = &promql.Result{Value: v}
 	case parser.ValueTypeVector:
+		root := q.root.(types.InstantVectorOperator)
+		series, err := root.SeriesMetadata(ctx)
+		if err != nil {
+			return &promql.Result{Err: err}
+		}
+		defer pooling.PutSeriesMetadataSlice(series)
+
 		if q.IsInstant() {
-			v, err := q.populateVectorFromInstantVectorOperator(ctx, q.root.(types.InstantVectorOperator), series)
+			v, err := q.populateVectorFromInstantVectorOperator(ctx, root, series)
 			if err != nil {
 				return &promql.Result{Err: err}
 			}
 
 			q.result = &promql.Result{Value: v}
 		} else {
-			v, err := q.populateMatrixFromInstantVectorOperator(ctx, q.root.(types.InstantVectorOperator), series)
+			v, err := q.populateMatrixFromInstantVectorOperator(ctx, root, series)
 			if err != nil {
 				return &promql.Result{Err: err}
 			}
 
 			q.result = &promql.Result{Value: v}
 		}
+	case parser.ValueTypeScalar:
+		d, err := q.root.(types.ScalarOperator).GetValues(ctx)
+		if err != nil {
+			return &promql.Result{Err: err}
+		}
+
+		if q.IsInstant() {
+			v, err := q.populateScalarFromScalarOperator(d)
+			if err != nil {
+				return &promql.Result{Err: err}
+			}
+
+			q.result = &promql.Result{Value: v}
+		} else {
+			q.result = &promql.Result{Value: q.populateMatrixFromScalarOperator(d)}
+		}
 	default:
 		// This should be caught in newQuery above.
 		return &promql.Result{Err: compat.NewNotSupportedError(fmt.Sprintf("unsupported result type %s", parser.DocumentedType(q.statement.Expr.Type())))}
@@ -408,8 +673,10 @@ func (q *Query) populateMatrixFromInstantVectorOperator(ctx context.Context, o t
 
 func (q *Query) populateMatrixFromRangeVectorOperator(ctx context.Context, o types.RangeVectorOperator, series []types.SeriesMetadata) (promql.Matrix, error) {
 	m := pooling.GetMatrix(len(series))
-	b := types.NewRingBuffer(q.pool)
-	defer b.Close()
+	floatBuffer := types.NewFPointRingBuffer(q.pool)
+	defer floatBuffer.Close()
+	histogramBuffer := types.NewHPointRingBuffer(q.pool)
+	defer histogramBuffer.Close()
 
 	for i, s := range series {
 		err := o.NextSeries(ctx)
@@ -421,20 +688,27 @@ func (q *Query) populateMatrixFromRangeVectorOperator(ctx context.Context, o typ
 			return nil, err
 		}
 
-		b.Reset()
-		step, err := o.NextStepSamples(b)
+		floatBuffer.Reset()
+		histogramBuffer.Reset()
+		step, err := o.NextStepSamples(floatBuffer, histogramBuffer)
+		if err != nil {
+			return nil, err
+		}
+
+		floats, err := floatBuffer.CopyPoints(step.RangeEnd)
 		if err != nil {
 			return nil, err
 		}
 
-		floats, err := b.CopyPoints(step.RangeEnd)
+		histograms, err := histogramBuffer.CopyPoints(step.RangeEnd)
 		if err != nil {
 			return nil, err
 		}
 
 		m = append(m, promql.Series{
-			Metric: s.Labels,
-			Floats: floats,
+			Metric:     s.Labels,
+			Floats:     floats,
+			Histograms: histograms,
 		})
 	}
 
@@ -445,6 +719,28 @@ func (q *Query) populateMatrixFromRangeVectorOperator(ctx context.Context, o typ
 	return m, nil
 }
 
+func (q *Query) populateScalarFromScalarOperator(d types.ScalarData) (promql.Scalar, error) {
+	defer q.pool.PutFPointSlice(d.Samples)
+
+	if len(d.Samples) != 1 {
+		return promql.Scalar{}, fmt.Errorf("expected scalar for instant query to contain a single value, but got %v", len(d.Samples))
+	}
+
+	return promql.Scalar{
+		T: d.Samples[0].T,
+		V: d.Samples[0].F,
+	}, nil
+}
+
+func (q *Query) populateMatrixFromScalarOperator(d types.ScalarData) promql.Matrix {
+	m := pooling.GetMatrix(1)
+
+	return append(m, promql.Series{
+		Metric: labels.EmptyLabels(),
+		Floats: d.Samples,
+	})
+}
+
 func (q *Query) Close() {
 	if q.cancel != nil {
 		q.cancel(errQueryClosed)
@@ -464,6 +760,8 @@ func (q *Query) Close() {
 		pooling.PutMatrix(v)
 	case promql.Vector:
 		q.pool.PutVector(v)
+	case promql.Scalar:
+		// Nothing to do, we already returned the slice in populateScalarFromScalarOperator.
 	default:
 		panic(fmt.Sprintf("unknown result value type %T", q.result.Value))
 	}
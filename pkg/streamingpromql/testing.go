@@ -11,11 +11,12 @@ import (
 
 func NewTestEngineOpts() promql.EngineOpts {
 	return promql.EngineOpts{
-		Logger:               nil,
-		Reg:                  nil,
-		MaxSamples:           math.MaxInt,
-		Timeout:              100 * time.Second,
-		EnableAtModifier:     true,
-		EnableNegativeOffset: true,
+		Logger:                   nil,
+		Reg:                      nil,
+		MaxSamples:               math.MaxInt,
+		Timeout:                  100 * time.Second,
+		EnableAtModifier:         true,
+		EnableNegativeOffset:     true,
+		NoStepSubqueryIntervalFn: func(int64) int64 { return time.Minute.Milliseconds() },
 	}
 }
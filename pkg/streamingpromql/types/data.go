@@ -25,6 +25,19 @@ type InstantVectorSeriesData struct {
 	Histograms []promql.HPoint
 }
 
+// HistogramBucket is a single bucket of a classic histogram.
+type HistogramBucket struct {
+	UpperBound float64
+	Count      float64
+}
+
+type ScalarData struct {
+	// Samples contains the value of this scalar at each time step.
+	// Samples must be sorted in timestamp order, earliest timestamps first.
+	// Samples must not have duplicate timestamps.
+	Samples []promql.FPoint
+}
+
 // RangeVectorStepData contains the timestamps associated with a single time step produced by a
 // RangeVectorOperator.
 //
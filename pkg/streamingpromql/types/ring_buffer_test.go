@@ -6,102 +6,151 @@ import (
 	"math"
 	"testing"
 
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/stretchr/testify/require"
 )
 
+// ringBuffer is the interface shared by FPointRingBuffer and HPointRingBuffer, so that we can run the same tests against both.
+type ringBuffer[T any] interface {
+	DiscardPointsBefore(t int64)
+	UnsafePoints(maxT int64) (head []T, tail []T)
+	CopyPoints(maxT int64) ([]T, error)
+	ForEach(f func(p T))
+	Append(p T) error
+	Reset()
+	First() T
+	LastAtOrBefore(maxT int64) (T, bool)
+	AnyAtOrBefore(maxT int64) bool
+
+	// Used by tests to check the internal state of the buffer.
+	getPoints() []T
+	getFirstIndex() int
+}
+
+func (b *FPointRingBuffer) getPoints() []promql.FPoint { return b.points }
+func (b *FPointRingBuffer) getFirstIndex() int         { return b.firstIndex }
+func (b *HPointRingBuffer) getPoints() []promql.HPoint { return b.points }
+func (b *HPointRingBuffer) getFirstIndex() int         { return b.firstIndex }
+
+func fPoint(t int64, f float64) promql.FPoint {
+	return promql.FPoint{T: t, F: f}
+}
+
+func hPoint(t int64, f float64) promql.HPoint {
+	return promql.HPoint{T: t, H: &histogram.FloatHistogram{Count: f}}
+}
+
 func TestRingBuffer(t *testing.T) {
-	buf := NewRingBuffer(poolForRingBufferTesting{})
+	t.Run("FPointRingBuffer", func(t *testing.T) {
+		testRingBuffer[promql.FPoint](t, NewFPointRingBuffer(poolForRingBufferTesting{}), fPoint)
+	})
+
+	t.Run("HPointRingBuffer", func(t *testing.T) {
+		testRingBuffer[promql.HPoint](t, NewHPointRingBuffer(poolForRingBufferTesting{}), hPoint)
+	})
+}
+
+func testRingBuffer[T any](t *testing.T, buf ringBuffer[T], point func(t int64, f float64) T) {
 	shouldHaveNoPoints(t, buf)
 
 	buf.DiscardPointsBefore(1) // Should handle empty buffer.
 	shouldHaveNoPoints(t, buf)
 
-	require.NoError(t, buf.Append(promql.FPoint{T: 1, F: 100}))
-	shouldHavePoints(t, buf, promql.FPoint{T: 1, F: 100})
+	require.NoError(t, buf.Append(point(1, 100)))
+	shouldHavePoints(t, buf, point(1, 100))
 
-	require.NoError(t, buf.Append(promql.FPoint{T: 2, F: 200}))
-	shouldHavePoints(t, buf, promql.FPoint{T: 1, F: 100}, promql.FPoint{T: 2, F: 200})
+	require.NoError(t, buf.Append(point(2, 200)))
+	shouldHavePoints(t, buf, point(1, 100), point(2, 200))
 
 	buf.DiscardPointsBefore(1)
-	shouldHavePoints(t, buf, promql.FPoint{T: 1, F: 100}, promql.FPoint{T: 2, F: 200}) // No change.
+	shouldHavePoints(t, buf, point(1, 100), point(2, 200)) // No change.
 
 	buf.DiscardPointsBefore(2)
-	shouldHavePoints(t, buf, promql.FPoint{T: 2, F: 200})
+	shouldHavePoints(t, buf, point(2, 200))
 
-	require.NoError(t, buf.Append(promql.FPoint{T: 3, F: 300}))
-	shouldHavePoints(t, buf, promql.FPoint{T: 2, F: 200}, promql.FPoint{T: 3, F: 300})
+	require.NoError(t, buf.Append(point(3, 300)))
+	shouldHavePoints(t, buf, point(2, 200), point(3, 300))
 
 	buf.DiscardPointsBefore(4)
 	shouldHaveNoPoints(t, buf)
 
-	require.NoError(t, buf.Append(promql.FPoint{T: 4, F: 400}))
-	require.NoError(t, buf.Append(promql.FPoint{T: 5, F: 500}))
-	shouldHavePoints(t, buf, promql.FPoint{T: 4, F: 400}, promql.FPoint{T: 5, F: 500})
+	require.NoError(t, buf.Append(point(4, 400)))
+	require.NoError(t, buf.Append(point(5, 500)))
+	shouldHavePoints(t, buf, point(4, 400), point(5, 500))
 
 	// Trigger expansion of buffer (we resize in powers of two, and the underlying slice comes from a pool that uses a factor of 2 as well).
 	// Ideally we wouldn't reach into the internals here, but this helps ensure the test is testing the correct scenario.
-	require.Len(t, buf.points, 2, "expected underlying slice to have length 2, if this assertion fails, the test setup is not as expected")
-	require.Equal(t, 2, cap(buf.points), "expected underlying slice to have capacity 2, if this assertion fails, the test setup is not as expected")
-	require.NoError(t, buf.Append(promql.FPoint{T: 6, F: 600}))
-	require.NoError(t, buf.Append(promql.FPoint{T: 7, F: 700}))
-	require.Greater(t, cap(buf.points), 2, "expected underlying slice to be expanded, if this assertion fails, the test setup is not as expected")
+	require.Len(t, buf.getPoints(), 2, "expected underlying slice to have length 2, if this assertion fails, the test setup is not as expected")
+	require.Equal(t, 2, cap(buf.getPoints()), "expected underlying slice to have capacity 2, if this assertion fails, the test setup is not as expected")
+	require.NoError(t, buf.Append(point(6, 600)))
+	require.NoError(t, buf.Append(point(7, 700)))
+	require.Greater(t, cap(buf.getPoints()), 2, "expected underlying slice to be expanded, if this assertion fails, the test setup is not as expected")
 
 	shouldHavePoints(t,
 		buf,
-		promql.FPoint{T: 4, F: 400},
-		promql.FPoint{T: 5, F: 500},
-		promql.FPoint{T: 6, F: 600},
-		promql.FPoint{T: 7, F: 700},
+		point(4, 400),
+		point(5, 500),
+		point(6, 600),
+		point(7, 700),
 	)
 
 	buf.Reset()
 	shouldHaveNoPoints(t, buf)
 
-	require.NoError(t, buf.Append(promql.FPoint{T: 9, F: 900}))
-	shouldHavePoints(t, buf, promql.FPoint{T: 9, F: 900})
+	require.NoError(t, buf.Append(point(9, 900)))
+	shouldHavePoints(t, buf, point(9, 900))
 }
 
 func TestRingBuffer_DiscardPointsBefore_ThroughWrapAround(t *testing.T) {
+	t.Run("FPointRingBuffer", func(t *testing.T) {
+		testDiscardPointsBeforeThroughWrapAround[promql.FPoint](t, NewFPointRingBuffer(poolForRingBufferTesting{}), fPoint)
+	})
+
+	t.Run("HPointRingBuffer", func(t *testing.T) {
+		testDiscardPointsBeforeThroughWrapAround[promql.HPoint](t, NewHPointRingBuffer(poolForRingBufferTesting{}), hPoint)
+	})
+}
+
+func testDiscardPointsBeforeThroughWrapAround[T any](t *testing.T, buf ringBuffer[T], point func(t int64, f float64) T) {
 	// Set up the buffer so that the first point is part-way through the underlying slice.
 	// We resize in powers of two, and the underlying slice comes from a pool that uses a factor of 2 as well.
-	buf := NewRingBuffer(poolForRingBufferTesting{})
-	require.NoError(t, buf.Append(promql.FPoint{T: 1, F: 100}))
-	require.NoError(t, buf.Append(promql.FPoint{T: 2, F: 200}))
-	require.NoError(t, buf.Append(promql.FPoint{T: 3, F: 300}))
-	require.NoError(t, buf.Append(promql.FPoint{T: 4, F: 400}))
+	require.NoError(t, buf.Append(point(1, 100)))
+	require.NoError(t, buf.Append(point(2, 200)))
+	require.NoError(t, buf.Append(point(3, 300)))
+	require.NoError(t, buf.Append(point(4, 400)))
 
 	// Ideally we wouldn't reach into the internals here, but this helps ensure the test is testing the correct scenario.
-	require.Len(t, buf.points, 4, "expected underlying slice to have length 4, if this assertion fails, the test setup is not as expected")
-	require.Equal(t, 4, cap(buf.points), "expected underlying slice to have capacity 4, if this assertion fails, the test setup is not as expected")
+	require.Len(t, buf.getPoints(), 4, "expected underlying slice to have length 4, if this assertion fails, the test setup is not as expected")
+	require.Equal(t, 4, cap(buf.getPoints()), "expected underlying slice to have capacity 4, if this assertion fails, the test setup is not as expected")
 	buf.DiscardPointsBefore(3)
-	require.NoError(t, buf.Append(promql.FPoint{T: 5, F: 500}))
-	require.NoError(t, buf.Append(promql.FPoint{T: 6, F: 600}))
+	require.NoError(t, buf.Append(point(5, 500)))
+	require.NoError(t, buf.Append(point(6, 600)))
 
 	// Should not have expanded slice.
-	require.Len(t, buf.points, 4, "expected underlying slice to have length 4")
-	require.Equal(t, 4, cap(buf.points), "expected underlying slice to have capacity 4")
+	require.Len(t, buf.getPoints(), 4, "expected underlying slice to have length 4")
+	require.Equal(t, 4, cap(buf.getPoints()), "expected underlying slice to have capacity 4")
 
 	// Discard before end of underlying slice.
 	buf.DiscardPointsBefore(4)
 	shouldHavePoints(t,
 		buf,
-		promql.FPoint{T: 4, F: 400},
-		promql.FPoint{T: 5, F: 500},
-		promql.FPoint{T: 6, F: 600},
+		point(4, 400),
+		point(5, 500),
+		point(6, 600),
 	)
 
-	require.Equal(t, 3, buf.firstIndex, "expected first point to be in middle of underlying slice, if this assertion fails, the test setup is not as expected")
+	require.Equal(t, 3, buf.getFirstIndex(), "expected first point to be in middle of underlying slice, if this assertion fails, the test setup is not as expected")
 
 	// Discard after wraparound.
 	buf.DiscardPointsBefore(6)
 	shouldHavePoints(t,
 		buf,
-		promql.FPoint{T: 6, F: 600},
+		point(6, 600),
 	)
 }
 
-func shouldHaveNoPoints(t *testing.T, buf *RingBuffer) {
+func shouldHaveNoPoints[T any](t *testing.T, buf ringBuffer[T]) {
 	shouldHavePoints(
 		t,
 		buf,
@@ -109,10 +158,10 @@ func shouldHaveNoPoints(t *testing.T, buf *RingBuffer) {
 	)
 }
 
-func shouldHavePoints(t *testing.T, buf *RingBuffer, expected ...promql.FPoint) {
-	var pointsFromForEach []promql.FPoint
+func shouldHavePoints[T any](t *testing.T, buf ringBuffer[T], expected ...T) {
+	var pointsFromForEach []T
 
-	buf.ForEach(func(p promql.FPoint) {
+	buf.ForEach(func(p T) {
 		pointsFromForEach = append(pointsFromForEach, p)
 	})
 
@@ -126,7 +175,7 @@ func shouldHavePoints(t *testing.T, buf *RingBuffer, expected ...promql.FPoint)
 		require.Equal(t, expected[0], buf.First())
 		// We test LastAtOrBefore() below.
 
-		lastPointT := expected[len(expected)-1].T
+		lastPointT := timestampOf(expected[len(expected)-1])
 
 		shouldHavePointsAtOrBeforeTime(t, buf, lastPointT, expected...)
 		shouldHavePointsAtOrBeforeTime(t, buf, lastPointT+1, expected...)
@@ -134,7 +183,7 @@ func shouldHavePoints(t *testing.T, buf *RingBuffer, expected ...promql.FPoint)
 	}
 }
 
-func shouldHavePointsAtOrBeforeTime(t *testing.T, buf *RingBuffer, ts int64, expected ...promql.FPoint) {
+func shouldHavePointsAtOrBeforeTime[T any](t *testing.T, buf ringBuffer[T], ts int64, expected ...T) {
 	head, tail := buf.UnsafePoints(ts)
 	combinedPoints := append(head, tail...)
 
@@ -148,6 +197,8 @@ func shouldHavePointsAtOrBeforeTime(t *testing.T, buf *RingBuffer, ts int64, exp
 	require.NoError(t, err)
 	require.Equal(t, expected, copiedPoints)
 
+	require.Equal(t, len(expected) > 0, buf.AnyAtOrBefore(ts))
+
 	end, present := buf.LastAtOrBefore(ts)
 
 	if len(expected) == 0 {
@@ -158,7 +209,18 @@ func shouldHavePointsAtOrBeforeTime(t *testing.T, buf *RingBuffer, ts int64, exp
 	}
 }
 
-// poolForRingBufferTesting is a dummy pool implementation for testing RingBuffer.
+func timestampOf[T any](p T) int64 {
+	switch p := any(p).(type) {
+	case promql.FPoint:
+		return p.T
+	case promql.HPoint:
+		return p.T
+	default:
+		panic("unknown point type")
+	}
+}
+
+// poolForRingBufferTesting is a dummy pool implementation for testing FPointRingBuffer and HPointRingBuffer.
 //
 // This helps ensure that the tests behave as expected: the default global pool does not guarantee that
 // slices returned have exactly the capacity requested. Instead, it only guarantees that slices have
@@ -173,3 +235,11 @@ func (p poolForRingBufferTesting) GetFPointSlice(size int) ([]promql.FPoint, err
 func (p poolForRingBufferTesting) PutFPointSlice(_ []promql.FPoint) {
 	// Drop slice on the floor - we don't need it.
 }
+
+func (p poolForRingBufferTesting) GetHPointSlice(size int) ([]promql.HPoint, error) {
+	return make([]promql.HPoint, 0, size), nil
+}
+
+func (p poolForRingBufferTesting) PutHPointSlice(_ []promql.HPoint) {
+	// Drop slice on the floor - we don't need it.
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package util
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+const (
+	// CostAttributionOverflowValue is the cost attribution of series whose label value exceeds the cardinality limit.
+	CostAttributionOverflowValue = "__overflow__"
+
+	// CostAttributionMissingValue is the cost attribution of series without the cost attribution label.
+	CostAttributionMissingValue = "__missing__"
+)
+
+// CostAttributionTracker attributes series to the values of a tenant's cost attribution label,
+// capping the number of distinct values. Once the cap is reached, series with new values
+// are attributed to CostAttributionOverflowValue.
+type CostAttributionTracker struct {
+	label     string
+	maxValues int
+
+	mu               sync.RWMutex
+	values           map[string]*costAttributionValue
+	overflowLastSeen atomic.Int64 // Unix nanoseconds.
+}
+
+type costAttributionValue struct {
+	value    string
+	lastSeen atomic.Int64 // Unix nanoseconds.
+}
+
+// NewCostAttributionTracker returns a tracker for the given cost attribution label, or nil if the label is empty.
+// A maxValues of 0 disables the cardinality limit.
+func NewCostAttributionTracker(label string, maxValues int) *CostAttributionTracker {
+	if label == "" {
+		return nil
+	}
+	return &CostAttributionTracker{
+		label:     label,
+		maxValues: maxValues,
+		values:    map[string]*costAttributionValue{},
+	}
+}
+
+// Label returns the cost attribution label, or an empty string if t is nil.
+func (t *CostAttributionTracker) Label() string {
+	if t == nil {
+		return ""
+	}
+	return t.label
+}
+
+// MaxValues returns the maximum number of distinct values, or 0 if t is nil.
+func (t *CostAttributionTracker) MaxValues() int {
+	if t == nil {
+		return 0
+	}
+	return t.maxValues
+}
+
+// Attribute returns the cost attribution of a series with the given value of the cost attribution label,
+// and records the attribution as seen at now. The returned string is safe to retain, even if labelValue isn't.
+func (t *CostAttributionTracker) Attribute(labelValue string, now time.Time) string {
+	if labelValue == "" {
+		labelValue = CostAttributionMissingValue
+	}
+	ts := now.UnixNano()
+
+	t.mu.RLock()
+	v := t.values[labelValue]
+	t.mu.RUnlock()
+	if v != nil {
+		v.lastSeen.Store(ts)
+		return v.value
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if v := t.values[labelValue]; v != nil {
+		v.lastSeen.Store(ts)
+		return v.value
+	}
+	if t.maxValues > 0 && len(t.values) >= t.maxValues {
+		t.overflowLastSeen.Store(ts)
+		return CostAttributionOverflowValue
+	}
+
+	v = &costAttributionValue{value: strings.Clone(labelValue)}
+	v.lastSeen.Store(ts)
+	t.values[v.value] = v
+	return v.value
+}
+
+// PurgeInactive forgets the attributions which haven't been seen since the deadline, and returns them.
+func (t *CostAttributionTracker) PurgeInactive(deadline time.Time) []string {
+	deadlineNanos := deadline.UnixNano()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var purged []string
+	for value, v := range t.values {
+		if v.lastSeen.Load() < deadlineNanos {
+			delete(t.values, value)
+			purged = append(purged, value)
+		}
+	}
+	if lastSeen := t.overflowLastSeen.Load(); lastSeen > 0 && lastSeen < deadlineNanos {
+		t.overflowLastSeen.Store(0)
+		purged = append(purged, CostAttributionOverflowValue)
+	}
+	return purged
+}
+
+// Retain forgets the attributions for which keep returns false.
+func (t *CostAttributionTracker) Retain(keep func(value string) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for value := range t.values {
+		if !keep(value) {
+			delete(t.values, value)
+		}
+	}
+}
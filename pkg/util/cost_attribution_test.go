@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostAttributionTracker(t *testing.T) {
+	require.Nil(t, NewCostAttributionTracker("", 10))
+
+	tracker := NewCostAttributionTracker("team", 2)
+	require.Equal(t, "team", tracker.Label())
+	require.Equal(t, 2, tracker.MaxValues())
+
+	require.Equal(t, "a", tracker.Attribute("a", time.Unix(0, 10)))
+	require.Equal(t, CostAttributionMissingValue, tracker.Attribute("", time.Unix(0, 15)))
+	require.Equal(t, CostAttributionOverflowValue, tracker.Attribute("b", time.Unix(0, 20)))
+	require.Equal(t, "a", tracker.Attribute("a", time.Unix(0, 25)))
+
+	require.Nil(t, tracker.PurgeInactive(time.Unix(0, 5)))
+	require.ElementsMatch(t, []string{CostAttributionMissingValue}, tracker.PurgeInactive(time.Unix(0, 16)))
+
+	// A value can be tracked once another one has been purged.
+	require.Equal(t, "b", tracker.Attribute("b", time.Unix(0, 30)))
+	require.ElementsMatch(t, []string{"a", CostAttributionOverflowValue}, tracker.PurgeInactive(time.Unix(0, 26)))
+
+	tracker.Retain(func(value string) bool { return value != "b" })
+	require.Equal(t, "c", tracker.Attribute("c", time.Unix(0, 35)))
+	require.Equal(t, "d", tracker.Attribute("d", time.Unix(0, 35)))
+	require.Equal(t, CostAttributionOverflowValue, tracker.Attribute("e", time.Unix(0, 35)))
+}
+
+func TestCostAttributionTracker_NoLimit(t *testing.T) {
+	tracker := NewCostAttributionTracker("team", 0)
+	for _, value := range []string{"a", "b", "c"} {
+		require.Equal(t, value, tracker.Attribute(value, time.Unix(0, 10)))
+	}
+}
@@ -28,6 +28,9 @@ const (
 	SampleTooFarInFuture                  ID = "too-far-in-future"
 	SampleTooFarInPast                    ID = "too-far-in-past"
 	MaxSeriesPerMetric                    ID = "max-series-per-metric"
+	MaxSeriesPerLabelValue                ID = "max-series-per-label-value"
+	MaxLabelValuesPerLabelName            ID = "max-label-values-per-label-name"
+	MaxSeriesCreationRate                 ID = "max-series-creation-rate"
 	MaxMetadataPerMetric                  ID = "max-metadata-per-metric"
 	MaxSeriesPerUser                      ID = "max-series-per-user"
 	MaxMetadataPerUser                    ID = "max-metadata-per-user"
@@ -46,6 +49,7 @@ const (
 	IngesterMaxInMemorySeries            ID = "ingester-max-series"
 	IngesterMaxInflightPushRequests      ID = "ingester-max-inflight-push-requests"
 	IngesterMaxInflightPushRequestsBytes ID = "ingester-max-inflight-push-requests-bytes"
+	IngesterHeapLimit                    ID = "ingester-heap-limit"
 
 	ExemplarLabelsMissing    ID = "exemplar-labels-missing"
 	ExemplarLabelsTooLong    ID = "exemplar-labels-too-long"
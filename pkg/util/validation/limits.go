@@ -30,6 +30,9 @@ import (
 
 const (
 	MaxSeriesPerMetricFlag                    = "ingester.max-global-series-per-metric"
+	MaxSeriesPerLabelValueFlag                = "ingester.max-global-series-per-label-value"
+	MaxLabelValuesPerLabelNameFlag            = "ingester.max-label-values-per-label-name"
+	MaxSeriesCreationRateFlag                 = "ingester.max-series-creation-rate"
 	MaxMetadataPerMetricFlag                  = "ingester.max-global-metadata-per-metric"
 	MaxSeriesPerUserFlag                      = "ingester.max-global-series-per-user"
 	MaxMetadataPerUserFlag                    = "ingester.max-global-metadata-per-user"
@@ -38,6 +41,7 @@ const (
 	MaxSeriesPerQueryFlag                     = "querier.max-fetched-series-per-query"
 	MaxEstimatedChunksPerQueryMultiplierFlag  = "querier.max-estimated-fetched-chunks-per-query-multiplier"
 	MaxEstimatedMemoryConsumptionPerQueryFlag = "querier.max-estimated-memory-consumption-per-query"
+	MaxConcurrencyPerQueryFlag                = "querier.max-concurrency-per-query"
 	MaxLabelNamesPerSeriesFlag                = "validation.max-label-names-per-series"
 	MaxLabelNameLengthFlag                    = "validation.max-length-label-name"
 	MaxLabelValueLengthFlag                   = "validation.max-length-label-value"
@@ -122,10 +126,23 @@ type Limits struct {
 	MetricRelabelConfigs                        []*relabel.Config   `yaml:"metric_relabel_configs,omitempty" json:"metric_relabel_configs,omitempty" doc:"nocli|description=List of metric relabel configurations. Note that in most situations, it is more effective to use metrics relabeling directly in the Prometheus server, e.g. remote_write.write_relabel_configs. Labels available during the relabeling phase and cleaned afterwards: __meta_tenant_id" category:"experimental"`
 	MetricRelabelingEnabled                     bool                `yaml:"metric_relabeling_enabled" json:"metric_relabeling_enabled" category:"experimental"`
 	ServiceOverloadStatusCodeOnRateLimitEnabled bool                `yaml:"service_overload_status_code_on_rate_limit_enabled" json:"service_overload_status_code_on_rate_limit_enabled" category:"experimental"`
+
+	StreamAggregationRules []*StreamAggregationRule `yaml:"stream_aggregation_rules,omitempty" json:"stream_aggregation_rules,omitempty" doc:"nocli|description=List of stream aggregation rules applied by the distributor after metric relabeling. The float samples of the series matching a rule selector are aggregated, without the rule labels, into the rule outputs (sum, count, max or rate), which are written once per rule interval as series named <metric>:<interval>[_without_<labels>]_<output>. The input series are dropped unless keep_input is true. Each output series is aggregated by the distributor owning it in the distributors ring, which the other distributors forward its input samples to." category:"experimental"`
+
+	ShadowMetricRelabelingEnabled bool                `yaml:"shadow_metric_relabeling_enabled" json:"shadow_metric_relabeling_enabled" category:"experimental"`
+	ShadowMetricRelabelConfigs    []*relabel.Config   `yaml:"shadow_metric_relabel_configs,omitempty" json:"shadow_metric_relabel_configs,omitempty" doc:"nocli|description=List of shadow metric relabel configurations. When shadow metric relabeling is enabled, the shadow metric relabel configs and shadow drop labels are evaluated alongside the active ones without modifying the ingested series, and the series they would drop, modify or newly create are reported. Labels available during the relabeling phase and cleaned afterwards: __meta_tenant_id" category:"experimental"`
+	ShadowDropLabels              flagext.StringSlice `yaml:"shadow_drop_labels" json:"shadow_drop_labels" category:"experimental"`
+
 	// Ingester enforced limits.
 	// Series
 	MaxGlobalSeriesPerUser   int `yaml:"max_global_series_per_user" json:"max_global_series_per_user"`
 	MaxGlobalSeriesPerMetric int `yaml:"max_global_series_per_metric" json:"max_global_series_per_metric"`
+	// Series per label
+	MaxGlobalSeriesPerLabelValue LimitsMap[int] `yaml:"max_global_series_per_label_value" json:"max_global_series_per_label_value" category:"experimental"`
+	MaxLabelValuesPerLabelName   LimitsMap[int] `yaml:"max_label_values_per_label_name" json:"max_label_values_per_label_name" category:"experimental"`
+	// Series creation
+	MaxSeriesCreationRate      float64 `yaml:"max_series_creation_rate" json:"max_series_creation_rate" category:"experimental"`
+	MaxSeriesCreationBurstSize int     `yaml:"max_series_creation_burst_size" json:"max_series_creation_burst_size" category:"experimental"`
 	// Metadata
 	MaxGlobalMetricsWithMetadataPerUser int `yaml:"max_global_metadata_per_user" json:"max_global_metadata_per_user"`
 	MaxGlobalMetadataPerMetric          int `yaml:"max_global_metadata_per_metric" json:"max_global_metadata_per_metric"`
@@ -138,16 +155,21 @@ type Limits struct {
 	// Max allowed time window for out-of-order samples.
 	OutOfOrderTimeWindow                 model.Duration `yaml:"out_of_order_time_window" json:"out_of_order_time_window" category:"experimental"`
 	OutOfOrderBlocksExternalLabelEnabled bool           `yaml:"out_of_order_blocks_external_label_enabled" json:"out_of_order_blocks_external_label_enabled" category:"experimental"`
+	ShipperSplitBlocksEnabled            bool           `yaml:"shipper_split_blocks_enabled" json:"shipper_split_blocks_enabled" category:"experimental"`
 
 	// User defined label to give the option of subdividing specific metrics by another label
 	SeparateMetricsGroupLabel string `yaml:"separate_metrics_group_label" json:"separate_metrics_group_label" category:"experimental"`
 
+	CostAttributionLabel                 string `yaml:"cost_attribution_label" json:"cost_attribution_label" category:"experimental"`
+	MaxCostAttributionCardinalityPerUser int    `yaml:"max_cost_attribution_cardinality_per_user" json:"max_cost_attribution_cardinality_per_user" category:"experimental"`
+
 	// Querier enforced limits.
 	MaxChunksPerQuery                     int            `yaml:"max_fetched_chunks_per_query" json:"max_fetched_chunks_per_query"`
 	MaxEstimatedChunksPerQueryMultiplier  float64        `yaml:"max_estimated_fetched_chunks_per_query_multiplier" json:"max_estimated_fetched_chunks_per_query_multiplier" category:"experimental"`
 	MaxFetchedSeriesPerQuery              int            `yaml:"max_fetched_series_per_query" json:"max_fetched_series_per_query"`
 	MaxFetchedChunkBytesPerQuery          int            `yaml:"max_fetched_chunk_bytes_per_query" json:"max_fetched_chunk_bytes_per_query"`
 	MaxEstimatedMemoryConsumptionPerQuery uint64         `yaml:"max_estimated_memory_consumption_per_query" json:"max_estimated_memory_consumption_per_query" category:"experimental"`
+	MaxConcurrencyPerQuery                int            `yaml:"max_concurrency_per_query" json:"max_concurrency_per_query" category:"experimental"`
 	MaxQueryLookback                      model.Duration `yaml:"max_query_lookback" json:"max_query_lookback"`
 	MaxPartialQueryLength                 model.Duration `yaml:"max_partial_query_length" json:"max_partial_query_length"`
 	MaxQueryParallelism                   int            `yaml:"max_query_parallelism" json:"max_query_parallelism"`
@@ -202,6 +224,8 @@ type Limits struct {
 	CompactorBlockUploadVerifyChunks      bool           `yaml:"compactor_block_upload_verify_chunks" json:"compactor_block_upload_verify_chunks"`
 	CompactorBlockUploadMaxBlockSizeBytes int64          `yaml:"compactor_block_upload_max_block_size_bytes" json:"compactor_block_upload_max_block_size_bytes" category:"advanced"`
 
+	CompactorSeriesRetentionRules []*SeriesRetentionRule `yaml:"compactor_series_retention_rules,omitempty" json:"compactor_series_retention_rules,omitempty" doc:"nocli|description=List of retention rules applied to the series matching the rule selector. The samples of the matching series older than the rule period are deleted by the compactor, which rewrites the blocks containing them, and filtered out by queriers. Each rule has a selector (for example {__name__=~\"debug_.*\"}) and a period (for example 7d)." category:"experimental"`
+
 	// This config doesn't have a CLI flag registered here because they're registered in
 	// their own original config struct.
 	S3SSEType                 string `yaml:"s3_sse_type" json:"s3_sse_type" doc:"nocli|description=S3 server-side encryption type. Required to enable server-side encryption overrides for a specific tenant. If not set, the default S3 client settings are used."`
@@ -225,7 +249,15 @@ type Limits struct {
 	AlertmanagerMaxAlertsSizeBytes             int `yaml:"alertmanager_max_alerts_size_bytes" json:"alertmanager_max_alerts_size_bytes"`
 
 	// OpenTelemetry
-	OTelMetricSuffixesEnabled bool `yaml:"otel_metric_suffixes_enabled" json:"otel_metric_suffixes_enabled" category:"advanced"`
+	OTelMetricSuffixesEnabled     bool                   `yaml:"otel_metric_suffixes_enabled" json:"otel_metric_suffixes_enabled" category:"advanced"`
+	OTelConvertDeltaToCumulative  bool                   `yaml:"otel_convert_delta_to_cumulative" json:"otel_convert_delta_to_cumulative" category:"experimental"`
+	OTelDeltaMaxTrackedSeries     int                    `yaml:"otel_delta_max_tracked_series" json:"otel_delta_max_tracked_series" category:"experimental"`
+	PromoteOTelResourceAttributes flagext.StringSliceCSV `yaml:"promote_otel_resource_attributes" json:"promote_otel_resource_attributes" category:"experimental"`
+	OTelDisableTargetInfo         bool                   `yaml:"otel_disable_target_info" json:"otel_disable_target_info" category:"experimental"`
+	OTelPromoteScopeMetadata      bool                   `yaml:"otel_promote_scope_metadata" json:"otel_promote_scope_metadata" category:"experimental"`
+
+	// Prometheus remote-write 2.0
+	RemoteWriteCreatedTimestampZeroIngestionEnabled bool `yaml:"remote_write_created_timestamp_zero_ingestion_enabled" json:"remote_write_created_timestamp_zero_ingestion_enabled" category:"experimental"`
 
 	// Ingest storage.
 	IngestStorageReadConsistency       string `yaml:"ingest_storage_read_consistency" json:"ingest_storage_read_consistency" category:"experimental" doc:"hidden"`
@@ -259,11 +291,29 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&l.PastGracePeriod, PastGracePeriodFlag, "Controls how far into the past incoming samples and exemplars are accepted compared to the wall clock. Any sample or exemplar will be rejected if its timestamp is lower than '(now - OOO window - past_grace_period)'. This configuration is enforced in the distributor and ingester. 0 to disable.")
 	f.BoolVar(&l.EnforceMetadataMetricName, "validation.enforce-metadata-metric-name", true, "Enforce every metadata has a metric name.")
 	f.BoolVar(&l.MetricRelabelingEnabled, "distributor.metric-relabeling-enabled", true, "Enable metric relabeling for the tenant. This configuration option can be used to forcefully disable metric relabeling on a per-tenant basis.")
+	f.BoolVar(&l.ShadowMetricRelabelingEnabled, "distributor.shadow-metric-relabeling-enabled", false, "Evaluate the shadow metric relabel configs and shadow drop labels of the tenant alongside the active ones, and report the series they would drop, modify or newly create, without modifying the ingested series.")
+	f.Var(&l.ShadowDropLabels, "distributor.shadow-drop-label", "Label names to drop in the shadow relabeling evaluated when shadow metric relabeling is enabled. Can be repeated in order to drop multiple labels.")
 	f.BoolVar(&l.ServiceOverloadStatusCodeOnRateLimitEnabled, "distributor.service-overload-status-code-on-rate-limit-enabled", false, "If enabled, rate limit errors will be reported to the client with HTTP status code 529 (Service is overloaded). If disabled, status code 429 (Too Many Requests) is used. Enabling -distributor.retry-after-header.enabled before utilizing this option is strongly recommended as it helps prevent premature request retries by the client.")
 	f.BoolVar(&l.OTelMetricSuffixesEnabled, "distributor.otel-metric-suffixes-enabled", false, "Whether to enable automatic suffixes to names of metrics ingested through OTLP.")
+	f.BoolVar(&l.OTelConvertDeltaToCumulative, "distributor.otel-convert-delta-to-cumulative", false, "Whether to convert OTLP delta sums and delta exponential histograms to cumulative ones, by keeping the running total of each series in the distributor. Data points of the same series should be sent in order to the same distributor. Data points which can't be converted are discarded.")
+	f.IntVar(&l.OTelDeltaMaxTrackedSeries, "distributor.otel-delta-max-tracked-series", 100000, "Maximum number of OTLP delta series whose running total is kept by each distributor for a tenant, when the conversion of OTLP delta metrics to cumulative ones is enabled. Data points of new series exceeding the limit are discarded. 0 to disable the limit.")
+	f.Var(&l.PromoteOTelResourceAttributes, "distributor.promote-otel-resource-attributes", "Comma-separated list of OTLP resource attributes to promote to labels of every series of the resource. Data point attributes take precedence over promoted resource attributes with the same name. Attributes which would exceed the label limits aren't promoted.")
+	f.BoolVar(&l.OTelDisableTargetInfo, "distributor.otel-disable-target-info", false, "Whether to disable the target_info metric, holding the resource attributes, for metrics ingested through OTLP.")
+	f.BoolVar(&l.OTelPromoteScopeMetadata, "distributor.otel-promote-scope-metadata", false, "Whether to add the otel_scope_name and otel_scope_version labels, and an otel_scope_<attribute> label for each instrumentation scope attribute, to every series of metrics ingested through OTLP. Attributes which would exceed the label limits aren't promoted.")
+	f.BoolVar(&l.RemoteWriteCreatedTimestampZeroIngestionEnabled, "distributor.remote-write-created-timestamp-zero-ingestion-enabled", false, "Whether to ingest the created timestamp of the series of Prometheus remote-write 2.0 requests as a zero sample at the created timestamp, when it's earlier than the first sample of the series in the request.")
 
 	f.IntVar(&l.MaxGlobalSeriesPerUser, MaxSeriesPerUserFlag, 150000, "The maximum number of in-memory series per tenant, across the cluster before replication. 0 to disable.")
 	f.IntVar(&l.MaxGlobalSeriesPerMetric, MaxSeriesPerMetricFlag, 0, "The maximum number of in-memory series per metric name, across the cluster before replication. 0 to disable.")
+	if !l.MaxGlobalSeriesPerLabelValue.IsInitialized() {
+		l.MaxGlobalSeriesPerLabelValue = NewLimitsMap[int](nil)
+	}
+	f.Var(&l.MaxGlobalSeriesPerLabelValue, MaxSeriesPerLabelValueFlag, "The maximum number of in-memory series with the same value of a label, across the cluster before replication. Value is a map, where each key is the label name and value is the maximum number of series with any given value of that label (int). On the command line, this map is given in a JSON format.")
+	if !l.MaxLabelValuesPerLabelName.IsInitialized() {
+		l.MaxLabelValuesPerLabelName = NewLimitsMap[int](nil)
+	}
+	f.Var(&l.MaxLabelValuesPerLabelName, MaxLabelValuesPerLabelNameFlag, "The maximum number of distinct values of a label in the in-memory series of each ingester. Value is a map, where each key is the label name and value is the maximum number of values of that label (int). On the command line, this map is given in a JSON format.")
+	f.Float64Var(&l.MaxSeriesCreationRate, MaxSeriesCreationRateFlag, 0, "The maximum number of new in-memory series created per second per tenant in each ingester. Samples of the series which can't be created are discarded. 0 to disable.")
+	f.IntVar(&l.MaxSeriesCreationBurstSize, "ingester.max-series-creation-burst-size", 1000, "The maximum number of new in-memory series that can be created at once per tenant in each ingester, when the series creation rate limit is enabled. When lower than the series creation rate limit, the rate limit is used.")
 
 	f.IntVar(&l.MaxGlobalMetricsWithMetadataPerUser, MaxMetadataPerUserFlag, 0, "The maximum number of in-memory metrics with metadata per tenant, across the cluster. 0 to disable.")
 	f.IntVar(&l.MaxGlobalMetadataPerMetric, MaxMetadataPerMetricFlag, 0, "The maximum number of metadata per metric, across the cluster. 0 to disable.")
@@ -272,14 +322,18 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&l.OutOfOrderTimeWindow, "ingester.out-of-order-time-window", fmt.Sprintf("Non-zero value enables out-of-order support for most recent samples that are within the time window in relation to the TSDB's maximum time, i.e., within [db.maxTime-timeWindow, db.maxTime]). The ingester will need more memory as a factor of rate of out-of-order samples being ingested and the number of series that are getting out-of-order samples. If query falls into this window, cached results will use value from -%s option to specify TTL for resulting cache entry.", resultsCacheTTLForOutOfOrderWindowFlag))
 	f.BoolVar(&l.NativeHistogramsIngestionEnabled, "ingester.native-histograms-ingestion-enabled", false, "Enable ingestion of native histogram samples. If false, native histogram samples are ignored without an error. To query native histograms with query-sharding enabled make sure to set -query-frontend.query-result-response-format to 'protobuf'.")
 	f.BoolVar(&l.OutOfOrderBlocksExternalLabelEnabled, "ingester.out-of-order-blocks-external-label-enabled", false, "Whether the shipper should label out-of-order blocks with an external label before uploading them. Setting this label will compact out-of-order blocks separately from non-out-of-order blocks")
+	f.BoolVar(&l.ShipperSplitBlocksEnabled, "ingester.shipper-split-blocks-enabled", false, "Whether the shipper should split blocks by series hash into the number of shards configured with -compactor.split-and-merge-shards before uploading them. The uploaded blocks have the same shard ID external label the compactor adds to split blocks, so the compactor can skip the split stage for them.")
 
 	f.StringVar(&l.SeparateMetricsGroupLabel, "validation.separate-metrics-group-label", "", "Label used to define the group label for metrics separation. For each write request, the group is obtained from the first non-empty group label from the first timeseries in the incoming list of timeseries. Specific distributor and ingester metrics will be further separated adding a 'group' label with group label's value. Currently applies to the following metrics: cortex_discarded_samples_total")
+	f.StringVar(&l.CostAttributionLabel, "validation.cost-attribution-label", "", "Label used to attribute the ingested series to a cost center. When set, the received and discarded samples in the distributor, and the active series in the ingester, are additionally tracked by the value of this label. Series without the label are attributed to "+`"__missing__"`+".")
+	f.IntVar(&l.MaxCostAttributionCardinalityPerUser, "validation.max-cost-attribution-cardinality-per-user", 100, "Maximum number of distinct values of the cost attribution label tracked per tenant. Series with further values are attributed to "+`"__overflow__"`+". 0 to disable the limit.")
 
 	f.IntVar(&l.MaxChunksPerQuery, MaxChunksPerQueryFlag, 2e6, "Maximum number of chunks that can be fetched in a single query from ingesters and store-gateways. This limit is enforced in the querier, ruler and store-gateway. 0 to disable.")
 	f.Float64Var(&l.MaxEstimatedChunksPerQueryMultiplier, MaxEstimatedChunksPerQueryMultiplierFlag, 0, "Maximum number of chunks estimated to be fetched in a single query from ingesters and store-gateways, as a multiple of -"+MaxChunksPerQueryFlag+". This limit is enforced in the querier. Must be greater than or equal to 1, or 0 to disable.")
 	f.IntVar(&l.MaxFetchedSeriesPerQuery, MaxSeriesPerQueryFlag, 0, "The maximum number of unique series for which a query can fetch samples from ingesters and store-gateways. This limit is enforced in the querier, ruler and store-gateway. 0 to disable")
 	f.IntVar(&l.MaxFetchedChunkBytesPerQuery, MaxChunkBytesPerQueryFlag, 0, "The maximum size of all chunks in bytes that a query can fetch from ingesters and store-gateways. This limit is enforced in the querier and ruler. 0 to disable.")
 	f.Uint64Var(&l.MaxEstimatedMemoryConsumptionPerQuery, MaxEstimatedMemoryConsumptionPerQueryFlag, 0, "The maximum estimated memory a single query can consume at once, in bytes. This limit is only enforced when Mimir's query engine is in use. This limit is enforced in the querier. 0 to disable.")
+	f.IntVar(&l.MaxConcurrencyPerQuery, MaxConcurrencyPerQueryFlag, 0, "The maximum number of goroutines that can evaluate a single query at once. Additional goroutines are used to evaluate independent parts of a query concurrently, such as both sides of a binary operation. Series are only evaluated ahead of when they are needed if -querier.max-estimated-memory-consumption-per-query is disabled. This limit is only enforced when Mimir's query engine is in use. This limit is enforced in the querier. 0 or 1 to evaluate each query in a single goroutine.")
 	f.Var(&l.MaxPartialQueryLength, MaxPartialQueryLengthFlag, "Limit the time range for partial queries at the querier level.")
 	f.Var(&l.MaxQueryLookback, "querier.max-query-lookback", "Limit how long back data (series and metadata) can be queried, up until <lookback> duration ago. This limit is enforced in the query-frontend, querier and ruler for instant, range and remote read queries. For metadata queries like series, label names, label values queries the limit is enforced in the querier and ruler. If the requested time range is outside the allowed range, the request will not fail but will be manipulated to only query data within the allowed time range. 0 to disable.")
 	f.IntVar(&l.MaxQueryParallelism, "querier.max-query-parallelism", 14, "Maximum number of split (by time) or partial (by shard) queries that will be scheduled in parallel by the query-frontend for a single input query. This limit is introduced to have a fairer query scheduling and avoid a single query over a large time range saturating all available queriers.")
@@ -396,6 +450,8 @@ func (l *Limits) unmarshal(decode func(any) error) error {
 		l.NotificationRateLimitPerIntegration = defaultLimits.NotificationRateLimitPerIntegration.Clone()
 		l.RulerMaxRulesPerRuleGroupByNamespace = defaultLimits.RulerMaxRulesPerRuleGroupByNamespace.Clone()
 		l.RulerMaxRuleGroupsPerTenantByNamespace = defaultLimits.RulerMaxRuleGroupsPerTenantByNamespace.Clone()
+		l.MaxGlobalSeriesPerLabelValue = defaultLimits.MaxGlobalSeriesPerLabelValue.Clone()
+		l.MaxLabelValuesPerLabelName = defaultLimits.MaxLabelValuesPerLabelName.Clone()
 	}
 
 	// Decode into a reflection-crafted struct that has fields for the extensions.
@@ -433,6 +489,30 @@ func (l *Limits) validate() error {
 		}
 	}
 
+	for _, cfg := range l.ShadowMetricRelabelConfigs {
+		if cfg == nil {
+			return errors.New("invalid shadow_metric_relabel_configs")
+		}
+	}
+
+	for _, rule := range l.StreamAggregationRules {
+		if rule == nil {
+			return errInvalidStreamAggregationRule
+		}
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range l.CompactorSeriesRetentionRules {
+		if rule == nil {
+			return errInvalidSeriesRetentionRule
+		}
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+
 	if l.MaxEstimatedChunksPerQueryMultiplier < 1 && l.MaxEstimatedChunksPerQueryMultiplier != 0 {
 		return errInvalidMaxEstimatedChunksPerQueryMultiplier
 	}
@@ -603,6 +683,28 @@ func (o *Overrides) MaxGlobalSeriesPerUser(userID string) int {
 	return o.getOverridesForUser(userID).MaxGlobalSeriesPerUser
 }
 
+// MaxGlobalSeriesPerLabelValue returns the maximum number of series allowed per value of each limited label name,
+// across the cluster. The returned map must not be modified.
+func (o *Overrides) MaxGlobalSeriesPerLabelValue(userID string) map[string]int {
+	return o.getOverridesForUser(userID).MaxGlobalSeriesPerLabelValue.data
+}
+
+// MaxLabelValuesPerLabelName returns the maximum number of values allowed per limited label name in each ingester.
+// The returned map must not be modified.
+func (o *Overrides) MaxLabelValuesPerLabelName(userID string) map[string]int {
+	return o.getOverridesForUser(userID).MaxLabelValuesPerLabelName.data
+}
+
+// MaxSeriesCreationRate returns the maximum number of new series created per second in each ingester.
+func (o *Overrides) MaxSeriesCreationRate(userID string) float64 {
+	return o.getOverridesForUser(userID).MaxSeriesCreationRate
+}
+
+// MaxSeriesCreationBurstSize returns the maximum number of new series created at once in each ingester.
+func (o *Overrides) MaxSeriesCreationBurstSize(userID string) int {
+	return o.getOverridesForUser(userID).MaxSeriesCreationBurstSize
+}
+
 // MaxGlobalSeriesPerMetric returns the maximum number of series allowed per metric across the cluster.
 func (o *Overrides) MaxGlobalSeriesPerMetric(userID string) int {
 	return o.getOverridesForUser(userID).MaxGlobalSeriesPerMetric
@@ -635,6 +737,12 @@ func (o *Overrides) MaxEstimatedMemoryConsumptionPerQuery(userID string) uint64
 	return o.getOverridesForUser(userID).MaxEstimatedMemoryConsumptionPerQuery
 }
 
+// MaxConcurrencyPerQuery returns the maximum number of goroutines that can evaluate a single query at once.
+// This is only effective when using Mimir's query engine (not Prometheus' engine).
+func (o *Overrides) MaxConcurrencyPerQuery(userID string) int {
+	return o.getOverridesForUser(userID).MaxConcurrencyPerQuery
+}
+
 // MaxQueryLookback returns the max lookback period of queries.
 func (o *Overrides) MaxQueryLookback(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).MaxQueryLookback)
@@ -747,11 +855,26 @@ func (o *Overrides) OutOfOrderBlocksExternalLabelEnabled(userID string) bool {
 	return o.getOverridesForUser(userID).OutOfOrderBlocksExternalLabelEnabled
 }
 
+// ShipperSplitBlocksEnabled returns whether the shipper splits blocks by the compactor shards before uploading them.
+func (o *Overrides) ShipperSplitBlocksEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).ShipperSplitBlocksEnabled
+}
+
 // SeparateMetricsGroupLabel returns the custom label used to separate specific metrics
 func (o *Overrides) SeparateMetricsGroupLabel(userID string) string {
 	return o.getOverridesForUser(userID).SeparateMetricsGroupLabel
 }
 
+// CostAttributionLabel returns the label used to attribute the ingested series to a cost center.
+func (o *Overrides) CostAttributionLabel(userID string) string {
+	return o.getOverridesForUser(userID).CostAttributionLabel
+}
+
+// MaxCostAttributionCardinalityPerUser returns the maximum number of distinct values of the cost attribution label tracked for a given user.
+func (o *Overrides) MaxCostAttributionCardinalityPerUser(userID string) int {
+	return o.getOverridesForUser(userID).MaxCostAttributionCardinalityPerUser
+}
+
 // IngestionTenantShardSize returns the ingesters shard size for a given user.
 func (o *Overrides) IngestionTenantShardSize(userID string) int {
 	return o.getOverridesForUser(userID).IngestionTenantShardSize
@@ -772,6 +895,11 @@ func (o *Overrides) CompactorBlocksRetentionPeriod(userID string) time.Duration
 	return time.Duration(o.getOverridesForUser(userID).CompactorBlocksRetentionPeriod)
 }
 
+// CompactorSeriesRetentionRules returns the per-series retention rules for a given user.
+func (o *Overrides) CompactorSeriesRetentionRules(userID string) []*SeriesRetentionRule {
+	return o.getOverridesForUser(userID).CompactorSeriesRetentionRules
+}
+
 // CompactorSplitAndMergeShards returns the number of shards to use when splitting blocks.
 func (o *Overrides) CompactorSplitAndMergeShards(userID string) int {
 	return o.getOverridesForUser(userID).CompactorSplitAndMergeShards
@@ -821,6 +949,27 @@ func (o *Overrides) MetricRelabelConfigs(userID string) []*relabel.Config {
 	return o.getOverridesForUser(userID).MetricRelabelConfigs
 }
 
+// ShadowMetricRelabelingEnabled returns whether the shadow metric relabel configs and shadow drop labels
+// are evaluated for a given user.
+func (o *Overrides) ShadowMetricRelabelingEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).ShadowMetricRelabelingEnabled
+}
+
+// ShadowMetricRelabelConfigs returns the shadow metric relabel configs for a given user.
+func (o *Overrides) ShadowMetricRelabelConfigs(userID string) []*relabel.Config {
+	return o.getOverridesForUser(userID).ShadowMetricRelabelConfigs
+}
+
+// ShadowDropLabels returns the list of labels dropped by the shadow relabeling for a given user.
+func (o *Overrides) ShadowDropLabels(userID string) flagext.StringSlice {
+	return o.getOverridesForUser(userID).ShadowDropLabels
+}
+
+// StreamAggregationRules returns the stream aggregation rules for a given user.
+func (o *Overrides) StreamAggregationRules(userID string) []*StreamAggregationRule {
+	return o.getOverridesForUser(userID).StreamAggregationRules
+}
+
 func (o *Overrides) MetricRelabelingEnabled(userID string) bool {
 	return o.getOverridesForUser(userID).MetricRelabelingEnabled
 }
@@ -1027,6 +1176,30 @@ func (o *Overrides) OTelMetricSuffixesEnabled(tenantID string) bool {
 	return o.getOverridesForUser(tenantID).OTelMetricSuffixesEnabled
 }
 
+func (o *Overrides) OTelConvertDeltaToCumulative(tenantID string) bool {
+	return o.getOverridesForUser(tenantID).OTelConvertDeltaToCumulative
+}
+
+func (o *Overrides) OTelDeltaMaxTrackedSeries(tenantID string) int {
+	return o.getOverridesForUser(tenantID).OTelDeltaMaxTrackedSeries
+}
+
+func (o *Overrides) PromoteOTelResourceAttributes(tenantID string) []string {
+	return o.getOverridesForUser(tenantID).PromoteOTelResourceAttributes
+}
+
+func (o *Overrides) OTelDisableTargetInfo(tenantID string) bool {
+	return o.getOverridesForUser(tenantID).OTelDisableTargetInfo
+}
+
+func (o *Overrides) OTelPromoteScopeMetadata(tenantID string) bool {
+	return o.getOverridesForUser(tenantID).OTelPromoteScopeMetadata
+}
+
+func (o *Overrides) RemoteWriteCreatedTimestampZeroIngestionEnabled(tenantID string) bool {
+	return o.getOverridesForUser(tenantID).RemoteWriteCreatedTimestampZeroIngestionEnabled
+}
+
 func (o *Overrides) AlignQueriesWithStep(userID string) bool {
 	return o.getOverridesForUser(userID).AlignQueriesWithStep
 }
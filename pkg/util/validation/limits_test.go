@@ -169,6 +169,63 @@ metric_relabel_configs:
 	assert.Equal(t, []*relabel.Config{&exp}, l.MetricRelabelConfigs)
 }
 
+func TestSeriesRetentionRulesLimitsLoadingFromYaml(t *testing.T) {
+	inp := `
+compactor_series_retention_rules:
+- selector: '{__name__=~"debug_.*"}'
+  period: 7d
+- selector: '{job="test"}'
+  period: 1h
+`
+
+	l := Limits{}
+	dec := yaml.NewDecoder(strings.NewReader(inp))
+	dec.KnownFields(true)
+	require.NoError(t, dec.Decode(&l))
+
+	require.Equal(t, []*SeriesRetentionRule{
+		{Selector: `{__name__=~"debug_.*"}`, Period: model.Duration(7 * 24 * time.Hour)},
+		{Selector: `{job="test"}`, Period: model.Duration(time.Hour)},
+	}, l.CompactorSeriesRetentionRules)
+
+	assert.Equal(t, `{__name__=~"debug_.*"}[1w]`, l.CompactorSeriesRetentionRules[0].String())
+	assert.Equal(t, time.Unix(100, 0).Add(-time.Hour).UnixMilli(), l.CompactorSeriesRetentionRules[1].Cutoff(time.Unix(100, 0)))
+
+	matchers, err := l.CompactorSeriesRetentionRules[0].Matchers()
+	require.NoError(t, err)
+	require.Len(t, matchers, 1)
+	assert.Equal(t, `__name__=~"debug_.*"`, matchers[0].String())
+}
+
+func TestStreamAggregationRulesLimitsLoadingFromYaml(t *testing.T) {
+	inp := `
+stream_aggregation_rules:
+- selector: '{__name__="http_requests_total"}'
+  interval: 1m
+  without: [pod, instance]
+  outputs: [sum, rate]
+  keep_input: true
+`
+
+	l := Limits{}
+	dec := yaml.NewDecoder(strings.NewReader(inp))
+	dec.KnownFields(true)
+	require.NoError(t, dec.Decode(&l))
+
+	require.Equal(t, []*StreamAggregationRule{
+		{
+			Selector:  `{__name__="http_requests_total"}`,
+			Interval:  model.Duration(time.Minute),
+			Without:   []string{"pod", "instance"},
+			Outputs:   []string{StreamAggregationOutputSum, StreamAggregationOutputRate},
+			KeepInput: true,
+		},
+	}, l.StreamAggregationRules)
+
+	assert.Equal(t, "http_requests_total:1m_without_pod_instance_rate", l.StreamAggregationRules[0].OutputMetricName("http_requests_total", StreamAggregationOutputRate))
+	assert.Equal(t, "up:5m_count", (&StreamAggregationRule{Interval: model.Duration(5 * time.Minute)}).OutputMetricName("up", StreamAggregationOutputCount))
+}
+
 func TestSmallestPositiveIntPerTenant(t *testing.T) {
 	tenantLimits := map[string]*Limits{
 		"tenant-a": {
@@ -967,6 +1024,105 @@ metric_relabel_configs:
 			cfg:         `ingest_storage_read_consistency: xyz`,
 			expectedErr: errInvalidIngestStorageReadConsistency.Error(),
 		},
+		"should fail on invalid compactor_series_retention_rules": {
+			cfg: `
+compactor_series_retention_rules:
+  -
+`,
+			expectedErr: errInvalidSeriesRetentionRule.Error(),
+		},
+		"should fail on compactor_series_retention_rules with invalid selector": {
+			cfg: `
+compactor_series_retention_rules:
+  - selector: '{__name__="up"'
+    period: 1d
+`,
+			expectedErr: "invalid series retention rule selector",
+		},
+		"should fail on compactor_series_retention_rules with selector matching all series": {
+			cfg: `
+compactor_series_retention_rules:
+  - selector: '{__name__=~".*"}'
+    period: 1d
+`,
+			expectedErr: errSeriesRetentionRuleMatchesAll.Error(),
+		},
+		"should fail on compactor_series_retention_rules without period": {
+			cfg: `
+compactor_series_retention_rules:
+  - selector: '{__name__="up"}'
+`,
+			expectedErr: errSeriesRetentionRuleNonPositivePeriod.Error(),
+		},
+		"should fail on invalid stream_aggregation_rules": {
+			cfg: `
+stream_aggregation_rules:
+  -
+`,
+			expectedErr: errInvalidStreamAggregationRule.Error(),
+		},
+		"should fail on stream_aggregation_rules with selector matching all series": {
+			cfg: `
+stream_aggregation_rules:
+  - selector: '{__name__=~".*"}'
+    interval: 1m
+    outputs: [sum]
+`,
+			expectedErr: errStreamAggregationRuleMatchesAll.Error(),
+		},
+		"should fail on stream_aggregation_rules without interval": {
+			cfg: `
+stream_aggregation_rules:
+  - selector: '{__name__="up"}'
+    outputs: [sum]
+`,
+			expectedErr: errStreamAggregationRuleNonPositiveInterval.Error(),
+		},
+		"should fail on stream_aggregation_rules without outputs": {
+			cfg: `
+stream_aggregation_rules:
+  - selector: '{__name__="up"}'
+    interval: 1m
+`,
+			expectedErr: errStreamAggregationRuleNoOutputs.Error(),
+		},
+		"should fail on stream_aggregation_rules with unsupported output": {
+			cfg: `
+stream_aggregation_rules:
+  - selector: '{__name__="up"}'
+    interval: 1m
+    outputs: [avg]
+`,
+			expectedErr: `unsupported stream aggregation rule output "avg"`,
+		},
+		"should fail on stream_aggregation_rules dropping the metric name": {
+			cfg: `
+stream_aggregation_rules:
+  - selector: '{__name__="up"}'
+    interval: 1m
+    without: [__name__]
+    outputs: [sum]
+`,
+			expectedErr: errStreamAggregationRuleWithoutMetricName.Error(),
+		},
+		"should pass on valid stream_aggregation_rules": {
+			cfg: `
+stream_aggregation_rules:
+  - selector: '{__name__="up"}'
+    interval: 1m
+    without: [pod]
+    outputs: [sum, count, max, rate]
+`,
+			expectedErr: "",
+		},
+		"should pass on valid compactor_series_retention_rules": {
+			cfg: `
+compactor_series_retention_rules:
+  - selector: '{__name__="up"}'
+    period: 1d
+`,
+			expectedErr: "",
+		},
 	}
 
 	for testName, testData := range tests {
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+var (
+	errInvalidSeriesRetentionRule           = errors.New("invalid compactor_series_retention_rules")
+	errSeriesRetentionRuleMatchesAll        = errors.New("series retention rule selector must contain at least one non-empty matcher")
+	errSeriesRetentionRuleNonPositivePeriod = errors.New("series retention rule period must be greater than 0")
+)
+
+// SeriesRetentionRule deletes the samples of the series matching the selector older than the period.
+type SeriesRetentionRule struct {
+	Selector string         `yaml:"selector" json:"selector"`
+	Period   model.Duration `yaml:"period" json:"period"`
+}
+
+// Matchers returns the parsed matchers of the rule selector.
+func (r *SeriesRetentionRule) Matchers() ([]*labels.Matcher, error) {
+	matchers, err := parser.ParseMetricSelector(r.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid series retention rule selector %q: %w", r.Selector, err)
+	}
+
+	// Applying a retention period to all series must be done with the blocks retention period.
+	for _, m := range matchers {
+		if !m.Matches("") {
+			return matchers, nil
+		}
+	}
+	return nil, errSeriesRetentionRuleMatchesAll
+}
+
+// Cutoff returns the timestamp, in milliseconds, before which the samples of the matching series are deleted.
+func (r *SeriesRetentionRule) Cutoff(now time.Time) int64 {
+	return now.Add(-time.Duration(r.Period)).UnixMilli()
+}
+
+// String returns the rule in the "<selector>[<period>]" format, which identifies it.
+func (r *SeriesRetentionRule) String() string {
+	return fmt.Sprintf("%s[%s]", r.Selector, r.Period)
+}
+
+func (r *SeriesRetentionRule) validate() error {
+	if r.Period <= 0 {
+		return errSeriesRetentionRuleNonPositivePeriod
+	}
+	_, err := r.Matchers()
+	return err
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Outputs of a stream aggregation rule.
+const (
+	StreamAggregationOutputSum   = "sum"
+	StreamAggregationOutputCount = "count"
+	StreamAggregationOutputMax   = "max"
+	StreamAggregationOutputRate  = "rate"
+)
+
+var (
+	errInvalidStreamAggregationRule             = errors.New("invalid stream_aggregation_rules")
+	errStreamAggregationRuleMatchesAll          = errors.New("stream aggregation rule selector must contain at least one non-empty matcher")
+	errStreamAggregationRuleNonPositiveInterval = errors.New("stream aggregation rule interval must be greater than 0")
+	errStreamAggregationRuleNoOutputs           = errors.New("stream aggregation rule must have at least one output")
+	errStreamAggregationRuleWithoutMetricName   = errors.New("stream aggregation rule can't drop the metric name")
+)
+
+// StreamAggregationRule aggregates the samples of the series matching the selector, without the given labels,
+// into the outputs, which are written once per interval.
+type StreamAggregationRule struct {
+	Selector  string         `yaml:"selector" json:"selector"`
+	Interval  model.Duration `yaml:"interval" json:"interval"`
+	Without   []string       `yaml:"without,omitempty" json:"without,omitempty"`
+	Outputs   []string       `yaml:"outputs" json:"outputs"`
+	KeepInput bool           `yaml:"keep_input,omitempty" json:"keep_input,omitempty"`
+}
+
+// Matchers returns the parsed matchers of the rule selector.
+func (r *StreamAggregationRule) Matchers() ([]*labels.Matcher, error) {
+	matchers, err := parser.ParseMetricSelector(r.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stream aggregation rule selector %q: %w", r.Selector, err)
+	}
+
+	for _, m := range matchers {
+		if !m.Matches("") {
+			return matchers, nil
+		}
+	}
+	return nil, errStreamAggregationRuleMatchesAll
+}
+
+// OutputMetricName returns the metric name of the given output of the rule for the input metric name,
+// in the "<metric>:<interval>[_without_<labels>]_<output>" format.
+func (r *StreamAggregationRule) OutputMetricName(metricName, output string) string {
+	var b strings.Builder
+	b.WriteString(metricName)
+	b.WriteByte(':')
+	b.WriteString(r.Interval.String())
+	if len(r.Without) > 0 {
+		b.WriteString("_without_")
+		b.WriteString(strings.Join(r.Without, "_"))
+	}
+	b.WriteByte('_')
+	b.WriteString(output)
+	return b.String()
+}
+
+func (r *StreamAggregationRule) validate() error {
+	if r.Interval <= 0 {
+		return errStreamAggregationRuleNonPositiveInterval
+	}
+	if len(r.Outputs) == 0 {
+		return errStreamAggregationRuleNoOutputs
+	}
+	for _, output := range r.Outputs {
+		switch output {
+		case StreamAggregationOutputSum, StreamAggregationOutputCount, StreamAggregationOutputMax, StreamAggregationOutputRate:
+		default:
+			return fmt.Errorf("unsupported stream aggregation rule output %q", output)
+		}
+	}
+	for _, name := range r.Without {
+		if name == labels.MetricName {
+			return errStreamAggregationRuleWithoutMetricName
+		}
+	}
+	_, err := r.Matchers()
+	return err
+}
@@ -359,6 +359,10 @@ func getFieldCustomType(t reflect.Type) (string, bool) {
 		return "relabel_config...", true
 	case reflect.TypeOf([]*validation.BlockedQuery{}).String():
 		return "blocked_queries_config...", true
+	case reflect.TypeOf([]*validation.SeriesRetentionRule{}).String():
+		return "series_retention_rules_config...", true
+	case reflect.TypeOf([]*validation.StreamAggregationRule{}).String():
+		return "stream_aggregation_rules_config...", true
 	case reflect.TypeOf(activeseries.CustomTrackersConfig{}).String():
 		return "map of tracker name (string) to matcher (string)", true
 	default:
@@ -445,6 +449,10 @@ func getCustomFieldType(t reflect.Type) (string, bool) {
 		return "relabel_config...", true
 	case reflect.TypeOf([]*validation.BlockedQuery{}).String():
 		return "blocked_queries_config...", true
+	case reflect.TypeOf([]*validation.SeriesRetentionRule{}).String():
+		return "series_retention_rules_config...", true
+	case reflect.TypeOf([]*validation.StreamAggregationRule{}).String():
+		return "stream_aggregation_rules_config...", true
 	case reflect.TypeOf(activeseries.CustomTrackersConfig{}).String():
 		return "map of tracker name (string) to matcher (string)", true
 	default:
@@ -478,6 +486,10 @@ func ReflectType(typ string) reflect.Type {
 		return reflect.TypeOf([]*relabel.Config{})
 	case "blocked_queries_config...":
 		return reflect.TypeOf([]*validation.BlockedQuery{})
+	case "series_retention_rules_config...":
+		return reflect.TypeOf([]*validation.SeriesRetentionRule{})
+	case "stream_aggregation_rules_config...":
+		return reflect.TypeOf([]*validation.StreamAggregationRule{})
 	case "map of string to float64":
 		return reflect.TypeOf(validation.LimitsMap[float64]{})
 	case "map of string to int":